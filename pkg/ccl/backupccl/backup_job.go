@@ -357,6 +357,11 @@ func backup(
 				return RowCount{}, err
 			}
 			for _, stat := range tableStatisticsAcc {
+				// Merged and forecast statistics are derived from the stored
+				// statistics when they are read, so they are not backed up.
+				if stat.IsMerged() || stat.IsForecast() {
+					continue
+				}
 				tableStatistics = append(tableStatistics, &stat.TableStatisticProto)
 			}
 		}
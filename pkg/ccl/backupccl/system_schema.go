@@ -127,6 +127,18 @@ var systemTableBackupConfiguration = map[string]systemBackupConfiguration{
 	systemschema.TenantsTable.Name: {
 		includeInClusterBackup: optOutOfClusterBackup,
 	},
+	systemschema.TenantUsageTable.Name: {
+		includeInClusterBackup: optOutOfClusterBackup,
+	},
+	systemschema.StatementStatisticsTable.Name: {
+		includeInClusterBackup: optOutOfClusterBackup,
+	},
+	systemschema.TransactionStatisticsTable.Name: {
+		includeInClusterBackup: optOutOfClusterBackup,
+	},
+	systemschema.StatementHintsTable.Name: {
+		includeInClusterBackup: optInToClusterBackup,
+	},
 	systemschema.WebSessionsTable.Name: {
 		includeInClusterBackup: optOutOfClusterBackup,
 	},
@@ -104,6 +104,14 @@ func canUseFollowerRead(clusterID uuid.UUID, st *cluster.Settings, ts hlc.Timest
 // canSendToFollower implements the logic for checking whether a batch request
 // may be sent to a follower.
 func canSendToFollower(clusterID uuid.UUID, st *cluster.Settings, ba roachpb.BatchRequest) bool {
+	if ba.BoundedStaleness != nil {
+		// Bounded staleness reads negotiate their timestamp with the replica
+		// that serves them, so they can be sent to the nearest replica without
+		// regard for their timestamp.
+		return batchCanBeEvaluatedOnFollower(ba) &&
+			kvserver.FollowerReadsEnabled.Get(&st.SV) &&
+			checkEnterpriseEnabled(clusterID, st) == nil
+	}
 	return batchCanBeEvaluatedOnFollower(ba) &&
 		txnCanPerformFollowerRead(ba.Txn) &&
 		canUseFollowerRead(clusterID, st, forward(ba.Txn.ReadTimestamp, ba.Txn.MaxTimestamp))
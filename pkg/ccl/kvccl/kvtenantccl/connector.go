@@ -88,6 +88,11 @@ var _ kvcoord.RangeDescriptorDB = (*Connector)(nil)
 // network.
 var _ config.SystemConfigProvider = (*Connector)(nil)
 
+// Connector is capable of reporting the resource consumption of the tenant and
+// of requesting Request Units from the tenant's global token bucket, through
+// delegated TokenBucket requests.
+var _ kvtenant.TokenBucketProvider = (*Connector)(nil)
+
 // NewConnector creates a new Connector.
 func NewConnector(cfg kvtenant.ConnectorConfig, addrs []string) *Connector {
 	cfg.AmbientCtx.AddLogTag("tenant-connector", nil)
@@ -319,6 +324,37 @@ func (c *Connector) RangeLookup(
 	return nil, nil, ctx.Err()
 }
 
+// TokenBucket implements the kvtenant.TokenBucketProvider interface.
+func (c *Connector) TokenBucket(
+	ctx context.Context, in *roachpb.TokenBucketRequest,
+) (*roachpb.TokenBucketResponse, error) {
+	// Proxy token bucket requests through the Internal service.
+	ctx = c.AnnotateCtx(ctx)
+	for ctx.Err() == nil {
+		client, err := c.getClient(ctx)
+		if err != nil {
+			continue
+		}
+		resp, err := client.TokenBucket(ctx, in)
+		if err != nil {
+			log.Warningf(ctx, "error issuing TokenBucket RPC: %v", err)
+			if grpcutil.IsAuthenticationError(err) {
+				// Authentication error. Propagate.
+				return nil, err
+			}
+			// Soft RPC error. Drop client and retry.
+			c.tryForgetClient(ctx, client)
+			continue
+		}
+		if resp.Error != nil {
+			// Hard logical error. Propagate.
+			return nil, resp.Error.GoError()
+		}
+		return resp, nil
+	}
+	return nil, ctx.Err()
+}
+
 // FirstRange implements the kvcoord.RangeDescriptorDB interface.
 func (c *Connector) FirstRange() (*roachpb.RangeDescriptor, error) {
 	return nil, status.Error(codes.Unauthenticated, "kvtenant.Proxy does not have access to FirstRange")
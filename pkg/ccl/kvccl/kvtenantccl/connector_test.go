@@ -78,6 +78,12 @@ func (m *mockServer) Join(
 	panic("unimplemented")
 }
 
+func (m *mockServer) TokenBucket(
+	ctx context.Context, in *roachpb.TokenBucketRequest,
+) (*roachpb.TokenBucketResponse, error) {
+	panic("unimplemented")
+}
+
 func gossipEventForNodeDesc(desc *roachpb.NodeDescriptor) *roachpb.GossipSubscriptionEvent {
 	val, err := protoutil.Marshal(desc)
 	if err != nil {
@@ -0,0 +1,262 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"crypto/tls"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// fakeEntry is an entry in the directory of a fakeServer.
+type fakeEntry struct {
+	dn       string
+	password string
+	attrs    map[string][]string
+}
+
+// fakeServer is a minimal in-process LDAP server. It supports simple
+// binds, searches with equality, presence, AND and OR filters, and
+// StartTLS. Searches are only permitted on authenticated connections.
+type fakeServer struct {
+	t         testing.TB
+	ln        net.Listener
+	tlsConfig *tls.Config
+	entries   []fakeEntry
+	wg        sync.WaitGroup
+
+	mu struct {
+		syncutil.Mutex
+		// binds records the DNs of successful binds.
+		binds []string
+		// startTLS counts the connections upgraded with StartTLS.
+		startTLS int
+	}
+}
+
+func newFakeServer(t testing.TB, tlsConfig *tls.Config, entries ...fakeEntry) *fakeServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeServer{t: t, ln: ln, tlsConfig: tlsConfig, entries: entries}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serve(conn)
+			}()
+		}
+	}()
+	return s
+}
+
+// port returns the port the server listens on.
+func (s *fakeServer) port() string {
+	_, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	return port
+}
+
+func (s *fakeServer) close() {
+	_ = s.ln.Close()
+	s.wg.Wait()
+}
+
+func (s *fakeServer) binds() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.mu.binds...)
+}
+
+func (s *fakeServer) startTLSCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.startTLS
+}
+
+func (s *fakeServer) lookup(dn string) *fakeEntry {
+	for i := range s.entries {
+		if strings.EqualFold(s.entries[i].dn, dn) {
+			return &s.entries[i]
+		}
+	}
+	return nil
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	bound := false
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		if len(packet.Children) < 2 {
+			return
+		}
+		msgID := packet.Children[0].Value.(int64)
+		req := packet.Children[1]
+		switch req.Tag {
+		case ldap.ApplicationBindRequest:
+			dn := req.Children[1].Value.(string)
+			password := req.Children[2].Data.String()
+			code := uint16(ldap.LDAPResultInvalidCredentials)
+			if e := s.lookup(dn); e != nil && password != "" && e.password == password {
+				code = ldap.LDAPResultSuccess
+				bound = true
+				s.mu.Lock()
+				s.mu.binds = append(s.mu.binds, dn)
+				s.mu.Unlock()
+			}
+			s.respond(conn, msgID, ldap.ApplicationBindResponse, code)
+
+		case ldap.ApplicationUnbindRequest:
+			return
+
+		case ldap.ApplicationSearchRequest:
+			if !bound {
+				s.respond(conn, msgID, ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights)
+				continue
+			}
+			baseDN := strings.ToLower(req.Children[0].Value.(string))
+			scope := req.Children[1].Value.(int64)
+			filter := req.Children[6]
+			var attrs []string
+			for _, a := range req.Children[7].Children {
+				attrs = append(attrs, a.Value.(string))
+			}
+			for i := range s.entries {
+				e := &s.entries[i]
+				dn := strings.ToLower(e.dn)
+				if scope == ldap.ScopeBaseObject && dn != baseDN {
+					continue
+				}
+				if !strings.HasSuffix(dn, baseDN) || !matchFilter(e, filter) {
+					continue
+				}
+				s.sendEntry(conn, msgID, e, attrs)
+			}
+			s.respond(conn, msgID, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)
+
+		case ldap.ApplicationExtendedRequest:
+			if req.Children[0].Data.String() != startTLSOID || s.tlsConfig == nil {
+				s.respond(conn, msgID, ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError)
+				continue
+			}
+			s.respond(conn, msgID, ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess)
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				s.t.Logf("fake LDAP server: TLS handshake failed: %v", err)
+				return
+			}
+			s.mu.Lock()
+			s.mu.startTLS++
+			s.mu.Unlock()
+			conn = tlsConn
+
+		default:
+			s.t.Logf("fake LDAP server: unsupported request %d", req.Tag)
+			return
+		}
+	}
+}
+
+func envelope(msgID int64) *ber.Packet {
+	p := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, msgID, "MessageID"))
+	return p
+}
+
+func (s *fakeServer) respond(conn net.Conn, msgID int64, tag ber.Tag, code uint16) {
+	p := envelope(msgID)
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	res.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, ldap.LDAPResultCodeMap[code], "diagnosticMessage"))
+	p.AppendChild(res)
+	if _, err := conn.Write(p.Bytes()); err != nil {
+		s.t.Logf("fake LDAP server: %v", err)
+	}
+}
+
+func (s *fakeServer) sendEntry(conn net.Conn, msgID int64, e *fakeEntry, attrs []string) {
+	p := envelope(msgID)
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "objectName"))
+	attrList := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for _, name := range attrs {
+		vals := e.attrs[name]
+		if len(vals) == 0 {
+			continue
+		}
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, v := range vals {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "value"))
+		}
+		attr.AppendChild(set)
+		attrList.AppendChild(attr)
+	}
+	res.AppendChild(attrList)
+	p.AppendChild(res)
+	if _, err := conn.Write(p.Bytes()); err != nil {
+		s.t.Logf("fake LDAP server: %v", err)
+	}
+}
+
+// matchFilter evaluates the subset of LDAP filters supported by the fake
+// server. The objectClass attribute is considered present on all entries.
+func matchFilter(e *fakeEntry, f *ber.Packet) bool {
+	switch f.Tag {
+	case ldap.FilterAnd:
+		for _, c := range f.Children {
+			if !matchFilter(e, c) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, c := range f.Children {
+			if matchFilter(e, c) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterPresent:
+		attr := f.Data.String()
+		return strings.EqualFold(attr, "objectClass") || len(e.attrs[attr]) > 0
+	case ldap.FilterEqualityMatch:
+		attr := f.Children[0].Value.(string)
+		val := f.Children[1].Value.(string)
+		for _, v := range e.attrs[attr] {
+			if strings.EqualFold(v, val) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
@@ -0,0 +1,480 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/go-ldap/ldap/v3"
+)
+
+const authTypeCleartextPassword int32 = 3
+
+// ldapTimeout bounds the duration of each network operation performed
+// against the LDAP server.
+const ldapTimeout = 10 * time.Second
+
+// testingRootCAs, when set, is used to verify the certificate presented
+// by the LDAP server instead of the system roots.
+var testingRootCAs *x509.CertPool
+
+// ldapConfig is the LDAP configuration of a single HBA entry. The
+// option names follow those of PostgreSQL, see:
+// https://www.postgresql.org/docs/current/auth-ldap.html
+type ldapConfig struct {
+	server   string
+	port     int
+	scheme   string
+	startTLS bool
+
+	// Simple bind mode: the DN used to bind is prefix + user + suffix.
+	prefix, suffix string
+
+	// Search+bind mode: the user's DN is looked up under baseDN, using
+	// either searchAttribute or searchFilter, after binding as bindDN.
+	baseDN          string
+	bindDN          string
+	bindPasswd      string
+	searchAttribute string
+	searchFilter    string
+
+	// syncRoles, if set, causes the memberships of the user in groupRoles
+	// to be replaced at login by those among them named after the LDAP
+	// groups listed in groupAttribute of the user's entry. Memberships in
+	// other roles are left untouched.
+	syncRoles      bool
+	groupAttribute string
+	groupRoles     map[security.SQLUsername]struct{}
+}
+
+func parseConfig(entry hba.Entry) (ldapConfig, error) {
+	cfg := ldapConfig{
+		scheme:          "ldap",
+		searchAttribute: "uid",
+		groupAttribute:  "memberOf",
+	}
+	var hasSearchAttribute, hasGroupAttribute bool
+	parseBool := func(name, val string) (bool, error) {
+		switch val {
+		case "0":
+			return false, nil
+		case "1":
+			return true, nil
+		}
+		return false, errors.Errorf("%s must be set to 0 or 1: %s", name, val)
+	}
+	for _, op := range entry.Options {
+		var err error
+		switch op[0] {
+		case "ldapserver":
+			cfg.server = op[1]
+		case "ldapport":
+			cfg.port, err = strconv.Atoi(op[1])
+			if err != nil || cfg.port <= 0 || cfg.port > 65535 {
+				return cfg, errors.Errorf("invalid ldapport: %s", op[1])
+			}
+		case "ldapscheme":
+			if op[1] != "ldap" && op[1] != "ldaps" {
+				return cfg, errors.Errorf("ldapscheme must be set to ldap or ldaps: %s", op[1])
+			}
+			cfg.scheme = op[1]
+		case "ldaptls":
+			cfg.startTLS, err = parseBool(op[0], op[1])
+		case "ldapprefix":
+			cfg.prefix = op[1]
+		case "ldapsuffix":
+			cfg.suffix = op[1]
+		case "ldapbasedn":
+			cfg.baseDN = op[1]
+		case "ldapbinddn":
+			cfg.bindDN = op[1]
+		case "ldapbindpasswd":
+			cfg.bindPasswd = op[1]
+		case "ldapsearchattribute":
+			cfg.searchAttribute = op[1]
+			hasSearchAttribute = true
+		case "ldapsearchfilter":
+			cfg.searchFilter = op[1]
+		case "ldapsyncroles":
+			cfg.syncRoles, err = parseBool(op[0], op[1])
+		case "ldapgroupattribute":
+			cfg.groupAttribute = op[1]
+			hasGroupAttribute = true
+		case "ldapgrouproles":
+			cfg.groupRoles = make(map[security.SQLUsername]struct{})
+			for _, name := range strings.Split(op[1], ",") {
+				role, err := security.MakeSQLUsernameFromUserInput(
+					strings.TrimSpace(name), security.UsernameValidation)
+				if err != nil {
+					return cfg, errors.Wrapf(err, "invalid ldapgrouproles")
+				}
+				if role.Undefined() {
+					return cfg, errors.Errorf("invalid ldapgrouproles: empty role name in %q", op[1])
+				}
+				cfg.groupRoles[role] = struct{}{}
+			}
+		default:
+			err = errors.Errorf("unsupported option %s", op[0])
+			if !strings.HasPrefix(op[0], "ldap") {
+				err = errors.WithHint(err,
+					`Option values containing commas must be enclosed in double quotes together with the option name, `+
+						`e.g. "ldapbasedn=dc=example,dc=com".`)
+			}
+		}
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	if cfg.server == "" {
+		return cfg, errors.New(`missing "ldapserver" option in LDAP entry`)
+	}
+	if cfg.scheme == "ldaps" && cfg.startTLS {
+		return cfg, errors.New("ldaptls cannot be used with ldapscheme=ldaps")
+	}
+	if cfg.port == 0 {
+		cfg.port = 389
+		if cfg.scheme == "ldaps" {
+			cfg.port = 636
+		}
+	}
+	if cfg.simpleBind() {
+		if cfg.baseDN != "" || cfg.bindDN != "" || cfg.bindPasswd != "" ||
+			hasSearchAttribute || cfg.searchFilter != "" {
+			return cfg, errors.New(
+				"cannot use ldapbasedn, ldapbinddn, ldapbindpasswd, ldapsearchattribute, " +
+					"or ldapsearchfilter together with ldapprefix or ldapsuffix")
+		}
+	} else {
+		if cfg.baseDN == "" {
+			return cfg, errors.New(
+				"LDAP entry must specify either ldapbasedn, or ldapprefix and/or ldapsuffix")
+		}
+		if hasSearchAttribute && cfg.searchFilter != "" {
+			return cfg, errors.New("cannot use ldapsearchattribute together with ldapsearchfilter")
+		}
+		if cfg.bindPasswd != "" && cfg.bindDN == "" {
+			return cfg, errors.New("ldapbindpasswd requires ldapbinddn")
+		}
+	}
+	if hasGroupAttribute && !cfg.syncRoles {
+		return cfg, errors.New("ldapgroupattribute requires ldapsyncroles=1")
+	}
+	if cfg.groupRoles != nil && !cfg.syncRoles {
+		return cfg, errors.New("ldapgrouproles requires ldapsyncroles=1")
+	}
+	if cfg.syncRoles && len(cfg.groupRoles) == 0 {
+		// The roles managed by LDAP have to be listed explicitly, so that
+		// memberships granted by other means are never revoked, and an
+		// LDAP group can't be used to obtain an arbitrary role (e.g. admin).
+		return cfg, errors.New("ldapsyncroles=1 requires ldapgrouproles")
+	}
+	return cfg, nil
+}
+
+// simpleBind returns true iff the configuration uses simple bind mode
+// rather than search+bind mode.
+func (cfg *ldapConfig) simpleBind() bool {
+	return cfg.prefix != "" || cfg.suffix != ""
+}
+
+// dial opens a connection to the LDAP server, upgrading it with
+// StartTLS if requested. All the operations performed on the returned
+// connection must complete within ldapTimeout.
+func (cfg *ldapConfig) dial() (*ldap.Conn, error) {
+	tlsConfig := &tls.Config{ServerName: cfg.server, RootCAs: testingRootCAs}
+	netConn, err := net.DialTimeout("tcp", net.JoinHostPort(cfg.server, strconv.Itoa(cfg.port)), ldapTimeout)
+	if err != nil {
+		return nil, err
+	}
+	// We use a deadline on the underlying connection rather than
+	// (*ldap.Conn).SetTimeout, which leaves a goroutine behind for
+	// every request.
+	if err := netConn.SetDeadline(timeutil.Now().Add(ldapTimeout)); err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+	isTLS := cfg.scheme == "ldaps"
+	if isTLS {
+		netConn = tls.Client(netConn, tlsConfig)
+	}
+	conn := ldap.NewConn(netConn, isTLS)
+	conn.Start()
+	if cfg.startTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "StartTLS")
+		}
+	}
+	return conn, nil
+}
+
+// authenticate verifies the password of user against the LDAP server.
+// If role synchronization is enabled, it also returns the values of the
+// group attribute of the user's entry.
+func (cfg *ldapConfig) authenticate(
+	ctx context.Context, c pgwire.AuthConn, user, password string,
+) (groups []string, _ error) {
+	authFailed := errors.Errorf(security.ErrPasswordUserAuthFailed, user)
+	// An empty password would be treated by the server as an
+	// unauthenticated bind, which always succeeds.
+	if password == "" {
+		c.Logf(ctx, "empty password")
+		return nil, authFailed
+	}
+
+	conn, err := cfg.dial()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to LDAP server")
+	}
+	defer conn.Close()
+
+	userDN := cfg.prefix + escapeDNValue(user) + cfg.suffix
+	if !cfg.simpleBind() {
+		if cfg.bindDN != "" {
+			if err := conn.Bind(cfg.bindDN, cfg.bindPasswd); err != nil {
+				return nil, errors.Wrapf(err, "could not bind to LDAP server as %q", cfg.bindDN)
+			}
+		}
+		filter := "(" + cfg.searchAttribute + "=" + ldap.EscapeFilter(user) + ")"
+		if cfg.searchFilter != "" {
+			filter = strings.ReplaceAll(cfg.searchFilter, "$username", ldap.EscapeFilter(user))
+		}
+		res, err := conn.Search(ldap.NewSearchRequest(
+			cfg.baseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+			0 /* sizeLimit */, int(ldapTimeout.Seconds()), false, /* typesOnly */
+			filter, []string{"dn"}, nil, /* controls */
+		))
+		if err != nil {
+			return nil, errors.Wrapf(err, "LDAP search for %q failed", filter)
+		}
+		if len(res.Entries) != 1 {
+			c.Logf(ctx, "LDAP search for %q returned %d entries", filter, len(res.Entries))
+			return nil, authFailed
+		}
+		userDN = res.Entries[0].DN
+	}
+
+	if err := conn.Bind(userDN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			c.Logf(ctx, "LDAP bind as %q failed: %v", userDN, err)
+			return nil, authFailed
+		}
+		return nil, errors.Wrapf(err, "LDAP bind as %q failed", userDN)
+	}
+
+	if !cfg.syncRoles {
+		return nil, nil
+	}
+	res, err := conn.Search(ldap.NewSearchRequest(
+		userDN, ldap.ScopeBaseObject, ldap.NeverDerefAliases,
+		0 /* sizeLimit */, int(ldapTimeout.Seconds()), false, /* typesOnly */
+		"(objectClass=*)", []string{cfg.groupAttribute}, nil, /* controls */
+	))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not retrieve LDAP groups of %q", userDN)
+	}
+	for _, e := range res.Entries {
+		groups = append(groups, e.GetEqualFoldAttributeValues(cfg.groupAttribute)...)
+	}
+	return groups, nil
+}
+
+// escapeDNValue escapes the special characters of an attribute value of a
+// distinguished name, as specified by RFC 4514, so that a username can't
+// alter the structure of the DN it is spliced into.
+func escapeDNValue(value string) string {
+	var buf strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == 0:
+			buf.WriteString(`\00`)
+			continue
+		case c == '"' || c == '+' || c == ',' || c == ';' || c == '<' || c == '>' || c == '\\':
+			buf.WriteByte('\\')
+		case (c == ' ' || c == '#') && i == 0:
+			buf.WriteByte('\\')
+		case c == ' ' && i == len(value)-1:
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
+// groupRoleName returns the name of the SQL role corresponding to an LDAP
+// group, that is, the value of the leading CN attribute of its DN.
+func groupRoleName(groupDN string) (security.SQLUsername, bool) {
+	dn, err := ldap.ParseDN(groupDN)
+	if err != nil || len(dn.RDNs) == 0 {
+		return security.SQLUsername{}, false
+	}
+	for _, attr := range dn.RDNs[0].Attributes {
+		if strings.EqualFold(attr.Type, "cn") {
+			role, err := security.MakeSQLUsernameFromUserInput(attr.Value, security.UsernameValidation)
+			if err != nil {
+				return security.SQLUsername{}, false
+			}
+			return role, true
+		}
+	}
+	return security.SQLUsername{}, false
+}
+
+// syncRoles makes user a member of exactly those of groupRoles which are
+// named after the given LDAP groups. Groups without a matching role in
+// groupRoles are ignored, and memberships in roles other than groupRoles are
+// left untouched. The memberships are read and updated in a single
+// transaction, so that a failure can't leave the user with only part of
+// their roles.
+func syncRoles(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user security.SQLUsername,
+	groups []string,
+	groupRoles map[security.SQLUsername]struct{},
+) error {
+	ie := execCfg.InternalExecutor
+	override := sessiondata.InternalExecutorOverride{User: security.RootUserName()}
+
+	return execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		wanted := make(map[security.SQLUsername]struct{})
+		for _, group := range groups {
+			role, ok := groupRoleName(group)
+			if !ok || role == user || role.IsPublicRole() {
+				continue
+			}
+			if _, ok := groupRoles[role]; !ok {
+				log.VEventf(ctx, 2, "LDAP group %q is not listed in ldapgrouproles", group)
+				continue
+			}
+			row, err := ie.QueryRowEx(ctx, "ldap-check-role", txn, override,
+				`SELECT 1 FROM system.users WHERE username = $1 AND "isRole"`, role.Normalized())
+			if err != nil {
+				return err
+			}
+			if row == nil {
+				log.VEventf(ctx, 2, "no SQL role matches LDAP group %q", group)
+				continue
+			}
+			wanted[role] = struct{}{}
+		}
+
+		rows, err := ie.QueryEx(ctx, "ldap-get-role-memberships", txn, override,
+			`SELECT "role" FROM system.role_members WHERE "member" = $1`, user.Normalized())
+		if err != nil {
+			return err
+		}
+		current := make(map[security.SQLUsername]struct{}, len(rows))
+		for _, row := range rows {
+			role := security.MakeSQLUsernameFromPreNormalizedString(string(*row[0].(*tree.DString)))
+			current[role] = struct{}{}
+			if _, ok := groupRoles[role]; !ok {
+				// The membership was not granted through LDAP.
+				continue
+			}
+			if _, ok := wanted[role]; ok {
+				continue
+			}
+			if _, err := ie.ExecEx(ctx, "ldap-revoke-role", txn, override,
+				"REVOKE "+role.SQLIdentifier()+" FROM "+user.SQLIdentifier()); err != nil {
+				return err
+			}
+		}
+		for role := range wanted {
+			if _, ok := current[role]; ok {
+				continue
+			}
+			if _, err := ie.ExecEx(ctx, "ldap-grant-role", txn, override,
+				"GRANT "+role.SQLIdentifier()+" TO "+user.SQLIdentifier()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// authLDAP performs LDAP authentication. The client's cleartext password
+// is verified by binding to the LDAP server, either directly (simple bind
+// mode) or after looking up the user's DN (search+bind mode).
+func authLDAP(
+	ctx context.Context,
+	c pgwire.AuthConn,
+	_ tls.ConnectionState,
+	_ pgwire.PasswordRetrievalFn,
+	_ pgwire.PasswordValidUntilFn,
+	execCfg *sql.ExecutorConfig,
+	entry *hba.Entry,
+) (security.UserAuthHook, error) {
+	cfg, err := parseConfig(*entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.SendAuthRequest(authTypeCleartextPassword, nil /* data */); err != nil {
+		return nil, err
+	}
+	pwdData, err := c.GetPwdData()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(pwdData, 0) != len(pwdData)-1 {
+		return nil, errors.New("expected 0-terminated byte array")
+	}
+	password := string(pwdData[:len(pwdData)-1])
+
+	return func(requestedUser security.SQLUsername, clientConnection bool) (func(), error) {
+		groups, err := cfg.authenticate(ctx, c, requestedUser.Normalized(), password)
+		if err != nil {
+			return nil, err
+		}
+
+		// Do the license check after authentication so that administrators
+		// are able to test whether their LDAP configuration is correct.
+		if err := utilccl.CheckEnterpriseEnabled(
+			execCfg.Settings, execCfg.ClusterID(), execCfg.Organization(), "LDAP authentication",
+		); err != nil {
+			return nil, err
+		}
+
+		if cfg.syncRoles {
+			if err := syncRoles(ctx, execCfg, requestedUser, groups, cfg.groupRoles); err != nil {
+				return nil, errors.Wrap(err, "synchronizing LDAP group memberships")
+			}
+		}
+		return nil, nil
+	}, nil
+}
+
+func checkEntry(entry hba.Entry) error {
+	_, err := parseConfig(entry)
+	return err
+}
+
+func init() {
+	pgwire.RegisterAuthMethod("ldap", authLDAP, hba.ConnAny, checkEntry)
+}
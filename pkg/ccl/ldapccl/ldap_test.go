@@ -0,0 +1,259 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	gosql "database/sql"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/security/securitytest"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckEntry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		options string
+		err     string
+	}{
+		{`ldapserver=ldap.example.com ldapprefix=uid= "ldapsuffix=,dc=example,dc=com"`, ``},
+		{`ldapserver=ldap.example.com "ldapbasedn=dc=example,dc=com"`, ``},
+		{`ldapserver=ldap.example.com ldapscheme=ldaps ldapport=1636 "ldapbasedn=dc=example,dc=com" ` +
+			`"ldapbinddn=cn=svc,dc=example,dc=com" ldapbindpasswd=secret ldapsyncroles=1 ldapgroupattribute=isMemberOf ` +
+			`"ldapgrouproles=devs, ops"`, ``},
+		{`ldapserver=ldap.example.com ldaptls=1 "ldapbasedn=dc=example,dc=com" ` +
+			`"ldapsearchfilter=(&(objectClass=person)(sAMAccountName=$username))"`, ``},
+		{`"ldapbasedn=dc=example,dc=com"`, `missing "ldapserver" option in LDAP entry`},
+		{`ldapserver=ldap.example.com`, `LDAP entry must specify either ldapbasedn, or ldapprefix and/or ldapsuffix`},
+		{`ldapserver=ldap.example.com ldapbasedn=dc=example,dc=com`, `unsupported option dc`},
+		{`ldapserver=ldap.example.com ldapbasedn=dc=example ldapfoo=bar`, `unsupported option ldapfoo`},
+		{`ldapserver=ldap.example.com ldapbasedn=dc=example ldapport=abc`, `invalid ldapport: abc`},
+		{`ldapserver=ldap.example.com ldapbasedn=dc=example ldapscheme=http`, `ldapscheme must be set to ldap or ldaps: http`},
+		{`ldapserver=ldap.example.com ldapbasedn=dc=example ldaptls=yes`, `ldaptls must be set to 0 or 1: yes`},
+		{`ldapserver=ldap.example.com ldapbasedn=dc=example ldapscheme=ldaps ldaptls=1`, `ldaptls cannot be used with ldapscheme=ldaps`},
+		{`ldapserver=ldap.example.com ldapprefix=uid= ldapbasedn=dc=example`, `cannot use ldapbasedn`},
+		{`ldapserver=ldap.example.com ldapbasedn=dc=example ldapsearchattribute=cn ldapsearchfilter=(cn=$username)`,
+			`cannot use ldapsearchattribute together with ldapsearchfilter`},
+		{`ldapserver=ldap.example.com ldapbasedn=dc=example ldapbindpasswd=secret`, `ldapbindpasswd requires ldapbinddn`},
+		{`ldapserver=ldap.example.com ldapbasedn=dc=example ldapgroupattribute=memberOf`, `ldapgroupattribute requires ldapsyncroles=1`},
+		{`ldapserver=ldap.example.com ldapbasedn=dc=example ldapgrouproles=devs`, `ldapgrouproles requires ldapsyncroles=1`},
+		{`ldapserver=ldap.example.com ldapbasedn=dc=example ldapsyncroles=1`, `ldapsyncroles=1 requires ldapgrouproles`},
+		{`ldapserver=ldap.example.com ldapbasedn=dc=example ldapsyncroles=1 "ldapgrouproles=devs,,ops"`, `invalid ldapgrouproles: empty role name`},
+	} {
+		t.Run(tc.options, func(t *testing.T) {
+			conf, err := hba.ParseAndNormalize("host all all all ldap " + tc.options)
+			require.NoError(t, err)
+			err = checkEntry(conf.Entries[0])
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestEscapeDNValue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		value, escaped string
+	}{
+		{"alice", "alice"},
+		{"alice,ou=admins", `alice\,ou=admins`},
+		{`a+b;c"d<e>f\g`, `a\+b\;c\"d\<e\>f\\g`},
+		{"#alice", `\#alice`},
+		{"a#lice", "a#lice"},
+		{" alice ", `\ alice\ `},
+		{"al ice", "al ice"},
+		{"ali\x00ce", `ali\00ce`},
+	} {
+		require.Equal(t, tc.escaped, escapeDNValue(tc.value), tc.value)
+	}
+}
+
+func TestGroupRoleName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		dn, role string
+	}{
+		{"cn=devs,ou=groups,dc=example,dc=com", "devs"},
+		{"CN=Site Reliability,OU=Groups,DC=corp,DC=example,DC=com", "site reliability"},
+		{"CN=SRE,OU=Groups,DC=corp,DC=example,DC=com", "sre"},
+		{"ou=groups,dc=example,dc=com", ""},
+		{"not a dn", ""},
+	} {
+		role, ok := groupRoleName(tc.dn)
+		require.Equal(t, tc.role != "", ok, tc.dn)
+		if ok {
+			require.Equal(t, tc.role, role.Normalized())
+		}
+	}
+}
+
+func TestLDAPAuthentication(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+
+	caPEM, err := securitytest.EmbeddedAssets.ReadFile(
+		filepath.Join(security.EmbeddedCertsDir, security.EmbeddedCACert))
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(caPEM))
+	defer func(prev *x509.CertPool) { testingRootCAs = prev }(testingRootCAs)
+	testingRootCAs = roots
+
+	certPEM, err := securitytest.EmbeddedAssets.ReadFile(
+		filepath.Join(security.EmbeddedCertsDir, security.EmbeddedNodeCert))
+	require.NoError(t, err)
+	keyPEM, err := securitytest.EmbeddedAssets.ReadFile(
+		filepath.Join(security.EmbeddedCertsDir, security.EmbeddedNodeKey))
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	serverTLSConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	ldapSrv := newFakeServer(t, serverTLSConfig,
+		fakeEntry{dn: "cn=svc,dc=example,dc=com", password: "svcpass"},
+		fakeEntry{
+			dn:       "uid=alice,ou=people,dc=example,dc=com",
+			password: "alicepass",
+			attrs: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"alice"},
+				"memberOf": {
+					"cn=devs,ou=groups,dc=example,dc=com",
+					"cn=nosuchrole,ou=groups,dc=example,dc=com",
+					"cn=admin,ou=groups,dc=example,dc=com",
+				},
+			},
+		},
+		fakeEntry{
+			dn:       "uid=bob,ou=people,dc=example,dc=com",
+			password: "bobpass",
+			attrs:    map[string][]string{"objectClass": {"person"}, "uid": {"bob"}},
+		},
+	)
+	defer ldapSrv.close()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE USER alice`)
+	sqlDB.Exec(t, `CREATE USER bob`)
+	sqlDB.Exec(t, `CREATE USER carol`)
+	sqlDB.Exec(t, `CREATE ROLE devs`)
+	sqlDB.Exec(t, `CREATE ROLE ops`)
+	sqlDB.Exec(t, `CREATE ROLE qa`)
+	sqlDB.Exec(t, `GRANT ops TO alice`)
+
+	connect := func(user, password string) error {
+		pgURL, cleanup := sqlutils.PGUrlWithOptionalClientCerts(
+			t, s.ServingSQLAddr(), t.Name(), url.UserPassword(user, password), false /* withClientCerts */)
+		defer cleanup()
+		userDB, err := gosql.Open("postgres", pgURL.String())
+		if err != nil {
+			return err
+		}
+		defer userDB.Close()
+		return userDB.Ping()
+	}
+	setHBA := func(options string) {
+		sqlDB.Exec(t, fmt.Sprintf(
+			`SET CLUSTER SETTING server.host_based_authentication.configuration = 'host all all all ldap ldapserver=localhost ldapport=%s %s'`,
+			ldapSrv.port(), options))
+	}
+	memberships := func(user string) [][]string {
+		return sqlDB.QueryStr(t, `SELECT role FROM system.role_members WHERE member = $1 ORDER BY role`, user)
+	}
+
+	t.Run("search+bind", func(t *testing.T) {
+		setHBA(`ldaptls=1 "ldapbasedn=dc=example,dc=com" "ldapbinddn=cn=svc,dc=example,dc=com" ldapbindpasswd=svcpass`)
+
+		require.NoError(t, connect("alice", "alicepass"))
+		require.NoError(t, connect("bob", "bobpass"))
+		require.Contains(t, ldapSrv.binds(), "uid=alice,ou=people,dc=example,dc=com")
+		require.NotZero(t, ldapSrv.startTLSCount())
+
+		for _, tc := range []struct{ user, password string }{
+			{"alice", "bobpass"},
+			{"alice", ""},
+			// carol exists in SQL but not in the directory.
+			{"carol", "carolpass"},
+		} {
+			err := connect(tc.user, tc.password)
+			require.True(t, testutils.IsError(err,
+				fmt.Sprintf("password authentication failed for user %s", tc.user)), "%v", err)
+		}
+
+		// Role memberships are left untouched without ldapsyncroles.
+		require.Equal(t, [][]string{{"ops"}}, memberships("alice"))
+	})
+
+	t.Run("search filter", func(t *testing.T) {
+		setHBA(`"ldapbasedn=ou=people,dc=example,dc=com" "ldapbinddn=cn=svc,dc=example,dc=com" ` +
+			`ldapbindpasswd=svcpass "ldapsearchfilter=(&(objectClass=person)(uid=$username))"`)
+		require.NoError(t, connect("bob", "bobpass"))
+	})
+
+	t.Run("bad bind credentials", func(t *testing.T) {
+		setHBA(`"ldapbasedn=dc=example,dc=com" "ldapbinddn=cn=svc,dc=example,dc=com" ldapbindpasswd=wrong`)
+		err := connect("alice", "alicepass")
+		require.True(t, testutils.IsError(err, `could not bind to LDAP server as "cn=svc,dc=example,dc=com"`), "%v", err)
+	})
+
+	t.Run("simple bind", func(t *testing.T) {
+		setHBA(`ldapprefix=uid= "ldapsuffix=,ou=people,dc=example,dc=com"`)
+		require.NoError(t, connect("bob", "bobpass"))
+		err := connect("bob", "alicepass")
+		require.True(t, testutils.IsError(err, "password authentication failed for user bob"), "%v", err)
+	})
+
+	t.Run("role sync", func(t *testing.T) {
+		setHBA(`"ldapbasedn=dc=example,dc=com" "ldapbinddn=cn=svc,dc=example,dc=com" ldapbindpasswd=svcpass ` +
+			`ldapsyncroles=1 "ldapgrouproles=devs,qa,nosuchrole"`)
+
+		// A failed login does not change memberships.
+		require.Error(t, connect("alice", "wrong"))
+		require.Equal(t, [][]string{{"ops"}}, memberships("alice"))
+
+		// The membership in ops was not granted through LDAP, so it is kept,
+		// and the admin group is ignored as admin is not in ldapgrouproles.
+		require.NoError(t, connect("alice", "alicepass"))
+		require.Equal(t, [][]string{{"devs"}, {"ops"}}, memberships("alice"))
+		// Logging in again is idempotent.
+		require.NoError(t, connect("alice", "alicepass"))
+		require.Equal(t, [][]string{{"devs"}, {"ops"}}, memberships("alice"))
+
+		// Memberships in roles managed through LDAP are revoked when the
+		// user is no longer part of the corresponding group.
+		sqlDB.Exec(t, `GRANT devs, qa, ops TO bob`)
+		require.NoError(t, connect("bob", "bobpass"))
+		require.Equal(t, [][]string{{"ops"}}, memberships("bob"))
+	})
+}
@@ -0,0 +1,266 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package oidcccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil/singleflight"
+	"github.com/cockroachdb/errors"
+	"github.com/coreos/go-oidc"
+)
+
+const (
+	authTypeCleartextPassword int32 = 3
+
+	jwtAuthFailed              = "JWT authentication failed for user %s"
+	jwtLoginSuccessCounterName = counterPrefix + "jwt_login_success"
+
+	// jwtProviderTimeout bounds the duration of each request made to an
+	// issuer, and the time a login waits for the issuer's discovery
+	// document to be retrieved.
+	jwtProviderTimeout = 10 * time.Second
+)
+
+var jwtLoginSuccessUseCounter = telemetry.GetCounterOnce(jwtLoginSuccessCounterName)
+
+// jwtVerifiers caches one verifier per trusted issuer and audience. The
+// verifiers hold the keys retrieved from the issuers' JWKS endpoints, which
+// are refreshed when a token signed by an unknown key is presented.
+var jwtVerifiers struct {
+	syncutil.Mutex
+	m map[jwtVerifierKey]*oidc.IDTokenVerifier
+	// group deduplicates the concurrent retrievals of the discovery document
+	// of an issuer. The retrievals are performed without holding the mutex,
+	// so that a slow issuer doesn't delay the logins using other issuers.
+	group singleflight.Group
+}
+
+type jwtVerifierKey struct {
+	issuer, audience string
+}
+
+// jwtConf is the configuration of the jwt_token authentication method,
+// derived from the JWT and OIDC cluster settings.
+type jwtConf struct {
+	issuers        []string
+	audience       string
+	claimJSONKey   string
+	principalRegex *regexp.Regexp
+}
+
+func getJWTConf(st *cluster.Settings) jwtConf {
+	conf := jwtConf{
+		issuers:      strings.Fields(JWTIssuers.Get(&st.SV)),
+		audience:     JWTAudience.Get(&st.SV),
+		claimJSONKey: OIDCClaimJSONKey.Get(&st.SV),
+		// The success of this line is guaranteed by the validation of the setting
+		principalRegex: regexp.MustCompile(OIDCPrincipalRegex.Get(&st.SV)),
+	}
+	if len(conf.issuers) == 0 {
+		if providerURL := OIDCProviderURL.Get(&st.SV); providerURL != "" {
+			conf.issuers = []string{providerURL}
+		}
+	}
+	if conf.audience == "" {
+		conf.audience = OIDCClientID.Get(&st.SV)
+	}
+	if conf.claimJSONKey == "" {
+		conf.claimJSONKey = "sub"
+	}
+	return conf
+}
+
+// getVerifier returns the verifier for tokens of the given issuer,
+// retrieving the issuer's discovery document if needed.
+func getVerifier(ctx context.Context, issuer, audience string) (*oidc.IDTokenVerifier, error) {
+	key := jwtVerifierKey{issuer: issuer, audience: audience}
+	jwtVerifiers.Lock()
+	if v, ok := jwtVerifiers.m[key]; ok {
+		jwtVerifiers.Unlock()
+		return v, nil
+	}
+	resC, _ := jwtVerifiers.group.DoChan(issuer+"\x00"+audience, func() (interface{}, error) {
+		// The provider retains the context to fetch keys after this
+		// connection's authentication has completed, so it can't be derived
+		// from the connection's context. Each request is bounded by the
+		// timeout of the client instead.
+		providerCtx := oidc.ClientContext(
+			context.Background(), httputil.NewClientWithTimeout(jwtProviderTimeout).Client,
+		)
+		provider, err := oidc.NewProvider(providerCtx, issuer)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to initialize OIDC provider for issuer %s", issuer)
+		}
+		v := provider.Verifier(&oidc.Config{ClientID: audience})
+		jwtVerifiers.Lock()
+		defer jwtVerifiers.Unlock()
+		if jwtVerifiers.m == nil {
+			jwtVerifiers.m = make(map[jwtVerifierKey]*oidc.IDTokenVerifier)
+		}
+		jwtVerifiers.m[key] = v
+		return v, nil
+	})
+	jwtVerifiers.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, jwtProviderTimeout)
+	defer cancel()
+	select {
+	case res := <-resC:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*oidc.IDTokenVerifier), nil
+	case <-ctx.Done():
+		return nil, errors.Wrapf(ctx.Err(), "retrieving OIDC discovery document of issuer %s", issuer)
+	}
+}
+
+// unverifiedIssuer extracts the issuer claim of a token without verifying
+// it, so that the token can be handed to the verifier of that issuer.
+func unverifiedIssuer(rawToken string) (string, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return "", errors.New("malformed token: expected 3 parts")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.Wrap(err, "malformed token payload")
+	}
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", errors.Wrap(err, "malformed token payload")
+	}
+	return claims.Issuer, nil
+}
+
+// principalFromToken verifies the token against the configured issuers and
+// audience and returns the SQL username derived from its claims.
+func (conf *jwtConf) principalFromToken(
+	ctx context.Context, rawToken string,
+) (security.SQLUsername, error) {
+	issuer, err := unverifiedIssuer(rawToken)
+	if err != nil {
+		return security.SQLUsername{}, err
+	}
+	trusted := false
+	for _, iss := range conf.issuers {
+		if iss == issuer {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return security.SQLUsername{}, errors.Newf("token issuer %q is not trusted", issuer)
+	}
+	verifier, err := getVerifier(ctx, issuer, conf.audience)
+	if err != nil {
+		return security.SQLUsername{}, err
+	}
+	idToken, err := verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return security.SQLUsername{}, err
+	}
+
+	var claims map[string]json.RawMessage
+	if err := idToken.Claims(&claims); err != nil {
+		return security.SQLUsername{}, errors.Wrap(err, "unable to deserialize token claims")
+	}
+	var principal string
+	if err := json.Unmarshal(claims[conf.claimJSONKey], &principal); err != nil {
+		return security.SQLUsername{}, errors.Wrapf(err, "failed to extract claim key %s", conf.claimJSONKey)
+	}
+	match := conf.principalRegex.FindStringSubmatch(principal)
+	if numGroups := len(match); numGroups != 2 {
+		return security.SQLUsername{}, errors.Newf("expected one group in regexp, got %d", numGroups)
+	}
+	return security.MakeSQLUsernameFromUserInput(match[1], security.UsernameValidation)
+}
+
+// authJWT performs authentication using a JSON Web Token signed by one of
+// the issuers configured in server.jwt_authentication.issuers. The token
+// is sent by the client in place of a cleartext password. The token must
+// be issued for the configured audience and not be expired, and the SQL
+// user is derived from the claim set in
+// server.oidc_authentication.claim_json_key using
+// server.oidc_authentication.principal_regex.
+func authJWT(
+	ctx context.Context,
+	c pgwire.AuthConn,
+	_ tls.ConnectionState,
+	_ pgwire.PasswordRetrievalFn,
+	_ pgwire.PasswordValidUntilFn,
+	execCfg *sql.ExecutorConfig,
+	_ *hba.Entry,
+) (security.UserAuthHook, error) {
+	if err := c.SendAuthRequest(authTypeCleartextPassword, nil /* data */); err != nil {
+		return nil, err
+	}
+	pwdData, err := c.GetPwdData()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(pwdData, 0) != len(pwdData)-1 {
+		return nil, errors.New("expected 0-terminated byte array")
+	}
+	rawToken := string(pwdData[:len(pwdData)-1])
+
+	return func(requestedUser security.SQLUsername, clientConnection bool) (func(), error) {
+		conf := getJWTConf(execCfg.Settings)
+		if len(conf.issuers) == 0 {
+			return nil, errors.WithHintf(
+				errors.New("JWT authentication is not configured"),
+				"Set the %s cluster setting.", JWTIssuersSettingName)
+		}
+		principal, err := conf.principalFromToken(ctx, rawToken)
+		if err != nil {
+			c.Logf(ctx, "JWT verification failed: %v", err)
+			return nil, errors.Errorf(jwtAuthFailed, requestedUser)
+		}
+		if principal != requestedUser {
+			c.Logf(ctx, "token principal %s does not match requested user", principal)
+			return nil, errors.Errorf(jwtAuthFailed, requestedUser)
+		}
+
+		// Do the license check last so that administrators are able to test
+		// whether their token configuration is correct.
+		if err := utilccl.CheckEnterpriseEnabled(
+			execCfg.Settings, execCfg.ClusterID(), execCfg.Organization(), "JWT authentication",
+		); err != nil {
+			return nil, err
+		}
+		telemetry.Inc(jwtLoginSuccessUseCounter)
+		return nil, nil
+	}, nil
+}
+
+func init() {
+	// The "jwt_token" method requires an OIDC token in place of the
+	// cleartext password. As with the "password" method, care should be
+	// taken to only accept it over secure connections.
+	pgwire.RegisterAuthMethod("jwt_token", authJWT, hba.ConnAny, nil)
+}
@@ -0,0 +1,198 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package oidcccl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	gosql "database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// testIssuer is an OIDC issuer serving a discovery document and a JWKS
+// containing the public part of its signing key.
+type testIssuer struct {
+	srv *httptest.Server
+	key *rsa.PrivateKey
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	iss := &testIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                iss.srv.URL,
+			"jwks_uri":                              iss.srv.URL + "/jwks",
+			"authorization_endpoint":                iss.srv.URL + "/auth",
+			"token_endpoint":                        iss.srv.URL + "/token",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+			Key: &key.PublicKey, KeyID: "key1", Algorithm: string(jose.RS256), Use: "sig",
+		}}})
+	})
+	iss.srv = httptest.NewServer(mux)
+	return iss
+}
+
+// token returns a token signed with key, issued by the issuer for the
+// given audience, with the given extra claims.
+func (iss *testIssuer) token(
+	t *testing.T, key *rsa.PrivateKey, audience string, expiry time.Time, claims map[string]interface{},
+) string {
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.RS256,
+		Key:       jose.JSONWebKey{Key: key, KeyID: "key1"},
+	}, nil)
+	require.NoError(t, err)
+	token, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Issuer:   iss.srv.URL,
+		Audience: jwt.Audience{audience},
+		IssuedAt: jwt.NewNumericDate(timeutil.Now()),
+		Expiry:   jwt.NewNumericDate(expiry),
+	}).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func TestJWTAuthentication(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+
+	issuer := newTestIssuer(t)
+	defer issuer.srv.Close()
+	untrusted := newTestIssuer(t)
+	defer untrusted.srv.Close()
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE USER alice`)
+	sqlDB.Exec(t, `CREATE USER bob`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.host_based_authentication.configuration = 'host all all all jwt_token'`)
+
+	connect := func(user, token string) error {
+		pgURL, cleanup := sqlutils.PGUrlWithOptionalClientCerts(
+			t, s.ServingSQLAddr(), t.Name(), url.UserPassword(user, token), false /* withClientCerts */)
+		defer cleanup()
+		userDB, err := gosql.Open("postgres", pgURL.String())
+		if err != nil {
+			return err
+		}
+		defer userDB.Close()
+		return userDB.Ping()
+	}
+
+	valid := timeutil.Now().Add(time.Hour)
+	aliceToken := issuer.token(t, issuer.key, "crdb", valid, map[string]interface{}{"sub": "alice"})
+
+	t.Run("not configured", func(t *testing.T) {
+		err := connect("alice", aliceToken)
+		require.True(t, testutils.IsError(err, "JWT authentication is not configured"), "%v", err)
+	})
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.jwt_authentication.issuers = $1`, issuer.srv.URL)
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.jwt_authentication.audience = 'crdb'`)
+
+	t.Run("valid token", func(t *testing.T) {
+		require.NoError(t, connect("alice", aliceToken))
+	})
+
+	for _, tc := range []struct {
+		name, user, token string
+	}{
+		{"other user", "bob", aliceToken},
+		{"expired", "alice",
+			issuer.token(t, issuer.key, "crdb", timeutil.Now().Add(-time.Minute), map[string]interface{}{"sub": "alice"})},
+		{"wrong audience", "alice",
+			issuer.token(t, issuer.key, "other", valid, map[string]interface{}{"sub": "alice"})},
+		{"bad signature", "alice",
+			issuer.token(t, otherKey, "crdb", valid, map[string]interface{}{"sub": "alice"})},
+		{"untrusted issuer", "alice",
+			untrusted.token(t, untrusted.key, "crdb", valid, map[string]interface{}{"sub": "alice"})},
+		{"not a token", "alice", "hunter2"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := connect(tc.user, tc.token)
+			require.True(t, testutils.IsError(err, "JWT authentication failed for user "+tc.user), "%v", err)
+		})
+	}
+
+	t.Run("oidc settings", func(t *testing.T) {
+		// The issuer and audience fall back to the OIDC provider and client,
+		// and the principal is derived from the OIDC claim settings.
+		sqlDB.Exec(t, `SET CLUSTER SETTING server.jwt_authentication.issuers = ''`)
+		sqlDB.Exec(t, `SET CLUSTER SETTING server.jwt_authentication.audience = ''`)
+		sqlDB.Exec(t, `SET CLUSTER SETTING server.oidc_authentication.provider_url = $1`, issuer.srv.URL)
+		sqlDB.Exec(t, `SET CLUSTER SETTING server.oidc_authentication.client_id = 'console'`)
+		sqlDB.Exec(t, `SET CLUSTER SETTING server.oidc_authentication.claim_json_key = 'email'`)
+		sqlDB.Exec(t, `SET CLUSTER SETTING server.oidc_authentication.principal_regex = '^([^@]+)@example.com$'`)
+
+		token := issuer.token(t, issuer.key, "console", valid,
+			map[string]interface{}{"sub": "1234", "email": "bob@example.com"})
+		require.NoError(t, connect("bob", token))
+
+		token = issuer.token(t, issuer.key, "console", valid,
+			map[string]interface{}{"sub": "1234", "email": "bob@example.org"})
+		err := connect("bob", token)
+		require.True(t, testutils.IsError(err, "JWT authentication failed for user bob"), "%v", err)
+	})
+}
+
+func TestGetVerifierWithHungIssuer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	unblock := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer hung.Close()
+	defer close(unblock)
+	issuer := newTestIssuer(t)
+	defer issuer.srv.Close()
+
+	// A login using the hung issuer gives up once its context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := getVerifier(ctx, hung.URL, "aud")
+	require.True(t, testutils.IsError(err, "retrieving OIDC discovery document"), "%v", err)
+
+	// The discovery document of the hung issuer is still being retrieved,
+	// which doesn't prevent the verifiers of other issuers from being
+	// retrieved.
+	v, err := getVerifier(context.Background(), issuer.srv.URL, "aud")
+	require.NoError(t, err)
+	require.NotNil(t, v)
+}
@@ -31,6 +31,10 @@ const (
 	OIDCPrincipalRegexSettingName = baseOIDCSettingName + "principal_regex"
 	OIDCButtonTextSettingName     = baseOIDCSettingName + "button_text"
 	OIDCAutoLoginSettingName      = baseOIDCSettingName + "autologin"
+
+	baseJWTSettingName     = "server.jwt_authentication."
+	JWTIssuersSettingName  = baseJWTSettingName + "issuers"
+	JWTAudienceSettingName = baseJWTSettingName + "audience"
 )
 
 // OIDCEnabled enables or disabled OIDC login for the DB Console
@@ -176,3 +180,39 @@ var OIDCAutoLogin = func() *settings.BoolSetting {
 	)
 	return s
 }()
+
+// JWTIssuers is the list of issuers whose tokens are accepted by the
+// jwt_token SQL authentication method.
+var JWTIssuers = func() *settings.StringSetting {
+	s := settings.RegisterValidatedStringSetting(
+		JWTIssuersSettingName,
+		"sets the space delimited list of issuer URLs trusted to sign tokens for the jwt_token "+
+			"authentication method ({issuer}/.well-known/openid-configuration must resolve); "+
+			"if empty, "+OIDCProviderURLSettingName+" is used (this feature is experimental)",
+		"",
+		func(values *settings.Values, s string) error {
+			for _, issuer := range strings.Fields(s) {
+				if _, err := url.Parse(issuer); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	s.SetReportable(true)
+	s.SetVisibility(settings.Public)
+	return s
+}()
+
+// JWTAudience is the audience that tokens must be issued for to be
+// accepted by the jwt_token SQL authentication method.
+var JWTAudience = func() *settings.StringSetting {
+	s := settings.RegisterPublicStringSetting(
+		JWTAudienceSettingName,
+		"sets the audience that tokens must be issued for to be accepted by the jwt_token "+
+			"authentication method; if empty, "+OIDCClientIDSettingName+" is used (this feature is experimental)",
+		"",
+	)
+	s.SetReportable(true)
+	return s
+}()
@@ -114,7 +114,7 @@ func TestVerifyPassword(t *testing.T) {
 	} {
 		t.Run("", func(t *testing.T) {
 			username := security.MakeSQLUsernameFromPreNormalizedString(tc.username)
-			exists, canLogin, pwRetrieveFn, validUntilFn, err := sql.GetUserHashedPassword(context.Background(), &ie, username)
+			exists, canLogin, pwRetrieveFn, validUntilFn, _, err := sql.GetUserHashedPassword(context.Background(), &ie, username)
 
 			if err != nil {
 				t.Errorf(
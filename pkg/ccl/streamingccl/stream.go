@@ -0,0 +1,40 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+// Package streamingccl contains the definitions shared by the producing and
+// consuming sides of a cluster-to-cluster replication stream.
+package streamingccl
+
+import (
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// StreamAddress is the location of the source of a replication stream. It is
+// currently a postgres connection URL pointing at any node of the source
+// cluster.
+type StreamAddress string
+
+// URL parses the stream address as a URL.
+func (sa StreamAddress) URL() (*url.URL, error) {
+	return url.Parse(string(sa))
+}
+
+// CursorOpt is the CREATE REPLICATION STREAM option which sets the timestamp
+// after which changes are streamed. If it is not specified, the stream begins
+// with a scan of the streamed keyspace as of the statement time.
+const CursorOpt = "cursor"
+
+// TenantSpan returns the span covering all of the keys owned by the given
+// tenant.
+func TenantSpan(tenantID roachpb.TenantID) roachpb.Span {
+	prefix := keys.MakeTenantPrefix(tenantID)
+	return roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+}
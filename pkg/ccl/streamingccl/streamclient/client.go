@@ -0,0 +1,51 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+// Package streamclient provides the clients through which a stream ingestion
+// job consumes a replication stream from a source cluster.
+package streamclient
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// Client provides a way for the stream ingestion job to consume a
+// replication stream.
+type Client interface {
+	// ConsumeStream starts streaming the keyspace of the given tenant. Every
+	// change made after startTime is delivered on the returned event channel;
+	// if startTime is empty, the stream begins with a scan of the keyspace
+	// instead. The event channel is closed when the stream ends, after which
+	// the error channel yields the error that ended it, if any. The stream is
+	// torn down when ctx is canceled.
+	ConsumeStream(
+		ctx context.Context, tenantID roachpb.TenantID, startTime hlc.Timestamp,
+	) (chan *roachpb.RangeFeedEvent, chan error, error)
+
+	// Close releases any resources held by the client.
+	Close() error
+}
+
+// NewStreamClient creates a client for the stream at the given address.
+func NewStreamClient(streamAddress streamingccl.StreamAddress) (Client, error) {
+	streamURL, err := streamAddress.URL()
+	if err != nil {
+		return nil, err
+	}
+	switch streamURL.Scheme {
+	case "postgres", "postgresql":
+		return newPGWireClient(streamURL)
+	default:
+		return nil, errors.Newf("stream replication from scheme %q is unsupported", streamURL.Scheme)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamclient
+
+import (
+	"context"
+	gosql "database/sql"
+	"fmt"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	// Load the postgres driver.
+	_ "github.com/lib/pq"
+)
+
+// pgwireClient consumes a replication stream by running CREATE REPLICATION
+// STREAM against the source cluster over a regular SQL connection.
+type pgwireClient struct {
+	db *gosql.DB
+}
+
+var _ Client = &pgwireClient{}
+
+func newPGWireClient(streamURL *url.URL) (*pgwireClient, error) {
+	db, err := gosql.Open("postgres", streamURL.String())
+	if err != nil {
+		return nil, err
+	}
+	return &pgwireClient{db: db}, nil
+}
+
+// ConsumeStream implements the Client interface.
+func (c *pgwireClient) ConsumeStream(
+	ctx context.Context, tenantID roachpb.TenantID, startTime hlc.Timestamp,
+) (chan *roachpb.RangeFeedEvent, chan error, error) {
+	query := fmt.Sprintf(`CREATE REPLICATION STREAM FOR TENANT %d`, tenantID.ToUint64())
+	if !startTime.IsEmpty() {
+		query += fmt.Sprintf(` WITH %s = '%s'`, streamingccl.CursorOpt, startTime.AsOfSystemTime())
+	}
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "creating replication stream")
+	}
+
+	eventCh := make(chan *roachpb.RangeFeedEvent)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		defer close(eventCh)
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			var data []byte
+			if err := rows.Scan(&data); err != nil {
+				errCh <- err
+				return
+			}
+			var ev roachpb.RangeFeedEvent
+			if err := protoutil.Unmarshal(data, &ev); err != nil {
+				errCh <- errors.Wrap(err, "decoding replication stream event")
+				return
+			}
+			select {
+			case eventCh <- &ev:
+			case <-ctx.Done():
+				errCh <- ctx.Err()
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			errCh <- err
+		}
+	}()
+	return eventCh, errCh, nil
+}
+
+// Close implements the Client interface.
+func (c *pgwireClient) Close() error {
+	return c.db.Close()
+}
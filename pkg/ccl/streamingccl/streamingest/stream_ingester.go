@@ -0,0 +1,274 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingest
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streamclient"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/bulk"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+var minimumFlushInterval = settings.RegisterNonNegativeDurationSetting(
+	"bulkio.stream_ingestion.minimum_flush_interval",
+	"the minimum time between flushes of the data received by a stream ingestion job",
+	5*time.Second,
+)
+
+var maxBufferSize = settings.RegisterByteSizeSetting(
+	"bulkio.stream_ingestion.max_buffer_size",
+	"the maximum size of the data received by a stream ingestion job that is buffered "+
+		"before it is flushed, regardless of the minimum flush interval",
+	32<<20, /* 32 MiB */
+)
+
+// streamIngester consumes a replication stream and writes it into the
+// ingesting cluster.
+//
+// KVs received from the stream are buffered until the stream's resolved
+// timestamp advances, at which point the buffer is flushed via AddSSTable and
+// the job's high-water mark is moved to the new resolved timestamp. The buffer
+// is also flushed, without moving the high-water mark, once it grows past
+// bulkio.stream_ingestion.max_buffer_size. Its memory is accounted against the
+// SQL memory monitor. Once the
+// high-water mark reaches the cutover time set on the job's progress, the
+// ingested keyspace is reverted to the cutover time and the ingestion is
+// complete.
+type streamIngester struct {
+	execCfg  *sql.ExecutorConfig
+	job      *jobs.Job
+	span     roachpb.Span
+	tenantID roachpb.TenantID
+
+	// buffer holds the KVs received since the last flush. Their size is
+	// accounted in memAcc.
+	buffer []storage.MVCCKeyValue
+	memAcc mon.BoundAccount
+	// lastFlush is the time of the last flush that advanced the high-water mark.
+	lastFlush time.Time
+}
+
+func newStreamIngester(
+	execCfg *sql.ExecutorConfig, job *jobs.Job, details jobspb.StreamIngestionDetails,
+) *streamIngester {
+	return &streamIngester{
+		execCfg:  execCfg,
+		job:      job,
+		span:     details.Span,
+		tenantID: roachpb.MakeTenantID(details.TenantID),
+	}
+}
+
+// ingest consumes the stream from startTime until the job is cut over.
+func (si *streamIngester) ingest(
+	ctx context.Context, client streamclient.Client, startTime hlc.Timestamp,
+) error {
+	// Cancel the stream when ingestion stops, whether it was cut over or not.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	memMonitor := mon.NewMonitorInheritWithLimit(
+		"stream-ingestion", math.MaxInt64, si.execCfg.DistSQLSrv.ParentMemoryMonitor)
+	memMonitor.Start(ctx, si.execCfg.DistSQLSrv.ParentMemoryMonitor, mon.BoundAccount{})
+	defer memMonitor.Stop(ctx)
+	si.memAcc = memMonitor.MakeBoundAccount()
+	defer si.memAcc.Close(ctx)
+
+	eventCh, errCh, err := client.ConsumeStream(ctx, si.tenantID, startTime)
+	if err != nil {
+		return err
+	}
+
+	frontier := span.MakeFrontier(si.span)
+	frontier.Forward(si.span, startTime)
+	si.lastFlush = timeutil.Now()
+	for {
+		select {
+		case ev, ok := <-eventCh:
+			if !ok {
+				if err := <-errCh; err != nil {
+					return err
+				}
+				return errors.New("replication stream ended unexpectedly")
+			}
+			switch {
+			case ev.Val != nil:
+				if err := si.bufferKV(ctx, storage.MVCCKeyValue{
+					Key:   storage.MVCCKey{Key: ev.Val.Key, Timestamp: ev.Val.Value.Timestamp},
+					Value: ev.Val.Value.RawBytes,
+				}); err != nil {
+					return err
+				}
+			case ev.Checkpoint != nil:
+				if !frontier.Forward(ev.Checkpoint.Span, ev.Checkpoint.ResolvedTS) {
+					continue
+				}
+				interval := minimumFlushInterval.Get(&si.execCfg.Settings.SV)
+				if timeutil.Since(si.lastFlush) < interval {
+					continue
+				}
+				cutoverTime, err := si.checkpoint(ctx, frontier.Frontier())
+				if err != nil {
+					return err
+				}
+				if !cutoverTime.IsEmpty() && cutoverTime.LessEq(frontier.Frontier()) {
+					return si.cutover(ctx, cutoverTime)
+				}
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// bufferKV adds a KV to the buffer, flushing the buffer if it has grown past
+// its maximum size. The buffered data is flushed early if the memory monitor
+// refuses to grow its account.
+func (si *streamIngester) bufferKV(ctx context.Context, keyValue storage.MVCCKeyValue) error {
+	size := int64(len(keyValue.Key.Key) + len(keyValue.Value))
+	if err := si.memAcc.Grow(ctx, size); err != nil {
+		if len(si.buffer) == 0 {
+			return err
+		}
+		if err := si.flush(ctx); err != nil {
+			return err
+		}
+		if err := si.memAcc.Grow(ctx, size); err != nil {
+			return err
+		}
+	}
+	si.buffer = append(si.buffer, keyValue)
+	if si.memAcc.Used() >= maxBufferSize.Get(&si.execCfg.Settings.SV) {
+		return si.flush(ctx)
+	}
+	return nil
+}
+
+// checkpoint flushes the buffered KVs and advances the job's high-water mark to
+// resolved, which must be a timestamp up to which every KV in the stream has
+// been received. It returns the cutover time of the job, if one has been set.
+func (si *streamIngester) checkpoint(
+	ctx context.Context, resolved hlc.Timestamp,
+) (hlc.Timestamp, error) {
+	si.lastFlush = timeutil.Now()
+	if err := si.flush(ctx); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	var cutoverTime hlc.Timestamp
+	if err := si.job.Update(ctx, func(txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+		if err := md.CheckRunningOrReverting(); err != nil {
+			return err
+		}
+		// The first checkpoint of a new stream follows its initial scan, which
+		// only carries the latest revision of each key. Record when it happened,
+		// since the ingested data cannot be cut over to any earlier time.
+		details := md.Payload.GetStreamIngestion()
+		if details.StartTime.IsEmpty() {
+			details.StartTime = resolved
+			ju.UpdatePayload(md.Payload)
+		}
+		md.Progress.Progress = &jobspb.Progress_HighWater{HighWater: &resolved}
+		ju.UpdateProgress(md.Progress)
+
+		cutoverTime = md.Progress.GetStreamIngestion().CutoverTime
+		if !cutoverTime.IsEmpty() && cutoverTime.Less(details.StartTime) {
+			return errors.Newf("cutover time %s precedes the start of the replication stream at %s",
+				cutoverTime, details.StartTime)
+		}
+		return nil
+	}); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	return cutoverTime, nil
+}
+
+// flush writes the buffered KVs into the ingested keyspace.
+func (si *streamIngester) flush(ctx context.Context) error {
+	if len(si.buffer) == 0 {
+		return nil
+	}
+
+	// The SST batcher requires keys in MVCC order. A KV may also have been
+	// received more than once, e.g. when the stream was restarted, and only one
+	// copy of each revision may be added.
+	sort.Slice(si.buffer, func(i, j int) bool {
+		return si.buffer[i].Key.Less(si.buffer[j].Key)
+	})
+
+	settings := si.execCfg.Settings
+	batcher, err := bulk.MakeStreamSSTBatcher(ctx, si.execCfg.DB, settings,
+		func() int64 { return storageccl.MaxImportBatchSize(settings) })
+	if err != nil {
+		return err
+	}
+	defer batcher.Close()
+	for i, keyValue := range si.buffer {
+		if i > 0 && keyValue.Key.Equal(si.buffer[i-1].Key) {
+			continue
+		}
+		if err := batcher.AddMVCCKey(ctx, keyValue.Key, keyValue.Value); err != nil {
+			return err
+		}
+	}
+	if err := batcher.Flush(ctx); err != nil {
+		return err
+	}
+	// Drop the buffer entirely, rather than truncating it, so that the memory
+	// of the flushed KVs is released along with their reservation.
+	si.buffer = nil
+	si.memAcc.Clear(ctx)
+	return nil
+}
+
+// cutover reverts the ingested keyspace to cutoverTime, discarding anything
+// ingested above it, and marks the ingested tenant active.
+func (si *streamIngester) cutover(ctx context.Context, cutoverTime hlc.Timestamp) error {
+	log.Infof(ctx, "cutting over stream ingestion job %d to %s", *si.job.ID(), cutoverTime)
+	if err := si.flush(ctx); err != nil {
+		return err
+	}
+
+	db := si.execCfg.DB
+	for resume := &si.span; resume != nil; {
+		var b kv.Batch
+		b.AddRawRequest(&roachpb.RevertRangeRequest{
+			RequestHeader: roachpb.RequestHeaderFromSpan(*resume),
+			TargetTime:    cutoverTime,
+		})
+		b.Header.MaxSpanRequestKeys = sql.RevertTableDefaultBatchSize
+		if err := db.Run(ctx, &b); err != nil {
+			return err
+		}
+		resume = b.RawResponse().Responses[0].GetRevertRange().ResumeSpan
+		if resume != nil && !resume.Valid() {
+			return errors.Errorf("invalid resume span: %s", resume)
+		}
+	}
+
+	return db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		return sql.ActivateTenant(ctx, si.execCfg, txn, si.tenantID.ToUint64())
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingest
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streamclient"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/streaming"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+type streamIngestionResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &streamIngestionResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (s *streamIngestionResumer) Resume(
+	ctx context.Context, execCtx interface{}, _ chan<- tree.Datums,
+) error {
+	details := s.job.Details().(jobspb.StreamIngestionDetails)
+	p := execCtx.(sql.JobExecContext)
+
+	client, err := streamclient.NewStreamClient(streamingccl.StreamAddress(details.StreamAddress))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			log.Warningf(ctx, "failed to close stream client: %v", err)
+		}
+	}()
+
+	// Resume the stream from the last timestamp up to which all of the
+	// ingested data is known to be complete.
+	startTime := details.StartTime
+	progress := s.job.Progress()
+	if highWater := progress.GetHighWater(); highWater != nil && !highWater.IsEmpty() {
+		startTime = *highWater
+	}
+
+	ingester := newStreamIngester(p.ExecCfg(), s.job, details)
+	return ingester.ingest(ctx, client, startTime)
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (s *streamIngestionResumer) OnFailOrCancel(ctx context.Context, execCtx interface{}) error {
+	// The tenant record and any data ingested so far are left in place, so
+	// that they can be inspected and cleared with crdb_internal.destroy_tenant.
+	return nil
+}
+
+// completeStreamIngestion signals the stream ingestion job with the given ID
+// to cut over at cutoverTimestamp. It implements
+// streaming.CompleteIngestionHook.
+func completeStreamIngestion(
+	evalCtx *tree.EvalContext, txn *kv.Txn, jobID int64, cutoverTimestamp hlc.Timestamp,
+) error {
+	p := evalCtx.Planner.(sql.PlanHookState)
+	if err := p.RequireAdminRole(evalCtx.Ctx(), "complete stream ingestion"); err != nil {
+		return err
+	}
+	if cutoverTimestamp.IsEmpty() {
+		return errors.New("cutover timestamp must be specified")
+	}
+
+	job, err := p.ExecCfg().JobRegistry.LoadJobWithTxn(evalCtx.Ctx(), jobID, txn)
+	if err != nil {
+		return err
+	}
+	details, ok := job.Details().(jobspb.StreamIngestionDetails)
+	if !ok {
+		return errors.Newf("job %d is not a stream ingestion job", jobID)
+	}
+	if cutoverTimestamp.Less(details.StartTime) {
+		return errors.Newf("cutover time %s precedes the start of the replication stream at %s",
+			cutoverTimestamp, details.StartTime)
+	}
+	return job.WithTxn(txn).Update(evalCtx.Ctx(), func(
+		txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		if md.Status != jobs.StatusRunning && md.Status != jobs.StatusPaused {
+			return errors.Newf("cannot complete stream ingestion job %d in state %s", jobID, md.Status)
+		}
+		progress := md.Progress.GetStreamIngestion()
+		if !progress.CutoverTime.IsEmpty() {
+			return errors.Newf("cutover timestamp already set to %s for job %d",
+				progress.CutoverTime, jobID)
+		}
+		progress.CutoverTime = cutoverTimestamp
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
+func init() {
+	streaming.CompleteIngestionHook = completeStreamIngestion
+	jobs.RegisterConstructor(
+		jobspb.TypeStreamIngestion,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &streamIngestionResumer{job: job}
+		},
+	)
+}
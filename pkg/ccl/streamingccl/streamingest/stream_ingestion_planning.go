@@ -0,0 +1,125 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingest
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+const streamIngestionOp = "RESTORE FROM REPLICATION STREAM"
+
+func streamIngestionJobDescription(
+	p sql.PlanHookState, streamIngestion *tree.StreamIngestion,
+) string {
+	ann := p.ExtendedEvalContext().Annotations
+	return tree.AsStringWithFQNames(streamIngestion, ann)
+}
+
+// ingestionPlanHook implements sql.PlanHookFn.
+func ingestionPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	ingestionStmt, ok := stmt.(*tree.StreamIngestion)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	fromFn, err := p.TypeAsStringArray(ctx, tree.Exprs(ingestionStmt.From), streamIngestionOp)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if err := utilccl.CheckEnterpriseEnabled(
+			p.ExecCfg().Settings, p.ExecCfg().ClusterID(), p.ExecCfg().Organization(),
+			streamIngestionOp,
+		); err != nil {
+			return err
+		}
+		if !p.ExecCfg().Codec.ForSystemTenant() {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"only the system tenant can ingest replication streams")
+		}
+		if err := p.RequireAdminRole(ctx, streamIngestionOp); err != nil {
+			return err
+		}
+
+		// Only tenants can currently be replicated, so a stream always ingests
+		// the keyspace of the tenant being restored.
+		tenantID := ingestionStmt.Targets.Tenant
+		if tenantID == (roachpb.TenantID{}) {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"replication streams are only supported for tenants")
+		}
+
+		from, err := fromFn()
+		if err != nil {
+			return err
+		}
+		if len(from) != 1 {
+			return errors.Newf("ingestion from %d stream addresses is unsupported", len(from))
+		}
+		streamAddress := streamingccl.StreamAddress(from[0])
+		if _, err := streamAddress.URL(); err != nil {
+			return errors.Wrapf(err, "invalid stream address %q", streamAddress)
+		}
+
+		jr := jobs.Record{
+			Description: streamIngestionJobDescription(p, ingestionStmt),
+			Username:    p.User(),
+			Details: jobspb.StreamIngestionDetails{
+				StreamAddress: string(streamAddress),
+				Span:          streamingccl.TenantSpan(tenantID),
+				TenantID:      tenantID.ToUint64(),
+			},
+			Progress: jobspb.StreamIngestionProgress{},
+		}
+
+		// The tenant remains in the ADD state, and so is unusable, until the
+		// job is cut over and activates it.
+		txn := p.ExtendedEvalContext().Txn
+		if err := sql.CreateTenantRecord(ctx, p.ExecCfg(), txn, &descpb.TenantInfo{
+			ID:    tenantID.ToUint64(),
+			State: descpb.TenantInfo_ADD,
+		}); err != nil {
+			return err
+		}
+		sj, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(ctx, jr, txn)
+		if err != nil {
+			return err
+		}
+
+		telemetry.Count("replication.ingestion.create")
+		resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(*sj.ID()))}
+		return nil
+	}
+	return fn, utilccl.DetachedJobExecutionResultHeader, nil, false, nil
+}
+
+func init() {
+	sql.AddPlanHook(ingestionPlanHook)
+}
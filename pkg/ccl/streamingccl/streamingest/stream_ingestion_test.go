@@ -0,0 +1,123 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingest
+
+import (
+	"context"
+	gosql "database/sql"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	// Register the CREATE REPLICATION STREAM plan hook on the source server.
+	_ "github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streamproducer"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamIngestionJobEndToEnd(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	defer jobs.TestingSetAdoptAndCancelIntervals(100*time.Millisecond, 100*time.Millisecond)()
+
+	// With a small buffer, the ingested data is flushed before the stream's
+	// resolved timestamp advances and must still be reverted at cutover.
+	testutils.RunTrueAndFalse(t, "small-buffer", func(t *testing.T, smallBuffer bool) {
+		ctx := context.Background()
+		tenantID := roachpb.MakeTenantID(10)
+		tenantPrefix := keys.MakeTenantPrefix(tenantID)
+		tenantKey := func(k string) roachpb.Key {
+			return append(tenantPrefix[:len(tenantPrefix):len(tenantPrefix)], k...)
+		}
+
+		source, sourceConn, sourceKV := serverutils.StartServer(t, base.TestServerArgs{})
+		defer source.Stopper().Stop(ctx)
+		sourceDB := sqlutils.MakeSQLRunner(sourceConn)
+		sourceDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+		sourceDB.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.target_duration = '100ms'`)
+		sourceDB.Exec(t, `SELECT crdb_internal.create_tenant($1)`, tenantID.ToUint64())
+		require.NoError(t, sourceKV.Put(ctx, tenantKey("a"), "1"))
+		require.NoError(t, sourceKV.Put(ctx, tenantKey("b"), "2"))
+
+		dest, destConn, destKV := serverutils.StartServer(t, base.TestServerArgs{})
+		defer dest.Stopper().Stop(ctx)
+		destDB := sqlutils.MakeSQLRunner(destConn)
+		destDB.Exec(t, `SET CLUSTER SETTING bulkio.stream_ingestion.minimum_flush_interval = '0s'`)
+		if smallBuffer {
+			// Flush every KV as soon as it is received, ahead of the checkpoints.
+			destDB.Exec(t, `SET CLUSTER SETTING bulkio.stream_ingestion.max_buffer_size = '1B'`)
+		}
+
+		pgURL, cleanup := sqlutils.PGUrl(t, source.ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+		defer cleanup()
+
+		var jobID int64
+		destDB.QueryRow(t,
+			`RESTORE TENANT 10 FROM REPLICATION STREAM FROM $1`, pgURL.String(),
+		).Scan(&jobID)
+		destDB.CheckQueryResults(t,
+			`SELECT id, active FROM system.tenants`, [][]string{{`10`, `false`}},
+		)
+
+		// Wait for the initial scan of the stream to be ingested, since the stream
+		// cannot be cut over to any earlier time.
+		testutils.SucceedsSoon(t, func() error {
+			var highWater gosql.NullString
+			destDB.QueryRow(t,
+				`SELECT high_water_timestamp FROM crdb_internal.jobs WHERE job_id = $1`, jobID,
+			).Scan(&highWater)
+			if !highWater.Valid {
+				return errors.New("waiting for the initial scan to be ingested")
+			}
+			return nil
+		})
+
+		// Write some data before and some after the cutover time. Only the former
+		// should remain once the stream is cut over. The cutover time has
+		// microsecond precision, so round it up to make sure it falls after the
+		// writes that precede it.
+		require.NoError(t, sourceKV.Put(ctx, tenantKey("a"), "3"))
+		cutoverTime := timeutil.Unix(0, source.Clock().Now().WallTime).
+			Truncate(time.Microsecond).Add(time.Microsecond)
+		time.Sleep(time.Millisecond)
+		require.NoError(t, sourceKV.Put(ctx, tenantKey("b"), "4"))
+		require.NoError(t, sourceKV.Put(ctx, tenantKey("c"), "5"))
+
+		destDB.Exec(t, `SELECT crdb_internal.complete_stream_ingestion_job($1, $2)`, jobID, cutoverTime)
+		destDB.ExpectErr(t, "cutover timestamp already set",
+			`SELECT crdb_internal.complete_stream_ingestion_job($1, $2)`, jobID, cutoverTime)
+		jobutils.WaitForJob(t, destDB, jobID)
+
+		destDB.CheckQueryResults(t,
+			`SELECT id, active FROM system.tenants`, [][]string{{`10`, `true`}},
+		)
+		kvs, err := destKV.Scan(ctx, tenantKey("a"), tenantKey("d"), 0 /* maxRows */)
+		require.NoError(t, err)
+		var actual [][]string
+		for _, keyValue := range kvs {
+			value, err := keyValue.Value.GetBytes()
+			require.NoError(t, err)
+			actual = append(actual, []string{string(keyValue.Key[len(tenantPrefix):]), string(value)})
+		}
+		require.Equal(t, [][]string{{"a", "3"}, {"b", "2"}}, actual)
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamproducer
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+// initialScanPageSize is the number of keys read per request while performing
+// the initial scan of a stream.
+const initialScanPageSize = 10000
+
+// streamTenant emits every change made to the keyspace of the given tenant
+// after startTime to resultsCh, each as a marshaled roachpb.RangeFeedEvent. If
+// initialScan is set, the stream first emits every key in the keyspace as of
+// startTime, followed by a checkpoint for the whole keyspace at startTime.
+// streamTenant only returns when ctx is canceled or the rangefeed fails.
+func streamTenant(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	tenantID roachpb.TenantID,
+	startTime hlc.Timestamp,
+	initialScan bool,
+	resultsCh chan<- tree.Datums,
+) error {
+	span := streamingccl.TenantSpan(tenantID)
+	emit := func(ctx context.Context, ev *roachpb.RangeFeedEvent) error {
+		data, err := protoutil.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		select {
+		case resultsCh <- tree.Datums{tree.NewDBytes(tree.DBytes(data))}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if initialScan {
+		if err := scanSpan(ctx, execCfg.DB, span, startTime, emit); err != nil {
+			return err
+		}
+		if err := emit(ctx, &roachpb.RangeFeedEvent{
+			Checkpoint: &roachpb.RangeFeedCheckpoint{Span: span, ResolvedTS: startTime},
+		}); err != nil {
+			return err
+		}
+	}
+
+	g := ctxgroup.WithContext(ctx)
+	eventCh := make(chan *roachpb.RangeFeedEvent)
+	g.GoCtx(func(ctx context.Context) error {
+		return execCfg.DistSender.RangeFeed(ctx, span, startTime, false /* withDiff */, eventCh)
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		for {
+			select {
+			case ev := <-eventCh:
+				if ev.Error != nil {
+					return ev.Error.Error.GoError()
+				}
+				if err := emit(ctx, ev); err != nil {
+					return err
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	})
+	return g.Wait()
+}
+
+// scanSpan emits every key in span as of the given timestamp as a rangefeed
+// value event. Each value carries the MVCC timestamp at which it was written.
+func scanSpan(
+	ctx context.Context,
+	db *kv.DB,
+	span roachpb.Span,
+	asOf hlc.Timestamp,
+	emit func(context.Context, *roachpb.RangeFeedEvent) error,
+) error {
+	start := span.Key
+	for {
+		var kvs []kv.KeyValue
+		if err := db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			txn.SetFixedTimestamp(ctx, asOf)
+			var err error
+			kvs, err = txn.Scan(ctx, start, span.EndKey, initialScanPageSize)
+			return err
+		}); err != nil {
+			return err
+		}
+		for _, keyValue := range kvs {
+			if err := emit(ctx, &roachpb.RangeFeedEvent{
+				Val: &roachpb.RangeFeedValue{Key: keyValue.Key, Value: *keyValue.Value},
+			}); err != nil {
+				return err
+			}
+		}
+		if len(kvs) < initialScanPageSize {
+			return nil
+		}
+		start = kvs[len(kvs)-1].Key.Next()
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamproducer
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+const createReplicationStreamOp = "CREATE REPLICATION STREAM"
+
+var replicationStreamOptionExpectValues = map[string]sql.KVStringOptValidate{
+	streamingccl.CursorOpt: sql.KVStringOptRequireValue,
+}
+
+// replicationStreamHeader is the header of the rows returned by a
+// replication stream. Each row holds a single marshaled
+// roachpb.RangeFeedEvent.
+var replicationStreamHeader = colinfo.ResultColumns{
+	{Name: "event", Typ: types.Bytes},
+}
+
+func init() {
+	sql.AddPlanHook(createReplicationStreamHook)
+}
+
+// createReplicationStreamHook implements sql.PlanHookFn.
+func createReplicationStreamHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	stream, ok := stmt.(*tree.ReplicationStream)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+	if stream.Targets.Tenant == (roachpb.TenantID{}) {
+		return nil, nil, nil, false, pgerror.New(pgcode.FeatureNotSupported,
+			"replication streams are only supported for tenants")
+	}
+
+	optsFn, err := p.TypeAsStringOpts(ctx, stream.Options, replicationStreamOptionExpectValues)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if !p.ExecCfg().Codec.ForSystemTenant() {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"only the system tenant can create replication streams")
+		}
+		if err := p.RequireAdminRole(ctx, createReplicationStreamOp); err != nil {
+			return err
+		}
+
+		settings := p.ExecCfg().Settings
+		// Replication streams are based on the Rangefeed abstraction, which
+		// requires the `kv.rangefeed.enabled` setting to be true.
+		if !kvserver.RangefeedEnabled.Get(&settings.SV) {
+			return errors.Errorf("replication streams require the kv.rangefeed.enabled setting")
+		}
+		if err := utilccl.CheckEnterpriseEnabled(
+			settings, p.ExecCfg().ClusterID(), p.ExecCfg().Organization(), createReplicationStreamOp,
+		); err != nil {
+			return err
+		}
+
+		opts, err := optsFn()
+		if err != nil {
+			return err
+		}
+
+		startTime := hlc.Timestamp{
+			WallTime: p.ExtendedEvalContext().GetStmtTimestamp().UnixNano(),
+		}
+		initialScan := true
+		if cursor, ok := opts[streamingccl.CursorOpt]; ok {
+			asOf := tree.AsOfClause{Expr: tree.NewStrVal(cursor)}
+			if startTime, err = p.EvalAsOfTimestamp(ctx, asOf); err != nil {
+				return err
+			}
+			initialScan = false
+		}
+
+		telemetry.Count("replication.stream.create")
+		return streamTenant(ctx, p.ExecCfg(), stream.Targets.Tenant, startTime, initialScan, resultsCh)
+	}
+	// The stream never completes on its own, so its rows must be sent to the
+	// client as they are produced.
+	return fn, replicationStreamHeader, nil, true /* avoidBuffering */, nil
+}
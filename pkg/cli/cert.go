@@ -196,6 +196,43 @@ func runCreateClientCert(cmd *cobra.Command, args []string) error {
 		"failed to generate client certificate and key")
 }
 
+// A rotateCerts command re-signs the node and client certificates in the
+// cert directory with the CA key, keeping their existing keys.
+var rotateCertsCmd = &cobra.Command{
+	Use:     "rotate --certs-dir=<path to cockroach certs dir> --ca-key=<path-to-ca-key>",
+	Aliases: []string{"renew"},
+	Short:   "re-sign node and client certificates",
+	Long: `
+Re-sign the node certificate "<certs-dir>/node.crt" and the client certificates
+"<certs-dir>/client.<username>.crt" using the CA key. The subject, hosts and
+key of each certificate are preserved, only the validity period changes.
+Existing key files are left untouched.
+
+Requires a CA cert in "<certs-dir>/ca.crt" and matching key in "--ca-key".
+Only certificates issued by "ca.crt" are rotated.
+Rotation fails if the CA expiration time is before the desired certificate expiration.
+
+Running nodes pick up the rotated certificates automatically when they next
+scan the certs directory, or immediately upon receiving SIGHUP. Rotated
+certificates get new serial numbers. The serial numbers of the certificates
+loaded by each node are listed by the "/_status/certificates/<node_id>"
+endpoint and the certificates report of the admin UI, which show which nodes
+still present the old certificates.
+`,
+	Args: cobra.NoArgs,
+	RunE: MaybeDecorateGRPCError(runRotateCerts),
+}
+
+// runRotateCerts re-signs the existing node and client certificates.
+func runRotateCerts(cmd *cobra.Command, args []string) error {
+	return errors.Wrap(
+		security.RotateCerts(
+			baseCfg.SSLCertsDir,
+			baseCfg.SSLCAKey,
+			certificateLifetime),
+		"failed to rotate certificates")
+}
+
 // A listCerts command generates a client certificate and stores it
 // in the cert directory under <username>.crt and key under <username>.key.
 var listCertsCmd = &cobra.Command{
@@ -307,6 +344,7 @@ var certCmds = []*cobra.Command{
 	createClientCACertCmd,
 	createNodeCertCmd,
 	createClientCertCmd,
+	rotateCertsCmd,
 	listCertsCmd,
 }
 
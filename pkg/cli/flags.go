@@ -487,11 +487,14 @@ func init() {
 	for _, cmd := range []*cobra.Command{
 		createNodeCertCmd,
 		createClientCertCmd,
+		rotateCertsCmd,
 		mtCreateTenantClientCertCmd,
 	} {
 		f := cmd.Flags()
 		durationFlag(f, &certificateLifetime, cliflags.CertificateLifetime)
 	}
+	// Rotation re-signs existing certificates with the CA key.
+	stringFlag(rotateCertsCmd.Flags(), &baseCfg.SSLCAKey, cliflags.CAKey)
 
 	// The remaining flags are shared between all cert-generating functions.
 	for _, cmd := range []*cobra.Command{
@@ -61,11 +61,18 @@ requires the cluster to be live.
 
 // Tables containing cluster-wide info that are collected in a debug zip.
 var debugZipTablesPerCluster = []string{
+	"crdb_internal.cluster_contended_indexes",
+	"crdb_internal.cluster_contended_keys",
+	"crdb_internal.cluster_contended_tables",
+	"crdb_internal.cluster_contention_events",
 	"crdb_internal.cluster_queries",
 	"crdb_internal.cluster_sessions",
 	"crdb_internal.cluster_settings",
 	"crdb_internal.cluster_transactions",
 
+	"crdb_internal.statement_statistics",
+	"crdb_internal.transaction_statistics",
+
 	"crdb_internal.jobs",
 	"system.jobs",       // get the raw, restorable jobs records too.
 	"system.descriptor", // descriptors also contain job-like mutation state.
@@ -93,6 +100,7 @@ var debugZipTablesPerNode = []string{
 	"crdb_internal.leases",
 
 	"crdb_internal.node_build_info",
+	"crdb_internal.node_contention_events",
 	"crdb_internal.node_metrics",
 	"crdb_internal.node_queries",
 	"crdb_internal.node_runtime_info",
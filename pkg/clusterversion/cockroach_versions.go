@@ -199,6 +199,29 @@ const (
 	// EmptyArraysInInvertedIndexes is when empty arrays are added to array
 	// inverted indexes.
 	EmptyArraysInInvertedIndexes
+	// SkipLockedWaitPolicy is when the SkipLocked lock wait policy is
+	// introduced, which backs SELECT ... FOR UPDATE SKIP LOCKED.
+	SkipLockedWaitPolicy
+	// ReplicatedLocks is when locking reads can acquire replicated Shared and
+	// Exclusive locks that are stored in the replicated lock table key space.
+	ReplicatedLocks
+	// TenantUsageTable is when the system.tenant_usage table is introduced,
+	// which holds the request unit budget of each tenant.
+	TenantUsageTable
+	// PersistedSQLStats is when the system.statement_statistics and
+	// system.transaction_statistics tables are introduced, and nodes start
+	// flushing their in-memory SQL statistics into them.
+	PersistedSQLStats
+	// StatementHints is when the system.statement_hints table is introduced,
+	// which holds optimizer hints keyed by statement fingerprint.
+	StatementHints
+	// PartialTableStats is when the partialPredicate column is added to
+	// system.table_statistics, and CREATE STATISTICS ... USING EXTREMES can
+	// be used to collect partial statistics.
+	PartialTableStats
+	// TSearchTypes is when the tsquery and tsvector types can be used for
+	// table columns.
+	TSearchTypes
 
 	// Step (1): Add new versions here.
 )
@@ -321,6 +344,34 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     EmptyArraysInInvertedIndexes,
 		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 4},
 	},
+	{
+		Key:     SkipLockedWaitPolicy,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 6},
+	},
+	{
+		Key:     ReplicatedLocks,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 8},
+	},
+	{
+		Key:     TenantUsageTable,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 10},
+	},
+	{
+		Key:     PersistedSQLStats,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 12},
+	},
+	{
+		Key:     StatementHints,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 14},
+	},
+	{
+		Key:     PartialTableStats,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 16},
+	},
+	{
+		Key:     TSearchTypes,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 18},
+	},
 
 	// Step (2): Add new versions here.
 })
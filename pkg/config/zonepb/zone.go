@@ -383,6 +383,11 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.GC = &tempGC
 		}
 	}
+	if z.GlobalReads == nil {
+		if parent.GlobalReads != nil {
+			z.GlobalReads = proto.Bool(*parent.GlobalReads)
+		}
+	}
 	if z.InheritedConstraints {
 		if !parent.InheritedConstraints {
 			z.Constraints = parent.Constraints
@@ -425,6 +430,12 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 				z.GC = &tempGC
 			}
 		}
+		if fieldName == "global_reads" {
+			z.GlobalReads = nil
+			if other.GlobalReads != nil {
+				z.GlobalReads = proto.Bool(*other.GlobalReads)
+			}
+		}
 		if fieldName == "constraints" {
 			z.Constraints = other.Constraints
 			z.InheritedConstraints = other.InheritedConstraints
@@ -390,6 +390,10 @@ const (
 	ScheduledJobsTableID                = 37
 	TenantsRangesID                     = 38 // pseudo
 	SqllivenessID                       = 39
+	TenantUsageTableID                  = 40
+	StatementStatisticsTableID          = 41
+	TransactionStatisticsTableID        = 42
+	StatementHintsTableID               = 43
 
 	// CommentType is type for system.comments
 	DatabaseCommentType = 0
@@ -48,7 +48,10 @@ type Batch struct {
 	// The Header which will be used to send the resulting BatchRequest.
 	// To be modified directly.
 	Header roachpb.Header
-	reqs   []roachpb.RequestUnion
+	// The AdmissionHeader which will be used when sending the resulting
+	// BatchRequest. To be modified directly.
+	AdmissionHeader roachpb.AdmissionHeader
+	reqs            []roachpb.RequestUnion
 	// Set when AddRawRequest is used, in which case using the "other"
 	// operations renders the batch unusable.
 	raw bool
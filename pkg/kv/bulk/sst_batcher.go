@@ -70,6 +70,10 @@ type SSTBatcher struct {
 	// maintain uniform behavior, duplicates in the same batch with equal values
 	// will not raise a DuplicateKeyError.
 	skipDuplicates bool
+	// allows multiple revisions of the same key, with distinct timestamps, to be
+	// added to the same batch. This is true when ingesting a replication stream,
+	// which carries the MVCC history of the keys it covers.
+	allowRevisions bool
 
 	// The rest of the fields accumulated state as opposed to configuration. Some,
 	// like totalRows, are accumulated _across_ batches and are not reset between
@@ -95,6 +99,7 @@ type SSTBatcher struct {
 	batchStartKey   []byte
 	batchEndKey     []byte
 	batchEndValue   []byte
+	batchEndTS      hlc.Timestamp
 	flushKeyChecked bool
 	flushKey        roachpb.Key
 	// stores on-the-fly stats for the SST if disallowShadowing is true.
@@ -112,6 +117,19 @@ func MakeSSTBatcher(
 	return b, err
 }
 
+// MakeStreamSSTBatcher makes a ready-to-use SSTBatcher for ingesting a
+// replication stream. Unlike MakeSSTBatcher, the returned batcher allows keys
+// to shadow existing keys, since a stream carries successive revisions of the
+// same key both within and across flushes. Revisions of a key must be added in
+// MVCC order, i.e. newest first.
+func MakeStreamSSTBatcher(
+	ctx context.Context, db SSTSender, settings *cluster.Settings, flushBytes func() int64,
+) (*SSTBatcher, error) {
+	b := &SSTBatcher{db: db, settings: settings, maxSize: flushBytes, allowRevisions: true}
+	err := b.Reset(ctx)
+	return b, err
+}
+
 func (b *SSTBatcher) updateMVCCStats(key storage.MVCCKey, value []byte) {
 	metaKeySize := int64(len(key.Key)) + 1
 	metaValSize := int64(0)
@@ -133,7 +151,8 @@ func (b *SSTBatcher) updateMVCCStats(key storage.MVCCKey, value []byte) {
 // keys -- like RESTORE where we want the restored data to look the like backup.
 // Keys must be added in order.
 func (b *SSTBatcher) AddMVCCKey(ctx context.Context, key storage.MVCCKey, value []byte) error {
-	if len(b.batchEndKey) > 0 && bytes.Equal(b.batchEndKey, key.Key) {
+	if len(b.batchEndKey) > 0 && bytes.Equal(b.batchEndKey, key.Key) &&
+		!(b.allowRevisions && key.Timestamp.Less(b.batchEndTS)) {
 		if b.skipDuplicates && bytes.Equal(b.batchEndValue, value) {
 			return nil
 		}
@@ -156,6 +175,7 @@ func (b *SSTBatcher) AddMVCCKey(ctx context.Context, key storage.MVCCKey, value
 	}
 	b.batchEndKey = append(b.batchEndKey[:0], key.Key...)
 	b.batchEndValue = append(b.batchEndValue[:0], value...)
+	b.batchEndTS = key.Timestamp
 
 	if err := b.rowCounter.Count(key.Key); err != nil {
 		return err
@@ -184,6 +204,7 @@ func (b *SSTBatcher) Reset(ctx context.Context) error {
 	b.batchStartKey = b.batchStartKey[:0]
 	b.batchEndKey = b.batchEndKey[:0]
 	b.batchEndValue = b.batchEndValue[:0]
+	b.batchEndTS = hlc.Timestamp{}
 	b.flushKey = nil
 	b.flushKeyChecked = false
 	b.ms.Reset()
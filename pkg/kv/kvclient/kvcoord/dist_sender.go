@@ -24,11 +24,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/rpc/nodedialer"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -275,6 +278,11 @@ type DistSender struct {
 	// If set, the DistSender will try the replicas in the order they appear in
 	// the descriptor, instead of trying to reorder them by latency.
 	dontReorderReplicas bool
+
+	// kvInterceptor, if set, is notified of the requests sent to each range
+	// and of their responses. It is used by tenants to account for (and
+	// throttle) their KV usage.
+	kvInterceptor multitenant.TenantSideKVInterceptor
 }
 
 var _ kv.Sender = &DistSender{}
@@ -312,6 +320,11 @@ type DistSenderConfig struct {
 	FirstRangeProvider FirstRangeProvider
 	RangeDescriptorDB  RangeDescriptorDB
 
+	// KVInterceptor is set for tenants; when set, information about all
+	// KV requests is passed through this interceptor, which can potentially
+	// throttle requests.
+	KVInterceptor multitenant.TenantSideKVInterceptor
+
 	TestingKnobs ClientTestingKnobs
 }
 
@@ -359,6 +372,7 @@ func NewDistSender(cfg DistSenderConfig) *DistSender {
 		ds.transportFactory = GRPCTransportFactory
 	}
 	ds.dontReorderReplicas = cfg.TestingKnobs.DontReorderReplicas
+	ds.kvInterceptor = cfg.KVInterceptor
 	ds.rpcRetryOptions = base.DefaultRetryOptions()
 	if cfg.RPCRetryOptions != nil {
 		ds.rpcRetryOptions = *cfg.RPCRetryOptions
@@ -807,9 +821,11 @@ func (ds *DistSender) Send(
 		for _, rpl := range rplChunks[1:] {
 			reply.Responses = append(reply.Responses, rpl.Responses...)
 			reply.CollectedSpans = append(reply.CollectedSpans, rpl.CollectedSpans...)
+			reply.ContentionEvents = append(reply.ContentionEvents, rpl.ContentionEvents...)
 		}
 		lastHeader := rplChunks[len(rplChunks)-1].BatchResponse_Header
 		lastHeader.CollectedSpans = reply.CollectedSpans
+		lastHeader.ContentionEvents = reply.ContentionEvents
 		reply.BatchResponse_Header = lastHeader
 	}
 
@@ -1146,6 +1162,12 @@ func (ds *DistSender) divideAndSendBatchToRanges(
 		mismatch := roachpb.NewRangeKeyMismatchError(ctx, rs.Key.AsRawKey(), rs.EndKey.AsRawKey(), ri.Desc(), nil /* lease */)
 		return nil, roachpb.NewError(mismatch)
 	}
+	// Bounded staleness reads negotiate their timestamp with the range that
+	// serves them, so they cannot be split across ranges.
+	if ba.BoundedStaleness != nil {
+		return nil, roachpb.NewError(unimplemented.Newf("bounded staleness multi-range",
+			"bounded staleness read cannot span multiple ranges: %s", rs))
+	}
 	// If there's no transaction and ba spans ranges, possibly re-run as part of
 	// a transaction for consistency. The case where we don't need to re-run is
 	// if the read consistency is not required.
@@ -1811,6 +1833,16 @@ func (ds *DistSender) sendToReplicas(
 	}
 	defer transport.Release()
 
+	// Account for the request with the tenant's cost controller, which may
+	// delay the request if the tenant has exhausted its budget.
+	var reqInfo tenantcostmodel.RequestInfo
+	if ds.kvInterceptor != nil {
+		reqInfo = tenantcostmodel.MakeRequestInfo(&ba)
+		if err := ds.kvInterceptor.OnRequestWait(ctx, reqInfo); err != nil {
+			return nil, err
+		}
+	}
+
 	// inTransferRetry is used to slow down retries in cases where an ongoing
 	// lease transfer is suspected.
 	inTransferRetry := retry.StartWithCtx(ctx, ds.rpcRetryOptions)
@@ -1971,6 +2003,9 @@ func (ds *DistSender) sendToReplicas(
 					log.VEventf(ctx, 2, "received updated range info: %s", br.RangeInfos)
 					routing.EvictAndReplace(ctx, br.RangeInfos...)
 				}
+				if ds.kvInterceptor != nil {
+					ds.kvInterceptor.OnResponse(ctx, reqInfo, tenantcostmodel.MakeResponseInfo(br))
+				}
 				return br, nil
 			}
 
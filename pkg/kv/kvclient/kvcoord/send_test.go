@@ -67,6 +67,12 @@ func (n Node) Join(context.Context, *roachpb.JoinNodeRequest) (*roachpb.JoinNode
 	panic("unimplemented")
 }
 
+func (n Node) TokenBucket(
+	context.Context, *roachpb.TokenBucketRequest,
+) (*roachpb.TokenBucketResponse, error) {
+	panic("unimplemented")
+}
+
 func (n Node) ResetQuorum(
 	context.Context, *roachpb.ResetQuorumRequest,
 ) (*roachpb.ResetQuorumResponse, error) {
@@ -17,9 +17,12 @@ import (
 	"runtime/debug"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -438,6 +441,9 @@ func (tc *TxnCoordSender) commitReadOnlyTxnLocked(
 	// Also so that the correct metric gets incremented.
 	tc.mu.txn.Status = roachpb.COMMITTED
 	tc.cleanupTxnLocked(ctx)
+	if err := tc.maybeCommitWait(ctx, tc.mu.txn.WriteTimestamp); err != nil {
+		return roachpb.NewError(err)
+	}
 	return nil
 }
 
@@ -495,6 +501,8 @@ func (tc *TxnCoordSender) Send(
 	// worrying about synchronization.
 	ba.Txn = tc.mu.txn.Clone()
 
+	ba = tc.maybeReplicateLockingLocked(ctx, ba)
+
 	// Send the command through the txnInterceptor stack.
 	br, pErr := tc.interceptorStack[0].SendLocked(ctx, ba)
 
@@ -509,6 +517,9 @@ func (tc *TxnCoordSender) Send(
 				tc.mu.txnState = txnFinalized
 				tc.cleanupTxnLocked(ctx)
 				tc.maybeSleepForLinearizable(ctx, br, startNs)
+				if err := tc.maybeCommitWait(ctx, br.Txn.WriteTimestamp); err != nil {
+					pErr = roachpb.NewError(err)
+				}
 			}
 		} else {
 			// Rollbacks always move us to txnFinalized.
@@ -553,6 +564,89 @@ func (tc *TxnCoordSender) maybeSleepForLinearizable(
 	}
 }
 
+// maybeCommitWait waits until the local clock exceeds the commit timestamp of
+// the transaction if that timestamp is synthetic. Transactions that write to
+// ranges which close timestamps in the future are pushed to a synthetic
+// timestamp ahead of present time. Before acknowledging such a commit, the
+// coordinator must wait out the difference so that any causally dependent
+// transaction is guaranteed to observe the writes, no matter which gateway it
+// goes through.
+func (tc *TxnCoordSender) maybeCommitWait(ctx context.Context, commitTS hlc.Timestamp) error {
+	if !commitTS.IsFlagSet(hlc.TimestampFlag_SYNTHETIC) {
+		return nil
+	}
+	if wait := commitTS.GoTime().Sub(tc.clock.Now().GoTime()); wait > 0 {
+		log.VEventf(ctx, 2, "waiting %s on EndTxn for commit-wait of synthetic timestamp %s",
+			duration.Truncate(wait, time.Millisecond), commitTS)
+	}
+	return tc.clock.SleepUntil(ctx, commitTS)
+}
+
+// maybeReplicateLockingLocked determines the durability of the locks acquired
+// by locking reads in the batch. If replicated locking is enabled, all locking
+// scans are instructed to acquire replicated locks. Otherwise, Exclusive
+// locking scans acquire unreplicated locks and Shared locking scans, which are
+// only supported with replicated durability, are performed without locking.
+// Locking scans that already request replicated locks are left untouched.
+//
+// The requests in the batch are never mutated in place. Instead, the batch's
+// request slice is forked and the modified requests are shallow copied.
+func (tc *TxnCoordSender) maybeReplicateLockingLocked(
+	ctx context.Context, ba roachpb.BatchRequest,
+) roachpb.BatchRequest {
+	replicated := tc.typ == kv.RootTxn &&
+		storage.ReplicatedLockingEnabled.Get(&tc.st.SV) &&
+		tc.st.Version.IsActive(ctx, clusterversion.ReplicatedLocks)
+	adjust := func(keyLocking *lock.Strength, replicatedLocking *bool) {
+		if replicated {
+			*replicatedLocking = true
+		} else {
+			*keyLocking = lock.None
+		}
+	}
+
+	forked := false
+	for i, ru := range ba.Requests {
+		var newReq roachpb.Request
+		switch req := ru.GetInner().(type) {
+		case *roachpb.ScanRequest:
+			if !needsLockingAdjustment(req.KeyLocking, req.ReplicatedLocking, replicated) {
+				continue
+			}
+			reqCopy := *req
+			adjust(&reqCopy.KeyLocking, &reqCopy.ReplicatedLocking)
+			newReq = &reqCopy
+		case *roachpb.ReverseScanRequest:
+			if !needsLockingAdjustment(req.KeyLocking, req.ReplicatedLocking, replicated) {
+				continue
+			}
+			reqCopy := *req
+			adjust(&reqCopy.KeyLocking, &reqCopy.ReplicatedLocking)
+			newReq = &reqCopy
+		default:
+			continue
+		}
+		if !forked {
+			oldReqs := ba.Requests
+			ba.Requests = make([]roachpb.RequestUnion, len(oldReqs))
+			copy(ba.Requests, oldReqs)
+			forked = true
+		}
+		ba.Requests[i].MustSetInner(newReq)
+	}
+	return ba
+}
+
+// needsLockingAdjustment returns whether a locking scan with the provided
+// locking strength and durability needs to be adjusted by
+// maybeReplicateLockingLocked.
+func needsLockingAdjustment(keyLocking lock.Strength, replicatedLocking, replicated bool) bool {
+	if keyLocking == lock.None || replicatedLocking {
+		return false
+	}
+	return replicated || keyLocking == lock.Shared
+}
+
 // maybeRejectClientLocked checks whether the transaction is in a state that
 // prevents it from continuing, such as the heartbeat having detected the
 // transaction to have been aborted.
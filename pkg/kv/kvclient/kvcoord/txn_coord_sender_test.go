@@ -21,8 +21,10 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage"
@@ -2191,6 +2193,93 @@ func TestTxnCoordSenderPipelining(t *testing.T) {
 	}
 }
 
+// TestTxnCoordSenderReplicatedLocking tests that locking scans are instructed
+// to acquire replicated locks when replicated locking is enabled, that Shared
+// locking scans are performed without locking when it is not, and that the
+// replicated locks are released when the transaction commits.
+func TestTxnCoordSenderReplicatedLocking(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s := createTestDB(t)
+	defer s.Stop()
+	distSender := s.DB.GetFactory().(*TxnCoordSenderFactory).NonTransactionalSender()
+
+	var scans []roachpb.ScanRequest
+	var senderFn kv.SenderFunc = func(
+		ctx context.Context, ba roachpb.BatchRequest,
+	) (*roachpb.BatchResponse, *roachpb.Error) {
+		for _, ru := range ba.Requests {
+			if scan, ok := ru.GetInner().(*roachpb.ScanRequest); ok {
+				scans = append(scans, *scan)
+			}
+		}
+		return distSender.Send(ctx, ba)
+	}
+
+	ambientCtx := log.AmbientContext{Tracer: tracing.NewTracer()}
+	tsf := NewTxnCoordSenderFactory(TxnCoordSenderFactoryConfig{
+		AmbientCtx: ambientCtx,
+		Settings:   s.Cfg.Settings,
+		Clock:      s.Clock,
+		Stopper:    s.Stopper(),
+	}, senderFn)
+	db := kv.NewDB(ambientCtx, tsf, s.Clock, s.Stopper())
+	require.NoError(t, db.Put(ctx, "a", "val"))
+
+	lockingScan := func(str lock.Strength) error {
+		return db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			var ba roachpb.BatchRequest
+			ba.Add(&roachpb.ScanRequest{
+				RequestHeader: roachpb.RequestHeader{Key: roachpb.Key("a"), EndKey: roachpb.Key("b")},
+				KeyLocking:    str,
+			})
+			br, pErr := txn.Send(ctx, ba)
+			if pErr != nil {
+				return pErr.GoError()
+			}
+			require.Len(t, br.Responses[0].GetScan().Rows, 1)
+			return nil
+		})
+	}
+
+	testutils.RunTrueAndFalse(t, "replicated", func(t *testing.T, replicated bool) {
+		storage.ReplicatedLockingEnabled.Override(&s.Cfg.Settings.SV, replicated)
+		for _, str := range []lock.Strength{lock.Shared, lock.Exclusive} {
+			scans = nil
+			require.NoError(t, lockingScan(str))
+			require.Len(t, scans, 1)
+			require.Equal(t, replicated, scans[0].ReplicatedLocking)
+			expStr := str
+			if !replicated && str == lock.Shared {
+				expStr = lock.None
+			}
+			require.Equal(t, expStr, scans[0].KeyLocking)
+		}
+
+		// All replicated locks are released once the transactions commit.
+		testutils.SucceedsSoon(t, func() error {
+			ltStart, _ := keys.LockTableSingleKey(roachpb.KeyMin, nil)
+			ltEnd, _ := keys.LockTableSingleKey(roachpb.KeyMax, nil)
+			iter := s.Eng.NewEngineIterator(storage.IterOptions{LowerBound: ltStart, UpperBound: ltEnd})
+			defer iter.Close()
+			valid, err := iter.SeekEngineKeyGE(storage.EngineKey{Key: ltStart})
+			if err != nil {
+				return err
+			}
+			if valid {
+				key, err := iter.EngineKey()
+				if err != nil {
+					return err
+				}
+				return errors.Errorf("unexpected lock table key %s", key)
+			}
+			return nil
+		})
+	})
+}
+
 // Test that a txn's anchor is set to the first write key in batches mixing
 // reads with writes.
 func TestAnchorKey(t *testing.T) {
@@ -14,6 +14,7 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/errors"
@@ -85,9 +86,10 @@ func (s *txnSeqNumAllocator) SendLocked(
 	for _, ru := range ba.Requests {
 		req := ru.GetInner()
 		// Only increment the sequence number generator for requests that
-		// will leave intents or requests that will commit the transaction.
-		// This enables ba.IsCompleteTransaction to work properly.
-		if roachpb.IsIntentWrite(req) || req.Method() == roachpb.EndTxn {
+		// will leave intents or replicated locks or requests that will commit
+		// the transaction. This enables ba.IsCompleteTransaction to work
+		// properly.
+		if roachpb.IsIntentWrite(req) || isReplicatedLockingRead(req) || req.Method() == roachpb.EndTxn {
 			s.writeSeq++
 		}
 
@@ -105,6 +107,15 @@ func (s *txnSeqNumAllocator) SendLocked(
 	return s.wrapped.SendLocked(ctx, ba)
 }
 
+// isReplicatedLockingRead returns whether the request is a locking read that
+// acquires replicated locks. Like intent writes, such requests leave behind
+// replicated state that must be cleaned up when the transaction is finalized,
+// so they prevent the transaction from committing in one phase.
+func isReplicatedLockingRead(req roachpb.Request) bool {
+	return !roachpb.IsIntentWrite(req) && roachpb.IsLocking(req) &&
+		roachpb.LockingDurability(req) == lock.Replicated
+}
+
 // setWrapped is part of the txnInterceptor interface.
 func (s *txnSeqNumAllocator) setWrapped(wrapped lockedSender) { s.wrapped = wrapped }
 
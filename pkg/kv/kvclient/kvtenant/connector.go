@@ -57,6 +57,20 @@ type Connector interface {
 	// obviates the need for SQL-only tenant processes to join the cluster-wide
 	// gossip network.
 	config.SystemConfigProvider
+
+	// Connector is capable of reporting the resource consumption of the tenant
+	// and of requesting Request Units from the tenant's global token bucket,
+	// which is maintained by the KV nodes.
+	TokenBucketProvider
+}
+
+// TokenBucketProvider supplies an endpoint (to tenants) for the TokenBucket API
+// (defined in roachpb.Internal), used to distribute Request Units among the
+// SQL pods of a tenant.
+type TokenBucketProvider interface {
+	TokenBucket(
+		ctx context.Context, in *roachpb.TokenBucketRequest,
+	) (*roachpb.TokenBucketResponse, error)
 }
 
 // ConnectorConfig encompasses the configuration required to create a Connector.
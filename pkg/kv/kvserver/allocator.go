@@ -216,6 +216,7 @@ type RangeUsageInfo struct {
 	LogicalBytes     int64
 	QueriesPerSecond float64
 	WritesPerSecond  float64
+	CPUPerSecond     float64
 }
 
 func rangeUsageInfoForRepl(repl *Replica) RangeUsageInfo {
@@ -228,6 +229,9 @@ func rangeUsageInfoForRepl(repl *Replica) RangeUsageInfo {
 	if writesPerSecond, dur := repl.writeStats.avgQPS(); dur >= MinStatsDuration {
 		info.WritesPerSecond = writesPerSecond
 	}
+	if cpuPerSecond, dur := repl.cpuStats.avgNanosPerSecond(); dur >= MinStatsDuration {
+		info.CPUPerSecond = cpuPerSecond
+	}
 	return info
 }
 
@@ -927,6 +927,7 @@ func TestAllocatorRebalanceTarget(t *testing.T) {
 
 	repl.leaseholderStats = newReplicaStats(clock, nil)
 	repl.writeStats = newReplicaStats(clock, nil)
+	repl.cpuStats = newReplicaStats(clock, nil)
 
 	var rangeUsageInfo RangeUsageInfo
 
@@ -14,6 +14,7 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -31,10 +32,15 @@ func Get(
 	h := cArgs.Header
 	reply := resp.(*roachpb.GetResponse)
 
-	val, intent, err := storage.MVCCGet(ctx, reader, args.Key, h.Timestamp, storage.MVCCGetOptions{
+	opts := storage.MVCCGetOptions{
 		Inconsistent: h.ReadConsistency != roachpb.CONSISTENT,
 		Txn:          h.Txn,
-	})
+	}
+	if h.WaitPolicy == lock.WaitPolicy_SkipLocked {
+		opts.SkipLocked = true
+		opts.LockTable = cArgs.Concurrency
+	}
+	val, intent, err := storage.MVCCGet(ctx, reader, args.Key, h.Timestamp, opts)
 	if err != nil {
 		return result.Result{}, err
 	}
@@ -158,7 +158,12 @@ func Subsume(
 	// is subsumed, we ensure that the initial MLAI update broadcast by the new
 	// leaseholder respects the invariant in question, in much the same way we do
 	// here. Take a look at `EmitMLAI()` in replica_closedts.go for more details.
-	_, untrack := cArgs.EvalCtx.GetTracker().Track(ctx)
+	//
+	// If the range closes timestamps in the future, the closed timestamps
+	// published before the range was subsumed may lead the current time. The
+	// freeze time is forwarded past all of them, so that the merged range is
+	// not permitted to write below them on the RHS's keyspace.
+	minProp, untrack := cArgs.EvalCtx.GetTracker().Track(ctx, cArgs.EvalCtx.GetClosedTimestampPolicy())
 	lease, _ := cArgs.EvalCtx.GetLease()
 	lai := cArgs.EvalCtx.GetLeaseAppliedIndex()
 	untrack(ctx, ctpb.Epoch(lease.Epoch), desc.RangeID, ctpb.LAI(lai+1))
@@ -171,6 +176,7 @@ func Subsume(
 	reply.MVCCStats = cArgs.EvalCtx.GetMVCCStats()
 	reply.LeaseAppliedIndex = lai
 	reply.FreezeStart = cArgs.EvalCtx.Clock().Now()
+	reply.FreezeStart.Forward(minProp)
 
 	return result.Result{
 		Local: result.LocalResult{FreezeStart: reply.FreezeStart},
@@ -14,6 +14,7 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -114,4 +115,7 @@ type CommandArgs struct {
 	Args    roachpb.Request
 	// *Stats should be mutated to reflect any writes made by the command.
 	Stats *enginepb.MVCCStats
+	// Concurrency is the request's concurrency guard, which provides a view
+	// into the lock table. May be nil.
+	Concurrency *concurrency.Guard
 }
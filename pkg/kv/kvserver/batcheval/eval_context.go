@@ -68,6 +68,7 @@ type EvalContext interface {
 	GetTerm(uint64) (uint64, error)
 	GetLeaseAppliedIndex() uint64
 	GetTracker() closedts.TrackerI
+	GetClosedTimestampPolicy() closedts.RangeClosedTimestampPolicy
 
 	Desc() *roachpb.RangeDescriptor
 	ContainsKey(key roachpb.Key) bool
@@ -184,6 +185,9 @@ func (m *mockEvalCtxImpl) GetLeaseAppliedIndex() uint64 {
 func (m *mockEvalCtxImpl) GetTracker() closedts.TrackerI {
 	panic("unimplemented")
 }
+func (m *mockEvalCtxImpl) GetClosedTimestampPolicy() closedts.RangeClosedTimestampPolicy {
+	return closedts.LagByClusterSetting
+}
 func (m *mockEvalCtxImpl) Desc() *roachpb.RangeDescriptor {
 	return m.MockEvalCtx.Desc
 }
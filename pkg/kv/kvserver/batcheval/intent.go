@@ -103,26 +103,57 @@ func readProvisionalVal(
 
 }
 
-// acquireUnreplicatedLocksOnKeys adds an unreplicated lock acquisition by the
-// transaction to the provided result.Result for each key in the scan result.
-func acquireUnreplicatedLocksOnKeys(
+// acquireLocksOnKeys adds a lock acquisition by the transaction to the
+// provided result.Result for each key in the scan result. If replicated
+// locking was requested, each lock is also written to the replicated lock
+// table using the provided ReadWriter; otherwise, the locks are unreplicated
+// and only held in the leaseholder's in-memory lock table.
+func acquireLocksOnKeys(
+	ctx context.Context,
+	readWriter storage.ReadWriter,
 	res *result.Result,
 	txn *roachpb.Transaction,
+	keyLocking lock.Strength,
+	replicated bool,
 	scanFmt roachpb.ScanFormat,
 	scanRes *storage.MVCCScanResult,
 ) error {
+	dur, str := lock.Unreplicated, lock.Exclusive
+	if replicated {
+		dur = lock.Replicated
+		if keyLocking == lock.Shared {
+			str = lock.Shared
+		}
+	}
+	acquireLock := func(key roachpb.Key) (roachpb.LockAcquisition, error) {
+		if replicated {
+			if err := storage.MVCCAcquireLock(ctx, readWriter, txn, str, key); err != nil {
+				return roachpb.LockAcquisition{}, err
+			}
+		}
+		return roachpb.MakeLockAcquisition(txn, key, dur, str), nil
+	}
+
 	res.Local.AcquiredLocks = make([]roachpb.LockAcquisition, scanRes.NumKeys)
 	switch scanFmt {
 	case roachpb.BATCH_RESPONSE:
 		var i int
 		return storage.MVCCScanDecodeKeyValues(scanRes.KVData, func(key storage.MVCCKey, _ []byte) error {
-			res.Local.AcquiredLocks[i] = roachpb.MakeLockAcquisition(txn, key.Key, lock.Unreplicated)
+			acq, err := acquireLock(key.Key)
+			if err != nil {
+				return err
+			}
+			res.Local.AcquiredLocks[i] = acq
 			i++
 			return nil
 		})
 	case roachpb.KEY_VALUES:
 		for i, row := range scanRes.KVs {
-			res.Local.AcquiredLocks[i] = roachpb.MakeLockAcquisition(txn, row.Key, lock.Unreplicated)
+			acq, err := acquireLock(row.Key)
+			if err != nil {
+				return err
+			}
+			res.Local.AcquiredLocks[i] = acq
 		}
 		return nil
 	default:
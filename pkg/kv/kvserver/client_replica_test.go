@@ -3584,3 +3584,123 @@ func TestRaftSchedulerPrioritizesNodeLiveness(t *testing.T) {
 	priorityID := store.RaftSchedulerPriorityID()
 	require.Equal(t, livenessRangeID, priorityID)
 }
+
+// TestBoundedStalenessNegotiation verifies that bounded staleness reads
+// negotiate the freshest timestamp that can be served locally without blocking
+// and that the transaction performing the read is fixed to that timestamp.
+func TestBoundedStalenessNegotiation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 1, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	sqlRunner := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+	sqlRunner.Exec(t, "SET CLUSTER SETTING kv.closed_timestamp.target_duration = '10ms'")
+
+	db := tc.Server(0).DB()
+	clock := tc.Server(0).Clock()
+	key := tc.ScratchRange(t)
+	intentKey := key.Next()
+	require.NoError(t, db.Put(ctx, key, "val"))
+
+	// Leave an intent behind on the range.
+	intentTxn := db.NewTxn(ctx, "intent")
+	require.NoError(t, intentTxn.Put(ctx, intentKey, "intent"))
+	defer func() { _ = intentTxn.Rollback(ctx) }()
+	intentTS := intentTxn.ProvisionalCommitTimestamp()
+
+	negotiate := func(
+		bs roachpb.BoundedStalenessHeader, k roachpb.Key,
+	) (*kv.Txn, *roachpb.BatchResponse, *roachpb.Error) {
+		txn := db.NewTxn(ctx, "bounded staleness")
+		var ba roachpb.BatchRequest
+		ba.BoundedStaleness = &bs
+		ba.Add(roachpb.NewGet(k))
+		br, pErr := txn.NegotiateAndSend(ctx, ba)
+		return txn, br, pErr
+	}
+
+	// A minimum timestamp bound above the resolved timestamp cannot be
+	// satisfied locally when the bound is strict.
+	now := clock.Now()
+	_, _, pErr := negotiate(roachpb.BoundedStalenessHeader{
+		MinTimestampBound:       now.Add(time.Hour.Nanoseconds(), 0),
+		MinTimestampBoundStrict: true,
+	}, key)
+	require.NotNil(t, pErr)
+	require.True(t, errors.HasType(pErr.GoError(), (*roachpb.MinTimestampBoundUnsatisfiableError)(nil)), pErr)
+
+	// Otherwise, the read is performed at the minimum timestamp bound.
+	txn, br, pErr := negotiate(roachpb.BoundedStalenessHeader{MinTimestampBound: now}, key)
+	require.Nil(t, pErr)
+	require.Equal(t, now, br.Timestamp)
+	require.Equal(t, now, txn.ReadTimestamp())
+	require.NotNil(t, br.Responses[0].GetGet().Value)
+
+	// A stale minimum timestamp bound negotiates a timestamp below the intent
+	// once the closed timestamp has caught up with it, so the read does not
+	// block on the intent. Reads of other keys are not held back by it.
+	testutils.SucceedsSoon(t, func() error {
+		txn, br, pErr := negotiate(roachpb.BoundedStalenessHeader{
+			MinTimestampBound:       hlc.Timestamp{WallTime: 1},
+			MinTimestampBoundStrict: true,
+		}, intentKey)
+		if pErr != nil {
+			return pErr.GoError()
+		}
+		if br.Timestamp != intentTS.Prev() {
+			return errors.Errorf("negotiated timestamp %s, expected %s", br.Timestamp, intentTS.Prev())
+		}
+		require.Equal(t, br.Timestamp, txn.ReadTimestamp())
+		require.Nil(t, br.Responses[0].GetGet().Value)
+		return nil
+	})
+	_, br, pErr = negotiate(roachpb.BoundedStalenessHeader{
+		MinTimestampBound:       hlc.Timestamp{WallTime: 1},
+		MinTimestampBoundStrict: true,
+	}, key)
+	require.Nil(t, pErr)
+	require.True(t, intentTS.Prev().LessEq(br.Timestamp))
+	require.NotNil(t, br.Responses[0].GetGet().Value)
+
+	// The negotiated timestamp respects the maximum timestamp bound.
+	maxTS := now.Add(1, 0)
+	_, br, pErr = negotiate(roachpb.BoundedStalenessHeader{
+		MinTimestampBound: hlc.Timestamp{WallTime: 1},
+		MaxTimestampBound: maxTS,
+	}, key)
+	require.Nil(t, pErr)
+	require.True(t, br.Timestamp.Less(maxTS))
+
+	// If the intents on the read's span exceed the intent scan limit, the local
+	// resolved timestamp cannot be determined. The leaseholder then cannot
+	// satisfy a strict minimum timestamp bound.
+	sqlRunner.Exec(t, "SET CLUSTER SETTING kv.bounded_staleness.max_intents_scanned = 1")
+	var scanBA roachpb.BatchRequest
+	scanBA.BoundedStaleness = &roachpb.BoundedStalenessHeader{
+		MinTimestampBound:       hlc.Timestamp{WallTime: 1},
+		MinTimestampBoundStrict: true,
+	}
+	scanBA.Add(roachpb.NewScan(key, key.PrefixEnd(), false /* forUpdate */))
+	secondIntentKey := intentKey.Next()
+	require.NoError(t, intentTxn.Put(ctx, secondIntentKey, "intent"))
+	testutils.SucceedsSoon(t, func() error {
+		_, pErr := db.NewTxn(ctx, "bounded staleness").NegotiateAndSend(ctx, scanBA)
+		if !errors.HasType(pErr.GoError(), (*roachpb.MinTimestampBoundUnsatisfiableError)(nil)) {
+			return errors.Errorf("expected MinTimestampBoundUnsatisfiableError, found %v", pErr)
+		}
+		return nil
+	})
+	sqlRunner.Exec(t, "RESET CLUSTER SETTING kv.bounded_staleness.max_intents_scanned")
+
+	// Bounded staleness batches that span ranges are rejected.
+	var ba roachpb.BatchRequest
+	ba.BoundedStaleness = &roachpb.BoundedStalenessHeader{MinTimestampBound: now}
+	ba.Add(roachpb.NewScan(keys.TableDataMin, roachpb.KeyMax, false /* forUpdate */))
+	_, pErr = db.NewTxn(ctx, "bounded staleness").NegotiateAndSend(ctx, ba)
+	require.Regexp(t, "bounded staleness read cannot span multiple ranges", pErr)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -114,6 +115,83 @@ func TestClosedTimestampCanServe(t *testing.T) {
 	}
 }
 
+// TestClosedTimestampCanServePresentTimeForGlobalReads verifies that a range
+// whose zone configuration sets global_reads closes timestamps in the future,
+// that writes to it are pushed to synthetic future timestamps and commit-wait,
+// and that all of its replicas can serve reads at present time, even when the
+// reader's clock runs ahead of the writer's by a fraction of the max offset.
+func TestClosedTimestampCanServePresentTimeForGlobalReads(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	skip.UnderRace(t)
+
+	ctx := context.Background()
+	tc, db0, desc, repls := setupClusterForClosedTimestampTesting(ctx, t, testingTargetDuration,
+		testingCloseFraction, aggressiveResolvedTimestampClusterArgs)
+	defer tc.Stopper().Stop(ctx)
+
+	if _, err := db0.Exec(`ALTER TABLE cttest.kv CONFIGURE ZONE USING global_reads = true`); err != nil {
+		t.Fatal(err)
+	}
+	testutils.SucceedsSoon(t, func() error {
+		for _, repl := range repls {
+			if policy := repl.GetClosedTimestampPolicy(); policy != closedts.LeadForGlobalReads {
+				return errors.Errorf("replica %s has closed timestamp policy %s", repl, policy)
+			}
+		}
+		return nil
+	})
+
+	// A non-transactional write is pushed into the future and is not
+	// acknowledged until the local clock has passed its timestamp.
+	tableID, err := getTableID(db0, "cttest", "kv")
+	if err != nil {
+		t.Fatalf("failed to lookup ids: %+v", err)
+	}
+	idxPrefix := keys.SystemSQLCodec.IndexPrefix(uint32(tableID), 1)
+	key, err := rowenc.EncodeTableKey(idxPrefix, tree.NewDInt(1), encoding.Ascending)
+	if err != nil {
+		t.Fatalf("failed to encode key: %+v", err)
+	}
+	clock := tc.Server(0).Clock()
+	before := clock.Now()
+	if err := tc.Server(0).DB().Put(ctx, key, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	after := clock.Now()
+	res, err := tc.Server(0).DB().Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTS := res.Value.Timestamp
+	if !writeTS.IsFlagSet(hlc.TimestampFlag_SYNTHETIC) {
+		t.Fatalf("expected synthetic write timestamp, found %s", writeTS)
+	}
+	if minLead := before.Add(testingTargetDuration.Nanoseconds(), 0); writeTS.Less(minLead) {
+		t.Fatalf("expected write timestamp %s to lead %s", writeTS, minLead)
+	}
+	if after.Less(writeTS) {
+		t.Fatalf("write at %s acknowledged before commit-wait, clock at %s", writeTS, after)
+	}
+
+	// Transactional writes commit-wait in the TxnCoordSender.
+	if _, err := db0.Exec(`INSERT INTO cttest.kv VALUES(2, $1)`, "bar"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Present-time reads can be served by every replica, including reads from
+	// a gateway whose clock leads the leaseholder's by half the max offset.
+	offset := clock.MaxOffset() / 2
+	for _, skew := range []time.Duration{0, offset} {
+		ts := clock.Now().Add(skew.Nanoseconds(), 0)
+		baRead := makeReadBatchRequestForDesc(desc, ts)
+		testutils.SucceedsSoon(t, func() error {
+			return verifyCanReadFromAllRepls(ctx, t, baRead, repls, expectRows(2))
+		})
+	}
+}
+
 // TestClosedTimestampCanServerThroughoutLeaseTransfer verifies that lease
 // transfers does not prevent reading a value from a follower that was
 // previously readable.
@@ -40,12 +40,45 @@ package closedts
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 )
 
+// RangeClosedTimestampPolicy represents the policy that a range uses to close
+// timestamps. The policy determines which of the two closed timestamps carried
+// in each closed timestamp update applies to the range, and which timestamps
+// proposals on the range are forced above.
+type RangeClosedTimestampPolicy int
+
+const (
+	// LagByClusterSetting is the default policy. Ranges with this policy close
+	// timestamps that trail present time by kv.closed_timestamp.target_duration.
+	// Followers of these ranges can serve historical reads.
+	LagByClusterSetting RangeClosedTimestampPolicy = iota
+	// LeadForGlobalReads is the policy of ranges whose zone configuration sets
+	// global_reads. Ranges with this policy close timestamps that lead present
+	// time by enough to account for clock uncertainty and the propagation of
+	// closed timestamp updates, so that followers of these ranges can serve
+	// present-time reads. Writes to these ranges are pushed into the future,
+	// and transactions that perform them must wait for their commit timestamp
+	// to pass before acknowledging their commit.
+	LeadForGlobalReads
+)
+
+func (p RangeClosedTimestampPolicy) String() string {
+	switch p {
+	case LagByClusterSetting:
+		return "LAG_BY_CLUSTER_SETTING"
+	case LeadForGlobalReads:
+		return "LEAD_FOR_GLOBAL_READS"
+	default:
+		return fmt.Sprintf("RangeClosedTimestampPolicy(%d)", int(p))
+	}
+}
+
 // ReleaseFunc is a closure returned from Track which is used to record the
 // LeaseAppliedIndex (LAI) given to a tracked proposal. The supplied epoch must
 // match that of the lease under which the proposal was proposed.
@@ -58,12 +91,18 @@ type ReleaseFunc func(context.Context, ctpb.Epoch, roachpb.RangeID, ctpb.LAI)
 // updates along with a map delta of minimum Lease Applied Indexes a replica
 // wishing to serve a follower read must reach in order to do so correctly.
 //
+// The Tracker closes two timestamps at once: one that lags present time, which
+// applies to ranges with the LagByClusterSetting policy, and one that leads
+// present time, which applies to ranges with the LeadForGlobalReads policy.
+// Proposals are forced above the timestamp corresponding to the policy of the
+// range they are proposed on.
+//
 // See https://github.com/cockroachdb/cockroach/pull/26362 for more information.
 //
 // The methods exposed on Tracker are safe for concurrent use.
 type TrackerI interface {
-	Close(next hlc.Timestamp, expCurEpoch ctpb.Epoch) (hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool)
-	Track(ctx context.Context) (hlc.Timestamp, ReleaseFunc)
+	Close(next, nextLead hlc.Timestamp, expCurEpoch ctpb.Epoch) (closed, closedLead hlc.Timestamp, _ map[roachpb.RangeID]ctpb.LAI, ok bool)
+	Track(ctx context.Context, policy RangeClosedTimestampPolicy) (hlc.Timestamp, ReleaseFunc)
 	FailedCloseAttempts() int64
 }
 
@@ -132,7 +171,7 @@ type Producer interface {
 // 4. the CanServe method determines via the underlying storage whether a
 //    given read can be satisfied via follower reads.
 // 5. the MaxClosed method determines via the underlying storage what the maximum
-//    closed timestamp is for the specified LAI.
+//    closed timestamp is for the specified LAI and closed timestamp policy.
 //    TODO(tschottdorf): This is already adding some cruft to this nice interface.
 //    CanServe and MaxClosed are almost identical.
 //
@@ -142,7 +181,7 @@ type Provider interface {
 	Producer
 	Notifyee
 	Start()
-	MaxClosed(roachpb.NodeID, roachpb.RangeID, ctpb.Epoch, ctpb.LAI) hlc.Timestamp
+	MaxClosed(roachpb.NodeID, roachpb.RangeID, ctpb.Epoch, ctpb.LAI, RangeClosedTimestampPolicy) hlc.Timestamp
 }
 
 // A ClientRegistry is the client component of the follower reads subsystem. It
@@ -162,12 +201,12 @@ type ClientRegistry interface {
 // detailed description of the semantics. The final returned boolean indicates
 // whether tracked epoch matched the expCurEpoch and that returned information
 // may be used.
-type CloseFn func(next hlc.Timestamp, expCurEpoch ctpb.Epoch) (hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool)
+type CloseFn func(next, nextLead hlc.Timestamp, expCurEpoch ctpb.Epoch) (closed, closedLead hlc.Timestamp, _ map[roachpb.RangeID]ctpb.LAI, ok bool)
 
 // AsCloseFn uses the TrackerI as a CloseFn.
 func AsCloseFn(t TrackerI) CloseFn {
-	return func(next hlc.Timestamp, expCurEpoch ctpb.Epoch) (hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool) {
-		return t.Close(next, expCurEpoch)
+	return func(next, nextLead hlc.Timestamp, expCurEpoch ctpb.Epoch) (hlc.Timestamp, hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool) {
+		return t.Close(next, nextLead, expCurEpoch)
 	}
 }
 
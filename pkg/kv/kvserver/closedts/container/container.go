@@ -35,6 +35,8 @@ type Config struct {
 	Clock    closedts.LiveClockFn
 	Refresh  closedts.RefreshFn
 	Dialer   closedts.Dialer
+	// MaxOffset is the maximum clock offset in the cluster.
+	MaxOffset time.Duration
 }
 
 // A Container is a full closed timestamp subsystem along with the Config it was
@@ -119,12 +121,13 @@ func (c *Container) Start(nodeID roachpb.NodeID) {
 	tracker := minprop.NewTracker()
 
 	pConf := provider.Config{
-		NodeID:   nodeID,
-		Settings: cfg.Settings,
-		Stopper:  cfg.Stopper,
-		Storage:  storage,
-		Clock:    cfg.Clock,
-		Close:    closedts.AsCloseFn(tracker),
+		NodeID:    nodeID,
+		Settings:  cfg.Settings,
+		Stopper:   cfg.Stopper,
+		Storage:   storage,
+		Clock:     cfg.Clock,
+		Close:     closedts.AsCloseFn(tracker),
+		MaxOffset: cfg.MaxOffset,
 	}
 
 	provider := provider.NewProvider(&pConf)
@@ -145,14 +145,14 @@ func TestTwoNodes(t *testing.T) {
 	)
 	// Initially, can't serve random things for either n1 or n2.
 	require.True(t, c1.Container.Provider.MaxClosed(
-		c1.NodeID, roachpb.RangeID(5), ep0, ctpb.LAI(0)).IsEmpty(),
+		c1.NodeID, roachpb.RangeID(5), ep0, ctpb.LAI(0), closedts.LagByClusterSetting).IsEmpty(),
 	)
 	require.True(t, c1.Container.Provider.MaxClosed(
-		c2.NodeID, roachpb.RangeID(5), ep0, ctpb.LAI(0)).IsEmpty(),
+		c2.NodeID, roachpb.RangeID(5), ep0, ctpb.LAI(0), closedts.LagByClusterSetting).IsEmpty(),
 	)
 
 	// Track and release a command.
-	ts, release := c1.Tracker.Track(ctx)
+	ts, release := c1.Tracker.Track(ctx, closedts.LagByClusterSetting)
 	release(ctx, ep1, roachpb.RangeID(17), ctpb.LAI(12))
 
 	// The command is forced above ts=0.2. This is just an artifact of how the
@@ -175,15 +175,15 @@ func TestTwoNodes(t *testing.T) {
 	// (Note that the Tracker may not have processed the closing yet, so if there were
 	// a bug here, this test would fail flakily - that's ok).
 	require.True(t, c1.Container.Provider.MaxClosed(
-		c1.NodeID, roachpb.RangeID(17), ep1, ctpb.LAI(12)).IsEmpty(),
+		c1.NodeID, roachpb.RangeID(17), ep1, ctpb.LAI(12), closedts.LagByClusterSetting).IsEmpty(),
 	)
 
 	// Two more commands come in.
-	ts, release = c1.Tracker.Track(ctx)
+	ts, release = c1.Tracker.Track(ctx, closedts.LagByClusterSetting)
 	release(ctx, ep1, roachpb.RangeID(17), ctpb.LAI(16))
 	require.Equal(t, hlc.Timestamp{WallTime: 1e9, Logical: 1}, ts)
 
-	ts, release = c1.Tracker.Track(ctx)
+	ts, release = c1.Tracker.Track(ctx, closedts.LagByClusterSetting)
 	release(ctx, ep1, roachpb.RangeID(8), ctpb.LAI(88))
 	require.Equal(t, hlc.Timestamp{WallTime: 1e9, Logical: 1}, ts)
 
@@ -193,7 +193,7 @@ func TestTwoNodes(t *testing.T) {
 
 	testutils.SucceedsSoon(t, func() error {
 		if c1.Container.Provider.MaxClosed(
-			c1.NodeID, roachpb.RangeID(17), ep1, ctpb.LAI(12),
+			c1.NodeID, roachpb.RangeID(17), ep1, ctpb.LAI(12), closedts.LagByClusterSetting,
 		).Less(hlc.Timestamp{WallTime: 1e9}) {
 			return errors.New("still can't serve")
 		}
@@ -202,12 +202,12 @@ func TestTwoNodes(t *testing.T) {
 
 	// Shouldn't be able to serve the same thing if we haven't caught up yet.
 	require.False(t, !c1.Container.Provider.MaxClosed(
-		c1.NodeID, roachpb.RangeID(17), ep1, ctpb.LAI(11),
+		c1.NodeID, roachpb.RangeID(17), ep1, ctpb.LAI(11), closedts.LagByClusterSetting,
 	).Less(hlc.Timestamp{WallTime: 1e9}))
 
 	// Shouldn't be able to serve at a higher timestamp.
 	require.False(t, !c1.Container.Provider.MaxClosed(
-		c1.NodeID, roachpb.RangeID(17), ep1, ctpb.LAI(12),
+		c1.NodeID, roachpb.RangeID(17), ep1, ctpb.LAI(12), closedts.LagByClusterSetting,
 	).Less(hlc.Timestamp{WallTime: 1e9, Logical: 1}))
 
 	// Now things get a little more interesting. Tell node2 to get a stream of
@@ -228,7 +228,7 @@ func TestTwoNodes(t *testing.T) {
 	// n1 when it has caught up.
 	testutils.SucceedsSoon(t, func() error {
 		if c2.Container.Provider.MaxClosed(
-			c1.NodeID, roachpb.RangeID(17), ep1, ctpb.LAI(12),
+			c1.NodeID, roachpb.RangeID(17), ep1, ctpb.LAI(12), closedts.LagByClusterSetting,
 		).Less(hlc.Timestamp{WallTime: 1e9}) {
 			return errors.New("n2 still can't serve")
 		}
@@ -253,7 +253,7 @@ func TestTwoNodes(t *testing.T) {
 				testutils.SucceedsSoon(t, func() error {
 					t.Helper()
 					if c.Container.Provider.MaxClosed(
-						c1.NodeID, tuple.RangeID, ep1, tuple.LAI,
+						c1.NodeID, tuple.RangeID, ep1, tuple.LAI, closedts.LagByClusterSetting,
 					).Less(ts) {
 						return errors.Errorf("n%d still can't serve (r%d,%d) @ %s", i+1, tuple.RangeID, tuple.LAI, ts)
 					}
@@ -261,18 +261,18 @@ func TestTwoNodes(t *testing.T) {
 				})
 				// Still can't serve when not caught up.
 				require.False(t, !c.Container.Provider.MaxClosed(
-					c1.NodeID, tuple.RangeID, ep1, tuple.LAI-1,
+					c1.NodeID, tuple.RangeID, ep1, tuple.LAI-1, closedts.LagByClusterSetting,
 				).Less(ts))
 				// Can serve when more than caught up.
 				require.True(t, !c.Container.Provider.MaxClosed(
-					c1.NodeID, tuple.RangeID, ep1, tuple.LAI+1,
+					c1.NodeID, tuple.RangeID, ep1, tuple.LAI+1, closedts.LagByClusterSetting,
 				).Less(ts))
 				// Can't serve when in different epoch, no matter larger or smaller.
 				require.False(t, !c.Container.Provider.MaxClosed(
-					c1.NodeID, tuple.RangeID, ep0, tuple.LAI,
+					c1.NodeID, tuple.RangeID, ep0, tuple.LAI, closedts.LagByClusterSetting,
 				).Less(ts))
 				require.False(t, !c.Container.Provider.MaxClosed(
-					c1.NodeID, tuple.RangeID, ep2, tuple.LAI,
+					c1.NodeID, tuple.RangeID, ep2, tuple.LAI, closedts.LagByClusterSetting,
 				).Less(ts))
 			}
 		}
@@ -302,7 +302,7 @@ func TestTwoNodes(t *testing.T) {
 	// the next closed timestamp (from the tick above) minus target interval.
 	// The SucceedsSoon is to ensure that the above tick in ep2 has made it to the tracker.
 	testutils.SucceedsSoon(t, func() error {
-		ts, release = c1.Tracker.Track(ctx)
+		ts, release = c1.Tracker.Track(ctx, closedts.LagByClusterSetting)
 		release(ctx, ep2, roachpb.RangeID(123), ctpb.LAI(456))
 		if !(&hlc.Timestamp{WallTime: int64(container.StorageBucketScale) + 4e9, Logical: 1}).Equal(ts) {
 			return errors.Errorf("command still not forced above %v", ts)
@@ -343,7 +343,7 @@ func TestTwoNodes(t *testing.T) {
 
 		testutils.SucceedsSoon(t, func() error {
 			if c.Container.Provider.MaxClosed(
-				c1.NodeID, rangeID, epoch, lai,
+				c1.NodeID, rangeID, epoch, lai, closedts.LagByClusterSetting,
 			).Less(ts) {
 				return errors.Errorf("n%d still can't serve (r%d,%d) @ %s", i+1, rangeID, lai, ts)
 			}
@@ -352,20 +352,20 @@ func TestTwoNodes(t *testing.T) {
 
 		// Still can't serve when not caught up.
 		require.False(t, !c.Container.Provider.MaxClosed(
-			c1.NodeID, rangeID, epoch, lai-1,
+			c1.NodeID, rangeID, epoch, lai-1, closedts.LagByClusterSetting,
 		).Less(ts))
 
 		// Can serve when more than caught up.
 		require.True(t, !c.Container.Provider.MaxClosed(
-			c1.NodeID, rangeID, epoch, lai+1,
+			c1.NodeID, rangeID, epoch, lai+1, closedts.LagByClusterSetting,
 		).Less(ts))
 
 		// Can't serve when in different epoch, no matter larger or smaller.
 		require.False(t, !c.Container.Provider.MaxClosed(
-			c1.NodeID, rangeID, epoch-1, lai,
+			c1.NodeID, rangeID, epoch-1, lai, closedts.LagByClusterSetting,
 		).Less(ts))
 		require.False(t, !c.Container.Provider.MaxClosed(
-			c1.NodeID, rangeID, epoch+1, lai,
+			c1.NodeID, rangeID, epoch+1, lai, closedts.LagByClusterSetting,
 		).Less(ts))
 	}
 }
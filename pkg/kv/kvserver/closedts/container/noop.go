@@ -54,11 +54,13 @@ func (noopEverything) Get(client ctpb.InboundClient) error {
 	return errors.New("closed timestamps disabled")
 }
 func (noopEverything) Close(
-	next hlc.Timestamp, expCurEpoch ctpb.Epoch,
-) (hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool) {
-	return hlc.Timestamp{}, nil, false
+	next, nextLead hlc.Timestamp, expCurEpoch ctpb.Epoch,
+) (hlc.Timestamp, hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool) {
+	return hlc.Timestamp{}, hlc.Timestamp{}, nil, false
 }
-func (noopEverything) Track(ctx context.Context) (hlc.Timestamp, closedts.ReleaseFunc) {
+func (noopEverything) Track(
+	ctx context.Context, policy closedts.RangeClosedTimestampPolicy,
+) (hlc.Timestamp, closedts.ReleaseFunc) {
 	return hlc.Timestamp{}, func(context.Context, ctpb.Epoch, roachpb.RangeID, ctpb.LAI) {}
 }
 func (noopEverything) FailedCloseAttempts() int64 {
@@ -74,7 +76,7 @@ func (noopEverything) Notify(roachpb.NodeID) chan<- ctpb.Entry {
 func (noopEverything) Subscribe(context.Context, chan<- ctpb.Entry) {}
 func (noopEverything) Start()                                       {}
 func (noopEverything) MaxClosed(
-	roachpb.NodeID, roachpb.RangeID, ctpb.Epoch, ctpb.LAI,
+	roachpb.NodeID, roachpb.RangeID, ctpb.Epoch, ctpb.LAI, closedts.RangeClosedTimestampPolicy,
 ) hlc.Timestamp {
 	return hlc.Timestamp{}
 }
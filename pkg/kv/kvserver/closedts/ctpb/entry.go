@@ -47,7 +47,11 @@ func (e Entry) String() string {
 	if len(sl) == 0 {
 		sl = []string{"(empty)"}
 	}
-	return fmt.Sprintf("CT: %s @ Epoch %d\nFull: %t\nMLAI: %s\n", e.ClosedTimestamp, e.Epoch, e.Full, strings.Join(sl, ", "))
+	var lead string
+	if !e.ClosedTimestampLead.IsEmpty() {
+		lead = fmt.Sprintf("CT (lead): %s\n", e.ClosedTimestampLead)
+	}
+	return fmt.Sprintf("CT: %s @ Epoch %d\n%sFull: %t\nMLAI: %s\n", e.ClosedTimestamp, e.Epoch, lead, e.Full, strings.Join(sl, ", "))
 }
 
 func (r Reaction) String() string {
@@ -16,6 +16,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -30,13 +31,13 @@ func Example() {
 	fmt.Println(tracker)
 
 	fmt.Println("A first command arrives on range 12 (though the range isn't known yet to the Tracker).")
-	ts, done1 := tracker.Track(ctx)
+	ts, done1 := tracker.Track(ctx, closedts.LagByClusterSetting)
 	fmt.Println("All commands initially start out on the right. The command has its timestamp forwarded to", ts, ".")
 	fmt.Println(tracker)
 
 	fmt.Println("Two more commands arrive, on r1 and r12.")
-	_, done2 := tracker.Track(ctx)
-	_, done3 := tracker.Track(ctx)
+	_, done2 := tracker.Track(ctx, closedts.LagByClusterSetting)
+	_, done3 := tracker.Track(ctx, closedts.LagByClusterSetting)
 	fmt.Println(tracker)
 
 	fmt.Println("The command on r1 finishes evaluating at Lease Applied Index 10 and lets the Tracker know.")
@@ -48,7 +49,7 @@ func Example() {
 	fmt.Println(tracker)
 
 	fmt.Println("The system closes out a timestamp (registering 1000 as the next timestamp to close out).")
-	closed1, mlai1, _ := tracker.Close(hlc.Timestamp{WallTime: 1e9}, ep1)
+	closed1, _, mlai1, _ := tracker.Close(hlc.Timestamp{WallTime: 1e9}, hlc.Timestamp{}, ep1)
 	fmt.Println("No problem: nothing is tracked on the left side; returns:", closed1, "and", mlaiString(mlai1))
 	fmt.Println("Note how the items on the right have moved to the left, as they are relevant for the")
 	fmt.Println("next call to Close.")
@@ -56,11 +57,11 @@ func Example() {
 
 	fmt.Println("Nothing happens for a while until the system tries to close out the next timestamp.")
 	fmt.Println("However, the very first proposal is still tracked and blocks progress.")
-	closed2, mlai2, _ := tracker.Close(hlc.Timestamp{WallTime: 2e9}, ep1)
+	closed2, _, mlai2, _ := tracker.Close(hlc.Timestamp{WallTime: 2e9}, hlc.Timestamp{}, ep1)
 	fmt.Println("The call returns a no-op in the form", closed2, mlaiString(mlai2), ".")
 	fmt.Println(tracker)
 
-	ts4, done4 := tracker.Track(ctx)
+	ts4, done4 := tracker.Track(ctx, closedts.LagByClusterSetting)
 	fmt.Println("A new command gets tracked on r12 (and is forwarded to", ts4, "(if necessary).")
 	fmt.Println("It terminates quickly, leaving an MLAI entry of 78 behind.")
 	done4(ctx, ep1, 12, 78)
@@ -72,7 +73,7 @@ func Example() {
 	done1(ctx, ep1, 12, 79)
 	fmt.Println(tracker)
 
-	closed3, mlai3, _ := tracker.Close(hlc.Timestamp{WallTime: 3e9}, ep1)
+	closed3, _, mlai3, _ := tracker.Close(hlc.Timestamp{WallTime: 3e9}, hlc.Timestamp{}, ep1)
 	fmt.Println("The next call to Close() is successful and returns:", closed3, "and", mlaiString(mlai3))
 	fmt.Println(tracker)
 
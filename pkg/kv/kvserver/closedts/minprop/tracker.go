@@ -30,6 +30,10 @@ type Tracker struct {
 		// closed is the most recently closed timestamp.
 		closed      hlc.Timestamp
 		closedEpoch ctpb.Epoch
+		// closedLead is the most recently closed timestamp for ranges with the
+		// LeadForGlobalReads policy. It is closed out together with `closed`
+		// and shares its MLAIs and epoch.
+		closedLead hlc.Timestamp
 
 		// The variables below track required information for the next closed
 		// timestamp and beyond. First, `next` is the timestamp that will be
@@ -78,7 +82,15 @@ type Tracker struct {
 		// later epoch than is currently tracked will result in the current data
 		// corresponding to the prior epoch to be evicted.
 
+		//
+		// `nextLead` is the counterpart of `next` for ranges with the
+		// LeadForGlobalReads policy: proposals on these ranges are forced
+		// above it, and it replaces `closedLead` when `next` replaces `closed`.
+		// Because both are closed out at the same time, proposals on either
+		// kind of range share the reference counts and MLAI maps.
+
 		next                  hlc.Timestamp
+		nextLead              hlc.Timestamp
 		leftMLAI, rightMLAI   map[roachpb.RangeID]ctpb.LAI
 		leftRef, rightRef     int
 		leftEpoch, rightEpoch ctpb.Epoch
@@ -91,8 +103,8 @@ type Tracker struct {
 
 var _ closedts.TrackerI = (*Tracker)(nil)
 
-// NewTracker returns a Tracker initialized to a closed timestamp of zero and
-// a next closed timestamp of one logical tick past zero.
+// NewTracker returns a Tracker initialized to closed timestamps of zero and
+// next closed timestamps of one logical tick past zero.
 func NewTracker() *Tracker {
 	t := &Tracker{}
 	const initialEpoch = 1
@@ -100,6 +112,7 @@ func NewTracker() *Tracker {
 	t.mu.leftEpoch = initialEpoch
 	t.mu.rightEpoch = initialEpoch
 	t.mu.next = hlc.Timestamp{Logical: 1}
+	t.mu.nextLead = hlc.Timestamp{Logical: 1}
 	t.mu.leftMLAI = map[roachpb.RangeID]ctpb.LAI{}
 	t.mu.rightMLAI = map[roachpb.RangeID]ctpb.LAI{}
 	return t
@@ -161,13 +174,14 @@ func (t *Tracker) String() string {
 	)
 }
 
-// Close attempts to close out the current candidate timestamp (replacing it
-// with the provided one). This is possible only if tracked proposals that were
+// Close attempts to close out the current candidate timestamps (replacing them
+// with the provided ones). This is possible only if tracked proposals that were
 // evaluating when Close was previously called have since completed. On success,
 // all subsequent proposals will be forced to evaluate strictly above the
-// provided timestamp, and the timestamp previously passed to Close is returned
-// as a closed timestamp along with a map of minimum Lease Applied Indexes
-// reflecting the updates for the past period. On failure, the previous closed
+// provided timestamp corresponding to the closed timestamp policy of their
+// range (next or nextLead), and the timestamps previously passed to Close are
+// returned as closed timestamps along with a map of minimum Lease Applied
+// Indexes reflecting the updates for the past period. On failure, the previous closed
 // timestamp is returned along with a nil map (which can be treated by callers
 // like a successful call that happens to not return any new information).
 // Similarly, failure to provide a timestamp strictly larger than that to be
@@ -190,9 +204,13 @@ func (t *Tracker) String() string {
 // values are returned. If the caller's expected epoch is newer than that of
 // tracked data the state of the tracker is progressed but zero values are
 // returned.
+//
+// The lead timestamp is forwarded to next if it trails it, and it is bumped
+// past the previous lead timestamp if it would otherwise not increase, since
+// both candidate timestamps must increase strictly with each successful call.
 func (t *Tracker) Close(
-	next hlc.Timestamp, expCurEpoch ctpb.Epoch,
-) (ts hlc.Timestamp, mlai map[roachpb.RangeID]ctpb.LAI, ok bool) {
+	next, nextLead hlc.Timestamp, expCurEpoch ctpb.Epoch,
+) (ts, tsLead hlc.Timestamp, mlai map[roachpb.RangeID]ctpb.LAI, ok bool) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	defer func() {
@@ -204,9 +222,9 @@ func (t *Tracker) Close(
 
 	if log.V(3) {
 		log.Infof(context.TODO(),
-			"close: leftRef=%d (ep: %d) rightRef=%d (ep: %d) next=%s closed=%s@ (ep: %d) new=%s (ep: %d)",
-			t.mu.leftRef, t.mu.leftEpoch, t.mu.rightRef, t.mu.rightEpoch, t.mu.next,
-			t.mu.closed, t.mu.closedEpoch, next, expCurEpoch)
+			"close: leftRef=%d (ep: %d) rightRef=%d (ep: %d) next=%s nextLead=%s closed=%s@ (ep: %d) new=%s newLead=%s (ep: %d)",
+			t.mu.leftRef, t.mu.leftEpoch, t.mu.rightRef, t.mu.rightEpoch, t.mu.next, t.mu.nextLead,
+			t.mu.closed, t.mu.closedEpoch, next, nextLead, expCurEpoch)
 	}
 
 	// Make sure to not let `t.mu.next` regress, or we'll accept proposals
@@ -231,6 +249,7 @@ func (t *Tracker) Close(
 		// them. If we want to make use of this optimization, we should emit
 		// two closed timestamp updates for this case.
 		t.mu.closed = t.mu.next
+		t.mu.closedLead = t.mu.nextLead
 		t.mu.closedEpoch = t.mu.leftEpoch
 		mlai = t.mu.leftMLAI
 
@@ -256,18 +275,23 @@ func (t *Tracker) Close(
 		t.mu.rightRef = 0
 
 		t.mu.next = next
+		nextLead.Forward(next)
+		if !t.mu.nextLead.Less(nextLead) {
+			nextLead = t.mu.nextLead.Next()
+		}
+		t.mu.nextLead = nextLead
 	}
 
 	if t.mu.closedEpoch != expCurEpoch {
-		return hlc.Timestamp{}, nil, false
+		return hlc.Timestamp{}, hlc.Timestamp{}, nil, false
 	}
-	return t.mu.closed, mlai, true
+	return t.mu.closed, t.mu.closedLead, mlai, true
 }
 
 // Track is called before evaluating a proposal. It returns the minimum
 // timestamp at which the proposal can be evaluated (i.e. the request timestamp
-// needs to be forwarded if necessary), and acquires a reference with the
-// Tracker. This reference is released by calling the returned closure either
+// needs to be forwarded if necessary) given the closed timestamp policy of the
+// proposal's range, and acquires a reference with the Tracker. This reference is released by calling the returned closure either
 // a) before proposing the command, supplying the Lease Applied Index at which
 //    the proposal will be carried out, or
 // b) with zero arguments if the command won't end up being proposed (i.e. hit
@@ -275,11 +299,16 @@ func (t *Tracker) Close(
 //
 // The ReleaseFunc is not thread safe. For convenience, it may be called with
 // zero arguments once after a regular call.
-func (t *Tracker) Track(ctx context.Context) (hlc.Timestamp, closedts.ReleaseFunc) {
+func (t *Tracker) Track(
+	ctx context.Context, policy closedts.RangeClosedTimestampPolicy,
+) (hlc.Timestamp, closedts.ReleaseFunc) {
 	shouldLog := log.V(3)
 
 	t.mu.Lock()
 	minProp := t.mu.next.Next()
+	if policy == closedts.LeadForGlobalReads {
+		minProp = t.mu.nextLead.Next()
+	}
 	t.mu.rightRef++
 	t.mu.Unlock()
 
@@ -297,7 +326,7 @@ func (t *Tracker) Track(ctx context.Context) (hlc.Timestamp, closedts.ReleaseFun
 			}
 			return
 		}
-		t.release(ctx, minProp, epoch, rangeID, lai, shouldLog)
+		t.release(ctx, minProp, policy, epoch, rangeID, lai, shouldLog)
 	}
 
 	return minProp, release
@@ -317,6 +346,7 @@ func (t *Tracker) FailedCloseAttempts() int64 {
 func (t *Tracker) release(
 	ctx context.Context,
 	minProp hlc.Timestamp,
+	policy closedts.RangeClosedTimestampPolicy,
 	epoch ctpb.Epoch,
 	rangeID roachpb.RangeID,
 	lai ctpb.LAI,
@@ -324,13 +354,17 @@ func (t *Tracker) release(
 ) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	closed, next := t.mu.closed, t.mu.next
+	if policy == closedts.LeadForGlobalReads {
+		closed, next = t.mu.closedLead, t.mu.nextLead
+	}
 	var left bool
-	if minProp == t.mu.closed.Next() {
+	if minProp == closed.Next() {
 		left = true
-	} else if minProp == t.mu.next.Next() {
+	} else if minProp == next.Next() {
 		left = false
 	} else {
-		log.Fatalf(ctx, "min proposal %s not tracked under closed (%s) or next (%s) timestamp", minProp, t.mu.closed, t.mu.next)
+		log.Fatalf(ctx, "min proposal %s not tracked under closed (%s) or next (%s) timestamp", minProp, closed, next)
 	}
 	// If the update is from the left side, clear all existing MLAIs from the left
 	// to uphold the invariant that all tracked MLAIs belong to the same (and
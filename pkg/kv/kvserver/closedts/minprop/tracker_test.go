@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -39,7 +40,7 @@ const (
 func TestTrackerClosure(t *testing.T) {
 	ctx := context.Background()
 	tracker := NewTracker()
-	_, done := tracker.Track(ctx)
+	_, done := tracker.Track(ctx, closedts.LagByClusterSetting)
 
 	done(ctx, ep1, 100, 200)
 	done(ctx, ep1, 0, 0)
@@ -48,19 +49,19 @@ func TestTrackerClosure(t *testing.T) {
 func ExampleTracker_Close() {
 	ctx := context.Background()
 	tracker := NewTracker()
-	_, slow := tracker.Track(ctx)
-	_, _, _ = tracker.Close(hlc.Timestamp{WallTime: 1e9}, ep1)
-	_, fast := tracker.Track(ctx)
+	_, slow := tracker.Track(ctx, closedts.LagByClusterSetting)
+	_, _, _, _ = tracker.Close(hlc.Timestamp{WallTime: 1e9}, hlc.Timestamp{}, ep1)
+	_, fast := tracker.Track(ctx, closedts.LagByClusterSetting)
 
 	fmt.Println("Slow proposal finishes at LAI 2")
 	slow(ctx, ep1, 99, 2)
-	closed, m, ok := tracker.Close(hlc.Timestamp{WallTime: 2e9}, ep1)
+	closed, _, m, ok := tracker.Close(hlc.Timestamp{WallTime: 2e9}, hlc.Timestamp{}, ep1)
 	fmt.Println("Closed:", closed, m, ok)
 
 	fmt.Println("Fast proposal finishes at LAI 1")
 	fast(ctx, ep1, 99, 1)
 	fmt.Println(tracker)
-	closed, m, ok = tracker.Close(hlc.Timestamp{WallTime: 3e9}, ep1)
+	closed, _, m, ok = tracker.Close(hlc.Timestamp{WallTime: 3e9}, hlc.Timestamp{}, ep1)
 	fmt.Println("Closed:", closed, m, ok)
 	fmt.Println("Note how the MLAI has 'regressed' from 2 to 1. The consumer")
 	fmt.Println("needs to track the maximum over all deltas received.")
@@ -92,7 +93,7 @@ func TestTrackerDoubleRelease(t *testing.T) {
 	ctx := context.Background()
 	tracker := NewTracker()
 
-	_, release := tracker.Track(ctx)
+	_, release := tracker.Track(ctx, closedts.LagByClusterSetting)
 	release(ctx, ep1, 0, 0)
 	release(ctx, ep1, 4, 10)
 
@@ -104,13 +105,13 @@ func TestTrackerDoubleRelease(t *testing.T) {
 func TestTrackerReleaseZero(t *testing.T) {
 	ctx := context.Background()
 	tracker := NewTracker()
-	trackedTs1, release1 := tracker.Track(ctx)
-	trackedTs2, release2 := tracker.Track(ctx)
+	trackedTs1, release1 := tracker.Track(ctx, closedts.LagByClusterSetting)
+	trackedTs2, release2 := tracker.Track(ctx, closedts.LagByClusterSetting)
 	release2(ctx, ep1, 2, 0)
-	leftTs, _, _ := tracker.Close(trackedTs2, ep1)
+	leftTs, _, _, _ := tracker.Close(trackedTs2, hlc.Timestamp{}, ep1)
 	leftTs.Logical += 2
 	release1(ctx, ep1, 1, 0)
-	closedTs, mlais, ok := tracker.Close(leftTs, ep1)
+	closedTs, _, mlais, ok := tracker.Close(leftTs, hlc.Timestamp{}, ep1)
 	if !ok {
 		t.Fatalf("expected closed to succeed")
 	} else if closedTs != trackedTs1 {
@@ -184,7 +185,7 @@ func TestTrackerConcurrentUse(t *testing.T) {
 		t.Log("before closing:", tracker)
 		// Ignore epoch mismatches which may occur before any values have been
 		// released from the tracker.
-		closed, m, _ := tracker.Close(newNext, ep1)
+		closed, _, m, _ := tracker.Close(newNext, hlc.Timestamp{}, ep1)
 		if closed.Less(prevClosed) {
 			return errors.Errorf("closed timestamp regressed from %s to %s", prevClosed, closed)
 		} else if prevClosed == closed && len(m) != 0 {
@@ -253,7 +254,7 @@ func TestTrackerConcurrentUse(t *testing.T) {
 		prevClosed := mc.mu.closed[len(mc.mu.closed)-1]
 		mc.mu.Unlock()
 
-		ts, done := tracker.Track(ctx)
+		ts, done := tracker.Track(ctx, closedts.LagByClusterSetting)
 		if ts.Less(prevClosed) {
 			return errors.Errorf("%d: proposal forwarded to %s, but closed %s", i, ts, prevClosed)
 		}
@@ -336,19 +337,19 @@ func ExampleTracker_Close_epochChange() {
 	fmt.Println(tracker)
 
 	fmt.Println("A first command arrives on range 1 (though the range isn't known yet to the Tracker).")
-	ts, r1e1lai1 := tracker.Track(ctx)
+	ts, r1e1lai1 := tracker.Track(ctx, closedts.LagByClusterSetting)
 	fmt.Println("All commands initially start out on the right. The command has its timestamp forwarded to", ts, ".")
 	fmt.Println("The command finished quickly and is released in epoch 1.")
 	r1e1lai1(ctx, ep1, 1, 1)
 	fmt.Println(tracker)
 
 	fmt.Println("Another proposal arrives on range 2 but does not complete before the next call to Close().")
-	_, r2e2lai1 := tracker.Track(ctx)
+	_, r2e2lai1 := tracker.Track(ctx, closedts.LagByClusterSetting)
 	fmt.Println(tracker)
 
 	fmt.Println("The system closes out a timestamp expecting liveness epoch 2 (registering", ts1, "as the next",
 		"timestamp to close out).")
-	closed, mlai, ok := tracker.Close(ts1, ep2)
+	closed, _, mlai, ok := tracker.Close(ts1, hlc.Timestamp{}, ep2)
 	fmt.Println("The Close() call fails due to the liveness epoch mismatch between",
 		"the expected current epoch and the tracked data, returning", closed, mlai, ok)
 	fmt.Println("The Close() call evicts the tracked range 1 LAI.")
@@ -360,28 +361,28 @@ func ExampleTracker_Close_epochChange() {
 
 	fmt.Println("Another proposal arrives on range 1 and quickly finishes with",
 		"LAI 2 but is still in epoch 1 and is not tracked.")
-	_, r1e1lai2 := tracker.Track(ctx)
+	_, r1e1lai2 := tracker.Track(ctx, closedts.LagByClusterSetting)
 	r1e1lai2(ctx, ep1, 2, 2)
 	fmt.Println("Meanwhile a proposal arrives on range 2 and quickly finishes with",
 		"LAI 2 in epoch 2.")
-	_, r2e2lai2 := tracker.Track(ctx)
+	_, r2e2lai2 := tracker.Track(ctx, closedts.LagByClusterSetting)
 	r2e2lai2(ctx, ep2, 2, 2)
 	fmt.Println(tracker)
 
 	fmt.Println("A new proposal arrives on range 1 and quickly finishes with LAI 2 in epoch 3.")
 	fmt.Println("This new epoch evicts the data on the right side corresponding to epoch 2.")
-	_, r1e3lai2 := tracker.Track(ctx)
+	_, r1e3lai2 := tracker.Track(ctx, closedts.LagByClusterSetting)
 	r1e3lai2(ctx, ep3, 1, 2)
 	fmt.Println(tracker)
 
-	closed, mlai, ok = tracker.Close(ts2, ep2)
+	closed, _, mlai, ok = tracker.Close(ts2, hlc.Timestamp{}, ep2)
 	fmt.Println("The next call to Close() occurs in epoch 2 and successfully returns:", closed, mlai, ok)
-	closed, mlai, ok = tracker.Close(ts3, ep2)
+	closed, _, mlai, ok = tracker.Close(ts3, hlc.Timestamp{}, ep2)
 	fmt.Println("Subsequent calls to Close() at later times but still in epoch 2 do not move the tracker state.")
 	fmt.Println("They return the previous closed timestamp with an empty mlai map:", closed, mlai, ok, ".")
 	fmt.Println("Data corresponding to epoch 3 is retained.")
 	fmt.Println(tracker)
-	closed, mlai, ok = tracker.Close(ts3, ep3)
+	closed, _, mlai, ok = tracker.Close(ts3, hlc.Timestamp{}, ep3)
 	fmt.Println("The next call to Close() occurs in epoch 3 and successfully returns:", closed, mlai, ok, ".")
 
 	// Output:
@@ -498,17 +499,17 @@ func TestTrackerMultipleEpochsReleased(t *testing.T) {
 	tracker := NewTracker()
 
 	// Track and release a proposal on range 1 in ep1.
-	_, r1e1lai1 := tracker.Track(ctx)
+	_, r1e1lai1 := tracker.Track(ctx, closedts.LagByClusterSetting)
 	r1e1lai1(ctx, ep1, 1, 1)
 	// Begin tracking a proposal on range 2 which won't be released until after
 	// the next call to Close.
-	_, r2e2lai1 := tracker.Track(ctx)
+	_, r2e2lai1 := tracker.Track(ctx, closedts.LagByClusterSetting)
 	// Close the current left side and assert that the tracker reports an empty
 	// MLAI map in epoch 1 for the initial timestamp value.
-	assertClosed(tracker.Close(ts1, ep1))(t, ts0, mlais{}, true)
+	assertClosed(tracker.Close(ts1, hlc.Timestamp{}, ep1))(t, ts0, mlais{}, true)
 	// Track and release another proposal on range 1 in epoch 1 with LAI 2.
 	// This proposal is on the right side.
-	_, r1e1lai2 := tracker.Track(ctx)
+	_, r1e1lai2 := tracker.Track(ctx, closedts.LagByClusterSetting)
 	r1e1lai2(ctx, ep1, 1, 2)
 	// Release the proposal for range 2 in epoch 2 which should be on the left
 	// side. This release call will invalidate the LAI for range 1 that was
@@ -516,15 +517,62 @@ func TestTrackerMultipleEpochsReleased(t *testing.T) {
 	r2e2lai1(ctx, ep2, 2, 1)
 	// Close the current left side and assert that the tracker value on the
 	// range 1 epoch 1 value from the first interval is not present.
-	assertClosed(tracker.Close(ts2, ep2))(t, ts1, mlais{2: 1}, true)
-	assertClosed(tracker.Close(ts2, ep2))(t, ts1, nil, true)
-	assertClosed(tracker.Close(ts3, ep2))(t, ts2, mlais{}, true)
+	assertClosed(tracker.Close(ts2, hlc.Timestamp{}, ep2))(t, ts1, mlais{2: 1}, true)
+	assertClosed(tracker.Close(ts2, hlc.Timestamp{}, ep2))(t, ts1, nil, true)
+	assertClosed(tracker.Close(ts3, hlc.Timestamp{}, ep2))(t, ts2, mlais{}, true)
+}
+
+// TestTrackerLeadForGlobalReads verifies that proposals on ranges with the
+// LeadForGlobalReads policy are forced above the lead timestamp, that they are
+// accounted for on the same side as proposals on other ranges, and that the
+// lead timestamp is closed out together with the lagging one.
+func TestTrackerLeadForGlobalReads(t *testing.T) {
+	ctx := context.Background()
+	tracker := NewTracker()
+
+	ts1 := hlc.Timestamp{WallTime: 1e9}
+	lead1 := hlc.Timestamp{WallTime: 5e9}
+	ts2 := hlc.Timestamp{WallTime: 2e9}
+	lead2 := hlc.Timestamp{WallTime: 6e9}
+	ts3 := hlc.Timestamp{WallTime: 3e9}
+
+	_, _, _, _ = tracker.Close(ts1, lead1, ep1)
+
+	minProp, lag := tracker.Track(ctx, closedts.LagByClusterSetting)
+	assert.Equal(t, ts1.Next(), minProp)
+	minProp, lead := tracker.Track(ctx, closedts.LeadForGlobalReads)
+	assert.Equal(t, lead1.Next(), minProp)
+
+	// Both proposals are on the right side, so the next call to Close succeeds
+	// and moves them to the left side.
+	closed, closedLead, m, ok := tracker.Close(ts2, lead2, ep1)
+	assert.True(t, ok)
+	assert.Equal(t, ts1, closed)
+	assert.Equal(t, lead1, closedLead)
+	assert.Empty(t, m)
+
+	// The proposals are now on the left side and block the next close.
+	_, _, m, _ = tracker.Close(ts3, hlc.Timestamp{}, ep1)
+	assert.Nil(t, m)
+
+	lag(ctx, ep1, 1, 7)
+	lead(ctx, ep1, 2, 9)
+	closed, closedLead, m, ok = tracker.Close(ts3, hlc.Timestamp{}, ep1)
+	assert.True(t, ok)
+	assert.Equal(t, ts2, closed)
+	assert.Equal(t, lead2, closedLead)
+	assert.EqualValues(t, mlais{1: 7, 2: 9}, m)
+
+	// A lead timestamp that does not increase is bumped past the previous one.
+	minProp, lead = tracker.Track(ctx, closedts.LeadForGlobalReads)
+	assert.Equal(t, lead2.Next().Next(), minProp)
+	lead(ctx, ep1, 0, 0)
 }
 
 type mlais = map[roachpb.RangeID]ctpb.LAI
 
 func assertClosed(
-	ts hlc.Timestamp, m mlais, ok bool,
+	ts, _ hlc.Timestamp, m mlais, ok bool,
 ) func(t *testing.T, expTs hlc.Timestamp, expM mlais, expOk bool) {
 	return func(
 		t *testing.T, expTs hlc.Timestamp, expM mlais, expOk bool,
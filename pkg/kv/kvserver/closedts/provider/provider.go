@@ -37,6 +37,10 @@ type Config struct {
 	Storage  closedts.Storage
 	Clock    closedts.LiveClockFn
 	Close    closedts.CloseFn
+	// MaxOffset is the maximum clock offset in the cluster. It determines how
+	// far ahead of present time the closed timestamp for ranges with the
+	// LeadForGlobalReads policy is placed.
+	MaxOffset time.Duration
 }
 
 type subscriber struct {
@@ -123,6 +127,7 @@ func (p *Provider) runCloser(ctx context.Context) {
 		}
 	}
 	closedts.TargetDuration.SetOnChange(&p.cfg.Settings.SV, confChanged)
+	closedts.LeadForGlobalReadsOverride.SetOnChange(&p.cfg.Settings.SV, confChanged)
 	// Track whether we've ever been live to avoid logging warnings about not
 	// being live during node startup.
 	var everBeenLive bool
@@ -149,6 +154,12 @@ func (p *Provider) runCloser(ctx context.Context) {
 		}
 
 		next, liveAtEpoch, err := p.cfg.Clock(p.cfg.NodeID)
+		// The lead timestamp is in the future, so it is marked as synthetic:
+		// it does not correspond to a reading of any node's clock and must not
+		// be used to advance one.
+		nextLead := next.Add(
+			closedts.LeadTargetDuration(&p.cfg.Settings.SV, p.cfg.MaxOffset).Nanoseconds(), 0,
+		).SetFlag(hlc.TimestampFlag_SYNTHETIC)
 		next.WallTime -= int64(targetDuration)
 		if err != nil {
 			if everBeenLive && p.everyClockLog.ShouldLog() {
@@ -161,7 +172,7 @@ func (p *Provider) runCloser(ctx context.Context) {
 			everBeenLive = true
 			// Close may fail if the data being closed does not correspond to the
 			// current liveAtEpoch.
-			closed, m, ok := p.cfg.Close(next, liveAtEpoch)
+			closed, closedLead, m, ok := p.cfg.Close(next, nextLead, liveAtEpoch)
 			if !ok {
 				if log.V(1) {
 					log.Infof(ctx, "failed to close %v due to liveness epoch mismatch at %v",
@@ -170,13 +181,14 @@ func (p *Provider) runCloser(ctx context.Context) {
 				continue
 			}
 			if log.V(1) {
-				log.Infof(ctx, "closed ts=%s with %+v, next closed timestamp should be %s",
-					closed, m, next)
+				log.Infof(ctx, "closed ts=%s (lead %s) with %+v, next closed timestamp should be %s (lead %s)",
+					closed, closedLead, m, next, nextLead)
 			}
 			entry := ctpb.Entry{
-				Epoch:           liveAtEpoch,
-				ClosedTimestamp: closed,
-				MLAI:            m,
+				Epoch:               liveAtEpoch,
+				ClosedTimestamp:     closed,
+				ClosedTimestampLead: closedLead,
+				MLAI:                m,
 			}
 
 			// Simulate a subscription to the local node, so that the new information
@@ -342,13 +354,20 @@ func (p *Provider) Subscribe(ctx context.Context, ch chan<- ctpb.Entry) {
 
 // MaxClosed implements closedts.Provider.
 func (p *Provider) MaxClosed(
-	nodeID roachpb.NodeID, rangeID roachpb.RangeID, epoch ctpb.Epoch, lai ctpb.LAI,
+	nodeID roachpb.NodeID,
+	rangeID roachpb.RangeID,
+	epoch ctpb.Epoch,
+	lai ctpb.LAI,
+	policy closedts.RangeClosedTimestampPolicy,
 ) hlc.Timestamp {
 	var maxTS hlc.Timestamp
 	p.cfg.Storage.VisitDescending(nodeID, func(entry ctpb.Entry) (done bool) {
 		if mlai, found := entry.MLAI[rangeID]; found {
 			if entry.Epoch == epoch && mlai <= lai {
 				maxTS = entry.ClosedTimestamp
+				if policy == closedts.LeadForGlobalReads {
+					maxTS = entry.ClosedTimestampLead
+				}
 				return true
 			}
 		}
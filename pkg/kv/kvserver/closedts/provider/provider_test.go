@@ -63,7 +63,7 @@ func TestProviderSubscribeNotify(t *testing.T) {
 			}
 			return hlc.Timestamp{}, ctpb.Epoch(1), errors.New("injected clock error")
 		},
-		Close: func(next hlc.Timestamp, expCurEpoch ctpb.Epoch) (hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool) {
+		Close: func(next, nextLead hlc.Timestamp, expCurEpoch ctpb.Epoch) (hlc.Timestamp, hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool) {
 			panic("should never be called")
 		},
 	}
@@ -254,10 +254,10 @@ func TestProviderSubscribeConcurrent(t *testing.T) {
 		Clock: func(roachpb.NodeID) (hlc.Timestamp, ctpb.Epoch, error) {
 			return hlc.Timestamp{}, 1, nil
 		},
-		Close: func(next hlc.Timestamp, expCurEpoch ctpb.Epoch) (hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool) {
+		Close: func(next, nextLead hlc.Timestamp, expCurEpoch ctpb.Epoch) (hlc.Timestamp, hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool) {
 			return hlc.Timestamp{
 					WallTime: atomic.AddInt64(&ts, 1),
-				}, map[roachpb.RangeID]ctpb.LAI{
+				}, hlc.Timestamp{}, map[roachpb.RangeID]ctpb.LAI{
 					1: ctpb.LAI(atomic.LoadInt64(&ts)),
 				}, true
 		},
@@ -312,7 +312,7 @@ func TestProviderTargetDurationSetting(t *testing.T) {
 		Clock: func(roachpb.NodeID) (hlc.Timestamp, ctpb.Epoch, error) {
 			return hlc.Timestamp{}, 1, nil
 		},
-		Close: func(next hlc.Timestamp, expCurEpoch ctpb.Epoch) (hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool) {
+		Close: func(next, nextLead hlc.Timestamp, expCurEpoch ctpb.Epoch) (hlc.Timestamp, hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool) {
 			if called++; called == 1 {
 				closedts.TargetDuration.Override(&st.SV, 0)
 			}
@@ -322,7 +322,7 @@ func TestProviderTargetDurationSetting(t *testing.T) {
 			}
 			return hlc.Timestamp{
 					WallTime: atomic.AddInt64(&ts, 1),
-				}, map[roachpb.RangeID]ctpb.LAI{
+				}, hlc.Timestamp{}, map[roachpb.RangeID]ctpb.LAI{
 					1: ctpb.LAI(atomic.LoadInt64(&ts)),
 				}, true
 		},
@@ -36,3 +36,32 @@ var CloseFraction = settings.RegisterValidatedFloatSetting(
 		}
 		return nil
 	})
+
+// LeadForGlobalReadsOverride overrides the lead time that ranges with the
+// LeadForGlobalReads closed timestamp policy use to publish closed timestamps.
+var LeadForGlobalReadsOverride = settings.RegisterNonNegativeDurationSetting(
+	"kv.closed_timestamp.lead_for_global_reads_override",
+	"if nonzero, overrides the lead time that global_reads ranges use to publish closed timestamps",
+	0,
+)
+
+// closedTimestampPropagationSlack is the time allotted for a closed timestamp
+// update to be published by the leaseholder and to be received and applied by
+// its followers.
+const closedTimestampPropagationSlack = 200 * time.Millisecond
+
+// LeadTargetDuration returns the duration by which ranges with the
+// LeadForGlobalReads policy attempt to close timestamps ahead of present time.
+//
+// A follower can serve a present-time read only if the read's uncertainty
+// interval, which extends the maximum clock offset past the read timestamp, is
+// closed. The lead time therefore covers the maximum clock offset, the interval
+// at which closed timestamps are advanced and the time it takes for an update
+// to reach the follower.
+func LeadTargetDuration(sv *settings.Values, maxClockOffset time.Duration) time.Duration {
+	if override := LeadForGlobalReadsOverride.Get(sv); override != 0 {
+		return override
+	}
+	closeInterval := time.Duration(CloseFraction.Get(sv) * float64(TargetDuration.Get(sv)))
+	return maxClockOffset + closeInterval + closedTimestampPropagationSlack
+}
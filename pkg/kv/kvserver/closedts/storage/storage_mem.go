@@ -180,6 +180,7 @@ func merge(e, ee ctpb.Entry) ctpb.Entry {
 	// Use the larger of both timestamps with the union of the MLAIs, preferring larger
 	// ones on conflict.
 	re.ClosedTimestamp.Forward(ee.ClosedTimestamp)
+	re.ClosedTimestampLead.Forward(ee.ClosedTimestampLead)
 	for rangeID, mlai := range ee.MLAI {
 		if cur, found := re.MLAI[rangeID]; !found || cur < mlai {
 			re.MLAI[rangeID] = mlai
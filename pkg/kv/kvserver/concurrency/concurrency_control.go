@@ -359,6 +359,9 @@ type Guard struct {
 	Req Request
 	lg  latchGuard
 	ltg lockTableGuard
+	// contentionEvents accumulates the ContentionEvents encountered by the
+	// request while waiting in lock wait-queues.
+	contentionEvents []roachpb.ContentionEvent
 }
 
 // Response is a slice of responses to requests in a batch. This type is used
@@ -588,6 +591,17 @@ type lockTableGuard interface {
 
 	// CurState returns the latest waiting state.
 	CurState() waitingState
+
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked
+	// or reserved (for strength > lock.None) by a conflicting transaction in
+	// the lockTableGuard's snapshot of the lock table, given the caller's own
+	// desired locking strength. If so, the lock holder is returned. A nil
+	// TxnMeta is returned if the key is only reserved.
+	//
+	// The method is used by requests with a SkipLocked wait policy, which do
+	// not wait on conflicting locks during sequencing, to determine which
+	// keys to skip during evaluation.
+	IsKeyLockedByConflictingTxn(roachpb.Key, lock.Strength) (bool, *enginepb.TxnMeta)
 }
 
 // lockTableWaiter is concerned with waiting in lock wait-queues for locks held
@@ -629,7 +643,10 @@ type lockTableWaiter interface {
 	// has acquired. It returns when the request is at the front of all lock
 	// wait-queues and it is safe to re-acquire latches and scan the lockTable
 	// again.
-	WaitOn(context.Context, Request, lockTableGuard) *Error
+	//
+	// Each ContentionEvent encountered while waiting is passed to the provided
+	// function, which may be nil.
+	WaitOn(context.Context, Request, lockTableGuard, func(*roachpb.ContentionEvent)) *Error
 
 	// WaitOnLock waits on the transaction responsible for the specified lock
 	// and then ensures that the lock is cleared out of the request's way.
@@ -645,7 +662,7 @@ type lockTableWaiter interface {
 	// LocalResult, we should be able to remove the lockTable "disabled" state
 	// and, in turn, remove this method. This will likely fall out of pulling
 	// all replicated locks into the lockTable.
-	WaitOnLock(context.Context, Request, *roachpb.Intent) *Error
+	WaitOnLock(context.Context, Request, *roachpb.Intent, func(*roachpb.ContentionEvent)) *Error
 
 	// ClearCaches wipes all caches maintained by the lockTableWaiter. This is
 	// primarily used to recover memory when a replica loses a lease. However,
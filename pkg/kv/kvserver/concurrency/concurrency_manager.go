@@ -169,7 +169,7 @@ func (m *managerImpl) sequenceReqWithGuard(
 			m.lm.Release(g.moveLatchGuard())
 
 			log.Event(ctx, "waiting in lock wait-queues")
-			if err := m.ltw.WaitOn(ctx, g.Req, g.ltg); err != nil {
+			if err := m.ltw.WaitOn(ctx, g.Req, g.ltg, g.addContentionEvent); err != nil {
 				return nil, err
 			}
 			continue
@@ -279,7 +279,7 @@ func (m *managerImpl) HandleWriterIntentError(
 	if wait {
 		for i := range t.Intents {
 			intent := &t.Intents[i]
-			if err := m.ltw.WaitOnLock(ctx, g.Req, intent); err != nil {
+			if err := m.ltw.WaitOnLock(ctx, g.Req, intent, g.addContentionEvent); err != nil {
 				m.FinishReq(g)
 				return nil, err
 			}
@@ -306,7 +306,7 @@ func (m *managerImpl) HandleTransactionPushError(
 
 // OnLockAcquired implements the LockManager interface.
 func (m *managerImpl) OnLockAcquired(ctx context.Context, acq *roachpb.LockAcquisition) {
-	if err := m.lt.AcquireLock(&acq.Txn, acq.Key, lock.Exclusive, acq.Durability); err != nil {
+	if err := m.lt.AcquireLock(&acq.Txn, acq.Key, acq.Strength, acq.Durability); err != nil {
 		log.Fatalf(ctx, "%v", err)
 	}
 }
@@ -435,6 +435,31 @@ func (r *Request) writeConflictTimestamp() hlc.Timestamp {
 	return ts
 }
 
+// lockStrength returns the strength of the locks that the request acquires on
+// the keys that it writes to. Only batches composed entirely of scans that
+// acquire replicated Shared locks acquire locks weaker than Exclusive.
+func (r *Request) lockStrength() lock.Strength {
+	if len(r.Requests) == 0 {
+		return lock.Exclusive
+	}
+	for _, ru := range r.Requests {
+		var keyLocking lock.Strength
+		var replicated bool
+		switch req := ru.GetInner().(type) {
+		case *roachpb.ScanRequest:
+			keyLocking, replicated = req.KeyLocking, req.ReplicatedLocking
+		case *roachpb.ReverseScanRequest:
+			keyLocking, replicated = req.KeyLocking, req.ReplicatedLocking
+		default:
+			return lock.Exclusive
+		}
+		if keyLocking != lock.Shared || !replicated {
+			return lock.Exclusive
+		}
+	}
+	return lock.Shared
+}
+
 func (r *Request) isSingle(m roachpb.Method) bool {
 	if len(r.Requests) != 1 {
 		return false
@@ -463,6 +488,20 @@ func (g *Guard) LatchSpans() *spanset.SpanSet {
 	return g.Req.LatchSpans
 }
 
+// IsKeyLockedByConflictingTxn returns whether the specified key is locked or
+// reserved (for strength > lock.None) by a conflicting transaction, as of the
+// snapshot of the lock table captured while the request was sequenced. If so,
+// the lock holder is returned. A nil TxnMeta is returned if the key is only
+// reserved.
+func (g *Guard) IsKeyLockedByConflictingTxn(
+	key roachpb.Key, strength lock.Strength,
+) (bool, *enginepb.TxnMeta) {
+	if g == nil || g.ltg == nil {
+		return false, nil
+	}
+	return g.ltg.IsKeyLockedByConflictingTxn(key, strength)
+}
+
 // HoldingLatches returned whether the guard is holding latches or not.
 func (g *Guard) HoldingLatches() bool {
 	return g != nil && g.lg != nil
@@ -482,6 +521,19 @@ func (g *Guard) AssertNoLatches() {
 	}
 }
 
+// ContentionEvents returns the ContentionEvents that the request encountered
+// while waiting in lock wait-queues, across all of its sequencing attempts.
+func (g *Guard) ContentionEvents() []roachpb.ContentionEvent {
+	if g == nil {
+		return nil
+	}
+	return g.contentionEvents
+}
+
+func (g *Guard) addContentionEvent(ev *roachpb.ContentionEvent) {
+	g.contentionEvents = append(g.contentionEvents, *ev)
+}
+
 func (g *Guard) moveLatchGuard() latchGuard {
 	lg := g.lg
 	g.lg = nil
@@ -14,10 +14,18 @@ package lock
 
 import fmt "fmt"
 
+// MaxStrength is the maximum value in the Strength enum.
+const MaxStrength = Intent
+
 // MaxDurability is the maximum value in the Durability enum.
 const MaxDurability = Unreplicated
 
 func init() {
+	for v := range Strength_name {
+		if s := Strength(v); s > MaxStrength {
+			panic(fmt.Sprintf("Strength (%s) with value larger than MaxStrength", s))
+		}
+	}
 	for v := range Durability_name {
 		if d := Durability(v); d > MaxDurability {
 			panic(fmt.Sprintf("Durability (%s) with value larger than MaxDurability", d))
@@ -262,6 +262,10 @@ type lockTableGuardImpl struct {
 	spans   *spanset.SpanSet
 	readTS  hlc.Timestamp
 	writeTS hlc.Timestamp
+	// The strength of the locks acquired by the request on the keys that it
+	// declares with SpanReadWrite access. Requests that acquire Shared locks
+	// are compatible with other Shared lock holders.
+	str lock.Strength
 
 	// Snapshots of the trees for which this request has some spans. Note that
 	// the lockStates in these snapshots may have been removed from
@@ -400,6 +404,58 @@ func (g *lockTableGuardImpl) CurState() waitingState {
 	return g.mu.state
 }
 
+func (g *lockTableGuardImpl) IsKeyLockedByConflictingTxn(
+	key roachpb.Key, strength lock.Strength,
+) (bool, *enginepb.TxnMeta) {
+	ss := spanset.SpanGlobal
+	if keys.IsLocal(key) {
+		ss = spanset.SpanLocal
+	}
+	iter := g.tableSnapshot[ss].MakeIter()
+	iter.SeekGE(&lockState{key: key})
+	if !iter.Valid() || !iter.Cur().key.Equal(key) {
+		// No lock on key.
+		return false, nil
+	}
+	l := iter.Cur()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.isEmptyLock() {
+		// The lock is empty but has not yet been deleted.
+		return false, nil
+	}
+	lockHolderTxn, lockHolderTS := l.getLockHolder()
+	if lockHolderTxn != nil && g.isSameTxn(lockHolderTxn) {
+		// Already locked by this txn.
+		return false, nil
+	}
+	if lockHolderTxn != nil && l.holder.strength == lock.Shared &&
+		(strength == lock.None || strength == lock.Shared) {
+		// Shared locks are compatible with non-locking reads and with other
+		// Shared locks.
+		return false, nil
+	}
+	if strength == lock.None {
+		// Non-locking reads only conflict with locks held at or below their
+		// read timestamp. They ignore reservations.
+		if lockHolderTxn == nil || g.readTS.Less(lockHolderTS) {
+			return false, nil
+		}
+		return true, lockHolderTxn
+	}
+	if lockHolderTxn != nil {
+		// Locked by some other txn.
+		return true, lockHolderTxn
+	}
+	// Locking reads also conflict with reservations held by other
+	// transactions, since the reservation holder is about to acquire the
+	// lock.
+	if l.reservation != nil && !g.isSameTxn(l.reservation.txn) {
+		return true, nil
+	}
+	return false, nil
+}
+
 func (g *lockTableGuardImpl) notify() {
 	select {
 	case g.mu.signal <- struct{}{}:
@@ -550,8 +606,11 @@ type lockState struct {
 	// replicated and unreplicated mode at different stages.
 	holder struct {
 		locked bool
-		// LockStrength is always Exclusive
-		holder [lock.MaxDurability + 1]lockHolderInfo
+		// The strength of the held lock. This is Exclusive unless the lock was
+		// acquired as a replicated Shared lock, which is compatible with other
+		// Shared locks and with non-locking reads.
+		strength lock.Strength
+		holder   [lock.MaxDurability + 1]lockHolderInfo
 	}
 
 	// Information about the requests waiting on the lock.
@@ -779,6 +838,9 @@ func (l *lockState) Format(buf *strings.Builder) {
 			}
 			fmt.Fprintf(b, "]")
 		}
+		if l.holder.strength == lock.Shared {
+			fmt.Fprintf(b, ", strength: %s", l.holder.strength)
+		}
 		fmt.Fprintln(b, "")
 	}
 	txn, ts := l.getLockHolder()
@@ -1017,6 +1079,7 @@ func (l *lockState) getLockHolder() (*enginepb.TxnMeta, hlc.Timestamp) {
 // REQUIRES: l.mu is locked.
 func (l *lockState) clearLockHolder() {
 	l.holder.locked = false
+	l.holder.strength = lock.None
 	for i := range l.holder.holder {
 		l.holder.holder[i] = lockHolderInfo{}
 	}
@@ -1047,6 +1110,13 @@ func (l *lockState) tryActiveWait(g *lockTableGuardImpl, sa spanset.SpanAccess,
 		return false
 	}
 
+	if lockHolderTxn != nil && l.holder.strength == lock.Shared &&
+		(sa == spanset.SpanReadOnly || g.str == lock.Shared) {
+		// Shared locks are compatible with non-locking reads and with other
+		// Shared locks.
+		return false
+	}
+
 	if sa == spanset.SpanReadOnly {
 		if lockHolderTxn == nil {
 			// Reads only care about locker, not a reservation.
@@ -1185,7 +1255,7 @@ func (l *lockState) tryActiveWait(g *lockTableGuardImpl, sa spanset.SpanAccess,
 // that is acquiring the lock.
 // Acquires l.mu.
 func (l *lockState) acquireLock(
-	_ lock.Strength, durability lock.Durability, txn *enginepb.TxnMeta, ts hlc.Timestamp,
+	strength lock.Strength, durability lock.Durability, txn *enginepb.TxnMeta, ts hlc.Timestamp,
 ) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -1193,8 +1263,18 @@ func (l *lockState) acquireLock(
 		// Already held.
 		beforeTxn, beforeTs := l.getLockHolder()
 		if txn.ID != beforeTxn.ID {
+			if strength == lock.Shared && l.holder.strength == lock.Shared {
+				// Shared locks held by multiple transactions are only tracked in
+				// the replicated lock table key space, where their compatibility
+				// has already been validated. The lockTable continues to track
+				// the first holder.
+				return nil
+			}
 			return errors.AssertionFailedf("existing lock cannot be acquired by different transaction")
 		}
+		if l.holder.strength < strength {
+			l.holder.strength = strength
+		}
 		seqs := l.holder.holder[durability].seqs
 		if l.holder.holder[durability].txn != nil && l.holder.holder[durability].txn.Epoch < txn.Epoch {
 			// Clear the sequences for the older epoch.
@@ -1299,6 +1379,7 @@ func (l *lockState) acquireLock(
 	}
 	l.reservation = nil
 	l.holder.locked = true
+	l.holder.strength = strength
 	l.holder.holder[durability].txn = txn
 	l.holder.holder[durability].ts = ts
 	l.holder.holder[durability].seqs = append([]enginepb.TxnSeq(nil), txn.Sequence)
@@ -1311,21 +1392,36 @@ func (l *lockState) acquireLock(
 	return nil
 }
 
-// A replicated lock held by txn with timestamp ts was discovered by guard g
-// where g is trying to access this key with access sa.
+// A replicated lock with strength str held by txn with timestamp ts was
+// discovered by guard g where g is trying to access this key with access sa.
 // Acquires l.mu.
 func (l *lockState) discoveredLock(
-	txn *enginepb.TxnMeta, ts hlc.Timestamp, g *lockTableGuardImpl, sa spanset.SpanAccess,
+	str lock.Strength,
+	txn *enginepb.TxnMeta,
+	ts hlc.Timestamp,
+	g *lockTableGuardImpl,
+	sa spanset.SpanAccess,
 ) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	if l.holder.locked {
 		if !l.isLockedBy(txn.ID) {
+			if str == lock.Shared && l.holder.strength == lock.Shared {
+				// The key is locked in Shared mode by multiple transactions. The
+				// lockTable only tracks one of them, so the request will wait on
+				// the tracked holder and re-discover the others after it has
+				// released its lock.
+				return nil
+			}
 			return errors.AssertionFailedf("discovered lock by different transaction than existing lock")
 		}
+		if l.holder.strength < str {
+			l.holder.strength = str
+		}
 	} else {
 		l.holder.locked = true
+		l.holder.strength = str
 	}
 	holder := &l.holder.holder[lock.Replicated]
 	if holder.txn == nil {
@@ -1783,6 +1879,7 @@ func (t *lockTableImpl) ScanAndEnqueue(req Request, guard lockTableGuard) lockTa
 		g.spans = req.LockSpans
 		g.readTS = req.readConflictTimestamp()
 		g.writeTS = req.writeConflictTimestamp()
+		g.str = req.lockStrength()
 		g.sa = spanset.NumSpanAccess - 1
 		g.index = -1
 	} else {
@@ -1811,6 +1908,12 @@ func (t *lockTableImpl) ScanAndEnqueue(req Request, guard lockTableGuard) lockTa
 			}
 		}
 	}
+	if req.WaitPolicy == lock.WaitPolicy_SkipLocked {
+		// A request using the SkipLocked wait policy captures a snapshot of the
+		// lockTable but does not wait on or enqueue behind any locks. Instead,
+		// it consults the snapshot during evaluation to skip over locked keys.
+		return g
+	}
 	g.findNextLockAfter(true /* notify */)
 	return g
 }
@@ -1888,7 +1991,12 @@ func (t *lockTableImpl) AddDiscoveredLock(
 	} else {
 		l = iter.Cur()
 	}
-	return true, l.discoveredLock(&intent.Txn, intent.Txn.WriteTimestamp, g, sa)
+	str := intent.Strength
+	if str == lock.None {
+		// Intents are written by mutations, which acquire Exclusive locks.
+		str = lock.Exclusive
+	}
+	return true, l.discoveredLock(str, &intent.Txn, intent.Txn.WriteTimestamp, g, sa)
 }
 
 // AcquireLock implements the lockTable interface.
@@ -1901,8 +2009,14 @@ func (t *lockTableImpl) AcquireLock(
 		// If not enabled, don't track any locks.
 		return nil
 	}
-	if strength != lock.Exclusive {
-		return errors.AssertionFailedf("lock strength not Exclusive")
+	switch strength {
+	case lock.Exclusive:
+	case lock.Shared:
+		if durability != lock.Replicated {
+			return errors.AssertionFailedf("unreplicated lock strength not Exclusive")
+		}
+	default:
+		return errors.AssertionFailedf("unsupported lock strength %s", strength)
 	}
 	ss := spanset.SpanGlobal
 	if keys.IsLocal(key) {
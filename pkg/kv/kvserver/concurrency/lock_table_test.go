@@ -52,10 +52,11 @@ new-txn txn=<name> ts=<int>[,<int>] epoch=<int> [seq=<int>]
 
  Creates a TxnMeta.
 
-new-request r=<name> txn=<name>|none ts=<int>[,<int>] spans=r|w@<start>[,<end>]+...
+new-request r=<name> txn=<name>|none ts=<int>[,<int>] spans=r|w@<start>[,<end>]+... [wait-policy=<policy>] [strength=shared]
 ----
 
- Creates a Request.
+ Creates a Request. If strength=shared, the request is a scan that acquires
+ replicated Shared locks on the keys it writes.
 
 scan r=<name>
 ----
@@ -64,11 +65,12 @@ scan r=<name>
  Calls lockTable.ScanAndEnqueue. If the request has an existing guard, uses it.
  If a guard is returned, stores it for later use.
 
-acquire r=<name> k=<key> durability=r|u
+acquire r=<name> k=<key> durability=r|u [strength=shared]
 ----
 <error string>
 
  Acquires lock for the request, using the existing guard for that request.
+ The lock is Exclusive unless strength=shared is specified.
 
 release txn=<name> span=<start>[,<end>]
 ----
@@ -82,11 +84,12 @@ update txn=<name> ts=<int>[,<int>] epoch=<int> span=<start>[,<end>] [ignored-seq
 
  Updates locks for the named transaction.
 
-add-discovered r=<name> k=<key> txn=<name> [lease-seq=<seq>]
+add-discovered r=<name> k=<key> txn=<name> [lease-seq=<seq>] [strength=shared]
 ----
 <error string>
 
- Adds a discovered lock that is discovered by the named request.
+ Adds a discovered lock that is discovered by the named request. The lock is
+ an intent unless strength=shared is specified.
 
 dequeue r=<name>
 ----
@@ -108,6 +111,12 @@ should-wait r=<name>
 
  Calls lockTableGuard.ShouldWait.
 
+is-key-locked-by-conflicting-txn r=<name> k=<key> strength=none|exclusive
+----
+locked: <bool>[, holder: <txn>]
+
+ Calls lockTableGuard.IsKeyLockedByConflictingTxn.
+
 enable [lease-seq=<seq>]
 ----
 
@@ -201,9 +210,18 @@ func TestLockTableBasic(t *testing.T) {
 				spans := scanSpans(t, d, ts)
 				req := Request{
 					Timestamp:  ts,
+					WaitPolicy: scanWaitPolicy(t, d, false /* required */),
 					LatchSpans: spans,
 					LockSpans:  spans,
 				}
+				if scanStrength(t, d) == lock.Shared {
+					req.Requests = []roachpb.RequestUnion{{
+						Value: &roachpb.RequestUnion_Scan{Scan: &roachpb.ScanRequest{
+							KeyLocking:        lock.Shared,
+							ReplicatedLocking: true,
+						}},
+					}}
+				}
 				if txnMeta != nil {
 					// Update the transaction's timestamp, if necessary. The transaction
 					// may have needed to move its timestamp for any number of reasons.
@@ -246,7 +264,11 @@ func TestLockTableBasic(t *testing.T) {
 				if s[0] == 'r' {
 					durability = lock.Replicated
 				}
-				if err := lt.AcquireLock(&req.Txn.TxnMeta, roachpb.Key(key), lock.Exclusive, durability); err != nil {
+				strength := scanStrength(t, d)
+				if strength == lock.None {
+					strength = lock.Exclusive
+				}
+				if err := lt.AcquireLock(&req.Txn.TxnMeta, roachpb.Key(key), strength, durability); err != nil {
 					return err.Error()
 				}
 				return lt.(*lockTableImpl).String()
@@ -335,6 +357,7 @@ func TestLockTableBasic(t *testing.T) {
 					d.Fatalf(t, "unknown txn %s", txnName)
 				}
 				intent := roachpb.MakeIntent(txnMeta, roachpb.Key(key))
+				intent.Strength = scanStrength(t, d)
 				seq := int(1)
 				if d.HasArg("lease-seq") {
 					d.ScanArgs(t, "lease-seq", &seq)
@@ -366,6 +389,37 @@ func TestLockTableBasic(t *testing.T) {
 				}
 				return fmt.Sprintf("%t", g.ShouldWait())
 
+			case "is-key-locked-by-conflicting-txn":
+				var reqName string
+				d.ScanArgs(t, "r", &reqName)
+				g := guardsByReqName[reqName]
+				if g == nil {
+					d.Fatalf(t, "unknown guard: %s", reqName)
+				}
+				var key string
+				d.ScanArgs(t, "k", &key)
+				var strS string
+				d.ScanArgs(t, "strength", &strS)
+				var strength lock.Strength
+				switch strS {
+				case "none":
+					strength = lock.None
+				case "exclusive":
+					strength = lock.Exclusive
+				default:
+					d.Fatalf(t, "unknown lock strength: %s", strS)
+				}
+				locked, holder := g.IsKeyLockedByConflictingTxn(roachpb.Key(key), strength)
+				if !locked || holder == nil {
+					return fmt.Sprintf("locked: %t", locked)
+				}
+				for name, txn := range txnsByName {
+					if txn.ID == holder.ID {
+						return fmt.Sprintf("locked: true, holder: %s", name)
+					}
+				}
+				return fmt.Sprintf("locked: true, holder: %s", holder.ID)
+
 			case "guard-state":
 				var reqName string
 				d.ScanArgs(t, "r", &reqName)
@@ -460,6 +514,44 @@ func scanTimestamp(t *testing.T, d *datadriven.TestData) hlc.Timestamp {
 	return ts
 }
 
+func scanWaitPolicy(t *testing.T, d *datadriven.TestData, required bool) lock.WaitPolicy {
+	const key = "wait-policy"
+	if !required && !d.HasArg(key) {
+		return lock.WaitPolicy_Block
+	}
+	var policy string
+	d.ScanArgs(t, key, &policy)
+	switch policy {
+	case "block":
+		return lock.WaitPolicy_Block
+	case "error":
+		return lock.WaitPolicy_Error
+	case "skip-locked":
+		return lock.WaitPolicy_SkipLocked
+	default:
+		d.Fatalf(t, "unknown wait policy: %s", policy)
+		return 0
+	}
+}
+
+func scanStrength(t *testing.T, d *datadriven.TestData) lock.Strength {
+	const key = "strength"
+	if !d.HasArg(key) {
+		return lock.None
+	}
+	var str string
+	d.ScanArgs(t, key, &str)
+	switch str {
+	case "shared":
+		return lock.Shared
+	case "exclusive":
+		return lock.Exclusive
+	default:
+		d.Fatalf(t, "unknown lock strength: %s", str)
+		return 0
+	}
+}
+
 func getSpan(t *testing.T, d *datadriven.TestData, str string) roachpb.Span {
 	parts := strings.Split(str, ",")
 	span := roachpb.Span{Key: roachpb.Key(parts[0])}
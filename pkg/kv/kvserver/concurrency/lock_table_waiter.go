@@ -133,7 +133,10 @@ type IntentResolver interface {
 
 // WaitOn implements the lockTableWaiter interface.
 func (w *lockTableWaiterImpl) WaitOn(
-	ctx context.Context, req Request, guard lockTableGuard,
+	ctx context.Context,
+	req Request,
+	guard lockTableGuard,
+	onContentionEvent func(*roachpb.ContentionEvent),
 ) (err *Error) {
 	newStateC := guard.NewStateChan()
 	ctxDoneC := ctx.Done()
@@ -152,11 +155,16 @@ func (w *lockTableWaiterImpl) WaitOn(
 	// re-discover the intent(s) during evaluation and resolve them themselves.
 	var deferredResolution []roachpb.LockUpdate
 	defer w.resolveDeferredIntents(ctx, &err, &deferredResolution)
+	// Used to track and emit ContentionEvents for each conflicting
+	// transaction and key that the request waits on.
+	h := contentionEventHelper{onEvent: onContentionEvent}
+	defer h.emit(ctx)
 	for {
 		select {
 		case <-newStateC:
 			timerC = nil
 			state := guard.CurState()
+			h.emitAndInit(ctx, state)
 			switch state.kind {
 			case waitFor, waitForDistinguished:
 				if req.WaitPolicy == lock.WaitPolicy_Error {
@@ -400,19 +408,79 @@ func (w *lockTableWaiterImpl) WaitOn(
 
 // WaitOnLock implements the lockTableWaiter interface.
 func (w *lockTableWaiterImpl) WaitOnLock(
-	ctx context.Context, req Request, intent *roachpb.Intent,
+	ctx context.Context,
+	req Request,
+	intent *roachpb.Intent,
+	onContentionEvent func(*roachpb.ContentionEvent),
 ) *Error {
 	sa, _, err := findAccessInSpans(intent.Key, req.LockSpans)
 	if err != nil {
 		return roachpb.NewError(err)
 	}
-	return w.pushLockTxn(ctx, req, waitingState{
+	state := waitingState{
 		kind:        waitFor,
 		txn:         &intent.Txn,
 		key:         intent.Key,
 		held:        true,
 		guardAccess: sa,
-	})
+	}
+	h := contentionEventHelper{onEvent: onContentionEvent}
+	defer h.emit(ctx)
+	h.emitAndInit(ctx, state)
+	return w.pushLockTxn(ctx, req, state)
+}
+
+// contentionEventHelper tracks the conflicting transaction and key that a
+// request is waiting on and emits a ContentionEvent once the request stops
+// waiting on them.
+type contentionEventHelper struct {
+	// onEvent is called with each ContentionEvent that is emitted. May be nil.
+	onEvent func(*roachpb.ContentionEvent)
+
+	// ev is the ContentionEvent that is currently being tracked, if any.
+	ev     *roachpb.ContentionEvent
+	tBegin time.Time
+}
+
+// emit finalizes the ContentionEvent that is currently being tracked, if any,
+// records it in the trace and hands it to onEvent.
+func (h *contentionEventHelper) emit(ctx context.Context) {
+	if h.ev == nil {
+		return
+	}
+	h.ev.Duration = timeutil.Since(h.tBegin)
+	log.VEventf(ctx, 2, "contention event: waited %s on txn %s for key %s",
+		h.ev.Duration, h.ev.Txn.ID.Short(), h.ev.Key)
+	if h.onEvent != nil {
+		h.onEvent(h.ev)
+	}
+	h.ev = nil
+}
+
+// emitAndInit is called with each waitingState observed by the request. If the
+// request starts waiting on a different transaction or key, the previous
+// ContentionEvent is emitted and a new one is started.
+func (h *contentionEventHelper) emitAndInit(ctx context.Context, s waitingState) {
+	switch s.kind {
+	case waitFor, waitForDistinguished, waitElsewhere:
+		if s.txn == nil {
+			// Not waiting on a known transaction.
+			h.emit(ctx)
+			return
+		}
+		if h.ev != nil && h.ev.Txn.ID == s.txn.ID && h.ev.Key.Equal(s.key) {
+			// Still waiting on the same transaction and key.
+			return
+		}
+		h.emit(ctx)
+		h.ev = &roachpb.ContentionEvent{
+			Key: s.key,
+			Txn: roachpb.Transaction{TxnMeta: *s.txn},
+		}
+		h.tBegin = timeutil.Now()
+	case waitSelf, doneWaiting:
+		h.emit(ctx)
+	}
 }
 
 // ClearCaches implements the lockTableWaiter interface.
@@ -72,6 +72,12 @@ func (g *mockLockTableGuard) CurState() waitingState {
 	}
 	return s
 }
+func (g *mockLockTableGuard) IsKeyLockedByConflictingTxn(
+	roachpb.Key, lock.Strength,
+) (bool, *enginepb.TxnMeta) {
+	// The mock guard does not track any locks.
+	return false, nil
+}
 func (g *mockLockTableGuard) notify() { g.signal <- struct{}{} }
 
 // mockLockTableGuard implements the LockManager interface.
@@ -147,7 +153,7 @@ func TestLockTableWaiterWithTxn(t *testing.T) {
 			g.state = waitingState{kind: doneWaiting}
 			g.notify()
 
-			err := w.WaitOn(ctx, makeReq(), g)
+			err := w.WaitOn(ctx, makeReq(), g, nil /* onContentionEvent */)
 			require.Nil(t, err)
 		})
 	})
@@ -159,7 +165,7 @@ func TestLockTableWaiterWithTxn(t *testing.T) {
 		ctxWithCancel, cancel := context.WithCancel(ctx)
 		go cancel()
 
-		err := w.WaitOn(ctxWithCancel, makeReq(), g)
+		err := w.WaitOn(ctxWithCancel, makeReq(), g, nil /* onContentionEvent */)
 		require.NotNil(t, err)
 		require.Equal(t, context.Canceled.Error(), err.GoError().Error())
 	})
@@ -172,7 +178,7 @@ func TestLockTableWaiterWithTxn(t *testing.T) {
 			w.stopper.Quiesce(ctx)
 		}()
 
-		err := w.WaitOn(ctx, makeReq(), g)
+		err := w.WaitOn(ctx, makeReq(), g, nil /* onContentionEvent */)
 		require.NotNil(t, err)
 		require.IsType(t, &roachpb.NodeUnavailableError{}, err.GetDetail())
 	})
@@ -218,7 +224,7 @@ func TestLockTableWaiterWithNonTxn(t *testing.T) {
 			g.state = waitingState{kind: doneWaiting}
 			g.notify()
 
-			err := w.WaitOn(ctx, makeReq(), g)
+			err := w.WaitOn(ctx, makeReq(), g, nil /* onContentionEvent */)
 			require.Nil(t, err)
 		})
 	})
@@ -230,7 +236,7 @@ func TestLockTableWaiterWithNonTxn(t *testing.T) {
 		ctxWithCancel, cancel := context.WithCancel(ctx)
 		go cancel()
 
-		err := w.WaitOn(ctxWithCancel, makeReq(), g)
+		err := w.WaitOn(ctxWithCancel, makeReq(), g, nil /* onContentionEvent */)
 		require.NotNil(t, err)
 		require.Equal(t, context.Canceled.Error(), err.GoError().Error())
 	})
@@ -243,7 +249,7 @@ func TestLockTableWaiterWithNonTxn(t *testing.T) {
 			w.stopper.Quiesce(ctx)
 		}()
 
-		err := w.WaitOn(ctx, makeReq(), g)
+		err := w.WaitOn(ctx, makeReq(), g, nil /* onContentionEvent */)
 		require.NotNil(t, err)
 		require.IsType(t, &roachpb.NodeUnavailableError{}, err.GetDetail())
 	})
@@ -274,7 +280,7 @@ func testWaitPush(t *testing.T, k waitKind, makeReq func() Request, expPushTS hl
 			// waitElsewhere does not cause a push if the lock is not held.
 			// It returns immediately.
 			if k == waitElsewhere && !lockHeld {
-				err := w.WaitOn(ctx, req, g)
+				err := w.WaitOn(ctx, req, g, nil /* onContentionEvent */)
 				require.Nil(t, err)
 				return
 			}
@@ -283,7 +289,7 @@ func testWaitPush(t *testing.T, k waitKind, makeReq func() Request, expPushTS hl
 			// They wait for doneWaiting.
 			if req.Txn == nil && !lockHeld {
 				defer notifyUntilDone(t, g)()
-				err := w.WaitOn(ctx, req, g)
+				err := w.WaitOn(ctx, req, g, nil /* onContentionEvent */)
 				require.Nil(t, err)
 				return
 			}
@@ -324,7 +330,7 @@ func testWaitPush(t *testing.T, k waitKind, makeReq func() Request, expPushTS hl
 				return resp, nil
 			}
 
-			err := w.WaitOn(ctx, req, g)
+			err := w.WaitOn(ctx, req, g, nil /* onContentionEvent */)
 			require.Nil(t, err)
 		})
 	})
@@ -339,7 +345,7 @@ func testWaitNoopUntilDone(t *testing.T, k waitKind, makeReq func() Request) {
 	g.notify()
 	defer notifyUntilDone(t, g)()
 
-	err := w.WaitOn(ctx, makeReq(), g)
+	err := w.WaitOn(ctx, makeReq(), g, nil /* onContentionEvent */)
 	require.Nil(t, err)
 }
 
@@ -400,7 +406,7 @@ func TestLockTableWaiterWithErrorWaitPolicy(t *testing.T) {
 			g.state = waitingState{kind: doneWaiting}
 			g.notify()
 
-			err := w.WaitOn(ctx, makeReq(), g)
+			err := w.WaitOn(ctx, makeReq(), g, nil /* onContentionEvent */)
 			require.Nil(t, err)
 		})
 	})
@@ -427,7 +433,7 @@ func testErrorWaitPush(t *testing.T, k waitKind, makeReq func() Request, expPush
 		// If the lock is not held, expect an error immediately. The one
 		// exception to this is waitElsewhere, which expects no error.
 		if !lockHeld {
-			err := w.WaitOn(ctx, req, g)
+			err := w.WaitOn(ctx, req, g, nil /* onContentionEvent */)
 			if k == waitElsewhere {
 				require.Nil(t, err)
 			} else {
@@ -463,7 +469,7 @@ func testErrorWaitPush(t *testing.T, k waitKind, makeReq func() Request, expPush
 			return resp, nil
 		}
 
-		err := w.WaitOn(ctx, req, g)
+		err := w.WaitOn(ctx, req, g, nil /* onContentionEvent */)
 		require.Nil(t, err)
 	})
 }
@@ -508,7 +514,7 @@ func TestLockTableWaiterIntentResolverError(t *testing.T) {
 		) (*roachpb.Transaction, *Error) {
 			return nil, err1
 		}
-		err := w.WaitOn(ctx, req, g)
+		err := w.WaitOn(ctx, req, g, nil /* onContentionEvent */)
 		require.Equal(t, err1, err)
 
 		if lockHeld {
@@ -522,7 +528,7 @@ func TestLockTableWaiterIntentResolverError(t *testing.T) {
 			ir.resolveIntent = func(_ context.Context, intent roachpb.LockUpdate) *Error {
 				return err2
 			}
-			err = w.WaitOn(ctx, req, g)
+			err = w.WaitOn(ctx, req, g, nil /* onContentionEvent */)
 			require.Equal(t, err2, err)
 		}
 	})
@@ -568,7 +574,7 @@ func TestLockTableWaiterDeferredIntentResolverError(t *testing.T) {
 		require.Equal(t, roachpb.ABORTED, intents[0].Status)
 		return err1
 	}
-	err := w.WaitOn(ctx, req, g)
+	err := w.WaitOn(ctx, req, g, nil /* onContentionEvent */)
 	require.Equal(t, err1, err)
 }
 
@@ -635,3 +641,46 @@ func BenchmarkTxnCache(b *testing.B) {
 		}
 	}
 }
+
+// TestContentionEventHelper tests that the contentionEventHelper emits a
+// ContentionEvent each time the request stops waiting on a transaction and key.
+func TestContentionEventHelper(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	var events []roachpb.ContentionEvent
+	h := contentionEventHelper{
+		onEvent: func(ev *roachpb.ContentionEvent) { events = append(events, *ev) },
+	}
+	txn1 := makeTxnProto("txn1")
+	txn2 := makeTxnProto("txn2")
+	keyA, keyB := roachpb.Key("a"), roachpb.Key("b")
+
+	// Waiting on the same transaction and key does not emit an event.
+	h.emitAndInit(ctx, waitingState{kind: waitFor, txn: &txn1.TxnMeta, key: keyA})
+	h.emitAndInit(ctx, waitingState{kind: waitForDistinguished, txn: &txn1.TxnMeta, key: keyA})
+	require.Len(t, events, 0)
+
+	// Waiting on a different key emits an event for the previous one.
+	h.emitAndInit(ctx, waitingState{kind: waitFor, txn: &txn1.TxnMeta, key: keyB})
+	require.Len(t, events, 1)
+	require.Equal(t, keyA, events[0].Key)
+	require.Equal(t, txn1.ID, events[0].Txn.ID)
+
+	// Waiting on a different transaction emits an event for the previous one.
+	h.emitAndInit(ctx, waitingState{kind: waitElsewhere, txn: &txn2.TxnMeta, key: keyB})
+	require.Len(t, events, 2)
+	require.Equal(t, keyB, events[1].Key)
+	require.Equal(t, txn1.ID, events[1].Txn.ID)
+
+	// Finishing waiting emits the last event.
+	h.emitAndInit(ctx, waitingState{kind: doneWaiting})
+	require.Len(t, events, 3)
+	require.Equal(t, keyB, events[2].Key)
+	require.Equal(t, txn2.ID, events[2].Txn.ID)
+
+	// Nothing is left to emit.
+	h.emit(ctx)
+	require.Len(t, events, 3)
+}
@@ -285,6 +285,12 @@ var (
 		Measurement: "Keys/Sec",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaAverageWallNanosPerSecond = metric.Metadata{
+		Name:        "rebalancing.wallnanospersecond",
+		Help:        "Wall time nanoseconds per second spent evaluating requests and applying raft commands on the store's replicas, used as an estimate of their CPU usage, averaged over a large time period as used in rebalancing decisions",
+		Measurement: "Nanoseconds/Sec",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
 
 	// Metric for tracking follower reads.
 	metaFollowerReadsCount = metric.Metadata{
@@ -1078,8 +1084,9 @@ type StoreMetrics struct {
 	Reserved           *metric.Gauge
 
 	// Rebalancing metrics.
-	AverageQueriesPerSecond *metric.GaugeFloat64
-	AverageWritesPerSecond  *metric.GaugeFloat64
+	AverageQueriesPerSecond   *metric.GaugeFloat64
+	AverageWritesPerSecond    *metric.GaugeFloat64
+	AverageWallNanosPerSecond *metric.GaugeFloat64
 
 	// Follower read metrics.
 	FollowerReadsCount *metric.Counter
@@ -1449,8 +1456,9 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		Reserved:  metric.NewGauge(metaReserved),
 
 		// Rebalancing metrics.
-		AverageQueriesPerSecond: metric.NewGaugeFloat64(metaAverageQueriesPerSecond),
-		AverageWritesPerSecond:  metric.NewGaugeFloat64(metaAverageWritesPerSecond),
+		AverageQueriesPerSecond:   metric.NewGaugeFloat64(metaAverageQueriesPerSecond),
+		AverageWritesPerSecond:    metric.NewGaugeFloat64(metaAverageWritesPerSecond),
+		AverageWallNanosPerSecond: metric.NewGaugeFloat64(metaAverageWallNanosPerSecond),
 
 		// Follower reads metrics.
 		FollowerReadsCount: metric.NewCounter(metaFollowerReadsCount),
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/abortspan"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/gc"
@@ -205,6 +206,11 @@ type Replica struct {
 	// writeStats tracks the number of keys written by applied raft commands
 	// in order to aid in replica rebalancing decisions.
 	writeStats *replicaStats
+	// cpuStats tracks the nanoseconds spent evaluating requests and applying
+	// raft commands in order to aid in CPU-based rebalancing decisions. The
+	// time is measured on the goroutine doing the work, which approximates the
+	// CPU time attributable to the replica.
+	cpuStats *replicaStats
 
 	// creatingReplica is set when a replica is created as uninitialized
 	// via a raft message.
@@ -1060,6 +1066,7 @@ func (r *Replica) State() kvserverpb.RangeInfo {
 		// Learner replicas don't serve follower reads, but they still receive
 		// closed timestamp updates, so include them here.
 		allReplicas := desc.Replicas().All()
+		policy := r.closedTimestampPolicyRLocked()
 		for i := range allReplicas {
 			replDesc := &allReplicas[i]
 			r.store.cfg.ClosedTimestamp.Storage.VisitDescending(replDesc.NodeID, func(e ctpb.Entry) (done bool) {
@@ -1067,9 +1074,13 @@ func (r *Replica) State() kvserverpb.RangeInfo {
 				if !found {
 					return false // not done
 				}
-				if ri.NewestClosedTimestamp.ClosedTimestamp.Less(e.ClosedTimestamp) {
+				closed := e.ClosedTimestamp
+				if policy == closedts.LeadForGlobalReads {
+					closed = e.ClosedTimestampLead
+				}
+				if ri.NewestClosedTimestamp.ClosedTimestamp.Less(closed) {
 					ri.NewestClosedTimestamp.NodeID = replDesc.NodeID
-					ri.NewestClosedTimestamp.ClosedTimestamp = e.ClosedTimestamp
+					ri.NewestClosedTimestamp.ClosedTimestamp = closed
 					ri.NewestClosedTimestamp.MLAI = int64(mlai)
 					ri.NewestClosedTimestamp.Epoch = int64(e.Epoch)
 				}
@@ -203,7 +203,7 @@ func (r *Replica) tryReproposeWithNewLeaseIndex(
 		return nil
 	}
 
-	minTS, untrack := r.store.cfg.ClosedTimestamp.Tracker.Track(ctx)
+	minTS, untrack := r.store.cfg.ClosedTimestamp.Tracker.Track(ctx, r.GetClosedTimestampPolicy())
 	defer untrack(ctx, 0, 0, 0) // covers all error paths below
 	// NB: p.Request.Timestamp reflects the action of ba.SetActiveTimestamp.
 	if p.Request.Timestamp.Less(minTS) {
@@ -13,6 +13,7 @@ package kvserver
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 )
 
@@ -28,13 +29,14 @@ func (r *Replica) EmitMLAI() {
 	epoch := r.mu.state.Lease.Epoch
 	isLeaseholder := r.mu.state.Lease.Replica.ReplicaID == r.mu.replicaID
 	isMergeInProgress := r.mergeInProgressRLocked()
+	policy := r.closedTimestampPolicyRLocked()
 	r.mu.RUnlock()
 
 	// If we're the leaseholder of an epoch-based lease, notify the minPropTracker
 	// of the current LAI to trigger a re-broadcast of this range's LAI.
 	if isLeaseholder && epoch > 0 {
 		ctx := r.AnnotateCtx(context.Background())
-		_, untrack := r.store.cfg.ClosedTimestamp.Tracker.Track(ctx)
+		_, untrack := r.store.cfg.ClosedTimestamp.Tracker.Track(ctx, policy)
 		if isMergeInProgress {
 			// A critical requirement for the correctness of range merges is that we
 			// don't allow follower reads on closed timestamps that are greater than
@@ -57,3 +59,20 @@ func (r *Replica) EmitMLAI() {
 		}
 	}
 }
+
+// GetClosedTimestampPolicy returns the closed timestamp policy of the range,
+// which is determined by the global_reads attribute of its zone configuration.
+func (r *Replica) GetClosedTimestampPolicy() closedts.RangeClosedTimestampPolicy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.closedTimestampPolicyRLocked()
+}
+
+// closedTimestampPolicyRLocked is like GetClosedTimestampPolicy, but requires
+// r.mu to be held.
+func (r *Replica) closedTimestampPolicyRLocked() closedts.RangeClosedTimestampPolicy {
+	if zone := r.mu.zone; zone != nil && zone.GlobalReads != nil && *zone.GlobalReads {
+		return closedts.LeadForGlobalReads
+	}
+	return closedts.LagByClusterSetting
+}
@@ -117,6 +117,11 @@ func (rec *SpanSetReplicaEvalContext) GetTracker() closedts.TrackerI {
 	return rec.i.GetTracker()
 }
 
+// GetClosedTimestampPolicy returns the closed timestamp policy of the range.
+func (rec *SpanSetReplicaEvalContext) GetClosedTimestampPolicy() closedts.RangeClosedTimestampPolicy {
+	return rec.i.GetClosedTimestampPolicy()
+}
+
 // IsFirstRange returns true iff the replica belongs to the first range.
 func (rec *SpanSetReplicaEvalContext) IsFirstRange() bool {
 	return rec.i.IsFirstRange()
@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -142,7 +143,8 @@ func optimizePuts(
 
 // evaluateBatch evaluates a batch request by splitting it up into its
 // individual commands, passing them to evaluateCommand, and combining
-// the results.
+// the results. The concurrency guard g, which may be nil, gives commands a
+// view into the lock table.
 func evaluateBatch(
 	ctx context.Context,
 	idKey kvserverbase.CmdIDKey,
@@ -150,6 +152,7 @@ func evaluateBatch(
 	rec batcheval.EvalContext,
 	ms *enginepb.MVCCStats,
 	ba *roachpb.BatchRequest,
+	g *concurrency.Guard,
 	readOnly bool,
 ) (_ *roachpb.BatchResponse, _ result.Result, retErr *roachpb.Error) {
 
@@ -268,7 +271,7 @@ func evaluateBatch(
 		// may carry a response transaction and in the case of WriteTooOldError
 		// (which is sometimes deferred) it is fully populated.
 		curResult, err := evaluateCommand(
-			ctx, idKey, index, readWriter, rec, ms, baHeader, args, reply)
+			ctx, idKey, index, readWriter, rec, ms, baHeader, args, reply, g)
 
 		if filter := rec.EvalKnobs().TestingPostEvalFilter; filter != nil {
 			filterArgs := kvserverbase.FilterArgs{
@@ -487,6 +490,7 @@ func evaluateCommand(
 	h roachpb.Header,
 	args roachpb.Request,
 	reply roachpb.Response,
+	g *concurrency.Guard,
 ) (result.Result, error) {
 	var err error
 	var pd result.Result
@@ -495,8 +499,9 @@ func evaluateCommand(
 		cArgs := batcheval.CommandArgs{
 			EvalCtx: rec,
 			Header:  h,
-			Args:    args,
-			Stats:   ms,
+			Args:        args,
+			Stats:       ms,
+			Concurrency: g,
 		}
 
 		if cmd.EvalRW != nil {
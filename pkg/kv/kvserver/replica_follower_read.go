@@ -18,6 +18,7 @@ import (
 	ctstorage "github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/storage"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
@@ -32,6 +33,28 @@ var FollowerReadsEnabled = settings.RegisterPublicBoolSetting(
 	true,
 )
 
+// boundedStalenessMaxIntents is the maximum number of intents scanned when
+// computing the local resolved timestamp for a bounded staleness read. If the
+// batch's spans contain more intents, the read is redirected to the
+// leaseholder.
+var boundedStalenessMaxIntents = settings.RegisterNonNegativeIntSetting(
+	"kv.bounded_staleness.max_intents_scanned",
+	"maximum number of intents scanned by a follower to negotiate the timestamp of "+
+		"a bounded staleness read before redirecting it to the leaseholder",
+	1000,
+)
+
+// boundedStalenessMaxIntentBytes is the maximum number of intent bytes
+// scanned when computing the local resolved timestamp for a bounded staleness
+// read. If the batch's spans contain more intent bytes, the read is
+// redirected to the leaseholder.
+var boundedStalenessMaxIntentBytes = settings.RegisterByteSizeSetting(
+	"kv.bounded_staleness.max_intent_bytes_scanned",
+	"maximum number of intent bytes scanned by a follower to negotiate the timestamp of "+
+		"a bounded staleness read before redirecting it to the leaseholder",
+	1<<20, /* 1 MiB */
+)
+
 // canServeFollowerRead tests, when a range lease could not be acquired, whether
 // the batch can be served as a follower read despite the error. Only
 // non-locking, read-only requests can be served as follower reads. The batch
@@ -110,7 +133,9 @@ func (r *Replica) canServeFollowerRead(
 // maxClosed returns the maximum closed timestamp for this range.
 // It is computed as the most recent of the known closed timestamp for the
 // current lease holder for this range as tracked by the closed timestamp
-// subsystem and the start time of the current lease. It is safe to use the
+// subsystem and the start time of the current lease. The known closed
+// timestamp is the one corresponding to the range's closed timestamp policy,
+// which leads present time for ranges configured for global reads. It is safe to use the
 // start time of the current lease because leasePostApply bumps the timestamp
 // cache forward to at least the new lease start time. Using this combination
 // allows the closed timestamp mechanism to be robust to lease transfers.
@@ -123,13 +148,103 @@ func (r *Replica) maxClosed(ctx context.Context) (_ hlc.Timestamp, ok bool) {
 	lai := r.mu.state.LeaseAppliedIndex
 	lease := *r.mu.state.Lease
 	initialMaxClosed := r.mu.initialMaxClosed
+	policy := r.closedTimestampPolicyRLocked()
 	r.mu.RUnlock()
 	if lease.Expiration != nil {
 		return hlc.Timestamp{}, false
 	}
 	maxClosed := r.store.cfg.ClosedTimestamp.Provider.MaxClosed(
-		lease.Replica.NodeID, r.RangeID, ctpb.Epoch(lease.Epoch), ctpb.LAI(lai))
+		lease.Replica.NodeID, r.RangeID, ctpb.Epoch(lease.Epoch), ctpb.LAI(lai), policy)
 	maxClosed.Forward(lease.Start)
 	maxClosed.Forward(initialMaxClosed)
 	return maxClosed, true
 }
+
+// negotiateBoundedStalenessTimestamp returns the timestamp at which a bounded
+// staleness read batch is to be evaluated on this replica. The timestamp is
+// the freshest timestamp at which the replica can serve the batch without
+// blocking, constrained by the batch's timestamp bounds.
+//
+// If the local resolved timestamp is below the batch's minimum timestamp bound
+// and the bound is not strict, the batch is evaluated at the bound. If this
+// replica cannot serve reads at that timestamp, the batch will be redirected
+// to the leaseholder, where it may block on conflicting transactions.
+func (r *Replica) negotiateBoundedStalenessTimestamp(
+	ctx context.Context, ba *roachpb.BatchRequest,
+) (hlc.Timestamp, *roachpb.Error) {
+	bs := ba.BoundedStaleness
+	resolvedTS, ok, err := r.localResolvedTimestamp(ctx, ba)
+	if err != nil {
+		return hlc.Timestamp{}, roachpb.NewError(err)
+	}
+	if !ok {
+		// The batch's spans contain too many intents to compute the local
+		// resolved timestamp. Redirect the batch to the leaseholder, unless this
+		// replica is the leaseholder, in which case the batch is evaluated as if
+		// nothing was resolved and may block on the intents.
+		if lease, _ := r.GetLease(); !lease.OwnedBy(r.store.StoreID()) {
+			return hlc.Timestamp{}, roachpb.NewError(newNotLeaseHolderError(
+				&lease, r.store.StoreID(), r.Desc(),
+				"too many intents to negotiate bounded staleness timestamp on follower"))
+		}
+		resolvedTS = hlc.Timestamp{}
+	}
+	ts := resolvedTS
+	if ts.Less(bs.MinTimestampBound) {
+		if bs.MinTimestampBoundStrict {
+			return hlc.Timestamp{}, roachpb.NewError(
+				roachpb.NewMinTimestampBoundUnsatisfiableError(bs.MinTimestampBound, resolvedTS))
+		}
+		ts = bs.MinTimestampBound
+	}
+	if !bs.MaxTimestampBound.IsEmpty() && bs.MaxTimestampBound.LessEq(ts) {
+		ts = bs.MaxTimestampBound.Prev()
+	}
+	log.VEventf(ctx, 2, "negotiated bounded staleness timestamp %s; local resolved timestamp: %s",
+		ts, resolvedTS)
+	return ts, nil
+}
+
+// localResolvedTimestamp returns the timestamp at or below which this replica
+// can serve reads over the spans of the batch without blocking. It is the
+// range's closed timestamp, lowered to just below the timestamp of any intent
+// in the batch's spans that is not above the closed timestamp.
+//
+// Only intents are scanned, up to the kv.bounded_staleness.max_intents_scanned
+// and kv.bounded_staleness.max_intent_bytes_scanned limits. If either limit is
+// reached, ok is false and the resolved timestamp cannot be determined.
+//
+// A zero timestamp is returned if the range does not support closed timestamps.
+func (r *Replica) localResolvedTimestamp(
+	ctx context.Context, ba *roachpb.BatchRequest,
+) (_ hlc.Timestamp, ok bool, _ error) {
+	resolvedTS, ok := r.maxClosed(ctx)
+	if !ok || resolvedTS.IsEmpty() {
+		return hlc.Timestamp{}, true, nil
+	}
+	maxIntents := boundedStalenessMaxIntents.Get(&r.ClusterSettings().SV)
+	maxIntentBytes := boundedStalenessMaxIntentBytes.Get(&r.ClusterSettings().SV)
+	reader := r.Engine().NewReadOnly()
+	defer reader.Close()
+	for _, union := range ba.Requests {
+		h := union.GetInner().Header()
+		endKey := h.EndKey
+		if len(endKey) == 0 {
+			endKey = h.Key.Next()
+		}
+		intents, resumeKey, err := storage.ScanIntents(
+			ctx, reader, h.Key, endKey, maxIntents, maxIntentBytes,
+		)
+		if err != nil {
+			return hlc.Timestamp{}, false, err
+		}
+		if resumeKey != nil {
+			log.VEventf(ctx, 2, "intent scan for bounded staleness read stopped at %s", resumeKey)
+			return hlc.Timestamp{}, false, nil
+		}
+		for _, intent := range intents {
+			resolvedTS.Backward(intent.Txn.WriteTimestamp.Prev())
+		}
+	}
+	return resolvedTS, true, nil
+}
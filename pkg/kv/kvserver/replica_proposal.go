@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
@@ -381,7 +382,18 @@ func (r *Replica) leasePostApply(ctx context.Context, newLease roachpb.Lease, pe
 		// requests, this is kosher). This means that we don't use the old
 		// lease's expiration but instead use the new lease's start to initialize
 		// the timestamp cache low water.
-		setTimestampCacheLowWaterMark(r.store.tsCache, r.Desc(), newLease.Start)
+		//
+		// If the range closes timestamps in the future, the previous leaseholder
+		// may have published closed timestamps that lead the new lease's start
+		// time by up to the lead target duration. The low water mark is forwarded
+		// past them so that the new leaseholder does not write below a timestamp
+		// that followers may already consider closed.
+		lowWater := newLease.Start
+		if r.GetClosedTimestampPolicy() == closedts.LeadForGlobalReads {
+			lead := closedts.LeadTargetDuration(&r.ClusterSettings().SV, r.Clock().MaxOffset())
+			lowWater = lowWater.Add(lead.Nanoseconds(), 0).SetFlag(hlc.TimestampFlag_SYNTHETIC)
+		}
+		setTimestampCacheLowWaterMark(r.store.tsCache, r.Desc(), lowWater)
 
 		// Reset the request counts used to make lease placement decisions whenever
 		// starting a new lease.
@@ -71,7 +71,9 @@ func (r *Replica) evalAndPropose(
 	ctx context.Context, ba *roachpb.BatchRequest, g *concurrency.Guard, lease *roachpb.Lease,
 ) (chan proposalResult, func(), int64, *roachpb.Error) {
 	idKey := makeIDKey()
+	evalStart := timeutil.Now()
 	proposal, pErr := r.requestToProposal(ctx, idKey, ba, g.LatchSpans())
+	r.recordRequestCPU(ctx, g.LatchSpans(), timeutil.Since(evalStart))
 	log.Event(proposal.ctx, "evaluated request")
 
 	// If the request hit a server-side concurrency retry error, immediately
@@ -800,6 +802,11 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 	}
 	applicationElapsed := timeutil.Since(applicationStart).Nanoseconds()
 	r.store.metrics.RaftApplyCommittedLatency.RecordValue(applicationElapsed)
+	if len(rd.CommittedEntries) > 0 && r.cpuStats != nil {
+		// Attribute the time spent applying committed entries to the replica's
+		// CPU usage.
+		r.cpuStats.recordDuration(time.Duration(applicationElapsed))
+	}
 	if r.store.TestingKnobs().EnableUnconditionalRefreshesInRaftReady {
 		refreshReason = reasonNewLeaderOrConfigChange
 	}
@@ -24,17 +24,20 @@ const (
 type replicaWithStats struct {
 	repl *Replica
 	qps  float64
+	// cpu is the CPU time, in nanoseconds, spent per second on the replica.
+	cpu float64
 	// TODO(a-robinson): Include writes-per-second and logicalBytes of storage?
 }
 
 // replicaRankings maintains top-k orderings of the replicas in a store along
-// different dimensions of concern, such as QPS, keys written per second, and
-// disk used.
+// different dimensions of concern, such as QPS, CPU, keys written per second,
+// and disk used.
 type replicaRankings struct {
 	mu struct {
 		syncutil.Mutex
-		qpsAccumulator *rrAccumulator
-		byQPS          []replicaWithStats
+		accumulator *rrAccumulator
+		byQPS       []replicaWithStats
+		byCPU       []replicaWithStats
 	}
 }
 
@@ -45,12 +48,13 @@ func newReplicaRankings() *replicaRankings {
 func (rr *replicaRankings) newAccumulator() *rrAccumulator {
 	res := &rrAccumulator{}
 	res.qps.val = func(r replicaWithStats) float64 { return r.qps }
+	res.cpu.val = func(r replicaWithStats) float64 { return r.cpu }
 	return res
 }
 
 func (rr *replicaRankings) update(acc *rrAccumulator) {
 	rr.mu.Lock()
-	rr.mu.qpsAccumulator = acc
+	rr.mu.accumulator = acc
 	rr.mu.Unlock()
 }
 
@@ -59,12 +63,32 @@ func (rr *replicaRankings) topQPS() []replicaWithStats {
 	defer rr.mu.Unlock()
 	// If we have a new set of data, consume it. Otherwise, just return the most
 	// recently consumed data.
-	if rr.mu.qpsAccumulator.qps.Len() > 0 {
-		rr.mu.byQPS = consumeAccumulator(&rr.mu.qpsAccumulator.qps)
+	if rr.mu.accumulator.qps.Len() > 0 {
+		rr.mu.byQPS = consumeAccumulator(&rr.mu.accumulator.qps)
 	}
 	return rr.mu.byQPS
 }
 
+func (rr *replicaRankings) topCPU() []replicaWithStats {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	// If we have a new set of data, consume it. Otherwise, just return the most
+	// recently consumed data.
+	if rr.mu.accumulator.cpu.Len() > 0 {
+		rr.mu.byCPU = consumeAccumulator(&rr.mu.accumulator.cpu)
+	}
+	return rr.mu.byCPU
+}
+
+// top returns the replicas with the most load along the objective's
+// dimension, in descending order.
+func (rr *replicaRankings) top(objective LBRebalancingObjective) []replicaWithStats {
+	if objective == LBRebalancingCPU {
+		return rr.topCPU()
+	}
+	return rr.topQPS()
+}
+
 // rrAccumulator is used to update the replicas tracked by replicaRankings.
 // The typical pattern should be to call replicaRankings.newAccumulator, add
 // all the replicas you care about to the accumulator using addReplica, then
@@ -75,20 +99,26 @@ func (rr *replicaRankings) topQPS() []replicaWithStats {
 // `update`d accumulator will win.
 type rrAccumulator struct {
 	qps rrPriorityQueue
+	cpu rrPriorityQueue
 }
 
 func (a *rrAccumulator) addReplica(repl replicaWithStats) {
+	a.qps.maybeAdd(repl)
+	a.cpu.maybeAdd(repl)
+}
+
+func (pq *rrPriorityQueue) maybeAdd(repl replicaWithStats) {
 	// If the heap isn't full, just push the new replica and return.
-	if a.qps.Len() < numTopReplicasToTrack {
-		heap.Push(&a.qps, repl)
+	if pq.Len() < numTopReplicasToTrack {
+		heap.Push(pq, repl)
 		return
 	}
 
 	// Otherwise, conditionally push if the new replica is more deserving than
 	// the current tip of the heap.
-	if repl.qps > a.qps.entries[0].qps {
-		heap.Pop(&a.qps)
-		heap.Push(&a.qps, repl)
+	if pq.val(repl) > pq.val(pq.entries[0]) {
+		heap.Pop(pq)
+		heap.Push(pq, repl)
 	}
 }
 
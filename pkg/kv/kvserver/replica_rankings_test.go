@@ -51,6 +51,8 @@ func TestReplicaRankings(t *testing.T) {
 			acc.addReplica(replicaWithStats{
 				repl: &Replica{RangeID: roachpb.RangeID(i)},
 				qps:  replQPS,
+				// Rank replicas by CPU in the opposite order to QPS.
+				cpu: -replQPS,
 			})
 		}
 		rr.update(acc)
@@ -71,5 +73,18 @@ func TestReplicaRankings(t *testing.T) {
 		if !reflect.DeepEqual(repls, replsCopy) {
 			t.Errorf("got different replicas on second call to topQPS; first call: %v, second call: %v", repls, replsCopy)
 		}
+
+		// The CPU rankings are tracked independently of the QPS rankings.
+		repls = rr.topCPU()
+		if len(repls) != len(want) {
+			t.Errorf("wrong number of replicas in output; got: %v; want: %v", repls, tc.replicasByQPS)
+			continue
+		}
+		for i := range want {
+			if wantCPU := -want[len(want)-1-i]; repls[i].cpu != wantCPU {
+				t.Errorf("got %f for %d'th element; want %f (input: %v)", repls[i].cpu, i, wantCPU, tc.replicasByQPS)
+				break
+			}
+		}
 	}
 }
@@ -351,9 +351,16 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 			}
 		}
 
+		// Grab the contention events encountered while sequencing before
+		// evaluating the batch. The guard may be released during evaluation.
+		contentionEvents := g.ContentionEvents()
+
 		br, g, pErr = fn(r, ctx, ba, status, g)
 		if pErr == nil {
 			// Success.
+			if len(contentionEvents) > 0 {
+				br.ContentionEvents = append(br.ContentionEvents, contentionEvents...)
+			}
 			return br, nil
 		} else if !isConcurrencyRetryError(pErr) {
 			// Propagate error.
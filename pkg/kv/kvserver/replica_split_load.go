@@ -34,6 +34,14 @@ var SplitByLoadQPSThreshold = settings.RegisterPublicIntSetting(
 	2500, // 2500 req/s
 )
 
+// SplitByLoadCPUThreshold wraps "kv.range_split.load_cpu_threshold".
+var SplitByLoadCPUThreshold = settings.RegisterPublicNonNegativeDurationSetting(
+	"kv.range_split.load_cpu_threshold",
+	"the CPU time per second over which, the range becomes a candidate for load based splitting "+
+		"when the load based rebalancing objective is cpu",
+	250*time.Millisecond, // 250ms/s
+)
+
 // SplitByLoadMergeDelay wraps "kv.range_split.by_load_merge_delay".
 var SplitByLoadMergeDelay = settings.RegisterNonNegativeDurationSetting(
 	"kv.range_split.by_load_merge_delay",
@@ -41,9 +49,19 @@ var SplitByLoadMergeDelay = settings.RegisterNonNegativeDurationSetting(
 	5*time.Minute,
 )
 
-// SplitByLoadQPSThreshold returns the QPS request rate for a given replica.
-func (r *Replica) SplitByLoadQPSThreshold() float64 {
-	return float64(SplitByLoadQPSThreshold.Get(&r.store.cfg.Settings.SV))
+// SplitByLoadThreshold returns the load threshold over which a given replica
+// becomes a candidate for load based splitting. The threshold is expressed in
+// the unit of the current load based rebalancing objective: requests per
+// second for qps and nanoseconds per second for cpu.
+func (r *Replica) SplitByLoadThreshold() float64 {
+	return splitByLoadThreshold(&r.store.cfg.Settings.SV)
+}
+
+func splitByLoadThreshold(sv *settings.Values) float64 {
+	if LBRebalancingObjective(LoadBasedRebalancingObjective.Get(sv)) == LBRebalancingCPU {
+		return float64(SplitByLoadCPUThreshold.Get(sv))
+	}
+	return float64(SplitByLoadQPSThreshold.Get(sv))
 }
 
 // SplitByLoadEnabled returns whether load based splitting is enabled.
@@ -59,10 +77,38 @@ func (r *Replica) SplitByLoadEnabled() bool {
 func (r *Replica) recordBatchForLoadBasedSplitting(
 	ctx context.Context, ba *roachpb.BatchRequest, spans *spanset.SpanSet,
 ) {
-	if !r.SplitByLoadEnabled() {
+	if !r.SplitByLoadEnabled() || r.splitByCPU() {
+		return
+	}
+	r.recordLoadForLoadBasedSplitting(ctx, len(ba.Requests), spans)
+}
+
+// recordRequestCPU records the time spent evaluating a batch against the
+// replica's CPU stats. If load based splitting is configured to split on CPU,
+// the time is also recorded against the batch's spans.
+func (r *Replica) recordRequestCPU(
+	ctx context.Context, spans *spanset.SpanSet, dur time.Duration,
+) {
+	if r.cpuStats != nil {
+		r.cpuStats.recordDuration(dur)
+	}
+	if !r.SplitByLoadEnabled() || !r.splitByCPU() {
 		return
 	}
-	shouldInitSplit := r.loadBasedSplitter.Record(timeutil.Now(), len(ba.Requests), func() roachpb.Span {
+	r.recordLoadForLoadBasedSplitting(ctx, int(dur), spans)
+}
+
+// splitByCPU returns whether load based splitting measures load as CPU time
+// instead of as requests.
+func (r *Replica) splitByCPU() bool {
+	return LBRebalancingObjective(LoadBasedRebalancingObjective.Get(&r.store.cfg.Settings.SV)) ==
+		LBRebalancingCPU
+}
+
+func (r *Replica) recordLoadForLoadBasedSplitting(
+	ctx context.Context, n int, spans *spanset.SpanSet,
+) {
+	shouldInitSplit := r.loadBasedSplitter.Record(timeutil.Now(), n, func() roachpb.Span {
 		return spans.BoundarySpan(spanset.SpanGlobal)
 	})
 	if shouldInitSplit {
@@ -213,6 +213,20 @@ func (rs *replicaStats) avgQPS() (float64, time.Duration) {
 	return sum / duration.Seconds(), duration
 }
 
+// recordDuration records time spent on work done on behalf of the replica. It
+// is used by stats that account for the replica's CPU usage, which is
+// approximated by the wall time of the goroutine doing the work. The time is
+// not attributed to a locality.
+func (rs *replicaStats) recordDuration(d time.Duration) {
+	rs.recordCount(float64(d.Nanoseconds()), 0 /* nodeID */)
+}
+
+// avgNanosPerSecond returns the average nanoseconds per second recorded with
+// recordDuration and the amount of time over which the stat was accumulated.
+func (rs *replicaStats) avgNanosPerSecond() (float64, time.Duration) {
+	return rs.avgQPS()
+}
+
 func (rs *replicaStats) resetRequestCounts() {
 	rs.mu.Lock()
 	defer rs.mu.Unlock()
@@ -84,7 +84,7 @@ func (r *Replica) executeWriteBatch(
 		return nil, g, roachpb.NewError(err)
 	}
 
-	minTS, untrack := r.store.cfg.ClosedTimestamp.Tracker.Track(ctx)
+	minTS, untrack := r.store.cfg.ClosedTimestamp.Tracker.Track(ctx, r.GetClosedTimestampPolicy())
 	defer untrack(ctx, 0, 0, 0) // covers all error returns below
 
 	// Examine the timestamp cache for preceding commands which require this
@@ -216,6 +216,16 @@ func (r *Replica) executeWriteBatch(
 					log.Warningf(ctx, "%v", err)
 				}
 			}
+			// Non-transactional writes that were pushed into the future by a
+			// closed timestamp lead must commit-wait before being acknowledged,
+			// so that a causally dependent read observes them. Transactional
+			// writes commit-wait in the TxnCoordSender instead.
+			if ba.Txn == nil && propResult.Err == nil && propResult.Reply != nil &&
+				propResult.Reply.Timestamp.IsFlagSet(hlc.TimestampFlag_SYNTHETIC) {
+				if err := r.store.Clock().SleepUntil(ctx, propResult.Reply.Timestamp); err != nil {
+					return nil, nil, roachpb.NewError(roachpb.NewAmbiguousResultError(err.Error()))
+				}
+			}
 			return propResult.Reply, nil, propResult.Err
 		case <-slowTimer.C:
 			slowTimer.Read = true
@@ -599,7 +609,10 @@ func (r *Replica) evaluateWriteBatchWrapper(
 	latchSpans *spanset.SpanSet,
 ) (storage.Batch, *roachpb.BatchResponse, result.Result, *roachpb.Error) {
 	batch, opLogger := r.newBatchedEngine(latchSpans)
-	br, res, pErr := evaluateBatch(ctx, idKey, batch, rec, ms, ba, false /* readOnly */)
+	// NB: write batches don't need a view into the lock table during
+	// evaluation because only read-only batches may use the SkipLocked wait
+	// policy.
+	br, res, pErr := evaluateBatch(ctx, idKey, batch, rec, ms, ba, nil /* g */, false /* readOnly */)
 	if pErr == nil {
 		if opLogger != nil {
 			res.LogicalOpLog = &kvserverpb.LogicalOpLog{
@@ -11,6 +11,9 @@
 package spanset
 
 import (
+	"bytes"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -231,6 +234,10 @@ func (i *MVCCIterator) SupportsPrev() bool {
 type EngineIterator struct {
 	i     storage.EngineIterator
 	spans *SpanSet
+
+	// spansOnly and ts have the same meaning as in MVCCIterator.
+	spansOnly bool
+	ts        hlc.Timestamp
 }
 
 // Close is part of the storage.EngineIterator interface.
@@ -244,7 +251,7 @@ func (i *EngineIterator) SeekEngineKeyGE(key storage.EngineKey) (valid bool, err
 	if !valid {
 		return valid, err
 	}
-	if err = i.spans.CheckAllowed(SpanReadOnly, roachpb.Span{Key: key.Key}); err != nil {
+	if err = checkAllowedEngineKey(i.spans, SpanReadOnly, key.Key, false /* endKey */, i.spansOnly, i.ts); err != nil {
 		return false, err
 	}
 	return valid, err
@@ -256,7 +263,7 @@ func (i *EngineIterator) SeekEngineKeyLT(key storage.EngineKey) (valid bool, err
 	if !valid {
 		return valid, err
 	}
-	if err = i.spans.CheckAllowed(SpanReadOnly, roachpb.Span{EndKey: key.Key}); err != nil {
+	if err = checkAllowedEngineKey(i.spans, SpanReadOnly, key.Key, true /* endKey */, i.spansOnly, i.ts); err != nil {
 		return false, err
 	}
 	return valid, err
@@ -285,13 +292,44 @@ func (i *EngineIterator) checkKeyAllowed() (valid bool, err error) {
 	if err != nil {
 		return false, err
 	}
-	if err = i.spans.CheckAllowed(SpanReadOnly, roachpb.Span{Key: key.Key}); err != nil {
+	if err = checkAllowedEngineKey(i.spans, SpanReadOnly, key.Key, false /* endKey */, i.spansOnly, i.ts); err != nil {
 		// Invalid, but no error.
 		return false, nil // nolint:returnerrcheck
 	}
 	return true, nil
 }
 
+// checkAllowedEngineKey returns an error if the access to the given engine
+// key is not permitted by the spans. Keys in the lock table key space are
+// permitted if either the lock table key itself or the key that it locks is
+// covered by the spans, since requests that acquire or release replicated
+// locks declare the keys that they lock and not the corresponding lock table
+// keys. If spansOnly is false, the access is checked at timestamp ts. If
+// endKey is true, key is treated as an exclusive end key.
+func checkAllowedEngineKey(
+	spans *SpanSet, access SpanAccess, key roachpb.Key, endKey, spansOnly bool, ts hlc.Timestamp,
+) error {
+	makeSpan := func(k roachpb.Key) roachpb.Span {
+		if endKey {
+			return roachpb.Span{EndKey: k}
+		}
+		return roachpb.Span{Key: k}
+	}
+	span := makeSpan(key)
+	if bytes.HasPrefix(key, keys.LocalRangeLockTablePrefix) {
+		if spans.CheckAllowed(access, span) == nil {
+			return nil
+		}
+		if lockedKey, err := keys.DecodeLockTableSingleKey(key); err == nil {
+			span = makeSpan(lockedKey)
+		}
+	}
+	if spansOnly {
+		return spans.CheckAllowed(access, span)
+	}
+	return spans.CheckAllowedAt(access, span, ts)
+}
+
 // UnsafeEngineKey is part of the storage.EngineIterator interface.
 func (i *EngineIterator) UnsafeEngineKey() (storage.EngineKey, error) {
 	return i.i.UnsafeEngineKey()
@@ -406,12 +444,11 @@ func (s spanSetReader) NewMVCCIterator(
 }
 
 func (s spanSetReader) NewEngineIterator(opts storage.IterOptions) storage.EngineIterator {
-	if !s.spansOnly {
-		panic("cannot do timestamp checking for EngineIterator")
-	}
 	return &EngineIterator{
-		i:     s.r.NewEngineIterator(opts),
-		spans: s.spans,
+		i:         s.r.NewEngineIterator(opts),
+		spans:     s.spans,
+		spansOnly: s.spansOnly,
+		ts:        s.ts,
 	}
 }
 
@@ -489,10 +526,7 @@ func (s spanSetWriter) ClearIntent(
 }
 
 func (s spanSetWriter) ClearEngineKey(key storage.EngineKey) error {
-	if !s.spansOnly {
-		panic("cannot do timestamp checking for clearing EngineKey")
-	}
-	if err := s.spans.CheckAllowed(SpanReadWrite, roachpb.Span{Key: key.Key}); err != nil {
+	if err := checkAllowedEngineKey(s.spans, SpanReadWrite, key.Key, false /* endKey */, s.spansOnly, s.ts); err != nil {
 		return err
 	}
 	return s.w.ClearEngineKey(key)
@@ -586,10 +620,7 @@ func (s spanSetWriter) PutIntent(
 }
 
 func (s spanSetWriter) PutEngineKey(key storage.EngineKey, value []byte) error {
-	if !s.spansOnly {
-		panic("cannot do timestamp checking for putting EngineKey")
-	}
-	if err := s.spans.CheckAllowed(SpanReadWrite, roachpb.Span{Key: key.Key}); err != nil {
+	if err := checkAllowedEngineKey(s.spans, SpanReadWrite, key.Key, false /* endKey */, s.spansOnly, s.ts); err != nil {
 		return err
 	}
 	return s.w.PutEngineKey(key, value)
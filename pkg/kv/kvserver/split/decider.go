@@ -25,12 +25,15 @@ const minSplitSuggestionInterval = time.Minute
 // A Decider collects measurements about the activity (measured in qps) on a
 // Replica and, assuming that qps thresholds are exceeded, tries to determine
 // a split key that would approximately result in halving the load on each of
-// the resultant ranges.
+// the resultant ranges. The unit of load is up to the caller: it is typically
+// the number of requests, but may also be a weight such as the nanoseconds of
+// CPU time spent on each request, in which case the "qps" is a CPU rate and
+// the threshold should be expressed in the same unit.
 //
 // Operations should call `Record` with a current timestamp. Operation counts
 // are aggregated over a second and a qps computed. If the QPS is above threshold,
-// a split finder is instantiated and the spans supplied to Record are sampled
-// for a duration (on the order of ten seconds). Assuming that load consistently
+// a split finder is instantiated and the spans supplied to Record are sampled,
+// weighted by their load, for a duration (on the order of ten seconds). Assuming that load consistently
 // remains over threshold, and the workload touches a diverse enough set of keys
 // to benefit from a split, sampling will eventually instruct a caller of Record
 // to carry out a split. When the split is initiated, it can obtain the suggested
@@ -60,8 +63,8 @@ func Init(lbs *Decider, intn func(n int) int, qpsThreshold func() float64) {
 	lbs.qpsThreshold = qpsThreshold
 }
 
-// Record notifies the Decider that 'n' operations are being carried out which
-// operate on the span returned by the supplied method. The closure will only
+// Record notifies the Decider that 'n' operations (or units of load) are being
+// carried out which operate on the span returned by the supplied method. The closure will only
 // be called when necessary, that is, when the Decider is considering a split
 // and is sampling key spans to determine a suitable split point.
 //
@@ -109,7 +112,7 @@ func (d *Decider) recordLocked(now time.Time, n int, span func() roachpb.Span) b
 	if d.mu.splitFinder != nil && n != 0 {
 		s := span()
 		if s.Key != nil {
-			d.mu.splitFinder.Record(span(), d.intn)
+			d.mu.splitFinder.Record(span(), d.intn, n)
 		}
 		if now.Sub(d.mu.lastSplitSuggestion) > minSplitSuggestionInterval && d.mu.splitFinder.Ready(now) && d.mu.splitFinder.Key() != nil {
 			d.mu.lastSplitSuggestion = now
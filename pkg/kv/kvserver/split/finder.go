@@ -32,10 +32,13 @@ import (
 //     on whether the span falls entirely to the left, to the right.
 //     If exactly on the key, increment neither.
 //   - If the span overlaps with the key, increment the contained counter.
+//   - Counters are incremented by the weight of the span, which is the
+//     load it represents (e.g. its number of requests or CPU time). The
+//     number of spans recorded against each sample is also tracked.
 //   - When a sample is replaced, discard its counters.
 //  - If a range is on for more than a threshold interval:
 //   - Examine sample for the smallest diff between left and right counters,
-//     excluding any whose counters are not sufficiently advanced;
+//     excluding any that have not recorded sufficiently many spans;
 //     If not less than some constant threshold, skip split.
 //   - Use the contained counters to give lower priority to potential split
 //     points that have more requests that span over it.
@@ -47,14 +50,15 @@ const (
 	// will record a range for, before being ready for a split.
 	RecordDurationThreshold    = 10 * time.Second // 10s
 	splitKeySampleSize         = 20               // size of split key sample
-	splitKeyMinCounter         = 100              // min aggregate counters before consideration
+	splitKeyMinCounter         = 100              // min recorded spans before consideration
 	splitKeyThreshold          = 0.25             // 25% difference between left/right counters
 	splitKeyContainedThreshold = 0.50             // too many spanning queries over split point
 )
 
 type sample struct {
 	key                    roachpb.Key
-	left, right, contained int
+	left, right, contained int // weighted by the load of the recorded spans
+	count                  int // number of recorded spans
 }
 
 // Finder is a structure that is used to determine the split point
@@ -79,8 +83,9 @@ func (f *Finder) Ready(nowTime time.Time) bool {
 }
 
 // Record informs the Finder about where the span lies with
-// regard to the keys in the samples.
-func (f *Finder) Record(span roachpb.Span, intNFn func(int) int) {
+// regard to the keys in the samples. The span's weight is the
+// load it represents.
+func (f *Finder) Record(span roachpb.Span, intNFn func(int) int, weight int) {
 	if f == nil {
 		return
 	}
@@ -93,8 +98,9 @@ func (f *Finder) Record(span roachpb.Span, intNFn func(int) int) {
 	} else if idx = intNFn(count); idx >= splitKeySampleSize {
 		// Increment all existing keys' counters.
 		for i := range f.samples {
+			f.samples[i].count++
 			if span.ProperlyContainsKey(f.samples[i].key) {
-				f.samples[i].contained++
+				f.samples[i].contained += weight
 			} else {
 				// If the split is chosen to be here and the key is on or to the left
 				// of the start key of the span, we know that the request the span represents
@@ -103,9 +109,9 @@ func (f *Finder) Record(span roachpb.Span, intNFn func(int) int) {
 				// (and given that it is not properly contained by the span) it must mean
 				// that the request the span represents would be on the left.
 				if comp := bytes.Compare(f.samples[i].key, span.Key); comp <= 0 {
-					f.samples[i].right++
+					f.samples[i].right += weight
 				} else if comp > 0 {
-					f.samples[i].left++
+					f.samples[i].left += weight
 				}
 			}
 		}
@@ -128,7 +134,7 @@ func (f *Finder) Key() roachpb.Key {
 	var bestIdx = -1
 	var bestScore float64 = 2
 	for i, s := range f.samples {
-		if s.left+s.right+s.contained < splitKeyMinCounter {
+		if s.count < splitKeyMinCounter {
 			continue
 		}
 		balanceScore := math.Abs(float64(s.left-s.right)) / float64(s.left+s.right)
@@ -152,6 +152,11 @@ func TestSplitFinderKey(t *testing.T) {
 	for i, test := range testCases {
 		finder := NewFinder(timeutil.Now())
 		finder.samples = test.reservoir
+		// The reservoirs above are recorded with unit weights.
+		for j := range finder.samples {
+			s := &finder.samples[j]
+			s.count = s.left + s.right + s.contained
+		}
 		if splitByLoadKey := finder.Key(); !bytes.Equal(splitByLoadKey, test.splitByLoadKey) {
 			t.Errorf(
 				"%d: expected splitByLoadKey: %v, but got splitByLoadKey: %v",
@@ -230,6 +235,9 @@ func TestSplitFinderRecorder(t *testing.T) {
 	}
 	expectedFullReservoir[0].left = 0
 	expectedFullReservoir[0].right = 1
+	for i := range expectedFullReservoir {
+		expectedFullReservoir[i].count = 1
+	}
 
 	// Test recording a spanning query.
 	spanningReservoir := replacementReservoir
@@ -240,6 +248,7 @@ func TestSplitFinderRecorder(t *testing.T) {
 	expectedSpanningReservoir := spanningReservoir
 	for i := 0; i < splitKeySampleSize; i++ {
 		expectedSpanningReservoir[i].contained++
+		expectedSpanningReservoir[i].count++
 	}
 
 	testCases := []struct {
@@ -263,7 +272,7 @@ func TestSplitFinderRecorder(t *testing.T) {
 		finder := NewFinder(timeutil.Now())
 		finder.samples = test.currReservoir
 		finder.count = test.currCount
-		finder.Record(test.recordSpan, test.intNFn)
+		finder.Record(test.recordSpan, test.intNFn, 1 /* weight */)
 		if !reflect.DeepEqual(finder.samples, test.expectedReservoir) {
 			t.Errorf(
 				"%d: expected reservoir: %v, but got reservoir: %v",
@@ -271,3 +280,48 @@ func TestSplitFinderRecorder(t *testing.T) {
 		}
 	}
 }
+
+// TestSplitFinderWeighted verifies that the split point found by the Finder
+// balances the weight of the recorded spans rather than their number.
+func TestSplitFinderWeighted(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const ReservoirKeyOffset = 1000
+	key := func(i int) roachpb.Key {
+		return keys.SystemSQLCodec.TablePrefix(uint32(ReservoirKeyOffset + i))
+	}
+	// getLargest never replaces a sample once the reservoir is full.
+	getLargest := func(n int) int { return n - 1 }
+
+	for _, tc := range []struct {
+		name     string
+		weighted bool
+		expKey   roachpb.Key
+	}{
+		// Two thirds of the spans are to the right of the samples between
+		// the first and the second span, which is too unbalanced to split.
+		{"unweighted", false, nil},
+		// Half of the load is to the left of the same samples, so the range
+		// is split at the first of them.
+		{"weighted", true, key(6)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			finder := NewFinder(timeutil.Now())
+			for i := 0; i < splitKeySampleSize; i++ {
+				finder.Record(roachpb.Span{Key: key(i)}, getLargest, 1 /* weight */)
+			}
+			for i := 0; i < splitKeyMinCounter; i++ {
+				heavyWeight := 1
+				if tc.weighted {
+					heavyWeight = 2
+				}
+				finder.Record(roachpb.Span{Key: key(5)}, getLargest, heavyWeight)
+				finder.Record(roachpb.Span{Key: key(15)}, getLargest, 1 /* weight */)
+				finder.Record(roachpb.Span{Key: key(17)}, getLargest, 1 /* weight */)
+			}
+			if splitKey := finder.Key(); !bytes.Equal(splitKey, tc.expKey) {
+				t.Errorf("expected split key %v, but got %v", tc.expKey, splitKey)
+			}
+		})
+	}
+}
@@ -220,11 +220,15 @@ func (sq *splitQueue) processAttempt(
 	if splitByLoadKey := r.loadBasedSplitter.MaybeSplitKey(now); splitByLoadKey != nil {
 		batchHandledQPS := r.QueriesPerSecond()
 		raftAppliedQPS := r.WritesPerSecond()
-		splitQPS := r.loadBasedSplitter.LastQPS(now)
+		splitLoad := r.loadBasedSplitter.LastQPS(now)
+		splitLoadDesc := fmt.Sprintf("%.2f splitQPS", splitLoad)
+		if r.splitByCPU() {
+			splitLoadDesc = fmt.Sprintf("%s/s splitCPU", time.Duration(splitLoad))
+		}
 		reason := fmt.Sprintf(
-			"load at key %s (%.2f splitQPS, %.2f batches/sec, %.2f raft mutations/sec)",
+			"load at key %s (%s, %.2f batches/sec, %.2f raft mutations/sec)",
 			splitByLoadKey,
-			splitQPS,
+			splitLoadDesc,
 			batchHandledQPS,
 			raftAppliedQPS,
 		)
@@ -2346,6 +2346,7 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 	var logicalBytes int64
 	var totalQueriesPerSecond float64
 	var totalWritesPerSecond float64
+	var totalCPUPerSecond float64
 	replicaCount := s.metrics.ReplicaCount.Value()
 	bytesPerReplica := make([]float64, 0, replicaCount)
 	writesPerReplica := make([]float64, 0, replicaCount)
@@ -2372,9 +2373,15 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 			totalWritesPerSecond += wps
 			writesPerReplica = append(writesPerReplica, wps)
 		}
+		var cpu float64
+		if avgCPU, dur := r.cpuStats.avgNanosPerSecond(); dur >= MinStatsDuration {
+			cpu = avgCPU
+			totalCPUPerSecond += avgCPU
+		}
 		rankingsAccumulator.addReplica(replicaWithStats{
 			repl: r,
 			qps:  qps,
+			cpu:  cpu,
 		})
 		return true
 	})
@@ -2383,6 +2390,7 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 	capacity.LogicalBytes = logicalBytes
 	capacity.QueriesPerSecond = totalQueriesPerSecond
 	capacity.WritesPerSecond = totalWritesPerSecond
+	capacity.CPUPerSecond = totalCPUPerSecond
 	capacity.BytesPerReplica = roachpb.PercentilesFromData(bytesPerReplica)
 	capacity.WritesPerReplica = roachpb.PercentilesFromData(writesPerReplica)
 	s.recordNewPerSecondStats(totalQueriesPerSecond, totalWritesPerSecond)
@@ -2488,6 +2496,7 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 		quiescentCount                int64
 		averageQueriesPerSecond       float64
 		averageWritesPerSecond        float64
+		averageWallNanosPerSecond     float64
 
 		rangeCount                int64
 		unavailableRangeCount     int64
@@ -2544,6 +2553,9 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 		if wps, dur := rep.writeStats.avgQPS(); dur >= MinStatsDuration {
 			averageWritesPerSecond += wps
 		}
+		if cpu, dur := rep.cpuStats.avgNanosPerSecond(); dur >= MinStatsDuration {
+			averageWallNanosPerSecond += cpu
+		}
 		mc, ok := rep.maxClosed(ctx)
 		if ok && (minMaxClosedTS.IsEmpty() || mc.Less(minMaxClosedTS)) {
 			minMaxClosedTS = mc
@@ -2559,6 +2571,7 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 	s.metrics.QuiescentCount.Update(quiescentCount)
 	s.metrics.AverageQueriesPerSecond.Update(averageQueriesPerSecond)
 	s.metrics.AverageWritesPerSecond.Update(averageWritesPerSecond)
+	s.metrics.AverageWallNanosPerSecond.Update(averageWallNanosPerSecond)
 	s.recordNewPerSecondStats(averageQueriesPerSecond, averageWritesPerSecond)
 
 	s.metrics.RangeCount.Update(rangeCount)
@@ -386,6 +386,7 @@ func (sp *StorePool) updateLocalStoreAfterRebalance(
 		detail.desc.Capacity.RangeCount++
 		detail.desc.Capacity.LogicalBytes += rangeUsageInfo.LogicalBytes
 		detail.desc.Capacity.WritesPerSecond += rangeUsageInfo.WritesPerSecond
+		detail.desc.Capacity.CPUPerSecond += rangeUsageInfo.CPUPerSecond
 	case roachpb.REMOVE_VOTER:
 		detail.desc.Capacity.RangeCount--
 		if detail.desc.Capacity.LogicalBytes <= rangeUsageInfo.LogicalBytes {
@@ -398,6 +399,11 @@ func (sp *StorePool) updateLocalStoreAfterRebalance(
 		} else {
 			detail.desc.Capacity.WritesPerSecond -= rangeUsageInfo.WritesPerSecond
 		}
+		if detail.desc.Capacity.CPUPerSecond <= rangeUsageInfo.CPUPerSecond {
+			detail.desc.Capacity.CPUPerSecond = 0
+		} else {
+			detail.desc.Capacity.CPUPerSecond -= rangeUsageInfo.CPUPerSecond
+		}
 	}
 	sp.detailsMu.storeDetails[storeID] = &detail
 }
@@ -582,6 +588,10 @@ type StoreList struct {
 	// candidateWritesPerSecond tracks writes-per-second stats for stores that are
 	// eligible to be rebalance targets.
 	candidateWritesPerSecond stat
+
+	// candidateCPUPerSecond tracks CPU time stats for stores that are eligible
+	// to be rebalance targets.
+	candidateCPUPerSecond stat
 }
 
 // Generates a new store list based on the passed in descriptors. It will
@@ -596,6 +606,7 @@ func makeStoreList(descriptors []roachpb.StoreDescriptor) StoreList {
 		sl.candidateLogicalBytes.update(float64(desc.Capacity.LogicalBytes))
 		sl.candidateQueriesPerSecond.update(desc.Capacity.QueriesPerSecond)
 		sl.candidateWritesPerSecond.update(desc.Capacity.WritesPerSecond)
+		sl.candidateCPUPerSecond.update(desc.Capacity.CPUPerSecond)
 	}
 	return sl
 }
@@ -603,21 +614,22 @@ func makeStoreList(descriptors []roachpb.StoreDescriptor) StoreList {
 func (sl StoreList) String() string {
 	var buf bytes.Buffer
 	fmt.Fprintf(&buf,
-		"  candidate: avg-ranges=%v avg-leases=%v avg-disk-usage=%v avg-queries-per-second=%v",
+		"  candidate: avg-ranges=%v avg-leases=%v avg-disk-usage=%v avg-queries-per-second=%v avg-cpu-per-second=%s",
 		sl.candidateRanges.mean,
 		sl.candidateLeases.mean,
 		humanizeutil.IBytes(int64(sl.candidateLogicalBytes.mean)),
-		sl.candidateQueriesPerSecond.mean)
+		sl.candidateQueriesPerSecond.mean,
+		time.Duration(sl.candidateCPUPerSecond.mean))
 	if len(sl.stores) > 0 {
 		fmt.Fprintf(&buf, "\n")
 	} else {
 		fmt.Fprintf(&buf, " <no candidates>")
 	}
 	for _, desc := range sl.stores {
-		fmt.Fprintf(&buf, "  %d: ranges=%d leases=%d disk-usage=%s queries-per-second=%.2f\n",
+		fmt.Fprintf(&buf, "  %d: ranges=%d leases=%d disk-usage=%s queries-per-second=%.2f cpu-per-second=%s\n",
 			desc.StoreID, desc.Capacity.RangeCount,
 			desc.Capacity.LeaseCount, humanizeutil.IBytes(desc.Capacity.LogicalBytes),
-			desc.Capacity.QueriesPerSecond)
+			desc.Capacity.QueriesPerSecond, time.Duration(desc.Capacity.CPUPerSecond))
 	}
 	return buf.String()
 }
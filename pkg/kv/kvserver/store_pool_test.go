@@ -470,6 +470,7 @@ func TestStorePoolUpdateLocalStore(t *testing.T) {
 				LogicalBytes:     30,
 				QueriesPerSecond: 100,
 				WritesPerSecond:  30,
+				CPUPerSecond:     30,
 			},
 		},
 		{
@@ -483,6 +484,7 @@ func TestStorePoolUpdateLocalStore(t *testing.T) {
 				LogicalBytes:     25,
 				QueriesPerSecond: 50,
 				WritesPerSecond:  25,
+				CPUPerSecond:     25,
 			},
 		},
 	}
@@ -502,6 +504,7 @@ func TestStorePoolUpdateLocalStore(t *testing.T) {
 	manual.Increment(int64(MinStatsDuration + time.Second))
 	replica.leaseholderStats = rs
 	replica.writeStats = rs
+	replica.cpuStats = rs
 
 	rangeUsageInfo := rangeUsageInfoForRepl(replica)
 
@@ -512,6 +515,7 @@ func TestStorePoolUpdateLocalStore(t *testing.T) {
 	}
 	QPS, _ := replica.leaseholderStats.avgQPS()
 	WPS, _ := replica.writeStats.avgQPS()
+	CPU, _ := replica.cpuStats.avgQPS()
 	if expectedRangeCount := int32(6); desc.Capacity.RangeCount != expectedRangeCount {
 		t.Errorf("expected RangeCount %d, but got %d", expectedRangeCount, desc.Capacity.RangeCount)
 	}
@@ -524,6 +528,9 @@ func TestStorePoolUpdateLocalStore(t *testing.T) {
 	if expectedWPS := 30 + WPS; desc.Capacity.WritesPerSecond != expectedWPS {
 		t.Errorf("expected WritesPerSecond %f, but got %f", expectedWPS, desc.Capacity.WritesPerSecond)
 	}
+	if expectedCPU := 30 + CPU; desc.Capacity.CPUPerSecond != expectedCPU {
+		t.Errorf("expected CPUPerSecond %f, but got %f", expectedCPU, desc.Capacity.CPUPerSecond)
+	}
 
 	sp.updateLocalStoreAfterRebalance(roachpb.StoreID(2), rangeUsageInfo, roachpb.REMOVE_VOTER)
 	desc, ok = sp.getStoreDescriptor(roachpb.StoreID(2))
@@ -542,6 +549,9 @@ func TestStorePoolUpdateLocalStore(t *testing.T) {
 	if expectedWPS := 25 - WPS; desc.Capacity.WritesPerSecond != expectedWPS {
 		t.Errorf("expected WritesPerSecond %f, but got %f", expectedWPS, desc.Capacity.WritesPerSecond)
 	}
+	if expectedCPU := 25 - CPU; desc.Capacity.CPUPerSecond != expectedCPU {
+		t.Errorf("expected CPUPerSecond %f, but got %f", expectedCPU, desc.Capacity.CPUPerSecond)
+	}
 
 	sp.updateLocalStoresAfterLeaseTransfer(roachpb.StoreID(1), roachpb.StoreID(2), rangeUsageInfo.QueriesPerSecond)
 	desc, ok = sp.getStoreDescriptor(roachpb.StoreID(1))
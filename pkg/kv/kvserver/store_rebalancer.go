@@ -12,6 +12,7 @@ package kvserver
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"math/rand"
 	"sort"
@@ -41,6 +42,11 @@ const (
 	// by less than this amount even if the amount is greater than the percentage
 	// threshold. This avoids too many lease transfers in lightly loaded clusters.
 	minQPSThresholdDifference = 100
+
+	// minCPUThresholdDifference is the analog of minQPSThresholdDifference for
+	// the CPU time spent per second on a store's replicas. It corresponds to a
+	// tenth of a core.
+	minCPUThresholdDifference = 100 * time.Millisecond
 )
 
 var (
@@ -76,7 +82,7 @@ func makeStoreRebalancerMetrics() StoreRebalancerMetrics {
 // If disabled, rebalancing is done purely based on replica count.
 var LoadBasedRebalancingMode = settings.RegisterPublicEnumSetting(
 	"kv.allocator.load_based_rebalancing",
-	"whether to rebalance based on the distribution of QPS across stores",
+	"whether to rebalance based on the distribution of load across stores",
 	"leases and replicas",
 	map[int64]string{
 		int64(LBRebalancingOff):               "off",
@@ -100,6 +106,34 @@ var qpsRebalanceThreshold = func() *settings.FloatSetting {
 	return s
 }()
 
+// cpuRebalanceThreshold is the analog of qpsRebalanceThreshold for the CPU
+// time spent per second on a store's replicas. CPU usage is less sensitive to
+// the mix of requests in a workload than QPS, so it can afford a tighter
+// threshold.
+var cpuRebalanceThreshold = func() *settings.FloatSetting {
+	s := settings.RegisterNonNegativeFloatSetting(
+		"kv.allocator.cpu_rebalance_threshold",
+		"minimum fraction away from the mean a store's CPU usage can be before it is considered overfull or underfull",
+		0.1,
+	)
+	s.SetVisibility(settings.Public)
+	return s
+}()
+
+// LoadBasedRebalancingObjective controls which dimension of load the store
+// rebalancer balances across stores when load based rebalancing is enabled.
+// It also determines what load based splitting measures.
+var LoadBasedRebalancingObjective = settings.RegisterPublicEnumSetting(
+	"kv.allocator.load_based_rebalancing.objective",
+	"what to balance across stores and split ranges on when doing load based "+
+		"rebalancing and splitting: qps (queries per second) or cpu (CPU time spent on replicas)",
+	"qps",
+	map[int64]string{
+		int64(LBRebalancingQueries): "qps",
+		int64(LBRebalancingCPU):     "cpu",
+	},
+)
+
 // LBRebalancingMode controls if and when we do store-level rebalancing
 // based on load.
 type LBRebalancingMode int64
@@ -109,13 +143,89 @@ const (
 	// based on load statistics.
 	LBRebalancingOff LBRebalancingMode = iota
 	// LBRebalancingLeasesOnly means that we rebalance leases based on
-	// store-level QPS imbalances.
+	// store-level load imbalances.
 	LBRebalancingLeasesOnly
 	// LBRebalancingLeasesAndReplicas means that we rebalance both leases and
-	// replicas based on store-level QPS imbalances.
+	// replicas based on store-level load imbalances.
 	LBRebalancingLeasesAndReplicas
 )
 
+// LBRebalancingObjective controls which dimension of load store-level
+// rebalancing and load-based splitting act on.
+type LBRebalancingObjective int64
+
+const (
+	// LBRebalancingQueries means that we balance the number of queries per
+	// second across stores.
+	LBRebalancingQueries LBRebalancingObjective = iota
+	// LBRebalancingCPU means that we balance the CPU time spent on replicas
+	// across stores. Replica CPU time is attributed as the time spent
+	// evaluating requests and applying raft commands.
+	LBRebalancingCPU
+)
+
+// storeLoad returns the store's load along the objective's dimension.
+func (o LBRebalancingObjective) storeLoad(c roachpb.StoreCapacity) float64 {
+	if o == LBRebalancingCPU {
+		return c.CPUPerSecond
+	}
+	return c.QueriesPerSecond
+}
+
+// adjustStoreLoad adds delta to the store's load along the objective's
+// dimension.
+func (o LBRebalancingObjective) adjustStoreLoad(c *roachpb.StoreCapacity, delta float64) {
+	if o == LBRebalancingCPU {
+		c.CPUPerSecond += delta
+	} else {
+		c.QueriesPerSecond += delta
+	}
+}
+
+// replicaLoad returns the replica's load along the objective's dimension.
+func (o LBRebalancingObjective) replicaLoad(r replicaWithStats) float64 {
+	if o == LBRebalancingCPU {
+		return r.cpu
+	}
+	return r.qps
+}
+
+// candidateLoad returns the load stats of the candidate stores in the list
+// along the objective's dimension.
+func (o LBRebalancingObjective) candidateLoad(sl StoreList) stat {
+	if o == LBRebalancingCPU {
+		return sl.candidateCPUPerSecond
+	}
+	return sl.candidateQueriesPerSecond
+}
+
+// rebalanceThreshold returns the fraction away from the mean a store's load
+// can be before it is considered overfull or underfull.
+func (o LBRebalancingObjective) rebalanceThreshold(sv *settings.Values) float64 {
+	if o == LBRebalancingCPU {
+		return cpuRebalanceThreshold.Get(sv)
+	}
+	return qpsRebalanceThreshold.Get(sv)
+}
+
+// minThresholdDifference returns the minimum difference from the mean load
+// that the store rebalancer should care about.
+func (o LBRebalancingObjective) minThresholdDifference() float64 {
+	if o == LBRebalancingCPU {
+		return float64(minCPUThresholdDifference)
+	}
+	return minQPSThresholdDifference
+}
+
+// format returns a human-readable representation of the given amount of
+// load along the objective's dimension.
+func (o LBRebalancingObjective) format(load float64) redact.SafeString {
+	if o == LBRebalancingCPU {
+		return redact.SafeString(fmt.Sprintf("%s/s cpu", time.Duration(load)))
+	}
+	return redact.SafeString(fmt.Sprintf("%.2f qps", load))
+}
+
 // StoreRebalancer is responsible for examining how the associated store's load
 // compares to the load on other stores in the cluster and transferring leases
 // or replicas away if the local store is overloaded.
@@ -196,23 +306,28 @@ func (sr *StoreRebalancer) Start(ctx context.Context, stopper *stop.Stopper) {
 			if mode == LBRebalancingOff {
 				continue
 			}
+			objective := LBRebalancingObjective(LoadBasedRebalancingObjective.Get(&sr.st.SV))
 
 			storeList, _, _ := sr.rq.allocator.storePool.getStoreList(storeFilterNone)
-			sr.rebalanceStore(ctx, mode, storeList)
+			sr.rebalanceStore(ctx, mode, objective, storeList)
 		}
 	})
 }
 
 func (sr *StoreRebalancer) rebalanceStore(
-	ctx context.Context, mode LBRebalancingMode, storeList StoreList,
+	ctx context.Context,
+	mode LBRebalancingMode,
+	objective LBRebalancingObjective,
+	storeList StoreList,
 ) {
-	qpsThresholdFraction := qpsRebalanceThreshold.Get(&sr.st.SV)
+	thresholdFraction := objective.rebalanceThreshold(&sr.st.SV)
+	candidateLoad := objective.candidateLoad(storeList)
 
-	// First check if we should transfer leases away to better balance QPS.
-	qpsMinThreshold := math.Min(storeList.candidateQueriesPerSecond.mean*(1-qpsThresholdFraction),
-		storeList.candidateQueriesPerSecond.mean-minQPSThresholdDifference)
-	qpsMaxThreshold := math.Max(storeList.candidateQueriesPerSecond.mean*(1+qpsThresholdFraction),
-		storeList.candidateQueriesPerSecond.mean+minQPSThresholdDifference)
+	// First check if we should transfer leases away to better balance load.
+	minThreshold := math.Min(candidateLoad.mean*(1-thresholdFraction),
+		candidateLoad.mean-objective.minThresholdDifference())
+	maxThreshold := math.Max(candidateLoad.mean*(1+thresholdFraction),
+		candidateLoad.mean+objective.minThresholdDifference())
 
 	var localDesc *roachpb.StoreDescriptor
 	for i := range storeList.stores {
@@ -225,9 +340,10 @@ func (sr *StoreRebalancer) rebalanceStore(
 		return
 	}
 
-	if !(localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold) {
-		log.VEventf(ctx, 1, "local QPS %.2f is below max threshold %.2f (mean=%.2f); no rebalancing needed",
-			localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold, storeList.candidateQueriesPerSecond.mean)
+	if !(objective.storeLoad(localDesc.Capacity) > maxThreshold) {
+		log.VEventf(ctx, 1, "local load %s is below max threshold %s (mean=%s); no rebalancing needed",
+			objective.format(objective.storeLoad(localDesc.Capacity)), objective.format(maxThreshold),
+			objective.format(candidateLoad.mean))
 		return
 	}
 
@@ -235,20 +351,22 @@ func (sr *StoreRebalancer) rebalanceStore(
 	storeMap := storeListToMap(storeList)
 
 	log.Infof(ctx,
-		"considering load-based lease transfers for s%d with %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-		localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+		"considering load-based lease transfers for s%d with %s (mean=%s, upperThreshold=%s)",
+		localDesc.StoreID, objective.format(objective.storeLoad(localDesc.Capacity)),
+		objective.format(candidateLoad.mean), objective.format(maxThreshold))
 
-	hottestRanges := sr.replRankings.topQPS()
-	for localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold {
+	hottestRanges := sr.replRankings.top(objective)
+	for objective.storeLoad(localDesc.Capacity) > maxThreshold {
 		replWithStats, target, considerForRebalance := sr.chooseLeaseToTransfer(
-			ctx, &hottestRanges, localDesc, storeList, storeMap, qpsMinThreshold, qpsMaxThreshold)
+			ctx, objective, &hottestRanges, localDesc, storeList, storeMap, minThreshold, maxThreshold)
 		replicasToMaybeRebalance = append(replicasToMaybeRebalance, considerForRebalance...)
 		if replWithStats.repl == nil {
 			break
 		}
 
-		log.VEventf(ctx, 1, "transferring r%d (%.2f qps) to s%d to better balance load",
-			replWithStats.repl.RangeID, replWithStats.qps, target.StoreID)
+		replLoad := objective.replicaLoad(replWithStats)
+		log.VEventf(ctx, 1, "transferring r%d (%s) to s%d to better balance load",
+			replWithStats.repl.RangeID, objective.format(replLoad), target.StoreID)
 		timeout := sr.rq.processTimeoutFunc(sr.st, replWithStats.repl)
 		if err := contextutil.RunWithTimeout(ctx, "transfer lease", timeout, func(ctx context.Context) error {
 			return sr.rq.transferLease(ctx, replWithStats.repl, target, replWithStats.qps)
@@ -262,53 +380,56 @@ func (sr *StoreRebalancer) rebalanceStore(
 		// additional transfers are needed we'll be making the decisions with more
 		// up-to-date info. The StorePool copies are updated by transferLease.
 		localDesc.Capacity.LeaseCount--
-		localDesc.Capacity.QueriesPerSecond -= replWithStats.qps
+		objective.adjustStoreLoad(&localDesc.Capacity, -replLoad)
 		if otherDesc := storeMap[target.StoreID]; otherDesc != nil {
 			otherDesc.Capacity.LeaseCount++
-			otherDesc.Capacity.QueriesPerSecond += replWithStats.qps
+			objective.adjustStoreLoad(&otherDesc.Capacity, replLoad)
 		}
 	}
 
-	if !(localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold) {
+	if !(objective.storeLoad(localDesc.Capacity) > maxThreshold) {
 		log.Infof(ctx,
-			"load-based lease transfers successfully brought s%d down to %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-			localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+			"load-based lease transfers successfully brought s%d down to %s (mean=%s, upperThreshold=%s)",
+			localDesc.StoreID, objective.format(objective.storeLoad(localDesc.Capacity)),
+			objective.format(candidateLoad.mean), objective.format(maxThreshold))
 		return
 	}
 
 	if mode != LBRebalancingLeasesAndReplicas {
 		log.Infof(ctx,
-			"ran out of leases worth transferring and qps (%.2f) is still above desired threshold (%.2f)",
-			localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+			"ran out of leases worth transferring and load (%s) is still above desired threshold (%s)",
+			objective.format(objective.storeLoad(localDesc.Capacity)), objective.format(maxThreshold))
 		return
 	}
 	log.Infof(ctx,
-		"ran out of leases worth transferring and qps (%.2f) is still above desired threshold (%.2f); considering load-based replica rebalances",
-		localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+		"ran out of leases worth transferring and load (%s) is still above desired threshold (%s); considering load-based replica rebalances",
+		objective.format(objective.storeLoad(localDesc.Capacity)), objective.format(maxThreshold))
 
 	// Re-combine replicasToMaybeRebalance with what remains of hottestRanges so
 	// that we'll reconsider them for replica rebalancing.
 	replicasToMaybeRebalance = append(replicasToMaybeRebalance, hottestRanges...)
 
-	for localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold {
+	for objective.storeLoad(localDesc.Capacity) > maxThreshold {
 		replWithStats, targets := sr.chooseReplicaToRebalance(
 			ctx,
+			objective,
 			&replicasToMaybeRebalance,
 			localDesc,
 			storeList,
 			storeMap,
-			qpsMinThreshold,
-			qpsMaxThreshold)
+			minThreshold,
+			maxThreshold)
 		if replWithStats.repl == nil {
 			log.Infof(ctx,
-				"ran out of replicas worth transferring and qps (%.2f) is still above desired threshold (%.2f); will check again soon",
-				localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+				"ran out of replicas worth transferring and load (%s) is still above desired threshold (%s); will check again soon",
+				objective.format(objective.storeLoad(localDesc.Capacity)), objective.format(maxThreshold))
 			return
 		}
 
+		replLoad := objective.replicaLoad(replWithStats)
 		descBeforeRebalance := replWithStats.repl.Desc()
-		log.VEventf(ctx, 1, "rebalancing r%d (%.2f qps) from %v to %v to better balance load",
-			replWithStats.repl.RangeID, replWithStats.qps, descBeforeRebalance.Replicas(), targets)
+		log.VEventf(ctx, 1, "rebalancing r%d (%s) from %v to %v to better balance load",
+			replWithStats.repl.RangeID, objective.format(replLoad), descBeforeRebalance.Replicas(), targets)
 		timeout := sr.rq.processTimeoutFunc(sr.st, replWithStats.repl)
 		if err := contextutil.RunWithTimeout(ctx, "relocate range", timeout, func(ctx context.Context) error {
 			return sr.rq.store.AdminRelocateRange(ctx, *descBeforeRebalance, targets)
@@ -332,33 +453,35 @@ func (sr *StoreRebalancer) rebalanceStore(
 			}
 		}
 		localDesc.Capacity.LeaseCount--
-		localDesc.Capacity.QueriesPerSecond -= replWithStats.qps
+		objective.adjustStoreLoad(&localDesc.Capacity, -replLoad)
 		for i := range targets {
 			if storeDesc := storeMap[targets[i].StoreID]; storeDesc != nil {
 				storeDesc.Capacity.RangeCount++
 				if i == 0 {
 					storeDesc.Capacity.LeaseCount++
-					storeDesc.Capacity.QueriesPerSecond += replWithStats.qps
+					objective.adjustStoreLoad(&storeDesc.Capacity, replLoad)
 				}
 			}
 		}
 	}
 
 	log.Infof(ctx,
-		"load-based replica transfers successfully brought s%d down to %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-		localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+		"load-based replica transfers successfully brought s%d down to %s (mean=%s, upperThreshold=%s)",
+		localDesc.StoreID, objective.format(objective.storeLoad(localDesc.Capacity)),
+		objective.format(candidateLoad.mean), objective.format(maxThreshold))
 }
 
 // TODO(a-robinson): Should we take the number of leases on each store into
 // account here or just continue to let that happen in allocator.go?
 func (sr *StoreRebalancer) chooseLeaseToTransfer(
 	ctx context.Context,
+	objective LBRebalancingObjective,
 	hottestRanges *[]replicaWithStats,
 	localDesc *roachpb.StoreDescriptor,
 	storeList StoreList,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
-	minQPS float64,
-	maxQPS float64,
+	minLoad float64,
+	maxLoad float64,
 ) (replicaWithStats, roachpb.ReplicaDescriptor, []replicaWithStats) {
 	var considerForRebalance []replicaWithStats
 	now := sr.rq.store.Clock().Now()
@@ -374,39 +497,42 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 			return replicaWithStats{}, roachpb.ReplicaDescriptor{}, considerForRebalance
 		}
 
-		if shouldNotMoveAway(ctx, replWithStats, localDesc, now, minQPS) {
+		if shouldNotMoveAway(ctx, objective, replWithStats, localDesc, now, minLoad) {
 			continue
 		}
 
-		// Don't bother moving leases whose QPS is below some small fraction of the
-		// store's QPS (unless the store has extra leases to spare anyway). It's
-		// just unnecessary churn with no benefit to move leases responsible for,
-		// for example, 1 qps on a store with 5000 qps.
-		const minQPSFraction = .001
-		if replWithStats.qps < localDesc.Capacity.QueriesPerSecond*minQPSFraction &&
+		// Don't bother moving leases whose load is below some small fraction of
+		// the store's load (unless the store has extra leases to spare anyway).
+		// It's just unnecessary churn with no benefit to move leases responsible
+		// for, for example, 1 qps on a store with 5000 qps.
+		const minLoadFraction = .001
+		replLoad := objective.replicaLoad(replWithStats)
+		storeLoad := objective.storeLoad(localDesc.Capacity)
+		if replLoad < storeLoad*minLoadFraction &&
 			float64(localDesc.Capacity.LeaseCount) <= storeList.candidateLeases.mean {
-			log.VEventf(ctx, 5, "r%d's %.2f qps is too little to matter relative to s%d's %.2f total qps",
-				replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, localDesc.Capacity.QueriesPerSecond)
+			log.VEventf(ctx, 5, "r%d's %s is too little to matter relative to s%d's %s total",
+				replWithStats.repl.RangeID, objective.format(replLoad), localDesc.StoreID,
+				objective.format(storeLoad))
 			continue
 		}
 
 		desc, zone := replWithStats.repl.DescAndZone()
-		log.VEventf(ctx, 3, "considering lease transfer for r%d with %.2f qps",
-			desc.RangeID, replWithStats.qps)
+		log.VEventf(ctx, 3, "considering lease transfer for r%d with %s",
+			desc.RangeID, objective.format(replLoad))
 
-		// Check all the other replicas in order of increasing qps. Learner replicas
-		// aren't allowed to become the leaseholder or raft leader, so only consider
-		// the `Voters` replicas.
+		// Check all the other replicas in order of increasing load. Learner
+		// replicas aren't allowed to become the leaseholder or raft leader, so
+		// only consider the `Voters` replicas.
 		candidates := desc.Replicas().DeepCopy().Voters()
 		sort.Slice(candidates, func(i, j int) bool {
-			var iQPS, jQPS float64
+			var iLoad, jLoad float64
 			if desc := storeMap[candidates[i].StoreID]; desc != nil {
-				iQPS = desc.Capacity.QueriesPerSecond
+				iLoad = objective.storeLoad(desc.Capacity)
 			}
 			if desc := storeMap[candidates[j].StoreID]; desc != nil {
-				jQPS = desc.Capacity.QueriesPerSecond
+				jLoad = objective.storeLoad(desc.Capacity)
 			}
-			return iQPS < jQPS
+			return iLoad < jLoad
 		})
 
 		var raftStatus *raft.Status
@@ -417,8 +543,8 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 				continue
 			}
 
-			meanQPS := storeList.candidateQueriesPerSecond.mean
-			if sr.shouldNotMoveTo(ctx, storeMap, replWithStats, candidate.StoreID, meanQPS, minQPS, maxQPS) {
+			meanLoad := objective.candidateLoad(storeList).mean
+			if sr.shouldNotMoveTo(ctx, objective, storeMap, replWithStats, candidate.StoreID, meanLoad, minLoad, maxLoad) {
 				continue
 			}
 
@@ -462,12 +588,13 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 
 func (sr *StoreRebalancer) chooseReplicaToRebalance(
 	ctx context.Context,
+	objective LBRebalancingObjective,
 	hottestRanges *[]replicaWithStats,
 	localDesc *roachpb.StoreDescriptor,
 	storeList StoreList,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
-	minQPS float64,
-	maxQPS float64,
+	minLoad float64,
+	maxLoad float64,
 ) (replicaWithStats, []roachpb.ReplicationTarget) {
 	now := sr.rq.store.Clock().Now()
 	for {
@@ -481,25 +608,28 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			return replicaWithStats{}, nil
 		}
 
-		if shouldNotMoveAway(ctx, replWithStats, localDesc, now, minQPS) {
+		if shouldNotMoveAway(ctx, objective, replWithStats, localDesc, now, minLoad) {
 			continue
 		}
 
-		// Don't bother moving ranges whose QPS is below some small fraction of the
-		// store's QPS (unless the store has extra ranges to spare anyway). It's
-		// just unnecessary churn with no benefit to move ranges responsible for,
-		// for example, 1 qps on a store with 5000 qps.
-		const minQPSFraction = .001
-		if replWithStats.qps < localDesc.Capacity.QueriesPerSecond*minQPSFraction &&
+		// Don't bother moving ranges whose load is below some small fraction of
+		// the store's load (unless the store has extra ranges to spare anyway).
+		// It's just unnecessary churn with no benefit to move ranges responsible
+		// for, for example, 1 qps on a store with 5000 qps.
+		const minLoadFraction = .001
+		replLoad := objective.replicaLoad(replWithStats)
+		storeLoad := objective.storeLoad(localDesc.Capacity)
+		if replLoad < storeLoad*minLoadFraction &&
 			float64(localDesc.Capacity.RangeCount) <= storeList.candidateRanges.mean {
-			log.VEventf(ctx, 5, "r%d's %.2f qps is too little to matter relative to s%d's %.2f total qps",
-				replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, localDesc.Capacity.QueriesPerSecond)
+			log.VEventf(ctx, 5, "r%d's %s is too little to matter relative to s%d's %s total",
+				replWithStats.repl.RangeID, objective.format(replLoad), localDesc.StoreID,
+				objective.format(storeLoad))
 			continue
 		}
 
 		desc, zone := replWithStats.repl.DescAndZone()
-		log.VEventf(ctx, 3, "considering replica rebalance for r%d with %.2f qps",
-			desc.RangeID, replWithStats.qps)
+		log.VEventf(ctx, 3, "considering replica rebalance for r%d with %s",
+			desc.RangeID, objective.format(replLoad))
 
 		clusterNodes := sr.rq.allocator.storePool.ClusterNodeCount()
 		desiredReplicas := GetNeededReplicas(*zone.NumReplicas, clusterNodes)
@@ -508,7 +638,7 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 		currentReplicas := desc.Replicas().All()
 
 		// Check the range's existing diversity score, since we want to ensure we
-		// don't hurt locality diversity just to improve QPS.
+		// don't hurt locality diversity just to improve load.
 		curDiversity := rangeDiversityScore(
 			sr.rq.allocator.storePool.getLocalitiesByStore(currentReplicas))
 
@@ -517,15 +647,16 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			if currentReplicas[i].StoreID == localDesc.StoreID {
 				continue
 			}
-			// Keep the replica in the range if we don't know its QPS or if its QPS
-			// is below the upper threshold. Punishing stores not in our store map
-			// could cause mass evictions if the storePool gets out of sync.
+			// Keep the replica in the range if we don't know its load or if its
+			// load is below the upper threshold. Punishing stores not in our store
+			// map could cause mass evictions if the storePool gets out of sync.
 			storeDesc, ok := storeMap[currentReplicas[i].StoreID]
-			if !ok || storeDesc.Capacity.QueriesPerSecond < maxQPS {
+			if !ok || objective.storeLoad(storeDesc.Capacity) < maxLoad {
 				if log.V(3) {
 					var reason redact.RedactableString
 					if ok {
-						reason = redact.Sprintf(" (qps %.2f vs max %.2f)", storeDesc.Capacity.QueriesPerSecond, maxQPS)
+						reason = redact.Sprintf(" (load %s vs max %s)",
+							objective.format(objective.storeLoad(storeDesc.Capacity)), objective.format(maxLoad))
 					}
 					log.VEventf(ctx, 3, "keeping r%d/%d on s%d%s", desc.RangeID, currentReplicas[i].ReplicaID, currentReplicas[i].StoreID, reason)
 				}
@@ -542,7 +673,8 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 
 		// Then pick out which new stores to add the remaining replicas to.
 		options := sr.rq.allocator.scorerOptions()
-		options.qpsRebalanceThreshold = qpsRebalanceThreshold.Get(&sr.st.SV)
+		options.loadObjective = objective
+		options.loadRebalanceThreshold = objective.rebalanceThreshold(&sr.st.SV)
 		for len(targets) < desiredReplicas {
 			// Use the preexisting AllocateTarget logic to ensure that considerations
 			// such as zone constraints, locality diversity, and full disk come
@@ -560,8 +692,8 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 				break
 			}
 
-			meanQPS := storeList.candidateQueriesPerSecond.mean
-			if sr.shouldNotMoveTo(ctx, storeMap, replWithStats, target.StoreID, meanQPS, minQPS, maxQPS) {
+			meanLoad := objective.candidateLoad(storeList).mean
+			if sr.shouldNotMoveTo(ctx, objective, storeMap, replWithStats, target.StoreID, meanLoad, minLoad, maxLoad) {
 				break
 			}
 
@@ -580,7 +712,7 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 		// TODO(a-robinson): Support more incremental improvements -- move what we
 		// can if it makes things better even if it isn't great. For example,
 		// moving one of the other existing replicas that's on a store with less
-		// qps than the max threshold but above the mean would help in certain
+		// load than the max threshold but above the mean would help in certain
 		// locality configurations.
 		if len(targets) < desiredReplicas {
 			log.VEventf(ctx, 3, "couldn't find enough rebalance targets for r%d (%d/%d)",
@@ -595,10 +727,10 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			continue
 		}
 
-		// Pick the replica with the least QPS to be leaseholder;
+		// Pick the replica with the least load to be leaseholder;
 		// RelocateRange transfers the lease to the first provided target.
 		newLeaseIdx := 0
-		newLeaseQPS := math.MaxFloat64
+		newLeaseLoad := math.MaxFloat64
 		var raftStatus *raft.Status
 		for i := 0; i < len(targets); i++ {
 			// Ensure we don't transfer the lease to an existing replica that is behind
@@ -613,9 +745,9 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			}
 
 			storeDesc, ok := storeMap[targets[i].StoreID]
-			if ok && storeDesc.Capacity.QueriesPerSecond < newLeaseQPS {
+			if ok && objective.storeLoad(storeDesc.Capacity) < newLeaseLoad {
 				newLeaseIdx = i
-				newLeaseQPS = storeDesc.Capacity.QueriesPerSecond
+				newLeaseLoad = objective.storeLoad(storeDesc.Capacity)
 			}
 		}
 		targets[0], targets[newLeaseIdx] = targets[newLeaseIdx], targets[0]
@@ -625,18 +757,21 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 
 func shouldNotMoveAway(
 	ctx context.Context,
+	objective LBRebalancingObjective,
 	replWithStats replicaWithStats,
 	localDesc *roachpb.StoreDescriptor,
 	now hlc.Timestamp,
-	minQPS float64,
+	minLoad float64,
 ) bool {
 	if !replWithStats.repl.OwnsValidLease(ctx, now) {
 		log.VEventf(ctx, 3, "store doesn't own the lease for r%d", replWithStats.repl.RangeID)
 		return true
 	}
-	if localDesc.Capacity.QueriesPerSecond-replWithStats.qps < minQPS {
-		log.VEventf(ctx, 3, "moving r%d's %.2f qps would bring s%d below the min threshold (%.2f)",
-			replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, minQPS)
+	replLoad := objective.replicaLoad(replWithStats)
+	if objective.storeLoad(localDesc.Capacity)-replLoad < minLoad {
+		log.VEventf(ctx, 3, "moving r%d's %s would bring s%d below the min threshold (%s)",
+			replWithStats.repl.RangeID, objective.format(replLoad), localDesc.StoreID,
+			objective.format(minLoad))
 		return true
 	}
 	return false
@@ -644,12 +779,13 @@ func shouldNotMoveAway(
 
 func (sr *StoreRebalancer) shouldNotMoveTo(
 	ctx context.Context,
+	objective LBRebalancingObjective,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
 	replWithStats replicaWithStats,
 	candidateStore roachpb.StoreID,
-	meanQPS float64,
-	minQPS float64,
-	maxQPS float64,
+	meanLoad float64,
+	minLoad float64,
+	maxLoad float64,
 ) bool {
 	storeDesc, ok := storeMap[candidateStore]
 	if !ok {
@@ -657,18 +793,22 @@ func (sr *StoreRebalancer) shouldNotMoveTo(
 		return true
 	}
 
-	newCandidateQPS := storeDesc.Capacity.QueriesPerSecond + replWithStats.qps
-	if storeDesc.Capacity.QueriesPerSecond < minQPS {
-		if newCandidateQPS > maxQPS {
+	replLoad := objective.replicaLoad(replWithStats)
+	candidateLoad := objective.storeLoad(storeDesc.Capacity)
+	newCandidateLoad := candidateLoad + replLoad
+	if candidateLoad < minLoad {
+		if newCandidateLoad > maxLoad {
 			log.VEventf(ctx, 3,
-				"r%d's %.2f qps would push s%d over the max threshold (%.2f) with %.2f qps afterwards",
-				replWithStats.repl.RangeID, replWithStats.qps, candidateStore, maxQPS, newCandidateQPS)
+				"r%d's %s would push s%d over the max threshold (%s) with %s afterwards",
+				replWithStats.repl.RangeID, objective.format(replLoad), candidateStore,
+				objective.format(maxLoad), objective.format(newCandidateLoad))
 			return true
 		}
-	} else if newCandidateQPS > meanQPS {
+	} else if newCandidateLoad > meanLoad {
 		log.VEventf(ctx, 3,
-			"r%d's %.2f qps would push s%d over the mean (%.2f) with %.2f qps afterwards",
-			replWithStats.repl.RangeID, replWithStats.qps, candidateStore, meanQPS, newCandidateQPS)
+			"r%d's %s would push s%d over the mean (%s) with %s afterwards",
+			replWithStats.repl.RangeID, objective.format(replLoad), candidateStore,
+			objective.format(meanLoad), objective.format(newCandidateLoad))
 		return true
 	}
 
@@ -15,6 +15,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -31,13 +32,15 @@ import (
 var (
 	// noLocalityStores specifies a set of stores where s5 is
 	// under-utilized in terms of QPS, s2-s4 are in the middle, and s1 is
-	// over-utilized.
+	// over-utilized. In terms of CPU, s2 is under-utilized, s3-s5 are in the
+	// middle, and s1 is over-utilized.
 	noLocalityStores = []*roachpb.StoreDescriptor{
 		{
 			StoreID: 1,
 			Node:    roachpb.NodeDescriptor{NodeID: 1},
 			Capacity: roachpb.StoreCapacity{
 				QueriesPerSecond: 1500,
+				CPUPerSecond:     float64(1500 * time.Millisecond),
 			},
 		},
 		{
@@ -45,6 +48,7 @@ var (
 			Node:    roachpb.NodeDescriptor{NodeID: 2},
 			Capacity: roachpb.StoreCapacity{
 				QueriesPerSecond: 1100,
+				CPUPerSecond:     float64(500 * time.Millisecond),
 			},
 		},
 		{
@@ -52,6 +56,7 @@ var (
 			Node:    roachpb.NodeDescriptor{NodeID: 3},
 			Capacity: roachpb.StoreCapacity{
 				QueriesPerSecond: 1000,
+				CPUPerSecond:     float64(1000 * time.Millisecond),
 			},
 		},
 		{
@@ -59,6 +64,7 @@ var (
 			Node:    roachpb.NodeDescriptor{NodeID: 4},
 			Capacity: roachpb.StoreCapacity{
 				QueriesPerSecond: 900,
+				CPUPerSecond:     float64(1100 * time.Millisecond),
 			},
 		},
 		{
@@ -66,6 +72,7 @@ var (
 			Node:    roachpb.NodeDescriptor{NodeID: 5},
 			Capacity: roachpb.StoreCapacity{
 				QueriesPerSecond: 500,
+				CPUPerSecond:     float64(900 * time.Millisecond),
 			},
 		},
 	}
@@ -75,6 +82,7 @@ type testRange struct {
 	// The first storeID in the list will be the leaseholder.
 	storeIDs []roachpb.StoreID
 	qps      float64
+	cpu      float64
 }
 
 func loadRanges(rr *replicaRankings, s *Store, ranges []testRange) {
@@ -99,9 +107,11 @@ func loadRanges(rr *replicaRankings, s *Store, ranges []testRange) {
 		repl.mu.state.Stats = &enginepb.MVCCStats{}
 		repl.leaseholderStats = newReplicaStats(s.Clock(), nil)
 		repl.writeStats = newReplicaStats(s.Clock(), nil)
+		repl.cpuStats = newReplicaStats(s.Clock(), nil)
 		acc.addReplica(replicaWithStats{
 			repl: repl,
 			qps:  r.qps,
+			cpu:  r.cpu,
 		})
 	}
 	rr.update(acc)
@@ -183,7 +193,7 @@ func TestChooseLeaseToTransfer(t *testing.T) {
 		loadRanges(rr, s, []testRange{{storeIDs: tc.storeIDs, qps: tc.qps}})
 		hottestRanges := rr.topQPS()
 		_, target, _ := sr.chooseLeaseToTransfer(
-			ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+			ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
 		if target.StoreID != tc.expectTarget {
 			t.Errorf("got target store %d for range with replicas %v and %f qps; want %d",
 				target.StoreID, tc.storeIDs, tc.qps, tc.expectTarget)
@@ -191,6 +201,79 @@ func TestChooseLeaseToTransfer(t *testing.T) {
 	}
 }
 
+func TestChooseLeaseToTransferByCPU(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	stopper, g, _, a, _ := createTestAllocator(10, false /* deterministic */)
+	defer stopper.Stop(context.Background())
+	gossiputil.NewStoreGossiper(g).GossipStores(noLocalityStores, t)
+	storeList, _, _ := a.storePool.getStoreList(storeFilterThrottled)
+	storeMap := storeListToMap(storeList)
+
+	const minCPU = float64(800 * time.Millisecond)
+	const maxCPU = float64(1200 * time.Millisecond)
+
+	localDesc := *noLocalityStores[0]
+	cfg := TestStoreConfig(nil)
+	s := createTestStoreWithoutStart(t, stopper, testStoreOpts{createSystemRanges: true}, &cfg)
+	s.Ident = &roachpb.StoreIdent{StoreID: localDesc.StoreID}
+	rq := newReplicateQueue(s, g, a)
+	rr := newReplicaRankings()
+
+	sr := NewStoreRebalancer(cfg.AmbientCtx, cfg.Settings, rq, rr)
+
+	// Rather than trying to populate every Replica with a real raft group in
+	// order to pass replicaIsBehind checks, fake out the function for getting
+	// raft status with one that always returns all replicas as up to date.
+	sr.getRaftStatusFn = func(r *Replica) *raft.Status {
+		status := &raft.Status{
+			Progress: make(map[uint64]tracker.Progress),
+		}
+		status.Lead = uint64(r.ReplicaID())
+		status.Commit = 1
+		for _, replica := range r.Desc().InternalReplicas {
+			status.Progress[uint64(replica.ReplicaID)] = tracker.Progress{
+				Match: 1,
+				State: tracker.StateReplicate,
+			}
+		}
+		return status
+	}
+
+	// The stores are ordered differently by CPU than by QPS, so the targets
+	// chosen here differ from those in TestChooseLeaseToTransfer.
+	testCases := []struct {
+		storeIDs     []roachpb.StoreID
+		cpu          time.Duration
+		expectTarget roachpb.StoreID
+	}{
+		{[]roachpb.StoreID{1, 2}, 100 * time.Millisecond, 2},
+		{[]roachpb.StoreID{1, 3}, 100 * time.Millisecond, 0},
+		{[]roachpb.StoreID{1, 4}, 100 * time.Millisecond, 0},
+		{[]roachpb.StoreID{1, 5}, 50 * time.Millisecond, 5},
+		{[]roachpb.StoreID{1, 5}, 200 * time.Millisecond, 0},
+		{[]roachpb.StoreID{1, 2}, 600 * time.Millisecond, 2},
+		{[]roachpb.StoreID{1, 2}, 800 * time.Millisecond, 0},
+		{[]roachpb.StoreID{5, 1}, 100 * time.Millisecond, 0},
+	}
+
+	for _, tc := range testCases {
+		loadRanges(rr, s, []testRange{{storeIDs: tc.storeIDs, cpu: float64(tc.cpu)}})
+		hottestRanges := rr.topCPU()
+		_, target, _ := sr.chooseLeaseToTransfer(
+			ctx, LBRebalancingCPU, &hottestRanges, &localDesc, storeList, storeMap, minCPU, maxCPU)
+		if target.StoreID != tc.expectTarget {
+			t.Errorf("got target store %d for range with replicas %v and %s cpu; want %d",
+				target.StoreID, tc.storeIDs, tc.cpu, tc.expectTarget)
+		}
+	}
+}
+
 func TestChooseReplicaToRebalance(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -295,7 +378,7 @@ func TestChooseReplicaToRebalance(t *testing.T) {
 			loadRanges(rr, s, []testRange{{storeIDs: tc.storeIDs, qps: tc.qps}})
 			hottestRanges := rr.topQPS()
 			_, targets := sr.chooseReplicaToRebalance(
-				ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+				ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
 
 			if len(targets) != len(tc.expectTargets) {
 				t.Fatalf("chooseReplicaToRebalance(existing=%v, qps=%f) got %v; want %v",
@@ -380,7 +463,7 @@ func TestNoLeaseTransferToBehindReplicas(t *testing.T) {
 	}
 
 	_, target, _ := sr.chooseLeaseToTransfer(
-		ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+		ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
 	expectTarget := roachpb.StoreID(4)
 	if target.StoreID != expectTarget {
 		t.Errorf("got target store s%d for range with RaftStatus %v; want s%d",
@@ -395,7 +478,7 @@ func TestNoLeaseTransferToBehindReplicas(t *testing.T) {
 	repl = hottestRanges[0].repl
 
 	_, targets := sr.chooseReplicaToRebalance(
-		ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+		ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
 	expectTargets := []roachpb.ReplicationTarget{
 		{NodeID: 4, StoreID: 4}, {NodeID: 5, StoreID: 5}, {NodeID: 3, StoreID: 3},
 	}
@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
 )
 
 // Send fetches a range based on the header's replica, assembles method, args &
@@ -74,6 +75,14 @@ func (s *Store) Send(
 		}
 	}
 
+	// Bounded staleness batches arrive without a timestamp. Negotiate one with
+	// the local replica before the batch is assigned the current time.
+	if ba.BoundedStaleness != nil {
+		if pErr := s.negotiateBoundedStalenessTimestamp(ctx, &ba); pErr != nil {
+			return nil, pErr
+		}
+	}
+
 	if err := ba.SetActiveTimestamp(s.Clock().Now); err != nil {
 		return nil, roachpb.NewError(err)
 	}
@@ -260,3 +269,51 @@ func (s *Store) Send(
 	}
 	return nil, pErr
 }
+
+// negotiateBoundedStalenessTimestamp validates a bounded staleness batch and
+// assigns it the timestamp at which the local replica of its range is able to
+// serve it without blocking. See Replica.negotiateBoundedStalenessTimestamp.
+func (s *Store) negotiateBoundedStalenessTimestamp(
+	ctx context.Context, ba *roachpb.BatchRequest,
+) *roachpb.Error {
+	if err := checkBoundedStalenessBatch(ba); err != nil {
+		return roachpb.NewError(err)
+	}
+	repl, err := s.GetReplica(ba.RangeID)
+	if err != nil {
+		return roachpb.NewError(err)
+	}
+	if !repl.IsInitialized() {
+		// The batch will be rejected with a NotLeaseHolderError by Send.
+		return nil
+	}
+	ts, pErr := repl.negotiateBoundedStalenessTimestamp(ctx, ba)
+	if pErr != nil {
+		return pErr
+	}
+	ba.Timestamp = ts
+	return nil
+}
+
+// checkBoundedStalenessBatch verifies that a batch with a bounded staleness
+// header is a non-transactional, consistent, read-only batch whose timestamp
+// is left to be negotiated by the server.
+func checkBoundedStalenessBatch(ba *roachpb.BatchRequest) error {
+	bs := ba.BoundedStaleness
+	switch {
+	case ba.Txn != nil:
+		return errors.Errorf("bounded staleness header passed with transactional batch: %s", ba)
+	case !ba.Timestamp.IsEmpty():
+		return errors.Errorf("bounded staleness header passed with batch timestamp: %s", ba)
+	case !ba.IsReadOnly():
+		return errors.Errorf("bounded staleness header passed with non-read-only batch: %s", ba)
+	case ba.ReadConsistency != roachpb.CONSISTENT:
+		return errors.Errorf("bounded staleness header passed with %s batch: %s", ba.ReadConsistency, ba)
+	case bs.MinTimestampBound.IsEmpty():
+		return errors.Errorf("bounded staleness header missing minimum timestamp bound: %s", ba)
+	case !bs.MaxTimestampBound.IsEmpty() && bs.MaxTimestampBound.LessEq(bs.MinTimestampBound):
+		return errors.Errorf("bounded staleness maximum timestamp bound %s not above "+
+			"minimum timestamp bound %s", bs.MaxTimestampBound, bs.MinTimestampBound)
+	}
+	return nil
+}
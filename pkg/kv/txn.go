@@ -17,6 +17,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -41,6 +42,12 @@ type Txn struct {
 	// It will be attached to all requests sent through this transaction.
 	gatewayNodeID roachpb.NodeID
 
+	// admissionHeader is used for admission control for work done in this
+	// transaction. Only certain paths initialize this properly, and the
+	// remaining just use the zero value. The set of code paths that initialize
+	// this are expected to expand over time.
+	admissionHeader roachpb.AdmissionHeader
+
 	// The following fields are not safe for concurrent modification.
 	// They should be set before operating on the transaction.
 
@@ -72,6 +79,11 @@ type Txn struct {
 		// The txn has to be committed by this deadline. A nil value indicates no
 		// deadline.
 		deadline *hlc.Timestamp
+
+		// boundedStaleness, if set, is the bounded staleness header with which
+		// the first read-only batch sent through the txn negotiates the txn's
+		// timestamp. See SetBoundedStaleness.
+		boundedStaleness *roachpb.BoundedStalenessHeader
 	}
 }
 
@@ -110,9 +122,30 @@ func NewTxn(ctx context.Context, db *DB, gatewayNodeID roachpb.NodeID) *Txn {
 	return NewTxnFromProto(ctx, db, gatewayNodeID, now, RootTxn, &kvTxn)
 }
 
-// NewTxnWithSteppingEnabled is like NewTxn but suitable for use by SQL.
-func NewTxnWithSteppingEnabled(ctx context.Context, db *DB, gatewayNodeID roachpb.NodeID) *Txn {
+// NewTxnWithAdmissionControl creates a new transaction with the specified
+// admission control source and priority. See NewTxn() for details.
+func NewTxnWithAdmissionControl(
+	ctx context.Context,
+	db *DB,
+	gatewayNodeID roachpb.NodeID,
+	source roachpb.AdmissionHeader_Source,
+	priority admission.WorkPriority,
+) *Txn {
 	txn := NewTxn(ctx, db, gatewayNodeID)
+	txn.admissionHeader = roachpb.AdmissionHeader{
+		CreateTime: db.clock.PhysicalNow(),
+		Priority:   int32(priority),
+		Source:     source,
+	}
+	return txn
+}
+
+// NewTxnWithSteppingEnabled is like NewTxn but suitable for use by SQL. Note
+// that this initializes Txn.admissionHeader to specify that the source is
+// FROM_SQL.
+func NewTxnWithSteppingEnabled(ctx context.Context, db *DB, gatewayNodeID roachpb.NodeID) *Txn {
+	txn := NewTxnWithAdmissionControl(ctx, db, gatewayNodeID,
+		roachpb.AdmissionHeader_FROM_SQL, admission.NormalPri)
 	_ = txn.ConfigureStepping(ctx, SteppingEnabled)
 	return txn
 }
@@ -165,6 +198,12 @@ func (txn *Txn) DB() *DB {
 	return txn.db
 }
 
+// AdmissionHeader returns the admission header for work done in the context
+// of this transaction.
+func (txn *Txn) AdmissionHeader() roachpb.AdmissionHeader {
+	return txn.admissionHeader
+}
+
 // Sender returns a transaction's TxnSender.
 func (txn *Txn) Sender() TxnSender {
 	txn.mu.Lock()
@@ -911,11 +950,22 @@ func (txn *Txn) Send(
 	if txn.gatewayNodeID != 0 {
 		ba.Header.GatewayNodeID = txn.gatewayNodeID
 	}
+	// Requests that have not specified an admission header inherit the one of
+	// the transaction.
+	if ba.AdmissionHeader.CreateTime == 0 {
+		ba.AdmissionHeader = txn.admissionHeader
+	}
 
 	txn.mu.Lock()
 	requestTxnID := txn.mu.ID
 	sender := txn.mu.sender
+	boundedStaleness := txn.mu.boundedStaleness
+	txn.mu.boundedStaleness = nil
 	txn.mu.Unlock()
+	if boundedStaleness != nil && ba.IsReadOnly() {
+		ba.BoundedStaleness = boundedStaleness
+		return txn.NegotiateAndSend(ctx, ba)
+	}
 	br, pErr := txn.db.sendUsingSender(ctx, ba, sender)
 	if pErr == nil {
 		return br, nil
@@ -939,6 +989,104 @@ func (txn *Txn) Send(
 	return br, pErr
 }
 
+// NegotiateAndSend is a specialized version of Send that is capable of
+// orchestrating a bounded staleness read through the transaction, given a
+// read-only BatchRequest with a bounded staleness header. The batch is sent
+// outside of the transaction, which lets the range that serves it negotiate
+// the freshest timestamp within the header's bounds at which it can be
+// evaluated without blocking. The transaction's timestamp is then fixed to the
+// negotiated timestamp, so that all subsequent reads performed by the
+// transaction observe the same snapshot.
+//
+// The method must be called before any other operation is performed with the
+// transaction, and the batch must be addressed to a single range.
+func (txn *Txn) NegotiateAndSend(
+	ctx context.Context, ba roachpb.BatchRequest,
+) (*roachpb.BatchResponse, *roachpb.Error) {
+	if err := txn.checkNegotiateAndSendPreconditions(ctx, ba); err != nil {
+		return nil, roachpb.NewError(err)
+	}
+	if deadline := txn.deadline(); deadline != nil {
+		// The transaction must not read above its deadline.
+		bs := *ba.BoundedStaleness
+		if bs.MaxTimestampBound.IsEmpty() || deadline.Less(bs.MaxTimestampBound) {
+			bs.MaxTimestampBound = *deadline
+		}
+		ba.BoundedStaleness = &bs
+	}
+	if txn.gatewayNodeID != 0 {
+		ba.Header.GatewayNodeID = txn.gatewayNodeID
+	}
+	if ba.AdmissionHeader.CreateTime == 0 {
+		ba.AdmissionHeader = txn.admissionHeader
+	}
+
+	br, pErr := txn.db.sendUsingSender(ctx, ba, txn.db.NonTransactionalSender())
+	if pErr != nil {
+		return nil, pErr
+	}
+	if br.Timestamp.IsEmpty() {
+		return nil, roachpb.NewError(errors.AssertionFailedf(
+			"bounded staleness read returned empty timestamp"))
+	}
+	txn.SetFixedTimestamp(ctx, br.Timestamp)
+	return br, nil
+}
+
+// checkNegotiateAndSendPreconditions checks that the transaction and the batch
+// are in a state in which NegotiateAndSend can be used.
+func (txn *Txn) checkNegotiateAndSendPreconditions(
+	ctx context.Context, ba roachpb.BatchRequest,
+) error {
+	if txn.typ != RootTxn {
+		return errors.WithContextTags(
+			errors.AssertionFailedf("NegotiateAndSend() called on leaf txn"), ctx)
+	}
+	bs := ba.BoundedStaleness
+	switch {
+	case bs == nil:
+		return errors.AssertionFailedf("bounded staleness header missing")
+	case bs.MinTimestampBound.IsEmpty():
+		return errors.AssertionFailedf("bounded staleness minimum timestamp bound missing")
+	case ba.Txn != nil:
+		return errors.AssertionFailedf("bounded staleness batch must not set txn")
+	case !ba.Timestamp.IsEmpty():
+		return errors.AssertionFailedf("bounded staleness batch must not set timestamp")
+	case !ba.IsReadOnly():
+		return errors.AssertionFailedf("bounded staleness batch must be read-only")
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	if txn.mu.sender.Active() || txn.mu.sender.CommitTimestampFixed() {
+		return errors.AssertionFailedf("bounded staleness read performed with active txn")
+	}
+	return nil
+}
+
+// SetBoundedStaleness configures the transaction to perform a bounded
+// staleness read. Instead of running at a timestamp chosen upfront, the
+// transaction's timestamp is negotiated by the first read-only batch sent
+// through it using the provided header, as if the batch had been sent with
+// NegotiateAndSend. That batch must be addressed to a single range.
+//
+// The method must be called before any operation is performed with the
+// transaction.
+func (txn *Txn) SetBoundedStaleness(
+	ctx context.Context, bs roachpb.BoundedStalenessHeader,
+) error {
+	if txn.typ != RootTxn {
+		return errors.WithContextTags(
+			errors.AssertionFailedf("SetBoundedStaleness() called on leaf txn"), ctx)
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	if txn.mu.sender.Active() || txn.mu.sender.CommitTimestampFixed() {
+		return errors.AssertionFailedf("SetBoundedStaleness() called on active txn")
+	}
+	txn.mu.boundedStaleness = &bs
+	return nil
+}
+
 func (txn *Txn) handleErrIfRetryableLocked(ctx context.Context, err error) {
 	var retryErr *roachpb.TransactionRetryWithProtoRefreshError
 	if !errors.As(err, &retryErr) {
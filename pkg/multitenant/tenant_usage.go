@@ -0,0 +1,88 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package multitenant contains the interfaces through which tenant resource
+// usage is accounted for and controlled. The implementations live in the
+// tenantcostserver (host cluster side) and tenantcostclient (tenant SQL pod
+// side) packages.
+package multitenant
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// TenantUsageServer is an interface through which tenant usage is reported and
+// controlled. It runs on the host cluster, where the global per-tenant budget
+// is kept in the system.tenant_usage table.
+type TenantUsageServer interface {
+	// TokenBucketRequest implements the TokenBucket API of the roachpb.Internal
+	// service. It is used by tenant SQL pods to report consumption and to
+	// request Request Units.
+	TokenBucketRequest(
+		ctx context.Context, tenantID roachpb.TenantID, in *roachpb.TokenBucketRequest,
+	) *roachpb.TokenBucketResponse
+
+	// ReconfigureTokenBucket updates a tenant's token bucket settings. The
+	// update happens in the provided transaction.
+	ReconfigureTokenBucket(
+		ctx context.Context,
+		txn *kv.Txn,
+		tenantID roachpb.TenantID,
+		availableRU float64,
+		refillRate float64,
+		maxBurstRU float64,
+	) error
+
+	// Metrics returns the top-level metrics.
+	Metrics() metric.Struct
+}
+
+// TenantSideCostController is an interface through which tenant code reports
+// and throttles resource usage. Its implementation lives in the
+// tenantcostclient package.
+type TenantSideCostController interface {
+	// Start launches the background work of the controller, which periodically
+	// reports consumption to the host cluster and requests more Request Units.
+	// cpuSecsFn returns the cumulative CPU usage of the SQL pod, in seconds.
+	Start(ctx context.Context, stopper *stop.Stopper, cpuSecsFn CPUSecsFn) error
+
+	// Metrics returns the top-level metrics.
+	Metrics() metric.Struct
+
+	TenantSideKVInterceptor
+}
+
+// CPUSecsFn is a function which returns the cumulative CPU usage of the SQL
+// pod, in seconds.
+type CPUSecsFn func(ctx context.Context) float64
+
+// TenantSideKVInterceptor intercepts KV requests and responses, accounting
+// for resource usage and potentially throttling requests.
+//
+// The TenantSideKVInterceptor is installed in the DistSender.
+type TenantSideKVInterceptor interface {
+	// OnRequestWait accounts for portion of the cost that can be determined
+	// upfront. It can block to delay the request as needed, depending on the
+	// current allowed rate of resource usage.
+	OnRequestWait(ctx context.Context, info tenantcostmodel.RequestInfo) error
+
+	// OnResponse accounts for the portion of the cost that can only be
+	// determined after-the-fact. It does not block, but it can push the rate
+	// limiting into the "debt", causing future requests to be blocked.
+	OnResponse(
+		ctx context.Context, req tenantcostmodel.RequestInfo, resp tenantcostmodel.ResponseInfo,
+	)
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tenantcostclient
+
+import "github.com/cockroachdb/cockroach/pkg/util/metric"
+
+// Metrics is a metric.Struct for the tenant side cost controller.
+type Metrics struct {
+	TotalRU                   *metric.GaugeFloat64
+	AvailableRU               *metric.GaugeFloat64
+	CurrentBlocked            *metric.Gauge
+	BlockedRequests           *metric.Counter
+	FailedTokenBucketRequests *metric.Counter
+}
+
+var _ metric.Struct = (*Metrics)(nil)
+
+// MetricStruct indicates that Metrics is a metric.Struct
+func (m *Metrics) MetricStruct() {}
+
+var (
+	metaTotalRU = metric.Metadata{
+		Name:        "tenant.sql_usage.request_units",
+		Help:        "Total RU consumption of this SQL pod",
+		Measurement: "Request Units",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaAvailableRU = metric.Metadata{
+		Name:        "tenant.cost_client.available_request_units",
+		Help:        "Number of Request Units available in the local token bucket",
+		Measurement: "Request Units",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaCurrentBlocked = metric.Metadata{
+		Name:        "tenant.cost_client.current_blocked",
+		Help:        "Number of KV requests currently blocked because the tenant exhausted its Request Units",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaBlockedRequests = metric.Metadata{
+		Name:        "tenant.cost_client.blocked_requests",
+		Help:        "Number of KV requests that were blocked because the tenant exhausted its Request Units",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaFailedTokenBucketRequests = metric.Metadata{
+		Name:        "tenant.cost_client.failed_token_bucket_requests",
+		Help:        "Number of token bucket requests to the host cluster which failed",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+)
+
+func (m *Metrics) init() {
+	*m = Metrics{
+		TotalRU:                   metric.NewGaugeFloat64(metaTotalRU),
+		AvailableRU:               metric.NewGaugeFloat64(metaAvailableRU),
+		CurrentBlocked:            metric.NewGauge(metaCurrentBlocked),
+		BlockedRequests:           metric.NewCounter(metaBlockedRequests),
+		FailedTokenBucketRequests: metric.NewCounter(metaFailedTokenBucketRequests),
+	}
+}
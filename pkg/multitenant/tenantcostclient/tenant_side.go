@@ -0,0 +1,387 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package tenantcostclient contains the tenant side of the tenant cost control
+// machinery: it accounts for the resources used by a SQL pod in Request Units,
+// reports the consumption to the host cluster and throttles KV requests when
+// the tenant has exhausted its budget.
+package tenantcostclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvtenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+const (
+	// mainLoopUpdateInterval is the period at which the SQL pod reports its
+	// consumption to the host cluster, even if it doesn't need more Request
+	// Units.
+	mainLoopUpdateInterval = 10 * time.Second
+
+	// initialRUs is the number of Request Units that the SQL pod can use when
+	// it starts, before it receives its first grant.
+	initialRUs = 1000
+
+	// bufferRUs is the target number of Request Units that the SQL pod keeps
+	// in its local bucket. When the local bucket falls below half of this
+	// value, more Request Units are requested from the host cluster.
+	bufferRUs = 5000
+
+	// maxWaitDuration is the longest a request waits before re-checking the
+	// local bucket, when the bucket is not being refilled at a known rate.
+	maxWaitDuration = time.Second
+)
+
+// tenantSideCostController implements multitenant.TenantSideCostController.
+type tenantSideCostController struct {
+	timeSource timeutil.TimeSource
+	settings   *cluster.Settings
+	tenantID   roachpb.TenantID
+	instanceID base.SQLInstanceID
+	provider   kvtenant.TokenBucketProvider
+	metrics    Metrics
+
+	// lowRUNotifyChan is used to signal the main loop that the local bucket
+	// is running low and more Request Units should be requested.
+	lowRUNotifyChan chan struct{}
+
+	// lastCPUSecs is the cumulative CPU usage of the SQL pod at the time of the
+	// last main loop iteration. Only accessed by the main loop.
+	lastCPUSecs float64
+
+	// lastRequestFailed is set when the last token bucket request failed. Until
+	// the next periodic update, running low on Request Units doesn't trigger a
+	// new request. Only accessed by the main loop.
+	lastRequestFailed bool
+
+	mu struct {
+		syncutil.Mutex
+
+		costCfg tenantcostmodel.Config
+
+		// availableRU is the number of Request Units in the local bucket. It can
+		// be negative, in which case requests are blocked until the debt is
+		// paid.
+		availableRU float64
+		// trickleRate is the rate (in RU/s) at which Request Units are added to
+		// the local bucket, until trickleDeadline. It is non-zero when the last
+		// grant had to be trickled because the tenant's global bucket was
+		// depleted, or when the last token bucket request failed.
+		trickleRate     float64
+		trickleDeadline time.Time
+		lastUpdate      time.Time
+
+		// refillCh is closed (and replaced) whenever a grant is received, to
+		// wake up blocked requests.
+		refillCh chan struct{}
+
+		// consumption is the consumption accumulated since the last report.
+		consumption roachpb.TenantConsumption
+	}
+}
+
+var _ multitenant.TenantSideCostController = (*tenantSideCostController)(nil)
+
+// NewTenantSideCostController creates an object which implements the
+// multitenant.TenantSideCostController interface.
+func NewTenantSideCostController(
+	st *cluster.Settings,
+	tenantID roachpb.TenantID,
+	instanceID base.SQLInstanceID,
+	provider kvtenant.TokenBucketProvider,
+	timeSource timeutil.TimeSource,
+) multitenant.TenantSideCostController {
+	c := &tenantSideCostController{
+		timeSource:      timeSource,
+		settings:        st,
+		tenantID:        tenantID,
+		instanceID:      instanceID,
+		provider:        provider,
+		lowRUNotifyChan: make(chan struct{}, 1),
+	}
+	c.metrics.init()
+	c.mu.costCfg = tenantcostmodel.ConfigFromSettings(&st.SV)
+	c.mu.availableRU = initialRUs
+	c.mu.lastUpdate = timeSource.Now()
+	c.mu.refillCh = make(chan struct{})
+	return c
+}
+
+// Metrics is part of multitenant.TenantSideCostController.
+func (c *tenantSideCostController) Metrics() metric.Struct {
+	return &c.metrics
+}
+
+// Start is part of multitenant.TenantSideCostController.
+func (c *tenantSideCostController) Start(
+	ctx context.Context, stopper *stop.Stopper, cpuSecsFn multitenant.CPUSecsFn,
+) error {
+	for _, setOnChange := range tenantcostmodel.SetOnChangeFuncs {
+		setOnChange(&c.settings.SV, func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.mu.costCfg = tenantcostmodel.ConfigFromSettings(&c.settings.SV)
+		})
+	}
+	c.lastCPUSecs = cpuSecsFn(ctx)
+	return stopper.RunAsyncTask(ctx, "cost-controller", func(ctx context.Context) {
+		ctx, cancel := stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		c.mainLoop(ctx, stopper, cpuSecsFn)
+	})
+}
+
+func (c *tenantSideCostController) mainLoop(
+	ctx context.Context, stopper *stop.Stopper, cpuSecsFn multitenant.CPUSecsFn,
+) {
+	timer := c.timeSource.NewTimer()
+	defer timer.Stop()
+	timer.Reset(mainLoopUpdateInterval)
+	for {
+		select {
+		case <-timer.Ch():
+			timer.MarkRead()
+			c.updateCPU(ctx, cpuSecsFn)
+			c.sendTokenBucketRequest(ctx)
+			timer.Reset(mainLoopUpdateInterval)
+
+		case <-c.lowRUNotifyChan:
+			if !c.lastRequestFailed {
+				c.sendTokenBucketRequest(ctx)
+			}
+
+		case <-stopper.ShouldQuiesce():
+			return
+		}
+	}
+}
+
+// updateCPU charges the CPU used by the SQL pod since the last call.
+func (c *tenantSideCostController) updateCPU(ctx context.Context, cpuSecsFn multitenant.CPUSecsFn) {
+	cpuSecs := cpuSecsFn(ctx)
+	delta := cpuSecs - c.lastCPUSecs
+	c.lastCPUSecs = cpuSecs
+	if delta <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ru := c.mu.costCfg.PodCPUCost(delta)
+	c.mu.consumption.SQLPodsCPUSeconds += delta
+	c.chargeLocked(float64(ru))
+}
+
+// sendTokenBucketRequest reports the consumption accumulated since the last
+// request and requests enough Request Units to refill the local bucket.
+func (c *tenantSideCostController) sendTokenBucketRequest(ctx context.Context) {
+	c.mu.Lock()
+	now := c.timeSource.Now()
+	c.refillLocked(now)
+	expectedRU := c.mu.availableRU
+	if c.mu.trickleRate > 0 {
+		expectedRU += c.mu.trickleRate * c.mu.trickleDeadline.Sub(now).Seconds()
+	}
+	var requested float64
+	if expectedRU < bufferRUs {
+		requested = bufferRUs - expectedRU
+	}
+	consumption := c.mu.consumption
+	c.mu.consumption = roachpb.TenantConsumption{}
+	c.mu.Unlock()
+
+	req := roachpb.TokenBucketRequest{
+		TenantID:                    c.tenantID.ToUint64(),
+		InstanceID:                  uint32(c.instanceID),
+		ConsumptionSinceLastRequest: consumption,
+		RequestedRU:                 requested,
+	}
+	resp, err := c.provider.TokenBucket(ctx, &req)
+	c.lastRequestFailed = err != nil
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now = c.timeSource.Now()
+	c.refillLocked(now)
+	if err != nil {
+		// Don't let a failure of the cost control machinery block the tenant,
+		// but don't hand out the requested Request Units at once either: trickle
+		// them in until the next periodic update, which retries the request. The
+		// consumption is reported with the next successful request.
+		log.Warningf(ctx, "token bucket request failed: %v", err)
+		c.mu.consumption.Add(&consumption)
+		c.metrics.FailedTokenBucketRequests.Inc(1)
+		c.trickleLocked(now, requested, mainLoopUpdateInterval)
+		return
+	}
+	if resp.TrickleDuration == 0 {
+		c.addRULocked(resp.GrantedRU)
+		return
+	}
+	c.trickleLocked(now, resp.GrantedRU, resp.TrickleDuration)
+}
+
+// trickleLocked arranges for the given Request Units to be added to the local
+// bucket gradually, over the given duration. Any part of a previous trickle
+// which hasn't been delivered yet is rolled into the new one.
+func (c *tenantSideCostController) trickleLocked(
+	now time.Time, ru float64, duration time.Duration,
+) {
+	if c.mu.trickleRate > 0 {
+		ru += c.mu.trickleRate * c.mu.trickleDeadline.Sub(now).Seconds()
+	}
+	c.mu.trickleRate = ru / duration.Seconds()
+	c.mu.trickleDeadline = now.Add(duration)
+	c.notifyRefillLocked()
+}
+
+// refillLocked adds the trickled Request Units accumulated since the last
+// update to the local bucket.
+func (c *tenantSideCostController) refillLocked(now time.Time) {
+	if c.mu.trickleRate > 0 {
+		end := now
+		if end.After(c.mu.trickleDeadline) {
+			end = c.mu.trickleDeadline
+		}
+		if end.After(c.mu.lastUpdate) {
+			c.mu.availableRU += c.mu.trickleRate * end.Sub(c.mu.lastUpdate).Seconds()
+		}
+		if !now.Before(c.mu.trickleDeadline) {
+			c.mu.trickleRate = 0
+		}
+	}
+	c.mu.lastUpdate = now
+	c.metrics.AvailableRU.Update(c.mu.availableRU)
+}
+
+// addRULocked adds Request Units to the local bucket and wakes up the blocked
+// requests.
+func (c *tenantSideCostController) addRULocked(ru float64) {
+	c.mu.availableRU += ru
+	c.metrics.AvailableRU.Update(c.mu.availableRU)
+	c.notifyRefillLocked()
+}
+
+func (c *tenantSideCostController) notifyRefillLocked() {
+	close(c.mu.refillCh)
+	c.mu.refillCh = make(chan struct{})
+}
+
+// chargeLocked removes Request Units from the local bucket and records them as
+// consumed. If the bucket is running low, the main loop is notified.
+func (c *tenantSideCostController) chargeLocked(ru float64) {
+	c.mu.availableRU -= ru
+	c.mu.consumption.RU += ru
+	c.metrics.TotalRU.Update(c.metrics.TotalRU.Value() + ru)
+	c.metrics.AvailableRU.Update(c.mu.availableRU)
+	if c.mu.availableRU < bufferRUs/2 {
+		select {
+		case c.lowRUNotifyChan <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// OnRequestWait is part of the multitenant.TenantSideKVInterceptor interface.
+func (c *tenantSideCostController) OnRequestWait(
+	ctx context.Context, info tenantcostmodel.RequestInfo,
+) error {
+	var timer timeutil.TimerI
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	blocked := false
+	defer func() {
+		if blocked {
+			c.metrics.CurrentBlocked.Dec(1)
+		}
+	}()
+	for {
+		c.mu.Lock()
+		c.refillLocked(c.timeSource.Now())
+		if c.mu.availableRU > 0 {
+			isWrite, writeBytes := info.IsWrite()
+			var ru tenantcostmodel.RU
+			if isWrite {
+				ru = c.mu.costCfg.KVWriteCost(writeBytes)
+				c.mu.consumption.WriteRequests++
+				c.mu.consumption.WriteBytes += uint64(writeBytes)
+			} else {
+				ru = c.mu.costCfg.KVReadRequest
+				c.mu.consumption.ReadRequests++
+			}
+			c.chargeLocked(float64(ru))
+			c.mu.Unlock()
+			return nil
+		}
+
+		// The local bucket is in debt; wait until it is refilled.
+		wait := maxWaitDuration
+		if c.mu.trickleRate > 0 {
+			needed := -c.mu.availableRU
+			if w := time.Duration(needed / c.mu.trickleRate * float64(time.Second)); w < wait {
+				wait = w + time.Millisecond
+			}
+		}
+		refillCh := c.mu.refillCh
+		select {
+		case c.lowRUNotifyChan <- struct{}{}:
+		default:
+		}
+		c.mu.Unlock()
+
+		if !blocked {
+			blocked = true
+			c.metrics.CurrentBlocked.Inc(1)
+			c.metrics.BlockedRequests.Inc(1)
+		}
+		if timer == nil {
+			timer = c.timeSource.NewTimer()
+		}
+		timer.Reset(wait)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-refillCh:
+		case <-timer.Ch():
+			timer.MarkRead()
+		}
+	}
+}
+
+// OnResponse is part of the multitenant.TenantSideKVInterceptor interface.
+func (c *tenantSideCostController) OnResponse(
+	ctx context.Context, req tenantcostmodel.RequestInfo, resp tenantcostmodel.ResponseInfo,
+) {
+	if isWrite, _ := req.IsWrite(); isWrite {
+		return
+	}
+	readBytes := resp.ReadBytes()
+	if readBytes <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.consumption.ReadBytes += uint64(readBytes)
+	c.chargeLocked(float64(tenantcostmodel.RU(readBytes) * c.mu.costCfg.KVReadByte))
+}
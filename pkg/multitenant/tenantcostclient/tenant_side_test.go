@@ -0,0 +1,183 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tenantcostclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// testProvider is a kvtenant.TokenBucketProvider which records the requests
+// and grants a configurable number of Request Units.
+type testProvider struct {
+	mu struct {
+		syncutil.Mutex
+		consumption roachpb.TenantConsumption
+		requests    int
+		grant       bool
+		err         error
+	}
+}
+
+func (p *testProvider) TokenBucket(
+	_ context.Context, in *roachpb.TokenBucketRequest,
+) (*roachpb.TokenBucketResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.mu.requests++
+	if p.mu.err != nil {
+		return nil, p.mu.err
+	}
+	p.mu.consumption.Add(&in.ConsumptionSinceLastRequest)
+	var resp roachpb.TokenBucketResponse
+	if p.mu.grant {
+		resp.GrantedRU = in.RequestedRU
+	}
+	return &resp, nil
+}
+
+func (p *testProvider) consumption() roachpb.TenantConsumption {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.mu.consumption
+}
+
+func (p *testProvider) setGrant(grant bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.mu.grant = grant
+}
+
+func (p *testProvider) setError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.mu.err = err
+}
+
+func TestTenantSideCostController(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	st := cluster.MakeTestingClusterSettings()
+	timeSource := timeutil.NewManualTime(timeutil.Unix(0, 0))
+	provider := &testProvider{}
+	ctrl := NewTenantSideCostController(
+		st, roachpb.MakeTenantID(5), 1 /* instanceID */, provider, timeSource,
+	).(*tenantSideCostController)
+	var cpuMu syncutil.Mutex
+	cpuSecs := 0.0
+	cpuSecsFn := func(context.Context) float64 {
+		cpuMu.Lock()
+		defer cpuMu.Unlock()
+		return cpuSecs
+	}
+	require.NoError(t, ctrl.Start(ctx, stopper, cpuSecsFn))
+	cfg := tenantcostmodel.ConfigFromSettings(&st.SV)
+
+	availableRU := func() float64 {
+		ctrl.mu.Lock()
+		defer ctrl.mu.Unlock()
+		return ctrl.mu.availableRU
+	}
+
+	// Reads and writes are charged according to the cost model.
+	read := tenantcostmodel.TestingRequestInfo(false /* isWrite */, 0)
+	write := tenantcostmodel.TestingRequestInfo(true /* isWrite */, 1024)
+	require.NoError(t, ctrl.OnRequestWait(ctx, read))
+	ctrl.OnResponse(ctx, read, tenantcostmodel.TestingResponseInfo(2048))
+	require.NoError(t, ctrl.OnRequestWait(ctx, write))
+	ctrl.OnResponse(ctx, write, tenantcostmodel.TestingResponseInfo(0))
+	expRU := float64(cfg.KVReadCost(2048) + cfg.KVWriteCost(1024))
+	require.InDelta(t, initialRUs-expRU, availableRU(), 1e-6)
+
+	// Exhaust the local bucket; the provider doesn't grant anything, so the
+	// next request blocks.
+	require.NoError(t, ctrl.OnRequestWait(ctx, tenantcostmodel.TestingRequestInfo(true, 4<<20)))
+	require.Less(t, availableRU(), 0.0)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ctrl.OnRequestWait(ctx, write)
+	}()
+	testutils.SucceedsSoon(t, func() error {
+		if ctrl.metrics.CurrentBlocked.Value() != 1 {
+			return errors.New("request not blocked yet")
+		}
+		return nil
+	})
+
+	// Once the provider grants Request Units, the request is unblocked.
+	provider.setGrant(true)
+	timeSource.Advance(mainLoopUpdateInterval)
+	require.NoError(t, <-errCh)
+	require.Equal(t, int64(0), ctrl.metrics.CurrentBlocked.Value())
+	require.Equal(t, int64(1), ctrl.metrics.BlockedRequests.Count())
+
+	// The consumption is reported to the provider.
+	testutils.SucceedsSoon(t, func() error {
+		c := provider.consumption()
+		if c.ReadRequests != 1 || c.ReadBytes != 2048 || c.WriteRequests < 2 {
+			return errors.Errorf("unexpected consumption %s", c.String())
+		}
+		return nil
+	})
+
+	// CPU usage is charged periodically.
+	cpuMu.Lock()
+	cpuSecs = 0.5
+	cpuMu.Unlock()
+	timeSource.Advance(mainLoopUpdateInterval)
+	testutils.SucceedsSoon(t, func() error {
+		if c := provider.consumption(); c.SQLPodsCPUSeconds != 0.5 {
+			return errors.Errorf("unexpected consumption %s", c.String())
+		}
+		return nil
+	})
+
+	// When token bucket requests fail, the Request Units are trickled in
+	// locally so that the tenant is throttled but not blocked indefinitely.
+	provider.setError(errors.New("injected error"))
+	require.NoError(t, ctrl.OnRequestWait(ctx, tenantcostmodel.TestingRequestInfo(true, 64<<20)))
+	testutils.SucceedsSoon(t, func() error {
+		if ctrl.metrics.FailedTokenBucketRequests.Count() == 0 {
+			return errors.New("no failed requests yet")
+		}
+		return nil
+	})
+	require.Less(t, availableRU(), 0.0)
+	go func() {
+		errCh <- ctrl.OnRequestWait(ctx, write)
+	}()
+	testutils.SucceedsSoon(t, func() error {
+		if ctrl.metrics.CurrentBlocked.Value() != 1 {
+			return errors.New("request not blocked yet")
+		}
+		return nil
+	})
+	// Running low on Request Units doesn't trigger new requests while the
+	// Request Units are trickled in.
+	require.Equal(t, int64(1), ctrl.metrics.FailedTokenBucketRequests.Count())
+	timeSource.Advance(mainLoopUpdateInterval)
+	require.NoError(t, <-errCh)
+	require.Greater(t, availableRU(), 0.0)
+}
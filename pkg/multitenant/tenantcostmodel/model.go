@@ -0,0 +1,130 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package tenantcostmodel contains the cost model used to convert the
+// resources consumed by a tenant into Request Units.
+//
+// Request Units (RU) are an abstract measure of the cost of the work performed
+// on behalf of a tenant. Reads, writes, the bytes they touch and the CPU time
+// used by the tenant's SQL pods all have a cost in RUs, which is configured
+// through cluster settings. Tenants are billed and rate limited in RUs.
+package tenantcostmodel
+
+import "github.com/cockroachdb/cockroach/pkg/roachpb"
+
+// RU stands for "Request Unit(s)"; the tenant cost model maps tenant activity
+// into this abstract unit.
+type RU float64
+
+// Config contains the cost model parameters. The values are controlled by
+// cluster settings.
+//
+// The aim of the model is to provide a reasonable estimate of the resource
+// utilization of a tenant, per-request. It is not meant to be precise.
+type Config struct {
+	// KVReadRequest is the baseline cost of a KV read request.
+	KVReadRequest RU
+
+	// KVReadByte is the per-byte cost of a KV read.
+	KVReadByte RU
+
+	// KVWriteRequest is the baseline cost of a KV write request.
+	KVWriteRequest RU
+
+	// KVWriteByte is the per-byte cost of a KV write.
+	KVWriteByte RU
+
+	// PodCPUSecond is the cost of using a CPU second on the SQL pod.
+	PodCPUSecond RU
+}
+
+// KVReadCost calculates the cost of a KV read request that read the given
+// number of bytes.
+func (c *Config) KVReadCost(bytes int64) RU {
+	return c.KVReadRequest + RU(bytes)*c.KVReadByte
+}
+
+// KVWriteCost calculates the cost of a KV write request that wrote the given
+// number of bytes.
+func (c *Config) KVWriteCost(bytes int64) RU {
+	return c.KVWriteRequest + RU(bytes)*c.KVWriteByte
+}
+
+// PodCPUCost calculates the cost of the given number of CPU seconds used on a
+// SQL pod.
+func (c *Config) PodCPUCost(seconds float64) RU {
+	return RU(seconds) * c.PodCPUSecond
+}
+
+// RequestInfo captures the request information that is used (together with
+// the cost model) to determine the portion of the cost that can be calculated
+// before the request is sent.
+type RequestInfo struct {
+	// writeBytes is the write size if the request is a write, or -1 if it is
+	// a read.
+	writeBytes int64
+}
+
+// MakeRequestInfo extracts the relevant information from a BatchRequest.
+func MakeRequestInfo(ba *roachpb.BatchRequest) RequestInfo {
+	if !ba.IsWrite() {
+		return RequestInfo{writeBytes: -1}
+	}
+	var writeBytes int64
+	for i := range ba.Requests {
+		if swr, isSizedWrite := ba.Requests[i].GetInner().(roachpb.SizedWriteRequest); isSizedWrite {
+			writeBytes += swr.WriteBytes()
+		}
+	}
+	return RequestInfo{writeBytes: writeBytes}
+}
+
+// IsWrite returns whether the request is a write, and if so the write size in
+// bytes.
+func (ri RequestInfo) IsWrite() (isWrite bool, writeBytes int64) {
+	if ri.writeBytes == -1 {
+		return false, 0
+	}
+	return true, ri.writeBytes
+}
+
+// TestingRequestInfo creates a RequestInfo for testing purposes.
+func TestingRequestInfo(isWrite bool, writeBytes int64) RequestInfo {
+	if !isWrite {
+		return RequestInfo{writeBytes: -1}
+	}
+	return RequestInfo{writeBytes: writeBytes}
+}
+
+// ResponseInfo captures the BatchResponse information that is used (together
+// with the cost model) to determine the portion of the cost that can only be
+// calculated after receiving the response.
+type ResponseInfo struct {
+	readBytes int64
+}
+
+// MakeResponseInfo extracts the relevant information from a BatchResponse.
+func MakeResponseInfo(br *roachpb.BatchResponse) ResponseInfo {
+	var readBytes int64
+	for _, ru := range br.Responses {
+		readBytes += ru.GetInner().Header().NumBytes
+	}
+	return ResponseInfo{readBytes: readBytes}
+}
+
+// ReadBytes returns the number of bytes read by the request.
+func (ri ResponseInfo) ReadBytes() int64 {
+	return ri.readBytes
+}
+
+// TestingResponseInfo creates a ResponseInfo for testing purposes.
+func TestingResponseInfo(readBytes int64) ResponseInfo {
+	return ResponseInfo{readBytes: readBytes}
+}
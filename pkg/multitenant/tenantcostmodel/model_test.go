@@ -0,0 +1,81 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tenantcostmodel
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFromSettings(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	st := cluster.MakeTestingClusterSettings()
+	cfg := ConfigFromSettings(&st.SV)
+	require.Equal(t, RU(0.7), cfg.KVReadRequest)
+	require.Equal(t, RU(1.0), cfg.KVWriteRequest)
+	require.InDelta(t, 10.0, float64(cfg.KVReadByte*1024*1024), 1e-9)
+	require.InDelta(t, 400.0, float64(cfg.KVWriteByte*1024*1024), 1e-9)
+
+	var changed int
+	for _, setOnChange := range SetOnChangeFuncs {
+		setOnChange(&st.SV, func() { changed++ })
+	}
+	writeRequestCost.Override(&st.SV, 2.5)
+	podCPUSecondCost.Override(&st.SV, 10)
+	require.Equal(t, 2, changed)
+	cfg = ConfigFromSettings(&st.SV)
+	require.Equal(t, RU(2.5), cfg.KVWriteRequest)
+	require.Equal(t, RU(10), cfg.PodCPUSecond)
+}
+
+func TestCosts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	cfg := Config{
+		KVReadRequest:  1,
+		KVReadByte:     0.5,
+		KVWriteRequest: 3,
+		KVWriteByte:    2,
+		PodCPUSecond:   100,
+	}
+	require.Equal(t, RU(1), cfg.KVReadCost(0))
+	require.Equal(t, RU(6), cfg.KVReadCost(10))
+	require.Equal(t, RU(3), cfg.KVWriteCost(0))
+	require.Equal(t, RU(23), cfg.KVWriteCost(10))
+	require.Equal(t, RU(50), cfg.PodCPUCost(0.5))
+}
+
+func TestRequestAndResponseInfo(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var ba roachpb.BatchRequest
+	ba.Add(roachpb.NewGet(roachpb.Key("a")))
+	isWrite, writeBytes := MakeRequestInfo(&ba).IsWrite()
+	require.False(t, isWrite)
+	require.Equal(t, int64(0), writeBytes)
+
+	ba = roachpb.BatchRequest{}
+	ba.Add(roachpb.NewPut(roachpb.Key("a"), roachpb.MakeValueFromString("hello")))
+	ba.Add(roachpb.NewDelete(roachpb.Key("b")))
+	isWrite, writeBytes = MakeRequestInfo(&ba).IsWrite()
+	require.True(t, isWrite)
+	require.Equal(t, ba.Requests[0].GetPut().WriteBytes()+ba.Requests[1].GetDelete().WriteBytes(), writeBytes)
+
+	var br roachpb.BatchResponse
+	br.Add(&roachpb.ScanResponse{ResponseHeader: roachpb.ResponseHeader{NumBytes: 100}})
+	br.Add(&roachpb.GetResponse{ResponseHeader: roachpb.ResponseHeader{NumBytes: 20}})
+	require.Equal(t, int64(120), MakeResponseInfo(&br).ReadBytes())
+}
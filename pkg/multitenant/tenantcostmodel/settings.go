@@ -0,0 +1,68 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tenantcostmodel
+
+import "github.com/cockroachdb/cockroach/pkg/settings"
+
+// Settings for the cost model parameters.
+var (
+	readRequestCost = settings.RegisterNonNegativeFloatSetting(
+		"tenant_cost_model.kv_read_request_cost",
+		"base cost of a read request in Request Units",
+		0.7,
+	)
+
+	readCostPerMB = settings.RegisterNonNegativeFloatSetting(
+		"tenant_cost_model.kv_read_cost_per_megabyte",
+		"cost of a read in Request Units per MB",
+		10.0,
+	)
+
+	writeRequestCost = settings.RegisterNonNegativeFloatSetting(
+		"tenant_cost_model.kv_write_request_cost",
+		"base cost of a write request in Request Units",
+		1.0,
+	)
+
+	writeCostPerMB = settings.RegisterNonNegativeFloatSetting(
+		"tenant_cost_model.kv_write_cost_per_megabyte",
+		"cost of a write in Request Units per MB",
+		400.0,
+	)
+
+	podCPUSecondCost = settings.RegisterNonNegativeFloatSetting(
+		"tenant_cost_model.pod_cpu_second_cost",
+		"cost of a CPU second on the tenant's SQL pods in Request Units",
+		1000.0,
+	)
+
+	// SetOnChangeFuncs are the functions used to register a callback to be
+	// notified of changes to any of the settings which configure the cost
+	// model.
+	SetOnChangeFuncs = [...]func(*settings.Values, func()){
+		readRequestCost.SetOnChange,
+		readCostPerMB.SetOnChange,
+		writeRequestCost.SetOnChange,
+		writeCostPerMB.SetOnChange,
+		podCPUSecondCost.SetOnChange,
+	}
+)
+
+// ConfigFromSettings constructs a Config using the cluster setting values.
+func ConfigFromSettings(sv *settings.Values) Config {
+	return Config{
+		KVReadRequest:  RU(readRequestCost.Get(sv)),
+		KVReadByte:     RU(readCostPerMB.Get(sv) / (1024 * 1024)),
+		KVWriteRequest: RU(writeRequestCost.Get(sv)),
+		KVWriteByte:    RU(writeCostPerMB.Get(sv) / (1024 * 1024)),
+		PodCPUSecond:   RU(podCPUSecondCost.Get(sv)),
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tenantcostserver
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/metric/aggmetric"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// Metrics is a metric.Struct for the tenant usage server. The metrics reflect
+// the total consumption of each tenant, as reported by its SQL pods, and are
+// updated by the node which processes a tenant's TokenBucket requests.
+type Metrics struct {
+	TotalRU                *aggmetric.AggGauge
+	TotalReadRequests      *aggmetric.AggGauge
+	TotalReadBytes         *aggmetric.AggGauge
+	TotalWriteRequests     *aggmetric.AggGauge
+	TotalWriteBytes        *aggmetric.AggGauge
+	TotalSQLPodsCPUSeconds *aggmetric.AggGauge
+
+	mu struct {
+		syncutil.Mutex
+		// tenantMetrics contains the per-tenant children.
+		tenantMetrics map[roachpb.TenantID]tenantMetrics
+	}
+}
+
+var _ metric.Struct = (*Metrics)(nil)
+
+// MetricStruct indicates that Metrics is a metric.Struct
+func (m *Metrics) MetricStruct() {}
+
+var (
+	metaTotalRU = metric.Metadata{
+		Name:        "tenant.consumption.request_units",
+		Help:        "Total RU consumption",
+		Measurement: "Request Units",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaTotalReadRequests = metric.Metadata{
+		Name:        "tenant.consumption.read_requests",
+		Help:        "Total number of KV read requests",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaTotalReadBytes = metric.Metadata{
+		Name:        "tenant.consumption.read_bytes",
+		Help:        "Total number of bytes read from KV",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaTotalWriteRequests = metric.Metadata{
+		Name:        "tenant.consumption.write_requests",
+		Help:        "Total number of KV write requests",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaTotalWriteBytes = metric.Metadata{
+		Name:        "tenant.consumption.write_bytes",
+		Help:        "Total number of bytes written to KV",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaTotalSQLPodsCPUSeconds = metric.Metadata{
+		Name:        "tenant.consumption.sql_pods_cpu_seconds",
+		Help:        "Total number of CPU seconds used by the tenant's SQL pods",
+		Measurement: "CPU Seconds",
+		Unit:        metric.Unit_SECONDS,
+	}
+)
+
+// TenantIDLabel is the label used with metrics associated with a tenant.
+// The value will be the integer tenant ID.
+const TenantIDLabel = "tenant_id"
+
+func (m *Metrics) init() {
+	b := aggmetric.MakeBuilder(TenantIDLabel)
+	*m = Metrics{
+		TotalRU:                b.Gauge(metaTotalRU),
+		TotalReadRequests:      b.Gauge(metaTotalReadRequests),
+		TotalReadBytes:         b.Gauge(metaTotalReadBytes),
+		TotalWriteRequests:     b.Gauge(metaTotalWriteRequests),
+		TotalWriteBytes:        b.Gauge(metaTotalWriteBytes),
+		TotalSQLPodsCPUSeconds: b.Gauge(metaTotalSQLPodsCPUSeconds),
+	}
+	m.mu.tenantMetrics = make(map[roachpb.TenantID]tenantMetrics)
+}
+
+// tenantMetrics represent metrics for an individual tenant.
+type tenantMetrics struct {
+	totalRU                *aggmetric.Gauge
+	totalReadRequests      *aggmetric.Gauge
+	totalReadBytes         *aggmetric.Gauge
+	totalWriteRequests     *aggmetric.Gauge
+	totalWriteBytes        *aggmetric.Gauge
+	totalSQLPodsCPUSeconds *aggmetric.Gauge
+}
+
+// getTenantMetrics returns the metrics for a tenant, creating them if
+// necessary.
+func (m *Metrics) getTenantMetrics(tenantID roachpb.TenantID) tenantMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tm, ok := m.mu.tenantMetrics[tenantID]
+	if !ok {
+		tid := tenantID.String()
+		tm = tenantMetrics{
+			totalRU:                m.TotalRU.AddChild(tid),
+			totalReadRequests:      m.TotalReadRequests.AddChild(tid),
+			totalReadBytes:         m.TotalReadBytes.AddChild(tid),
+			totalWriteRequests:     m.TotalWriteRequests.AddChild(tid),
+			totalWriteBytes:        m.TotalWriteBytes.AddChild(tid),
+			totalSQLPodsCPUSeconds: m.TotalSQLPodsCPUSeconds.AddChild(tid),
+		}
+		m.mu.tenantMetrics[tenantID] = tm
+	}
+	return tm
+}
+
+// update sets the metrics of a tenant to its current total consumption.
+func (m *Metrics) update(tenantID roachpb.TenantID, c *roachpb.TenantConsumption) {
+	tm := m.getTenantMetrics(tenantID)
+	tm.totalRU.Update(int64(c.RU))
+	tm.totalReadRequests.Update(int64(c.ReadRequests))
+	tm.totalReadBytes.Update(int64(c.ReadBytes))
+	tm.totalWriteRequests.Update(int64(c.WriteRequests))
+	tm.totalWriteBytes.Update(int64(c.WriteBytes))
+	tm.totalSQLPodsCPUSeconds.Update(int64(c.SQLPodsCPUSeconds))
+}
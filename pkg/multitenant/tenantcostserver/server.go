@@ -0,0 +1,50 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package tenantcostserver contains the host cluster side of the tenant cost
+// control machinery: it maintains the global Request Unit budget of each
+// tenant in the system.tenant_usage table and distributes it to the tenant's
+// SQL pods through the TokenBucket API.
+package tenantcostserver
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+type instance struct {
+	db         *kv.DB
+	executor   sqlutil.InternalExecutor
+	timeSource timeutil.TimeSource
+	metrics    Metrics
+}
+
+// NewInstance creates a tenant usage server.
+func NewInstance(
+	db *kv.DB, executor sqlutil.InternalExecutor, timeSource timeutil.TimeSource,
+) multitenant.TenantUsageServer {
+	res := &instance{
+		db:         db,
+		executor:   executor,
+		timeSource: timeSource,
+	}
+	res.metrics.init()
+	return res
+}
+
+// Metrics is part of the multitenant.TenantUsageServer interface.
+func (s *instance) Metrics() metric.Struct {
+	return &s.metrics
+}
+
+var _ multitenant.TenantUsageServer = (*instance)(nil)
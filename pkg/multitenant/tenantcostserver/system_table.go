@@ -0,0 +1,119 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tenantcostserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Default token bucket parameters, used for tenants that don't have a row in
+// system.tenant_usage (i.e. whose limits were never configured).
+const (
+	defaultRUBurstLimit = 10000000
+	defaultRURefillRate = 10000
+)
+
+// tenantState is the state of a tenant, as stored in its row of
+// system.tenant_usage.
+type tenantState struct {
+	// Present is true if the row exists in the table.
+	Present bool
+
+	// LastUpdate is the last time the bucket was updated.
+	LastUpdate time.Time
+
+	Bucket bucketState
+
+	// Consumption is the total consumption of the tenant.
+	Consumption roachpb.TenantConsumption
+}
+
+// defaultTenantState returns the state of a tenant without a row in the
+// system table.
+func defaultTenantState(now time.Time) tenantState {
+	return tenantState{
+		LastUpdate: now,
+		Bucket: bucketState{
+			RUBurstLimit: defaultRUBurstLimit,
+			RURefillRate: defaultRURefillRate,
+			RUCurrent:    defaultRUBurstLimit,
+		},
+	}
+}
+
+// readTenantState reads the state of a tenant from system.tenant_usage. If
+// there is no row for the tenant, the default state is returned (with Present
+// set to false).
+func (s *instance) readTenantState(
+	ctx context.Context, txn *kv.Txn, tenantID roachpb.TenantID,
+) (tenantState, error) {
+	row, err := s.executor.QueryRowEx(
+		ctx, "tenant-usage-select", txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT ru_burst_limit, ru_refill_rate, ru_current, last_update, total_consumption
+		   FROM system.tenant_usage WHERE tenant_id = $1`,
+		tenantID.ToUint64(),
+	)
+	if err != nil {
+		return tenantState{}, err
+	}
+	if row == nil {
+		return defaultTenantState(s.timeSource.Now()), nil
+	}
+	state := tenantState{
+		Present:    true,
+		LastUpdate: tree.MustBeDTimestamp(row[3]).Time,
+		Bucket: bucketState{
+			RUBurstLimit: float64(tree.MustBeDFloat(row[0])),
+			RURefillRate: float64(tree.MustBeDFloat(row[1])),
+			RUCurrent:    float64(tree.MustBeDFloat(row[2])),
+		},
+	}
+	if row[4] != tree.DNull {
+		consumption := tree.MustBeDBytes(row[4])
+		if err := protoutil.Unmarshal([]byte(consumption), &state.Consumption); err != nil {
+			return tenantState{}, errors.Wrapf(err, "decoding consumption of tenant %s", tenantID)
+		}
+	}
+	return state, nil
+}
+
+// writeTenantState writes the state of a tenant to system.tenant_usage.
+func (s *instance) writeTenantState(
+	ctx context.Context, txn *kv.Txn, tenantID roachpb.TenantID, state *tenantState,
+) error {
+	consumption, err := protoutil.Marshal(&state.Consumption)
+	if err != nil {
+		return err
+	}
+	_, err = s.executor.ExecEx(
+		ctx, "tenant-usage-upsert", txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`UPSERT INTO system.tenant_usage(
+		   tenant_id, ru_burst_limit, ru_refill_rate, ru_current, last_update, total_consumption
+		 ) VALUES ($1, $2, $3, $4, $5, $6)`,
+		tenantID.ToUint64(),
+		state.Bucket.RUBurstLimit,
+		state.Bucket.RURefillRate,
+		state.Bucket.RUCurrent,
+		state.LastUpdate,
+		consumption,
+	)
+	return err
+}
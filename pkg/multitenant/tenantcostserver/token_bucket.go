@@ -0,0 +1,151 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tenantcostserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// maxTrickleDuration is the longest period over which a grant can be trickled
+// to a SQL pod when the tenant's bucket is depleted. It bounds the "debt" that
+// a tenant can accumulate.
+const maxTrickleDuration = 10 * time.Second
+
+// bucketState is the global token bucket of a tenant.
+type bucketState struct {
+	// RUBurstLimit is the maximum number of Request Units that can accumulate
+	// in the bucket. Zero means there is no limit.
+	RUBurstLimit float64
+	// RURefillRate is the rate at which the bucket is refilled, in Request
+	// Units per second.
+	RURefillRate float64
+	// RUCurrent is the number of Request Units currently available in the
+	// bucket. It can be negative, in which case the tenant is in debt.
+	RUCurrent float64
+}
+
+// update refills the bucket with the Request Units accumulated over the given
+// period of time.
+func (s *bucketState) update(since time.Duration) {
+	if since <= 0 {
+		return
+	}
+	s.RUCurrent += s.RURefillRate * since.Seconds()
+	if s.RUBurstLimit > 0 && s.RUCurrent > s.RUBurstLimit {
+		s.RUCurrent = s.RUBurstLimit
+	}
+}
+
+// request processes a request for Request Units and returns the grant.
+//
+// If the bucket has enough Request Units, they are granted immediately.
+// Otherwise, the grant is taken out of the refill rate: the bucket goes into
+// debt and the grant is trickled to the SQL pod over the time it takes to pay
+// back the debt (at most maxTrickleDuration).
+func (s *bucketState) request(requested float64) (granted float64, trickle time.Duration) {
+	if requested <= 0 {
+		return 0, 0
+	}
+	available := s.RUCurrent
+	if available >= requested {
+		s.RUCurrent -= requested
+		return requested, 0
+	}
+	if s.RURefillRate <= 0 {
+		// The bucket doesn't refill; grant whatever is left in it.
+		if available <= 0 {
+			return 0, 0
+		}
+		s.RUCurrent = 0
+		return available, 0
+	}
+	// Grant as much as can be paid back within maxTrickleDuration.
+	granted = requested
+	if maxGrant := available + s.RURefillRate*maxTrickleDuration.Seconds(); granted > maxGrant {
+		granted = maxGrant
+	}
+	if granted <= 0 {
+		return 0, 0
+	}
+	s.RUCurrent -= granted
+	trickle = time.Duration((granted - available) / s.RURefillRate * float64(time.Second))
+	return granted, trickle
+}
+
+// TokenBucketRequest is part of the multitenant.TenantUsageServer interface.
+func (s *instance) TokenBucketRequest(
+	ctx context.Context, tenantID roachpb.TenantID, in *roachpb.TokenBucketRequest,
+) *roachpb.TokenBucketResponse {
+	if tenantID == roachpb.SystemTenantID {
+		return &roachpb.TokenBucketResponse{
+			Error: roachpb.NewError(errors.New("token bucket request for system tenant")),
+		}
+	}
+
+	var result roachpb.TokenBucketResponse
+	var consumption roachpb.TenantConsumption
+	if err := s.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		// Reset the result in case the transaction is retried.
+		result = roachpb.TokenBucketResponse{}
+
+		state, err := s.readTenantState(ctx, txn, tenantID)
+		if err != nil {
+			return err
+		}
+		now := s.timeSource.Now()
+		state.Bucket.update(now.Sub(state.LastUpdate))
+		state.LastUpdate = now
+
+		state.Consumption.Add(&in.ConsumptionSinceLastRequest)
+		result.GrantedRU, result.TrickleDuration = state.Bucket.request(in.RequestedRU)
+
+		if err := s.writeTenantState(ctx, txn, tenantID, &state); err != nil {
+			return err
+		}
+		consumption = state.Consumption
+		return nil
+	}); err != nil {
+		log.Warningf(ctx, "token bucket request for tenant %s failed: %v", tenantID, err)
+		return &roachpb.TokenBucketResponse{Error: roachpb.NewError(err)}
+	}
+
+	s.metrics.update(tenantID, &consumption)
+	return &result
+}
+
+// ReconfigureTokenBucket is part of the multitenant.TenantUsageServer
+// interface.
+func (s *instance) ReconfigureTokenBucket(
+	ctx context.Context,
+	txn *kv.Txn,
+	tenantID roachpb.TenantID,
+	availableRU float64,
+	refillRate float64,
+	maxBurstRU float64,
+) error {
+	state, err := s.readTenantState(ctx, txn, tenantID)
+	if err != nil {
+		return err
+	}
+	state.LastUpdate = s.timeSource.Now()
+	state.Bucket = bucketState{
+		RUBurstLimit: maxBurstRU,
+		RURefillRate: refillRate,
+		RUCurrent:    availableRU,
+	}
+	return s.writeTenantState(ctx, txn, tenantID, &state)
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tenantcostserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketUpdate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s := bucketState{RUBurstLimit: 1000, RURefillRate: 100, RUCurrent: 0}
+	s.update(-time.Second)
+	require.Equal(t, 0.0, s.RUCurrent)
+	s.update(2 * time.Second)
+	require.Equal(t, 200.0, s.RUCurrent)
+	// The bucket is capped at the burst limit.
+	s.update(time.Minute)
+	require.Equal(t, 1000.0, s.RUCurrent)
+
+	// A zero burst limit means there is no limit.
+	s = bucketState{RURefillRate: 100, RUCurrent: 0}
+	s.update(time.Minute)
+	require.Equal(t, 6000.0, s.RUCurrent)
+}
+
+func TestBucketRequest(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		name       string
+		bucket     bucketState
+		requested  float64
+		expGranted float64
+		expTrickle time.Duration
+		expCurrent float64
+	}{
+		{
+			name:       "nothing requested",
+			bucket:     bucketState{RURefillRate: 100, RUCurrent: 1000},
+			requested:  0,
+			expGranted: 0,
+			expCurrent: 1000,
+		},
+		{
+			name:       "enough available",
+			bucket:     bucketState{RURefillRate: 100, RUCurrent: 1000},
+			requested:  400,
+			expGranted: 400,
+			expCurrent: 600,
+		},
+		{
+			name:       "partially available",
+			bucket:     bucketState{RURefillRate: 100, RUCurrent: 100},
+			requested:  400,
+			expGranted: 400,
+			expTrickle: 3 * time.Second,
+			expCurrent: -300,
+		},
+		{
+			name:       "in debt",
+			bucket:     bucketState{RURefillRate: 100, RUCurrent: -200},
+			requested:  400,
+			expGranted: 400,
+			expTrickle: 6 * time.Second,
+			expCurrent: -600,
+		},
+		{
+			name:       "grant limited by max trickle duration",
+			bucket:     bucketState{RURefillRate: 100, RUCurrent: -500},
+			requested:  1000,
+			expGranted: 500,
+			expTrickle: maxTrickleDuration,
+			expCurrent: -1000,
+		},
+		{
+			name:       "too much debt",
+			bucket:     bucketState{RURefillRate: 100, RUCurrent: -1000},
+			requested:  1000,
+			expGranted: 0,
+			expCurrent: -1000,
+		},
+		{
+			name:       "no refill",
+			bucket:     bucketState{RUCurrent: 100},
+			requested:  400,
+			expGranted: 100,
+			expCurrent: 0,
+		},
+		{
+			name:       "no refill and empty",
+			bucket:     bucketState{RUCurrent: 0},
+			requested:  400,
+			expGranted: 0,
+			expCurrent: 0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := tc.bucket
+			granted, trickle := s.request(tc.requested)
+			require.Equal(t, tc.expGranted, granted)
+			require.Equal(t, tc.expTrickle, trickle)
+			require.Equal(t, tc.expCurrent, s.RUCurrent)
+		})
+	}
+}
@@ -532,6 +532,7 @@ func (h *BatchResponse_Header) combine(o BatchResponse_Header) error {
 	}
 	h.Now.Forward(o.Now)
 	h.CollectedSpans = append(h.CollectedSpans, o.CollectedSpans...)
+	h.ContentionEvents = append(h.ContentionEvents, o.ContentionEvents...)
 	// Deduplicate the RangeInfos and maintain them in sorted order.
 	//
 	// TODO(andrei): stop merging RangeInfos once everybody but the DistSender
@@ -1212,6 +1213,11 @@ func (sr *ScanRequest) flags() int {
 	maybeLocking := 0
 	if sr.KeyLocking != lock.None {
 		maybeLocking = isLocking
+		if sr.ReplicatedLocking {
+			// Replicated locks are written to the replicated lock table, so
+			// the request must be evaluated as a write and proposed to Raft.
+			maybeLocking |= isWrite
+		}
 	}
 	return isRead | isRange | isTxn | maybeLocking | updatesTSCache | needsRefresh
 }
@@ -1220,6 +1226,11 @@ func (rsr *ReverseScanRequest) flags() int {
 	maybeLocking := 0
 	if rsr.KeyLocking != lock.None {
 		maybeLocking = isLocking
+		if rsr.ReplicatedLocking {
+			// Replicated locks are written to the replicated lock table, so
+			// the request must be evaluated as a write and proposed to Raft.
+			maybeLocking |= isWrite
+		}
 	}
 	return isRead | isRange | isReverse | isTxn | maybeLocking | updatesTSCache | needsRefresh
 }
@@ -1478,3 +1489,13 @@ func (r *JoinNodeResponse) CreateStoreIdent() (StoreIdent, error) {
 	}
 	return sIdent, nil
 }
+
+// Add consumption from the given structure.
+func (c *TenantConsumption) Add(other *TenantConsumption) {
+	c.RU += other.RU
+	c.ReadRequests += other.ReadRequests
+	c.ReadBytes += other.ReadBytes
+	c.WriteRequests += other.WriteRequests
+	c.WriteBytes += other.WriteBytes
+	c.SQLPodsCPUSeconds += other.SQLPodsCPUSeconds
+}
@@ -19,6 +19,10 @@ import (
 // StmtID is the type of a Statement ID.
 type StmtID uint64
 
+// TransactionFingerprintID is the type of a transaction fingerprint ID, which
+// is the hash of the IDs of the statements that the transaction comprises.
+type TransactionFingerprintID uint64
+
 // ConstructStatementID constructs an ID by hashing an anonymized query, it's
 // failure status, and if it was part of an implicit txn. At the time of writing,
 // these are the axis' we use to bucket queries for stats collection
@@ -111,6 +115,7 @@ func (t *TransactionStatistics) Add(other *TransactionStatistics) {
 	t.RetryLat.Add(other.RetryLat, t.Count, other.Count)
 	t.ServiceLat.Add(other.ServiceLat, t.Count, other.Count)
 	t.NumRows.Add(other.NumRows, t.Count, other.Count)
+	t.ContentionTime.Add(other.ContentionTime, t.Count, other.Count)
 
 	t.Count += other.Count
 }
@@ -130,6 +135,7 @@ func (s *StatementStatistics) Add(other *StatementStatistics) {
 	s.BytesRead.Add(other.BytesRead, s.Count, other.Count)
 	s.RowsRead.Add(other.RowsRead, s.Count, other.Count)
 	s.BytesSentOverNetwork.Add(other.BytesSentOverNetwork, s.Count, other.Count)
+	s.ContentionTime.Add(other.ContentionTime, s.Count, other.Count)
 
 	if other.SensitiveInfo.LastErr != "" {
 		s.SensitiveInfo.LastErr = other.SensitiveInfo.LastErr
@@ -139,9 +145,39 @@ func (s *StatementStatistics) Add(other *StatementStatistics) {
 		s.SensitiveInfo = other.SensitiveInfo
 	}
 
+	for _, gist := range other.PlanGists {
+		s.AddPlanGist(gist)
+	}
+
 	s.Count += other.Count
 }
 
+// MaxPlanGists is the maximum number of plan gists that are recorded for a
+// statement.
+const MaxPlanGists = 10
+
+// AddPlanGist records that the plan with the given gist was used to execute
+// the statement, making it the most recently used plan. If more than
+// MaxPlanGists plans are recorded, the least recently used ones are dropped.
+func (s *StatementStatistics) AddPlanGist(gist string) {
+	if n := len(s.PlanGists); n > 0 && s.PlanGists[n-1] == gist {
+		return
+	}
+	// The gists are copied into a new slice rather than modified in place,
+	// since copies of the statistics may share the slice.
+	gists := make([]string, 0, len(s.PlanGists)+1)
+	for _, g := range s.PlanGists {
+		if g != gist {
+			gists = append(gists, g)
+		}
+	}
+	gists = append(gists, gist)
+	if len(gists) > MaxPlanGists {
+		gists = gists[len(gists)-MaxPlanGists:]
+	}
+	s.PlanGists = gists
+}
+
 // AlmostEqual compares two StatementStatistics and their contained NumericStats
 // objects within an window of size eps.
 func (s *StatementStatistics) AlmostEqual(other *StatementStatistics, eps float64) bool {
@@ -157,5 +193,6 @@ func (s *StatementStatistics) AlmostEqual(other *StatementStatistics, eps float6
 		s.SensitiveInfo.Equal(other.SensitiveInfo) &&
 		s.BytesRead.AlmostEqual(other.BytesRead, eps) &&
 		s.RowsRead.AlmostEqual(other.RowsRead, eps) &&
-		s.BytesSentOverNetwork.AlmostEqual(other.BytesSentOverNetwork, eps)
+		s.BytesSentOverNetwork.AlmostEqual(other.BytesSentOverNetwork, eps) &&
+		s.ContentionTime.AlmostEqual(other.ContentionTime, eps)
 }
@@ -11,7 +11,9 @@
 package roachpb
 
 import (
+	"fmt"
 	"math"
+	"reflect"
 	"testing"
 )
 
@@ -81,3 +83,30 @@ func TestAddNumericStats(t *testing.T) {
 		t.Fatalf("a.Add(b) should match add(a, b): %+v vs %+v", a, combined)
 	}
 }
+
+func TestAddPlanGist(t *testing.T) {
+	var s StatementStatistics
+	for _, gist := range []string{"a", "b", "b", "c", "a"} {
+		s.AddPlanGist(gist)
+	}
+	if exp := []string{"b", "c", "a"}; !reflect.DeepEqual(s.PlanGists, exp) {
+		t.Fatalf("expected gists %v, got %v", exp, s.PlanGists)
+	}
+
+	// Adding gists must not modify copies of the statistics.
+	c := s
+	c.AddPlanGist("b")
+	if exp := []string{"b", "c", "a"}; !reflect.DeepEqual(s.PlanGists, exp) {
+		t.Fatalf("expected gists %v, got %v", exp, s.PlanGists)
+	}
+
+	// Only the most recently used gists are kept.
+	for i := 0; i < MaxPlanGists; i++ {
+		c.AddPlanGist(fmt.Sprint(i))
+	}
+	s.Add(&c)
+	if len(s.PlanGists) != MaxPlanGists || s.PlanGists[0] != "0" ||
+		s.PlanGists[MaxPlanGists-1] != fmt.Sprint(MaxPlanGists-1) {
+		t.Fatalf("expected the %d most recent gists, got %v", MaxPlanGists, s.PlanGists)
+	}
+}
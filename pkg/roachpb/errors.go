@@ -1238,3 +1238,29 @@ func (e *IndeterminateCommitError) Type() ErrorDetailType {
 }
 
 var _ ErrorDetailInterface = &IndeterminateCommitError{}
+
+// NewMinTimestampBoundUnsatisfiableError initializes a new
+// MinTimestampBoundUnsatisfiableError.
+func NewMinTimestampBoundUnsatisfiableError(
+	minTimestampBound, resolvedTimestamp hlc.Timestamp,
+) *MinTimestampBoundUnsatisfiableError {
+	return &MinTimestampBoundUnsatisfiableError{
+		MinTimestampBound: minTimestampBound,
+		ResolvedTimestamp: resolvedTimestamp,
+	}
+}
+
+// SafeFormat implements redact.SafeFormatter.
+func (e *MinTimestampBoundUnsatisfiableError) SafeFormat(s redact.SafePrinter, _ rune) {
+	s.Printf("bounded staleness read with minimum timestamp bound of %s "+
+		"could not be satisfied by a local resolved timestamp of %s",
+		e.MinTimestampBound, e.ResolvedTimestamp)
+}
+
+func (e *MinTimestampBoundUnsatisfiableError) String() string {
+	return redact.StringWithoutMarkers(e)
+}
+
+func (e *MinTimestampBoundUnsatisfiableError) Error() string {
+	return e.String()
+}
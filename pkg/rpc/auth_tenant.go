@@ -52,6 +52,9 @@ func (a tenantAuthorizer) authorize(
 	case "/cockroach.roachpb.Internal/GossipSubscription":
 		return a.authGossipSubscription(tenID, req.(*roachpb.GossipSubscriptionRequest))
 
+	case "/cockroach.roachpb.Internal/TokenBucket":
+		return a.authTokenBucket(tenID, req.(*roachpb.TokenBucketRequest))
+
 	case "/cockroach.rpc.Heartbeat/Ping":
 		return nil // no authorization
 
@@ -157,6 +160,20 @@ func (a tenantAuthorizer) authGossipSubscription(
 	return nil
 }
 
+// authTokenBucket authorizes the provided tenant to invoke the TokenBucket RPC
+// with the provided args.
+func (a tenantAuthorizer) authTokenBucket(
+	tenID roachpb.TenantID, args *roachpb.TokenBucketRequest,
+) error {
+	if args.TenantID == 0 {
+		return authErrorf("token bucket request with unspecified tenant not permitted")
+	}
+	if argTenant := roachpb.MakeTenantID(args.TenantID); argTenant != tenID {
+		return authErrorf("token bucket request for tenant %s not permitted", argTenant)
+	}
+	return nil
+}
+
 // gossipSubscriptionPatternAllowlist contains keys outside of a tenant's
 // keyspace that GossipSubscription RPC invocations are allowed to touch.
 // WIP: can't import gossip directly.
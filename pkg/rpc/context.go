@@ -476,6 +476,13 @@ func (a internalClientAdapter) Join(
 	return a.InternalServer.Join(ctx, req)
 }
 
+// TokenBucket implements the roachpb.InternalClient interface.
+func (a internalClientAdapter) TokenBucket(
+	ctx context.Context, in *roachpb.TokenBucketRequest, _ ...grpc.CallOption,
+) (*roachpb.TokenBucketResponse, error) {
+	return a.InternalServer.TokenBucket(ctx, in)
+}
+
 func (a internalClientAdapter) ResetQuorum(
 	ctx context.Context, req *roachpb.ResetQuorumRequest, _ ...grpc.CallOption,
 ) (*roachpb.ResetQuorumResponse, error) {
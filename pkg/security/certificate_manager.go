@@ -14,10 +14,14 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
@@ -57,6 +61,12 @@ var (
 		Measurement: "Certificate Expiration",
 		Unit:        metric.Unit_TIMESTAMP_SEC,
 	}
+	metaClientExpiration = metric.Metadata{
+		Name:        "security.certificate.expiration.client",
+		Help:        "Earliest expiration of the client certificates in the certificates directory. 0 means no certificate or error.",
+		Measurement: "Certificate Expiration",
+		Unit:        metric.Unit_TIMESTAMP_SEC,
+	}
 	metaUIExpiration = metric.Metadata{
 		Name:        "security.certificate.expiration.ui",
 		Help:        "Expiration for the UI certificate. 0 means no certificate or error.",
@@ -147,6 +157,7 @@ type CertificateMetrics struct {
 	UICAExpiration           *metric.Gauge
 	NodeExpiration           *metric.Gauge
 	NodeClientExpiration     *metric.Gauge
+	ClientExpiration         *metric.Gauge
 	UIExpiration             *metric.Gauge
 	TenantClientCAExpiration *metric.Gauge
 	TenantClientExpiration   *metric.Gauge
@@ -170,6 +181,7 @@ func makeCertificateManager(
 			UICAExpiration:           metric.NewGauge(metaUICAExpiration),
 			NodeExpiration:           metric.NewGauge(metaNodeExpiration),
 			NodeClientExpiration:     metric.NewGauge(metaNodeClientExpiration),
+			ClientExpiration:         metric.NewGauge(metaClientExpiration),
 			UIExpiration:             metric.NewGauge(metaUIExpiration),
 			TenantClientCAExpiration: metric.NewGauge(metaTenantClientCAExpiration),
 			TenantClientExpiration:   metric.NewGauge(metaTenantClientExpiration),
@@ -223,6 +235,21 @@ func (cm *CertificateManager) Metrics() CertificateMetrics {
 	return cm.certMetrics
 }
 
+// certsDirPollInterval is the interval at which the certificates directory is
+// checked for changes. A non-positive value disables polling, in which case
+// certificates are only reloaded on SIGHUP.
+var certsDirPollInterval = envutil.EnvOrDefaultDuration(
+	"COCKROACH_CERTS_DIR_POLL_INTERVAL", 30*time.Second)
+
+// TestingSetCertsDirPollInterval overrides the interval at which newly
+// registered watchers poll the certificates directory. It returns a function
+// restoring the previous value.
+func TestingSetCertsDirPollInterval(interval time.Duration) func() {
+	prev := certsDirPollInterval
+	certsDirPollInterval = interval
+	return func() { certsDirPollInterval = prev }
+}
+
 // RegisterSignalHandler registers a signal handler for SIGHUP, triggering a
 // refresh of the certificates directory on notification.
 func (cm *CertificateManager) RegisterSignalHandler(stopper *stop.Stopper) {
@@ -234,16 +261,78 @@ func (cm *CertificateManager) RegisterSignalHandler(stopper *stop.Stopper) {
 				return
 			case sig := <-ch:
 				log.Infof(context.Background(), "received signal %q, triggering certificate reload", sig)
-				if err := cm.LoadCertificates(); err != nil {
-					log.Warningf(context.Background(), "could not reload certificates: %v", err)
-				} else {
-					log.Info(context.Background(), "successfully reloaded certificates")
+				cm.reloadCertificates(context.Background())
+			}
+		}
+	}()
+}
+
+// RegisterCertsDirWatcher starts polling the certificates directory,
+// triggering a refresh of the certificates whenever a file in it is added,
+// removed or rewritten. This allows certificates to be rotated without
+// signaling every process.
+func (cm *CertificateManager) RegisterCertsDirWatcher(stopper *stop.Stopper) {
+	interval := certsDirPollInterval
+	if interval <= 0 {
+		return
+	}
+	ctx := context.Background()
+	lastState, err := certsDirState(cm.certsDir)
+	if err != nil {
+		log.Warningf(ctx, "could not examine certificates directory: %v", err)
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopper.ShouldStop():
+				return
+			case <-ticker.C:
+				state, err := certsDirState(cm.certsDir)
+				if err != nil {
+					log.Warningf(ctx, "could not examine certificates directory: %v", err)
+					continue
 				}
+				if state == lastState {
+					continue
+				}
+				lastState = state
+				log.Infof(ctx, "certificates directory %s changed, triggering certificate reload", cm.certsDir)
+				cm.reloadCertificates(ctx)
 			}
 		}
 	}()
 }
 
+// reloadCertificates reloads the certificates and logs the outcome.
+func (cm *CertificateManager) reloadCertificates(ctx context.Context) {
+	if err := cm.LoadCertificates(); err != nil {
+		log.Warningf(ctx, "could not reload certificates: %v", err)
+	} else {
+		log.Info(ctx, "successfully reloaded certificates")
+	}
+}
+
+// certsDirState returns a summary of the names, sizes and modification times
+// of the files in the certificates directory. The summary changes whenever a
+// file is added, removed or rewritten. Temporary files, which are written
+// while certificates are rotated, are ignored.
+func certsDirState(certsDir string) (string, error) {
+	infos, err := ioutil.ReadDir(certsDir)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	for _, info := range infos {
+		if strings.HasSuffix(info.Name(), tmpFileExtension) {
+			continue
+		}
+		fmt.Fprintf(&buf, "%s:%d:%d\n", info.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return buf.String(), nil
+}
+
 // A CertsLocator provides locations to certificates.
 type CertsLocator struct {
 	certsDir string // os.ExpandEnv'ed
@@ -585,6 +674,26 @@ func (cm *CertificateManager) updateMetricsLocked() {
 
 	// UI certificate expiration.
 	maybeSetMetric(cm.certMetrics.UIExpiration, cm.uiCert)
+
+	// Client certificates expiration. We report the earliest expiration.
+	if m := cm.certMetrics.ClientExpiration; m != nil {
+		var earliest *CertInfo
+		for _, ci := range cm.clientCerts {
+			if ci.Error != nil {
+				continue
+			}
+			if earliest == nil || ci.ExpirationTime.Before(earliest.ExpirationTime) {
+				earliest = ci
+			}
+		}
+		maybeSetMetric(m, earliest)
+	}
+
+	// Tenant client CA certificate expiration.
+	maybeSetMetric(cm.certMetrics.TenantClientCAExpiration, cm.tenantClientCACert)
+
+	// Tenant client certificate expiration.
+	maybeSetMetric(cm.certMetrics.TenantClientExpiration, cm.tenantClientCert)
 }
 
 // GetServerTLSConfig returns a server TLS config with a callback to fetch the
@@ -11,6 +11,7 @@
 package security
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/rand"
@@ -537,6 +538,81 @@ func WriteTenantClientPair(certsDir string, cp *TenantClientPair, overwrite bool
 	return nil
 }
 
+// tmpFileExtension is the extension of the temporary files written to the
+// certs directory while rotating certificates. The certs directory watcher
+// ignores them.
+const tmpFileExtension = ".tmp"
+
+// RotateCerts re-signs the node and client certificates found in the certs
+// directory using the CA key, keeping their subjects, hosts and keys. Only
+// certificates issued by the CA certificate (ca.crt) are rotated. The new
+// certificates are valid for the specified lifetime and atomically replace
+// the existing files, so that running processes watching the certs directory
+// pick them up on their next reload.
+//
+// Rotated certificates get a new serial number. The serial numbers of the
+// certificates loaded by each node are reported by the certificates status
+// endpoint, which shows which nodes still present old certificates.
+func RotateCerts(certsDir, caKeyPath string, lifetime time.Duration) error {
+	if len(caKeyPath) == 0 {
+		return errors.New("the path to the CA key is required")
+	}
+	if len(certsDir) == 0 {
+		return errors.New("the path to the certs directory is required")
+	}
+
+	// The certificate manager expands the env for the certs directory.
+	// For consistency, we need to do this for the key as well.
+	caKeyPath = os.ExpandEnv(caKeyPath)
+	cl := MakeCertsLocator(certsDir)
+
+	// Load the CA pair.
+	caCert, caPrivateKey, err := loadCACertAndKey(cl.CACertPath(), caKeyPath)
+	if err != nil {
+		return err
+	}
+
+	loader := NewCertificateLoader(cl.certsDir)
+	if err := loader.Load(); err != nil {
+		return err
+	}
+
+	for _, ci := range loader.Certificates() {
+		if ci.FileUsage != NodePem && ci.FileUsage != ClientPem {
+			continue
+		}
+		certPath := filepath.Join(cl.certsDir, ci.Filename)
+		if ci.Error != nil {
+			log.Warningf(context.Background(), "skipping certificate %s: %v", certPath, ci.Error)
+			continue
+		}
+		cert := ci.ParsedCertificates[0]
+		if !bytes.Equal(cert.RawIssuer, caCert.RawSubject) {
+			log.Infof(context.Background(), "skipping certificate %s: not issued by %s",
+				certPath, cl.CACertPath())
+			continue
+		}
+
+		newCert, err := RenewCert(caCert, caPrivateKey, cert, lifetime)
+		if err != nil {
+			return errors.Errorf("error renewing certificate %s: %v", certPath, err)
+		}
+
+		// Write the new certificate to a temporary file first so that processes
+		// reloading certificates never observe a partially written file.
+		tmpPath := certPath + tmpFileExtension
+		if err := writeCertificateToFile(tmpPath, newCert, true /* overwrite */); err != nil {
+			return errors.Errorf("error writing certificate to %s: %v", tmpPath, err)
+		}
+		if err := os.Rename(tmpPath, certPath); err != nil {
+			return errors.Errorf("error replacing certificate %s: %v", certPath, err)
+		}
+		log.Infof(context.Background(), "rotated certificate: %s", certPath)
+	}
+
+	return nil
+}
+
 // PEMContentsToX509 takes raw pem-encoded contents and attempts to parse into
 // x509.Certificate objects.
 func PEMContentsToX509(contents []byte) ([]*x509.Certificate, error) {
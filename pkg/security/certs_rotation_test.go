@@ -40,6 +40,8 @@ func TestRotateCerts(t *testing.T) {
 	// Do not mock cert access for this test.
 	security.ResetAssetLoader()
 	defer ResetTest()
+	// Certificates must only be reloaded upon SIGHUP in this test.
+	defer security.TestingSetCertsDirPollInterval(0)()
 	certsDir, err := ioutil.TempDir("", "certs_test")
 	if err != nil {
 		t.Fatal(err)
@@ -12,6 +12,7 @@ package security_test
 
 import (
 	"context"
+	"crypto/x509"
 	gosql "database/sql"
 	"fmt"
 	"io/ioutil"
@@ -28,6 +29,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
 )
@@ -213,6 +216,136 @@ func TestGenerateNodeCerts(t *testing.T) {
 	}
 }
 
+func TestRotateNodeAndClientCerts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	// Do not mock cert access for this test.
+	security.ResetAssetLoader()
+	defer ResetTest()
+
+	certsDir, cleanup := tempDir(t)
+	defer cleanup()
+
+	if err := generateBaseCerts(certsDir); err != nil {
+		t.Fatal(err)
+	}
+
+	loadCert := func(filename string) *x509.Certificate {
+		contents, err := ioutil.ReadFile(filepath.Join(certsDir, filename))
+		require.NoError(t, err)
+		certs, err := security.PEMContentsToX509(contents)
+		require.NoError(t, err)
+		return certs[0]
+	}
+	readFile := func(filename string) []byte {
+		contents, err := ioutil.ReadFile(filepath.Join(certsDir, filename))
+		require.NoError(t, err)
+		return contents
+	}
+
+	// Watch the certs directory to verify that rotated certificates are
+	// picked up without a SIGHUP.
+	defer security.TestingSetCertsDirPollInterval(10 * time.Millisecond)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.Background())
+	cm, err := security.NewCertificateManager(certsDir, security.CommandTLSSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cm.RegisterCertsDirWatcher(stopper)
+
+	files := []string{"node", "client.root"}
+	oldCerts := make(map[string]*x509.Certificate)
+	oldKeys := make(map[string][]byte)
+	for _, f := range files {
+		oldCerts[f] = loadCert(f + ".crt")
+		oldKeys[f] = readFile(f + ".key")
+	}
+
+	// Rotating with a lifetime exceeding the CA's fails.
+	err = security.RotateCerts(certsDir, filepath.Join(certsDir, security.EmbeddedCAKey), time.Hour*200)
+	if !testutils.IsError(err, "CA lifetime is .*, shorter than the requested .*") {
+		t.Fatalf("expected CA lifetime error, got %v", err)
+	}
+
+	require.NoError(t, security.RotateCerts(
+		certsDir, filepath.Join(certsDir, security.EmbeddedCAKey), time.Hour*24))
+
+	for _, f := range files {
+		oldCert, newCert := oldCerts[f], loadCert(f+".crt")
+		require.NotEqual(t, oldCert.SerialNumber, newCert.SerialNumber, f)
+		require.Equal(t, oldCert.Subject.String(), newCert.Subject.String(), f)
+		require.Equal(t, oldCert.DNSNames, newCert.DNSNames, f)
+		require.Equal(t, oldCert.IPAddresses, newCert.IPAddresses, f)
+		require.Equal(t, oldCert.ExtKeyUsage, newCert.ExtKeyUsage, f)
+		require.Equal(t, oldCert.PublicKey, newCert.PublicKey, f)
+		require.True(t, newCert.NotAfter.Before(oldCert.NotAfter), f)
+		// Keys are left untouched.
+		require.Equal(t, oldKeys[f], readFile(f+".key"), f)
+	}
+
+	// The rotated certificates are reloaded and reflected in the metrics.
+	testutils.SucceedsSoon(t, func() error {
+		if a, e := cm.Metrics().ClientExpiration.Value(), loadCert("client.root.crt").NotAfter.Unix(); a != e {
+			return errors.Errorf("expected client cert expiration %d, got %d", e, a)
+		}
+		if a, e := cm.Metrics().NodeExpiration.Value(), loadCert("node.crt").NotAfter.Unix(); a != e {
+			return errors.Errorf("expected node cert expiration %d, got %d", e, a)
+		}
+		return nil
+	})
+}
+
+// TestCertificateExpirationMetrics verifies that the expiration of each kind of
+// certificate is reported by its own metric.
+func TestCertificateExpirationMetrics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	// Do not mock cert access for this test.
+	security.ResetAssetLoader()
+	defer ResetTest()
+
+	certsDir, cleanup := tempDir(t)
+	defer cleanup()
+
+	if err := generateSplitCACerts(certsDir); err != nil {
+		t.Fatal(err)
+	}
+	cm, err := security.NewCertificateManager(
+		certsDir, security.CommandTLSSettings{}, security.ForTenant(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loadCert := func(filename string) *x509.Certificate {
+		contents, err := ioutil.ReadFile(filepath.Join(certsDir, filename))
+		require.NoError(t, err)
+		certs, err := security.PEMContentsToX509(contents)
+		require.NoError(t, err)
+		return certs[0]
+	}
+	m := cm.Metrics()
+	for _, tc := range []struct {
+		gauge    *metric.Gauge
+		filename string
+	}{
+		{m.CAExpiration, "ca.crt"},
+		{m.ClientCAExpiration, "ca-client.crt"},
+		{m.UICAExpiration, "ca-ui.crt"},
+		{m.NodeExpiration, "node.crt"},
+		{m.NodeClientExpiration, "client.node.crt"},
+		{m.UIExpiration, "ui.crt"},
+		{m.TenantClientCAExpiration, "ca-client-tenant.crt"},
+		{m.TenantClientExpiration, "client-tenant.10.crt"},
+	} {
+		require.Equal(t, loadCert(tc.filename).NotAfter.Unix(), tc.gauge.Value(), tc.filename)
+	}
+	// The client certificates report the earliest expiration.
+	expClient := loadCert("client.root.crt").NotAfter
+	if nodeClient := loadCert("client.node.crt").NotAfter; nodeClient.Before(expClient) {
+		expClient = nodeClient
+	}
+	require.Equal(t, expClient.Unix(), m.ClientExpiration.Value())
+}
+
 // Generate basic certs:
 // ca.crt: CA certificate
 // node.crt: dual-purpose node certificate
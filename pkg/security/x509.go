@@ -273,3 +273,37 @@ func GenerateClientCert(
 
 	return certBytes, nil
 }
+
+// RenewCert generates a new certificate for the same subject, hosts, key usages
+// and public key as the passed-in certificate, signed by the CA and valid for
+// the requested lifetime. It returns the DER-encoded certificate.
+func RenewCert(
+	caCert *x509.Certificate,
+	caPrivateKey crypto.PrivateKey,
+	cert *x509.Certificate,
+	lifetime time.Duration,
+) ([]byte, error) {
+	template, err := newTemplate(cert.Subject.CommonName, lifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't issue certificates that outlast the CA cert.
+	if err := checkLifetimeAgainstCA(template, caCert); err != nil {
+		return nil, err
+	}
+
+	// Carry over the identity and usages of the existing certificate.
+	template.Subject = cert.Subject
+	template.KeyUsage = cert.KeyUsage
+	template.ExtKeyUsage = cert.ExtKeyUsage
+	template.DNSNames = cert.DNSNames
+	template.IPAddresses = cert.IPAddresses
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, caCert, cert.PublicKey, caPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return certBytes, nil
+}
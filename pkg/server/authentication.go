@@ -185,7 +185,7 @@ func (s *authenticationServer) UserLoginFromSSO(
 	// without further normalization.
 	username, _ := security.MakeSQLUsernameFromUserInput(reqUsername, security.UsernameValidation)
 
-	exists, canLogin, _, _, err := sql.GetUserHashedPassword(
+	exists, canLogin, _, _, _, err := sql.GetUserHashedPassword(
 		ctx, s.server.sqlServer.execCfg.InternalExecutor, username,
 	)
 
@@ -344,7 +344,7 @@ WHERE id = $1`
 func (s *authenticationServer) verifyPassword(
 	ctx context.Context, username security.SQLUsername, password string,
 ) (valid bool, expired bool, err error) {
-	exists, canLogin, pwRetrieveFn, validUntilFn, err := sql.GetUserHashedPassword(
+	exists, canLogin, pwRetrieveFn, validUntilFn, _, err := sql.GetUserHashedPassword(
 		ctx, s.server.sqlServer.execCfg.InternalExecutor, username,
 	)
 	if err != nil {
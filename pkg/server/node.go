@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvtenant"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/server/status"
@@ -38,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/bootstrap"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -50,6 +52,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/logtags"
+	"github.com/cockroachdb/pebble"
 	"github.com/cockroachdb/redact"
 	"google.golang.org/grpc/codes"
 	grpcstatus "google.golang.org/grpc/status"
@@ -168,6 +171,13 @@ type Node struct {
 	additionalStoreInitCh chan struct{}
 
 	perReplicaServer kvserver.Server
+
+	// admissionQ is the admission queue for KV work received by this node.
+	admissionQ *admission.WorkQueue
+
+	// tenantUsage is used to account for and control the resource usage of
+	// tenants through the TokenBucket API.
+	tenantUsage multitenant.TenantUsageServer
 }
 
 var _ roachpb.InternalServer = &Node{}
@@ -283,6 +293,8 @@ func NewNode(
 	txnMetrics kvcoord.TxnMetrics,
 	execCfg *sql.ExecutorConfig,
 	clusterID *base.ClusterIDContainer,
+	kvAdmissionQ *admission.WorkQueue,
+	tenantUsage multitenant.TenantUsageServer,
 ) *Node {
 	var eventLogger sql.EventLogger
 	if execCfg != nil {
@@ -297,11 +309,30 @@ func NewNode(
 		txnMetrics:  txnMetrics,
 		eventLogger: eventLogger,
 		clusterID:   clusterID,
+		admissionQ:  kvAdmissionQ,
+		tenantUsage: tenantUsage,
 	}
 	n.perReplicaServer = kvserver.MakeServer(&n.Descriptor, n.stores)
 	return n
 }
 
+// GetPebbleMetrics implements admission.PebbleMetricsProvider.
+func (n *Node) GetPebbleMetrics() []*pebble.Metrics {
+	var metrics []*pebble.Metrics
+	_ = n.stores.VisitStores(func(store *kvserver.Store) error {
+		m, err := store.Engine().GetMetrics()
+		if err != nil {
+			log.Warningf(context.Background(), "%s: unable to get metrics: %v", store, err)
+			return nil
+		}
+		if m.Pebble != nil {
+			metrics = append(metrics, m.Pebble)
+		}
+		return nil
+	})
+	return metrics
+}
+
 // InitLogger needs to be called if a nil execCfg was passed to NewNode().
 func (n *Node) InitLogger(execCfg *sql.ExecutorConfig) {
 	n.eventLogger = sql.MakeEventLogger(execCfg)
@@ -845,6 +876,28 @@ func (n *Node) batchInternal(
 			log.Eventf(ctx, "node received request: %s", args.Summary())
 		}
 
+		if n.admissionQ != nil {
+			tenantID, ok := roachpb.TenantFromContext(ctx)
+			if !ok {
+				tenantID = roachpb.SystemTenantID
+			}
+			admissionInfo := admission.WorkInfo{
+				TenantID:   tenantID,
+				Priority:   admission.WorkPriority(args.AdmissionHeader.Priority),
+				CreateTime: args.AdmissionHeader.CreateTime,
+				// Work that was not tagged with its source, such as the
+				// internal requests of the system, must not be delayed.
+				BypassAdmission: args.AdmissionHeader.Source == roachpb.AdmissionHeader_OTHER,
+			}
+			enabled, err := n.admissionQ.Admit(ctx, admissionInfo)
+			if err != nil {
+				return err
+			}
+			if enabled {
+				defer n.admissionQ.AdmittedWorkDone(tenantID)
+			}
+		}
+
 		tStart := timeutil.Now()
 		var pErr *roachpb.Error
 		br, pErr = n.stores.Send(ctx, *args)
@@ -1241,3 +1294,23 @@ func (n *Node) Join(
 		ActiveVersion: &activeVersion.Version,
 	}, nil
 }
+
+// TokenBucket is part of the roachpb.InternalServer service.
+func (n *Node) TokenBucket(
+	ctx context.Context, in *roachpb.TokenBucketRequest,
+) (*roachpb.TokenBucketResponse, error) {
+	// Check tenant ID. Note that in production configuration, the tenant ID has
+	// already been checked in the RPC layer (see rpc.tenantAuthorizer).
+	if in.TenantID == 0 || in.TenantID == roachpb.SystemTenantID.ToUint64() {
+		return &roachpb.TokenBucketResponse{
+			Error: roachpb.NewError(errors.Errorf("token bucket request with invalid tenant ID %d", in.TenantID)),
+		}, nil
+	}
+	if !n.storeCfg.Settings.Version.IsActive(ctx, clusterversion.TenantUsageTable) {
+		return &roachpb.TokenBucketResponse{
+			Error: roachpb.NewError(errors.New("token bucket requests are not supported until upgrade is finalized")),
+		}, nil
+	}
+	tenantID := roachpb.MakeTenantID(in.TenantID)
+	return n.tenantUsage.TokenBucketRequest(ctx, tenantID, in), nil
+}
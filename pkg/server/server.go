@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprotectedts"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
@@ -46,6 +47,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptprovider"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptreconcile"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/reports"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostserver"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/rpc/nodedialer"
@@ -59,6 +61,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/contention"
 	_ "github.com/cockroachdb/cockroach/pkg/sql/gcjob" // register jobs declared outside of pkg/sql
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
@@ -69,6 +72,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ts"
 	"github.com/cockroachdb/cockroach/pkg/ui"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
@@ -138,6 +142,9 @@ type Server struct {
 	registry     *metric.Registry
 	recorder     *status.MetricsRecorder
 	runtime      *status.RuntimeStatSampler
+	// gcoord coordinates admission control for the KV work and the SQL
+	// processing of KV responses on this node.
+	gcoord *admission.GrantCoordinator
 
 	admin           *adminServer
 	status          *statusServer
@@ -326,6 +333,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 			return nil, err
 		}
 		cm.RegisterSignalHandler(stopper)
+		cm.RegisterCertsDirWatcher(stopper)
 		registry.AddMetricStruct(cm.Metrics())
 	}
 
@@ -406,6 +414,13 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	dbCtx.Stopper = stopper
 	db := kv.NewDBWithContext(cfg.AmbientCtx, tcsFactory, clock, dbCtx)
 
+	gcoord := admission.NewGrantCoordinator(st, cfg.HistogramWindowInterval())
+	for _, m := range gcoord.Metrics() {
+		registry.AddMetricStruct(m)
+	}
+	stopper.AddCloser(gcoord)
+	db.SQLKVResponseAdmissionQ = gcoord.GetWorkQueue(admission.SQLKVResponseWork)
+
 	nlActive, nlRenewal := cfg.NodeLivenessDurations()
 	if knobs := cfg.TestingKnobs.NodeLiveness; knobs != nil {
 		nlKnobs := knobs.(kvserver.NodeLivenessTestingKnobs)
@@ -519,9 +534,10 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		// be ready until it is .Start()ed, but the grpc server can be
 		// registered early.
 		ClosedTimestamp: container.NewContainer(container.Config{
-			Settings: st,
-			Stopper:  stopper,
-			Clock:    nodeLiveness.AsLiveClock(),
+			Settings:  st,
+			Stopper:   stopper,
+			Clock:     nodeLiveness.AsLiveClock(),
+			MaxOffset: clock.MaxOffset(),
 			// NB: s.node is not defined at this point, but it will be
 			// before this is ever called.
 			Refresh: func(rangeIDs ...roachpb.RangeID) {
@@ -547,9 +563,13 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	recorder := status.NewMetricsRecorder(clock, nodeLiveness, rpcContext, g, st)
 	registry.AddMetricStruct(rpcContext.RemoteClocks.Metrics())
 
+	tenantUsage := tenantcostserver.NewInstance(db, internalExecutor, timeutil.DefaultTimeSource{})
+	registry.AddMetricStruct(tenantUsage.Metrics())
+
 	node := NewNode(
 		storeCfg, recorder, registry, stopper,
-		txnMetrics, nil /* execCfg */, &rpcContext.ClusterID)
+		txnMetrics, nil /* execCfg */, &rpcContext.ClusterID,
+		gcoord.GetWorkQueue(admission.KVWork), tenantUsage)
 	lateBoundNode = node
 	roachpb.RegisterInternalServer(grpcServer.Server, node)
 	kvserver.RegisterPerReplicaServer(grpcServer.Server, node.perReplicaServer)
@@ -573,6 +593,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	// TODO(tbg): give adminServer only what it needs (and avoid circular deps).
 	sAdmin := newAdminServer(lateBoundServer, internalExecutor)
 	sessionRegistry := sql.NewSessionRegistry()
+	contentionRegistry := contention.NewRegistry(keys.SystemSQLCodec)
 
 	sStatus := newStatusServer(
 		cfg.AmbientCtx,
@@ -588,6 +609,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		node.stores,
 		stopper,
 		sessionRegistry,
+		contentionRegistry,
 		internalExecutor,
 	)
 	// TODO(tbg): don't pass all of Server into this to avoid this hack.
@@ -617,6 +639,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 			externalStorage:        externalStorage,
 			externalStorageFromURI: externalStorageFromURI,
 			isMeta1Leaseholder:     node.stores.IsMeta1Leaseholder,
+			tenantUsageServer:      tenantUsage,
 		},
 		SQLConfig:                &cfg.SQLConfig,
 		BaseConfig:               &cfg.BaseConfig,
@@ -632,6 +655,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		registry:                 registry,
 		recorder:                 recorder,
 		sessionRegistry:          sessionRegistry,
+		contentionRegistry:       contentionRegistry,
 		circularInternalExecutor: internalExecutor,
 		circularJobRegistry:      jobRegistry,
 		jobAdoptionStopFile:      jobAdoptionStopFile,
@@ -664,6 +688,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		registry:               registry,
 		recorder:               recorder,
 		runtime:                runtimeSampler,
+		gcoord:                 gcoord,
 		admin:                  sAdmin,
 		status:                 sStatus,
 		authentication:         sAuth,
@@ -1520,6 +1545,9 @@ func (s *Server) PreStart(ctx context.Context) error {
 	}
 
 	log.Event(ctx, "started node")
+	// Now that the stores are started, admission control can react to the
+	// health of their LSMs.
+	s.gcoord.SetPebbleMetricsProvider(s.node)
 	if err := s.startPersistingHLCUpperBound(
 		ctx,
 		hlcUpperBound > 0,
@@ -13,9 +13,11 @@ package server
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"google.golang.org/grpc/codes"
@@ -119,3 +121,152 @@ func (s *statusServer) StatementsLocal() (*serverpb.StatementsResponse, error) {
 
 	return resp, nil
 }
+
+// CombinedStatementStats returns the statement and transaction statistics
+// persisted in the system.statement_statistics and
+// system.transaction_statistics tables whose aggregation interval starts in
+// the requested range. If the range includes the current aggregation
+// interval, the statistics held in memory by all nodes, which have not been
+// flushed yet, are merged in. The statistics of each node are aggregated over
+// the whole range.
+func (s *statusServer) CombinedStatementStats(
+	ctx context.Context, req *serverpb.CombinedStatementsStatsRequest,
+) (*serverpb.StatementsResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	if _, err := s.privilegeChecker.requireViewActivityPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	var start, end time.Time
+	if req.Start != 0 {
+		start = timeutil.Unix(req.Start, 0)
+	}
+	if req.End != 0 {
+		end = timeutil.Unix(req.End, 0)
+	}
+
+	response := &serverpb.StatementsResponse{
+		Statements:            []serverpb.StatementsResponse_CollectedStatementStatistics{},
+		LastReset:             timeutil.Now(),
+		InternalAppNamePrefix: catconstants.InternalAppNamePrefix,
+	}
+	aggregatedTs := timeutil.Now().Truncate(sql.SQLStatsAggregationInterval.Get(&s.st.SV))
+	if (start.IsZero() || !aggregatedTs.Before(start)) && (end.IsZero() || aggregatedTs.Before(end)) {
+		inMemory, err := s.Statements(ctx, &serverpb.StatementsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		response.Statements = append(response.Statements, inMemory.Statements...)
+		response.Transactions = append(response.Transactions, inMemory.Transactions...)
+		response.LastReset = inMemory.LastReset
+	}
+
+	stmts, err := sql.ReadPersistedStatementStatistics(ctx, s.internalExecutor, nil /* txn */, start, end)
+	if err != nil {
+		return nil, err
+	}
+	type stmtKey struct {
+		nodeID roachpb.NodeID
+		app    string
+		id     roachpb.StmtID
+	}
+	stmtIdx := make(map[stmtKey]int, len(response.Statements)+len(stmts))
+	for i, stmt := range response.Statements {
+		stmtIdx[stmtKey{stmt.Key.NodeID, stmt.Key.KeyData.App, stmt.ID}] = i
+	}
+	for _, stmt := range stmts {
+		if stmt.AggregatedTs.Before(response.LastReset) {
+			response.LastReset = stmt.AggregatedTs
+		}
+		k := stmtKey{roachpb.NodeID(stmt.NodeID), stmt.Key.App, stmt.ID}
+		if i, ok := stmtIdx[k]; ok {
+			response.Statements[i].Stats.Add(&stmt.Stats)
+			continue
+		}
+		stmtIdx[k] = len(response.Statements)
+		response.Statements = append(response.Statements, serverpb.StatementsResponse_CollectedStatementStatistics{
+			Key: serverpb.StatementsResponse_ExtendedStatementStatisticsKey{
+				KeyData: stmt.Key,
+				NodeID:  k.nodeID,
+			},
+			ID:    stmt.ID,
+			Stats: stmt.Stats,
+		})
+	}
+
+	txns, err := sql.ReadPersistedTransactionStatistics(ctx, s.internalExecutor, nil /* txn */, start, end)
+	if err != nil {
+		return nil, err
+	}
+	type txnKey struct {
+		nodeID roachpb.NodeID
+		app    string
+		id     roachpb.TransactionFingerprintID
+	}
+	txnIdx := make(map[txnKey]int, len(response.Transactions)+len(txns))
+	for i, txn := range response.Transactions {
+		txnIdx[txnKey{txn.NodeID, txn.StatsData.App, txn.StatsData.TransactionFingerprintID}] = i
+	}
+	for _, txn := range txns {
+		k := txnKey{roachpb.NodeID(txn.NodeID), txn.App, txn.TransactionFingerprintID}
+		if i, ok := txnIdx[k]; ok {
+			response.Transactions[i].StatsData.Stats.Add(&txn.Stats)
+			continue
+		}
+		txnIdx[k] = len(response.Transactions)
+		response.Transactions = append(response.Transactions, serverpb.StatementsResponse_ExtendedCollectedTransactionStatistics{
+			StatsData: txn.CollectedTransactionStatistics,
+			NodeID:    k.nodeID,
+		})
+	}
+
+	return response, nil
+}
+
+// IndexRecommendations returns the index recommendations made for the
+// statements in the combined statement statistics of the cluster, grouped by
+// recommendation.
+func (s *statusServer) IndexRecommendations(
+	ctx context.Context, req *serverpb.IndexRecommendationsRequest,
+) (*serverpb.IndexRecommendationsResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	if _, err := s.privilegeChecker.requireViewActivityPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	stmtStats, err := s.CombinedStatementStats(ctx, &serverpb.CombinedStatementsStatsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	collected := make([]roachpb.CollectedStatementStatistics, len(stmtStats.Statements))
+	for i, stmt := range stmtStats.Statements {
+		collected[i] = roachpb.CollectedStatementStatistics{
+			ID:    stmt.ID,
+			Key:   stmt.Key.KeyData,
+			Stats: stmt.Stats,
+		}
+	}
+	recs, err := sql.AggregateIndexRecommendations(collected)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &serverpb.IndexRecommendationsResponse{
+		Recommendations: make([]serverpb.IndexRecommendation, len(recs)),
+	}
+	for i, r := range recs {
+		response.Recommendations[i] = serverpb.IndexRecommendation{
+			Recommendation:  r.Recommendation,
+			TableName:       r.TableName,
+			StatementCount:  r.StatementCount,
+			ExecutionCount:  r.ExecutionCount,
+			LastRecommended: r.LastRecommended,
+			Fingerprints:    r.Fingerprints,
+		}
+	}
+	return response, nil
+}
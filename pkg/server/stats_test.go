@@ -243,3 +243,181 @@ func TestSQLStatCollection(t *testing.T) {
 		t.Fatal("expected to find stats for insert query in reported pool, but didn't")
 	}
 }
+
+func TestSQLStatsFlush(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	params.Settings = cluster.MakeClusterSettings()
+	// Disable the periodic flush so that the test controls when the
+	// statistics are written to disk.
+	sql.SQLStatsFlushEnabled.Override(&params.Settings.SV, false)
+	s, sqlDB, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+
+	sqlServer := s.(*TestServer).Server.sqlServer.pgServer.SQLServer
+	statusServer := s.(*TestServer).status
+	sqlServer.ResetSQLStats(ctx)
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `
+SET application_name = 'flush_test';
+CREATE DATABASE t;
+CREATE TABLE t.test (x INT PRIMARY KEY);
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	// queryCounts returns the number of executions of the INSERT statement
+	// and of the transactions consisting of only that statement, combining
+	// the persisted and the in-memory statistics.
+	queryCounts := func() (stmtCount, txnCount int) {
+		if err := conn.QueryRowContext(ctx, `
+WITH stmts AS (
+  SELECT * FROM crdb_internal.statement_statistics
+  WHERE app_name = 'flush_test' AND metadata->>'query' LIKE 'INSERT INTO t.test%'
+)
+SELECT
+  (SELECT sum((statistics->>'count')::INT8) FROM stmts),
+  (SELECT sum((statistics->>'count')::INT8) FROM crdb_internal.transaction_statistics
+   WHERE app_name = 'flush_test'
+   AND jsonb_array_length(metadata->'stmtFingerprintIDs') = 1
+   AND metadata->'stmtFingerprintIDs'->>0 IN (SELECT encode(fingerprint_id, 'hex') FROM stmts))`,
+		).Scan(&stmtCount, &txnCount); err != nil {
+			t.Fatal(err)
+		}
+		return stmtCount, txnCount
+	}
+	insert := func(n int) {
+		for i := 0; i < n; i++ {
+			if _, err := conn.ExecContext(ctx, `INSERT INTO t.test VALUES ($1)`, timeutil.Now().UnixNano()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	insert(2)
+	if err := sqlServer.FlushSQLStats(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// The flushed statistics are no longer held in memory.
+	for _, stat := range sqlServer.GetUnscrubbedStmtStats() {
+		if strings.HasPrefix(stat.Key.Query, "INSERT INTO t.test VALUES") {
+			t.Fatal("expected the insert statistics to be flushed, but found them in memory")
+		}
+	}
+	var numRows int
+	if err := conn.QueryRowContext(ctx,
+		`SELECT count(*) FROM system.statement_statistics WHERE app_name = 'flush_test'`,
+	).Scan(&numRows); err != nil {
+		t.Fatal(err)
+	}
+	if numRows == 0 {
+		t.Fatal("expected to find persisted statement statistics, but didn't")
+	}
+
+	// A second flush within the same aggregation interval is merged with the
+	// previously persisted statistics, and the virtual tables combine the
+	// persisted statistics with the statistics still held in memory.
+	insert(3)
+	if err := sqlServer.FlushSQLStats(ctx); err != nil {
+		t.Fatal(err)
+	}
+	insert(1)
+	if stmtCount, txnCount := queryCounts(); stmtCount != 6 || txnCount != 6 {
+		t.Fatalf("expected 6 statement and transaction executions, found %d and %d",
+			stmtCount, txnCount)
+	}
+
+	// The status endpoint reports the same combined statistics.
+	resp, err := statusServer.CombinedStatementStats(ctx, &serverpb.CombinedStatementsStatsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	for _, stmt := range resp.Statements {
+		if stmt.Key.KeyData.App == "flush_test" &&
+			strings.HasPrefix(stmt.Key.KeyData.Query, "INSERT INTO t.test VALUES") {
+			count += stmt.Stats.Count
+		}
+	}
+	if count != 6 {
+		t.Fatalf("expected 6 executions of the insert statement, found %d", count)
+	}
+	// Restricting the range to before the current aggregation interval
+	// excludes all of the statistics.
+	resp, err = statusServer.CombinedStatementStats(ctx, &serverpb.CombinedStatementsStatsRequest{
+		End: timeutil.Now().Add(-2 * sql.SQLStatsAggregationInterval.Get(&params.Settings.SV)).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range resp.Statements {
+		if stmt.Key.KeyData.App == "flush_test" {
+			t.Fatalf("expected no statistics, found %+v", stmt)
+		}
+	}
+}
+
+func TestIndexRecommendations(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	s, sqlDB, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+
+	statusServer := s.(*TestServer).status
+	if _, err := sqlDB.Exec(`
+CREATE DATABASE t;
+CREATE TABLE t.test (k INT PRIMARY KEY, v INT);
+`); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := sqlDB.Exec(`SELECT k FROM t.test WHERE v = $1`, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const expected = `CREATE INDEX ON t.public.test (v)`
+	var tableName string
+	var stmtCount, execCount int
+	if err := sqlDB.QueryRow(`
+SELECT table_name, statement_count, execution_count
+FROM crdb_internal.index_recommendations WHERE recommendation = $1`, expected,
+	).Scan(&tableName, &stmtCount, &execCount); err != nil {
+		t.Fatal(err)
+	}
+	if tableName != "t.public.test" || stmtCount != 1 || execCount != 3 {
+		t.Fatalf("unexpected recommendation: table %s, %d statements, %d executions",
+			tableName, stmtCount, execCount)
+	}
+
+	// The status endpoint reports the same recommendation.
+	resp, err := statusServer.IndexRecommendations(ctx, &serverpb.IndexRecommendationsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, rec := range resp.Recommendations {
+		if rec.Recommendation != expected {
+			continue
+		}
+		found = true
+		if rec.ExecutionCount != 3 || len(rec.Fingerprints) != 1 ||
+			rec.Fingerprints[0] != "SELECT k FROM t.test WHERE v = $1" {
+			t.Fatalf("unexpected recommendation: %+v", rec)
+		}
+	}
+	if !found {
+		t.Fatalf("expected to find %q in %+v", expected, resp.Recommendations)
+	}
+}
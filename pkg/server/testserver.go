@@ -35,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptprovider"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostclient"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/rpc/nodedialer"
@@ -45,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/contention"
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
@@ -467,6 +469,12 @@ func makeSQLServerArgs(
 	resolver := kvtenant.AddressResolver(tenantConnect)
 	nodeDialer := nodedialer.New(rpcContext, resolver)
 
+	const sqlInstanceID = base.SQLInstanceID(10001)
+	costController := tenantcostclient.NewTenantSideCostController(
+		st, sqlCfg.TenantID, sqlInstanceID, tenantConnect, timeutil.DefaultTimeSource{},
+	)
+	registry.AddMetricStruct(costController.Metrics())
+
 	dsCfg := kvcoord.DistSenderConfig{
 		AmbientCtx:        baseCfg.AmbientCtx,
 		Settings:          st,
@@ -476,6 +484,7 @@ func makeSQLServerArgs(
 		RPCContext:        rpcContext,
 		NodeDialer:        nodeDialer,
 		RangeDescriptorDB: tenantConnect,
+		KVInterceptor:     costController,
 		TestingKnobs:      dsKnobs,
 	}
 	ds := kvcoord.NewDistSender(dsCfg)
@@ -520,7 +529,6 @@ func makeSQLServerArgs(
 
 	recorder := status.NewMetricsRecorder(clock, nil, rpcContext, nil, st)
 
-	const sqlInstanceID = base.SQLInstanceID(10001)
 	idContainer := base.NewSQLIDContainer(sqlInstanceID, nil /* nodeID */)
 
 	runtime := status.NewRuntimeStatSampler(context.Background(), clock)
@@ -543,6 +551,7 @@ func makeSQLServerArgs(
 	// writing): the blob service and DistSQL.
 	dummyRPCServer := grpc.NewServer()
 	sessionRegistry := sql.NewSessionRegistry()
+	contentionRegistry := contention.NewRegistry(keys.MakeSQLCodec(sqlCfg.TenantID))
 	return sqlServerArgs{
 		sqlServerOptionalKVArgs: sqlServerOptionalKVArgs{
 			nodesStatusServer: serverpb.MakeOptionalNodesStatusServer(nil),
@@ -557,7 +566,8 @@ func makeSQLServerArgs(
 			externalStorageFromURI: externalStorageFromURI,
 		},
 		sqlServerOptionalTenantArgs: sqlServerOptionalTenantArgs{
-			tenantConnect: tenantConnect,
+			tenantConnect:  tenantConnect,
+			costController: costController,
 		},
 		SQLConfig:                &sqlCfg,
 		BaseConfig:               &baseCfg,
@@ -573,11 +583,13 @@ func makeSQLServerArgs(
 		registry:                 registry,
 		recorder:                 recorder,
 		sessionRegistry:          sessionRegistry,
+		contentionRegistry:       contentionRegistry,
 		circularInternalExecutor: circularInternalExecutor,
 		circularJobRegistry:      &jobs.Registry{},
 		protectedtsProvider:      protectedTSProvider,
 		sqlStatusServer: newTenantStatusServer(
-			baseCfg.AmbientCtx, &adminPrivilegeChecker{ie: circularInternalExecutor}, sessionRegistry, baseCfg.Settings,
+			baseCfg.AmbientCtx, &adminPrivilegeChecker{ie: circularInternalExecutor},
+			sessionRegistry, contentionRegistry, baseCfg.Settings,
 		),
 	}, nil
 }
@@ -639,6 +651,15 @@ func StartTenant(
 		return "", "", err
 	}
 
+	// Start the cost controller, which accounts for the resource usage of the
+	// tenant and throttles it once it has exhausted its budget.
+	cpuSecsFn := func(context.Context) float64 {
+		return float64(args.runtime.CPUUserNS.Value()+args.runtime.CPUSysNS.Value()) / 1e9
+	}
+	if err := args.costController.Start(ctx, args.stopper, cpuSecsFn); err != nil {
+		return "", "", err
+	}
+
 	// TODO(asubiotto): remove this. Right now it is needed to initialize the
 	// SpanResolver.
 	s.execCfg.DistSQLPlanner.SetNodeInfo(roachpb.NodeDescriptor{NodeID: 0})
@@ -380,6 +380,9 @@ func anonymizeZoneConfig(dst *zonepb.ZoneConfig, src zonepb.ZoneConfig, secret s
 	if src.NumReplicas != nil {
 		dst.NumReplicas = proto.Int32(*src.NumReplicas)
 	}
+	if src.GlobalReads != nil {
+		dst.GlobalReads = proto.Bool(*src.GlobalReads)
+	}
 	dst.Constraints = make([]zonepb.ConstraintsConjunction, len(src.Constraints))
 	for i := range src.Constraints {
 		dst.Constraints[i].NumReplicas = src.Constraints[i].NumReplicas
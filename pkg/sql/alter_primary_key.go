@@ -28,8 +28,44 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
+// alterPrimaryKeyLocalitySwap contains the metadata required to convert a
+// table to or from REGIONAL BY ROW as part of a primary key change.
+type alterPrimaryKeyLocalitySwap struct {
+	// localityConfigSwap is applied to the table when the primary index is
+	// swapped.
+	localityConfigSwap descpb.PrimaryKeySwap_LocalityConfigSwap
+	// regionColumn is the name of the column which stores the region of each
+	// row of a REGIONAL BY ROW table.
+	regionColumn tree.Name
+	// regions are the regions of the database. When converting to REGIONAL BY
+	// ROW, the new indexes are partitioned by these regions.
+	regions descpb.Regions
+	// mutationIdxAllowedInSameTxn, if set, is the index of a mutation which was
+	// queued in the same transaction and which is permitted alongside the
+	// primary key change (i.e. the addition of the region column).
+	mutationIdxAllowedInSameTxn *int
+}
+
+// toRegionalByRow returns whether the swap converts the table to REGIONAL BY
+// ROW.
+func (s *alterPrimaryKeyLocalitySwap) toRegionalByRow() bool {
+	return s != nil && s.localityConfigSwap.NewLocalityConfig.GetRegionalByRow() != nil
+}
+
+// fromRegionalByRow returns whether the swap converts the table from REGIONAL
+// BY ROW.
+func (s *alterPrimaryKeyLocalitySwap) fromRegionalByRow() bool {
+	return s != nil && s.localityConfigSwap.OldLocalityConfig.GetRegionalByRow() != nil
+}
+
+// AlterPrimaryKey queues the mutations required to change the primary key of
+// the table. If localitySwap is set, the primary key change is performed on
+// behalf of an ALTER TABLE ... SET LOCALITY statement.
 func (p *planner) AlterPrimaryKey(
-	ctx context.Context, tableDesc *tabledesc.Mutable, alterPKNode *tree.AlterTableAlterPrimaryKey,
+	ctx context.Context,
+	tableDesc *tabledesc.Mutable,
+	alterPKNode *tree.AlterTableAlterPrimaryKey,
+	localitySwap *alterPrimaryKeyLocalitySwap,
 ) error {
 	if alterPKNode.Interleave != nil {
 		p.BufferClientNotice(
@@ -55,6 +91,10 @@ func (p *planner) AlterPrimaryKey(
 	// in the current transaction.
 	currentMutationID := tableDesc.ClusterVersion.NextMutationID
 	for i := range tableDesc.Mutations {
+		if localitySwap != nil && localitySwap.mutationIdxAllowedInSameTxn != nil &&
+			*localitySwap.mutationIdxAllowedInSameTxn == i {
+			continue
+		}
 		mut := &tableDesc.Mutations[i]
 		if mut.MutationID == currentMutationID {
 			return unimplemented.NewWithIssuef(
@@ -198,6 +238,26 @@ func (p *planner) AlterPrimaryKey(
 		}
 	}
 
+	// When converting the table to REGIONAL BY ROW, the new primary index is
+	// prefixed by the region column and partitioned by region.
+	var regionColumn *descpb.ColumnDescriptor
+	var regionPartitioning descpb.PartitioningDescriptor
+	if localitySwap != nil {
+		var err error
+		regionColumn, _, err = tableDesc.FindColumnByName(localitySwap.regionColumn)
+		if err != nil {
+			return err
+		}
+	}
+	if localitySwap.toRegionalByRow() {
+		var err error
+		regionPartitioning, err = partitionByRegion(regionColumn.Type, localitySwap.regions)
+		if err != nil {
+			return err
+		}
+		newPrimaryIndexDesc.Partitioning = regionPartitioning
+	}
+
 	if alterPKNode.Interleave != nil {
 		if err := p.addInterleave(ctx, tableDesc, newPrimaryIndexDesc, alterPKNode.Interleave); err != nil {
 			return err
@@ -214,8 +274,15 @@ func (p *planner) AlterPrimaryKey(
 	}
 
 	// Create a new index that indexes everything the old primary index
-	// does, but doesn't store anything.
-	if shouldCopyPrimaryKey(tableDesc, newPrimaryIndexDesc) {
+	// does, but doesn't store anything. This is not necessary when converting
+	// the table from REGIONAL BY ROW, as the new primary key is the old primary
+	// key without the region column prefix, so it is at least as strict. When
+	// converting the table to REGIONAL BY ROW, a unique index on the old primary
+	// key columns may remain from an earlier conversion, in which case it is
+	// reused instead.
+	if shouldCopyPrimaryKey(tableDesc, newPrimaryIndexDesc) &&
+		!(localitySwap.fromRegionalByRow() && isIndexPrefixedByColumn(&tableDesc.PrimaryIndex, regionColumn.ID)) &&
+		!(localitySwap.toRegionalByRow() && hasUniqueIndexOnPrimaryKeyColumns(tableDesc)) {
 		oldPrimaryIndexCopy := protoutil.Clone(&tableDesc.PrimaryIndex).(*descpb.IndexDescriptor)
 		// Clear the name of the index so that it gets generated by AllocateIDs.
 		oldPrimaryIndexCopy.Name = ""
@@ -254,10 +321,13 @@ func (p *planner) AlterPrimaryKey(
 		}
 		return shouldRewrite || !idx.Unique || idx.Type == descpb.IndexDescriptor_INVERTED
 	}
+	// When converting the table from REGIONAL BY ROW, all indexes partitioned
+	// by region have to be rewritten to remove the partitioning.
 	var indexesToRewrite []*descpb.IndexDescriptor
 	for i := range tableDesc.Indexes {
 		idx := &tableDesc.Indexes[i]
-		if idx.ID != newPrimaryIndexDesc.ID && shouldRewriteIndex(idx) {
+		if idx.ID != newPrimaryIndexDesc.ID && (shouldRewriteIndex(idx) ||
+			(localitySwap.fromRegionalByRow() && isIndexPartitionedByColumn(idx, regionColumn.ID))) {
 			indexesToRewrite = append(indexesToRewrite, idx)
 		}
 	}
@@ -277,11 +347,57 @@ func (p *planner) AlterPrimaryKey(
 	// This new index will have an altered ExtraColumnIDs to allow it to be rewritten
 	// using the unique-ifying columns from the new table.
 	var oldIndexIDs, newIndexIDs []descpb.IndexID
+	// uniqueIndexTwins maps the IDs of unique indexes which are partitioned by
+	// region to the IDs of the unpartitioned unique indexes on the same columns
+	// which enforce their uniqueness across regions.
+	uniqueIndexTwins := make(map[descpb.IndexID]descpb.IndexID)
+	var addedTwins []*descpb.IndexDescriptor
 	for _, idx := range indexesToRewrite {
+		if localitySwap.fromRegionalByRow() && isIndexPartitionedByColumn(idx, regionColumn.ID) {
+			if twin := findUniqueIndexTwin(tableDesc, idx); twin != nil {
+				// The unpartitioned twin already enforces the uniqueness of the
+				// index, so the index is replaced by the twin below rather than
+				// rewritten into a duplicate of it.
+				uniqueIndexTwins[idx.ID] = twin.ID
+				continue
+			}
+		}
 		// Clone the index that we want to rewrite.
 		newIndex := protoutil.Clone(idx).(*descpb.IndexDescriptor)
 		basename := newIndex.Name + "_rewrite_for_primary_key_change"
 		newIndex.Name = tabledesc.GenerateUniqueConstraintName(basename, nameExists)
+		// Forward indexes of REGIONAL BY ROW tables are prefixed by the region
+		// column and partitioned by region.
+		switch {
+		case localitySwap.toRegionalByRow() && newIndex.Type == descpb.IndexDescriptor_FORWARD &&
+			!isIndexPrefixedByColumn(newIndex, regionColumn.ID):
+			newIndex.ColumnNames = append([]string{regionColumn.Name}, newIndex.ColumnNames...)
+			newIndex.ColumnIDs = append([]descpb.ColumnID{regionColumn.ID}, newIndex.ColumnIDs...)
+			newIndex.ColumnDirections = append(
+				[]descpb.IndexDescriptor_Direction{descpb.IndexDescriptor_ASC}, newIndex.ColumnDirections...,
+			)
+			newIndex.Partitioning = regionPartitioning
+			// The region prefix weakens the uniqueness of a unique index to
+			// within a region, so an unpartitioned unique index on the original
+			// columns is added to keep enforcing it across regions.
+			if newIndex.Unique && !hasUniqueIndexTwin(addedTwins, idx) {
+				twin := protoutil.Clone(idx).(*descpb.IndexDescriptor)
+				// Clear the name of the index so that it gets generated by AllocateIDs.
+				twin.Name = ""
+				twin.StoreColumnIDs = nil
+				twin.StoreColumnNames = nil
+				twin.Interleave = descpb.InterleaveDescriptor{}
+				if err := addIndexMutationWithSpecificPrimaryKey(ctx, tableDesc, twin, newPrimaryIndexDesc); err != nil {
+					return err
+				}
+				addedTwins = append(addedTwins, twin)
+			}
+		case localitySwap.fromRegionalByRow() && isIndexPartitionedByColumn(newIndex, regionColumn.ID):
+			newIndex.ColumnNames = newIndex.ColumnNames[1:]
+			newIndex.ColumnIDs = newIndex.ColumnIDs[1:]
+			newIndex.ColumnDirections = newIndex.ColumnDirections[1:]
+			newIndex.Partitioning = descpb.PartitioningDescriptor{}
+		}
 		if err := addIndexMutationWithSpecificPrimaryKey(ctx, tableDesc, newIndex, newPrimaryIndexDesc); err != nil {
 			return err
 		}
@@ -297,6 +413,24 @@ func (p *planner) AlterPrimaryKey(
 		oldIndexIDs = append(oldIndexIDs, idx.ID)
 		newIndexIDs = append(newIndexIDs, newIndex.ID)
 	}
+	// Swap out the partitioned unique indexes with their twins, or with the
+	// rewritten versions of their twins, which then take over their names.
+	// These are swapped last so that the names of the partitioned indexes
+	// take precedence over those of the twins.
+	for _, idx := range indexesToRewrite {
+		twinID, ok := uniqueIndexTwins[idx.ID]
+		if !ok {
+			continue
+		}
+		for j := range oldIndexIDs {
+			if oldIndexIDs[j] == twinID {
+				twinID = newIndexIDs[j]
+				break
+			}
+		}
+		oldIndexIDs = append(oldIndexIDs, idx.ID)
+		newIndexIDs = append(newIndexIDs, twinID)
+	}
 
 	swapArgs := &descpb.PrimaryKeySwap{
 		OldPrimaryIndexId: tableDesc.PrimaryIndex.ID,
@@ -304,6 +438,9 @@ func (p *planner) AlterPrimaryKey(
 		NewIndexes:        newIndexIDs,
 		OldIndexes:        oldIndexIDs,
 	}
+	if localitySwap != nil {
+		swapArgs.LocalityConfigSwap = &localitySwap.localityConfigSwap
+	}
 	tableDesc.AddPrimaryKeySwapMutation(swapArgs)
 
 	// Mark the primary key of the table as valid.
@@ -350,3 +487,59 @@ func shouldCopyPrimaryKey(desc *tabledesc.Mutable, newPK *descpb.IndexDescriptor
 
 	return true
 }
+
+// hasUniqueIndexOnPrimaryKeyColumns returns whether the table has a unique,
+// non-partial secondary index on exactly the columns of its primary key.
+func hasUniqueIndexOnPrimaryKeyColumns(desc *tabledesc.Mutable) bool {
+	for i := range desc.Indexes {
+		idx := &desc.Indexes[i]
+		if idx.Unique && !idx.IsPartial() &&
+			descpb.ColumnIDs(idx.ColumnIDs).Equals(desc.PrimaryIndex.ColumnIDs) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIndexPartitionedByColumn returns whether the index is prefixed by the given
+// column and partitioned.
+func isIndexPartitionedByColumn(idx *descpb.IndexDescriptor, colID descpb.ColumnID) bool {
+	return isIndexPrefixedByColumn(idx, colID) && idx.Partitioning.NumColumns > 0
+}
+
+// findUniqueIndexTwin returns the unpartitioned unique index of the table which
+// enforces the uniqueness of the given unique index across the partitions the
+// latter is prefixed by, or nil if there is none.
+func findUniqueIndexTwin(
+	desc *tabledesc.Mutable, idx *descpb.IndexDescriptor,
+) *descpb.IndexDescriptor {
+	if !idx.Unique || len(idx.ColumnIDs) < 2 {
+		return nil
+	}
+	for i := range desc.Indexes {
+		twin := &desc.Indexes[i]
+		if twin.ID != idx.ID && twin.Unique && twin.Partitioning.NumColumns == 0 &&
+			twin.Type == idx.Type && twin.Predicate == idx.Predicate &&
+			descpb.ColumnIDs(twin.ColumnIDs).Equals(idx.ColumnIDs[1:]) {
+			return twin
+		}
+	}
+	return nil
+}
+
+// hasUniqueIndexTwin returns whether one of the given indexes is an
+// unpartitioned unique index on the same columns as the given unique index.
+func hasUniqueIndexTwin(twins []*descpb.IndexDescriptor, idx *descpb.IndexDescriptor) bool {
+	for _, twin := range twins {
+		if twin.Predicate == idx.Predicate && descpb.ColumnIDs(twin.ColumnIDs).Equals(idx.ColumnIDs) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIndexPrefixedByColumn returns whether the first column of the index is the
+// given column.
+func isIndexPrefixedByColumn(idx *descpb.IndexDescriptor, colID descpb.ColumnID) bool {
+	return len(idx.ColumnIDs) > 0 && idx.ColumnIDs[0] == colID
+}
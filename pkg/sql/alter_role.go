@@ -101,6 +101,8 @@ func (p *planner) checkPasswordOptionConstraints(
 		roleOptions.Contains(roleoption.PASSWORD) ||
 		roleOptions.Contains(roleoption.VALIDUNTIL) ||
 		roleOptions.Contains(roleoption.LOGIN) ||
+		roleOptions.Contains(roleoption.AUTHENTICATION) ||
+		roleOptions.Contains(roleoption.CONNECTIONFROM) ||
 		// CREATE ROLE NOLOGIN is valid without CREATELOGIN.
 		(roleOptions.Contains(roleoption.NOLOGIN) && !newUser) ||
 		// Disallow implicit LOGIN upon new user.
@@ -108,7 +110,8 @@ func (p *planner) checkPasswordOptionConstraints(
 		// Only a role who has CREATELOGIN itself can grant CREATELOGIN or
 		// NOCREATELOGIN to another role, or set up a password for
 		// authentication, or set up password validity, or enable/disable
-		// LOGIN privilege; even if they have CREATEROLE privilege.
+		// LOGIN privilege, or constrain how the role authenticates; even if
+		// they have CREATEROLE privilege.
 		if err := p.CheckRoleOption(ctx, roleoption.CREATELOGIN); err != nil {
 			return err
 		}
@@ -191,7 +191,7 @@ func (n *alterTableNode) startExec(params runParams) error {
 						Sharded:    d.Sharded,
 						Interleave: d.Interleave,
 					}
-					if err := params.p.AlterPrimaryKey(params.ctx, n.tableDesc, alterPK); err != nil {
+					if err := params.p.AlterPrimaryKey(params.ctx, n.tableDesc, alterPK, nil /* localitySwap */); err != nil {
 						return err
 					}
 					continue
@@ -335,7 +335,7 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 
 		case *tree.AlterTableAlterPrimaryKey:
-			if err := params.p.AlterPrimaryKey(params.ctx, n.tableDesc, t); err != nil {
+			if err := params.p.AlterPrimaryKey(params.ctx, n.tableDesc, t, nil /* localitySwap */); err != nil {
 				return err
 			}
 			// Mark descriptorChanged so that a mutation job is scheduled at the end of startExec.
@@ -1115,6 +1115,10 @@ func injectTableStats(
 		if s.Name != "" {
 			name = s.Name
 		}
+		var partialPredicate interface{}
+		if s.PartialPredicate != "" {
+			partialPredicate = s.PartialPredicate
+		}
 		if _ /* rows */, err := params.extendedEvalCtx.ExecCfg.InternalExecutor.Exec(
 			params.ctx,
 			"insert-stats",
@@ -1127,8 +1131,9 @@ func injectTableStats(
 					"rowCount",
 					"distinctCount",
 					"nullCount",
-					histogram
-				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+					histogram,
+					"partialPredicate"
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
 			desc.GetID(),
 			name,
 			columnIDs,
@@ -1137,6 +1142,7 @@ func injectTableStats(
 			s.DistinctCount,
 			s.NullCount,
 			histogram,
+			partialPredicate,
 		); err != nil {
 			return errors.Wrapf(err, "failed to insert stats")
 		}
@@ -13,11 +13,25 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 )
 
+type alterTableSetLocalityNode struct {
+	n         tree.AlterTableLocality
+	tableDesc *tabledesc.Mutable
+	dbDesc    *dbdesc.Immutable
+}
+
 // AlterTableLocality transforms a tree.AlterTableLocality into a plan node.
+// Privileges: CREATE on table.
 func (p *planner) AlterTableLocality(
 	ctx context.Context, n *tree.AlterTableLocality,
 ) (planNode, error) {
@@ -28,5 +42,297 @@ func (p *planner) AlterTableLocality(
 	); err != nil {
 		return nil, err
 	}
-	return nil, unimplemented.New("alter table locality", "implementation pending")
+
+	tableDesc, err := p.ResolveMutableTableDescriptorEx(
+		ctx, n.Name, !n.IfExists, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	// This check for CREATE privilege is kept for backwards compatibility.
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of table %s or have CREATE privilege on table %s",
+			tree.Name(tableDesc.GetName()), tree.Name(tableDesc.GetName()))
+	}
+
+	dbDesc, err := p.Descriptors().GetDatabaseVersionByID(
+		ctx, p.txn, tableDesc.ParentID, tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !dbDesc.IsMultiRegion() {
+		return nil, pgerror.Newf(
+			pgcode.InvalidTableDefinition,
+			"cannot alter the locality of table %s as database %s is not multi-region enabled",
+			tree.Name(tableDesc.GetName()),
+			tree.Name(dbDesc.GetName()),
+		)
+	}
+
+	return &alterTableSetLocalityNode{
+		n:         *n,
+		tableDesc: tableDesc,
+		dbDesc:    dbDesc,
+	}, nil
 }
+
+func (n *alterTableSetLocalityNode) startExec(params runParams) error {
+	newLocalityConfig, err := makeTableLocalityConfig(n.n.Locality)
+	if err != nil {
+		return err
+	}
+	if err := tabledesc.ValidateTableLocalityConfig(
+		n.tableDesc.Name, newLocalityConfig, n.dbDesc,
+	); err != nil {
+		return err
+	}
+
+	// A previous locality change only takes effect once its primary key swap
+	// completes, so don't allow another one to be queued up behind it.
+	for i := range n.tableDesc.Mutations {
+		if pkSwap := n.tableDesc.Mutations[i].GetPrimaryKeySwap(); pkSwap != nil &&
+			pkSwap.LocalityConfigSwap != nil {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"cannot alter the locality of table %s while another locality change is in progress",
+				tree.Name(n.tableDesc.Name))
+		}
+	}
+
+	// Tables without a locality config are REGIONAL BY TABLE IN PRIMARY REGION.
+	oldLocalityConfig := descpb.TableDescriptor_LocalityConfig{
+		Locality: &descpb.TableDescriptor_LocalityConfig_RegionalByTable_{
+			RegionalByTable: &descpb.TableDescriptor_LocalityConfig_RegionalByTable{},
+		},
+	}
+	if n.tableDesc.LocalityConfig != nil {
+		oldLocalityConfig = *n.tableDesc.LocalityConfig
+	}
+	if oldLocalityConfig.Equal(newLocalityConfig) {
+		return nil
+	}
+
+	origNumMutations := len(n.tableDesc.Mutations)
+	fromRegionalByRow := oldLocalityConfig.GetRegionalByRow() != nil
+	toRegionalByRow := newLocalityConfig.GetRegionalByRow() != nil
+	switch {
+	case toRegionalByRow:
+		if err := n.alterTableLocalityToRegionalByRow(
+			params, oldLocalityConfig, *newLocalityConfig,
+		); err != nil {
+			return err
+		}
+	case fromRegionalByRow:
+		if err := n.alterTableLocalityFromRegionalByRow(
+			params, oldLocalityConfig, *newLocalityConfig,
+		); err != nil {
+			return err
+		}
+	default:
+		n.tableDesc.LocalityConfig = newLocalityConfig
+	}
+
+	mutationID := descpb.InvalidMutationID
+	if len(n.tableDesc.Mutations) > origNumMutations {
+		mutationID = n.tableDesc.ClusterVersion.NextMutationID
+	}
+
+	// If the primary key has to be changed, the new locality only takes effect
+	// once the primary key swap completes, and the zone configuration is
+	// updated by the schema changer at that point. Updating it now would place
+	// the table according to a locality it doesn't have yet, and would leave
+	// the zone configuration behind if the schema change were rolled back.
+	if mutationID == descpb.InvalidMutationID {
+		if err := applyZoneConfigForMultiRegionTable(
+			params.ctx,
+			params.p.txn,
+			params.ExecCfg(),
+			*n.dbDesc.RegionConfig,
+			n.tableDesc,
+			*newLocalityConfig,
+		); err != nil {
+			return err
+		}
+	}
+	if err := params.p.writeSchemaChange(
+		params.ctx, n.tableDesc, mutationID, tree.AsStringWithFQNames(&n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	// Add a back reference from the region enum to the table, in case the
+	// region column was added.
+	if err := params.p.addBackRefsFromAllTypesInTable(params.ctx, n.tableDesc); err != nil {
+		return err
+	}
+
+	// Record this table alteration in the event log. This is an auditable log
+	// event and is recorded in the same transaction as the table descriptor
+	// update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogAlterTable,
+		int32(n.tableDesc.ID),
+		int32(params.extendedEvalCtx.NodeID.SQLInstanceID()),
+		struct {
+			TableName  string
+			Statement  string
+			User       string
+			MutationID uint32
+		}{
+			params.p.ResolvedName(n.n.Name).FQString(),
+			n.n.String(),
+			params.SessionData().User().Normalized(),
+			uint32(mutationID),
+		},
+	)
+}
+
+// alterTableLocalityToRegionalByRow converts the table to REGIONAL BY ROW. The
+// region column is added to the table unless it already exists, and the
+// primary key is changed to be prefixed by the region column and partitioned
+// by region. The new locality takes effect once the primary key swap
+// completes. Rows which existed before the conversion are homed in the region
+// the table was homed in, which is the primary region of the database unless
+// the table was REGIONAL BY TABLE in another region.
+func (n *alterTableSetLocalityNode) alterTableLocalityToRegionalByRow(
+	params runParams, oldLocalityConfig, newLocalityConfig descpb.TableDescriptor_LocalityConfig,
+) error {
+	// REGIONAL BY ROW tables are partitioned, which is an enterprise feature.
+	st := params.ExecCfg().Settings
+	if err := base.CheckEnterpriseEnabled(
+		st, params.ExecCfg().ClusterID(), ClusterOrganization.Get(&st.SV), "REGIONAL BY ROW",
+	); err != nil {
+		return err
+	}
+
+	regionConfig := n.dbDesc.RegionConfig
+	regionType, err := params.p.ResolveTypeByOID(
+		params.ctx, typedesc.TypeIDToOID(regionConfig.RegionEnumID),
+	)
+	if err != nil {
+		return err
+	}
+
+	regionColName := tree.Name(tree.RegionalByRowRegionDefaultCol)
+	var mutationIdxAllowedInSameTxn *int
+	if col, dropped, err := n.tableDesc.FindColumnByName(regionColName); err == nil {
+		// The column may have been left behind by a previous conversion from
+		// REGIONAL BY ROW, in which case it can be reused.
+		if dropped {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"column %q is being dropped, try again later", col.Name)
+		}
+		if col.Type.Oid() != regionType.Oid() || col.Nullable {
+			return pgerror.Newf(pgcode.DuplicateColumn,
+				"cannot convert table %s to REGIONAL BY ROW as column %q already exists "+
+					"and is not a non-nullable column of type %s",
+				tree.Name(n.tableDesc.Name), col.Name, tree.RegionEnum)
+		}
+	} else {
+		colDef := &tree.ColumnTableDef{
+			Name: regionColName,
+			Type: regionType,
+		}
+		// Existing rows are homed in the region the table was homed in.
+		homeRegion := regionConfig.PrimaryRegion
+		if rbt := oldLocalityConfig.GetRegionalByTable(); rbt != nil && rbt.Region != nil {
+			homeRegion = *rbt.Region
+		}
+		colDef.Nullable.Nullability = tree.NotNull
+		colDef.DefaultExpr.Expr = tree.NewStrVal(string(homeRegion))
+		col, _, _, err := tabledesc.MakeColumnDefDescs(
+			params.ctx, colDef, &params.p.semaCtx, params.EvalContext(),
+		)
+		if err != nil {
+			return err
+		}
+		// The column is an implementation detail of the locality, so it
+		// isn't returned by SELECT *.
+		col.Hidden = true
+		n.tableDesc.AddColumnMutation(col, descpb.DescriptorMutation_ADD)
+		mutationIdx := len(n.tableDesc.Mutations) - 1
+		mutationIdxAllowedInSameTxn = &mutationIdx
+		if err := n.tableDesc.AllocateIDs(params.ctx); err != nil {
+			return err
+		}
+	}
+
+	pkColumns := make(tree.IndexElemList, 0, len(n.tableDesc.PrimaryIndex.ColumnNames)+1)
+	pkColumns = append(pkColumns, tree.IndexElem{Column: regionColName, Direction: tree.Ascending})
+	pkColumns = append(pkColumns, primaryIndexElems(&n.tableDesc.PrimaryIndex)...)
+	return params.p.AlterPrimaryKey(
+		params.ctx,
+		n.tableDesc,
+		&tree.AlterTableAlterPrimaryKey{Columns: pkColumns},
+		&alterPrimaryKeyLocalitySwap{
+			localityConfigSwap: descpb.PrimaryKeySwap_LocalityConfigSwap{
+				OldLocalityConfig: oldLocalityConfig,
+				NewLocalityConfig: newLocalityConfig,
+			},
+			regionColumn:                regionColName,
+			regions:                     regionConfig.Regions,
+			mutationIdxAllowedInSameTxn: mutationIdxAllowedInSameTxn,
+		},
+	)
+}
+
+// alterTableLocalityFromRegionalByRow converts a REGIONAL BY ROW table to
+// another locality. The primary key is changed to no longer be prefixed by the
+// region column, and the partitioning by region is removed. The new locality
+// takes effect once the primary key swap completes. The region column itself
+// is left in place.
+func (n *alterTableSetLocalityNode) alterTableLocalityFromRegionalByRow(
+	params runParams, oldLocalityConfig, newLocalityConfig descpb.TableDescriptor_LocalityConfig,
+) error {
+	regionColName := tree.Name(tree.RegionalByRowRegionDefaultCol)
+	col, _, err := n.tableDesc.FindColumnByName(regionColName)
+	if err != nil || !isIndexPrefixedByColumn(&n.tableDesc.PrimaryIndex, col.ID) ||
+		len(n.tableDesc.PrimaryIndex.ColumnIDs) == 1 {
+		// The table was never partitioned by region, so there is nothing to
+		// undo.
+		n.tableDesc.LocalityConfig = &newLocalityConfig
+		return nil
+	}
+
+	return params.p.AlterPrimaryKey(
+		params.ctx,
+		n.tableDesc,
+		&tree.AlterTableAlterPrimaryKey{Columns: primaryIndexElems(&n.tableDesc.PrimaryIndex)[1:]},
+		&alterPrimaryKeyLocalitySwap{
+			localityConfigSwap: descpb.PrimaryKeySwap_LocalityConfigSwap{
+				OldLocalityConfig: oldLocalityConfig,
+				NewLocalityConfig: newLocalityConfig,
+			},
+			regionColumn: regionColName,
+		},
+	)
+}
+
+// primaryIndexElems returns the columns of the given primary index as an
+// IndexElemList.
+func primaryIndexElems(idx *descpb.IndexDescriptor) tree.IndexElemList {
+	elems := make(tree.IndexElemList, len(idx.ColumnNames))
+	for i, name := range idx.ColumnNames {
+		elems[i] = tree.IndexElem{Column: tree.Name(name), Direction: tree.Ascending}
+		if idx.ColumnDirections[i] == descpb.IndexDescriptor_DESC {
+			elems[i].Direction = tree.Descending
+		}
+	}
+	return elems
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because ALTER TABLE ... SET LOCALITY performs multiple KV operations
+// on descriptors and zone configurations and expects to see its own writes.
+func (n *alterTableSetLocalityNode) ReadingOwnWrites() {}
+
+func (n *alterTableSetLocalityNode) Next(runParams) (bool, error) { return false, nil }
+func (n *alterTableSetLocalityNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *alterTableSetLocalityNode) Close(context.Context)        {}
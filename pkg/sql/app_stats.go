@@ -149,6 +149,19 @@ var logicalPlanCollectionPeriod = settings.RegisterPublicNonNegativeDurationSett
 	5*time.Minute,
 )
 
+var sampleIndexRecommendations = settings.RegisterPublicBoolSetting(
+	"sql.metrics.statement_details.index_recommendation_collection.enabled",
+	"generate index recommendations for each fingerprint when its logical plan is collected",
+	true,
+)
+
+var collectPlanGists = settings.RegisterPublicBoolSetting(
+	"sql.metrics.statement_details.plan_gist_collection.enabled",
+	"record a compact encoding of the plan of each statement execution, "+
+		"which can be decoded with crdb_internal.decode_plan_gist",
+	true,
+)
+
 func (s stmtKey) String() string {
 	if s.failed {
 		return "!" + s.anonymizedStmt
@@ -165,6 +178,8 @@ func (s stmtKey) String() string {
 func (a *appStats) recordStatement(
 	stmt *Statement,
 	samplePlanDescription *roachpb.ExplainTreePlanNode,
+	indexRecommendations []string,
+	planGist string,
 	distSQLUsed bool,
 	vectorized bool,
 	implicitTxn bool,
@@ -207,6 +222,10 @@ func (a *appStats) recordStatement(
 	if samplePlanDescription != nil {
 		s.mu.data.SensitiveInfo.MostRecentPlanDescription = *samplePlanDescription
 		s.mu.data.SensitiveInfo.MostRecentPlanTimestamp = timeutil.Now()
+		s.mu.data.SensitiveInfo.IndexRecommendations = indexRecommendations
+	}
+	if planGist != "" {
+		s.mu.data.AddPlanGist(planGist)
 	}
 	if automaticRetryCount == 0 {
 		s.mu.data.FirstAttemptCount++
@@ -221,6 +240,7 @@ func (a *appStats) recordStatement(
 	s.mu.data.OverheadLat.Record(s.mu.data.Count, ovhLat)
 	s.mu.data.BytesRead.Record(s.mu.data.Count, float64(stats.bytesRead))
 	s.mu.data.RowsRead.Record(s.mu.data.Count, float64(stats.rowsRead))
+	s.mu.data.ContentionTime.Record(s.mu.data.Count, stats.contentionTime.Seconds())
 	// Note that some fields derived from tracing statements (such as
 	// BytesSentOverNetwork) are not updated here because they are collected
 	// on-demand.
@@ -412,6 +432,7 @@ func (a *appStats) recordTransaction(
 	retryLat time.Duration,
 	commitLat time.Duration,
 	numRows int,
+	contentionTime time.Duration,
 ) {
 	if !txnStatsEnable.Get(&a.st.SV) {
 		return
@@ -437,6 +458,7 @@ func (a *appStats) recordTransaction(
 	s.mu.data.ServiceLat.Record(s.mu.data.Count, serviceLat.Seconds())
 	s.mu.data.RetryLat.Record(s.mu.data.Count, retryLat.Seconds())
 	s.mu.data.CommitLat.Record(s.mu.data.Count, commitLat.Seconds())
+	s.mu.data.ContentionTime.Record(s.mu.data.Count, contentionTime.Seconds())
 	if retryCount > s.mu.data.MaxRetries {
 		s.mu.data.MaxRetries = retryCount
 	}
@@ -643,15 +665,16 @@ func (s *sqlStats) getUnscrubbedTxnStats() []roachpb.CollectedTransactionStatist
 		if cap(ret) == 0 {
 			ret = make([]roachpb.CollectedTransactionStatistics, 0, len(a.txns)*len(s.apps))
 		}
-		for _, stats := range a.txns {
+		for key, stats := range a.txns {
 			stats.mu.Lock()
 			data := stats.mu.data
 			stats.mu.Unlock()
 
 			ret = append(ret, roachpb.CollectedTransactionStatistics{
-				StatementIDs: stats.statementIDs,
-				App:          appName,
-				Stats:        data,
+				StatementIDs:             stats.statementIDs,
+				App:                      appName,
+				Stats:                    data,
+				TransactionFingerprintID: roachpb.TransactionFingerprintID(key),
 			})
 		}
 		a.Unlock()
@@ -1691,6 +1691,28 @@ func runSchemaChangesInTxn(
 					}
 				}
 			}
+			// N.B. This logic needs to be kept up to date with the corresponding
+			// piece in (*SchemaChanger).done.
+			if pkSwap.LocalityConfigSwap != nil {
+				dbDesc, err := planner.Descriptors().GetDatabaseVersionByID(
+					ctx, planner.txn, tableDesc.ParentID, tree.DatabaseLookupFlags{Required: true},
+				)
+				if err != nil {
+					return err
+				}
+				if dbDesc.IsMultiRegion() {
+					if err := applyZoneConfigForMultiRegionTable(
+						ctx,
+						planner.txn,
+						planner.ExecCfg(),
+						*dbDesc.RegionConfig,
+						tableDesc,
+						pkSwap.LocalityConfigSwap.NewLocalityConfig,
+					); err != nil {
+						return err
+					}
+				}
+			}
 		}
 	}
 	// Clear all the mutations except for adding constraints.
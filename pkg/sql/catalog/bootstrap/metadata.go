@@ -342,6 +342,16 @@ func addSystemDescriptorsToSchema(target *MetadataSchema) {
 
 	target.AddDescriptor(keys.SystemDatabaseID, systemschema.ScheduledJobsTable)
 	target.AddDescriptor(keys.SystemDatabaseID, systemschema.SqllivenessTable)
+
+	// Tables introduced in 21.1.
+
+	if target.codec.ForSystemTenant() {
+		// Only add the tenant usage table if this is the system tenant.
+		target.AddDescriptor(keys.SystemDatabaseID, systemschema.TenantUsageTable)
+	}
+	target.AddDescriptor(keys.SystemDatabaseID, systemschema.StatementStatisticsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, systemschema.TransactionStatisticsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, systemschema.StatementHintsTable)
 }
 
 // addSplitIDs adds a split point for each of the PseudoTableIDs to the supplied
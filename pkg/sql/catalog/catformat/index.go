@@ -20,9 +20,29 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
 )
 
+// TrigramOpClass is the operator class of the inverted column of trigram
+// inverted indexes, which index strings by their trigrams.
+const TrigramOpClass tree.Name = "gin_trgm_ops"
+
+// InvertedColumnOpClass returns the operator class of the inverted column of
+// the given index, or the empty string if the index is not inverted or the
+// column uses the default operator class of its type. Only strings, which are
+// indexed by their trigrams, have no default operator class.
+func InvertedColumnOpClass(table catalog.TableDescriptor, index *descpb.IndexDescriptor) tree.Name {
+	if index.Type != descpb.IndexDescriptor_INVERTED {
+		return ""
+	}
+	col, _, err := table.FindColumnByName(tree.Name(index.InvertedColumnName()))
+	if err != nil || col.Type.Family() != types.StringFamily {
+		return ""
+	}
+	return TrigramOpClass
+}
+
 // IndexForDisplay formats a column descriptor as a SQL string. It
 // converts user defined types in partial index predicate expressions to a
 // human-readable form.
@@ -58,6 +78,11 @@ func IndexForDisplay(
 	}
 	f.WriteString(" (")
 	index.ColNamesFormat(f)
+	if opClass := InvertedColumnOpClass(table, index); opClass != "" {
+		// The inverted column is always the last column of the index.
+		f.WriteByte(' ')
+		f.FormatNode(&opClass)
+	}
 	f.WriteByte(')')
 
 	if index.IsSharded() {
@@ -100,7 +100,8 @@ func ValidateColumnDefType(t *types.T) error {
 	case types.BitFamily, types.IntFamily, types.FloatFamily, types.BoolFamily, types.BytesFamily, types.DateFamily,
 		types.INetFamily, types.IntervalFamily, types.JsonFamily, types.OidFamily, types.TimeFamily,
 		types.TimestampFamily, types.TimestampTZFamily, types.UuidFamily, types.TimeTZFamily,
-		types.GeographyFamily, types.GeometryFamily, types.EnumFamily, types.Box2DFamily:
+		types.GeographyFamily, types.GeometryFamily, types.EnumFamily, types.Box2DFamily,
+		types.TSQueryFamily, types.TSVectorFamily:
 		// These types are OK.
 
 	default:
@@ -115,15 +116,23 @@ func ValidateColumnDefType(t *types.T) error {
 func ColumnTypeIsIndexable(t *types.T) bool {
 	// Some inverted index types also have a key encoding, but we don't
 	// want to support those yet. See #50659.
-	return !MustBeValueEncoded(t) && !ColumnTypeIsInvertedIndexable(t)
+	return !MustBeValueEncoded(t) && !ColumnTypeIsOnlyInvertedIndexable(t)
 }
 
 // ColumnTypeIsInvertedIndexable returns whether the type t is valid to be indexed
 // using an inverted index.
 func ColumnTypeIsInvertedIndexable(t *types.T) bool {
+	// Strings are inverted indexed using their trigrams.
+	return ColumnTypeIsOnlyInvertedIndexable(t) || t.Family() == types.StringFamily
+}
+
+// ColumnTypeIsOnlyInvertedIndexable returns whether the type t is valid to be
+// indexed using an inverted index, but not using a forward index.
+func ColumnTypeIsOnlyInvertedIndexable(t *types.T) bool {
 	family := t.Family()
 	return family == types.JsonFamily || family == types.ArrayFamily ||
-		family == types.GeographyFamily || family == types.GeometryFamily
+		family == types.GeographyFamily || family == types.GeometryFamily ||
+		family == types.TSVectorFamily
 }
 
 // MustBeValueEncoded returns true if columns of the given kind can only be value
@@ -137,7 +146,8 @@ func MustBeValueEncoded(semanticType *types.T) bool {
 		default:
 			return MustBeValueEncoded(semanticType.ArrayContents())
 		}
-	case types.JsonFamily, types.TupleFamily, types.GeographyFamily, types.GeometryFamily:
+	case types.JsonFamily, types.TupleFamily, types.GeographyFamily, types.GeometryFamily,
+		types.TSQueryFamily, types.TSVectorFamily:
 		return true
 	}
 	return false
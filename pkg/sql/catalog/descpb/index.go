@@ -13,6 +13,8 @@ package descpb
 import (
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
@@ -90,10 +92,21 @@ func (desc *IndexDescriptor) ColNamesFormat(ctx *tree.FmtCtx) {
 func (desc *IndexDescriptor) FillColumns(elems tree.IndexElemList) error {
 	desc.ColumnNames = make([]string, 0, len(elems))
 	desc.ColumnDirections = make([]IndexDescriptor_Direction, 0, len(elems))
-	for _, c := range elems {
+	for i, c := range elems {
 		if c.Expr != nil {
 			return unimplemented.NewWithIssuef(9682, "only simple columns are supported as index elements")
 		}
+		// Operator classes are only supported for the inverted column of an
+		// inverted index. The caller checks that the operator class can be used
+		// with the type of the column.
+		if c.OpClass != "" && (desc.Type != IndexDescriptor_INVERTED || i != len(elems)-1) {
+			accessMethod := "btree"
+			if desc.Type == IndexDescriptor_INVERTED {
+				accessMethod = "gin"
+			}
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"operator class %q does not exist for access method %q", c.OpClass, accessMethod)
+		}
 		desc.ColumnNames = append(desc.ColumnNames, string(c.Column))
 		switch c.Direction {
 		case tree.Ascending, tree.DefaultDirection:
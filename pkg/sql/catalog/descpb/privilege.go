@@ -435,6 +435,10 @@ var SystemAllowedPrivileges = map[ID]privilege.List{
 	keys.StatementDiagnosticsTableID:          privilege.ReadWriteData,
 	keys.ScheduledJobsTableID:                 privilege.ReadWriteData,
 	keys.SqllivenessID:                        privilege.ReadWriteData,
+	keys.TenantUsageTableID:                   privilege.ReadWriteData,
+	keys.StatementStatisticsTableID:           privilege.ReadWriteData,
+	keys.TransactionStatisticsTableID:         privilege.ReadWriteData,
+	keys.StatementHintsTableID:                privilege.ReadWriteData,
 }
 
 // SetOwner sets the owner of the privilege descriptor to the provided string.
@@ -209,17 +209,18 @@ CREATE TABLE system.web_sessions (
 	// Design outlined in /docs/RFCS/20170908_sql_optimizer_statistics.md
 	TableStatisticsTableSchema = `
 CREATE TABLE system.table_statistics (
-	"tableID"       INT8       NOT NULL,
-	"statisticID"   INT8       NOT NULL DEFAULT unique_rowid(),
-	name            STRING,
-	"columnIDs"     INT8[]     NOT NULL,
-	"createdAt"     TIMESTAMP  NOT NULL DEFAULT now(),
-	"rowCount"      INT8       NOT NULL,
-	"distinctCount" INT8       NOT NULL,
-	"nullCount"     INT8       NOT NULL,
-	histogram       BYTES,
+	"tableID"          INT8       NOT NULL,
+	"statisticID"      INT8       NOT NULL DEFAULT unique_rowid(),
+	name               STRING,
+	"columnIDs"        INT8[]     NOT NULL,
+	"createdAt"        TIMESTAMP  NOT NULL DEFAULT now(),
+	"rowCount"         INT8       NOT NULL,
+	"distinctCount"    INT8       NOT NULL,
+	"nullCount"        INT8       NOT NULL,
+	histogram          BYTES,
+	"partialPredicate" STRING,
 	PRIMARY KEY ("tableID", "statisticID"),
-	FAMILY ("tableID", "statisticID", name, "columnIDs", "createdAt", "rowCount", "distinctCount", "nullCount", histogram)
+	FAMILY "fam_0_tableID_statisticID_name_columnIDs_createdAt_rowCount_distinctCount_nullCount_histogram" ("tableID", "statisticID", name, "columnIDs", "createdAt", "rowCount", "distinctCount", "nullCount", histogram, "partialPredicate")
 );`
 
 	// locations are used to map a locality specified by a node to geographic
@@ -342,6 +343,71 @@ CREATE TABLE system.sqlliveness (
     expiration       DECIMAL NOT NULL,
   	FAMILY fam0_session_id_expiration (session_id, expiration)
 )`
+
+	// TenantUsageTableSchema holds the global request unit budget of each
+	// tenant, which is distributed to the tenant's SQL pods through the
+	// TokenBucket RPC, along with the tenant's total consumption.
+	TenantUsageTableSchema = `
+CREATE TABLE system.tenant_usage (
+  tenant_id         INT8 NOT NULL PRIMARY KEY,
+  ru_burst_limit    FLOAT8 NOT NULL,
+  ru_refill_rate    FLOAT8 NOT NULL,
+  ru_current        FLOAT8 NOT NULL,
+  last_update       TIMESTAMP NOT NULL,
+  total_consumption BYTES,
+  FAMILY "primary" (
+    tenant_id, ru_burst_limit, ru_refill_rate, ru_current, last_update,
+    total_consumption
+  )
+)`
+
+	// StatementStatisticsTableSchema stores the statement statistics flushed
+	// by each node, aggregated over intervals of agg_interval starting at
+	// aggregated_ts. The statistics column holds an encoded
+	// roachpb.CollectedStatementStatistics.
+	StatementStatisticsTableSchema = `
+CREATE TABLE system.statement_statistics (
+  aggregated_ts  TIMESTAMPTZ NOT NULL,
+  fingerprint_id BYTES NOT NULL,
+  app_name       STRING NOT NULL,
+  node_id        INT8 NOT NULL,
+  agg_interval   INTERVAL NOT NULL,
+  statistics     BYTES NOT NULL,
+  PRIMARY KEY (aggregated_ts, fingerprint_id, app_name, node_id),
+  FAMILY "primary" (
+    aggregated_ts, fingerprint_id, app_name, node_id, agg_interval, statistics
+  )
+)`
+
+	// TransactionStatisticsTableSchema is the transaction counterpart of
+	// system.statement_statistics. The statistics column holds an encoded
+	// roachpb.CollectedTransactionStatistics.
+	TransactionStatisticsTableSchema = `
+CREATE TABLE system.transaction_statistics (
+  aggregated_ts  TIMESTAMPTZ NOT NULL,
+  fingerprint_id BYTES NOT NULL,
+  app_name       STRING NOT NULL,
+  node_id        INT8 NOT NULL,
+  agg_interval   INTERVAL NOT NULL,
+  statistics     BYTES NOT NULL,
+  PRIMARY KEY (aggregated_ts, fingerprint_id, app_name, node_id),
+  FAMILY "primary" (
+    aggregated_ts, fingerprint_id, app_name, node_id, agg_interval, statistics
+  )
+)`
+
+	// StatementHintsTableSchema stores the optimizer hints that are applied to
+	// every statement whose fingerprint matches the fingerprint column. A
+	// fingerprint may have several hints, of different types.
+	StatementHintsTableSchema = `
+CREATE TABLE system.statement_hints (
+  fingerprint STRING NOT NULL,
+  hint_type   STRING NOT NULL,
+  hint_value  STRING NOT NULL,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (fingerprint, hint_type, hint_value),
+  FAMILY "primary" (fingerprint, hint_type, hint_value, created_at)
+)`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -982,10 +1048,14 @@ var (
 			{Name: "distinctCount", ID: 7, Type: types.Int},
 			{Name: "nullCount", ID: 8, Type: types.Int},
 			{Name: "histogram", ID: 9, Type: types.Bytes, Nullable: true},
+			{Name: "partialPredicate", ID: 10, Type: types.String, Nullable: true},
 		},
-		NextColumnID: 10,
+		NextColumnID: 11,
 		Families: []descpb.ColumnFamilyDescriptor{
 			{
+				// NB: We are using the family name that existed prior to adding the
+				// partialPredicate column, so that the migration does not need to
+				// rename the family.
 				Name: "fam_0_tableID_statisticID_name_columnIDs_createdAt_rowCount_distinctCount_nullCount_histogram",
 				ID:   0,
 				ColumnNames: []string{
@@ -998,8 +1068,9 @@ var (
 					"distinctCount",
 					"nullCount",
 					"histogram",
+					"partialPredicate",
 				},
-				ColumnIDs: []descpb.ColumnID{1, 2, 3, 4, 5, 6, 7, 8, 9},
+				ColumnIDs: []descpb.ColumnID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
 			},
 		},
 		NextFamilyID: 1,
@@ -1684,8 +1755,143 @@ var (
 		FormatVersion:  descpb.InterleavedFormatVersion,
 		NextMutationID: 1,
 	})
+
+	// TenantUsageTable is the descriptor for the tenant_usage table. It is
+	// only present in the system tenant.
+	TenantUsageTable = tabledesc.NewImmutable(descpb.TableDescriptor{
+		Name:                    "tenant_usage",
+		ID:                      keys.TenantUsageTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []descpb.ColumnDescriptor{
+			{Name: "tenant_id", ID: 1, Type: types.Int},
+			{Name: "ru_burst_limit", ID: 2, Type: types.Float},
+			{Name: "ru_refill_rate", ID: 3, Type: types.Float},
+			{Name: "ru_current", ID: 4, Type: types.Float},
+			{Name: "last_update", ID: 5, Type: types.Timestamp},
+			{Name: "total_consumption", ID: 6, Type: types.Bytes, Nullable: true},
+		},
+		NextColumnID: 7,
+		Families: []descpb.ColumnFamilyDescriptor{{
+			Name: "primary",
+			ID:   0,
+			ColumnNames: []string{
+				"tenant_id", "ru_burst_limit", "ru_refill_rate", "ru_current",
+				"last_update", "total_consumption",
+			},
+			ColumnIDs: []descpb.ColumnID{1, 2, 3, 4, 5, 6},
+		}},
+		NextFamilyID: 1,
+		PrimaryIndex: pk("tenant_id"),
+		NextIndexID:  2,
+		Privileges: descpb.NewCustomSuperuserPrivilegeDescriptor(
+			descpb.SystemAllowedPrivileges[keys.TenantUsageTableID], security.NodeUserName()),
+		FormatVersion:  descpb.InterleavedFormatVersion,
+		NextMutationID: 1,
+	})
+
+	// StatementStatisticsTable is the descriptor for the statement_statistics
+	// table.
+	StatementStatisticsTable = tabledesc.NewImmutable(
+		sqlStatsTableDescriptor("statement_statistics", keys.StatementStatisticsTableID))
+
+	// TransactionStatisticsTable is the descriptor for the
+	// transaction_statistics table.
+	TransactionStatisticsTable = tabledesc.NewImmutable(
+		sqlStatsTableDescriptor("transaction_statistics", keys.TransactionStatisticsTableID))
+
+	// StatementHintsTable is the descriptor for the statement_hints table.
+	StatementHintsTable = tabledesc.NewImmutable(descpb.TableDescriptor{
+		Name:                    "statement_hints",
+		ID:                      keys.StatementHintsTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []descpb.ColumnDescriptor{
+			{Name: "fingerprint", ID: 1, Type: types.String},
+			{Name: "hint_type", ID: 2, Type: types.String},
+			{Name: "hint_value", ID: 3, Type: types.String},
+			{Name: "created_at", ID: 4, Type: types.TimestampTZ, DefaultExpr: &nowTZString},
+		},
+		NextColumnID: 5,
+		Families: []descpb.ColumnFamilyDescriptor{{
+			Name:            "primary",
+			ID:              0,
+			ColumnNames:     []string{"fingerprint", "hint_type", "hint_value", "created_at"},
+			ColumnIDs:       []descpb.ColumnID{1, 2, 3, 4},
+			DefaultColumnID: 4,
+		}},
+		NextFamilyID: 1,
+		PrimaryIndex: descpb.IndexDescriptor{
+			Name:        "primary",
+			ID:          1,
+			Unique:      true,
+			ColumnNames: []string{"fingerprint", "hint_type", "hint_value"},
+			ColumnDirections: []descpb.IndexDescriptor_Direction{
+				descpb.IndexDescriptor_ASC, descpb.IndexDescriptor_ASC, descpb.IndexDescriptor_ASC,
+			},
+			ColumnIDs: []descpb.ColumnID{1, 2, 3},
+			Version:   descpb.EmptyArraysInInvertedIndexesVersion,
+		},
+		NextIndexID: 2,
+		Privileges: descpb.NewCustomSuperuserPrivilegeDescriptor(
+			descpb.SystemAllowedPrivileges[keys.StatementHintsTableID], security.NodeUserName()),
+		FormatVersion:  descpb.InterleavedFormatVersion,
+		NextMutationID: 1,
+	})
 )
 
+// sqlStatsTableDescriptor returns the descriptor shared by the
+// statement_statistics and transaction_statistics tables.
+func sqlStatsTableDescriptor(name string, id descpb.ID) descpb.TableDescriptor {
+	return descpb.TableDescriptor{
+		Name:                    name,
+		ID:                      id,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []descpb.ColumnDescriptor{
+			{Name: "aggregated_ts", ID: 1, Type: types.TimestampTZ},
+			{Name: "fingerprint_id", ID: 2, Type: types.Bytes},
+			{Name: "app_name", ID: 3, Type: types.String},
+			{Name: "node_id", ID: 4, Type: types.Int},
+			{Name: "agg_interval", ID: 5, Type: types.Interval},
+			{Name: "statistics", ID: 6, Type: types.Bytes},
+		},
+		NextColumnID: 7,
+		Families: []descpb.ColumnFamilyDescriptor{{
+			Name: "primary",
+			ID:   0,
+			ColumnNames: []string{
+				"aggregated_ts", "fingerprint_id", "app_name", "node_id",
+				"agg_interval", "statistics",
+			},
+			ColumnIDs: []descpb.ColumnID{1, 2, 3, 4, 5, 6},
+		}},
+		NextFamilyID: 1,
+		PrimaryIndex: descpb.IndexDescriptor{
+			Name:   "primary",
+			ID:     1,
+			Unique: true,
+			ColumnNames: []string{
+				"aggregated_ts", "fingerprint_id", "app_name", "node_id",
+			},
+			ColumnDirections: []descpb.IndexDescriptor_Direction{
+				descpb.IndexDescriptor_ASC, descpb.IndexDescriptor_ASC,
+				descpb.IndexDescriptor_ASC, descpb.IndexDescriptor_ASC,
+			},
+			ColumnIDs: []descpb.ColumnID{1, 2, 3, 4},
+			Version:   descpb.EmptyArraysInInvertedIndexesVersion,
+		},
+		NextIndexID: 2,
+		Privileges: descpb.NewCustomSuperuserPrivilegeDescriptor(
+			descpb.SystemAllowedPrivileges[id], security.NodeUserName()),
+		FormatVersion:  descpb.InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+}
+
 // newCommentPrivilegeDescriptor returns a privilege descriptor for comment table
 func newCommentPrivilegeDescriptor(
 	priv privilege.List, owner security.SQLUsername,
@@ -3384,6 +3384,13 @@ func (desc *Mutable) MakeMutationComplete(m descpb.DescriptorMutation) error {
 					return err
 				}
 			}
+
+			// If the primary key change was performed to change the locality of
+			// the table, the new locality takes effect along with the new indexes.
+			if args.LocalityConfigSwap != nil {
+				newLocalityConfig := args.LocalityConfigSwap.NewLocalityConfig
+				desc.LocalityConfig = &newLocalityConfig
+			}
 		case *descpb.DescriptorMutation_ComputedColumnSwap:
 			if err := desc.performComputedColumnSwap(t.ComputedColumnSwap); err != nil {
 				return err
@@ -0,0 +1,296 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+	"github.com/marusama/semaphore"
+)
+
+// WindowArgs contains the arguments common to all window operators that need
+// to buffer the whole partition before computing the output.
+type WindowArgs struct {
+	EvalCtx *tree.EvalContext
+	// UnlimitedAllocator must be an allocator with an unlimited memory account
+	// since the operators are responsible for staying within MemoryLimit and
+	// spill to disk if necessary.
+	UnlimitedAllocator *colmem.Allocator
+	MemoryLimit        int64
+	DiskQueueCfg       colcontainer.DiskQueueCfg
+	FDSemaphore        semaphore.Semaphore
+	DiskAcc            *mon.BoundAccount
+	Input              colexecbase.Operator
+	InputTypes         []*types.T
+	// OutputColIdx specifies in which coldata.Vec the operator should put its
+	// output (it must be equal to the number of input columns).
+	OutputColIdx int
+	// PartitionColIdx, if not tree.NoColumnIdx, specifies the boolean column
+	// in which 'true' indicates the start of a new partition.
+	PartitionColIdx int
+	// PeersColIdx, if not tree.NoColumnIdx, specifies the boolean column in
+	// which 'true' indicates the start of a new peer group.
+	PeersColIdx int
+}
+
+// bufferedWindower is the interface implemented by the window functions that
+// need to have the whole partition buffered before they can compute the
+// output. The buffering of the partitions as well as the copying of the input
+// columns into the output is handled by bufferedWindowOp.
+type bufferedWindower interface {
+	// init is called once before any other method, buffer is the buffer into
+	// which all tuples of the current partition are appended.
+	init(ctx context.Context, buffer *spillingBuffer)
+	// startNewPartition is called once all tuples of a new partition have been
+	// appended to the buffer.
+	startNewPartition(ctx context.Context)
+	// processBatch computes the output of the window function for the tuples
+	// with indices [startIdx, endIdx) of the current partition and writes it
+	// into the output vector starting at position outputStartIdx. It is called
+	// with consecutive ranges covering the whole partition.
+	processBatch(ctx context.Context, output coldata.Vec, outputStartIdx, startIdx, endIdx int)
+	// close releases any resources held by the window function.
+	close(ctx context.Context)
+}
+
+// newBufferedWindowOperator creates a new Operator that computes the given
+// window function using a spillingBuffer to store the tuples of the current
+// partition.
+func newBufferedWindowOperator(
+	args *WindowArgs, windower bufferedWindower, outputColType *types.T,
+) colexecbase.Operator {
+	outputTypes := make([]*types.T, len(args.InputTypes), len(args.InputTypes)+1)
+	copy(outputTypes, args.InputTypes)
+	outputTypes = append(outputTypes, outputColType)
+	return &bufferedWindowOp{
+		OneInputNode: NewOneInputNode(args.Input),
+		args:         args,
+		windower:     windower,
+		outputTypes:  outputTypes,
+	}
+}
+
+type bufferedWindowState int
+
+const (
+	// windowInitializing is the state in which the operator initializes the
+	// windower. It is done on the first call to Next so that the windower gets
+	// the context of the query.
+	windowInitializing bufferedWindowState = iota
+	// windowLoading is the state in which the operator appends the tuples of
+	// the current partition to the buffer. Once the start of the next
+	// partition is seen (or the input is exhausted), the operator transitions
+	// to windowProcessing state.
+	windowLoading
+	// windowProcessing is the state in which the operator emits the tuples of
+	// the current partition along with the output of the window function.
+	// Once all tuples of the partition are emitted, the operator transitions
+	// back to windowLoading state (or to windowFinished if the input has been
+	// exhausted).
+	windowProcessing
+	// windowFinished is the state in which the operator emits the last
+	// non-empty output batch (if there is one) and then the zero-length
+	// batch.
+	windowFinished
+)
+
+// bufferedWindowOp is an operator that buffers all tuples of a partition
+// before delegating the computation of the window function to a
+// bufferedWindower. The output batches can contain tuples from multiple
+// partitions.
+type bufferedWindowOp struct {
+	OneInputNode
+	closerHelper
+
+	args        *WindowArgs
+	windower    bufferedWindower
+	outputTypes []*types.T
+	state       bufferedWindowState
+
+	buffer *spillingBuffer
+	// currentBatch is the input batch that hasn't been fully appended to the
+	// buffer, and nextIdx is the position of the first tuple in it (according
+	// to the selection vector) that hasn't been appended yet.
+	currentBatch coldata.Batch
+	nextIdx      int
+	// inputDone indicates whether the input has been exhausted.
+	inputDone bool
+	// processingIdx is the index of the first tuple of the current partition
+	// that hasn't been emitted yet.
+	processingIdx int
+	// startedPartition indicates whether startNewPartition has been called on
+	// the windower for the current partition.
+	startedPartition bool
+
+	output    coldata.Batch
+	outputLen int
+}
+
+var _ closableOperator = &bufferedWindowOp{}
+
+func (b *bufferedWindowOp) Init() {
+	b.Input().Init()
+	b.state = windowInitializing
+	b.buffer = newSpillingBuffer(
+		b.args.UnlimitedAllocator, b.args.MemoryLimit, b.args.DiskQueueCfg,
+		b.args.FDSemaphore, b.args.InputTypes, b.args.DiskAcc,
+	)
+	b.output = b.args.UnlimitedAllocator.NewMemBatchWithFixedCapacity(b.outputTypes, coldata.BatchSize())
+}
+
+func (b *bufferedWindowOp) Next(ctx context.Context) coldata.Batch {
+	if b.outputLen == coldata.BatchSize() || b.state == windowFinished {
+		// The output batch has been emitted on the previous call.
+		b.output.ResetInternalBatch()
+		b.outputLen = 0
+	}
+	for {
+		switch b.state {
+		case windowInitializing:
+			b.windower.init(ctx, b.buffer)
+			b.state = windowLoading
+
+		case windowLoading:
+			if b.currentBatch == nil || b.nextIdx >= b.currentBatch.Length() {
+				b.currentBatch = b.Input().Next(ctx)
+				b.nextIdx = 0
+				if b.currentBatch.Length() == 0 {
+					b.inputDone = true
+					if b.buffer.numTuples() > 0 {
+						b.state = windowProcessing
+					} else {
+						b.state = windowFinished
+					}
+					continue
+				}
+			}
+			n := b.currentBatch.Length()
+			endIdx := n
+			if b.args.PartitionColIdx != tree.NoColumnIdx {
+				// Find the start of the next partition (if it is in the
+				// current batch).
+				partitionCol := b.currentBatch.ColVec(b.args.PartitionColIdx).Bool()
+				sel := b.currentBatch.Selection()
+				i := b.nextIdx
+				if b.buffer.numTuples() == 0 {
+					// The first tuple belongs to the current partition.
+					i++
+				}
+				for ; i < n; i++ {
+					tupleIdx := i
+					if sel != nil {
+						tupleIdx = sel[i]
+					}
+					if partitionCol[tupleIdx] {
+						endIdx = i
+						break
+					}
+				}
+			}
+			b.buffer.appendTuples(ctx, b.currentBatch, b.nextIdx, endIdx)
+			b.nextIdx = endIdx
+			if endIdx < n {
+				// The current partition has been fully buffered.
+				b.state = windowProcessing
+			}
+
+		case windowProcessing:
+			if !b.startedPartition {
+				b.windower.startNewPartition(ctx)
+				b.startedPartition = true
+				b.processingIdx = 0
+			}
+			partitionSize := b.buffer.numTuples()
+			toEmit := partitionSize - b.processingIdx
+			if toEmit > coldata.BatchSize()-b.outputLen {
+				toEmit = coldata.BatchSize() - b.outputLen
+			}
+			if len(b.args.InputTypes) > 0 {
+				// Only emit the tuples that are stored contiguously with the
+				// first one, so that the buffer is read in order, without going
+				// back to the tuples stored before the current read position.
+				_, rowIdx, length := b.buffer.getVecWithTuple(ctx, 0 /* colIdx */, b.processingIdx)
+				if toEmit > length-rowIdx {
+					toEmit = length - rowIdx
+				}
+			}
+			startIdx, endIdx := b.processingIdx, b.processingIdx+toEmit
+			inputVecs := b.output.ColVecs()[:len(b.args.InputTypes)]
+			b.args.UnlimitedAllocator.PerformOperation(inputVecs, func() {
+				for colIdx, vec := range inputVecs {
+					src, rowIdx, _ := b.buffer.getVecWithTuple(ctx, colIdx, startIdx)
+					vec.Copy(
+						coldata.CopySliceArgs{
+							SliceArgs: coldata.SliceArgs{
+								Src:         src,
+								DestIdx:     b.outputLen,
+								SrcStartIdx: rowIdx,
+								SrcEndIdx:   rowIdx + toEmit,
+							},
+						},
+					)
+				}
+			})
+			outputVec := b.output.ColVec(b.args.OutputColIdx)
+			b.args.UnlimitedAllocator.PerformOperation([]coldata.Vec{outputVec}, func() {
+				b.windower.processBatch(ctx, outputVec, b.outputLen, startIdx, endIdx)
+			})
+			b.outputLen += toEmit
+			b.processingIdx = endIdx
+			if b.processingIdx == partitionSize {
+				// The current partition has been fully emitted.
+				b.buffer.reset(ctx)
+				b.startedPartition = false
+				if b.inputDone {
+					b.state = windowFinished
+				} else {
+					b.state = windowLoading
+				}
+			}
+			if b.outputLen == coldata.BatchSize() {
+				b.output.SetLength(b.outputLen)
+				return b.output
+			}
+
+		case windowFinished:
+			if b.outputLen > 0 {
+				b.output.SetLength(b.outputLen)
+				return b.output
+			}
+			if err := b.Close(ctx); err != nil {
+				colexecerror.InternalError(err)
+			}
+			return coldata.ZeroBatch
+
+		default:
+			colexecerror.InternalError(errors.AssertionFailedf("window operator in unhandled state"))
+			// This code is unreachable, but the compiler cannot infer that.
+			return nil
+		}
+	}
+}
+
+func (b *bufferedWindowOp) Close(ctx context.Context) error {
+	if !b.close() || b.buffer == nil {
+		return nil
+	}
+	b.windower.close(ctx)
+	return b.buffer.close(ctx)
+}
@@ -241,24 +241,13 @@ func supportedNatively(spec *execinfrapb.ProcessorSpec) error {
 
 	case spec.Core.Windower != nil:
 		for _, wf := range spec.Core.Windower.WindowFns {
-			if wf.Frame != nil {
-				frame, err := wf.Frame.ConvertToAST()
-				if err != nil {
-					return err
-				}
-				if !frame.IsDefaultFrame() {
-					return errors.Newf("window functions with non-default window frames are not supported")
-				}
-			}
 			if wf.FilterColIdx != tree.NoColumnIdx {
 				return errors.Newf("window functions with FILTER clause are not supported")
 			}
-			if wf.Func.AggregateFunc != nil {
-				return errors.Newf("aggregate functions used as window functions are not supported")
-			}
-
-			if _, supported := SupportedWindowFns[*wf.Func.WindowFunc]; !supported {
-				return errors.Newf("window function %s is not supported", wf.String())
+			if wf.Func.WindowFunc != nil {
+				if _, supported := SupportedWindowFns[*wf.Func.WindowFunc]; !supported {
+					return errors.Newf("window function %s is not supported", wf.String())
+				}
 			}
 		}
 		return nil
@@ -293,6 +282,7 @@ func canWrap(mode sessiondatapb.VectorizeExecMode, spec *execinfrapb.ProcessorSp
 	switch {
 	case spec.Core.Noop != nil:
 	case spec.Core.TableReader != nil:
+	case spec.Core.IndexSkipTableReader != nil:
 	case spec.Core.Filterer != nil:
 	case spec.Core.JoinReader != nil:
 	case spec.Core.Sorter != nil:
@@ -1044,14 +1034,14 @@ func NewColOperator(
 			input := inputs[0]
 			result.ColumnTypes = make([]*types.T, len(spec.Input[0].ColumnTypes))
 			copy(result.ColumnTypes, spec.Input[0].ColumnTypes)
-			for _, wf := range core.Windower.WindowFns {
+			for i := range core.Windower.WindowFns {
+				wf := &core.Windower.WindowFns[i]
 				// We allocate the capacity for two extra types because of the
 				// temporary columns that can be appended below.
 				typs := make([]*types.T, len(result.ColumnTypes), len(result.ColumnTypes)+2)
 				copy(typs, result.ColumnTypes)
 				tempColOffset, partitionColIdx := uint32(0), tree.NoColumnIdx
 				peersColIdx := tree.NoColumnIdx
-				windowFn := *wf.Func.WindowFunc
 				if len(core.Windower.PartitionBy) > 0 {
 					// TODO(yuzefovich): add support for hashing partitioner (probably by
 					// leveraging hash routers once we can distribute). The decision about
@@ -1084,7 +1074,7 @@ func NewColOperator(
 				if err != nil {
 					return r, err
 				}
-				if windowFnNeedsPeersInfo(*wf.Func.WindowFunc) {
+				if windowFnNeedsPeersInfo(wf) {
 					peersColIdx = int(wf.OutputColIdx + tempColOffset)
 					input, err = colexec.NewWindowPeerGrouper(
 						streamingAllocator, input, typs, wf.Ordering.Columns,
@@ -1096,38 +1086,94 @@ func NewColOperator(
 					typs[len(typs)-1] = types.Bool
 				}
 
+				argTypes := make([]*types.T, len(wf.ArgsIdxs))
+				for j, idx := range wf.ArgsIdxs {
+					argTypes[j] = typs[idx]
+				}
 				outputIdx := int(wf.OutputColIdx + tempColOffset)
-				switch windowFn {
-				case execinfrapb.WindowerSpec_ROW_NUMBER:
-					result.Op = colexec.NewRowNumberOperator(streamingAllocator, input, outputIdx, partitionColIdx)
-				case execinfrapb.WindowerSpec_RANK, execinfrapb.WindowerSpec_DENSE_RANK:
-					result.Op, err = colexec.NewRankOperator(
-						streamingAllocator, input, windowFn, wf.Ordering.Columns,
+				if wf.Func.AggregateFunc != nil {
+					windowArgs := result.makeWindowArgs(
+						ctx, flowCtx, evalCtx, args, factory, memMonitorsPrefix+"aggregate", input, typs,
 						outputIdx, partitionColIdx, peersColIdx,
 					)
-				case execinfrapb.WindowerSpec_PERCENT_RANK, execinfrapb.WindowerSpec_CUME_DIST:
-					// We are using an unlimited memory monitor here because
-					// relative rank operators themselves are responsible for
-					// making sure that we stay within the memory limit, and
-					// they will fall back to disk if necessary.
-					memAccName := memMonitorsPrefix + "relative-rank"
-					unlimitedAllocator := colmem.NewAllocator(
-						ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, memAccName), factory,
+					aggregations := []execinfrapb.AggregatorSpec_Aggregation{{
+						Func:   *wf.Func.AggregateFunc,
+						ColIdx: wf.ArgsIdxs,
+					}}
+					semaCtx := flowCtx.TypeResolverFactory.NewSemaContext(evalCtx.Txn)
+					constructors, constArguments, outputTypes, err := colexecagg.ProcessAggregations(
+						evalCtx, semaCtx, aggregations, typs,
 					)
-					diskAcc := result.createDiskAccount(ctx, flowCtx, memAccName)
-					result.Op, err = colexec.NewRelativeRankOperator(
-						unlimitedAllocator, execinfra.GetWorkMemLimit(flowCtx.Cfg), args.DiskQueueCfg,
-						args.FDSemaphore, input, typs, windowFn, wf.Ordering.Columns,
-						outputIdx, partitionColIdx, peersColIdx, diskAcc,
+					if err != nil {
+						return r, err
+					}
+					result.Op, err = colexec.NewWindowAggregatorOperator(
+						windowArgs, *wf.Func.AggregateFunc, wf.Frame, &wf.Ordering, wf.ArgsIdxs,
+						constructors, constArguments, outputTypes,
 					)
+					if err != nil {
+						return r, err
+					}
+					result.ToClose = append(result.ToClose, result.Op.(colexecbase.Closer))
+				} else {
+					windowFn := *wf.Func.WindowFunc
+					switch windowFn {
+					case execinfrapb.WindowerSpec_ROW_NUMBER:
+						result.Op = colexec.NewRowNumberOperator(streamingAllocator, input, outputIdx, partitionColIdx)
+					case execinfrapb.WindowerSpec_RANK, execinfrapb.WindowerSpec_DENSE_RANK:
+						result.Op, err = colexec.NewRankOperator(
+							streamingAllocator, input, windowFn, wf.Ordering.Columns,
+							outputIdx, partitionColIdx, peersColIdx,
+						)
+					case execinfrapb.WindowerSpec_PERCENT_RANK, execinfrapb.WindowerSpec_CUME_DIST:
+						// We are using an unlimited memory monitor here because
+						// relative rank operators themselves are responsible for
+						// making sure that we stay within the memory limit, and
+						// they will fall back to disk if necessary.
+						memAccName := memMonitorsPrefix + "relative-rank"
+						unlimitedAllocator := colmem.NewAllocator(
+							ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, memAccName), factory,
+						)
+						diskAcc := result.createDiskAccount(ctx, flowCtx, memAccName)
+						result.Op, err = colexec.NewRelativeRankOperator(
+							unlimitedAllocator, execinfra.GetWorkMemLimit(flowCtx.Cfg), args.DiskQueueCfg,
+							args.FDSemaphore, input, typs, windowFn, wf.Ordering.Columns,
+							outputIdx, partitionColIdx, peersColIdx, diskAcc,
+						)
+					case execinfrapb.WindowerSpec_NTILE:
+						windowArgs := result.makeWindowArgs(
+							ctx, flowCtx, evalCtx, args, factory, memMonitorsPrefix+"ntile", input, typs,
+							outputIdx, partitionColIdx, peersColIdx,
+						)
+						result.Op = colexec.NewNTileOperator(windowArgs, int(wf.ArgsIdxs[0]))
+					case execinfrapb.WindowerSpec_LAG, execinfrapb.WindowerSpec_LEAD:
+						windowArgs := result.makeWindowArgs(
+							ctx, flowCtx, evalCtx, args, factory, memMonitorsPrefix+"lead-lag", input, typs,
+							outputIdx, partitionColIdx, peersColIdx,
+						)
+						result.Op, err = colexec.NewLeadLagOperator(windowArgs, windowFn, wf.ArgsIdxs)
+					case execinfrapb.WindowerSpec_FIRST_VALUE, execinfrapb.WindowerSpec_LAST_VALUE,
+						execinfrapb.WindowerSpec_NTH_VALUE:
+						windowArgs := result.makeWindowArgs(
+							ctx, flowCtx, evalCtx, args, factory, memMonitorsPrefix+"value", input, typs,
+							outputIdx, partitionColIdx, peersColIdx,
+						)
+						result.Op, err = colexec.NewFirstLastNthValueOperator(
+							windowArgs, windowFn, wf.Frame, &wf.Ordering, wf.ArgsIdxs,
+						)
+					default:
+						return r, errors.AssertionFailedf("window function %s is not supported", wf.String())
+					}
+					if err != nil {
+						return r, err
+					}
+					// Operators that buffer the tuples need to be closed (note that
 					// NewRelativeRankOperator sometimes returns a constOp when there
-					// are no ordering columns, so we check that the returned operator
-					// is an Closer.
+					// are no ordering columns, so we check whether the returned
+					// operator is a Closer).
 					if c, ok := result.Op.(colexecbase.Closer); ok {
 						result.ToClose = append(result.ToClose, c)
 					}
-				default:
-					return r, errors.AssertionFailedf("window function %s is not supported", wf.String())
 				}
 
 				if tempColOffset > 0 {
@@ -1141,7 +1187,7 @@ func NewColOperator(
 					result.Op = colexec.NewSimpleProjectOp(result.Op, int(wf.OutputColIdx+tempColOffset), projection)
 				}
 
-				_, returnType, err := execinfrapb.GetWindowFunctionInfo(wf.Func, []*types.T{}...)
+				_, returnType, err := execinfrapb.GetWindowFunctionInfo(wf.Func, argTypes...)
 				if err != nil {
 					return r, err
 				}
@@ -1448,6 +1494,38 @@ func (r opResult) createDiskAccount(
 	return &opDiskAccount
 }
 
+// makeWindowArgs creates the arguments for a window operator that buffers
+// the tuples of the partition. We are using an unlimited memory monitor here
+// because such operators themselves are responsible for making sure that we
+// stay within the memory limit, and they will fall back to disk if necessary.
+func (r opResult) makeWindowArgs(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	evalCtx *tree.EvalContext,
+	args *colexec.NewColOperatorArgs,
+	factory coldata.ColumnFactory,
+	memAccName string,
+	input colexecbase.Operator,
+	inputTypes []*types.T,
+	outputColIdx, partitionColIdx, peersColIdx int,
+) *colexec.WindowArgs {
+	return &colexec.WindowArgs{
+		EvalCtx: evalCtx,
+		UnlimitedAllocator: colmem.NewAllocator(
+			ctx, r.createBufferingUnlimitedMemAccount(ctx, flowCtx, memAccName), factory,
+		),
+		MemoryLimit:     execinfra.GetWorkMemLimit(flowCtx.Cfg),
+		DiskQueueCfg:    args.DiskQueueCfg,
+		FDSemaphore:     args.FDSemaphore,
+		DiskAcc:         r.createDiskAccount(ctx, flowCtx, memAccName),
+		Input:           input,
+		InputTypes:      inputTypes,
+		OutputColIdx:    outputColIdx,
+		PartitionColIdx: partitionColIdx,
+		PeersColIdx:     peersColIdx,
+	}
+}
+
 type postProcessResult struct {
 	Op          colexecbase.Operator
 	ColumnTypes []*types.T
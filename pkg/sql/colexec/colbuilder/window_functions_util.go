@@ -24,6 +24,12 @@ var SupportedWindowFns = map[execinfrapb.WindowerSpec_WindowFunc]struct{}{
 	execinfrapb.WindowerSpec_DENSE_RANK:   {},
 	execinfrapb.WindowerSpec_PERCENT_RANK: {},
 	execinfrapb.WindowerSpec_CUME_DIST:    {},
+	execinfrapb.WindowerSpec_NTILE:        {},
+	execinfrapb.WindowerSpec_LAG:          {},
+	execinfrapb.WindowerSpec_LEAD:         {},
+	execinfrapb.WindowerSpec_FIRST_VALUE:  {},
+	execinfrapb.WindowerSpec_LAST_VALUE:   {},
+	execinfrapb.WindowerSpec_NTH_VALUE:    {},
 }
 
 // windowFnNeedsPeersInfo returns whether a window function pays attention to
@@ -32,10 +38,18 @@ var SupportedWindowFns = map[execinfrapb.WindowerSpec_WindowFunc]struct{}{
 // columns in ORDER BY clause). For most window functions, the result of
 // computation should be the same for "peers", so most window functions do need
 // this information.
-func windowFnNeedsPeersInfo(windowFn execinfrapb.WindowerSpec_WindowFunc) bool {
-	switch windowFn {
-	case execinfrapb.WindowerSpec_ROW_NUMBER:
-		// row_number doesn't pay attention to the concept of "peers."
+func windowFnNeedsPeersInfo(wf *execinfrapb.WindowerSpec_WindowFn) bool {
+	if wf.Func.AggregateFunc != nil {
+		// Aggregate functions are computed over the window frame.
+		return frameNeedsPeersInfo(wf.Frame)
+	}
+	switch *wf.Func.WindowFunc {
+	case
+		execinfrapb.WindowerSpec_ROW_NUMBER,
+		execinfrapb.WindowerSpec_NTILE,
+		execinfrapb.WindowerSpec_LAG,
+		execinfrapb.WindowerSpec_LEAD:
+		// These window functions don't pay attention to the concept of "peers."
 		return false
 	case
 		execinfrapb.WindowerSpec_RANK,
@@ -43,9 +57,23 @@ func windowFnNeedsPeersInfo(windowFn execinfrapb.WindowerSpec_WindowFunc) bool {
 		execinfrapb.WindowerSpec_PERCENT_RANK,
 		execinfrapb.WindowerSpec_CUME_DIST:
 		return true
+	case
+		execinfrapb.WindowerSpec_FIRST_VALUE,
+		execinfrapb.WindowerSpec_LAST_VALUE,
+		execinfrapb.WindowerSpec_NTH_VALUE:
+		return frameNeedsPeersInfo(wf.Frame)
 	default:
-		colexecerror.InternalError(errors.AssertionFailedf("window function %s is not supported", windowFn.String()))
+		colexecerror.InternalError(errors.AssertionFailedf("window function %s is not supported", wf.String()))
 		// This code is unreachable, but the compiler cannot infer that.
 		return false
 	}
 }
+
+// frameNeedsPeersInfo returns whether the bounds of the given window frame
+// depend on the "peers" of the current tuple. Only the frames in ROWS mode
+// without an exclusion clause can be computed without this information (nil
+// frame means the default frame which is in RANGE mode).
+func frameNeedsPeersInfo(frame *execinfrapb.WindowerSpec_Frame) bool {
+	return frame == nil || frame.Mode != execinfrapb.WindowerSpec_Frame_ROWS ||
+		frame.Exclusion != execinfrapb.WindowerSpec_Frame_NO_EXCLUSION
+}
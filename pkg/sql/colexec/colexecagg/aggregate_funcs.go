@@ -46,6 +46,27 @@ func IsAggOptimized(aggFn execinfrapb.AggregatorSpec_Func) bool {
 	}
 }
 
+// AggKind represents the type of the aggregator that is using an aggregate
+// function.
+type AggKind int
+
+const (
+	// HashAggKind indicates the hash aggregation. In this case, each aggregate
+	// function instance processes a single group, and the tuples to be
+	// aggregated are always specified via a selection vector.
+	HashAggKind AggKind = iota
+	// OrderedAggKind indicates the ordered aggregation. In this case, each
+	// aggregate function instance processes all groups, and the boundaries of
+	// the groups are specified by the groups vector.
+	OrderedAggKind
+	// WindowAggKind indicates the aggregate function being used as a window
+	// function. It is similar to HashAggKind in that a single group is being
+	// aggregated (the window frame of the current row), but Flush can be
+	// called multiple times without resetting the function, and the result of
+	// aggregation is retained until Reset is called.
+	WindowAggKind
+)
+
 // AggregateFunc is an aggregate function that performs computation on a batch
 // when Compute(batch) is called and writes the output to the Vec passed in
 // in Init. The AggregateFunc performs an aggregation per group and outputs the
@@ -91,6 +112,11 @@ type AggregateFunc interface {
 	// when the aggregate function is in scalar context. The output must always
 	// be a single value (either null or zero, depending on the function).
 	HandleEmptyInputScalar()
+
+	// Reset resets the aggregate function which allows for reusing the same
+	// instance for computation without the need to create a new instance.
+	// Note that the output vector remains the same.
+	Reset()
 }
 
 type orderedAggregateFuncBase struct {
@@ -121,6 +147,10 @@ func (o *orderedAggregateFuncBase) SetOutputIndex(idx int) {
 	o.curIdx = idx
 }
 
+func (o *orderedAggregateFuncBase) Reset() {
+	o.curIdx = 0
+}
+
 func (o *orderedAggregateFuncBase) HandleEmptyInputScalar() {
 	// Most aggregate functions return a single NULL value on an empty input
 	// in the scalar context (the exceptions are COUNT aggregates which need
@@ -128,6 +158,9 @@ func (o *orderedAggregateFuncBase) HandleEmptyInputScalar() {
 	o.nulls.SetNull(0)
 }
 
+// hashAggregateFuncBase is the base of the aggregate functions used by the
+// hash aggregator as well as of the aggregate functions used as window
+// functions (in both cases, a single group is aggregated at a time).
 type hashAggregateFuncBase struct {
 	allocator *colmem.Allocator
 	// vec is the output vector of this function.
@@ -195,7 +228,7 @@ func NewAggregateFuncsAlloc(
 	constArguments []tree.Datums,
 	outputTypes []*types.T,
 	allocSize int64,
-	isHashAgg bool,
+	aggKind AggKind,
 ) (*AggregateFuncsAlloc, *colconv.VecToDatumConverter, colexecbase.Closers, error) {
 	funcAllocs := make([]aggregateFuncAlloc, len(spec.Aggregations))
 	var toClose colexecbase.Closers
@@ -221,85 +254,124 @@ func NewAggregateFuncsAlloc(
 		var err error
 		switch aggFn.Func {
 		case execinfrapb.AggregatorSpec_ANY_NOT_NULL:
-			if isHashAgg {
+			switch aggKind {
+			case HashAggKind:
 				funcAllocs[i], err = newAnyNotNullHashAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
-			} else {
+			case OrderedAggKind:
 				funcAllocs[i], err = newAnyNotNullOrderedAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
+			default:
+				funcAllocs[i], err = newAnyNotNullWindowAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
 			}
 		case execinfrapb.AggregatorSpec_AVG:
-			if isHashAgg {
+			switch aggKind {
+			case HashAggKind:
 				funcAllocs[i], err = newAvgHashAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
-			} else {
+			case OrderedAggKind:
 				funcAllocs[i], err = newAvgOrderedAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
+			default:
+				funcAllocs[i], err = newAvgWindowAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
 			}
 		case execinfrapb.AggregatorSpec_SUM:
-			if isHashAgg {
+			switch aggKind {
+			case HashAggKind:
 				funcAllocs[i], err = newSumHashAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
-			} else {
+			case OrderedAggKind:
 				funcAllocs[i], err = newSumOrderedAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
+			default:
+				funcAllocs[i], err = newSumWindowAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
 			}
 		case execinfrapb.AggregatorSpec_SUM_INT:
-			if isHashAgg {
+			switch aggKind {
+			case HashAggKind:
 				funcAllocs[i], err = newSumIntHashAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
-			} else {
+			case OrderedAggKind:
 				funcAllocs[i], err = newSumIntOrderedAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
+			default:
+				funcAllocs[i], err = newSumIntWindowAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
 			}
 		case execinfrapb.AggregatorSpec_CONCAT_AGG:
-			if isHashAgg {
+			switch aggKind {
+			case HashAggKind:
 				funcAllocs[i] = newConcatHashAggAlloc(allocator, allocSize)
-			} else {
+			case OrderedAggKind:
 				funcAllocs[i] = newConcatOrderedAggAlloc(allocator, allocSize)
+			default:
+				funcAllocs[i] = newConcatWindowAggAlloc(allocator, allocSize)
 			}
 		case execinfrapb.AggregatorSpec_COUNT_ROWS:
-			if isHashAgg {
+			switch aggKind {
+			case HashAggKind:
 				funcAllocs[i] = newCountRowsHashAggAlloc(allocator, allocSize)
-			} else {
+			case OrderedAggKind:
 				funcAllocs[i] = newCountRowsOrderedAggAlloc(allocator, allocSize)
+			default:
+				funcAllocs[i] = newCountRowsWindowAggAlloc(allocator, allocSize)
 			}
 		case execinfrapb.AggregatorSpec_COUNT:
-			if isHashAgg {
+			switch aggKind {
+			case HashAggKind:
 				funcAllocs[i] = newCountHashAggAlloc(allocator, allocSize)
-			} else {
+			case OrderedAggKind:
 				funcAllocs[i] = newCountOrderedAggAlloc(allocator, allocSize)
+			default:
+				funcAllocs[i] = newCountWindowAggAlloc(allocator, allocSize)
 			}
 		case execinfrapb.AggregatorSpec_MIN:
-			if isHashAgg {
+			switch aggKind {
+			case HashAggKind:
 				funcAllocs[i] = newMinHashAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
-			} else {
+			case OrderedAggKind:
 				funcAllocs[i] = newMinOrderedAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
+			default:
+				funcAllocs[i] = newMinWindowAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
 			}
 		case execinfrapb.AggregatorSpec_MAX:
-			if isHashAgg {
+			switch aggKind {
+			case HashAggKind:
 				funcAllocs[i] = newMaxHashAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
-			} else {
+			case OrderedAggKind:
 				funcAllocs[i] = newMaxOrderedAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
+			default:
+				funcAllocs[i] = newMaxWindowAggAlloc(allocator, inputTypes[aggFn.ColIdx[0]], allocSize)
 			}
 		case execinfrapb.AggregatorSpec_BOOL_AND:
-			if isHashAgg {
+			switch aggKind {
+			case HashAggKind:
 				funcAllocs[i] = newBoolAndHashAggAlloc(allocator, allocSize)
-			} else {
+			case OrderedAggKind:
 				funcAllocs[i] = newBoolAndOrderedAggAlloc(allocator, allocSize)
+			default:
+				funcAllocs[i] = newBoolAndWindowAggAlloc(allocator, allocSize)
 			}
 		case execinfrapb.AggregatorSpec_BOOL_OR:
-			if isHashAgg {
+			switch aggKind {
+			case HashAggKind:
 				funcAllocs[i] = newBoolOrHashAggAlloc(allocator, allocSize)
-			} else {
+			case OrderedAggKind:
 				funcAllocs[i] = newBoolOrOrderedAggAlloc(allocator, allocSize)
+			default:
+				funcAllocs[i] = newBoolOrWindowAggAlloc(allocator, allocSize)
 			}
 		// NOTE: if you're adding an implementation of a new aggregate
 		// function, make sure to account for the memory under that struct in
 		// its constructor.
 		default:
-			if isHashAgg {
+			switch aggKind {
+			case HashAggKind:
 				funcAllocs[i] = newDefaultHashAggAlloc(
 					allocator, constructors[i], evalCtx, inputArgsConverter,
 					len(aggFn.ColIdx), constArguments[i], outputTypes[i], allocSize,
 				)
-			} else {
+			case OrderedAggKind:
 				funcAllocs[i] = newDefaultOrderedAggAlloc(
 					allocator, constructors[i], evalCtx, inputArgsConverter,
 					len(aggFn.ColIdx), constArguments[i], outputTypes[i], allocSize,
 				)
+			default:
+				funcAllocs[i] = newDefaultWindowAggAlloc(
+					allocator, constructors[i], evalCtx, inputArgsConverter,
+					len(aggFn.ColIdx), constArguments[i], outputTypes[i], allocSize,
+				)
 			}
 			toClose = append(toClose, funcAllocs[i].(colexecbase.Closer))
 		}
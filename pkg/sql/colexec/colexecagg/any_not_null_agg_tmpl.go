@@ -94,7 +94,7 @@ func (a *anyNotNull_TYPE_AGGKINDAgg) SetOutput(vec coldata.Vec) {
 func (a *anyNotNull_TYPE_AGGKINDAgg) Compute(
 	vecs []coldata.Vec, inputIdxs []uint32, inputLen int, sel []int,
 ) {
-	// {{if eq "_AGGKIND" "Hash"}}
+	// {{if ne "_AGGKIND" "Ordered"}}
 	if a.foundNonNullForCurrentGroup {
 		// We have already seen non-null for the current group, and since there
 		// is at most a single group when performing hash aggregation, we can
@@ -181,8 +181,29 @@ func (a *anyNotNull_TYPE_AGGKINDAgg) Flush(outputIdx int) {
 	} else {
 		execgen.SET(a.col, outputIdx, a.curAgg)
 	}
+	// {{if ne "_AGGKIND" "Window"}}
+	// {{if or (eq .VecMethod "Bytes") (eq .VecMethod "Datum")}}
+	// Release the reference to curAgg eagerly. We can't do this for the window
+	// variant because we might need to reuse curAgg between subsequent calls
+	// to Compute.
+	// {{if eq .VecMethod "Bytes"}}
+	a.allocator.AdjustMemoryUsage(-int64(len(a.curAgg)))
+	// {{else}}
+	if d, ok := a.curAgg.(*coldataext.Datum); ok {
+		a.allocator.AdjustMemoryUsage(-int64(d.Size()))
+	}
+	// {{end}}
+	a.curAgg = nil
+	// {{end}}
+	// {{end}}
+}
+
+func (a *anyNotNull_TYPE_AGGKINDAgg) Reset() {
+	// {{if eq "_AGGKIND" "Ordered"}}
+	a.orderedAggregateFuncBase.Reset()
+	// {{end}}
+	a.foundNonNullForCurrentGroup = false
 	// {{if or (eq .VecMethod "Bytes") (eq .VecMethod "Datum")}}
-	// Release the reference to curAgg eagerly.
 	// {{if eq .VecMethod "Bytes"}}
 	a.allocator.AdjustMemoryUsage(-int64(len(a.curAgg)))
 	// {{else}}
@@ -259,7 +280,7 @@ func _FIND_ANY_NOT_NULL(
 		execgen.COPYVAL(a.curAgg, val)
 		// {{end}}
 		a.foundNonNullForCurrentGroup = true
-		// {{if eq "_AGGKIND" "Hash"}}
+		// {{if ne "_AGGKIND" "Ordered"}}
 		// We have already seen non-null for the current group, and since there
 		// is at most a single group when performing hash aggregation, we can
 		// finish computing.
@@ -132,6 +132,14 @@ func (a *bool_OP_TYPE_AGGKINDAgg) Flush(outputIdx int) {
 	}
 }
 
+func (a *bool_OP_TYPE_AGGKINDAgg) Reset() {
+	// {{if eq "_AGGKIND" "Ordered"}}
+	a.orderedAggregateFuncBase.Reset()
+	// {{end}}
+	a.curAgg = _DEFAULT_VAL
+	a.sawNonNull = false
+}
+
 type bool_OP_TYPE_AGGKINDAggAlloc struct {
 	aggAllocBase
 	aggFuncs []bool_OP_TYPE_AGGKINDAgg
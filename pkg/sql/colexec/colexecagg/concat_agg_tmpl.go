@@ -114,7 +114,20 @@ func (a *concat_AGGKINDAgg) Flush(outputIdx int) {
 	} else {
 		a.col.Set(outputIdx, a.curAgg)
 	}
-	// Release the reference to curAgg eagerly.
+	// {{if ne "_AGGKIND" "Window"}}
+	// Release the reference to curAgg eagerly. We can't do this for the window
+	// variant because we might need to reuse curAgg between subsequent calls
+	// to Compute.
+	a.allocator.AdjustMemoryUsage(-int64(len(a.curAgg)))
+	a.curAgg = nil
+	// {{end}}
+}
+
+func (a *concat_AGGKINDAgg) Reset() {
+	// {{if eq "_AGGKIND" "Ordered"}}
+	a.orderedAggregateFuncBase.Reset()
+	// {{end}}
+	a.foundNonNullForCurrentGroup = false
 	a.allocator.AdjustMemoryUsage(-int64(len(a.curAgg)))
 	a.curAgg = nil
 }
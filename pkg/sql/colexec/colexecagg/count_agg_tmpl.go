@@ -101,7 +101,7 @@ func (a *count_COUNTKIND_AGGKINDAgg) Compute(
 			} else
 			// {{end}}
 			{
-				// {{if eq "_AGGKIND" "Hash"}}
+				// {{if ne "_AGGKIND" "Ordered"}}
 				// We don't need to pay attention to nulls (either because it's a
 				// COUNT_ROWS aggregate or because there are no nulls), and we're
 				// performing a hash aggregation (meaning there is a single group),
@@ -128,6 +128,13 @@ func (a *count_COUNTKIND_AGGKINDAgg) Flush(outputIdx int) {
 	a.col[outputIdx] = a.curAgg
 }
 
+func (a *count_COUNTKIND_AGGKINDAgg) Reset() {
+	// {{if eq "_AGGKIND" "Ordered"}}
+	a.orderedAggregateFuncBase.Reset()
+	// {{end}}
+	a.curAgg = 0
+}
+
 // {{if eq "_AGGKIND" "Ordered"}}
 func (a *count_COUNTKIND_AGGKINDAgg) HandleEmptyInputScalar() {
 	// COUNT aggregates are special because they return zero in case of an
@@ -77,7 +77,7 @@ func (a *default_AGGKINDAgg) Compute(
 		} else
 		// {{end}}
 		{
-			// {{if eq "_AGGKIND" "Hash"}}
+			// {{if ne "_AGGKIND" "Ordered"}}
 			// We don't need to check whether sel is non-nil in case of the
 			// hash aggregator because it always uses non-nil sel to specify
 			// the tuples to be aggregated.
@@ -102,6 +102,13 @@ func (a *default_AGGKINDAgg) Flush(outputIdx int) {
 	_SET_RESULT(a, outputIdx)
 }
 
+func (a *default_AGGKINDAgg) Reset() {
+	// {{if eq "_AGGKIND" "Ordered"}}
+	a.orderedAggregateFuncBase.Reset()
+	// {{end}}
+	a.fn.Reset(a.ctx)
+}
+
 // {{if eq "_AGGKIND" "Ordered"}}
 func (a *default_AGGKINDAgg) HandleEmptyInputScalar() {
 	outputIdx := 0
@@ -120,7 +120,7 @@ type _AGG_TYPE_AGGKINDAgg struct {
 	// {{end}}
 	// col points to the output vector we are updating.
 	col _GOTYPESLICE
-	// {{if eq "_AGGKIND" "Hash"}}
+	// {{if ne "_AGGKIND" "Ordered"}}
 	hashAggregateFuncBase
 	// {{end}}
 	// curAgg holds the running min/max, so we can index into the slice once per
@@ -225,8 +225,29 @@ func (a *_AGG_TYPE_AGGKINDAgg) Flush(outputIdx int) {
 	} else {
 		execgen.SET(a.col, outputIdx, a.curAgg)
 	}
+	// {{if ne "_AGGKIND" "Window"}}
+	// {{if or (eq .VecMethod "Bytes") (eq .VecMethod "Datum")}}
+	// Release the reference to curAgg eagerly. We can't do this for the window
+	// variant because we might need to reuse curAgg between subsequent calls
+	// to Compute.
+	// {{if eq .VecMethod "Bytes"}}
+	a.allocator.AdjustMemoryUsage(-int64(len(a.curAgg)))
+	// {{else}}
+	if d, ok := a.curAgg.(*coldataext.Datum); ok {
+		a.allocator.AdjustMemoryUsage(-int64(d.Size()))
+	}
+	// {{end}}
+	a.curAgg = nil
+	// {{end}}
+	// {{end}}
+}
+
+func (a *_AGG_TYPE_AGGKINDAgg) Reset() {
+	// {{if eq "_AGGKIND" "Ordered"}}
+	a.orderedAggregateFuncBase.Reset()
+	// {{end}}
+	a.foundNonNullForCurrentGroup = false
 	// {{if or (eq .VecMethod "Bytes") (eq .VecMethod "Datum")}}
-	// Release the reference to curAgg eagerly.
 	// {{if eq .VecMethod "Bytes"}}
 	a.allocator.AdjustMemoryUsage(-int64(len(a.curAgg)))
 	// {{else}}
@@ -19,10 +19,11 @@ import (
 const (
 	// aggKindTmplVar specifies the template "variable" that describes the kind
 	// of aggregator using an aggregate function. It is replaced with either
-	// "Hash" or "Ordered" before executing the template.
+	// "Hash", "Ordered", or "Window" before executing the template.
 	aggKindTmplVar = "_AGGKIND"
 	hashAggKind    = "Hash"
 	orderedAggKind = "Ordered"
+	windowAggKind  = "Window"
 )
 
 func registerAggGenerator(aggGen generator, filenameSuffix, dep string) {
@@ -32,7 +33,7 @@ func registerAggGenerator(aggGen generator, filenameSuffix, dep string) {
 			return aggGen(inputFileContents, wr)
 		}
 	}
-	for _, aggKind := range []string{hashAggKind, orderedAggKind} {
+	for _, aggKind := range []string{hashAggKind, orderedAggKind, windowAggKind} {
 		registerGenerator(
 			aggGeneratorAdapter(aggKind),
 			fmt.Sprintf("%s_%s", strings.ToLower(aggKind), filenameSuffix),
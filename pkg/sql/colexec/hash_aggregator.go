@@ -146,7 +146,7 @@ func NewHashAggregator(
 ) (colexecbase.Operator, error) {
 	aggFnsAlloc, inputArgsConverter, toClose, err := colexecagg.NewAggregateFuncsAlloc(
 		allocator, inputTypes, spec, evalCtx, constructors, constArguments,
-		outputTypes, hashAggregatorAllocSize, true, /* isHashAgg */
+		outputTypes, hashAggregatorAllocSize, colexecagg.HashAggKind,
 	)
 	// We want this number to be coldata.MaxBatchSize, but then we would lose
 	// some test coverage due to disabling of the randomization of the batch
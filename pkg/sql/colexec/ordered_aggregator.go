@@ -184,7 +184,7 @@ func NewOrderedAggregator(
 	// allocation size.
 	funcsAlloc, inputArgsConverter, toClose, err := colexecagg.NewAggregateFuncsAlloc(
 		allocator, inputTypes, spec, evalCtx, constructors, constArguments,
-		outputTypes, 1 /* allocSize */, false, /* isHashAgg */
+		outputTypes, 1 /* allocSize */, colexecagg.OrderedAggKind,
 	)
 	if err != nil {
 		return nil, errors.AssertionFailedf(
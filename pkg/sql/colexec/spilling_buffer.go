@@ -0,0 +1,324 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+	"github.com/marusama/semaphore"
+)
+
+// spillingBuffer is an append-only buffer of tuples that supports random
+// access by the index of a tuple. Tuples are kept in memory until the memory
+// limit is reached, at which point all of the subsequently appended tuples
+// are spilled to disk using a rewindable DiskQueue.
+//
+// The intended usage pattern is to append all of the tuples first and only
+// then read them; appending is not allowed once the reading has begun until
+// the buffer is reset.
+//
+// Reading is the most efficient when the tuples are accessed in an increasing
+// order of their indices since accessing a tuple that was spilled to disk and
+// comes before the most recently accessed one requires rewinding the disk
+// queue.
+type spillingBuffer struct {
+	unlimitedAllocator *colmem.Allocator
+	memoryLimit        int64
+	typs               []*types.T
+
+	// bufferedTuples contains the tuples that are kept in memory.
+	bufferedTuples *appendOnlyBufferedBatch
+	// length is the total number of tuples in the buffer (both in memory and
+	// on disk).
+	length int
+	// spilled indicates whether the tuples are currently being appended to
+	// the disk queue.
+	spilled bool
+	// reading indicates whether the buffer is being read from and, thus, no
+	// more tuples can be appended to it.
+	reading bool
+
+	diskQueueCfg colcontainer.DiskQueueCfg
+	diskQueue    colcontainer.RewindableQueue
+	fdSemaphore  semaphore.Semaphore
+	diskAcc      *mon.BoundAccount
+	// fdsAcquired indicates whether the file descriptors for the disk queue
+	// have been acquired from fdSemaphore.
+	fdsAcquired bool
+
+	// pending is the batch into which the tuples are accumulated before being
+	// enqueued to the disk queue. All batches in the disk queue (except for
+	// the last one) have exactly coldata.BatchSize() tuples which allows us to
+	// easily compute which batch contains a tuple with a particular index.
+	pending coldata.Batch
+	// dequeued is the batch that was the most recently dequeued from the disk
+	// queue, and numDequeued is the number of batches that have been dequeued
+	// since the last rewind (meaning that 'dequeued' batch has the ordinal
+	// numDequeued-1 among the batches on disk).
+	dequeued    coldata.Batch
+	numDequeued int
+}
+
+// newSpillingBuffer creates a new spillingBuffer. An unlimited allocator must
+// be passed in. The spillingBuffer will use this allocator to check whether
+// memory usage exceeds the given memory limit and use disk if so.
+func newSpillingBuffer(
+	unlimitedAllocator *colmem.Allocator,
+	memoryLimit int64,
+	cfg colcontainer.DiskQueueCfg,
+	fdSemaphore semaphore.Semaphore,
+	typs []*types.T,
+	diskAcc *mon.BoundAccount,
+) *spillingBuffer {
+	// Reduce the memory limit by what the DiskQueue may need to buffer
+	// writes/reads.
+	memoryLimit -= int64(cfg.BufferSizeBytes)
+	if memoryLimit < 0 {
+		memoryLimit = 0
+	}
+	return &spillingBuffer{
+		unlimitedAllocator: unlimitedAllocator,
+		memoryLimit:        memoryLimit,
+		typs:               typs,
+		bufferedTuples:     newAppendOnlyBufferedBatch(unlimitedAllocator, typs, nil /* colsToStore */),
+		diskQueueCfg:       cfg,
+		fdSemaphore:        fdSemaphore,
+		diskAcc:            diskAcc,
+	}
+}
+
+// appendTuples appends the tuples in range [startIdx, endIdx) of the batch
+// (paying attention to the selection vector) to the buffer.
+func (b *spillingBuffer) appendTuples(
+	ctx context.Context, batch coldata.Batch, startIdx, endIdx int,
+) {
+	if b.reading {
+		colexecerror.InternalError(errors.AssertionFailedf(
+			"unexpectedly appending to spillingBuffer after reading from it",
+		))
+	}
+	if startIdx >= endIdx {
+		return
+	}
+	if !b.spilled && b.unlimitedAllocator.Used() <= b.memoryLimit {
+		b.unlimitedAllocator.PerformOperation(b.bufferedTuples.ColVecs(), func() {
+			b.bufferedTuples.append(batch, startIdx, endIdx)
+		})
+		b.length += endIdx - startIdx
+		return
+	}
+	if !b.spilled {
+		if err := b.maybeCreateDiskQueue(ctx); err != nil {
+			colexecerror.InternalError(err)
+		}
+		b.spilled = true
+	}
+	sel := batch.Selection()
+	for startIdx < endIdx {
+		if b.pending == nil {
+			b.pending = b.unlimitedAllocator.NewMemBatchWithFixedCapacity(b.typs, coldata.BatchSize())
+		}
+		pendingLen := b.pending.Length()
+		toAppend := endIdx - startIdx
+		if pendingLen+toAppend > coldata.BatchSize() {
+			toAppend = coldata.BatchSize() - pendingLen
+		}
+		b.unlimitedAllocator.PerformOperation(b.pending.ColVecs(), func() {
+			for colIdx, vec := range b.pending.ColVecs() {
+				vec.Copy(
+					coldata.CopySliceArgs{
+						SliceArgs: coldata.SliceArgs{
+							Src:         batch.ColVec(colIdx),
+							Sel:         sel,
+							DestIdx:     pendingLen,
+							SrcStartIdx: startIdx,
+							SrcEndIdx:   startIdx + toAppend,
+						},
+					},
+				)
+			}
+			b.pending.SetLength(pendingLen + toAppend)
+		})
+		startIdx += toAppend
+		b.length += toAppend
+		if b.pending.Length() == coldata.BatchSize() {
+			b.flushPending(ctx)
+		}
+	}
+}
+
+// flushPending enqueues all of the pending tuples to the disk queue.
+func (b *spillingBuffer) flushPending(ctx context.Context) {
+	if b.pending == nil || b.pending.Length() == 0 {
+		return
+	}
+	if err := b.diskQueue.Enqueue(ctx, b.pending); err != nil {
+		colexecerror.InternalError(err)
+	}
+	b.pending.ResetInternalBatch()
+	b.pending.SetLength(0)
+}
+
+// getVecWithTuple returns the vector for the given column that contains the
+// tuple with the given index. The returned rowIdx is the position of the tuple
+// within the vector, and length is the number of tuples in the vector (all
+// tuples in [rowIdx, length) range of the vector are stored contiguously in
+// the buffer). The returned vector must not be modified and is only valid
+// until the next call to getVecWithTuple.
+func (b *spillingBuffer) getVecWithTuple(
+	ctx context.Context, colIdx int, idx int,
+) (vec coldata.Vec, rowIdx int, length int) {
+	if idx < 0 || idx >= b.length {
+		colexecerror.InternalError(errors.AssertionFailedf(
+			"index %d out of range [0, %d) in spillingBuffer", idx, b.length,
+		))
+	}
+	if !b.reading {
+		b.reading = true
+		if b.spilled {
+			// Finish writing to the disk queue.
+			b.flushPending(ctx)
+			if err := b.diskQueue.Enqueue(ctx, coldata.ZeroBatch); err != nil {
+				colexecerror.InternalError(err)
+			}
+			if b.pending != nil {
+				b.unlimitedAllocator.ReleaseBatch(b.pending)
+				b.pending = nil
+			}
+		}
+	}
+	numInMemory := b.bufferedTuples.Length()
+	if idx < numInMemory {
+		return b.bufferedTuples.ColVec(colIdx), idx, numInMemory
+	}
+	idx -= numInMemory
+	batchIdx := idx / coldata.BatchSize()
+	if batchIdx < b.numDequeued-1 {
+		// The batch we need has already been dequeued, so we have to rewind.
+		if err := b.diskQueue.Rewind(); err != nil {
+			colexecerror.InternalError(err)
+		}
+		b.numDequeued = 0
+	}
+	for b.numDequeued <= batchIdx {
+		if b.dequeued == nil {
+			b.dequeued = b.unlimitedAllocator.NewMemBatchWithFixedCapacity(b.typs, coldata.BatchSize())
+		}
+		// The footprint of the batch might change when dequeueing into it, so
+		// it is accounted for again once the batch has been dequeued.
+		b.unlimitedAllocator.ReleaseBatch(b.dequeued)
+		ok, err := b.diskQueue.Dequeue(ctx, b.dequeued)
+		if err != nil {
+			colexecerror.InternalError(err)
+		}
+		if !ok || b.dequeued.Length() == 0 {
+			colexecerror.InternalError(errors.AssertionFailedf(
+				"unexpectedly failed to dequeue a batch in spillingBuffer",
+			))
+		}
+		b.unlimitedAllocator.RetainBatch(b.dequeued)
+		b.numDequeued++
+	}
+	return b.dequeued.ColVec(colIdx), idx % coldata.BatchSize(), b.dequeued.Length()
+}
+
+// numFDsOpenAtAnyGivenTime returns the number of file descriptors that the
+// disk queue of the buffer might have open.
+func (b *spillingBuffer) numFDsOpenAtAnyGivenTime() int {
+	if b.diskQueueCfg.CacheMode != colcontainer.DiskQueueCacheModeDefault {
+		// The access pattern must be write-everything then read-everything so
+		// either a read FD or a write FD are open at any one point.
+		return 1
+	}
+	// Otherwise, both will be open.
+	return 2
+}
+
+func (b *spillingBuffer) maybeCreateDiskQueue(ctx context.Context) error {
+	if b.diskQueue != nil {
+		return nil
+	}
+	if b.fdSemaphore != nil && !b.fdsAcquired {
+		if err := b.fdSemaphore.Acquire(ctx, b.numFDsOpenAtAnyGivenTime()); err != nil {
+			return err
+		}
+		b.fdsAcquired = true
+	}
+	log.VEvent(ctx, 1, "spilled to disk")
+	diskQueue, err := colcontainer.NewRewindableDiskQueue(ctx, b.typs, b.diskQueueCfg, b.diskAcc)
+	if err != nil {
+		return err
+	}
+	// Only assign b.diskQueue if there was no error, otherwise the returned
+	// value may be non-nil but invalid.
+	b.diskQueue = diskQueue
+	return nil
+}
+
+// numTuples returns the number of tuples in the buffer.
+func (b *spillingBuffer) numTuples() int {
+	return b.length
+}
+
+// reset removes all tuples from the buffer, closing the disk queue if there
+// is one. The buffer can be appended to afterwards.
+func (b *spillingBuffer) reset(ctx context.Context) {
+	if err := b.closeDiskQueue(ctx); err != nil {
+		colexecerror.InternalError(err)
+	}
+	// Release the memory used by the tuples of the previous contents so that
+	// the buffer doesn't spill to disk because of them, and the batches used
+	// for the disk queue are allocated again if it is needed.
+	b.unlimitedAllocator.ReleaseBatch(b.bufferedTuples)
+	b.bufferedTuples = newAppendOnlyBufferedBatch(b.unlimitedAllocator, b.typs, nil /* colsToStore */)
+	if b.pending != nil {
+		b.unlimitedAllocator.ReleaseBatch(b.pending)
+		b.pending = nil
+	}
+	if b.dequeued != nil {
+		b.unlimitedAllocator.ReleaseBatch(b.dequeued)
+		b.dequeued = nil
+	}
+	b.length = 0
+	b.spilled = false
+	b.reading = false
+	b.numDequeued = 0
+}
+
+func (b *spillingBuffer) closeDiskQueue(ctx context.Context) error {
+	var err error
+	if b.diskQueue != nil {
+		err = b.diskQueue.Close(ctx)
+		b.diskQueue = nil
+	}
+	if b.fdsAcquired {
+		b.fdSemaphore.Release(b.numFDsOpenAtAnyGivenTime())
+		b.fdsAcquired = false
+	}
+	return err
+}
+
+// close releases all of the resources held by the buffer.
+func (b *spillingBuffer) close(ctx context.Context) error {
+	err := b.closeDiskQueue(ctx)
+	b.bufferedTuples = nil
+	b.pending = nil
+	b.dequeued = nil
+	return err
+}
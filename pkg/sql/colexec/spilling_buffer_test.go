@@ -0,0 +1,83 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/colcontainerutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpillingBuffer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	queueCfg, cleanup := colcontainerutils.NewTestingDiskQueueCfg(t, true /* inMem */)
+	defer cleanup()
+
+	memAcc := testMemMonitor.MakeBoundAccount()
+	defer memAcc.Close(ctx)
+	allocator := colmem.NewAllocator(ctx, &memAcc, testColumnFactory)
+	typs := []*types.T{types.Int}
+	buf := newSpillingBuffer(
+		allocator, 0 /* memoryLimit */, queueCfg, colexecbase.NewTestingSemaphore(2 /* limit */), typs, testDiskAcc,
+	)
+	// Only allow for the first appended batch to be kept in memory.
+	buf.memoryLimit = allocator.Used()
+	defer func() { require.NoError(t, buf.close(ctx)) }()
+
+	const numBatches = 4
+	batch := testAllocator.NewMemBatchWithFixedCapacity(typs, coldata.BatchSize())
+	appendTuples := func(numBatches int) {
+		for i := 0; i < numBatches; i++ {
+			col := batch.ColVec(0).Int64()
+			for j := range col {
+				col[j] = int64(i*coldata.BatchSize() + j)
+			}
+			batch.SetLength(coldata.BatchSize())
+			buf.appendTuples(ctx, batch, 0 /* startIdx */, coldata.BatchSize())
+		}
+	}
+	readTuple := func(idx int) int64 {
+		vec, rowIdx, length := buf.getVecWithTuple(ctx, 0 /* colIdx */, idx)
+		require.Less(t, rowIdx, length)
+		return vec.Int64()[rowIdx]
+	}
+
+	appendTuples(numBatches)
+	require.True(t, buf.spilled)
+	require.Equal(t, numBatches*coldata.BatchSize(), buf.numTuples())
+	for idx := 0; idx < buf.numTuples(); idx++ {
+		require.Equal(t, int64(idx), readTuple(idx))
+	}
+	// Going back to a tuple that was spilled requires rewinding the disk queue.
+	require.Equal(t, int64(coldata.BatchSize()), readTuple(coldata.BatchSize()))
+
+	// Resetting the buffer releases the memory used by its tuples, so a
+	// partition that fits in memory is not spilled.
+	buf.reset(ctx)
+	require.Zero(t, buf.numTuples())
+	require.LessOrEqual(t, allocator.Used(), buf.memoryLimit)
+	appendTuples(1 /* numBatches */)
+	require.False(t, buf.spilled)
+	for idx := 0; idx < buf.numTuples(); idx++ {
+		require.Equal(t, int64(idx), readTuple(idx))
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecagg"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// NewWindowAggregatorOperator creates a new Operator that computes the given
+// aggregate function used as a window function over the given window frame.
+// constructors, constArguments and outputTypes are the results of
+// colexecagg.ProcessAggregations for the aggregate function.
+func NewWindowAggregatorOperator(
+	args *WindowArgs,
+	aggFn execinfrapb.AggregatorSpec_Func,
+	frame *execinfrapb.WindowerSpec_Frame,
+	ordering *execinfrapb.Ordering,
+	argIdxs []uint32,
+	constructors []execinfrapb.AggregateConstructor,
+	constArguments []tree.Datums,
+	outputTypes []*types.T,
+) (colexecbase.Operator, error) {
+	framer, err := newWindowFramer(args.EvalCtx, frame, ordering, args.InputTypes, args.PeersColIdx)
+	if err != nil {
+		return nil, err
+	}
+	aggSpec := &execinfrapb.AggregatorSpec{
+		Aggregations: []execinfrapb.AggregatorSpec_Aggregation{{Func: aggFn, ColIdx: argIdxs}},
+	}
+	aggAlloc, inputArgsConverter, toClose, err := colexecagg.NewAggregateFuncsAlloc(
+		args.UnlimitedAllocator, args.InputTypes, aggSpec, args.EvalCtx, constructors,
+		constArguments, outputTypes, 1 /* allocSize */, colexecagg.WindowAggKind,
+	)
+	if err != nil {
+		return nil, err
+	}
+	windower := &windowAggregator{
+		allocator:          args.UnlimitedAllocator,
+		framer:             framer,
+		agg:                aggAlloc.MakeAggregateFuncs()[0],
+		result:             args.UnlimitedAllocator.NewMemColumn(outputTypes[0], 1 /* capacity */),
+		argIdxs:            argIdxs,
+		inputArgsConverter: inputArgsConverter,
+		vecs:               make([]coldata.Vec, len(args.InputTypes)),
+		toClose:            toClose,
+		// The builtin implementation of json_object_agg assumes that the
+		// returned object is immutable once Result has been called, so adding
+		// more tuples to the aggregation afterwards isn't allowed.
+		alwaysReset: aggFn == execinfrapb.AggregatorSpec_JSON_OBJECT_AGG ||
+			aggFn == execinfrapb.AggregatorSpec_JSONB_OBJECT_AGG,
+	}
+	windower.agg.SetOutput(windower.result)
+	return newBufferedWindowOperator(args, windower, outputTypes[0]), nil
+}
+
+// windowAggregator computes an aggregate function used as a window function.
+//
+// The aggregate function is computed incrementally when possible: if the
+// frame of a tuple is the same as the frame of the previous tuple (which is
+// always the case for peers with the default frame), the previous result is
+// reused, and if the frame only grew at the end, only the new tuples are
+// added to the aggregation. Otherwise, the aggregate function is reset and
+// computed over the whole frame.
+type windowAggregator struct {
+	bufferedWindowerBase
+	allocator *colmem.Allocator
+	framer    *windowFramer
+	agg       colexecagg.AggregateFunc
+	// result is a single-element vector into which the aggregate function
+	// flushes its result whenever the frame changes. It allows us to reuse the
+	// result for the tuples with the same frame without calling Flush again
+	// (some of the builtin aggregate functions don't support retrieving the
+	// result multiple times without any changes in between).
+	result  coldata.Vec
+	argIdxs []uint32
+	// inputArgsConverter converts the arguments to tree.Datums in case the
+	// aggregate function doesn't have an optimized implementation.
+	inputArgsConverter *colconv.VecToDatumConverter
+	// vecs contains the windows into the vectors of the argument columns that
+	// are currently being aggregated.
+	vecs []coldata.Vec
+	sel  []int
+	// prevIntervals is the frame of the previous tuple, and prevValid
+	// indicates whether it is valid.
+	prevIntervals []windowInterval
+	prevValid     bool
+	// alwaysReset indicates whether the aggregate function must be reset
+	// whenever the frame changes (i.e. it cannot be computed incrementally).
+	alwaysReset bool
+	toClose     colexecbase.Closers
+}
+
+var _ bufferedWindower = &windowAggregator{}
+
+func (w *windowAggregator) startNewPartition(ctx context.Context) {
+	w.framer.startPartition(ctx, w.buffer)
+	w.prevValid = false
+}
+
+func (w *windowAggregator) processBatch(
+	ctx context.Context, output coldata.Vec, outputStartIdx, startIdx, endIdx int,
+) {
+	for i := startIdx; i < endIdx; i++ {
+		w.framer.next(ctx)
+		intervals := w.framer.intervals
+		if !w.prevValid || !w.framesEqual(intervals) {
+			if w.prevValid && !w.alwaysReset && !w.framer.hasExclusion() && len(w.prevIntervals) == 1 &&
+				len(intervals) == 1 && w.prevIntervals[0].start == intervals[0].start &&
+				w.prevIntervals[0].end <= intervals[0].end {
+				// The frame only grew at the end, so we only need to add the new
+				// tuples.
+				w.aggregate(ctx, w.prevIntervals[0].end, intervals[0].end)
+			} else {
+				w.agg.Reset()
+				for _, interval := range intervals {
+					w.aggregate(ctx, interval.start, interval.end)
+				}
+			}
+			w.prevIntervals = append(w.prevIntervals[:0], intervals...)
+			w.prevValid = true
+			w.allocator.PerformOperation([]coldata.Vec{w.result}, func() {
+				w.result.Nulls().UnsetNulls()
+				w.agg.Flush(0 /* outputIdx */)
+			})
+		}
+		output.Copy(
+			coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					Src:         w.result,
+					DestIdx:     outputStartIdx + i - startIdx,
+					SrcStartIdx: 0,
+					SrcEndIdx:   1,
+				},
+			},
+		)
+	}
+}
+
+// framesEqual returns whether the given frame is the same as the frame of the
+// previous tuple.
+func (w *windowAggregator) framesEqual(intervals []windowInterval) bool {
+	if len(intervals) != len(w.prevIntervals) {
+		return false
+	}
+	for i := range intervals {
+		if intervals[i] != w.prevIntervals[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// aggregate adds the tuples in range [startIdx, endIdx) of the current
+// partition to the aggregation.
+func (w *windowAggregator) aggregate(ctx context.Context, startIdx, endIdx int) {
+	for startIdx < endIdx {
+		// All columns of a tuple are stored in the same batch of the buffer,
+		// so we can find out the number of contiguous tuples using any column.
+		_, rowIdx, length := w.buffer.getVecWithTuple(ctx, 0 /* colIdx */, startIdx)
+		n := length - rowIdx
+		if n > endIdx-startIdx {
+			n = endIdx - startIdx
+		}
+		if n > coldata.BatchSize() {
+			n = coldata.BatchSize()
+		}
+		for _, argIdx := range w.argIdxs {
+			vec, vecRowIdx, _ := w.buffer.getVecWithTuple(ctx, int(argIdx), startIdx)
+			w.vecs[argIdx] = vec.Window(vecRowIdx, vecRowIdx+n)
+		}
+		if cap(w.sel) < n {
+			w.sel = make([]int, n)
+			for i := range w.sel {
+				w.sel[i] = i
+			}
+		}
+		sel := w.sel[:n]
+		if w.inputArgsConverter != nil {
+			w.inputArgsConverter.ConvertVecs(w.vecs, n, sel)
+		}
+		w.agg.Compute(w.vecs, w.argIdxs, n, sel)
+		startIdx += n
+	}
+}
+
+func (w *windowAggregator) close(ctx context.Context) {
+	for _, c := range w.toClose {
+		if err := c.Close(ctx); err != nil {
+			log.Warningf(ctx, "error closing window aggregate function: %v", err)
+		}
+	}
+	w.toClose = nil
+	if w.inputArgsConverter != nil {
+		w.inputArgsConverter.Release()
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/colconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// windowInterval represents a range of tuples [start, end) of the current
+// partition.
+type windowInterval struct {
+	start int
+	end   int
+}
+
+// windowFramer computes the window frames of the tuples of the current
+// partition which is stored in a spillingBuffer. In order to have exactly the
+// same semantics as the row-by-row engine, the computation of the frame
+// bounds is delegated to tree.WindowFrameRun, with the peer groups determined
+// using the "peers" column and the values of the ordering column (needed in
+// RANGE mode with offsets) read from the buffer.
+//
+// The frames must be computed for all tuples of the partition in order by
+// calling next().
+type windowFramer struct {
+	evalCtx  *tree.EvalContext
+	frameRun tree.WindowFrameRun
+	rows     windowFramerRows
+
+	// peerGroupEndIdx is the index of the first tuple after the current peer
+	// group.
+	peerGroupEndIdx int
+	// started indicates whether next() has been called for the current
+	// partition.
+	started bool
+
+	// startIdx and endIdx are the bounds of the frame of the current tuple
+	// before the exclusion clause is applied.
+	startIdx int
+	endIdx   int
+	// intervals are the ranges of tuples that form the frame of the current
+	// tuple after the exclusion clause is applied (empty ranges are omitted).
+	intervals []windowInterval
+}
+
+// newWindowFramer returns a windowFramer for the given frame (which can be nil
+// in which case the default frame is used). peersColIdx must specify the
+// boolean column in which 'true' indicates the start of a new peer group.
+func newWindowFramer(
+	evalCtx *tree.EvalContext,
+	frame *execinfrapb.WindowerSpec_Frame,
+	ordering *execinfrapb.Ordering,
+	inputTypes []*types.T,
+	peersColIdx int,
+) (*windowFramer, error) {
+	f := &windowFramer{evalCtx: evalCtx}
+	f.rows.ordColIdx = tree.NoColumnIdx
+	f.rows.peersColIdx = peersColIdx
+	if frame == nil {
+		return f, nil
+	}
+	var err error
+	if f.frameRun.Frame, err = frame.ConvertToAST(); err != nil {
+		return nil, err
+	}
+	if f.frameRun.StartBoundOffset, err = decodeWindowFrameOffset(
+		frame.Mode, &frame.Bounds.Start,
+	); err != nil {
+		return nil, err
+	}
+	if frame.Bounds.End != nil {
+		if f.frameRun.EndBoundOffset, err = decodeWindowFrameOffset(
+			frame.Mode, frame.Bounds.End,
+		); err != nil {
+			return nil, err
+		}
+	}
+	if f.frameRun.RangeModeWithOffsets() {
+		ordCol := ordering.Columns[0]
+		f.frameRun.OrdColIdx = int(ordCol.ColIdx)
+		f.rows.ordColIdx = int(ordCol.ColIdx)
+		f.rows.ordColType = inputTypes[ordCol.ColIdx]
+		// We need this +1 because encoding.Direction has extra value "_" as
+		// zeroth "entry" which its proto equivalent doesn't have.
+		f.frameRun.OrdDirection = encoding.Direction(ordCol.Direction + 1)
+
+		colTyp := inputTypes[ordCol.ColIdx]
+		// Type of offset depends on the ordering column's type.
+		offsetTyp := colTyp
+		if types.IsDateTimeType(colTyp) {
+			// For datetime related ordering columns, offset must be an Interval.
+			offsetTyp = types.Interval
+		}
+		plusOp, minusOp, found := tree.WindowFrameRangeOps{}.LookupImpl(colTyp, offsetTyp)
+		if !found {
+			return nil, pgerror.Newf(pgcode.Windowing,
+				"given logical offset cannot be combined with ordering column")
+		}
+		f.frameRun.PlusOp, f.frameRun.MinusOp = plusOp, minusOp
+	}
+	return f, nil
+}
+
+// decodeWindowFrameOffset returns the offset of the bound as a datum (or nil
+// if the bound doesn't have an offset).
+func decodeWindowFrameOffset(
+	mode execinfrapb.WindowerSpec_Frame_Mode, bound *execinfrapb.WindowerSpec_Frame_Bound,
+) (tree.Datum, error) {
+	if bound.BoundType != execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING &&
+		bound.BoundType != execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING {
+		return nil, nil
+	}
+	switch mode {
+	case execinfrapb.WindowerSpec_Frame_ROWS, execinfrapb.WindowerSpec_Frame_GROUPS:
+		return tree.NewDInt(tree.DInt(int(bound.IntOffset))), nil
+	case execinfrapb.WindowerSpec_Frame_RANGE:
+		var datumAlloc rowenc.DatumAlloc
+		datum, rem, err := rowenc.DecodeTableValue(&datumAlloc, bound.OffsetType.Type, bound.TypedOffset)
+		if err != nil {
+			return nil, errors.NewAssertionErrorWithWrappedErrf(err,
+				"error decoding %d bytes", errors.Safe(len(bound.TypedOffset)))
+		}
+		if len(rem) != 0 {
+			return nil, errors.AssertionFailedf(
+				"%d trailing bytes in encoded value", errors.Safe(len(rem)))
+		}
+		return datum, nil
+	default:
+		return nil, errors.AssertionFailedf("unexpected WindowFrameMode: %d", errors.Safe(mode))
+	}
+}
+
+// startPartition prepares the framer to compute the frames of the tuples of
+// the new partition.
+func (f *windowFramer) startPartition(ctx context.Context, buffer *spillingBuffer) {
+	f.rows.ctx = ctx
+	f.rows.buffer = buffer
+	f.frameRun.Rows = &f.rows
+	f.frameRun.RowIdx = 0
+	f.frameRun.CurRowPeerGroupNum = 0
+	if err := f.frameRun.PeerHelper.Init(&f.frameRun, &f.rows); err != nil {
+		colexecerror.InternalError(err)
+	}
+	f.peerGroupEndIdx = f.frameRun.PeerHelper.GetFirstPeerIdx(0) + f.frameRun.PeerHelper.GetRowCount(0)
+	f.started = false
+}
+
+// next computes the frame of the next tuple of the partition.
+func (f *windowFramer) next(ctx context.Context) {
+	if f.started {
+		f.frameRun.RowIdx++
+	}
+	f.started = true
+	if f.frameRun.RowIdx == f.peerGroupEndIdx {
+		// The current tuple starts a new peer group.
+		if err := f.frameRun.PeerHelper.Update(&f.frameRun); err != nil {
+			colexecerror.InternalError(err)
+		}
+		f.frameRun.CurRowPeerGroupNum++
+		f.peerGroupEndIdx = f.frameRun.PeerHelper.GetFirstPeerIdx(f.frameRun.CurRowPeerGroupNum) +
+			f.frameRun.PeerHelper.GetRowCount(f.frameRun.CurRowPeerGroupNum)
+	}
+	var err error
+	if f.startIdx, err = f.frameRun.FrameStartIdx(ctx, f.evalCtx); err != nil {
+		colexecerror.ExpectedError(err)
+	}
+	if f.endIdx, err = f.frameRun.FrameEndIdx(ctx, f.evalCtx); err != nil {
+		colexecerror.ExpectedError(err)
+	}
+	if f.endIdx < f.startIdx {
+		f.endIdx = f.startIdx
+	}
+	f.intervals = f.intervals[:0]
+	if f.frameRun.Frame == nil || f.frameRun.Frame.DefaultFrameExclusion() {
+		f.appendInterval(f.startIdx, f.endIdx)
+		return
+	}
+	// Compute the range of excluded tuples. Note that with EXCLUDE TIES the
+	// current tuple itself is not excluded, so the peer group is split into
+	// two excluded ranges.
+	rowIdx := f.frameRun.RowIdx
+	peerGroupStartIdx := f.frameRun.PeerHelper.GetFirstPeerIdx(f.frameRun.CurRowPeerGroupNum)
+	switch f.frameRun.Frame.Exclusion {
+	case tree.ExcludeCurrentRow:
+		f.appendInterval(f.startIdx, rowIdx)
+		f.appendInterval(rowIdx+1, f.endIdx)
+	case tree.ExcludeGroup:
+		f.appendInterval(f.startIdx, peerGroupStartIdx)
+		f.appendInterval(f.peerGroupEndIdx, f.endIdx)
+	case tree.ExcludeTies:
+		f.appendInterval(f.startIdx, peerGroupStartIdx)
+		f.appendInterval(rowIdx, rowIdx+1)
+		f.appendInterval(f.peerGroupEndIdx, f.endIdx)
+	default:
+		colexecerror.InternalError(errors.AssertionFailedf("unexpected WindowFrameExclusion"))
+	}
+}
+
+// appendInterval adds the intersection of [start, end) with the frame bounds
+// to the list of intervals if it is non-empty.
+func (f *windowFramer) appendInterval(start, end int) {
+	if start < f.startIdx {
+		start = f.startIdx
+	}
+	if end > f.endIdx {
+		end = f.endIdx
+	}
+	if start < end {
+		f.intervals = append(f.intervals, windowInterval{start: start, end: end})
+	}
+}
+
+// hasExclusion returns whether the frame has a non-default exclusion clause.
+func (f *windowFramer) hasExclusion() bool {
+	return f.frameRun.Frame != nil && !f.frameRun.Frame.DefaultFrameExclusion()
+}
+
+// windowFramerRows is an adapter that exposes the tuples of the spillingBuffer
+// to tree.WindowFrameRun.
+type windowFramerRows struct {
+	ctx         context.Context
+	buffer      *spillingBuffer
+	peersColIdx int
+	ordColIdx   int
+	ordColType  *types.T
+	datumAlloc  rowenc.DatumAlloc
+	scratch     [1]tree.Datum
+	row         windowFramerRow
+}
+
+var _ tree.IndexedRows = &windowFramerRows{}
+var _ tree.PeerGroupChecker = &windowFramerRows{}
+
+// Len implements the tree.IndexedRows interface.
+func (r *windowFramerRows) Len() int {
+	return r.buffer.numTuples()
+}
+
+// GetRow implements the tree.IndexedRows interface.
+func (r *windowFramerRows) GetRow(_ context.Context, idx int) (tree.IndexedRow, error) {
+	r.row = windowFramerRow{rows: r, idx: idx}
+	return &r.row, nil
+}
+
+// InSameGroup implements the tree.PeerGroupChecker interface.
+func (r *windowFramerRows) InSameGroup(i, j int) (bool, error) {
+	if r.peersColIdx == tree.NoColumnIdx {
+		// All tuples of the partition are peers.
+		return true, nil
+	}
+	for idx := i + 1; idx <= j; idx++ {
+		vec, rowIdx, _ := r.buffer.getVecWithTuple(r.ctx, r.peersColIdx, idx)
+		if vec.Bool()[rowIdx] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// windowFramerRow is a tuple of the current partition. Only the value of the
+// ordering column can be retrieved from it.
+type windowFramerRow struct {
+	rows *windowFramerRows
+	idx  int
+}
+
+var _ tree.IndexedRow = &windowFramerRow{}
+
+// GetIdx implements the tree.IndexedRow interface.
+func (r *windowFramerRow) GetIdx() int {
+	return r.idx
+}
+
+// GetDatum implements the tree.IndexedRow interface.
+func (r *windowFramerRow) GetDatum(colIdx int) (tree.Datum, error) {
+	if colIdx != r.rows.ordColIdx {
+		return nil, errors.AssertionFailedf("unexpected request for datum in column %d", colIdx)
+	}
+	vec, rowIdx, _ := r.rows.buffer.getVecWithTuple(r.rows.ctx, colIdx, r.idx)
+	colconv.ColVecToDatum(r.rows.scratch[:], vec.Window(rowIdx, rowIdx+1), 1 /* length */, nil /* sel */, &r.rows.datumAlloc)
+	return r.rows.scratch[0], nil
+}
+
+// GetDatums implements the tree.IndexedRow interface.
+func (r *windowFramerRow) GetDatums(int, int) (tree.Datums, error) {
+	return nil, errors.AssertionFailedf("GetDatums is not supported")
+}
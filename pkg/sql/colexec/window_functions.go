@@ -0,0 +1,316 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// NewNTileOperator creates a new Operator that computes window function NTILE.
+// argIdx is the index of the column containing the number of buckets.
+func NewNTileOperator(args *WindowArgs, argIdx int) colexecbase.Operator {
+	return newBufferedWindowOperator(args, &ntileWindower{argIdx: argIdx}, types.Int)
+}
+
+// NewLeadLagOperator creates a new Operator that computes window function LEAD
+// or LAG (depending on the passed in windowFn). argIdxs contain the indices of
+// the value column and, optionally, of the offset and the default value
+// columns.
+func NewLeadLagOperator(
+	args *WindowArgs, windowFn execinfrapb.WindowerSpec_WindowFunc, argIdxs []uint32,
+) (colexecbase.Operator, error) {
+	var forward bool
+	switch windowFn {
+	case execinfrapb.WindowerSpec_LEAD:
+		forward = true
+	case execinfrapb.WindowerSpec_LAG:
+	default:
+		return nil, errors.AssertionFailedf("unexpected window function %s", windowFn)
+	}
+	if len(argIdxs) < 1 || len(argIdxs) > 3 {
+		return nil, errors.AssertionFailedf("unexpected number of arguments %d for %s", len(argIdxs), windowFn)
+	}
+	windower := &leadLagWindower{forward: forward, argIdxs: argIdxs}
+	return newBufferedWindowOperator(args, windower, args.InputTypes[argIdxs[0]]), nil
+}
+
+// NewFirstLastNthValueOperator creates a new Operator that computes window
+// function FIRST_VALUE, LAST_VALUE or NTH_VALUE (depending on the passed in
+// windowFn) over the given window frame. argIdxs contain the index of the
+// value column and, in case of NTH_VALUE, of the column with the number of
+// the row within the frame.
+func NewFirstLastNthValueOperator(
+	args *WindowArgs,
+	windowFn execinfrapb.WindowerSpec_WindowFunc,
+	frame *execinfrapb.WindowerSpec_Frame,
+	ordering *execinfrapb.Ordering,
+	argIdxs []uint32,
+) (colexecbase.Operator, error) {
+	switch windowFn {
+	case execinfrapb.WindowerSpec_FIRST_VALUE, execinfrapb.WindowerSpec_LAST_VALUE:
+		if len(argIdxs) != 1 {
+			return nil, errors.AssertionFailedf("unexpected number of arguments %d for %s", len(argIdxs), windowFn)
+		}
+	case execinfrapb.WindowerSpec_NTH_VALUE:
+		if len(argIdxs) != 2 {
+			return nil, errors.AssertionFailedf("unexpected number of arguments %d for %s", len(argIdxs), windowFn)
+		}
+	default:
+		return nil, errors.AssertionFailedf("unexpected window function %s", windowFn)
+	}
+	framer, err := newWindowFramer(args.EvalCtx, frame, ordering, args.InputTypes, args.PeersColIdx)
+	if err != nil {
+		return nil, err
+	}
+	windower := &valueWindower{windowFn: windowFn, framer: framer, argIdxs: argIdxs}
+	return newBufferedWindowOperator(args, windower, args.InputTypes[argIdxs[0]]), nil
+}
+
+// bufferedWindowerBase extracts the common fields of the bufferedWindowers.
+type bufferedWindowerBase struct {
+	buffer *spillingBuffer
+}
+
+func (b *bufferedWindowerBase) init(_ context.Context, buffer *spillingBuffer) {
+	b.buffer = buffer
+}
+
+func (b *bufferedWindowerBase) close(context.Context) {}
+
+// copyTuple copies the value of the given column of the tuple with index idx
+// of the current partition into the output vector at position outputIdx.
+func (b *bufferedWindowerBase) copyTuple(
+	ctx context.Context, output coldata.Vec, outputIdx int, colIdx int, idx int,
+) {
+	src, rowIdx, _ := b.buffer.getVecWithTuple(ctx, colIdx, idx)
+	output.Copy(
+		coldata.CopySliceArgs{
+			SliceArgs: coldata.SliceArgs{
+				Src:         src,
+				DestIdx:     outputIdx,
+				SrcStartIdx: rowIdx,
+				SrcEndIdx:   rowIdx + 1,
+			},
+		},
+	)
+}
+
+// getIntArg returns the value of the integer argument in the given column of
+// the tuple with index idx of the current partition as well as whether the
+// value is NULL.
+func (b *bufferedWindowerBase) getIntArg(
+	ctx context.Context, colIdx int, idx int,
+) (_ int64, isNull bool) {
+	vec, rowIdx, _ := b.buffer.getVecWithTuple(ctx, colIdx, idx)
+	if vec.Nulls().MaybeHasNulls() && vec.Nulls().NullAt(rowIdx) {
+		return 0, true
+	}
+	switch vec.Type().Width() {
+	case 16:
+		return int64(vec.Int16()[rowIdx]), false
+	case 32:
+		return int64(vec.Int32()[rowIdx]), false
+	default:
+		return vec.Int64()[rowIdx], false
+	}
+}
+
+// ntileWindower computes window function NTILE. It needs to know the size of
+// the partition in order to compute the sizes of the buckets.
+type ntileWindower struct {
+	bufferedWindowerBase
+	argIdx int
+
+	// hasBuckets indicates whether the number of buckets has been read for the
+	// current partition. The number of buckets is read from the first tuple
+	// that has a non-NULL argument (the output is NULL until then).
+	hasBuckets     bool
+	ntile          int64
+	curBucketCount int64
+	boundary       int64
+	remainder      int64
+}
+
+var _ bufferedWindower = &ntileWindower{}
+
+func (w *ntileWindower) startNewPartition(context.Context) {
+	w.hasBuckets = false
+	w.ntile = 0
+	w.curBucketCount = 0
+	w.boundary = 0
+	w.remainder = 0
+}
+
+func (w *ntileWindower) processBatch(
+	ctx context.Context, output coldata.Vec, outputStartIdx, startIdx, endIdx int,
+) {
+	outputCol := output.Int64()
+	outputNulls := output.Nulls()
+	for i := startIdx; i < endIdx; i++ {
+		outputIdx := outputStartIdx + i - startIdx
+		if !w.hasBuckets {
+			numBuckets, isNull := w.getIntArg(ctx, w.argIdx, i)
+			if isNull {
+				// Per spec, if argument is the null value, then the result is the
+				// null value.
+				outputNulls.SetNull(outputIdx)
+				continue
+			}
+			if numBuckets <= 0 {
+				colexecerror.ExpectedError(builtins.ErrInvalidArgumentForNtile)
+			}
+			w.hasBuckets = true
+			w.ntile = 1
+			total := int64(w.buffer.numTuples())
+			w.boundary = total / numBuckets
+			if w.boundary <= 0 {
+				w.boundary = 1
+			} else {
+				// If the total number is not divisible, add 1 row to leading
+				// buckets.
+				w.remainder = total % numBuckets
+				if w.remainder != 0 {
+					w.boundary++
+				}
+			}
+		}
+		w.curBucketCount++
+		if w.boundary < w.curBucketCount {
+			// Move to next ntile bucket.
+			if w.remainder != 0 && w.ntile == w.remainder {
+				w.remainder = 0
+				w.boundary--
+			}
+			w.ntile++
+			w.curBucketCount = 1
+		}
+		outputCol[outputIdx] = w.ntile
+	}
+}
+
+// leadLagWindower computes window functions LEAD and LAG.
+type leadLagWindower struct {
+	bufferedWindowerBase
+	forward bool
+	argIdxs []uint32
+}
+
+var _ bufferedWindower = &leadLagWindower{}
+
+func (w *leadLagWindower) startNewPartition(context.Context) {}
+
+func (w *leadLagWindower) processBatch(
+	ctx context.Context, output coldata.Vec, outputStartIdx, startIdx, endIdx int,
+) {
+	partitionSize := w.buffer.numTuples()
+	for i := startIdx; i < endIdx; i++ {
+		outputIdx := outputStartIdx + i - startIdx
+		offset := int64(1)
+		if len(w.argIdxs) > 1 {
+			var isNull bool
+			offset, isNull = w.getIntArg(ctx, int(w.argIdxs[1]), i)
+			if isNull {
+				output.Nulls().SetNull(outputIdx)
+				continue
+			}
+		}
+		if !w.forward {
+			offset = -offset
+		}
+		if target := int64(i) + offset; target < 0 || target >= int64(partitionSize) {
+			// Target tuple is out of the partition; supply default value if
+			// provided, otherwise return NULL.
+			if len(w.argIdxs) > 2 {
+				w.copyTuple(ctx, output, outputIdx, int(w.argIdxs[2]), i)
+			} else {
+				output.Nulls().SetNull(outputIdx)
+			}
+		} else {
+			w.copyTuple(ctx, output, outputIdx, int(w.argIdxs[0]), int(target))
+		}
+	}
+}
+
+// valueWindower computes window functions FIRST_VALUE, LAST_VALUE and
+// NTH_VALUE.
+type valueWindower struct {
+	bufferedWindowerBase
+	windowFn execinfrapb.WindowerSpec_WindowFunc
+	framer   *windowFramer
+	argIdxs  []uint32
+}
+
+var _ bufferedWindower = &valueWindower{}
+
+func (w *valueWindower) startNewPartition(ctx context.Context) {
+	w.framer.startPartition(ctx, w.buffer)
+}
+
+func (w *valueWindower) processBatch(
+	ctx context.Context, output coldata.Vec, outputStartIdx, startIdx, endIdx int,
+) {
+	valueColIdx := int(w.argIdxs[0])
+	for i := startIdx; i < endIdx; i++ {
+		outputIdx := outputStartIdx + i - startIdx
+		w.framer.next(ctx)
+		intervals := w.framer.intervals
+		switch w.windowFn {
+		case execinfrapb.WindowerSpec_FIRST_VALUE:
+			if len(intervals) == 0 {
+				// The frame is empty, so we return NULL.
+				output.Nulls().SetNull(outputIdx)
+				continue
+			}
+			w.copyTuple(ctx, output, outputIdx, valueColIdx, intervals[0].start)
+		case execinfrapb.WindowerSpec_LAST_VALUE:
+			if len(intervals) == 0 {
+				// The frame is empty, so we return NULL.
+				output.Nulls().SetNull(outputIdx)
+				continue
+			}
+			w.copyTuple(ctx, output, outputIdx, valueColIdx, intervals[len(intervals)-1].end-1)
+		default:
+			nth, isNull := w.getIntArg(ctx, int(w.argIdxs[1]), i)
+			if isNull {
+				output.Nulls().SetNull(outputIdx)
+				continue
+			}
+			if nth <= 0 {
+				colexecerror.ExpectedError(builtins.ErrInvalidArgumentForNthValue)
+			}
+			// Find the interval that contains the nth tuple of the frame.
+			found := false
+			for _, interval := range intervals {
+				if size := int64(interval.end - interval.start); nth > size {
+					nth -= size
+					continue
+				}
+				// We subtract 1 because nth is counting from 1.
+				w.copyTuple(ctx, output, outputIdx, valueColIdx, interval.start+int(nth)-1)
+				found = true
+				break
+			}
+			if !found {
+				// The requested tuple is outside of the window frame, so we return
+				// NULL.
+				output.Nulls().SetNull(outputIdx)
+			}
+		}
+	}
+}
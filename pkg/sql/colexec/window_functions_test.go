@@ -68,6 +68,18 @@ func TestWindowFunctions(t *testing.T) {
 	denseRankFn := execinfrapb.WindowerSpec_DENSE_RANK
 	percentRankFn := execinfrapb.WindowerSpec_PERCENT_RANK
 	cumeDistFn := execinfrapb.WindowerSpec_CUME_DIST
+	ntileFn := execinfrapb.WindowerSpec_NTILE
+	lagFn := execinfrapb.WindowerSpec_LAG
+	leadFn := execinfrapb.WindowerSpec_LEAD
+	lastValueFn := execinfrapb.WindowerSpec_LAST_VALUE
+	nthValueFn := execinfrapb.WindowerSpec_NTH_VALUE
+	maxFn := execinfrapb.AggregatorSpec_MAX
+	countRowsFn := execinfrapb.AggregatorSpec_COUNT_ROWS
+	makeFrame := func(f *tree.WindowFrame) *execinfrapb.WindowerSpec_Frame {
+		var frame execinfrapb.WindowerSpec_Frame
+		require.NoError(t, frame.InitFromAST(f, &evalCtx))
+		return &frame
+	}
 	accounts := make([]*mon.BoundAccount, 0)
 	monitors := make([]*mon.BytesMonitor, 0)
 	for _, spillForced := range []bool{false, true} {
@@ -278,6 +290,157 @@ func TestWindowFunctions(t *testing.T) {
 					},
 				},
 			},
+			// Window functions with arguments.
+			{
+				tuples:   tuples{{1, 2}, {1, 2}, {1, 2}, {2, 3}, {2, 3}, {3, nil}},
+				expected: tuples{{1, 2, 1}, {1, 2, 1}, {1, 2, 2}, {2, 3, 1}, {2, 3, 2}, {3, nil, nil}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &ntileFn},
+							ArgsIdxs:     []uint32{1},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			{
+				tuples:   tuples{{1, 3}, {2, 4}, {1, 1}, {2, 5}, {1, 2}},
+				expected: tuples{{1, 1, nil}, {1, 2, 1}, {1, 3, 2}, {2, 4, nil}, {2, 5, 4}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &lagFn},
+							ArgsIdxs:     []uint32{1},
+							Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			{
+				tuples:   tuples{{1, 3, 2}, {2, 4, 2}, {1, 1, 2}, {2, 5, nil}, {1, 2, 2}},
+				expected: tuples{{1, 1, 2, 3}, {1, 2, 2, 1}, {1, 3, 2, 1}, {2, 4, 2, 2}, {2, 5, nil, nil}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &leadFn},
+							ArgsIdxs:     []uint32{1, 2, 0},
+							Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							OutputColIdx: 3,
+						},
+					},
+				},
+			},
+			{
+				tuples:   tuples{{3, 1}, {1, 2}, {2, 3}, {5, 4}, {4, 5}},
+				expected: tuples{{1, 2, 3}, {2, 3, 1}, {3, 1, 5}, {4, 5, 4}, {5, 4, 4}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:     execinfrapb.WindowerSpec_Func{WindowFunc: &lastValueFn},
+							ArgsIdxs: []uint32{1},
+							Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 0}}},
+							Frame: makeFrame(&tree.WindowFrame{
+								Mode: tree.ROWS,
+								Bounds: tree.WindowFrameBounds{
+									StartBound: &tree.WindowFrameBound{BoundType: tree.CurrentRow},
+									EndBound:   &tree.WindowFrameBound{BoundType: tree.OffsetFollowing, OffsetExpr: tree.NewDInt(1)},
+								},
+							}),
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			{
+				tuples:   tuples{{4, 2}, {2, 2}, {1, 2}, {3, 6}, {5, nil}},
+				expected: tuples{{1, 2, 2}, {2, 2, 2}, {3, 6, nil}, {4, 2, 2}, {5, nil, nil}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:     execinfrapb.WindowerSpec_Func{WindowFunc: &nthValueFn},
+							ArgsIdxs: []uint32{0, 1},
+							Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 0}}},
+							Frame: makeFrame(&tree.WindowFrame{
+								Mode: tree.ROWS,
+								Bounds: tree.WindowFrameBounds{
+									StartBound: &tree.WindowFrameBound{BoundType: tree.UnboundedPreceding},
+									EndBound:   &tree.WindowFrameBound{BoundType: tree.UnboundedFollowing},
+								},
+							}),
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+
+			// Aggregate functions with window frames.
+			{
+				tuples:   tuples{{4, 1}, {2, 1}, {5, 5}, {1, 3}, {3, 4}},
+				expected: tuples{{1, 3, 3}, {2, 1, 4}, {3, 4, 4}, {4, 1, 5}, {5, 5, 5}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &maxFn},
+							ArgsIdxs: []uint32{1},
+							Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 0}}},
+							Frame: makeFrame(&tree.WindowFrame{
+								Mode: tree.ROWS,
+								Bounds: tree.WindowFrameBounds{
+									StartBound: &tree.WindowFrameBound{BoundType: tree.OffsetPreceding, OffsetExpr: tree.NewDInt(1)},
+									EndBound:   &tree.WindowFrameBound{BoundType: tree.OffsetFollowing, OffsetExpr: tree.NewDInt(1)},
+								},
+							}),
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			{
+				tuples:   tuples{{5}, {2}, {4}, {1}, {2}},
+				expected: tuples{{1, 1}, {2, 3}, {2, 3}, {4, 1}, {5, 2}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &countRowsFn},
+							Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 0}}},
+							Frame: makeFrame(&tree.WindowFrame{
+								Mode: tree.RANGE,
+								Bounds: tree.WindowFrameBounds{
+									StartBound: &tree.WindowFrameBound{BoundType: tree.OffsetPreceding, OffsetExpr: tree.NewDInt(1)},
+									EndBound:   &tree.WindowFrameBound{BoundType: tree.CurrentRow},
+								},
+							}),
+							OutputColIdx: 1,
+						},
+					},
+				},
+			},
+			{
+				tuples:   tuples{{3}, {1}, {2}, {3}, {1}},
+				expected: tuples{{1, 1}, {1, 1}, {2, 2}, {3, 2}, {3, 2}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &countRowsFn},
+							Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 0}}},
+							Frame: makeFrame(&tree.WindowFrame{
+								Mode: tree.GROUPS,
+								Bounds: tree.WindowFrameBounds{
+									StartBound: &tree.WindowFrameBound{BoundType: tree.OffsetPreceding, OffsetExpr: tree.NewDInt(1)},
+									EndBound:   &tree.WindowFrameBound{BoundType: tree.CurrentRow},
+								},
+								Exclusion: tree.ExcludeCurrentRow,
+							}),
+							OutputColIdx: 1,
+						},
+					},
+				},
+			},
 		} {
 			log.Infof(ctx, "spillForced=%t/%s", spillForced, tc.windowerSpec.WindowFns[0].Func.String())
 			var semsToCheck []semaphore.Semaphore
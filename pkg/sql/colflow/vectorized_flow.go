@@ -883,7 +883,7 @@ func (s *vectorizedFlowCreator) setupInput(
 			metaSources = []execinfrapb.MetadataSource{os}
 			toClose = []colexecbase.Closer{os}
 		} else {
-			if opt == flowinfra.FuseAggressively {
+			if opt == flowinfra.FuseAggressively || input.Type == execinfrapb.InputSyncSpec_SERIAL_UNORDERED {
 				sync := colexec.NewSerialUnorderedSynchronizer(inputStreamOps)
 				op = sync
 				metaSources = []execinfrapb.MetadataSource{sync}
@@ -328,11 +328,14 @@ func (s *Server) Start(ctx context.Context, stopper *stop.Stopper) {
 	// to ensure that we always have some worker clearing SQL stats to avoid
 	// continually allocating space for the SQL stats. Additionally, spawn
 	// a loop to clear the reported stats at the same large interval just
-	// in case the telemetry worker fails.
-	s.PeriodicallyClearSQLStats(ctx, stopper, MaxSQLStatReset, &s.sqlStats, s.ResetSQLStats)
+	// in case the telemetry worker fails. If the SQL stats are persisted, they
+	// are flushed to disk rather than discarded when cleared.
+	s.PeriodicallyClearSQLStats(ctx, stopper, MaxSQLStatReset, &s.sqlStats, s.maybeFlushAndResetSQLStats)
 	s.PeriodicallyClearSQLStats(ctx, stopper, MaxSQLStatReset, &s.reportedStats, s.ResetReportedStats)
 	// Start a second loop to clear SQL stats at the requested interval.
-	s.PeriodicallyClearSQLStats(ctx, stopper, SQLStatReset, &s.sqlStats, s.ResetSQLStats)
+	s.PeriodicallyClearSQLStats(ctx, stopper, SQLStatReset, &s.sqlStats, s.maybeFlushAndResetSQLStats)
+	// Start a loop to flush the SQL stats to disk.
+	s.periodicallyFlushSQLStats(ctx, stopper)
 }
 
 // ResetSQLStats resets the executor's collected sql statistics.
@@ -968,6 +971,10 @@ type connExecutor struct {
 		// comprising statements.
 		numRows int
 
+		// contentionTime keeps track of the time the statements of this
+		// transaction have spent waiting on locks held by other transactions.
+		contentionTime time.Duration
+
 		// txnRewindPos is the position within stmtBuf to which we'll rewind when
 		// performing automatic retries. This is more or less the position where the
 		// current transaction started.
@@ -2142,6 +2149,7 @@ func (ex *connExecutor) resetEvalCtx(evalCtx *extendedEvalContext, txn *kv.Txn,
 	evalCtx.Mon = ex.state.mon
 	evalCtx.PrepareOnly = false
 	evalCtx.SkipNormalize = false
+	evalCtx.AsOfSystemTime = nil
 }
 
 // getTransactionState retrieves a text representation of the given state.
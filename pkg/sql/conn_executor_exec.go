@@ -530,25 +530,35 @@ func (ex *connExecutor) execStmtInOpenState(
 	// don't return any event unless an error happens.
 
 	if os.ImplicitTxn.Get() {
-		asOfTs, err := p.isAsOf(ctx, ast)
+		asOf, err := p.isAsOf(ctx, ast)
 		if err != nil {
 			return makeErrEvent(err)
 		}
-		if asOfTs != nil {
-			p.semaCtx.AsOfTimestamp = asOfTs
-			p.extendedEvalCtx.SetTxnTimestamp(asOfTs.GoTime())
-			ex.state.setHistoricalTimestamp(ctx, *asOfTs)
+		if asOf != nil {
+			p.semaCtx.AsOfTimestamp = &asOf.Timestamp
+			p.extendedEvalCtx.AsOfSystemTime = asOf
+			// Bounded staleness reads negotiate their timestamp during
+			// execution; see dispatchToExecutionEngine.
+			if !asOf.BoundedStaleness {
+				p.extendedEvalCtx.SetTxnTimestamp(asOf.Timestamp.GoTime())
+				ex.state.setHistoricalTimestamp(ctx, asOf.Timestamp)
+			}
 		}
 	} else {
 		// If we're in an explicit txn, we allow AOST but only if it matches with
 		// the transaction's timestamp. This is useful for running AOST statements
 		// using the InternalExecutor inside an external transaction; one might want
 		// to do that to force p.avoidCachedDescriptors to be set below.
-		ts, err := p.isAsOf(ctx, ast)
+		asOf, err := p.isAsOf(ctx, ast)
 		if err != nil {
 			return makeErrEvent(err)
 		}
-		if ts != nil {
+		if asOf != nil {
+			if asOf.BoundedStaleness {
+				return makeErrEvent(pgerror.Newf(pgcode.FeatureNotSupported,
+					"cannot use a bounded staleness query in an explicit transaction"))
+			}
+			ts := &asOf.Timestamp
 			if readTs := ex.state.getReadTimestamp(); *ts != readTs {
 				err = pgerror.Newf(pgcode.Syntax,
 					"inconsistent AS OF SYSTEM TIME timestamp; expected: %s", readTs)
@@ -805,6 +815,17 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		return nil
 	}
 
+	if asOf := planner.EvalContext().AsOfSystemTime; asOf != nil && asOf.BoundedStaleness {
+		// Bounded staleness reads negotiate the transaction's timestamp using
+		// the first read performed by the plan. This is armed only once the
+		// plan has been built so that no read performed during planning
+		// takes part in the negotiation.
+		if err := ex.state.setBoundedStaleness(ctx, *asOf); err != nil {
+			res.SetError(err)
+			return nil
+		}
+	}
+
 	ex.sessionTracing.TracePlanCheckStart(ctx)
 	distributePlan := getPlanDistribution(
 		ctx, planner, planner.execCfg.NodeID, ex.sessionData.DistSQLMode, planner.curPlan.main,
@@ -910,6 +931,9 @@ type topLevelQueryStats struct {
 	bytesRead int64
 	// rowsRead is the number of rows read from disk.
 	rowsRead int64
+	// contentionTime is the time spent waiting on conflicting locks held by
+	// other transactions.
+	contentionTime time.Duration
 }
 
 // execWithDistSQLEngine converts a plan to a distributed SQL physical plan and
@@ -1407,6 +1431,7 @@ func (ex *connExecutor) recordTransactionStart() (onTxnFinish func(txnEvent), on
 	ex.extraTxnState.transactionStatementsHash = util.MakeFNV64()
 	ex.extraTxnState.transactionStatementIDs = nil
 	ex.extraTxnState.numRows = 0
+	ex.extraTxnState.contentionTime = 0
 
 	onTxnFinish = func(ev txnEvent) {
 		ex.phaseTimes[sessionEndExecTransaction] = timeutil.Now()
@@ -1417,6 +1442,7 @@ func (ex *connExecutor) recordTransactionStart() (onTxnFinish func(txnEvent), on
 		ex.extraTxnState.transactionStatementIDs = nil
 		ex.extraTxnState.transactionStatementsHash = util.MakeFNV64()
 		ex.extraTxnState.numRows = 0
+		ex.extraTxnState.contentionTime = 0
 	}
 	return onTxnFinish, onTxnRestart
 }
@@ -1441,5 +1467,6 @@ func (ex *connExecutor) recordTransaction(ev txnEvent, implicit bool, txnStart t
 		txnRetryLat,
 		commitLat,
 		ex.extraTxnState.numRows,
+		ex.extraTxnState.contentionTime,
 	)
 }
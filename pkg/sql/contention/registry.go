@@ -0,0 +1,329 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package contention provides an in-memory store of the contention events
+// observed by the SQL layer on a single node.
+package contention
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/biogo/store/llrb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+// Registry is an object that keeps track of aggregated contention information.
+// It can be thought of as three maps at different levels of granularity. The
+// first level maps table/index pairs to the aggregated contention information
+// for that index. The second level, stored within each index entry, maps the
+// keys of that index to the contention information observed on that key. The
+// third level, stored within each key entry, maps the IDs of the transactions
+// that held a conflicting lock on the key to the number of times they were
+// observed doing so.
+//
+// Every level is an LRU cache of bounded size, so the memory footprint of the
+// Registry does not grow with the number of contention events it is fed.
+type Registry struct {
+	codec keys.SQLCodec
+
+	globalLock syncutil.Mutex
+	// indexMap maps indexMapKeys to *indexMapValues.
+	indexMap *cache.OrderedCache
+}
+
+const (
+	// indexMapMaxSize is the maximum number of table/index pairs a Registry
+	// keeps track of contention events for.
+	indexMapMaxSize = 50
+	// orderedKeyMapMaxSize is the maximum number of keys of a given
+	// table/index pair a Registry keeps track of contention events for.
+	orderedKeyMapMaxSize = 50
+	// maxNumTxns is the maximum number of contending transactions a Registry
+	// keeps track of for a given key.
+	maxNumTxns = 10
+)
+
+// indexMapKey identifies a table/index pair.
+type indexMapKey struct {
+	tableID descpb.ID
+	indexID descpb.IndexID
+}
+
+// Compare implements the llrb.Comparable interface.
+func (k indexMapKey) Compare(c llrb.Comparable) int {
+	o := c.(indexMapKey)
+	switch {
+	case k.tableID < o.tableID:
+		return -1
+	case k.tableID > o.tableID:
+		return 1
+	case k.indexID < o.indexID:
+		return -1
+	case k.indexID > o.indexID:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// comparableKey is a roachpb.Key converted to a string so that it can be used
+// as the key of an OrderedCache.
+type comparableKey string
+
+// Compare implements the llrb.Comparable interface.
+func (k comparableKey) Compare(c llrb.Comparable) int {
+	o := c.(comparableKey)
+	switch {
+	case k < o:
+		return -1
+	case k > o:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// txnKey is a transaction ID that can be used as the key of an OrderedCache.
+type txnKey uuid.UUID
+
+// Compare implements the llrb.Comparable interface.
+func (k txnKey) Compare(c llrb.Comparable) int {
+	o := c.(txnKey)
+	return bytes.Compare(k[:], o[:])
+}
+
+// indexMapValue is the aggregated contention information for a single
+// table/index pair.
+type indexMapValue struct {
+	// numContentionEvents is the number of contention events that have
+	// happened on the index.
+	numContentionEvents int64
+	// cumulativeContentionTime is the total duration that transactions touching
+	// the index have spent contending.
+	cumulativeContentionTime time.Duration
+	// orderedKeyMap maps comparableKeys to *cache.OrderedCaches which in turn
+	// map txnKeys to the number of times that transaction was observed holding
+	// a conflicting lock on the key.
+	orderedKeyMap *cache.OrderedCache
+}
+
+func newOrderedCache(maxSize int) *cache.OrderedCache {
+	return cache.NewOrderedCache(cache.Config{
+		Policy: cache.CacheLRU,
+		ShouldEvict: func(size int, _, _ interface{}) bool {
+			return size > maxSize
+		},
+	})
+}
+
+func newIndexMapValue() *indexMapValue {
+	return &indexMapValue{orderedKeyMap: newOrderedCache(orderedKeyMapMaxSize)}
+}
+
+// addContentionEvent adds the given contention event to the aggregated
+// contention information of the index.
+func (v *indexMapValue) addContentionEvent(c roachpb.ContentionEvent) {
+	v.numContentionEvents++
+	v.cumulativeContentionTime += c.Duration
+	var txns *cache.OrderedCache
+	if t, ok := v.orderedKeyMap.Get(comparableKey(c.Key)); ok {
+		txns = t.(*cache.OrderedCache)
+	} else {
+		txns = newOrderedCache(maxNumTxns)
+		v.orderedKeyMap.Add(comparableKey(c.Key), txns)
+	}
+	var count int64
+	if n, ok := txns.Get(txnKey(c.Txn.ID)); ok {
+		count = n.(int64)
+	}
+	txns.Add(txnKey(c.Txn.ID), count+1)
+}
+
+// NewRegistry creates a new Registry. The codec is used to determine the
+// table and index that the key of each contention event belongs to.
+func NewRegistry(codec keys.SQLCodec) *Registry {
+	return &Registry{
+		codec:    codec,
+		indexMap: newOrderedCache(indexMapMaxSize),
+	}
+}
+
+// AddContentionEvent adds a new ContentionEvent to the Registry. Events on
+// keys that do not belong to a SQL index are ignored.
+func (r *Registry) AddContentionEvent(c roachpb.ContentionEvent) {
+	_, tableID, indexID, err := r.codec.DecodeIndexPrefix(c.Key)
+	if err != nil {
+		// The key is not a SQL key, so there is no index to attribute the
+		// contention to.
+		return
+	}
+	key := indexMapKey{tableID: descpb.ID(tableID), indexID: descpb.IndexID(indexID)}
+	r.globalLock.Lock()
+	defer r.globalLock.Unlock()
+	var v *indexMapValue
+	if iv, ok := r.indexMap.Get(key); ok {
+		v = iv.(*indexMapValue)
+	} else {
+		v = newIndexMapValue()
+		r.indexMap.Add(key, v)
+	}
+	v.addContentionEvent(c)
+}
+
+// Serialize returns the serialized representation of the Registry. The
+// indexes are ordered by cumulative contention time, the keys of each index
+// are ordered by key, and the transactions of each key are ordered by the
+// number of times they were observed.
+func (r *Registry) Serialize() []serverpb.IndexContentionEvents {
+	r.globalLock.Lock()
+	defer r.globalLock.Unlock()
+	resp := make([]serverpb.IndexContentionEvents, 0, r.indexMap.Len())
+	r.indexMap.Do(func(k, v interface{}) bool {
+		key := k.(indexMapKey)
+		value := v.(*indexMapValue)
+		ice := serverpb.IndexContentionEvents{
+			TableID:                  uint32(key.tableID),
+			IndexID:                  uint32(key.indexID),
+			NumContentionEvents:      value.numContentionEvents,
+			CumulativeContentionTime: value.cumulativeContentionTime,
+			Events:                   make([]serverpb.SingleKeyContention, 0, value.orderedKeyMap.Len()),
+		}
+		value.orderedKeyMap.Do(func(k, v interface{}) bool {
+			txnCache := v.(*cache.OrderedCache)
+			skc := serverpb.SingleKeyContention{
+				Key:  roachpb.Key(k.(comparableKey)),
+				Txns: make([]serverpb.SingleTxnContention, 0, txnCache.Len()),
+			}
+			txnCache.Do(func(k, v interface{}) bool {
+				skc.Txns = append(skc.Txns, serverpb.SingleTxnContention{
+					TxnID: uuid.UUID(k.(txnKey)),
+					Count: v.(int64),
+				})
+				return false
+			})
+			sortSingleTxnContention(skc.Txns)
+			ice.Events = append(ice.Events, skc)
+			return false
+		})
+		resp = append(resp, ice)
+		return false
+	})
+	sortIndexContentionEvents(resp)
+	return resp
+}
+
+// MergeSerializedRegistries merges the serialized representations of two
+// Registries, typically obtained from different nodes, into one. The result
+// is ordered as described in Serialize. Unlike the Registry itself, the merged
+// representation is not bounded in size.
+func MergeSerializedRegistries(
+	first, second []serverpb.IndexContentionEvents,
+) []serverpb.IndexContentionEvents {
+	byIndex := make(map[indexMapKey]*serverpb.IndexContentionEvents, len(first))
+	result := make([]serverpb.IndexContentionEvents, 0, len(first)+len(second))
+	for _, events := range [][]serverpb.IndexContentionEvents{first, second} {
+		for i := range events {
+			key := indexMapKey{
+				tableID: descpb.ID(events[i].TableID),
+				indexID: descpb.IndexID(events[i].IndexID),
+			}
+			existing, ok := byIndex[key]
+			if !ok {
+				result = append(result, events[i])
+				byIndex[key] = &result[len(result)-1]
+				continue
+			}
+			existing.NumContentionEvents += events[i].NumContentionEvents
+			existing.CumulativeContentionTime += events[i].CumulativeContentionTime
+			existing.Events = mergeSingleKeyContention(existing.Events, events[i].Events)
+		}
+	}
+	sortIndexContentionEvents(result)
+	return result
+}
+
+// mergeSingleKeyContention merges two slices of SingleKeyContention ordered by
+// key into a new slice that is ordered by key.
+func mergeSingleKeyContention(
+	first, second []serverpb.SingleKeyContention,
+) []serverpb.SingleKeyContention {
+	result := make([]serverpb.SingleKeyContention, 0, len(first)+len(second))
+	for len(first) > 0 && len(second) > 0 {
+		switch cmp := first[0].Key.Compare(second[0].Key); {
+		case cmp < 0:
+			result = append(result, first[0])
+			first = first[1:]
+		case cmp > 0:
+			result = append(result, second[0])
+			second = second[1:]
+		default:
+			result = append(result, serverpb.SingleKeyContention{
+				Key:  first[0].Key,
+				Txns: mergeSingleTxnContention(first[0].Txns, second[0].Txns),
+			})
+			first, second = first[1:], second[1:]
+		}
+	}
+	result = append(result, first...)
+	return append(result, second...)
+}
+
+// mergeSingleTxnContention merges two slices of SingleTxnContention into a new
+// slice ordered by the number of times each transaction was observed.
+func mergeSingleTxnContention(
+	first, second []serverpb.SingleTxnContention,
+) []serverpb.SingleTxnContention {
+	result := make([]serverpb.SingleTxnContention, 0, len(first)+len(second))
+	result = append(result, first...)
+	for _, t := range second {
+		found := false
+		for i := range result {
+			if result[i].TxnID == t.TxnID {
+				result[i].Count += t.Count
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, t)
+		}
+	}
+	sortSingleTxnContention(result)
+	return result
+}
+
+func sortIndexContentionEvents(events []serverpb.IndexContentionEvents) {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].CumulativeContentionTime != events[j].CumulativeContentionTime {
+			return events[i].CumulativeContentionTime > events[j].CumulativeContentionTime
+		}
+		if events[i].TableID != events[j].TableID {
+			return events[i].TableID < events[j].TableID
+		}
+		return events[i].IndexID < events[j].IndexID
+	})
+}
+
+func sortSingleTxnContention(txns []serverpb.SingleTxnContention) {
+	sort.Slice(txns, func(i, j int) bool {
+		if txns[i].Count != txns[j].Count {
+			return txns[i].Count > txns[j].Count
+		}
+		return bytes.Compare(txns[i].TxnID.GetBytes(), txns[j].TxnID.GetBytes()) < 0
+	})
+}
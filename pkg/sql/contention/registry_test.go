@@ -0,0 +1,108 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package contention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func makeKey(tableID, indexID uint32, val int64) roachpb.Key {
+	key := keys.SystemSQLCodec.IndexPrefix(tableID, indexID)
+	return encoding.EncodeVarintAscending(key, val)
+}
+
+func makeEvent(key roachpb.Key, txnID uuid.UUID, d time.Duration) roachpb.ContentionEvent {
+	ev := roachpb.ContentionEvent{Key: key, Duration: d}
+	ev.Txn.ID = txnID
+	return ev
+}
+
+func TestRegistry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	txn1, txn2 := uuid.FastMakeV4(), uuid.FastMakeV4()
+	r := NewRegistry(keys.SystemSQLCodec)
+	r.AddContentionEvent(makeEvent(makeKey(53, 1, 2), txn1, time.Second))
+	r.AddContentionEvent(makeEvent(makeKey(53, 1, 1), txn1, time.Second))
+	r.AddContentionEvent(makeEvent(makeKey(53, 1, 1), txn2, time.Second))
+	r.AddContentionEvent(makeEvent(makeKey(53, 1, 1), txn2, time.Second))
+	r.AddContentionEvent(makeEvent(makeKey(54, 2, 1), txn1, 5*time.Second))
+	// Contention on keys outside of the SQL keyspace is ignored.
+	r.AddContentionEvent(makeEvent(keys.NodeLivenessPrefix, txn1, time.Second))
+
+	events := r.Serialize()
+	require.Len(t, events, 2)
+
+	// The index with the most contention comes first.
+	require.Equal(t, uint32(54), events[0].TableID)
+	require.Equal(t, uint32(2), events[0].IndexID)
+	require.Equal(t, int64(1), events[0].NumContentionEvents)
+	require.Equal(t, 5*time.Second, events[0].CumulativeContentionTime)
+
+	require.Equal(t, uint32(53), events[1].TableID)
+	require.Equal(t, uint32(1), events[1].IndexID)
+	require.Equal(t, int64(4), events[1].NumContentionEvents)
+	require.Equal(t, 4*time.Second, events[1].CumulativeContentionTime)
+	// The keys are ordered, and the transactions on each key are ordered by the
+	// number of times they were observed.
+	require.Len(t, events[1].Events, 2)
+	require.Equal(t, makeKey(53, 1, 1), events[1].Events[0].Key)
+	require.Len(t, events[1].Events[0].Txns, 2)
+	require.Equal(t, txn2, events[1].Events[0].Txns[0].TxnID)
+	require.Equal(t, int64(2), events[1].Events[0].Txns[0].Count)
+	require.Equal(t, txn1, events[1].Events[0].Txns[1].TxnID)
+	require.Equal(t, int64(1), events[1].Events[0].Txns[1].Count)
+	require.Equal(t, makeKey(53, 1, 2), events[1].Events[1].Key)
+
+	// Merging the serialized registry with itself doubles all of the counts.
+	merged := MergeSerializedRegistries(events, r.Serialize())
+	require.Len(t, merged, 2)
+	require.Equal(t, int64(8), merged[1].NumContentionEvents)
+	require.Equal(t, 8*time.Second, merged[1].CumulativeContentionTime)
+	require.Len(t, merged[1].Events, 2)
+	require.Equal(t, int64(4), merged[1].Events[0].Txns[0].Count)
+	require.Equal(t, int64(2), merged[1].Events[0].Txns[1].Count)
+	// The inputs are not modified.
+	require.Equal(t, r.Serialize(), events)
+}
+
+func TestRegistryBounded(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	r := NewRegistry(keys.SystemSQLCodec)
+	for i := 0; i < 2*indexMapMaxSize; i++ {
+		r.AddContentionEvent(makeEvent(makeKey(uint32(100+i), 1, 1), uuid.FastMakeV4(), time.Second))
+	}
+	for i := 0; i < 2*orderedKeyMapMaxSize; i++ {
+		r.AddContentionEvent(makeEvent(makeKey(53, 1, int64(i)), uuid.FastMakeV4(), time.Second))
+	}
+	for i := 0; i < 2*maxNumTxns; i++ {
+		r.AddContentionEvent(makeEvent(makeKey(53, 1, 0), uuid.FastMakeV4(), time.Second))
+	}
+
+	events := r.Serialize()
+	require.Len(t, events, indexMapMaxSize)
+	require.Equal(t, uint32(53), events[0].TableID)
+	require.Equal(t, int64(2*orderedKeyMapMaxSize+2*maxNumTxns), events[0].NumContentionEvents)
+	require.Len(t, events[0].Events, orderedKeyMapMaxSize)
+	for _, e := range events[0].Events {
+		require.LessOrEqual(t, len(e.Txns), maxNumTxns)
+	}
+	require.Len(t, events[0].Events[0].Txns, maxNumTxns)
+}
@@ -13,6 +13,7 @@ package sql
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"net/url"
@@ -50,12 +51,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/protoreflect"
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -68,10 +71,10 @@ import (
 const CrdbInternalName = sessiondata.CRDBInternalSchemaName
 
 // Naming convention:
-// - if the response is served from memory, prefix with node_
-// - if the response is served via a kv request, prefix with kv_
-// - if the response is not from kv requests but is cluster-wide (i.e. the
-//    answer isn't specific to the sql connection being used, prefix with cluster_.
+//   - if the response is served from memory, prefix with node_
+//   - if the response is served via a kv request, prefix with kv_
+//   - if the response is not from kv requests but is cluster-wide (i.e. the
+//     answer isn't specific to the sql connection being used, prefix with cluster_.
 //
 // Adding something new here will require an update to `pkg/cli` for inclusion in
 // a `debug zip`; the unit tests will guide you.
@@ -81,48 +84,56 @@ const CrdbInternalName = sessiondata.CRDBInternalSchemaName
 var crdbInternal = virtualSchema{
 	name: CrdbInternalName,
 	tableDefs: map[descpb.ID]virtualSchemaDef{
-		catconstants.CrdbInternalBackwardDependenciesTableID: crdbInternalBackwardDependenciesTable,
-		catconstants.CrdbInternalBuildInfoTableID:            crdbInternalBuildInfoTable,
-		catconstants.CrdbInternalBuiltinFunctionsTableID:     crdbInternalBuiltinFunctionsTable,
-		catconstants.CrdbInternalClusterQueriesTableID:       crdbInternalClusterQueriesTable,
-		catconstants.CrdbInternalClusterTransactionsTableID:  crdbInternalClusterTxnsTable,
-		catconstants.CrdbInternalClusterSessionsTableID:      crdbInternalClusterSessionsTable,
-		catconstants.CrdbInternalClusterSettingsTableID:      crdbInternalClusterSettingsTable,
-		catconstants.CrdbInternalCreateStmtsTableID:          crdbInternalCreateStmtsTable,
-		catconstants.CrdbInternalCreateTypeStmtsTableID:      crdbInternalCreateTypeStmtsTable,
-		catconstants.CrdbInternalDatabasesTableID:            crdbInternalDatabasesTable,
-		catconstants.CrdbInternalFeatureUsageID:              crdbInternalFeatureUsage,
-		catconstants.CrdbInternalForwardDependenciesTableID:  crdbInternalForwardDependenciesTable,
-		catconstants.CrdbInternalGossipNodesTableID:          crdbInternalGossipNodesTable,
-		catconstants.CrdbInternalGossipAlertsTableID:         crdbInternalGossipAlertsTable,
-		catconstants.CrdbInternalGossipLivenessTableID:       crdbInternalGossipLivenessTable,
-		catconstants.CrdbInternalGossipNetworkTableID:        crdbInternalGossipNetworkTable,
-		catconstants.CrdbInternalIndexColumnsTableID:         crdbInternalIndexColumnsTable,
-		catconstants.CrdbInternalJobsTableID:                 crdbInternalJobsTable,
-		catconstants.CrdbInternalKVNodeStatusTableID:         crdbInternalKVNodeStatusTable,
-		catconstants.CrdbInternalKVStoreStatusTableID:        crdbInternalKVStoreStatusTable,
-		catconstants.CrdbInternalLeasesTableID:               crdbInternalLeasesTable,
-		catconstants.CrdbInternalLocalQueriesTableID:         crdbInternalLocalQueriesTable,
-		catconstants.CrdbInternalLocalTransactionsTableID:    crdbInternalLocalTxnsTable,
-		catconstants.CrdbInternalLocalSessionsTableID:        crdbInternalLocalSessionsTable,
-		catconstants.CrdbInternalLocalMetricsTableID:         crdbInternalLocalMetricsTable,
-		catconstants.CrdbInternalPartitionsTableID:           crdbInternalPartitionsTable,
-		catconstants.CrdbInternalPredefinedCommentsTableID:   crdbInternalPredefinedCommentsTable,
-		catconstants.CrdbInternalRangesNoLeasesTableID:       crdbInternalRangesNoLeasesTable,
-		catconstants.CrdbInternalRangesViewID:                crdbInternalRangesView,
-		catconstants.CrdbInternalRuntimeInfoTableID:          crdbInternalRuntimeInfoTable,
-		catconstants.CrdbInternalSchemaChangesTableID:        crdbInternalSchemaChangesTable,
-		catconstants.CrdbInternalSessionTraceTableID:         crdbInternalSessionTraceTable,
-		catconstants.CrdbInternalSessionVariablesTableID:     crdbInternalSessionVariablesTable,
-		catconstants.CrdbInternalStmtStatsTableID:            crdbInternalStmtStatsTable,
-		catconstants.CrdbInternalTableColumnsTableID:         crdbInternalTableColumnsTable,
-		catconstants.CrdbInternalTableIndexesTableID:         crdbInternalTableIndexesTable,
-		catconstants.CrdbInternalTablesTableLastStatsID:      crdbInternalTablesTableLastStats,
-		catconstants.CrdbInternalTablesTableID:               crdbInternalTablesTable,
-		catconstants.CrdbInternalTransactionStatsTableID:     crdbInternalTransactionStatisticsTable,
-		catconstants.CrdbInternalTxnStatsTableID:             crdbInternalTxnStatsTable,
-		catconstants.CrdbInternalZonesTableID:                crdbInternalZonesTable,
-		catconstants.CrdbInternalInvalidDescriptorsTableID:   crdbInternalInvalidDescriptorsTable,
+		catconstants.CrdbInternalBackwardDependenciesTableID:    crdbInternalBackwardDependenciesTable,
+		catconstants.CrdbInternalBuildInfoTableID:               crdbInternalBuildInfoTable,
+		catconstants.CrdbInternalBuiltinFunctionsTableID:        crdbInternalBuiltinFunctionsTable,
+		catconstants.CrdbInternalClusterContendedIndexesViewID:  crdbInternalClusterContendedIndexesView,
+		catconstants.CrdbInternalClusterContendedKeysViewID:     crdbInternalClusterContendedKeysView,
+		catconstants.CrdbInternalClusterContendedTablesViewID:   crdbInternalClusterContendedTablesView,
+		catconstants.CrdbInternalClusterContentionEventsTableID: crdbInternalClusterContentionEventsTable,
+		catconstants.CrdbInternalClusterQueriesTableID:          crdbInternalClusterQueriesTable,
+		catconstants.CrdbInternalClusterTransactionsTableID:     crdbInternalClusterTxnsTable,
+		catconstants.CrdbInternalClusterSessionsTableID:         crdbInternalClusterSessionsTable,
+		catconstants.CrdbInternalClusterSettingsTableID:         crdbInternalClusterSettingsTable,
+		catconstants.CrdbInternalClusterStmtStatsTableID:        crdbInternalClusterStmtStatsTable,
+		catconstants.CrdbInternalClusterTxnStatsTableID:         crdbInternalClusterTxnStatsTable,
+		catconstants.CrdbInternalCreateStmtsTableID:             crdbInternalCreateStmtsTable,
+		catconstants.CrdbInternalCreateTypeStmtsTableID:         crdbInternalCreateTypeStmtsTable,
+		catconstants.CrdbInternalDatabasesTableID:               crdbInternalDatabasesTable,
+		catconstants.CrdbInternalFeatureUsageID:                 crdbInternalFeatureUsage,
+		catconstants.CrdbInternalForwardDependenciesTableID:     crdbInternalForwardDependenciesTable,
+		catconstants.CrdbInternalGossipNodesTableID:             crdbInternalGossipNodesTable,
+		catconstants.CrdbInternalGossipAlertsTableID:            crdbInternalGossipAlertsTable,
+		catconstants.CrdbInternalGossipLivenessTableID:          crdbInternalGossipLivenessTable,
+		catconstants.CrdbInternalGossipNetworkTableID:           crdbInternalGossipNetworkTable,
+		catconstants.CrdbInternalIndexColumnsTableID:            crdbInternalIndexColumnsTable,
+		catconstants.CrdbInternalIndexRecommendationsTableID:    crdbInternalIndexRecommendationsTable,
+		catconstants.CrdbInternalJobsTableID:                    crdbInternalJobsTable,
+		catconstants.CrdbInternalKVNodeStatusTableID:            crdbInternalKVNodeStatusTable,
+		catconstants.CrdbInternalKVStoreStatusTableID:           crdbInternalKVStoreStatusTable,
+		catconstants.CrdbInternalLeasesTableID:                  crdbInternalLeasesTable,
+		catconstants.CrdbInternalLocalContentionEventsTableID:   crdbInternalLocalContentionEventsTable,
+		catconstants.CrdbInternalLocalQueriesTableID:            crdbInternalLocalQueriesTable,
+		catconstants.CrdbInternalLocalTransactionsTableID:       crdbInternalLocalTxnsTable,
+		catconstants.CrdbInternalLocalSessionsTableID:           crdbInternalLocalSessionsTable,
+		catconstants.CrdbInternalLocalMetricsTableID:            crdbInternalLocalMetricsTable,
+		catconstants.CrdbInternalPartitionsTableID:              crdbInternalPartitionsTable,
+		catconstants.CrdbInternalPredefinedCommentsTableID:      crdbInternalPredefinedCommentsTable,
+		catconstants.CrdbInternalRangesNoLeasesTableID:          crdbInternalRangesNoLeasesTable,
+		catconstants.CrdbInternalRangesViewID:                   crdbInternalRangesView,
+		catconstants.CrdbInternalRuntimeInfoTableID:             crdbInternalRuntimeInfoTable,
+		catconstants.CrdbInternalSchemaChangesTableID:           crdbInternalSchemaChangesTable,
+		catconstants.CrdbInternalSessionTraceTableID:            crdbInternalSessionTraceTable,
+		catconstants.CrdbInternalSessionVariablesTableID:        crdbInternalSessionVariablesTable,
+		catconstants.CrdbInternalStmtStatsTableID:               crdbInternalStmtStatsTable,
+		catconstants.CrdbInternalTableColumnsTableID:            crdbInternalTableColumnsTable,
+		catconstants.CrdbInternalTableIndexesTableID:            crdbInternalTableIndexesTable,
+		catconstants.CrdbInternalTablesTableLastStatsID:         crdbInternalTablesTableLastStats,
+		catconstants.CrdbInternalTablesTableID:                  crdbInternalTablesTable,
+		catconstants.CrdbInternalTransactionStatsTableID:        crdbInternalTransactionStatisticsTable,
+		catconstants.CrdbInternalTxnStatsTableID:                crdbInternalTxnStatsTable,
+		catconstants.CrdbInternalZonesTableID:                   crdbInternalZonesTable,
+		catconstants.CrdbInternalInvalidDescriptorsTableID:      crdbInternalInvalidDescriptorsTable,
 	},
 	validWithNoDatabaseContext: true,
 }
@@ -818,6 +829,8 @@ CREATE TABLE crdb_internal.node_statement_statistics (
   bytes_read_var      FLOAT NOT NULL,
   rows_read_avg       FLOAT NOT NULL,
   rows_read_var       FLOAT NOT NULL,
+  contention_time_avg FLOAT NOT NULL,
+  contention_time_var FLOAT NOT NULL,
   implicit_txn        BOOL NOT NULL
 )`,
 	populate: func(ctx context.Context, p *planner, _ *dbdesc.Immutable, addRow func(...tree.Datum) error) error {
@@ -911,6 +924,8 @@ CREATE TABLE crdb_internal.node_statement_statistics (
 					tree.NewDFloat(tree.DFloat(s.mu.data.BytesRead.GetVariance(s.mu.data.Count))),
 					tree.NewDFloat(tree.DFloat(s.mu.data.RowsRead.Mean)),
 					tree.NewDFloat(tree.DFloat(s.mu.data.RowsRead.GetVariance(s.mu.data.Count))),
+					tree.NewDFloat(tree.DFloat(s.mu.data.ContentionTime.Mean)),
+					tree.NewDFloat(tree.DFloat(s.mu.data.ContentionTime.GetVariance(s.mu.data.Count))),
 					tree.MakeDBool(tree.DBool(stmtKey.implicitTxn)),
 				)
 				s.mu.Unlock()
@@ -931,20 +946,22 @@ var crdbInternalTransactionStatisticsTable = virtualSchemaTable{
 		`This table is wiped periodically (by default, at least every two hours)`,
 	schema: `
 CREATE TABLE crdb_internal.node_transaction_statistics (
-  node_id           INT NOT NULL,
-  application_name  STRING NOT NULL,
-  key               STRING,
-  statement_ids     STRING[],
-  count             INT,
-  max_retries       INT,
-  service_lat_avg   FLOAT NOT NULL,
-  service_lat_var   FLOAT NOT NULL,
-  retry_lat_avg     FLOAT NOT NULL,
-  retry_lat_var     FLOAT NOT NULL,
-  commit_lat_avg    FLOAT NOT NULL,
-  commit_lat_var    FLOAT NOT NULL,
-  rows_read_avg     FLOAT NOT NULL,
-  rows_read_var     FLOAT NOT NULL
+  node_id             INT NOT NULL,
+  application_name    STRING NOT NULL,
+  key                 STRING,
+  statement_ids       STRING[],
+  count               INT,
+  max_retries         INT,
+  service_lat_avg     FLOAT NOT NULL,
+  service_lat_var     FLOAT NOT NULL,
+  retry_lat_avg       FLOAT NOT NULL,
+  retry_lat_var       FLOAT NOT NULL,
+  commit_lat_avg      FLOAT NOT NULL,
+  commit_lat_var      FLOAT NOT NULL,
+  rows_read_avg       FLOAT NOT NULL,
+  rows_read_var       FLOAT NOT NULL,
+  contention_time_avg FLOAT NOT NULL,
+  contention_time_var FLOAT NOT NULL
 )
 `,
 	populate: func(ctx context.Context, p *planner, _ *dbdesc.Immutable, addRow func(...tree.Datum) error) error {
@@ -1024,6 +1041,8 @@ CREATE TABLE crdb_internal.node_transaction_statistics (
 					tree.NewDFloat(tree.DFloat(s.mu.data.CommitLat.GetVariance(s.mu.data.Count))),
 					tree.NewDFloat(tree.DFloat(s.mu.data.NumRows.Mean)),
 					tree.NewDFloat(tree.DFloat(s.mu.data.NumRows.GetVariance(s.mu.data.Count))),
+					tree.NewDFloat(tree.DFloat(s.mu.data.ContentionTime.Mean)),
+					tree.NewDFloat(tree.DFloat(s.mu.data.ContentionTime.GetVariance(s.mu.data.Count))),
 				)
 
 				s.mu.Unlock()
@@ -1037,6 +1056,188 @@ CREATE TABLE crdb_internal.node_transaction_statistics (
 	},
 }
 
+var crdbInternalClusterStmtStatsTable = virtualSchemaTable{
+	comment: `statement statistics persisted in system.statement_statistics, ` +
+		`combined with the statistics not yet flushed by the local node`,
+	schema: `
+CREATE TABLE crdb_internal.statement_statistics (
+  aggregated_ts  TIMESTAMPTZ NOT NULL,
+  fingerprint_id BYTES NOT NULL,
+  app_name       STRING NOT NULL,
+  node_id        INT NOT NULL,
+  agg_interval   INTERVAL NOT NULL,
+  metadata       JSONB NOT NULL,
+  statistics     JSONB NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ *dbdesc.Immutable, addRow func(...tree.Datum) error) error {
+		if err := p.requireViewActivity(ctx); err != nil {
+			return err
+		}
+		if p.extendedEvalCtx.sqlStatsCollector.sqlStats == nil {
+			return errors.AssertionFailedf(
+				"cannot access sql statistics from this context")
+		}
+		stmtStats, err := p.combinedStmtStats(ctx)
+		if err != nil {
+			return err
+		}
+		for i := range stmtStats {
+			s := &stmtStats[i]
+			ts, err := tree.MakeDTimestampTZ(s.AggregatedTs, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			metadata := json.NewObjectBuilder(7)
+			metadata.Add("query", json.FromString(s.Key.Query))
+			metadata.Add("app", json.FromString(s.Key.App))
+			metadata.Add("distsql", json.FromBool(s.Key.DistSQL))
+			metadata.Add("failed", json.FromBool(s.Key.Failed))
+			metadata.Add("opt", json.FromBool(s.Key.Opt))
+			metadata.Add("implicitTxn", json.FromBool(s.Key.ImplicitTxn))
+			metadata.Add("vec", json.FromBool(s.Key.Vec))
+			statistics, err := protoreflect.MessageToJSON(&s.Stats, true /* emitDefaults */)
+			if err != nil {
+				return err
+			}
+			if err := addRow(
+				ts,
+				tree.NewDBytes(tree.DBytes(encodeFingerprintID(uint64(s.ID)))),
+				tree.NewDString(s.Key.App),
+				tree.NewDInt(tree.DInt(s.NodeID)),
+				tree.NewDInterval(
+					duration.MakeDuration(s.AggInterval.Nanoseconds(), 0 /* days */, 0 /* months */),
+					types.DefaultIntervalTypeMetadata,
+				),
+				tree.NewDJSON(metadata.Build()),
+				tree.NewDJSON(statistics),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var crdbInternalIndexRecommendationsTable = virtualSchemaTable{
+	comment: `indexes recommended by the optimizer for the statements in ` +
+		`crdb_internal.statement_statistics`,
+	schema: `
+CREATE TABLE crdb_internal.index_recommendations (
+  recommendation   STRING NOT NULL,
+  table_name       STRING NOT NULL,
+  statement_count  INT NOT NULL,
+  execution_count  INT NOT NULL,
+  last_recommended TIMESTAMPTZ NOT NULL,
+  fingerprints     STRING[] NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ *dbdesc.Immutable, addRow func(...tree.Datum) error) error {
+		if err := p.requireViewActivity(ctx); err != nil {
+			return err
+		}
+		if p.extendedEvalCtx.sqlStatsCollector.sqlStats == nil {
+			return errors.AssertionFailedf(
+				"cannot access sql statistics from this context")
+		}
+		stmtStats, err := p.combinedStmtStats(ctx)
+		if err != nil {
+			return err
+		}
+
+		collected := make([]roachpb.CollectedStatementStatistics, len(stmtStats))
+		for i := range stmtStats {
+			collected[i] = stmtStats[i].CollectedStatementStatistics
+		}
+		recs, err := AggregateIndexRecommendations(collected)
+		if err != nil {
+			return err
+		}
+		for i := range recs {
+			r := &recs[i]
+			ts, err := tree.MakeDTimestampTZ(r.LastRecommended, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			fingerprints := tree.NewDArray(types.String)
+			for _, f := range r.Fingerprints {
+				if err := fingerprints.Append(tree.NewDString(f)); err != nil {
+					return err
+				}
+			}
+			if err := addRow(
+				tree.NewDString(r.Recommendation),
+				tree.NewDString(r.TableName),
+				tree.NewDInt(tree.DInt(r.StatementCount)),
+				tree.NewDInt(tree.DInt(r.ExecutionCount)),
+				ts,
+				fingerprints,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var crdbInternalClusterTxnStatsTable = virtualSchemaTable{
+	comment: `transaction statistics persisted in system.transaction_statistics, ` +
+		`combined with the statistics not yet flushed by the local node`,
+	schema: `
+CREATE TABLE crdb_internal.transaction_statistics (
+  aggregated_ts  TIMESTAMPTZ NOT NULL,
+  fingerprint_id BYTES NOT NULL,
+  app_name       STRING NOT NULL,
+  node_id        INT NOT NULL,
+  agg_interval   INTERVAL NOT NULL,
+  metadata       JSONB NOT NULL,
+  statistics     JSONB NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ *dbdesc.Immutable, addRow func(...tree.Datum) error) error {
+		if err := p.requireViewActivity(ctx); err != nil {
+			return err
+		}
+		if p.extendedEvalCtx.sqlStatsCollector.sqlStats == nil {
+			return errors.AssertionFailedf(
+				"cannot access sql statistics from this context")
+		}
+		txnStats, err := p.combinedTxnStats(ctx)
+		if err != nil {
+			return err
+		}
+		for i := range txnStats {
+			s := &txnStats[i]
+			ts, err := tree.MakeDTimestampTZ(s.AggregatedTs, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			stmtIDs := json.NewArrayBuilder(len(s.StatementIDs))
+			for _, id := range s.StatementIDs {
+				stmtIDs.Add(json.FromString(hex.EncodeToString(encodeFingerprintID(uint64(id)))))
+			}
+			metadata := json.NewObjectBuilder(1)
+			metadata.Add("stmtFingerprintIDs", stmtIDs.Build())
+			statistics, err := protoreflect.MessageToJSON(&s.Stats, true /* emitDefaults */)
+			if err != nil {
+				return err
+			}
+			if err := addRow(
+				ts,
+				tree.NewDBytes(tree.DBytes(encodeFingerprintID(uint64(s.TransactionFingerprintID)))),
+				tree.NewDString(s.App),
+				tree.NewDInt(tree.DInt(s.NodeID)),
+				tree.NewDInterval(
+					duration.MakeDuration(s.AggInterval.Nanoseconds(), 0 /* days */, 0 /* months */),
+					types.DefaultIntervalTypeMetadata,
+				),
+				tree.NewDJSON(metadata.Build()),
+				tree.NewDJSON(statistics),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 var crdbInternalTxnStatsTable = virtualSchemaTable{
 	comment: `per-application transaction statistics (in-memory, not durable; local node only). ` +
 		`This table is wiped periodically (by default, at least every two hours)`,
@@ -2426,6 +2627,214 @@ CREATE TABLE crdb_internal.forward_dependencies (
 	},
 }
 
+const contentionEventsSchemaPattern = `
+CREATE TABLE crdb_internal.%s (
+  table_id                   INT,
+  index_id                   INT,
+  num_contention_events      INT NOT NULL,
+  cumulative_contention_time INTERVAL NOT NULL,
+  key                        BYTES NOT NULL,
+  txn_id                     UUID NOT NULL,
+  count                      INT NOT NULL
+)
+`
+
+// crdbInternalLocalContentionEventsTable exposes the contention events
+// observed by the statements executed on the current node.
+var crdbInternalLocalContentionEventsTable = virtualSchemaTable{
+	comment: `contention information (RAM; local node only)
+
+All of the contention information internally stored in three levels:
+- on the highest, it is grouped by tableID/indexID pair
+- on the middle, it is grouped by key
+- on the lowest, it is grouped by txnID.
+Each of the levels is maintained as an LRU cache with limited size, so
+it is possible that not all of the contention information ever observed
+is contained in this table.
+`,
+	schema: fmt.Sprintf(contentionEventsSchemaPattern, "node_contention_events"),
+	populate: func(ctx context.Context, p *planner, _ *dbdesc.Immutable, addRow func(...tree.Datum) error) error {
+		if err := p.requireViewActivity(ctx); err != nil {
+			return err
+		}
+		response, err := p.extendedEvalCtx.SQLStatusServer.ListLocalContentionEvents(
+			ctx, &serverpb.ListContentionEventsRequest{},
+		)
+		if err != nil {
+			return err
+		}
+		return populateContentionEventsTable(ctx, addRow, response)
+	},
+}
+
+// crdbInternalClusterContentionEventsTable exposes the contention events
+// observed by the statements executed on all nodes of the cluster.
+var crdbInternalClusterContentionEventsTable = virtualSchemaTable{
+	comment: `contention information (cluster RPC; expensive!)
+
+All of the contention information internally stored in three levels:
+- on the highest, it is grouped by tableID/indexID pair
+- on the middle, it is grouped by key
+- on the lowest, it is grouped by txnID.
+Each of the levels is maintained as an LRU cache with limited size, so
+it is possible that not all of the contention information ever observed
+is contained in this table.
+`,
+	schema: fmt.Sprintf(contentionEventsSchemaPattern, "cluster_contention_events"),
+	populate: func(ctx context.Context, p *planner, _ *dbdesc.Immutable, addRow func(...tree.Datum) error) error {
+		if err := p.requireViewActivity(ctx); err != nil {
+			return err
+		}
+		response, err := p.extendedEvalCtx.SQLStatusServer.ListContentionEvents(
+			ctx, &serverpb.ListContentionEventsRequest{},
+		)
+		if err != nil {
+			return err
+		}
+		return populateContentionEventsTable(ctx, addRow, response)
+	},
+}
+
+// requireViewActivity returns an error if the current user does not have the
+// VIEWACTIVITY role option, which is required to view the statement statistics
+// and the contention events. The contention events expose the keys of the rows
+// that were contended on, so they are restricted in the same way as the
+// statement statistics.
+func (p *planner) requireViewActivity(ctx context.Context) error {
+	hasViewActivity, err := p.HasRoleOption(ctx, roleoption.VIEWACTIVITY)
+	if err != nil {
+		return err
+	}
+	if !hasViewActivity {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"user %s does not have %s privilege", p.User(), roleoption.VIEWACTIVITY)
+	}
+	return nil
+}
+
+func populateContentionEventsTable(
+	ctx context.Context,
+	addRow func(...tree.Datum) error,
+	response *serverpb.ListContentionEventsResponse,
+) error {
+	for _, ice := range response.Events {
+		tableID := tree.NewDInt(tree.DInt(ice.TableID))
+		indexID := tree.NewDInt(tree.DInt(ice.IndexID))
+		numContentionEvents := tree.NewDInt(tree.DInt(ice.NumContentionEvents))
+		cumulativeContentionTime := tree.NewDInterval(
+			duration.MakeDuration(ice.CumulativeContentionTime.Nanoseconds(), 0 /* days */, 0 /* months */),
+			types.DefaultIntervalTypeMetadata,
+		)
+		for _, skc := range ice.Events {
+			for _, stc := range skc.Txns {
+				if err := addRow(
+					tableID,
+					indexID,
+					numContentionEvents,
+					cumulativeContentionTime,
+					tree.NewDBytes(tree.DBytes(skc.Key)),
+					tree.NewDUuid(tree.DUuid{UUID: stc.TxnID}),
+					tree.NewDInt(tree.DInt(stc.Count)),
+				); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, rpcErr := range response.Errors {
+		log.Warningf(ctx, "%v", rpcErr.Message)
+	}
+	return nil
+}
+
+// crdbInternalClusterContendedTablesView exposes the tables that experienced
+// contention, along with the number of contention events observed on each.
+var crdbInternalClusterContendedTablesView = virtualSchemaView{
+	schema: `
+CREATE VIEW crdb_internal.cluster_contended_tables AS
+  SELECT
+    database_name, schema_name, name, sum(num_contention_events) AS num_contention_events
+  FROM
+    (
+      SELECT DISTINCT
+        database_name, schema_name, name, index_id, num_contention_events
+      FROM
+        crdb_internal.cluster_contention_events
+        JOIN crdb_internal.tables ON
+            crdb_internal.cluster_contention_events.table_id = crdb_internal.tables.table_id
+    )
+  GROUP BY
+    database_name, schema_name, name
+`,
+	resultColumns: colinfo.ResultColumns{
+		{Name: "database_name", Typ: types.String},
+		{Name: "schema_name", Typ: types.String},
+		{Name: "name", Typ: types.String},
+		{Name: "num_contention_events", Typ: types.Decimal},
+	},
+}
+
+// crdbInternalClusterContendedIndexesView exposes the indexes that
+// experienced contention, along with the number of contention events observed
+// on each.
+var crdbInternalClusterContendedIndexesView = virtualSchemaView{
+	schema: `
+CREATE VIEW crdb_internal.cluster_contended_indexes AS
+  SELECT DISTINCT
+    database_name, schema_name, name, index_name, num_contention_events
+  FROM
+    crdb_internal.cluster_contention_events,
+    crdb_internal.tables,
+    crdb_internal.table_indexes
+  WHERE
+    crdb_internal.cluster_contention_events.index_id = crdb_internal.table_indexes.index_id
+    AND crdb_internal.cluster_contention_events.table_id = crdb_internal.table_indexes.descriptor_id
+    AND crdb_internal.cluster_contention_events.table_id = crdb_internal.tables.table_id
+  ORDER BY
+    num_contention_events DESC
+`,
+	resultColumns: colinfo.ResultColumns{
+		{Name: "database_name", Typ: types.String},
+		{Name: "schema_name", Typ: types.String},
+		{Name: "name", Typ: types.String},
+		{Name: "index_name", Typ: types.String},
+		{Name: "num_contention_events", Typ: types.Int},
+	},
+}
+
+// crdbInternalClusterContendedKeysView exposes the keys that experienced
+// contention, along with the number of contention events observed on each.
+var crdbInternalClusterContendedKeysView = virtualSchemaView{
+	schema: `
+CREATE VIEW crdb_internal.cluster_contended_keys AS
+  SELECT
+    database_name,
+    schema_name,
+    name,
+    index_name,
+    crdb_internal.pretty_key(key, 0) AS key,
+    sum(count) AS num_contention_events
+  FROM
+    crdb_internal.cluster_contention_events,
+    crdb_internal.tables,
+    crdb_internal.table_indexes
+  WHERE
+    crdb_internal.cluster_contention_events.index_id = crdb_internal.table_indexes.index_id
+    AND crdb_internal.cluster_contention_events.table_id = crdb_internal.table_indexes.descriptor_id
+    AND crdb_internal.cluster_contention_events.table_id = crdb_internal.tables.table_id
+  GROUP BY
+    database_name, schema_name, name, index_name, key
+`,
+	resultColumns: colinfo.ResultColumns{
+		{Name: "database_name", Typ: types.String},
+		{Name: "schema_name", Typ: types.String},
+		{Name: "name", Typ: types.String},
+		{Name: "index_name", Typ: types.String},
+		{Name: "key", Typ: types.String},
+		{Name: "num_contention_events", Typ: types.Decimal},
+	},
+}
+
 // crdbInternalRangesView exposes system ranges.
 var crdbInternalRangesView = virtualSchemaView{
 	schema: `
@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/tests"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
@@ -504,3 +505,74 @@ DELETE FROM system.descriptor WHERE id=54;
 
 	require.False(t, rows.Next())
 }
+
+// TestContentionEventsTables verifies that the contention events observed by
+// a read that blocks on a conflicting write intent are exposed through the
+// crdb_internal contention tables.
+func TestContentionEventsTables(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	sqlutils.MakeSQLRunner(sqlDB).Exec(t, `
+CREATE TABLE test (k INT PRIMARY KEY, v INT);
+INSERT INTO test VALUES (1, 1);
+`)
+
+	// Lay down an intent on the row and keep the writing transaction open.
+	writer, err := sqlDB.Begin()
+	require.NoError(t, err)
+	_, err = writer.Exec(`UPDATE test SET v = 2 WHERE k = 1`)
+	require.NoError(t, err)
+
+	// Read the row from a separate connection, which blocks on the intent until
+	// the writing transaction commits.
+	readErr := make(chan error, 1)
+	go func() {
+		_, err := sqlDB.Exec(`SELECT * FROM test WHERE k = 1`)
+		readErr <- err
+	}()
+	// Wait for the reader to start executing, and give it some time to start
+	// waiting on the intent.
+	testutils.SucceedsSoon(t, func() error {
+		var n int
+		if err := sqlDB.QueryRow(`
+SELECT count(*) FROM crdb_internal.cluster_queries
+WHERE query = 'SELECT * FROM test WHERE k = 1' AND phase = 'executing'`,
+		).Scan(&n); err != nil {
+			return err
+		}
+		if n == 0 {
+			return errors.New("reader is not executing yet")
+		}
+		return nil
+	})
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, writer.Commit())
+	require.NoError(t, <-readErr)
+
+	for _, table := range []string{"node_contention_events", "cluster_contention_events"} {
+		var numEvents, count int
+		require.NoError(t, sqlDB.QueryRow(fmt.Sprintf(`
+SELECT num_contention_events, count
+FROM crdb_internal.%s
+WHERE table_id = 'test'::REGCLASS::INT AND index_id = 1`, table),
+		).Scan(&numEvents, &count))
+		require.Equal(t, 1, numEvents)
+		require.Equal(t, 1, count)
+	}
+
+	var dbName, tableName, indexName string
+	var numEvents int
+	require.NoError(t, sqlDB.QueryRow(`
+SELECT database_name, name, index_name, num_contention_events
+FROM crdb_internal.cluster_contended_indexes`,
+	).Scan(&dbName, &tableName, &indexName, &numEvents))
+	require.Equal(t, "defaultdb", dbName)
+	require.Equal(t, "test", tableName)
+	require.Equal(t, "primary", indexName)
+	require.Equal(t, 1, numEvents)
+}
@@ -139,6 +139,29 @@ func (p *planner) setupFamilyAndConstraintForShard(
 	return nil
 }
 
+// checkInvertedColumnOpClass checks that the operator class given for the
+// inverted column of an inverted index, if any, can be used with the type of
+// the column. Strings are indexed by their trigrams, which must be requested
+// explicitly with the gin_trgm_ops operator class, as in Postgres.
+func checkInvertedColumnOpClass(col *descpb.ColumnDescriptor, elem *tree.IndexElem) error {
+	if col.Type.Family() == types.StringFamily {
+		if elem.OpClass == "" {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.UndefinedObject,
+					"data type %s has no default operator class for access method \"gin\"",
+					col.Type.SQLString()),
+				"You must specify an operator class for the index; use gin_trgm_ops to create a trigram index.",
+			)
+		}
+		return nil
+	}
+	if elem.OpClass != "" {
+		return pgerror.Newf(pgcode.DatatypeMismatch,
+			"operator class %q does not accept data type %s", elem.OpClass, col.Type.SQLString())
+	}
+	return nil
+}
+
 // MakeIndexDescriptor creates an index descriptor from a CreateIndex node and optionally
 // adds a hidden computed shard column (along with its check constraint) in case the index
 // is hash sharded. Note that `tableDesc` will be modified when this method is called for
@@ -188,10 +211,14 @@ func MakeIndexDescriptor(
 			return nil, pgerror.New(pgcode.FeatureNotSupported, "indexing more than one column with an inverted index is not supported")
 		}
 		indexDesc.Type = descpb.IndexDescriptor_INVERTED
-		columnDesc, _, err := tableDesc.FindColumnByName(n.Columns[len(n.Columns)-1].Column)
+		invertedElem := &n.Columns[len(n.Columns)-1]
+		columnDesc, _, err := tableDesc.FindColumnByName(invertedElem.Column)
 		if err != nil {
 			return nil, err
 		}
+		if err := checkInvertedColumnOpClass(columnDesc, invertedElem); err != nil {
+			return nil, err
+		}
 		switch columnDesc.Type.Family() {
 		case types.GeometryFamily:
 			config, err := geoindex.GeometryIndexConfigForSRID(columnDesc.Type.GeoSRIDOrZero())
@@ -203,6 +230,8 @@ func MakeIndexDescriptor(
 		case types.GeographyFamily:
 			indexDesc.GeoConfig = *geoindex.DefaultGeographyIndexConfig()
 			telemetry.Inc(sqltelemetry.GeographyInvertedIndexCounter)
+		case types.StringFamily:
+			telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
 		}
 		telemetry.Inc(sqltelemetry.InvertedIndexCounter)
 	}
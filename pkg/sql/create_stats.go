@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -218,7 +219,7 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 		if err != nil {
 			return nil, err
 		}
-		isInvIndex := colinfo.ColumnTypeIsInvertedIndexable(col.Type)
+		isInvIndex := colinfo.ColumnTypeIsOnlyInvertedIndexable(col.Type)
 		colStats = []jobspb.CreateStatsDetails_ColStat{{
 			ColumnIDs: columnIDs,
 			// By default, create histograms on all explicitly requested column stats
@@ -237,6 +238,26 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 		}
 	}
 
+	if n.Options.UsingExtremes {
+		if !n.p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.PartialTableStats) {
+			return nil, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"USING EXTREMES requires all nodes to be upgraded to %s",
+				clusterversion.ByKey(clusterversion.PartialTableStats),
+			)
+		}
+		if len(n.ColumnNames) != 1 || len(colStats) != 1 {
+			return nil, pgerror.New(pgcode.InvalidParameterValue,
+				"USING EXTREMES requires a single column that is not inverted indexable",
+			)
+		}
+		if findExtremesIndex(tableDesc, colStats[0].ColumnIDs[0]) == nil {
+			return nil, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"table %s does not have an index with column %s as its first key column",
+				fqTableName, tree.ErrString(&n.ColumnNames[0]),
+			)
+		}
+	}
+
 	// Evaluate the AS OF time, if any.
 	var asOf *hlc.Timestamp
 	if n.Options.AsOf.Expr != nil {
@@ -271,11 +292,29 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 			Statement:       n.String(),
 			AsOf:            asOf,
 			MaxFractionIdle: n.Options.Throttling,
+			UsingExtremes:   n.Options.UsingExtremes,
 		},
 		Progress: jobspb.CreateStatsProgress{},
 	}, nil
 }
 
+// findExtremesIndex returns an index that can be used to scan the lowest and
+// highest values of the given column for CREATE STATISTICS ... USING EXTREMES,
+// or nil if there is none. The index must be a public, non-partial forward
+// index with the column as its first key column.
+func findExtremesIndex(desc *tabledesc.Immutable, colID descpb.ColumnID) *descpb.IndexDescriptor {
+	if desc.PrimaryIndex.ColumnIDs[0] == colID {
+		return &desc.PrimaryIndex
+	}
+	for i := range desc.Indexes {
+		idx := &desc.Indexes[i]
+		if idx.Type == descpb.IndexDescriptor_FORWARD && !idx.IsPartial() && idx.ColumnIDs[0] == colID {
+			return idx
+		}
+	}
+	return nil
+}
+
 // maxNonIndexCols is the maximum number of non-index columns that we will use
 // when choosing a default set of column statistics.
 const maxNonIndexCols = 100
@@ -435,7 +474,7 @@ func createStatsDefaultColumns(
 		}
 		colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
 			ColumnIDs:           colList,
-			HasHistogram:        !colinfo.ColumnTypeIsInvertedIndexable(col.Type),
+			HasHistogram:        !colinfo.ColumnTypeIsOnlyInvertedIndexable(col.Type),
 			HistogramMaxBuckets: maxHistBuckets,
 		})
 		nonIdxCols++
@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catformat"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
@@ -84,6 +85,8 @@ var minimumTypeUsageVersions = map[types.Family]clusterversion.Key{
 	types.GeographyFamily: clusterversion.GeospatialType,
 	types.GeometryFamily:  clusterversion.GeospatialType,
 	types.Box2DFamily:     clusterversion.Box2DType,
+	types.TSQueryFamily:   clusterversion.TSearchTypes,
+	types.TSVectorFamily:  clusterversion.TSearchTypes,
 }
 
 // isTypeSupportedInVersion returns whether a given type is supported in the given version.
@@ -1426,6 +1429,9 @@ func NewTableDesc(
 				if err != nil {
 					return nil, err
 				}
+				if err := checkInvertedColumnOpClass(columnDesc, &d.Columns[len(d.Columns)-1]); err != nil {
+					return nil, err
+				}
 				switch columnDesc.Type.Family() {
 				case types.GeometryFamily:
 					config, err := geoindex.GeometryIndexConfigForSRID(columnDesc.Type.GeoSRIDOrZero())
@@ -1712,6 +1718,8 @@ func NewTableDesc(
 				} else if geoindex.IsGeometryConfig(&idx.GeoConfig) {
 					telemetry.Inc(sqltelemetry.GeometryInvertedIndexCounter)
 				}
+			} else if catformat.InvertedColumnOpClass(&desc, idx) == catformat.TrigramOpClass {
+				telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
 			}
 		}
 		return nil
@@ -1725,27 +1733,9 @@ func NewTableDesc(
 			return nil, errors.Wrap(err, "error fetching database descriptor for locality checks")
 		}
 
-		desc.LocalityConfig = &descpb.TableDescriptor_LocalityConfig{}
-		switch n.Locality.LocalityLevel {
-		case tree.LocalityLevelGlobal:
-			desc.LocalityConfig.Locality = &descpb.TableDescriptor_LocalityConfig_Global_{
-				Global: &descpb.TableDescriptor_LocalityConfig_Global{},
-			}
-		case tree.LocalityLevelTable:
-			l := &descpb.TableDescriptor_LocalityConfig_RegionalByTable_{
-				RegionalByTable: &descpb.TableDescriptor_LocalityConfig_RegionalByTable{},
-			}
-			if n.Locality.TableRegion != "" {
-				region := descpb.Region(n.Locality.TableRegion)
-				l.RegionalByTable.Region = &region
-			}
-			desc.LocalityConfig.Locality = l
-		case tree.LocalityLevelRow:
-			desc.LocalityConfig.Locality = &descpb.TableDescriptor_LocalityConfig_RegionalByRow_{
-				RegionalByRow: &descpb.TableDescriptor_LocalityConfig_RegionalByRow{},
-			}
-		default:
-			return nil, errors.Newf("unknown locality level: %v", n.Locality.LocalityLevel)
+		desc.LocalityConfig, err = makeTableLocalityConfig(n.Locality)
+		if err != nil {
+			return nil, err
 		}
 
 		if err := tabledesc.ValidateTableLocalityConfig(
@@ -1958,6 +1948,10 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 					}
 					indexDef.Columns = append(indexDef.Columns, elem)
 				}
+				// The inverted column is always the last column of the index.
+				if opClass := catformat.InvertedColumnOpClass(td, idx); opClass != "" {
+					indexDef.Columns[len(indexDef.Columns)-1].OpClass = opClass
+				}
 				for _, name := range idx.StoreColumnNames {
 					indexDef.Storing = append(indexDef.Storing, tree.Name(name))
 				}
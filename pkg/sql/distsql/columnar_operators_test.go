@@ -1027,11 +1027,26 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 	maxNum := 10
 	typs := make([]*types.T, maxCols)
 	for i := range typs {
-		// TODO(yuzefovich): randomize the types of the columns once we support
-		// window functions that take in arguments.
+		// TODO(yuzefovich): randomize the types of the columns.
 		typs[i] = types.Int
 	}
+	var windowFns []execinfrapb.WindowerSpec_Func
 	for windowFn := range colbuilder.SupportedWindowFns {
+		windowFn := windowFn
+		windowFns = append(windowFns, execinfrapb.WindowerSpec_Func{WindowFunc: &windowFn})
+	}
+	for _, aggFn := range []execinfrapb.AggregatorSpec_Func{
+		execinfrapb.AggregatorSpec_COUNT_ROWS,
+		execinfrapb.AggregatorSpec_COUNT,
+		execinfrapb.AggregatorSpec_SUM,
+		execinfrapb.AggregatorSpec_AVG,
+		execinfrapb.AggregatorSpec_MIN,
+		execinfrapb.AggregatorSpec_MAX,
+	} {
+		aggFn := aggFn
+		windowFns = append(windowFns, execinfrapb.WindowerSpec_Func{AggregateFunc: &aggFn})
+	}
+	for _, windowFn := range windowFns {
 		for _, partitionBy := range [][]uint32{
 			{},     // No PARTITION BY clause.
 			{0},    // Partitioning on the first input column.
@@ -1048,29 +1063,56 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 					}
 					inputTypes := typs[:nCols:nCols]
 					rows := rowenc.MakeRandIntRowsInRange(rng, nRows, nCols, maxNum, nullProbability)
+					argsIdxs := generateWindowFnArgs(rng, windowFn, nCols)
+					if windowFn.WindowFunc != nil {
+						switch *windowFn.WindowFunc {
+						case execinfrapb.WindowerSpec_NTILE, execinfrapb.WindowerSpec_NTH_VALUE:
+							// The last argument of these window functions must be
+							// positive.
+							positiveArgIdx := argsIdxs[len(argsIdxs)-1]
+							for _, row := range rows {
+								if !row[positiveArgIdx].IsNull() {
+									row[positiveArgIdx] = rowenc.DatumToEncDatum(
+										types.Int, tree.NewDInt(tree.DInt(1+rng.Intn(maxNum))),
+									)
+								}
+							}
+						}
+					}
 
 					windowerSpec := &execinfrapb.WindowerSpec{
 						PartitionBy: partitionBy,
 						WindowFns: []execinfrapb.WindowerSpec_WindowFn{
 							{
-								Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &windowFn},
+								Func:         windowFn,
+								ArgsIdxs:     argsIdxs,
 								Ordering:     generateOrderingGivenPartitionBy(rng, nCols, nOrderingCols, partitionBy),
 								OutputColIdx: uint32(nCols),
 								FilterColIdx: tree.NoColumnIdx,
 							},
 						},
 					}
-					if windowFn == execinfrapb.WindowerSpec_ROW_NUMBER &&
-						len(partitionBy)+len(windowerSpec.WindowFns[0].Ordering.Columns) < nCols {
-						// The output of row_number is not deterministic if there are
-						// columns that are not present in either PARTITION BY or ORDER BY
-						// clauses, so we skip such a configuration.
-						continue
+					if windowFn.WindowFunc != nil {
+						switch *windowFn.WindowFunc {
+						case execinfrapb.WindowerSpec_ROW_NUMBER, execinfrapb.WindowerSpec_NTILE,
+							execinfrapb.WindowerSpec_LAG, execinfrapb.WindowerSpec_LEAD,
+							execinfrapb.WindowerSpec_FIRST_VALUE, execinfrapb.WindowerSpec_LAST_VALUE,
+							execinfrapb.WindowerSpec_NTH_VALUE:
+							if len(partitionBy)+len(windowerSpec.WindowFns[0].Ordering.Columns) < nCols {
+								// The output of these window functions is not deterministic
+								// if there are columns that are not present in either
+								// PARTITION BY or ORDER BY clauses, so we skip such a
+								// configuration.
+								continue
+							}
+						}
 					}
 
-					// Currently, we only support window functions that take no
-					// arguments, so we leave the second argument empty.
-					_, outputType, err := execinfrapb.GetWindowFunctionInfo(execinfrapb.WindowerSpec_Func{WindowFunc: &windowFn})
+					argTypes := make([]*types.T, len(argsIdxs))
+					for i, idx := range argsIdxs {
+						argTypes[i] = inputTypes[idx]
+					}
+					_, outputType, err := execinfrapb.GetWindowFunctionInfo(windowFn, argTypes...)
 					require.NoError(t, err)
 					pspec := &execinfrapb.ProcessorSpec{
 						Input:       []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
@@ -1095,6 +1137,36 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 	}
 }
 
+// generateWindowFnArgs returns random indices of the argument columns for the
+// given window function assuming that all nCols input columns are integers.
+func generateWindowFnArgs(
+	rng *rand.Rand, windowFn execinfrapb.WindowerSpec_Func, nCols int,
+) []uint32 {
+	numArgs := 1
+	if windowFn.AggregateFunc != nil {
+		if *windowFn.AggregateFunc == execinfrapb.AggregatorSpec_COUNT_ROWS {
+			numArgs = 0
+		}
+	} else {
+		switch *windowFn.WindowFunc {
+		case execinfrapb.WindowerSpec_ROW_NUMBER, execinfrapb.WindowerSpec_RANK,
+			execinfrapb.WindowerSpec_DENSE_RANK, execinfrapb.WindowerSpec_PERCENT_RANK,
+			execinfrapb.WindowerSpec_CUME_DIST:
+			numArgs = 0
+		case execinfrapb.WindowerSpec_LAG, execinfrapb.WindowerSpec_LEAD:
+			// The offset and the default value are optional.
+			numArgs = 1 + rng.Intn(3)
+		case execinfrapb.WindowerSpec_NTH_VALUE:
+			numArgs = 2
+		}
+	}
+	argsIdxs := make([]uint32, numArgs)
+	for i := range argsIdxs {
+		argsIdxs[i] = uint32(rng.Intn(nCols))
+	}
+	return argsIdxs
+}
+
 // generateRandomSupportedTypes generates nCols random types that are supported
 // by the vectorized engine.
 func generateRandomSupportedTypes(rng *rand.Rand, nCols int) []*types.T {
@@ -468,8 +468,9 @@ func checkSupportForPlanNode(node planNode) (distRecommendation, error) {
 		// previous behavior we continue to ignore the soft limits for now.
 		// TODO(yuzefovich): pay attention to the soft limits.
 		rec := canDistribute
-		// Check if we are doing a full scan.
-		if n.isFull {
+		// Check if we are doing a full scan. A skip scan only reads a few rows
+		// from each distinct prefix, so it is not worth distributing.
+		if n.isFull && n.skipScanPrefixLen == 0 {
 			rec = rec.compose(shouldDistribute)
 		}
 		return rec, nil
@@ -487,6 +488,12 @@ func checkSupportForPlanNode(node planNode) (distRecommendation, error) {
 		return canDistribute, nil
 
 	case *unionNode:
+		if n.hardLimit != 0 {
+			// A UNION ALL with a hard limit is used for locality optimized search,
+			// which is planned locally so that the left input can be read before
+			// the right input.
+			return cannotDistribute, nil
+		}
 		recLeft, err := checkSupportForPlanNode(n.left)
 		if err != nil {
 			return cannotDistribute, err
@@ -1177,6 +1184,9 @@ func (dsp *DistSQLPlanner) createTableReaders(
 	if err != nil {
 		return nil, err
 	}
+	if n.skipScanPrefixLen > 0 && n.containsSystemColumns {
+		return nil, errors.AssertionFailedf("skip scan cannot produce system columns")
+	}
 
 	p := planCtx.NewPhysicalPlan()
 	err = dsp.planTableReaders(
@@ -1195,6 +1205,7 @@ func (dsp *DistSQLPlanner) createTableReaders(
 			cols:                  n.cols,
 			colsToTableOrdinalMap: scanNodeToTableOrdinalMap,
 			containsSystemColumns: n.containsSystemColumns,
+			skipScanPrefixLen:     n.skipScanPrefixLen,
 		},
 	)
 	return p, err
@@ -1216,6 +1227,9 @@ type tableReaderPlanningInfo struct {
 	cols                  []*descpb.ColumnDescriptor
 	colsToTableOrdinalMap []int
 	containsSystemColumns bool
+	// skipScanPrefixLen, if non-zero, indicates that index skip table readers
+	// should be planned instead of table readers. See scanNode.
+	skipScanPrefixLen int
 }
 
 func (dsp *DistSQLPlanner) planTableReaders(
@@ -1274,7 +1288,20 @@ func (dsp *DistSQLPlanner) planTableReaders(
 		}
 
 		corePlacement[i].NodeID = sp.Node
-		corePlacement[i].Core.TableReader = tr
+		if info.skipScanPrefixLen > 0 {
+			corePlacement[i].Core.IndexSkipTableReader = &execinfrapb.IndexSkipTableReaderSpec{
+				Table:             tr.Table,
+				IndexIdx:          tr.IndexIdx,
+				Spans:             tr.Spans,
+				Visibility:        tr.Visibility,
+				Reverse:           tr.Reverse,
+				LockingStrength:   tr.LockingStrength,
+				LockingWaitPolicy: tr.LockingWaitPolicy,
+				KeyPrefixLen:      uint32(info.skipScanPrefixLen),
+			}
+		} else {
+			corePlacement[i].Core.TableReader = tr
+		}
 	}
 
 	returnMutations := info.scanVisibility == execinfra.ScanVisibilityPublicAndNotPublic
@@ -3315,6 +3342,17 @@ func (dsp *DistSQLPlanner) createPlanForSetOp(
 				Distinct: &execinfrapb.DistinctSpec{DistinctColumns: streamCols},
 			}
 			p.AddSingleGroupStage(dsp.gatewayNodeID, distinctSpec, execinfrapb.PostProcessSpec{}, resultTypes)
+		} else if n.hardLimit != 0 {
+			// A UNION ALL with a hard limit is a locality optimized search. The
+			// streams are merged serially on the gateway so that the right
+			// (remote) input is only read if the left (local) input does not
+			// produce enough rows to reach the limit. Such a plan is never
+			// distributed (see checkSupportForPlanNode), so all the streams are
+			// already on the gateway.
+			p.EnsureSingleStreamOnGatewaySerially()
+			if err := p.AddLimit(int64(n.hardLimit), 0 /* offset */, planCtx); err != nil {
+				return nil, err
+			}
 		} else {
 			// With UNION ALL, we can end up with multiple streams on the same node.
 			// We don't want to have unnecessary routers and cross-node streams, so
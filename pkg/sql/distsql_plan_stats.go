@@ -22,6 +22,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/span"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
@@ -83,12 +88,19 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 	for i, c := range scan.cols {
 		colIdxMap.Set(c.ID, i)
 	}
-	sb := span.MakeBuilder(planCtx.EvalContext(), planCtx.ExtendedEvalCtx.Codec, desc, scan.index)
-	scan.spans, err = sb.UnconstrainedSpans()
-	if err != nil {
-		return nil, err
+	var partialPredicate string
+	if details.UsingExtremes {
+		if partialPredicate, err = dsp.constrainPartialStatsScan(planCtx, &scan, reqStats); err != nil {
+			return nil, err
+		}
+	} else {
+		sb := span.MakeBuilder(planCtx.EvalContext(), planCtx.ExtendedEvalCtx.Codec, desc, scan.index)
+		scan.spans, err = sb.UnconstrainedSpans()
+		if err != nil {
+			return nil, err
+		}
+		scan.isFull = true
 	}
-	scan.isFull = true
 
 	p, err := dsp.createTableReaders(planCtx, &scan)
 	if err != nil {
@@ -113,6 +125,7 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 			HistogramMaxBuckets: uint32(s.histogramMaxBuckets),
 			Columns:             make([]uint32, len(s.columns)),
 			StatName:            s.name,
+			PartialPredicate:    partialPredicate,
 		}
 		for i, colID := range s.columns {
 			colIdx, ok := colIdxMap.Get(colID)
@@ -192,14 +205,20 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 	}
 
 	var rowsExpected uint64
-	if len(tableStats) > 0 {
+	for _, stat := range tableStats {
+		// Partial and forecast statistics do not measure the number of rows in
+		// the table.
+		if stat.IsPartial() || stat.IsForecast() {
+			continue
+		}
 		overhead := stats.AutomaticStatisticsFractionStaleRows.Get(&dsp.st.SV)
 		// Convert to a signed integer first to make the linter happy.
 		rowsExpected = uint64(int64(
 			// The total expected number of rows is the same number that was measured
 			// most recently, plus some overhead for possible insertions.
-			float64(tableStats[0].RowCount) * (1 + overhead),
+			float64(stat.RowCount) * (1 + overhead),
 		))
+		break
 	}
 
 	var jobID int64
@@ -233,6 +252,97 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 	return p, nil
 }
 
+// constrainPartialStatsScan constrains the scan for CREATE STATISTICS ... USING
+// EXTREMES to the rows with values of the requested column below the lowest
+// bound or above the highest bound of the histogram of the most recent full
+// statistic on the column. It returns the predicate satisfied by the scanned
+// rows, which is stored with the partial statistic.
+func (dsp *DistSQLPlanner) constrainPartialStatsScan(
+	planCtx *PlanningCtx, scan *scanNode, reqStats []requestedStat,
+) (string, error) {
+	if len(reqStats) != 1 || len(reqStats[0].columns) != 1 || reqStats[0].inverted {
+		return "", errors.AssertionFailedf("USING EXTREMES requires a single column statistic")
+	}
+	colID := reqStats[0].columns[0]
+	col, err := scan.desc.FindColumnByID(colID)
+	if err != nil {
+		return "", err
+	}
+	index := findExtremesIndex(scan.desc, colID)
+	if index == nil {
+		return "", pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"table %s does not have an index with column %s as its first key column",
+			scan.desc.Name, col.Name,
+		)
+	}
+
+	// Find the bounds of the histogram of the most recent full statistic.
+	tableStats, err := planCtx.ExtendedEvalCtx.ExecCfg.TableStatsCache.GetTableStats(planCtx.ctx, scan.desc.ID)
+	if err != nil {
+		return "", err
+	}
+	var lo, hi tree.Datum
+	for _, stat := range tableStats {
+		if len(stat.ColumnIDs) != 1 || stat.ColumnIDs[0] != colID ||
+			stat.IsPartial() || stat.IsMerged() || stat.IsForecast() {
+			continue
+		}
+		for i := range stat.Histogram {
+			if stat.Histogram[i].UpperBound == tree.DNull {
+				continue
+			}
+			if lo == nil {
+				lo = stat.Histogram[i].UpperBound
+			}
+			hi = stat.Histogram[i].UpperBound
+		}
+		if lo != nil {
+			break
+		}
+	}
+	if lo == nil {
+		return "", pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"column %s does not have a full statistic with a histogram to extend USING EXTREMES",
+			col.Name,
+		)
+	}
+
+	// Scan the values below lo and above hi, excluding NULLs. The spans must be
+	// in the order of the index, which sorts NULLs last if it is descending.
+	var below, above constraint.Span
+	var spans constraint.Spans
+	descending := index.ColumnDirections[0] == descpb.IndexDescriptor_DESC
+	if descending {
+		above.Init(constraint.EmptyKey, constraint.IncludeBoundary, constraint.MakeKey(hi), constraint.ExcludeBoundary)
+		below.Init(constraint.MakeKey(lo), constraint.ExcludeBoundary, constraint.MakeKey(tree.DNull), constraint.ExcludeBoundary)
+		spans.Append(&above)
+		spans.Append(&below)
+	} else {
+		below.Init(constraint.MakeKey(tree.DNull), constraint.ExcludeBoundary, constraint.MakeKey(lo), constraint.ExcludeBoundary)
+		above.Init(constraint.MakeKey(hi), constraint.ExcludeBoundary, constraint.EmptyKey, constraint.IncludeBoundary)
+		spans.Append(&below)
+		spans.Append(&above)
+	}
+	var cols constraint.Columns
+	cols.InitSingle(opt.MakeOrderingColumn(opt.ColumnID(1), descending))
+	keyCtx := constraint.MakeKeyContext(&cols, planCtx.EvalContext())
+	var c constraint.Constraint
+	c.Init(&keyCtx, &spans)
+
+	scan.index = index
+	sb := span.MakeBuilder(planCtx.EvalContext(), planCtx.ExtendedEvalCtx.Codec, scan.desc, index)
+	if scan.spans, err = sb.SpansFromConstraint(&c, exec.TableColumnOrdinalSet{}, false /* forDelete */); err != nil {
+		return "", err
+	}
+
+	colItem := &tree.ColumnItem{ColumnName: tree.Name(col.Name)}
+	predicate := tree.OrExpr{
+		Left:  &tree.ComparisonExpr{Operator: tree.LT, Left: colItem, Right: lo},
+		Right: &tree.ComparisonExpr{Operator: tree.GT, Left: colItem, Right: hi},
+	}
+	return tree.AsStringWithFlags(&predicate, tree.FmtParsable), nil
+}
+
 func (dsp *DistSQLPlanner) createPlanForCreateStats(
 	planCtx *PlanningCtx, job *jobs.Job,
 ) (*PhysicalPlan, error) {
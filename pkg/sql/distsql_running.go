@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/colflow"
+	"github.com/cockroachdb/cockroach/pkg/sql/contention"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsql"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
@@ -347,6 +348,9 @@ func (dsp *DistSQLPlanner) Run(
 
 	recv.outputTypes = plan.GetResultTypes()
 	recv.contendedQueryMetric = dsp.distSQLSrv.Metrics.ContendedQueriesCount
+	if evalCtx.ExecCfg != nil {
+		recv.contentionRegistry = evalCtx.ExecCfg.ContentionRegistry
+	}
 
 	vectorizedThresholdMet := plan.MaxEstimatedRowCount >= evalCtx.SessionData.VectorizeRowCountThreshold
 
@@ -473,6 +477,10 @@ type DistSQLReceiver struct {
 	// contendedQueryMetric is a Counter that is incremented at most once if the
 	// query produces at least one contention event.
 	contendedQueryMetric *metric.Counter
+
+	// contentionRegistry is a Registry that all contention events produced by
+	// the query are added to. Nil if the contention events are not recorded.
+	contentionRegistry *contention.Registry
 }
 
 // rowResultWriter is a subset of CommandResult to be used with the
@@ -683,6 +691,12 @@ func (r *DistSQLReceiver) Push(
 			r.contendedQueryMetric.Inc(1)
 			r.contendedQueryMetric = nil
 		}
+		for _, ev := range meta.ContentionEvents {
+			r.stats.contentionTime += ev.Duration
+			if r.contentionRegistry != nil {
+				r.contentionRegistry.AddContentionEvent(ev)
+			}
+		}
 		// Release the meta object. It is unsafe for use after this call.
 		meta.Release()
 		return r.status
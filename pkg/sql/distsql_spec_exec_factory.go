@@ -168,6 +168,10 @@ func (e *distSQLSpecExecFactory) ConstructScan(
 		)
 	}
 
+	if params.SkipScanPrefixLen != 0 {
+		return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: skip scan")
+	}
+
 	// Although we don't yet recommend distributing plans where soft limits
 	// propagate to scan nodes because we don't have infrastructure to only
 	// plan for a few ranges at a time, the propagation of the soft limits
@@ -585,7 +589,7 @@ func (e *distSQLSpecExecFactory) ConstructDistinct(
 }
 
 func (e *distSQLSpecExecFactory) ConstructSetOp(
-	typ tree.UnionType, all bool, left, right exec.Node,
+	typ tree.UnionType, all bool, left, right exec.Node, hardLimit uint64,
 ) (exec.Node, error) {
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: set op")
 }
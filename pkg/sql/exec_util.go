@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/security"
@@ -46,6 +47,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/hydratedtables"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/contention"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsql"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
@@ -63,6 +65,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmthints"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
@@ -214,6 +217,13 @@ var zigzagJoinClusterMode = settings.RegisterBoolSetting(
 	true,
 )
 
+var indexRecommendationsClusterMode = settings.RegisterPublicBoolSetting(
+	"sql.defaults.index_recommendations.enabled",
+	"default value for index_recommendations_enabled session setting; "+
+		"when set, EXPLAIN shows indexes that would make the statement cheaper",
+	true,
+)
+
 var optDrivenFKCascadesClusterLimit = settings.RegisterNonNegativeIntSetting(
 	"sql.defaults.foreign_key_cascades_limit",
 	"default value for foreign_key_cascades_limit session setting; limits the number of cascading operations that run as part of a single query",
@@ -725,6 +735,14 @@ type ExecutorConfig struct {
 	// StmtDiagnosticsRecorder deals with recording statement diagnostics.
 	StmtDiagnosticsRecorder *stmtdiagnostics.Registry
 
+	// StatementHintsCache holds the statement hints that the optimizer applies
+	// to statements with matching fingerprints.
+	StatementHintsCache *stmthints.Cache
+
+	// ContentionRegistry aggregates the contention events observed by the
+	// statements executed on this node.
+	ContentionRegistry *contention.Registry
+
 	ExternalIODirConfig base.ExternalIODirConfig
 
 	// HydratedTables is a node-level cache of table descriptors which utilize
@@ -733,6 +751,10 @@ type ExecutorConfig struct {
 
 	GCJobNotifier *gcjobnotifier.Notifier
 
+	// TenantUsageServer is used to configure the token buckets of tenants. It
+	// is only set for the system tenant.
+	TenantUsageServer multitenant.TenantUsageServer
+
 	// VersionUpgradeHook is called after validating a `SET CLUSTER SETTING
 	// version` but before executing it. It can carry out arbitrary migrations
 	// that allow us to eventually remove legacy code.
@@ -928,6 +950,12 @@ func getPlanDistribution(
 		return physicalplan.LocalPlan
 	}
 
+	// Bounded staleness reads negotiate the transaction's timestamp through
+	// the root transaction on the gateway, so they are never distributed.
+	if asOf := p.EvalContext().AsOfSystemTime; asOf != nil && asOf.BoundedStaleness {
+		return physicalplan.LocalPlan
+	}
+
 	if _, singleTenant := nodeID.OptionalNodeID(); !singleTenant {
 		return physicalplan.LocalPlan
 	}
@@ -1051,6 +1079,8 @@ func checkResultType(typ *types.T) error {
 	case types.TimestampTZFamily:
 	case types.IntervalFamily:
 	case types.JsonFamily:
+	case types.TSQueryFamily:
+	case types.TSVectorFamily:
 	case types.UuidFamily:
 	case types.INetFamily:
 	case types.OidFamily:
@@ -1080,15 +1110,29 @@ func checkResultType(typ *types.T) error {
 func (p *planner) EvalAsOfTimestamp(
 	ctx context.Context, asOf tree.AsOfClause,
 ) (_ hlc.Timestamp, err error) {
-	ts, err := tree.EvalAsOfTimestamp(ctx, asOf, &p.semaCtx, p.EvalContext())
+	asOfSystemTime, err := p.evalAsOfSystemTime(ctx, asOf)
 	if err != nil {
 		return hlc.Timestamp{}, err
 	}
+	return asOfSystemTime.Timestamp, nil
+}
+
+// evalAsOfSystemTime is like EvalAsOfTimestamp, but it returns the full result
+// of the evaluation of the AS OF SYSTEM TIME clause, which may describe a
+// bounded staleness read if allowed by the provided options.
+func (p *planner) evalAsOfSystemTime(
+	ctx context.Context, asOf tree.AsOfClause, opts ...tree.EvalAsOfTimestampOption,
+) (tree.AsOfSystemTime, error) {
+	asOfSystemTime, err := tree.EvalAsOfTimestamp(ctx, asOf, &p.semaCtx, p.EvalContext(), opts...)
+	if err != nil {
+		return tree.AsOfSystemTime{}, err
+	}
+	ts := asOfSystemTime.Timestamp
 	if now := p.execCfg.Clock.Now(); now.Less(ts) {
-		return hlc.Timestamp{}, errors.Errorf(
+		return tree.AsOfSystemTime{}, errors.Errorf(
 			"AS OF SYSTEM TIME: cannot specify timestamp in the future (%s > %s)", ts, now)
 	}
-	return ts, nil
+	return asOfSystemTime, nil
 }
 
 // ParseHLC parses a string representation of an `hlc.Timestamp`.
@@ -1114,11 +1158,15 @@ func ParseHLC(s string) (hlc.Timestamp, error) {
 
 // isAsOf analyzes a statement to bypass the logic in newPlan(), since
 // that requires the transaction to be started already. If the returned
-// timestamp is not nil, it is the timestamp to which a transaction
-// should be set. The statements that will be checked are Select,
-// ShowTrace (of a Select statement), Scrub, Export, and CreateStats.
-func (p *planner) isAsOf(ctx context.Context, stmt tree.Statement) (*hlc.Timestamp, error) {
+// AsOfSystemTime is not nil, it describes the timestamp to which a
+// transaction should be set, or the bounds of a bounded staleness read
+// through which the transaction's timestamp is to be negotiated. The
+// statements that will be checked are Select, ShowTrace (of a Select
+// statement), Scrub, Export, and CreateStats. Bounded staleness is only
+// permitted for Select statements.
+func (p *planner) isAsOf(ctx context.Context, stmt tree.Statement) (*tree.AsOfSystemTime, error) {
 	var asOf tree.AsOfClause
+	var opts []tree.EvalAsOfTimestampOption
 	switch s := stmt.(type) {
 	case *tree.Select:
 		selStmt := s.Select
@@ -1137,13 +1185,22 @@ func (p *planner) isAsOf(ctx context.Context, stmt tree.Statement) (*hlc.Timesta
 		}
 
 		asOf = sc.From.AsOf
+		opts = append(opts, tree.EvalAsOfTimestampOptionAllowBoundedStaleness)
 	case *tree.Scrub:
 		if s.AsOf.Expr == nil {
 			return nil, nil
 		}
 		asOf = s.AsOf
 	case *tree.Export:
-		return p.isAsOf(ctx, s.Query)
+		asOfSystemTime, err := p.isAsOf(ctx, s.Query)
+		if err != nil {
+			return nil, err
+		}
+		if asOfSystemTime != nil && asOfSystemTime.BoundedStaleness {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"AS OF SYSTEM TIME: cannot use bounded staleness with EXPORT")
+		}
+		return asOfSystemTime, nil
 	case *tree.CreateStats:
 		if s.Options.AsOf.Expr == nil {
 			return nil, nil
@@ -1154,8 +1211,8 @@ func (p *planner) isAsOf(ctx context.Context, stmt tree.Statement) (*hlc.Timesta
 	default:
 		return nil, nil
 	}
-	ts, err := p.EvalAsOfTimestamp(ctx, asOf)
-	return &ts, err
+	asOfSystemTime, err := p.evalAsOfSystemTime(ctx, asOf, opts...)
+	return &asOfSystemTime, err
 }
 
 // isSavepoint returns true if ast is a SAVEPOINT statement.
@@ -2038,6 +2095,18 @@ func (m *sessionDataMutator) SetZigzagJoinEnabled(val bool) {
 	m.data.ZigzagJoinEnabled = val
 }
 
+func (m *sessionDataMutator) SetIndexRecommendationsEnabled(val bool) {
+	m.data.IndexRecommendationsEnabled = val
+}
+
+func (m *sessionDataMutator) SetTrigramSimilarityThreshold(val float64) {
+	m.data.TrigramSimilarityThreshold = val
+}
+
+func (m *sessionDataMutator) SetDefaultTextSearchConfig(val string) {
+	m.data.DefaultTextSearchConfig = val
+}
+
 func (m *sessionDataMutator) SetExperimentalDistSQLPlanning(
 	val sessiondata.ExperimentalDistSQLPlanningMode,
 ) {
@@ -2201,11 +2270,14 @@ func newSQLStatsCollector(
 }
 
 // recordStatement records stats for one statement. samplePlanDescription can
-// be nil, as these are only sampled periodically per unique fingerprint. It
-// returns the statement ID of the recorded statement.
+// be nil, as these are only sampled periodically per unique fingerprint;
+// indexRecommendations are recorded along with the sampled plan. It returns
+// the statement ID of the recorded statement.
 func (s *sqlStatsCollector) recordStatement(
 	stmt *Statement,
 	samplePlanDescription *roachpb.ExplainTreePlanNode,
+	indexRecommendations []string,
+	planGist string,
 	distSQLUsed bool,
 	vectorized bool,
 	implicitTxn bool,
@@ -2216,7 +2288,7 @@ func (s *sqlStatsCollector) recordStatement(
 	stats topLevelQueryStats,
 ) roachpb.StmtID {
 	return s.appStats.recordStatement(
-		stmt, samplePlanDescription, distSQLUsed, vectorized, implicitTxn,
+		stmt, samplePlanDescription, indexRecommendations, planGist, distSQLUsed, vectorized, implicitTxn,
 		automaticRetryCount, numRows, err, parseLat, planLat, runLat, svcLat,
 		ovhLat, stats,
 	)
@@ -2234,9 +2306,13 @@ func (s *sqlStatsCollector) recordTransaction(
 	retryLat time.Duration,
 	commitLat time.Duration,
 	numRows int,
+	contentionTime time.Duration,
 ) {
 	s.appStats.recordTransactionCounts(txnTimeSec, ev, implicit)
-	s.appStats.recordTransaction(key, int64(retryCount), statementIDs, serviceLat, retryLat, commitLat, numRows)
+	s.appStats.recordTransaction(
+		key, int64(retryCount), statementIDs, serviceLat, retryLat, commitLat,
+		numRows, contentionTime,
+	)
 }
 
 func (s *sqlStatsCollector) reset(sqlStats *sqlStats, appStats *appStats, phaseTimes *phaseTimes) {
@@ -39,7 +39,7 @@ import "github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 //
 // ATTENTION: When updating these fields, add a brief description of what
 // changed to the version history below.
-const Version execinfrapb.DistSQLVersion = 43
+const Version execinfrapb.DistSQLVersion = 44
 
 // MinAcceptedVersion is the oldest version that the server is compatible with.
 // A server will not accept flows with older versions.
@@ -51,6 +51,10 @@ const MinAcceptedVersion execinfrapb.DistSQLVersion = 43
 
 Please add new entries at the top.
 
+- Version: 44 (MinAcceptedVersion: 43)
+  - A new processor core IndexSkipTableReader is added for skip scans, and a
+    new field KeyPrefixLen is added to IndexSkipTableReaderSpec.
+
 - Version: 43 (MinAcceptedVersion: 43)
 	- Filter was removed from PostProcessSpec and a new Filterer processor was
 	  added.
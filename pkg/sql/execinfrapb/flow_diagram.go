@@ -148,27 +148,44 @@ func (tr *TableReaderSpec) summary() (string, []string) {
 	details := []string{indexDetail(&tr.Table, tr.IndexIdx)}
 
 	if len(tr.Spans) > 0 {
-		tbl := tabledesc.NewImmutable(tr.Table)
-		// only show the first span
-		idx, _, _ := tbl.FindIndexByIndexIdx(int(tr.IndexIdx))
-		valDirs := catalogkeys.IndexKeyValDirs(idx)
+		details = append(details, spansDetail(&tr.Table, tr.IndexIdx, tr.Spans))
+	}
 
-		var spanStr strings.Builder
-		spanStr.WriteString("Spans: ")
-		spanStr.WriteString(catalogkeys.PrettySpan(valDirs, tr.Spans[0].Span, 2))
+	return "TableReader", details
+}
 
-		if len(tr.Spans) > 1 {
-			spanStr.WriteString(fmt.Sprintf(" and %d other", len(tr.Spans)-1))
-		}
+// summary implements the diagramCellType interface.
+func (tr *IndexSkipTableReaderSpec) summary() (string, []string) {
+	details := []string{indexDetail(&tr.Table, tr.IndexIdx)}
 
-		if len(tr.Spans) > 2 {
-			spanStr.WriteString("s") // pluralize the 'other'
-		}
+	if len(tr.Spans) > 0 {
+		details = append(details, spansDetail(&tr.Table, tr.IndexIdx, tr.Spans))
+	}
+	details = append(details, fmt.Sprintf("Key prefix: %d", tr.KeyPrefixLen))
 
-		details = append(details, spanStr.String())
+	return "IndexSkipTableReader", details
+}
+
+// spansDetail returns a description of the given table reader spans, showing
+// only the first span.
+func spansDetail(desc *descpb.TableDescriptor, indexIdx uint32, spans []TableReaderSpan) string {
+	tbl := tabledesc.NewImmutable(*desc)
+	idx, _, _ := tbl.FindIndexByIndexIdx(int(indexIdx))
+	valDirs := catalogkeys.IndexKeyValDirs(idx)
+
+	var spanStr strings.Builder
+	spanStr.WriteString("Spans: ")
+	spanStr.WriteString(catalogkeys.PrettySpan(valDirs, spans[0].Span, 2))
+
+	if len(spans) > 1 {
+		spanStr.WriteString(fmt.Sprintf(" and %d other", len(spans)-1))
 	}
 
-	return "TableReader", details
+	if len(spans) > 2 {
+		spanStr.WriteString("s") // pluralize the 'other'
+	}
+
+	return spanStr.String()
 }
 
 // summary implements the diagramCellType interface.
@@ -405,6 +422,8 @@ func (is *InputSyncSpec) summary(showTypes bool) (string, []string) {
 		return "unordered", typs
 	case InputSyncSpec_ORDERED:
 		return "ordered", append(typs, is.Ordering.diagramString())
+	case InputSyncSpec_SERIAL_UNORDERED:
+		return "serial unordered", typs
 	default:
 		return "unknown", []string{}
 	}
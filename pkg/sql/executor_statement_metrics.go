@@ -197,6 +197,8 @@ func (ex *connExecutor) recordStatementSummary(
 
 	stmtID := ex.statsCollector.recordStatement(
 		stmt, planner.instrumentation.PlanForStats(ctx),
+		planner.instrumentation.IndexRecommendationsForStats(),
+		planner.instrumentation.PlanGistForStats(),
 		flags.IsDistributed(), flags.IsSet(planFlagVectorized),
 		flags.IsSet(planFlagImplicitTxn), automaticRetryCount, rowsAffected, err,
 		parseLat, planLat, runLat, svcLat, execOverhead, stats,
@@ -219,6 +221,7 @@ func (ex *connExecutor) recordStatementSummary(
 		ex.extraTxnState.transactionStatementsHash.Add(uint64(stmtID))
 	}
 	ex.extraTxnState.numRows += rowsAffected
+	ex.extraTxnState.contentionTime += stats.contentionTime
 
 	if log.V(2) {
 		// ages since significant epochs
@@ -12,6 +12,8 @@ package sql
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
@@ -22,9 +24,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/explain"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/indexrec"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmthints"
 )
 
 // explainPlanNode implements EXPLAIN (PLAN); it produces the output of
@@ -34,7 +38,12 @@ type explainPlanNode struct {
 
 	flags explain.Flags
 	plan  *explain.Plan
-	run   explainPlanNodeRun
+	// hints are the statement hints that were applied to the plan, if any.
+	hints *stmthints.Hints
+	// indexRecommendations are the indexes that would make the statement
+	// cheaper, if any.
+	indexRecommendations []indexrec.Recommendation
+	run                  explainPlanNodeRun
 }
 
 type explainPlanNodeRun struct {
@@ -46,11 +55,14 @@ func (e *explainPlanNode) startExec(params runParams) error {
 	distribution, willVectorize := explainGetDistributedAndVectorized(params, realPlan)
 
 	ob := explain.NewOutputBuilder(e.flags)
-	if err := emitExplain(ob, params.EvalContext(), params.p.ExecCfg().Codec, e.plan, distribution, willVectorize); err != nil {
+	if err := emitExplain(
+		ob, params.EvalContext(), params.p.ExecCfg().Codec, e.plan, distribution, willVectorize, e.hints,
+	); err != nil {
 		return err
 	}
 	v := params.p.newContainerValuesNode(colinfo.ExplainPlanColumns, 0)
 	rows := ob.BuildStringRows()
+	rows = appendIndexRecommendationRows(rows, e.indexRecommendations)
 	datums := make([]tree.DString, len(rows))
 	for i, row := range rows {
 		datums[i] = tree.DString(row)
@@ -70,9 +82,13 @@ func emitExplain(
 	explainPlan *explain.Plan,
 	distribution physicalplan.PlanDistribution,
 	vectorized bool,
+	hints *stmthints.Hints,
 ) error {
 	ob.AddDistribution(distribution.String())
 	ob.AddVectorized(vectorized)
+	if hints != nil {
+		ob.AddStatementHints(hints.String())
+	}
 	spanFormatFn := func(table cat.Table, index cat.Index, scanParams exec.ScanParams) string {
 		var tabDesc *tabledesc.Immutable
 		var idxDesc *descpb.IndexDescriptor
@@ -105,6 +121,28 @@ func emitExplain(
 	return explain.Emit(explainPlan, ob, spanFormatFn)
 }
 
+// appendIndexRecommendationRows appends rows describing the given index
+// recommendations to the rows of an EXPLAIN. For example:
+//
+//   index recommendations: 1
+//   1. type: index creation
+//      SQL command: CREATE INDEX ON db.public.t (a) STORING (b);
+//
+func appendIndexRecommendationRows(rows []string, recs []indexrec.Recommendation) []string {
+	if len(recs) == 0 {
+		return rows
+	}
+	rows = append(rows, "", fmt.Sprintf("index recommendations: %d", len(recs)))
+	for i := range recs {
+		prefix := fmt.Sprintf("%d. ", i+1)
+		rows = append(rows,
+			prefix+"type: index creation",
+			strings.Repeat(" ", len(prefix))+"SQL command: "+recs[i].SQL()+";",
+		)
+	}
+	return rows
+}
+
 func (e *explainPlanNode) Next(params runParams) (bool, error) { return e.run.results.Next(params) }
 func (e *explainPlanNode) Values() tree.Datums                 { return e.run.results.Values() }
 
@@ -165,3 +203,8 @@ func explainGetDistributedAndVectorized(
 	}
 	return distribution, willVectorize
 }
+
+// DecodePlanGist is part of the tree.EvalPlanner interface.
+func (p *planner) DecodePlanGist(ctx context.Context, gist string) ([]string, error) {
+	return explain.DecodePlanGistToRows(ctx, gist, &p.optPlanningCtx.catalog)
+}
@@ -115,6 +115,25 @@ func (ep *DummyEvalPlanner) UnsafeDeleteNamespaceEntry(
 	return errors.WithStack(errEvalPlanner)
 }
 
+// AddStatementHint is part of the EvalPlanner interface.
+func (ep *DummyEvalPlanner) AddStatementHint(
+	ctx context.Context, fingerprint string, hintType string, hintValue string,
+) error {
+	return errors.WithStack(errEvalPlanner)
+}
+
+// RemoveStatementHints is part of the EvalPlanner interface.
+func (ep *DummyEvalPlanner) RemoveStatementHints(
+	ctx context.Context, fingerprint string,
+) (int, error) {
+	return 0, errors.WithStack(errEvalPlanner)
+}
+
+// DecodePlanGist is part of the EvalPlanner interface.
+func (ep *DummyEvalPlanner) DecodePlanGist(ctx context.Context, gist string) ([]string, error) {
+	return nil, errors.WithStack(errEvalPlanner)
+}
+
 var _ tree.EvalPlanner = &DummyEvalPlanner{}
 
 var errEvalPlanner = pgerror.New(pgcode.ScalarOperationCannotRunWithoutFullSessionContext,
@@ -245,3 +264,10 @@ func (c *DummyTenantOperator) DestroyTenant(_ context.Context, _ uint64) error {
 func (c *DummyTenantOperator) GCTenant(_ context.Context, _ uint64) error {
 	return errors.WithStack(errEvalTenant)
 }
+
+// UpdateTenantResourceLimits is part of the tree.TenantOperator interface.
+func (c *DummyTenantOperator) UpdateTenantResourceLimits(
+	_ context.Context, _ uint64, _ float64, _ float64, _ float64,
+) error {
+	return errors.WithStack(errEvalTenant)
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// IndexRecommendationStats is an index recommendation aggregated over the
+// statements for which it was made.
+type IndexRecommendationStats struct {
+	// Recommendation is the statement that creates the recommended index.
+	Recommendation string
+	// TableName is the fully qualified name of the table on which the index
+	// would be created.
+	TableName string
+	// StatementCount is the number of distinct statements for which the index
+	// was recommended.
+	StatementCount int64
+	// ExecutionCount is the total number of executions of those statements.
+	ExecutionCount int64
+	// LastRecommended is the time at which the plan of one of those statements
+	// was last sampled.
+	LastRecommended time.Time
+	// Fingerprints contains the sorted fingerprints of those statements.
+	Fingerprints []string
+}
+
+// AggregateIndexRecommendations groups the index recommendations found in the
+// given statement statistics by recommendation. The statistics of a statement
+// can be split across aggregation intervals and nodes; the recommendations of
+// a statement are the ones made when its plan was most recently sampled, and
+// its execution count is summed across all of its entries. The result is
+// sorted by recommendation.
+func AggregateIndexRecommendations(
+	stmtStats []roachpb.CollectedStatementStatistics,
+) ([]IndexRecommendationStats, error) {
+	type stmtKey struct {
+		id  roachpb.StmtID
+		app string
+	}
+	type stmtInfo struct {
+		query   string
+		count   int64
+		sampled time.Time
+		recs    []string
+	}
+	stmts := make(map[stmtKey]*stmtInfo)
+	for i := range stmtStats {
+		s := &stmtStats[i]
+		k := stmtKey{id: s.ID, app: s.Key.App}
+		info, ok := stmts[k]
+		if !ok {
+			info = &stmtInfo{query: s.Key.Query}
+			stmts[k] = info
+		}
+		info.count += s.Stats.Count
+		if sampled := s.Stats.SensitiveInfo.MostRecentPlanTimestamp; !sampled.Before(info.sampled) {
+			info.sampled = sampled
+			info.recs = s.Stats.SensitiveInfo.IndexRecommendations
+		}
+	}
+
+	recs := make(map[string]*IndexRecommendationStats)
+	for _, info := range stmts {
+		for _, rec := range info.recs {
+			r, ok := recs[rec]
+			if !ok {
+				r = &IndexRecommendationStats{Recommendation: rec}
+				recs[rec] = r
+			}
+			r.StatementCount++
+			r.ExecutionCount += info.count
+			if r.LastRecommended.Before(info.sampled) {
+				r.LastRecommended = info.sampled
+			}
+			r.Fingerprints = append(r.Fingerprints, info.query)
+		}
+	}
+
+	res := make([]IndexRecommendationStats, 0, len(recs))
+	for _, r := range recs {
+		stmt, err := parser.ParseOne(r.Recommendation)
+		if err != nil {
+			return nil, err
+		}
+		createIndex, ok := stmt.AST.(*tree.CreateIndex)
+		if !ok {
+			return nil, errors.AssertionFailedf("unexpected index recommendation: %s", r.Recommendation)
+		}
+		r.TableName = createIndex.Table.String()
+		sort.Strings(r.Fingerprints)
+		res = append(res, *r)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Recommendation < res[j].Recommendation
+	})
+	return res, nil
+}
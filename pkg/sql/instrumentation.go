@@ -21,10 +21,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/explain"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/indexrec"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmthints"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing/tracingpb"
@@ -80,9 +82,20 @@ type instrumentationHelper struct {
 	// via PlanForStats().
 	savePlanForStats bool
 
-	explainPlan  *explain.Plan
-	distribution physicalplan.PlanDistribution
-	vectorized   bool
+	// If saveIndexRecommendations is true, index recommendations will be made
+	// for the query and returned via IndexRecommendationsForStats().
+	saveIndexRecommendations bool
+
+	// If collectPlanGist is true, the gist of the plan will be collected and
+	// returned via PlanGistForStats().
+	collectPlanGist bool
+
+	explainPlan          *explain.Plan
+	statementHints       *stmthints.Hints
+	indexRecommendations []string
+	planGist             string
+	distribution   physicalplan.PlanDistribution
+	vectorized     bool
 
 	traceMetadata execNodeTraceMetadata
 }
@@ -141,6 +154,8 @@ func (ih *instrumentationHelper) Setup(
 	ih.withStatementTrace = cfg.TestingKnobs.WithStatementTrace
 
 	ih.savePlanForStats = appStats.shouldSaveLogicalPlanDescription(fingerprint, implicitTxn)
+	ih.saveIndexRecommendations = ih.savePlanForStats && sampleIndexRecommendations.Get(&cfg.Settings.SV)
+	ih.collectPlanGist = collectPlanGists.Get(&cfg.Settings.SV)
 
 	if !ih.collectBundle && ih.withStatementTrace == nil && ih.outputMode == unmodifiedOutput {
 		return ctx, false
@@ -272,9 +287,54 @@ func (ih *instrumentationHelper) ShouldBuildExplainPlan() bool {
 	return ih.collectBundle || ih.savePlanForStats || ih.outputMode == explainAnalyzePlanOutput
 }
 
-// RecordExplainPlan records the explain.Plan for this query.
-func (ih *instrumentationHelper) RecordExplainPlan(explainPlan *explain.Plan) {
+// RecordExplainPlan records the explain.Plan for this query, along with the
+// statement hints that were applied to it.
+func (ih *instrumentationHelper) RecordExplainPlan(
+	explainPlan *explain.Plan, statementHints *stmthints.Hints,
+) {
 	ih.explainPlan = explainPlan
+	ih.statementHints = statementHints
+}
+
+// ShouldSaveIndexRecommendations returns true if index recommendations should
+// be made for the query and recorded with RecordIndexRecommendations.
+func (ih *instrumentationHelper) ShouldSaveIndexRecommendations() bool {
+	return ih.saveIndexRecommendations
+}
+
+// RecordIndexRecommendations records the index recommendations for this
+// query.
+func (ih *instrumentationHelper) RecordIndexRecommendations(recs []indexrec.Recommendation) {
+	if !ih.saveIndexRecommendations {
+		return
+	}
+	ih.indexRecommendations = make([]string, len(recs))
+	for i := range recs {
+		ih.indexRecommendations[i] = recs[i].SQL()
+	}
+}
+
+// IndexRecommendationsForStats returns the index recommendations for the
+// query, as CREATE INDEX statements, if they were recorded.
+func (ih *instrumentationHelper) IndexRecommendationsForStats() []string {
+	return ih.indexRecommendations
+}
+
+// ShouldCollectPlanGist returns true if the gist of the plan should be built
+// and recorded with RecordPlanGist.
+func (ih *instrumentationHelper) ShouldCollectPlanGist() bool {
+	return ih.collectPlanGist
+}
+
+// RecordPlanGist records the gist of the plan of this query.
+func (ih *instrumentationHelper) RecordPlanGist(gist string) {
+	ih.planGist = gist
+}
+
+// PlanGistForStats returns the gist of the plan of the query, if it was
+// recorded (the empty string otherwise).
+func (ih *instrumentationHelper) PlanGistForStats() string {
+	return ih.planGist
 }
 
 // RecordPlanInfo records top-level information about the plan.
@@ -296,7 +356,7 @@ func (ih *instrumentationHelper) PlanForStats(ctx context.Context) *roachpb.Expl
 	ob := explain.NewOutputBuilder(explain.Flags{
 		HideValues: true,
 	})
-	if err := emitExplain(ob, ih.evalCtx, ih.codec, ih.explainPlan, ih.distribution, ih.vectorized); err != nil {
+	if err := emitExplain(ob, ih.evalCtx, ih.codec, ih.explainPlan, ih.distribution, ih.vectorized, ih.statementHints); err != nil {
 		log.Warningf(ctx, "unable to emit explain plan tree: %v", err)
 		return nil
 	}
@@ -314,7 +374,7 @@ func (ih *instrumentationHelper) planStringForBundle(phaseTimes *phaseTimes) str
 	})
 	ob.AddPlanningTime(phaseTimes.getPlanningLatency())
 	ob.AddExecutionTime(phaseTimes.getRunLatency())
-	if err := emitExplain(ob, ih.evalCtx, ih.codec, ih.explainPlan, ih.distribution, ih.vectorized); err != nil {
+	if err := emitExplain(ob, ih.evalCtx, ih.codec, ih.explainPlan, ih.distribution, ih.vectorized, ih.statementHints); err != nil {
 		return fmt.Sprintf("error emitting plan: %v", err)
 	}
 	return ob.BuildString()
@@ -330,7 +390,7 @@ func (ih *instrumentationHelper) planRowsForExplainAnalyze(phaseTimes *phaseTime
 	ob := explain.NewOutputBuilder(ih.explainFlags)
 	ob.AddPlanningTime(phaseTimes.getPlanningLatency())
 	ob.AddExecutionTime(phaseTimes.getRunLatency())
-	if err := emitExplain(ob, ih.evalCtx, ih.codec, ih.explainPlan, ih.distribution, ih.vectorized); err != nil {
+	if err := emitExplain(ob, ih.evalCtx, ih.codec, ih.explainPlan, ih.distribution, ih.vectorized, ih.statementHints); err != nil {
 		return []string{fmt.Sprintf("error emitting plan: %v", err)}
 	}
 	return ob.BuildStringRows()
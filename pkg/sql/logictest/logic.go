@@ -1509,7 +1509,38 @@ func (t *logicTest) setup(cfg testClusterConfig, serverArgs TestServerArgs) {
 				t.Fatal(err)
 			}
 		}
+
+		// Index recommendations are disabled by default, so that EXPLAIN output
+		// does not depend on them; tests that exercise them enable them
+		// explicitly.
+		if _, err := conn.Exec(
+			"SET CLUSTER SETTING sql.defaults.index_recommendations.enabled = false",
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Wait until all servers (and the tenant, if any) are aware of the index
+	// recommendations setting, since it determines the default for new
+	// sessions.
+	connsToCheck := connsForClusterSettingChanges[1:]
+	for i := 0; i < t.cluster.NumServers(); i++ {
+		connsToCheck = append(connsToCheck, t.cluster.ServerConn(i))
 	}
+	testutils.SucceedsSoon(t.rootT, func() error {
+		for i, conn := range connsToCheck {
+			var enabled bool
+			if err := conn.QueryRow(
+				"SHOW CLUSTER SETTING sql.defaults.index_recommendations.enabled",
+			).Scan(&enabled); err != nil {
+				t.Fatal(errors.Wrapf(err, "%d", i))
+			}
+			if enabled {
+				return errors.Errorf("connection %d is still waiting for index recommendations to be disabled", i)
+			}
+		}
+		return nil
+	})
 
 	if cfg.overrideDistSQLMode != "" {
 		_, ok := sessiondata.DistSQLExecModeFromString(cfg.overrideDistSQLMode)
@@ -189,6 +189,14 @@ type Index interface {
 	//
 	PartitionByListPrefixes() []tree.Datums
 
+	// PartitionCount returns the number of PARTITION BY LIST partitions defined
+	// on this index.
+	PartitionCount() int
+
+	// Partition returns the ith PARTITION BY LIST partition within the index
+	// definition, where i < PartitionCount.
+	Partition(i int) Partition
+
 	// InterleaveAncestorCount returns the number of interleave ancestors for this
 	// index (or zero if this is not an interleaved index). Each ancestor is an
 	// index (usually from another table) with a key that shares a prefix with
@@ -240,6 +248,29 @@ type Index interface {
 	Version() descpb.IndexDescriptorVersion
 }
 
+// Partition is an interface to a PARTITION BY LIST partition of an index. The
+// intended use is to support planning of scans or lookup joins that will use
+// locality optimized search. Locality optimized search can be used for
+// queries in which all results must come from a single partition, but the
+// optimizer doesn't know which partition; it can first search the partitions
+// with a zone in the local region, and only search the remote partitions if
+// no results are found.
+type Partition interface {
+	// Name is the name of this partition.
+	Name() string
+
+	// Zone returns the zone which constrains placement of this partition's
+	// replicas. If this partition does not have an associated zone, the returned
+	// zone is the zone of the index.
+	Zone() Zone
+
+	// PartitionByListPrefixes returns the values of this partition. It has the
+	// same format as Index.PartitionByListPrefixes; see that method for details.
+	// Note that a DEFAULT partition has no values, so an empty slice is
+	// returned.
+	PartitionByListPrefixes() []tree.Datums
+}
+
 // IndexColumn describes a single column that is part of an index definition.
 type IndexColumn struct {
 	// Column is a reference to the column returned by Table.Column, given the
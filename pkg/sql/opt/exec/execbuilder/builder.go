@@ -79,6 +79,15 @@ type Builder struct {
 	// by scans. See forUpdateLocking.
 	forceForUpdateLocking bool
 
+	// boundedStaleness is true if the statement is a bounded staleness read.
+	// Such reads negotiate their timestamp with a single range, so the plan is
+	// restricted to a single scan that returns at most one row.
+	boundedStaleness bool
+
+	// numScans is the number of scans built so far. It is only maintained for
+	// bounded staleness reads.
+	numScans int
+
 	// -- output --
 
 	// IsDDL is set to true if the statement contains DDL.
@@ -125,6 +134,7 @@ func New(
 			b.nameGen = memo.NewExprNameGenerator(evalCtx.SessionData.SaveTablesPrefix)
 		}
 		b.allowInsertFastPath = evalCtx.SessionData.InsertFastPath
+		b.boundedStaleness = evalCtx.AsOfSystemTime != nil && evalCtx.AsOfSystemTime.BoundedStaleness
 	}
 	return b
 }
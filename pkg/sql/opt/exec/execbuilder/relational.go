@@ -168,6 +168,12 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 			"cannot execute %s in a read-only transaction", b.statementTag(e))
 	}
 
+	if b.boundedStaleness {
+		if err := b.checkBoundedStaleness(e); err != nil {
+			return execPlan{}, err
+		}
+	}
+
 	// Collect usage telemetry for relational node, if appropriate.
 	if !b.disableTelemetry {
 		if c := opt.OpTelemetryCounters[e.Op()]; c != nil {
@@ -534,7 +540,7 @@ func (b *Builder) scanParams(
 	hardLimit := scan.HardLimit.RowCount()
 
 	parallelize := false
-	if hardLimit == 0 && softLimit == 0 {
+	if hardLimit == 0 && softLimit == 0 && !scan.SkipScanPrefix.IsSet() {
 		maxResults, ok := b.indexConstraintMaxResults(scan)
 		if ok && maxResults < ParallelScanResultThreshold {
 			// Don't set the flag when we have a single span which returns a single
@@ -553,7 +559,9 @@ func (b *Builder) scanParams(
 		InvertedConstraint: scan.InvertedConstraint,
 		HardLimit:          hardLimit,
 		SoftLimit:          softLimit,
-		// HardLimit.Reverse() is taken into account by ScanIsReverse.
+		SkipScanPrefixLen:  scan.SkipScanPrefix.PrefixLen(),
+		// HardLimit.Reverse() and SkipScanPrefix.Reverse() are taken into account
+		// by ScanIsReverse.
 		Reverse:           ordering.ScanIsReverse(scan, &scan.RequiredPhysical().Ordering),
 		Parallelize:       parallelize,
 		Locking:           locking,
@@ -561,6 +569,33 @@ func (b *Builder) scanParams(
 	}, outputMap, nil
 }
 
+// checkBoundedStaleness returns an error if the given relational expression
+// cannot be part of a bounded staleness read. The timestamp of such a read is
+// negotiated by the single range it touches, so the plan may contain at most
+// one scan, which must return at most one row, and no operators that perform
+// additional reads or writes.
+func (b *Builder) checkBoundedStaleness(e memo.RelExpr) error {
+	switch e.Op() {
+	case opt.ScanOp:
+		b.numScans++
+		if b.numScans > 1 || e.Relational().Cardinality.Max > 1 {
+			return errBoundedStalenessUnsupported
+		}
+	case opt.IndexJoinOp, opt.LookupJoinOp, opt.InvertedJoinOp, opt.ZigzagJoinOp:
+		return errBoundedStalenessUnsupported
+	default:
+		if opt.IsMutationOp(e) {
+			return pgerror.Newf(pgcode.ReadOnlySQLTransaction,
+				"cannot execute %s in a bounded staleness read", b.statementTag(e))
+		}
+	}
+	return nil
+}
+
+var errBoundedStalenessUnsupported = unimplemented.Newf("bounded staleness",
+	"cannot use bounded staleness for queries that may touch more than one row "+
+		"or require an index join")
+
 func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 	md := b.mem.Metadata()
 	tab := md.Table(scan.Table)
@@ -1300,11 +1335,19 @@ func (b *Builder) buildSetOp(set memo.RelExpr) (execPlan, error) {
 
 	var typ tree.UnionType
 	var all bool
+	var hardLimit uint64
 	switch set.Op() {
 	case opt.UnionOp:
 		typ, all = tree.UnionOp, false
 	case opt.UnionAllOp:
 		typ, all = tree.UnionOp, true
+	case opt.LocalityOptimizedSearchOp:
+		// A locality optimized search is executed as a UNION ALL with a hard
+		// limit equal to the maximum cardinality of the expression. The local
+		// input is executed first, and the remote input can be skipped if the
+		// limit is reached.
+		typ, all = tree.UnionOp, true
+		hardLimit = uint64(set.Relational().Cardinality.Max)
 	case opt.IntersectOp:
 		typ, all = tree.IntersectOp, false
 	case opt.IntersectAllOp:
@@ -1317,7 +1360,7 @@ func (b *Builder) buildSetOp(set memo.RelExpr) (execPlan, error) {
 		panic(errors.AssertionFailedf("invalid operator %s", log.Safe(set.Op())))
 	}
 
-	node, err := b.factory.ConstructSetOp(typ, all, left.root, right.root)
+	node, err := b.factory.ConstructSetOp(typ, all, left.root, right.root, hardLimit)
 	if err != nil {
 		return execPlan{}, err
 	}
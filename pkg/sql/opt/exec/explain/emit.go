@@ -383,6 +383,10 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 			ob.Attr("limit", a.Params.HardLimit)
 		}
 
+		if a.Params.SkipScanPrefixLen > 0 {
+			ob.Attr("skip scan prefix", a.Params.SkipScanPrefixLen)
+		}
+
 		if a.Params.Parallelize {
 			ob.VAttr("parallel", "")
 		}
@@ -668,9 +672,14 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		}
 		e.emitSpans("spans", a.Table, a.Table.Index(cat.PrimaryIndex), params)
 
+	case setOpOp:
+		a := n.args.(*setOpArgs)
+		if a.HardLimit != 0 {
+			ob.Attr("limit", a.HardLimit)
+		}
+
 	case simpleProjectOp,
 		serializingProjectOp,
-		setOpOp,
 		ordinalityOp,
 		max1RowOp,
 		explainOptOp,
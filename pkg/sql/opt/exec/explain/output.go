@@ -331,6 +331,12 @@ func (ob *OutputBuilder) AddVectorized(value bool) {
 	ob.AddTopLevelField("vectorized", valueStr)
 }
 
+// AddStatementHints adds a top-level field listing the statement hints that
+// were applied to the plan. Cannot be called while inside a node.
+func (ob *OutputBuilder) AddStatementHints(value string) {
+	ob.AddTopLevelField("statement hints", value)
+}
+
 // AddPlanningTime adds a top-level planning time field. Cannot be called
 // while inside a node.
 func (ob *OutputBuilder) AddPlanningTime(delta time.Duration) {
@@ -0,0 +1,304 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package explain
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// PlanGistFactory implements exec.Factory. It wraps another factory and
+// forwards all factory calls, while also encoding the operators of the plan,
+// along with the tables and indexes they access and their join types, into a
+// compact "plan gist". Unlike an EXPLAIN plan, a gist contains no constants,
+// expressions or columns, so it is cheap enough to build for every execution
+// of a statement, and it identifies the shape of the plan independently of
+// the values used by the statement. Gists can be turned back into an
+// EXPLAIN-like tree with DecodePlanGistToRows.
+//
+// The gist is a base64 encoding of the operators in the order in which they
+// were constructed, which is bottom-up; the children of an operator are
+// recovered when decoding from the number of input nodes that the operator
+// takes.
+type PlanGistFactory struct {
+	wrappedFactory exec.Factory
+
+	buf     bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+}
+
+var _ exec.Factory = &PlanGistFactory{}
+
+// planGistVersion is encoded at the start of every plan gist. It must be
+// incremented whenever the encoding changes, including when operators are
+// added to or removed from the exec.Factory, since operators are encoded using
+// their execOperator values.
+const planGistVersion = 1
+
+// NewPlanGistFactory creates a new plan gist factory.
+func NewPlanGistFactory(wrappedFactory exec.Factory) *PlanGistFactory {
+	f := &PlanGistFactory{wrappedFactory: wrappedFactory}
+	f.encodeUvarint(planGistVersion)
+	return f
+}
+
+// PlanGist returns the gist of the operators constructed so far.
+func (f *PlanGistFactory) PlanGist() string {
+	return base64.StdEncoding.EncodeToString(f.buf.Bytes())
+}
+
+// ConstructPlan is part of the exec.Factory interface.
+func (f *PlanGistFactory) ConstructPlan(
+	root exec.Node, subqueries []exec.Subquery, cascades []exec.Cascade, checks []exec.Node,
+) (exec.Plan, error) {
+	return f.wrappedFactory.ConstructPlan(root, subqueries, cascades, checks)
+}
+
+func (f *PlanGistFactory) encodeOperator(op execOperator) {
+	f.encodeUvarint(uint64(op))
+}
+
+func (f *PlanGistFactory) encodeByte(b byte) {
+	f.buf.WriteByte(b)
+}
+
+func (f *PlanGistFactory) encodeUvarint(v uint64) {
+	n := binary.PutUvarint(f.scratch[:], v)
+	f.buf.Write(f.scratch[:n])
+}
+
+func (f *PlanGistFactory) encodeTable(table cat.Table) {
+	f.encodeUvarint(uint64(table.ID()))
+}
+
+func (f *PlanGistFactory) encodeIndex(index cat.Index) {
+	f.encodeUvarint(uint64(index.Table().ID()))
+	f.encodeUvarint(uint64(index.ID()))
+}
+
+// gistNode is an operator decoded from a plan gist.
+type gistNode struct {
+	op        execOperator
+	joinType  descpb.JoinType
+	unionType tree.UnionType
+	// attrs are the tables and indexes used by the operator, in the order in
+	// which they are passed to the factory.
+	attrs    []gistAttr
+	children []*gistNode
+}
+
+// gistAttr is a table, or an index of a table, used by an operator.
+type gistAttr struct {
+	label    string
+	tableID  cat.StableID
+	indexID  cat.StableID
+	hasIndex bool
+}
+
+// planGistDecoder decodes the operators of a plan gist. The decoded operators
+// are kept on a stack, from which each operator pops its children.
+type planGistDecoder struct {
+	buf   []byte
+	nodes []*gistNode
+	err   error
+}
+
+func (d *planGistDecoder) decodeUvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errors.New("plan gist is truncated")
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *planGistDecoder) decodeByte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) == 0 {
+		d.err = errors.New("plan gist is truncated")
+		return 0
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b
+}
+
+func (d *planGistDecoder) decodeTable(n *gistNode, label string) {
+	tableID := cat.StableID(d.decodeUvarint())
+	n.attrs = append(n.attrs, gistAttr{label: label, tableID: tableID})
+}
+
+func (d *planGistDecoder) decodeIndex(n *gistNode, label string) {
+	tableID := cat.StableID(d.decodeUvarint())
+	indexID := cat.StableID(d.decodeUvarint())
+	n.attrs = append(n.attrs, gistAttr{
+		label: label, tableID: tableID, indexID: indexID, hasIndex: true,
+	})
+}
+
+func (d *planGistDecoder) popChildren(n *gistNode, count int) {
+	if d.err != nil {
+		return
+	}
+	if len(d.nodes) < count {
+		d.err = errors.Errorf("plan gist is missing inputs of operator %d", n.op)
+		return
+	}
+	n.children = append([]*gistNode(nil), d.nodes[len(d.nodes)-count:]...)
+	d.nodes = d.nodes[:len(d.nodes)-count]
+}
+
+// DecodePlanGistToRows decodes a plan gist produced by a PlanGistFactory and
+// returns an EXPLAIN-like representation of the plan, one row per string.
+// Tables and indexes are resolved using the given catalog; the ones that
+// cannot be resolved (for example because they were dropped since the gist
+// was produced) are shown using their IDs.
+func DecodePlanGistToRows(ctx context.Context, gist string, catalog cat.Catalog) ([]string, error) {
+	buf, err := base64.StdEncoding.DecodeString(gist)
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.InvalidParameterValue, "invalid plan gist")
+	}
+	d := planGistDecoder{buf: buf}
+	if version := d.decodeUvarint(); d.err == nil && version != planGistVersion {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"unsupported plan gist version %d", version)
+	}
+	for d.err == nil && len(d.buf) > 0 {
+		n := &gistNode{op: execOperator(d.decodeUvarint())}
+		if err := d.decodeOperatorBody(n); err != nil {
+			return nil, pgerror.Wrap(err, pgcode.InvalidParameterValue, "invalid plan gist")
+		}
+		d.nodes = append(d.nodes, n)
+	}
+	if d.err != nil {
+		return nil, pgerror.Wrap(d.err, pgcode.InvalidParameterValue, "invalid plan gist")
+	}
+
+	// The plan gist does not record which operators are the roots of
+	// subqueries, postqueries and the main query, so all of the remaining
+	// operators are shown as separate trees, in the order in which they were
+	// constructed.
+	ob := NewOutputBuilder(Flags{})
+	e := makeEmitter(ob, nil /* spanFormatFn */)
+	r := gistResolver{ctx: ctx, catalog: catalog}
+	for _, n := range d.nodes {
+		if err := r.emitNode(&e, n); err != nil {
+			return nil, err
+		}
+	}
+	return ob.BuildStringRows(), nil
+}
+
+// gistResolver emits decoded plan gist operators, resolving the tables and
+// indexes they use with a catalog.
+type gistResolver struct {
+	ctx     context.Context
+	catalog cat.Catalog
+}
+
+func (r *gistResolver) emitNode(e *emitter, n *gistNode) error {
+	// Like non-verbose EXPLAIN, we skip projections.
+	if (n.op == serializingProjectOp || n.op == simpleProjectOp) && len(n.children) == 1 {
+		return r.emitNode(e, n.children[0])
+	}
+	name, err := e.gistNodeName(n)
+	if err != nil {
+		return err
+	}
+	e.ob.EnterNode(name, nil /* columns */, nil /* ordering */)
+	for _, a := range n.attrs {
+		e.ob.Attr(a.label, r.attrString(a))
+	}
+	for _, c := range n.children {
+		if err := r.emitNode(e, c); err != nil {
+			return err
+		}
+	}
+	e.ob.LeaveNode()
+	return nil
+}
+
+// attrString returns the table (and index) of the attribute in the same
+// form used by EXPLAIN, e.g. "t@t_pkey". Tables and indexes that cannot be
+// resolved are shown using numeric references, e.g. "[53]@[1]".
+func (r *gistResolver) attrString(a gistAttr) string {
+	var table cat.Table
+	if r.catalog != nil {
+		ds, _, err := r.catalog.ResolveDataSourceByID(r.ctx, cat.Flags{}, a.tableID)
+		if err == nil {
+			table, _ = ds.(cat.Table)
+		}
+	}
+	tableName := fmt.Sprintf("[%d]", a.tableID)
+	if table != nil {
+		tableName = string(table.Name())
+	}
+	if !a.hasIndex {
+		return tableName
+	}
+	indexName := fmt.Sprintf("[%d]", a.indexID)
+	if table != nil {
+		for i, n := 0, table.DeletableIndexCount(); i < n; i++ {
+			if index := table.Index(i); index.ID() == a.indexID {
+				indexName = string(index.Name())
+				break
+			}
+		}
+	}
+	return fmt.Sprintf("%s@%s", tableName, indexName)
+}
+
+// gistNodeName returns the name of a decoded plan gist operator. It is
+// similar to nodeName, but is limited to the information in the gist.
+func (e *emitter) gistNodeName(n *gistNode) (string, error) {
+	switch n.op {
+	case scanOp:
+		return "scan", nil
+	case valuesOp:
+		return "values", nil
+	case hashJoinOp:
+		return e.joinNodeName("hash", n.joinType), nil
+	case mergeJoinOp:
+		return e.joinNodeName("merge", n.joinType), nil
+	case lookupJoinOp:
+		return e.joinNodeName("lookup", n.joinType), nil
+	case invertedJoinOp:
+		return e.joinNodeName("inverted", n.joinType), nil
+	case applyJoinOp:
+		return e.joinNodeName("apply", n.joinType), nil
+	case setOpOp:
+		return strings.ToLower(n.unionType.String()), nil
+	case opaqueOp:
+		return "opaque", nil
+	}
+	if n.op < 0 || int(n.op) >= len(nodeNames) || nodeNames[n.op] == "" {
+		return "", errors.AssertionFailedf("unhandled op %d", n.op)
+	}
+	return nodeNames[n.op], nil
+}
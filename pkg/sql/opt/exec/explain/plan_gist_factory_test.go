@@ -0,0 +1,96 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package explain
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils/testcat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlanGist tests that plan gists can be decoded into the plan that was
+// used to build them.
+func TestPlanGist(t *testing.T) {
+	ctx := context.Background()
+	catalog := testcat.New()
+	for _, ddl := range []string{
+		"CREATE TABLE t (a INT PRIMARY KEY, b INT, INDEX b_idx (b))",
+		"CREATE TABLE u (c INT PRIMARY KEY)",
+	} {
+		_, err := catalog.ExecuteDDL(ddl)
+		require.NoError(t, err)
+	}
+	tab := catalog.Table(tree.NewUnqualifiedTableName("t"))
+	tabU := catalog.Table(tree.NewUnqualifiedTableName("u"))
+
+	f := NewPlanGistFactory(exec.StubFactory{})
+	left, err := f.ConstructScan(tab, tab.Index(1), exec.ScanParams{}, nil /* reqOrdering */)
+	require.NoError(t, err)
+	right, err := f.ConstructScan(tabU, tabU.Index(0), exec.ScanParams{}, nil /* reqOrdering */)
+	require.NoError(t, err)
+	join, err := f.ConstructHashJoin(
+		descpb.LeftOuterJoin, left, right,
+		[]exec.NodeColumnOrdinal{1}, []exec.NodeColumnOrdinal{0},
+		false /* leftEqColsAreKey */, true /* rightEqColsAreKey */, nil, /* extraOnCond */
+	)
+	require.NoError(t, err)
+	idxJoin, err := f.ConstructIndexJoin(
+		join, tab, nil /* keyCols */, exec.TableColumnOrdinalSet{}, nil, /* reqOrdering */
+	)
+	require.NoError(t, err)
+	_, err = f.ConstructSort(idxJoin, nil /* ordering */, 0 /* alreadyOrderedPrefix */)
+	require.NoError(t, err)
+	gist := f.PlanGist()
+
+	rows, err := DecodePlanGistToRows(ctx, gist, catalog)
+	require.NoError(t, err)
+	require.Equal(t, strings.TrimSpace(`
+• sort
+│
+└── • index join
+    │ table: t
+    │
+    └── • hash join (left outer)
+        │
+        ├── • scan
+        │     table: t@b_idx
+        │
+        └── • scan
+              table: u@primary
+`), strings.Join(rows, "\n"))
+
+	// Tables and indexes that cannot be resolved are shown using their IDs.
+	rows, err = DecodePlanGistToRows(ctx, gist, nil /* catalog */)
+	require.NoError(t, err)
+	require.Contains(t, rows, fmt.Sprintf("              table: [%d]@[%d]", tabU.ID(), tabU.Index(0).ID()))
+
+	for _, invalid := range []string{
+		// Not base64.
+		"!",
+		// Unsupported version.
+		base64.StdEncoding.EncodeToString([]byte{planGistVersion + 1}),
+		// Scan without an index.
+		base64.StdEncoding.EncodeToString([]byte{planGistVersion, byte(scanOp)}),
+		// Filter without an input.
+		base64.StdEncoding.EncodeToString([]byte{planGistVersion, byte(filterOp)}),
+	} {
+		_, err = DecodePlanGistToRows(ctx, invalid, catalog)
+		require.Error(t, err)
+	}
+}
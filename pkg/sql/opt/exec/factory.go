@@ -51,6 +51,11 @@ type ScanParams struct {
 	// assumption that only SoftLimit rows will be needed.
 	SoftLimit int64
 
+	// If non-zero, the scan is a skip scan: for each distinct value of the first
+	// SkipScanPrefixLen index key columns, it returns only the first row (in the
+	// scan direction) and then seeks to the next distinct value.
+	SkipScanPrefixLen int
+
 	Reverse bool
 
 	// If true, the scan will scan all spans in parallel. It should only be set to
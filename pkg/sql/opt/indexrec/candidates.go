@@ -0,0 +1,389 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package indexrec
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// maxCandidatesPerTable limits the number of hypothetical indexes that are
+// added to a single table, so that the re-optimization of a statement that
+// filters on many columns stays cheap.
+const maxCandidatesPerTable = 8
+
+// Column is a column of a candidate index, identified by its ordinal in the
+// table.
+type Column struct {
+	Ordinal    int
+	Descending bool
+}
+
+// Candidate is an index that does not exist, but that might allow the
+// optimizer to find a cheaper plan for a query. Candidates are added to the
+// catalog as hypothetical indexes (see HypotheticalIndex) before the query is
+// optimized again.
+type Candidate struct {
+	// Table is the table on which the index would be created.
+	Table cat.Table
+
+	// Columns are the explicit key columns of the index.
+	Columns []Column
+}
+
+// String returns a human-readable representation of the candidate, of the
+// form "tab (a, b DESC)".
+func (c *Candidate) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(string(c.Table.Name()))
+	buf.WriteString(" (")
+	for i, col := range c.Columns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(string(c.Table.Column(col.Ordinal).ColName()))
+		if col.Descending {
+			buf.WriteString(" DESC")
+		}
+	}
+	buf.WriteString(")")
+	return buf.String()
+}
+
+// key returns a string that uniquely identifies the candidate within its
+// table.
+func (c *Candidate) key() string {
+	var buf bytes.Buffer
+	for _, col := range c.Columns {
+		buf.WriteString(strconv.Itoa(col.Ordinal))
+		if col.Descending {
+			buf.WriteByte('-')
+		} else {
+			buf.WriteByte('+')
+		}
+	}
+	return buf.String()
+}
+
+// FindCandidates walks the given optimized expression tree and returns
+// candidate indexes for the tables it reads from. Candidates are derived from:
+//
+//   - filter conditions that constrain table columns; a candidate is generated
+//     for each constrained column, for all columns constrained to a single
+//     value, and for those columns followed by each column constrained to a
+//     range,
+//   - the equality columns of joins,
+//   - orderings that are provided by a sort.
+//
+// Candidates that are already served by an existing index (because their
+// columns are a prefix of the index key) are not returned. The result is
+// grouped by table, in the order in which the tables were added to the
+// metadata.
+func FindCandidates(root opt.Expr, md *opt.Metadata, evalCtx *tree.EvalContext) []Candidate {
+	var f candidateFinder
+	f.init(md, evalCtx)
+	f.walk(root)
+	return f.build()
+}
+
+// tableInfo accumulates the columns of a table that are referenced in ways
+// that could benefit from an index.
+type tableInfo struct {
+	tab       cat.Table
+	eqCols    []int
+	rangeCols []int
+	orderings [][]Column
+}
+
+func (ti *tableInfo) addEqCol(ord int) {
+	for _, c := range ti.eqCols {
+		if c == ord {
+			return
+		}
+	}
+	ti.eqCols = append(ti.eqCols, ord)
+}
+
+func (ti *tableInfo) addRangeCol(ord int) {
+	for _, c := range ti.rangeCols {
+		if c == ord {
+			return
+		}
+	}
+	ti.rangeCols = append(ti.rangeCols, ord)
+}
+
+type candidateFinder struct {
+	md      *opt.Metadata
+	evalCtx *tree.EvalContext
+
+	// tables maps a table ID in the catalog to the columns referenced in the
+	// query. The same table can appear under multiple metadata table IDs (e.g.
+	// in a self-join); their columns are merged.
+	tables map[cat.StableID]*tableInfo
+	order  []cat.StableID
+}
+
+func (f *candidateFinder) init(md *opt.Metadata, evalCtx *tree.EvalContext) {
+	f.md = md
+	f.evalCtx = evalCtx
+	f.tables = make(map[cat.StableID]*tableInfo)
+}
+
+// lookupColumn returns the table that the given column belongs to, along with
+// the column ordinal within that table. It returns ok=false if the column
+// does not come directly from a table, or if it cannot be indexed.
+func (f *candidateFinder) lookupColumn(col opt.ColumnID) (_ *tableInfo, ord int, ok bool) {
+	tabID := f.md.ColumnMeta(col).Table
+	if tabID == 0 {
+		return nil, 0, false
+	}
+	tab := f.md.Table(tabID)
+	if tab.IsVirtualTable() || tab.DeletableIndexCount() != tab.IndexCount() {
+		// Virtual tables can't be indexed, and tables with indexes that are
+		// being added or dropped are skipped to keep the hypothetical catalog
+		// simple.
+		return nil, 0, false
+	}
+	ord = tabID.ColumnOrdinal(col)
+	if ord >= tab.ColumnCount() {
+		// Columns of a table duplicated by Metadata.DuplicateTable (e.g. when a
+		// disjunction is split into a union of scans) refer to the original
+		// table ID, so they have no ordinal within it.
+		return nil, 0, false
+	}
+	column := tab.Column(ord)
+	if column.Kind() != cat.Ordinary || !colinfo.ColumnTypeIsIndexable(column.DatumType()) {
+		return nil, 0, false
+	}
+	ti, found := f.tables[tab.ID()]
+	if !found {
+		ti = &tableInfo{tab: tab}
+		f.tables[tab.ID()] = ti
+		f.order = append(f.order, tab.ID())
+	}
+	return ti, ord, true
+}
+
+func (f *candidateFinder) walk(e opt.Expr) {
+	switch t := e.(type) {
+	case *memo.ScanExpr:
+		if t.Constraint != nil {
+			f.addConstraint(t.Constraint)
+		}
+
+	case *memo.SelectExpr:
+		f.addFilters(t.Filters)
+
+	case *memo.LookupJoinExpr:
+		for _, col := range t.KeyCols {
+			f.addConstraintCols(col, true /* equality */)
+		}
+
+	case *memo.MergeJoinExpr:
+		for i := range t.LeftEq {
+			f.addConstraintCols(t.LeftEq[i].ID(), true /* equality */)
+			f.addConstraintCols(t.RightEq[i].ID(), true /* equality */)
+		}
+
+	case *memo.SortExpr:
+		f.addOrdering(t.ProvidedPhysical().Ordering)
+
+	default:
+		if opt.IsJoinOp(e) {
+			left := e.Child(0).(memo.RelExpr)
+			right := e.Child(1).(memo.RelExpr)
+			on := *e.Child(2).(*memo.FiltersExpr)
+			leftEq, rightEq := memo.ExtractJoinEqualityColumns(
+				left.Relational().OutputCols, right.Relational().OutputCols, on,
+			)
+			for i := range leftEq {
+				f.addConstraintCols(leftEq[i], true /* equality */)
+				f.addConstraintCols(rightEq[i], true /* equality */)
+			}
+		}
+	}
+
+	for i, n := 0, e.ChildCount(); i < n; i++ {
+		f.walk(e.Child(i))
+	}
+}
+
+// addFilters records the columns constrained by the given filters.
+func (f *candidateFinder) addFilters(filters memo.FiltersExpr) {
+	for i := range filters {
+		props := filters[i].ScalarProps()
+		if props.Constraints == nil {
+			continue
+		}
+		for j, n := 0, props.Constraints.Length(); j < n; j++ {
+			f.addConstraint(props.Constraints.Constraint(j))
+		}
+	}
+}
+
+// addConstraint records the columns constrained by the given constraint. The
+// columns of the prefix for which all spans are single values (e.g. x = 1 or
+// x IN (1, 2)) are recorded as equality columns; the column that follows the
+// prefix, if it is constrained, is recorded as a range column.
+func (f *candidateFinder) addConstraint(c *constraint.Constraint) {
+	prefix := c.Prefix(f.evalCtx)
+	for i := 0; i < prefix; i++ {
+		f.addConstraintCols(c.Columns.Get(i).ID(), true /* equality */)
+	}
+	if prefix < c.Columns.Count() && prefix < c.ConstrainedColumns(f.evalCtx) {
+		f.addConstraintCols(c.Columns.Get(prefix).ID(), false /* equality */)
+	}
+}
+
+func (f *candidateFinder) addConstraintCols(col opt.ColumnID, equality bool) {
+	ti, ord, ok := f.lookupColumn(col)
+	if !ok {
+		return
+	}
+	if equality {
+		ti.addEqCol(ord)
+	} else {
+		ti.addRangeCol(ord)
+	}
+}
+
+// addOrdering records an ordering if all of its columns belong to the same
+// table.
+func (f *candidateFinder) addOrdering(ordering opt.Ordering) {
+	if len(ordering) == 0 {
+		return
+	}
+	var ti *tableInfo
+	cols := make([]Column, len(ordering))
+	for i := range ordering {
+		t, ord, ok := f.lookupColumn(ordering[i].ID())
+		if !ok || (ti != nil && t != ti) {
+			return
+		}
+		ti = t
+		cols[i] = Column{Ordinal: ord, Descending: ordering[i].Descending()}
+	}
+	ti.orderings = append(ti.orderings, cols)
+}
+
+// build turns the columns collected for each table into candidates.
+func (f *candidateFinder) build() []Candidate {
+	var res []Candidate
+	for _, id := range f.order {
+		ti := f.tables[id]
+		seen := make(map[string]struct{})
+		var tabCandidates []Candidate
+		add := func(cols []Column) {
+			c := Candidate{Table: ti.tab, Columns: cols}
+			k := c.key()
+			if _, ok := seen[k]; ok || servedByExistingIndex(&c) {
+				return
+			}
+			seen[k] = struct{}{}
+			tabCandidates = append(tabCandidates, c)
+		}
+
+		eqCols := make([]Column, len(ti.eqCols))
+		for i, ord := range ti.eqCols {
+			eqCols[i] = Column{Ordinal: ord}
+		}
+		for i := range eqCols {
+			add(eqCols[i : i+1])
+		}
+		for _, ord := range ti.rangeCols {
+			add([]Column{{Ordinal: ord}})
+		}
+		if len(eqCols) > 1 {
+			add(eqCols)
+		}
+		if len(eqCols) > 0 {
+			for _, ord := range ti.rangeCols {
+				if !containsOrdinal(eqCols, ord) {
+					add(append(eqCols[:len(eqCols):len(eqCols)], Column{Ordinal: ord}))
+				}
+			}
+		}
+		for _, ordering := range ti.orderings {
+			add(ordering)
+			if len(eqCols) > 0 {
+				cols := eqCols[:len(eqCols):len(eqCols)]
+				for _, col := range ordering {
+					if !containsOrdinal(eqCols, col.Ordinal) {
+						cols = append(cols, col)
+					}
+				}
+				add(cols)
+			}
+		}
+
+		// Prefer the candidates with the most columns when there are too many,
+		// since they are the most selective.
+		if len(tabCandidates) > maxCandidatesPerTable {
+			sort.SliceStable(tabCandidates, func(i, j int) bool {
+				return len(tabCandidates[i].Columns) > len(tabCandidates[j].Columns)
+			})
+			tabCandidates = tabCandidates[:maxCandidatesPerTable]
+		}
+		res = append(res, tabCandidates...)
+	}
+	return res
+}
+
+func containsOrdinal(cols []Column, ord int) bool {
+	for i := range cols {
+		if cols[i].Ordinal == ord {
+			return true
+		}
+	}
+	return false
+}
+
+// servedByExistingIndex returns true if the candidate's columns are a prefix
+// of the key columns of an existing index on the table, either in the same
+// direction or in exactly the reverse direction (which can be scanned in
+// reverse).
+func servedByExistingIndex(c *Candidate) bool {
+	for i, n := 0, c.Table.IndexCount(); i < n; i++ {
+		idx := c.Table.Index(i)
+		if idx.IsInverted() || idx.LaxKeyColumnCount() < len(c.Columns) {
+			continue
+		}
+		if _, isPartial := idx.Predicate(); isPartial {
+			continue
+		}
+		forward, reverse := true, true
+		for j := range c.Columns {
+			idxCol := idx.Column(j)
+			if idxCol.Ordinal() != c.Columns[j].Ordinal {
+				forward, reverse = false, false
+				break
+			}
+			if idxCol.Descending != c.Columns[j].Descending {
+				forward = false
+			} else {
+				reverse = false
+			}
+		}
+		if forward || reverse {
+			return true
+		}
+	}
+	return false
+}
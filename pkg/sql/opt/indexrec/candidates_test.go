@@ -0,0 +1,74 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package indexrec_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/indexrec"
+	opttestutils "github.com/cockroachdb/cockroach/pkg/sql/opt/testutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils/testcat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/datadriven"
+)
+
+// The test files in testdata/candidates support the following commands:
+//
+//   - exec-ddl
+//
+//     Runs a CREATE TABLE or CREATE INDEX statement against the test catalog.
+//
+//   - candidates
+//
+//     Optimizes the given query and prints the candidate indexes that
+//     FindCandidates derives from the resulting plan, one per line.
+//
+func TestFindCandidates(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	datadriven.Walk(t, "testdata", func(t *testing.T, path string) {
+		catalog := testcat.New()
+		evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+
+		datadriven.RunTest(t, path, func(t *testing.T, d *datadriven.TestData) string {
+			switch d.Cmd {
+			case "exec-ddl":
+				if _, err := catalog.ExecuteDDL(d.Input); err != nil {
+					d.Fatalf(t, "%v", err)
+				}
+				return ""
+
+			case "candidates":
+				var o xform.Optimizer
+				opttestutils.BuildQuery(t, &o, catalog, &evalCtx, d.Input)
+				if _, err := o.Optimize(); err != nil {
+					d.Fatalf(t, "%v", err)
+				}
+				mem := o.Memo()
+				candidates := indexrec.FindCandidates(mem.RootExpr(), mem.Metadata(), &evalCtx)
+				var buf bytes.Buffer
+				for i := range candidates {
+					buf.WriteString(candidates[i].String())
+					buf.WriteByte('\n')
+				}
+				return buf.String()
+
+			default:
+				d.Fatalf(t, "unsupported command: %s", d.Cmd)
+				return ""
+			}
+		})
+	})
+}
@@ -0,0 +1,164 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package indexrec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
+)
+
+// HypotheticalIndex is implemented by catalog indexes that do not exist, but
+// that were added to a table so that the optimizer can determine whether
+// creating them would result in a cheaper plan.
+type HypotheticalIndex interface {
+	cat.Index
+
+	// Candidate returns the candidate that the hypothetical index was created
+	// from.
+	Candidate() *Candidate
+}
+
+// Recommendation is an index that, if created, would allow the optimizer to
+// find a cheaper plan for a query.
+type Recommendation struct {
+	// TableName is the fully qualified name of the table on which the index
+	// should be created.
+	TableName cat.DataSourceName
+
+	// Candidate describes the table and the key columns of the index.
+	Candidate *Candidate
+
+	// Storing contains the ordinals of the table columns that should be stored
+	// in the index, so that the index covers the query.
+	Storing []int
+}
+
+// CreateIndex returns the statement that creates the recommended index.
+func (r *Recommendation) CreateIndex() *tree.CreateIndex {
+	tab := r.Candidate.Table
+	stmt := &tree.CreateIndex{Table: r.TableName}
+	stmt.Table.ExplicitCatalog = true
+	stmt.Table.ExplicitSchema = true
+	for _, col := range r.Candidate.Columns {
+		elem := tree.IndexElem{Column: tab.Column(col.Ordinal).ColName()}
+		if col.Descending {
+			elem.Direction = tree.Descending
+		}
+		stmt.Columns = append(stmt.Columns, elem)
+	}
+	for _, ord := range r.Storing {
+		stmt.Storing = append(stmt.Storing, tab.Column(ord).ColName())
+	}
+	return stmt
+}
+
+// SQL returns the text of the statement that creates the recommended index.
+func (r *Recommendation) SQL() string {
+	return tree.AsString(r.CreateIndex())
+}
+
+// FindRecommendations walks an optimized expression tree that was built using
+// a catalog containing hypothetical indexes, and returns a recommendation for
+// every hypothetical index that the plan uses.
+func FindRecommendations(
+	ctx context.Context, root opt.Expr, md *opt.Metadata, catalog cat.Catalog,
+) ([]Recommendation, error) {
+	var r recommender
+	r.md = md
+	r.walk(root)
+
+	res := make([]Recommendation, len(r.indexes))
+	for i, idx := range r.indexes {
+		candidate := idx.Candidate()
+		name, err := catalog.FullyQualifiedName(ctx, candidate.Table)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = Recommendation{
+			TableName: name,
+			Candidate: candidate,
+			Storing:   r.storing[i].Ordered(),
+		}
+	}
+	return res, nil
+}
+
+type recommender struct {
+	md *opt.Metadata
+
+	// indexes are the hypothetical indexes used by the plan, and storing
+	// contains the ordinals of the columns that the plan reads from each of
+	// them, excluding the columns that are part of the index key.
+	indexes []HypotheticalIndex
+	storing []util.FastIntSet
+}
+
+func (r *recommender) walk(e opt.Expr) {
+	switch t := e.(type) {
+	case *memo.ScanExpr:
+		r.addIndex(t.Table, t.Index, t.Cols)
+
+	case *memo.LookupJoinExpr:
+		r.addIndex(t.Table, t.Index, t.Cols)
+
+	case *memo.ZigzagJoinExpr:
+		r.addIndex(t.LeftTable, t.LeftIndex, t.Cols)
+		r.addIndex(t.RightTable, t.RightIndex, t.Cols)
+	}
+
+	for i, n := 0, e.ChildCount(); i < n; i++ {
+		r.walk(e.Child(i))
+	}
+}
+
+// addIndex records the given index if it is hypothetical, along with the
+// columns in cols that belong to the table and that the index would need to
+// store.
+func (r *recommender) addIndex(tabID opt.TableID, idxOrd cat.IndexOrdinal, cols opt.ColSet) {
+	tab := r.md.Table(tabID)
+	idx, ok := tab.Index(idxOrd).(HypotheticalIndex)
+	if !ok {
+		return
+	}
+	i := 0
+	for ; i < len(r.indexes); i++ {
+		if r.indexes[i] == idx {
+			break
+		}
+	}
+	if i == len(r.indexes) {
+		r.indexes = append(r.indexes, idx)
+		r.storing = append(r.storing, util.FastIntSet{})
+	}
+
+	var keyCols util.FastIntSet
+	for _, col := range idx.Candidate().Columns {
+		keyCols.Add(col.Ordinal)
+	}
+	primary := tab.Index(cat.PrimaryIndex)
+	for j, n := 0, primary.KeyColumnCount(); j < n; j++ {
+		keyCols.Add(primary.Column(j).Ordinal())
+	}
+	cols.ForEach(func(col opt.ColumnID) {
+		if r.md.ColumnMeta(col).Table != tabID {
+			return
+		}
+		ord := tabID.ColumnOrdinal(col)
+		if !keyCols.Contains(ord) && tab.Column(ord).Kind() == cat.Ordinary {
+			r.storing[i].Add(ord)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedexpr
+
+import "github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+
+// TrigramsToSpanExpr returns a SpanExpression that represents the key ranges
+// of a trigram inverted index that must be scanned to find the strings that
+// contain the given trigrams. If allMustMatch is true, the expression finds the
+// strings that contain all of the trigrams; otherwise, it finds the strings
+// that contain any of them.
+//
+// The returned expression is never tight, since a string that contains a set
+// of trigrams does not necessarily match a LIKE pattern that has the same
+// trigrams, nor is it necessarily similar to another string that it shares
+// some trigrams with. Returns nil if there are no trigrams, in which case the
+// index cannot be used.
+func TrigramsToSpanExpr(trigrams []string, allMustMatch bool) *SpanExpression {
+	var invExpr InvertedExpression
+	for _, key := range rowenc.EncodeTrigramInvertedIndexKeys(trigrams, nil /* inKey */) {
+		spanExpr := ExprForInvertedSpan(MakeSingleInvertedValSpan(key), false /* tight */)
+		switch {
+		case invExpr == nil:
+			invExpr = spanExpr
+		case allMustMatch:
+			invExpr = And(invExpr, spanExpr)
+		default:
+			invExpr = Or(invExpr, spanExpr)
+		}
+	}
+	spanExpr, ok := invExpr.(*SpanExpression)
+	if !ok {
+		return nil
+	}
+	// The trigrams of each string are de-duplicated before they are indexed, so
+	// a single trigram cannot produce duplicate primary keys.
+	spanExpr.Unique = len(trigrams) == 1
+	return spanExpr
+}
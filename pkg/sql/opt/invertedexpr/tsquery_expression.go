@@ -0,0 +1,68 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedexpr
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+)
+
+// TSQueryToSpanExpr returns a SpanExpression that represents the key ranges of
+// a tsvector inverted index that must be scanned to find the tsvectors that
+// may match the given tsquery according to the @@ operator. Returns nil if the
+// index cannot be used, e.g. because the query is a negation.
+func TSQueryToSpanExpr(q tsearch.TSQuery) *SpanExpression {
+	invExpr, ok := q.BuildInvertedExpr(tsqueryExprFactory{}).(InvertedExpression)
+	if !ok {
+		return nil
+	}
+	spanExpr, ok := invExpr.(*SpanExpression)
+	if !ok {
+		return nil
+	}
+	return spanExpr
+}
+
+// tsqueryExprFactory builds the InvertedExpression of a tsquery.
+type tsqueryExprFactory struct{}
+
+var _ tsearch.InvertedExprFactory = tsqueryExprFactory{}
+
+// Lexeme is part of the tsearch.InvertedExprFactory interface.
+func (tsqueryExprFactory) Lexeme(lexeme string, prefix bool, tight bool) interface{} {
+	// If prefix is true, the key is the common prefix of the keys of the
+	// matching lexemes, so the span covers all of them.
+	key := rowenc.EncodeLexemeInvertedIndexKey(lexeme, prefix, nil /* inKey */)
+	spanExpr := ExprForInvertedSpan(MakeSingleInvertedValSpan(key), tight)
+	// The lexemes of a tsvector are unique, so a single lexeme cannot produce
+	// duplicate primary keys.
+	spanExpr.Unique = !prefix
+	return spanExpr
+}
+
+// Unconstrained is part of the tsearch.InvertedExprFactory interface.
+func (tsqueryExprFactory) Unconstrained() interface{} {
+	return NonInvertedColExpression{}
+}
+
+// And is part of the tsearch.InvertedExprFactory interface.
+func (tsqueryExprFactory) And(left, right interface{}, tight bool) interface{} {
+	res := And(left.(InvertedExpression), right.(InvertedExpression))
+	if !tight {
+		res.SetNotTight()
+	}
+	return res
+}
+
+// Or is part of the tsearch.InvertedExprFactory interface.
+func (tsqueryExprFactory) Or(left, right interface{}) interface{} {
+	return Or(left.(InvertedExpression), right.(InvertedExpression))
+}
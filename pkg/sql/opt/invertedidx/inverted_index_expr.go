@@ -108,12 +108,25 @@ func TryFilterInvertedIndex(
 		}
 		typ = types.Geometry
 	} else {
-		filterPlanner = &jsonOrArrayFilterPlanner{
-			tabID: tabID,
-			index: index,
-		}
 		col := index.VirtualInvertedColumn().InvertedSourceColumnOrdinal()
 		typ = factory.Metadata().Table(tabID).Column(col).DatumType()
+		switch typ.Family() {
+		case types.StringFamily:
+			filterPlanner = &trigramFilterPlanner{
+				tabID: tabID,
+				index: index,
+			}
+		case types.TSVectorFamily:
+			filterPlanner = &tsqueryFilterPlanner{
+				tabID: tabID,
+				index: index,
+			}
+		default:
+			filterPlanner = &jsonOrArrayFilterPlanner{
+				tabID: tabID,
+				index: index,
+			}
+		}
 	}
 
 	var invertedExpr invertedexpr.InvertedExpression
@@ -187,6 +200,9 @@ func TryJoinInvertedIndex(
 			inputCols:   inputCols,
 			getSpanExpr: getSpanExprForGeometryIndex,
 		}
+	} else if isTrigramIndex(factory, tabID, index) || isTSVectorIndex(factory, tabID, index) {
+		// Inverted joins are not supported for trigram and tsvector indexes.
+		return nil
 	} else {
 		joinPlanner = &jsonOrArrayJoinPlanner{
 			tabID:     tabID,
@@ -0,0 +1,139 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+)
+
+type trigramFilterPlanner struct {
+	tabID opt.TableID
+	index cat.Index
+}
+
+var _ invertedFilterPlanner = &trigramFilterPlanner{}
+
+// extractInvertedFilterConditionFromLeaf is part of the invertedFilterPlanner
+// interface.
+func (t *trigramFilterPlanner) extractInvertedFilterConditionFromLeaf(
+	evalCtx *tree.EvalContext, expr opt.ScalarExpr,
+) (
+	invertedExpr invertedexpr.InvertedExpression,
+	remainingFilters opt.ScalarExpr,
+	_ *invertedexpr.PreFiltererStateForInvertedFilterer,
+) {
+	var left, right opt.ScalarExpr
+	var getTrigrams func(s string) []string
+	var allMustMatch bool
+	switch e := expr.(type) {
+	case *memo.LikeExpr:
+		left, right = e.Left, e.Right
+		getTrigrams, allMustMatch = likePatternTrigrams, true
+	case *memo.ILikeExpr:
+		left, right = e.Left, e.Right
+		// Trigrams are always lowercased, so the same trigrams can be used for
+		// case-insensitive patterns.
+		getTrigrams, allMustMatch = likePatternTrigrams, true
+	case *memo.EqExpr:
+		left, right = e.Left, e.Right
+		getTrigrams, allMustMatch = paddedTrigrams, true
+	case *memo.ModExpr:
+		// The trigram similarity operator (%). Two strings can only be similar if
+		// they share at least one trigram, unless the similarity threshold is
+		// zero, in which case all strings are similar.
+		if e.Typ.Family() != types.BoolFamily ||
+			evalCtx.SessionData.TrigramSimilarityThreshold <= 0 {
+			return invertedexpr.NonInvertedColExpression{}, expr, nil
+		}
+		left, right = e.Left, e.Right
+		getTrigrams, allMustMatch = paddedTrigrams, false
+	default:
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+
+	// The first argument should be a variable corresponding to the index
+	// column, and the second argument should be a constant string.
+	variable, ok := left.(*memo.VariableExpr)
+	if !ok || variable.Col != t.tabID.ColumnID(
+		t.index.VirtualInvertedColumn().InvertedSourceColumnOrdinal(),
+	) {
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+	if !memo.CanExtractConstDatum(right) {
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+	d, ok := memo.ExtractConstDatum(right).(*tree.DString)
+	if !ok {
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+
+	spanExpr := invertedexpr.TrigramsToSpanExpr(getTrigrams(string(*d)), allMustMatch)
+	if spanExpr == nil {
+		// The constant has no trigrams, so the index cannot be used.
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+
+	// The expression is never tight, so the original filter must always be
+	// applied. We do not currently support pre-filtering for trigram indexes,
+	// so the returned pre-filter state is nil.
+	return spanExpr, expr, nil
+}
+
+// paddedTrigrams returns the trigrams of the given string, in the same form as
+// they are stored in a trigram index.
+func paddedTrigrams(s string) []string {
+	return trigram.MakeTrigrams(s, true /* pad */)
+}
+
+// likePatternTrigrams returns the trigrams that must be contained in any
+// string that matches the given LIKE pattern. The trigrams are extracted from
+// the literal parts of the pattern between wildcards, without padding, since
+// a literal part may be the fragment of a larger word.
+func likePatternTrigrams(pattern string) []string {
+	var trigrams []string
+	var part strings.Builder
+	addPart := func() {
+		trigrams = append(trigrams, trigram.MakeTrigrams(part.String(), false /* pad */)...)
+		part.Reset()
+	}
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			part.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%' || r == '_':
+			addPart()
+		default:
+			part.WriteRune(r)
+		}
+	}
+	addPart()
+	return trigrams
+}
+
+// isTrigramIndex returns true if the given inverted index is a trigram index,
+// i.e. an inverted index on a string column.
+func isTrigramIndex(factory *norm.Factory, tabID opt.TableID, index cat.Index) bool {
+	col := index.VirtualInvertedColumn().InvertedSourceColumnOrdinal()
+	return factory.Metadata().Table(tabID).Column(col).DatumType().Family() == types.StringFamily
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+type tsqueryFilterPlanner struct {
+	tabID opt.TableID
+	index cat.Index
+}
+
+var _ invertedFilterPlanner = &tsqueryFilterPlanner{}
+
+// extractInvertedFilterConditionFromLeaf is part of the invertedFilterPlanner
+// interface.
+func (t *tsqueryFilterPlanner) extractInvertedFilterConditionFromLeaf(
+	evalCtx *tree.EvalContext, expr opt.ScalarExpr,
+) (
+	invertedExpr invertedexpr.InvertedExpression,
+	remainingFilters opt.ScalarExpr,
+	_ *invertedexpr.PreFiltererStateForInvertedFilterer,
+) {
+	e, ok := expr.(*memo.TSMatchesExpr)
+	if !ok {
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+
+	// One argument should be a variable corresponding to the index column, and
+	// the other should be a constant tsquery. The @@ operator is commutative.
+	left, right := e.Left, e.Right
+	if _, ok := left.(*memo.VariableExpr); !ok {
+		left, right = right, left
+	}
+	variable, ok := left.(*memo.VariableExpr)
+	if !ok || variable.Col != t.tabID.ColumnID(
+		t.index.VirtualInvertedColumn().InvertedSourceColumnOrdinal(),
+	) {
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+	if !memo.CanExtractConstDatum(right) {
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+	d, ok := memo.ExtractConstDatum(right).(*tree.DTSQuery)
+	if !ok {
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+
+	spanExpr := invertedexpr.TSQueryToSpanExpr(d.TSQuery)
+	if spanExpr == nil {
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+	if spanExpr.Tight {
+		return spanExpr, nil, nil
+	}
+	// We do not currently support pre-filtering for tsvector indexes, so the
+	// returned pre-filter state is nil.
+	return spanExpr, expr, nil
+}
+
+// isTSVectorIndex returns true if the given inverted index is an index of the
+// lexemes of a tsvector column.
+func isTSVectorIndex(factory *norm.Factory, tabID opt.TableID, index cat.Index) bool {
+	col := index.VirtualInvertedColumn().InvertedSourceColumnOrdinal()
+	return factory.Metadata().Table(tabID).Column(col).DatumType().Family() == types.TSVectorFamily
+}
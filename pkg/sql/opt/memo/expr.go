@@ -337,6 +337,45 @@ func (sl ScanLimit) String() string {
 	return fmt.Sprintf("%d", sl)
 }
 
+// SkipScanPrefix is used for a skip scan and stores the number of index key
+// columns that make up the distinct prefix as well as the desired scan
+// direction. A value of 0 means that the scan is not a skip scan.
+type SkipScanPrefix int32
+
+// MakeSkipScanPrefix initializes a SkipScanPrefix with a prefix length and a
+// direction.
+func MakeSkipScanPrefix(prefixLen int, reverse bool) SkipScanPrefix {
+	if reverse {
+		return SkipScanPrefix(-prefixLen)
+	}
+	return SkipScanPrefix(prefixLen)
+}
+
+// IsSet returns true if the scan is a skip scan.
+func (sp SkipScanPrefix) IsSet() bool {
+	return sp != 0
+}
+
+// PrefixLen returns the number of index key columns in the distinct prefix.
+func (sp SkipScanPrefix) PrefixLen() int {
+	if sp.Reverse() {
+		return int(-sp)
+	}
+	return int(sp)
+}
+
+// Reverse returns true if the skip scan requires a reverse scan.
+func (sp SkipScanPrefix) Reverse() bool {
+	return sp < 0
+}
+
+func (sp SkipScanPrefix) String() string {
+	if sp.Reverse() {
+		return fmt.Sprintf("%d(rev)", -sp)
+	}
+	return fmt.Sprintf("%d", sp)
+}
+
 // ScanFlags stores any flags for the scan specified in the query (see
 // tree.IndexFlags). These flags may be consulted by transformation rules or the
 // coster.
@@ -626,11 +665,13 @@ func (f *WindowFrame) String() string {
 }
 
 // IsCanonical returns true if the ScanPrivate indicates an original unaltered
-// primary index Scan operator (i.e. unconstrained and not limited).
+// primary index Scan operator (i.e. unconstrained, not limited and not a skip
+// scan).
 func (s *ScanPrivate) IsCanonical() bool {
 	return s.Index == cat.PrimaryIndex &&
 		s.Constraint == nil &&
-		s.HardLimit == 0
+		s.HardLimit == 0 &&
+		!s.SkipScanPrefix.IsSet()
 }
 
 // IsUnfiltered returns true if the ScanPrivate will produce all rows in the
@@ -639,6 +680,7 @@ func (s *ScanPrivate) IsUnfiltered(md *opt.Metadata) bool {
 	return (s.Constraint == nil || s.Constraint.IsUnconstrained()) &&
 		s.InvertedConstraint == nil &&
 		s.HardLimit == 0 &&
+		!s.SkipScanPrefix.IsSet() &&
 		!s.UsesPartialIndex(md)
 }
 
@@ -677,6 +719,18 @@ func (s *ScanPrivate) PartialIndexPredicate(md *opt.Metadata) FiltersExpr {
 	return *p.(*FiltersExpr)
 }
 
+// SkipScanPrefixCols returns the set of index key columns that make up the
+// distinct prefix of a skip scan. It returns the empty set if the scan is not a
+// skip scan.
+func (s *ScanPrivate) SkipScanPrefixCols(md *opt.Metadata) opt.ColSet {
+	var cols opt.ColSet
+	index := md.Table(s.Table).Index(s.Index)
+	for i, n := 0, s.SkipScanPrefix.PrefixLen(); i < n; i++ {
+		cols.Add(s.Table.IndexColumnID(index, i))
+	}
+	return cols
+}
+
 // UsesPartialIndex returns true if the LookupJoinPrivate looks-up via a
 // partial index.
 func (lj *LookupJoinPrivate) UsesPartialIndex(md *opt.Metadata) bool {
@@ -480,6 +480,10 @@ func (h *hasher) HashScanLimit(val ScanLimit) {
 	h.HashUint64(uint64(val))
 }
 
+func (h *hasher) HashSkipScanPrefix(val SkipScanPrefix) {
+	h.HashInt(int(val))
+}
+
 func (h *hasher) HashScanFlags(val ScanFlags) {
 	h.HashBool(val.NoIndexJoin)
 	h.HashBool(val.ForceIndex)
@@ -868,6 +872,10 @@ func (h *hasher) IsScanLimitEqual(l, r ScanLimit) bool {
 	return l == r
 }
 
+func (h *hasher) IsSkipScanPrefixEqual(l, r SkipScanPrefix) bool {
+	return l == r
+}
+
 func (h *hasher) IsScanFlagsEqual(l, r ScanFlags) bool {
 	return l == r
 }
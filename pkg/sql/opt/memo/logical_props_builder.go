@@ -661,6 +661,12 @@ func (b *logicalPropsBuilder) buildUnionAllProps(union *UnionAllExpr, rel *props
 	b.buildSetProps(union, rel)
 }
 
+func (b *logicalPropsBuilder) buildLocalityOptimizedSearchProps(
+	locOptSearch *LocalityOptimizedSearchExpr, rel *props.Relational,
+) {
+	b.buildSetProps(locOptSearch, rel)
+}
+
 func (b *logicalPropsBuilder) buildIntersectAllProps(
 	isect *IntersectAllExpr, rel *props.Relational,
 ) {
@@ -1690,6 +1696,34 @@ func MakeTableFuncDep(md *opt.Metadata, tabID opt.TableID) *props.FuncDepSet {
 			fd.AddStrictKey(keyCols, allCols)
 		}
 	}
+
+	// Add keys for unique constraints that are not enforced by an index. Only
+	// validated constraints can be used, since the existing data in the table
+	// may not satisfy an unvalidated constraint.
+	for i := 0; i < tab.UniqueCount(); i++ {
+		unique := tab.Unique(i)
+		if !unique.WithoutIndex() || !unique.Validated() {
+			continue
+		}
+
+		var keyCols opt.ColSet
+		hasNulls := false
+		for j, n := 0, unique.ColumnCount(); j < n; j++ {
+			ord := unique.ColumnOrdinal(tab, j)
+			keyCols.Add(tabID.ColumnID(ord))
+			if tab.Column(ord).IsNullable() {
+				hasNulls = true
+			}
+		}
+
+		if hasNulls {
+			// Rows with NULL values in the constraint columns are not required to
+			// be unique, so only a lax key can be added.
+			fd.AddLaxKey(keyCols, allCols)
+		} else {
+			fd.AddStrictKey(keyCols, allCols)
+		}
+	}
 	md.SetTableAnnotation(tabID, fdAnnID, fd)
 	return fd
 }
@@ -1699,7 +1733,7 @@ func (b *logicalPropsBuilder) makeSetCardinality(
 ) props.Cardinality {
 	var card props.Cardinality
 	switch nt {
-	case opt.UnionOp, opt.UnionAllOp:
+	case opt.UnionOp, opt.UnionAllOp, opt.LocalityOptimizedSearchOp:
 		// Add cardinality of left and right inputs.
 		card = left.Add(right)
 
@@ -216,6 +216,12 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData.PreferLookupJoinsForFKs = false
 	notStale()
 
+	// Stale trigram similarity threshold.
+	evalCtx.SessionData.TrigramSimilarityThreshold = 0.5
+	stale()
+	evalCtx.SessionData.TrigramSimilarityThreshold = 0
+	notStale()
+
 	// Stale data sources and schema. Create new catalog so that data sources are
 	// recreated and can be modified independently.
 	catalog = testcat.New()
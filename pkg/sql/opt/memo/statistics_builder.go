@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
@@ -370,7 +371,8 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 		return sb.colStatIndexJoin(colSet, e.(*IndexJoinExpr))
 
 	case opt.UnionOp, opt.IntersectOp, opt.ExceptOp,
-		opt.UnionAllOp, opt.IntersectAllOp, opt.ExceptAllOp:
+		opt.UnionAllOp, opt.IntersectAllOp, opt.ExceptAllOp,
+		opt.LocalityOptimizedSearchOp:
 		return sb.colStatSetNode(colSet, e)
 
 	case opt.GroupByOp, opt.ScalarGroupByOp, opt.DistinctOnOp, opt.EnsureDistinctOnOp,
@@ -566,7 +568,7 @@ func (sb *statisticsBuilder) makeTableStatistics(tabID opt.TableID) *props.Stati
 					// entries, and we need to create a new stat for it, and not apply a histogram
 					// to the source column.
 					virtualColOrds := invIndexVirtualCols[stat.ColumnOrdinal(0)]
-					if len(virtualColOrds) == 0 {
+					if len(virtualColOrds) == 0 || !isInvertedHistogram(sb.md.ColumnMeta(col).Type, stat.Histogram()) {
 						colStat.Histogram = &props.Histogram{}
 						colStat.Histogram.Init(sb.evalCtx, col, stat.Histogram())
 					} else {
@@ -633,7 +635,7 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 	// scan on a non-partial index. The stats of the scan are the same as the
 	// underlying table stats.
 	if scan.Constraint == nil && scan.InvertedConstraint == nil && pred == nil {
-		sb.finalizeFromCardinality(relProps)
+		sb.finalizeScan(scan, relProps)
 		return
 	}
 
@@ -649,7 +651,7 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 			}
 		}
 		sb.filterRelExpr(pred, scan, notNullCols, relProps, s, &scan.Relational().FuncDeps)
-		sb.finalizeFromCardinality(relProps)
+		sb.finalizeScan(scan, relProps)
 		return
 	}
 
@@ -658,7 +660,7 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 	// predicate (if they exist) to the underlying table stats.
 	if scan.Constraint == nil || scan.Constraint.Spans.Count() < 2 {
 		sb.constrainScan(scan, scan.Constraint, scan.InvertedConstraint, pred, relProps, s)
-		sb.finalizeFromCardinality(relProps)
+		sb.finalizeScan(scan, relProps)
 		return
 	}
 
@@ -705,6 +707,27 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 	s.Selectivity = min(s.Selectivity, spanStatsUnion.Selectivity)
 	s.RowCount = min(s.RowCount, spanStatsUnion.RowCount)
 
+	sb.finalizeScan(scan, relProps)
+}
+
+// finalizeScan is called from buildScan once the row count of the scanned
+// spans is known. If the scan is a skip scan, it only returns one row for each
+// distinct value of the index key prefix, so the row count is reduced to the
+// distinct count of the prefix columns.
+func (sb *statisticsBuilder) finalizeScan(scan *ScanExpr, relProps *props.Relational) {
+	s := &relProps.Stats
+	if scan.SkipScanPrefix.IsSet() {
+		// Ignore prefix columns that are constant or determined by other prefix
+		// columns, since they do not increase the number of distinct prefixes.
+		prefixCols := relProps.FuncDeps.ReduceCols(scan.SkipScanPrefixCols(sb.md))
+		distinctCount := 1.0
+		if !prefixCols.Empty() {
+			distinctCount = sb.colStat(prefixCols, scan).DistinctCount
+		}
+		if distinctCount < s.RowCount {
+			s.ApplySelectivity(distinctCount / s.RowCount)
+		}
+	}
 	sb.finalizeFromCardinality(relProps)
 }
 
@@ -1831,7 +1854,7 @@ func (sb *statisticsBuilder) buildSetNode(setNode RelExpr, relProps *props.Relat
 	// These calculations are an upper bound on the row count. It's likely that
 	// there is some overlap between the two sets, but not full overlap.
 	switch setNode.Op() {
-	case opt.UnionOp, opt.UnionAllOp:
+	case opt.UnionOp, opt.UnionAllOp, opt.LocalityOptimizedSearchOp:
 		s.RowCount = leftStats.RowCount + rightStats.RowCount
 
 	case opt.IntersectOp, opt.IntersectAllOp:
@@ -1879,7 +1902,7 @@ func (sb *statisticsBuilder) colStatSetNodeImpl(
 	// These calculations are an upper bound on the distinct count. It's likely
 	// that there is some overlap between the two sets, but not full overlap.
 	switch setNode.Op() {
-	case opt.UnionOp, opt.UnionAllOp:
+	case opt.UnionOp, opt.UnionAllOp, opt.LocalityOptimizedSearchOp:
 		colStat.DistinctCount = leftColStat.DistinctCount + rightColStat.DistinctCount
 		colStat.NullCount = leftNullCount + rightNullCount
 
@@ -2582,6 +2605,20 @@ func (sb *statisticsBuilder) finalizeFromRowCountAndDistinctCounts(
 	}
 }
 
+// isInvertedHistogram returns true if the given histogram on a column of the
+// given type describes the entries of an inverted index on the column. Columns
+// of types that can be indexed by both forward and inverted indexes (strings,
+// which can have trigram indexes) can have either kind of histogram. Inverted
+// histograms are distinguished by their BYTES upper bounds, which are the
+// encoded inverted index keys.
+func isInvertedHistogram(colTyp *types.T, hist []cat.HistogramBucket) bool {
+	if colinfo.ColumnTypeIsOnlyInvertedIndexable(colTyp) {
+		return true
+	}
+	return len(hist) > 0 && hist[0].UpperBound.ResolvedType().Family() == types.BytesFamily &&
+		colTyp.Family() != types.BytesFamily
+}
+
 func (sb *statisticsBuilder) shouldUseHistogram(relProps *props.Relational, cols opt.ColSet) bool {
 	// If we know that the cardinality is below a certain threshold (e.g., due to
 	// a constraint on a key column), don't bother adding the overhead of
@@ -2594,10 +2631,10 @@ func (sb *statisticsBuilder) shouldUseHistogram(relProps *props.Relational, cols
 		colTyp := sb.md.ColumnMeta(col).Type
 		switch colTyp {
 		case types.Geometry, types.Geography:
-			// Special case these since ColumnTypeIsInvertedIndexable returns true for
-			// them, but they are supported in histograms now.
+			// Special case these since ColumnTypeIsOnlyInvertedIndexable returns true
+			// for them, but they are supported in histograms now.
 		default:
-			if colinfo.ColumnTypeIsInvertedIndexable(colTyp) {
+			if colinfo.ColumnTypeIsOnlyInvertedIndexable(colTyp) {
 				allowHist = false
 			}
 		}
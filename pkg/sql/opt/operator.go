@@ -142,6 +142,7 @@ var ComparisonOpReverseMap = map[Operator]tree.ComparisonOperator{
 	OverlapsOp:       tree.Overlaps,
 	BBoxCoversOp:     tree.RegMatch,
 	BBoxIntersectsOp: tree.Overlaps,
+	TSMatchesOp:      tree.TSMatches,
 }
 
 // BinaryOpReverseMap maps from an optimizer operator type to a semantic tree
@@ -96,6 +96,15 @@ type groupby struct {
 	// projects that expression.
 	groupStrs groupByStrSet
 
+	// rolledUpStrs contains a string representation of each GROUP BY
+	// expression that belongs to a different grouping set of a GROUPING SETS,
+	// ROLLUP or CUBE clause than the one being built, mapped to the type of the
+	// expression. These expressions are NULL in the rows produced by the grouping
+	// set being built. For example, when building the (a) grouping set of:
+	//   SELECT a, b, count(*) FROM t GROUP BY ROLLUP (a, b)
+	// rolledUpStrs contains b.
+	rolledUpStrs map[string]*types.T
+
 	// buildingGroupingCols is true while the grouping columns are being built.
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
@@ -324,7 +333,13 @@ func (b *Builder) constructGroupBy(
 // buildGroupingColumns builds the grouping columns and adds them to the
 // groupby scopes that will be used to build the aggregation expression.
 // Returns the slice of grouping columns.
-func (b *Builder) buildGroupingColumns(sel *tree.SelectClause, projectionsScope, fromScope *scope) {
+//
+// If sel is one of the grouping sets of a GROUPING SETS, ROLLUP or CUBE clause,
+// groupingSetExprs contains the GROUP BY expressions of all the grouping sets
+// (see buildGroupingSets).
+func (b *Builder) buildGroupingColumns(
+	sel *tree.SelectClause, groupingSetExprs tree.Exprs, projectionsScope, fromScope *scope,
+) {
 	if fromScope.groupby == nil {
 		fromScope.initGrouping()
 	}
@@ -332,6 +347,9 @@ func (b *Builder) buildGroupingColumns(sel *tree.SelectClause, projectionsScope,
 
 	// The "from" columns are visible to any grouping expressions.
 	b.buildGroupingList(sel.GroupBy, sel.Exprs, projectionsScope, fromScope)
+	if groupingSetExprs != nil {
+		b.buildRolledUpGroupingList(groupingSetExprs, sel.Exprs, projectionsScope, fromScope)
+	}
 
 	// Copy the grouping columns to the aggOutScope.
 	g.aggOutScope.appendColumns(g.groupingCols())
@@ -512,6 +530,33 @@ func (b *Builder) buildGroupingList(
 	g.buildingGroupingCols = false
 }
 
+// buildRolledUpGroupingList populates rolledUpStrs with the GROUP BY
+// expressions of a GROUPING SETS, ROLLUP or CUBE clause that are not part of
+// the grouping set being built. It must be called after buildGroupingList.
+//
+// groupingSetExprs The GROUP BY expressions of all the grouping sets.
+// selects          The select expressions (see buildGroupingList).
+// fromScope        The scope for the input to the aggregation (the FROM clause).
+func (b *Builder) buildRolledUpGroupingList(
+	groupingSetExprs tree.Exprs, selects tree.SelectExprs, projectionsScope *scope, fromScope *scope,
+) {
+	// We need to save and restore the previous value of the field in semaCtx
+	// in case we are recursively called within a subquery context.
+	defer b.semaCtx.Properties.Restore(b.semaCtx.Properties)
+
+	g := fromScope.groupby
+	g.rolledUpStrs = make(map[string]*types.T)
+	for _, e := range groupingSetExprs {
+		exprs, _ := b.resolveGrouping(e, selects, projectionsScope, fromScope)
+		for _, texpr := range exprs {
+			exprStr := symbolicExprStr(texpr)
+			if _, ok := g.groupStrs[exprStr]; !ok {
+				g.rolledUpStrs[exprStr] = texpr.ResolvedType()
+			}
+		}
+	}
+}
+
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
 // groupStrs and to the aggInScope.
@@ -529,9 +574,41 @@ func (b *Builder) buildGroupingList(
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
 ) {
+	// We need to save and restore the previous value of the field in semaCtx
+	// in case we are recursively called within a subquery context.
+	defer b.semaCtx.Properties.Restore(b.semaCtx.Properties)
+
+	exprs, alias := b.resolveGrouping(groupBy, selects, projectionsScope, fromScope)
+
+	// Finally, build each of the GROUP BY columns.
+	for _, e := range exprs {
+		// If a grouping column has already been added, don't add it again.
+		// GROUP BY a, a is semantically equivalent to GROUP BY a.
+		exprStr := symbolicExprStr(e)
+		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			continue
+		}
+
+		// Save a representation of the GROUP BY expression for validation of the
+		// SELECT and HAVING expressions. This enables queries such as:
+		//   SELECT x+y FROM t GROUP BY x+y
+		col := b.addColumn(aggInScope, alias, e)
+		b.buildScalar(e, fromScope, aggInScope, col, nil)
+		fromScope.groupby.groupStrs[exprStr] = col
+	}
+}
+
+// resolveGrouping resolves the types of a GROUP BY expression, expanding stars
+// and flattening tuples. It returns the resulting expressions along with the
+// alias of the SELECT target that the GROUP BY expression refers to, if any.
+// The caller is responsible for saving and restoring b.semaCtx.Properties.
+//
+// See buildGrouping for a description of the parameters.
+func (b *Builder) resolveGrouping(
+	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope *scope,
+) (exprs []tree.TypedExpr, alias string) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
-	alias := ""
 
 	// Comment below pasted from PostgreSQL (findTargetListEntrySQL92 in
 	// src/backend/parser/parse_clause.c).
@@ -609,34 +686,13 @@ func (b *Builder) buildGrouping(
 		}
 	}()
 
-	// We need to save and restore the previous value of the field in semaCtx
-	// in case we are recursively called within a subquery context.
-	defer b.semaCtx.Properties.Restore(b.semaCtx.Properties)
-
 	// Make sure the GROUP BY columns have no special functions.
 	b.semaCtx.Properties.Require(exprKindGroupBy.String(), tree.RejectSpecial)
 	fromScope.context = exprKindGroupBy
 
 	// Resolve types, expand stars, and flatten tuples.
-	exprs := b.expandStarAndResolveType(groupBy, fromScope)
-	exprs = flattenTuples(exprs)
-
-	// Finally, build each of the GROUP BY columns.
-	for _, e := range exprs {
-		// If a grouping column has already been added, don't add it again.
-		// GROUP BY a, a is semantically equivalent to GROUP BY a.
-		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
-			continue
-		}
-
-		// Save a representation of the GROUP BY expression for validation of the
-		// SELECT and HAVING expressions. This enables queries such as:
-		//   SELECT x+y FROM t GROUP BY x+y
-		col := b.addColumn(aggInScope, alias, e)
-		b.buildScalar(e, fromScope, aggInScope, col, nil)
-		fromScope.groupby.groupStrs[exprStr] = col
-	}
+	exprs = b.expandStarAndResolveType(groupBy, fromScope)
+	return flattenTuples(exprs), alias
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -902,6 +958,11 @@ func newGroupingError(name *tree.Name) error {
 // table. In that case, we can allow col as an "implicit" grouping column, even
 // if it is not specified in the query.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.rolledUpStrs != nil {
+		// The grouping sets of a GROUPING SETS, ROLLUP or CUBE clause are built on
+		// top of copies of the input which don't retain the table of each column.
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
@@ -0,0 +1,312 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+// This file has builder code specific to queries with GROUPING SETS, ROLLUP or
+// CUBE in the GROUP BY clause.
+//
+// We build such queries as a UNION ALL of one aggregation per grouping set.
+// Each aggregation is built from the same SELECT clause, except that it only
+// groups on the expressions of its own grouping set. References to the GROUP
+// BY expressions of the other grouping sets are replaced with NULLs, and
+// GROUPING() operations are replaced with constants.
+//
+// For example:
+//   SELECT a, b, sum(c), GROUPING(a, b) FROM t GROUP BY ROLLUP (a, b)
+//
+// is built like:
+//   SELECT a, b, sum(c), 0 FROM t GROUP BY a, b
+//   UNION ALL
+//   SELECT a, NULL, sum(c), 1 FROM t GROUP BY a
+//   UNION ALL
+//   SELECT NULL, NULL, sum(c), 3 FROM t
+//
+// The FROM and WHERE clauses are only built once, and each aggregation reads
+// its own copy of them with new column IDs. Note that this means that volatile
+// expressions in these clauses are evaluated separately for each grouping set.
+// DISTINCT, ORDER BY and LIMIT apply to the rows of all the grouping sets, so
+// they are built on top of the UNION ALL.
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// maxGroupingSets is the maximum number of grouping sets that a GROUP BY clause
+// can expand to. This matches Postgres.
+const maxGroupingSets = 4096
+
+var errTooManyGroupingSets = pgerror.Newf(
+	pgcode.StatementTooComplex, "too many grouping sets present (maximum %d)", maxGroupingSets,
+)
+
+// hasGroupingSets returns true if the given GROUP BY clause contains a
+// GROUPING SETS, ROLLUP or CUBE item.
+func hasGroupingSets(groupBy tree.GroupBy) bool {
+	for _, e := range groupBy {
+		if _, ok := e.(*tree.GroupingSet); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// buildGroupingSets builds a set of memo groups that represent the given
+// select clause, which has GROUPING SETS, ROLLUP or CUBE items in its GROUP BY
+// clause. fromScope contains the already built FROM and WHERE clauses.
+//
+// See Builder.buildStmt for a description of the remaining input and
+// return values.
+func (b *Builder) buildGroupingSets(
+	sel *tree.SelectClause,
+	orderBy tree.OrderBy,
+	locking lockingSpec,
+	desiredTypes []*types.T,
+	inScope, fromScope *scope,
+) (outScope *scope) {
+	if sel.DistinctOn != nil {
+		panic(unimplemented.New("grouping sets with distinct on",
+			"DISTINCT ON is not supported with GROUPING SETS, ROLLUP or CUBE"))
+	}
+
+	groupingSets := expandGroupingSets(sel.GroupBy)
+	allExprs := groupingSetExprs(sel.GroupBy, nil /* exprs */)
+
+	// Building a grouping set modifies its FROM scope, so save the input before
+	// building the first one.
+	input := fromScope.expr
+	inputCols := append([]scopeColumn(nil), fromScope.cols...)
+
+	// DISTINCT is applied to the rows of all the grouping sets below.
+	setSel := *sel
+	setSel.Distinct = false
+	for i, set := range groupingSets {
+		setFromScope := fromScope
+		if i > 0 {
+			setFromScope = b.copyGroupingSetsInput(input, inputCols, inScope)
+			setFromScope.windowDefs = fromScope.windowDefs
+		}
+		setSel.GroupBy = set
+		setScope := b.finishBuildSelectClause(
+			&setSel, allExprs, orderBy, locking, desiredTypes, setFromScope,
+		)
+		if i == 0 {
+			outScope = setScope
+		} else {
+			outScope = b.buildGroupingSetsUnionAll(outScope, setScope, inScope)
+		}
+	}
+
+	if sel.Distinct {
+		outScope.expr = b.constructDistinct(outScope)
+	}
+	return outScope
+}
+
+// expandGroupingSets returns the grouping sets represented by the given GROUP
+// BY clause. This is the cross product of the grouping sets of its items. For
+// example:
+//   GROUP BY a, ROLLUP (b, c)
+// expands to the grouping sets (a, b, c), (a, b) and (a).
+func expandGroupingSets(groupBy tree.GroupBy) []tree.GroupBy {
+	sets := []tree.GroupBy{nil}
+	for _, item := range groupBy {
+		itemSets := expandGroupingItem(item)
+		if len(sets)*len(itemSets) > maxGroupingSets {
+			panic(errTooManyGroupingSets)
+		}
+		product := make([]tree.GroupBy, 0, len(sets)*len(itemSets))
+		for _, set := range sets {
+			for _, itemSet := range itemSets {
+				newSet := make(tree.GroupBy, 0, len(set)+len(itemSet))
+				newSet = append(newSet, set...)
+				newSet = append(newSet, itemSet...)
+				product = append(product, newSet)
+			}
+		}
+		sets = product
+	}
+	return sets
+}
+
+// expandGroupingItem returns the grouping sets represented by a single item of
+// a GROUP BY clause. The sets are returned in the same order as Postgres.
+func expandGroupingItem(item tree.Expr) []tree.GroupBy {
+	gs, ok := item.(*tree.GroupingSet)
+	if !ok {
+		return []tree.GroupBy{{item}}
+	}
+
+	switch gs.Type {
+	case tree.RollupGroupingSet:
+		// ROLLUP (a, b) groups on every prefix of its list: (a, b), (a) and ().
+		sets := make([]tree.GroupBy, 0, len(gs.Exprs)+1)
+		for i := len(gs.Exprs); i >= 0; i-- {
+			sets = append(sets, tree.GroupBy(gs.Exprs[:i]))
+		}
+		return sets
+
+	case tree.CubeGroupingSet:
+		// CUBE (a, b) groups on every subset of its list: (a, b), (a), (b) and ().
+		// The check on the length of the list avoids overflowing the shift.
+		n := len(gs.Exprs)
+		if n >= 63 || 1<<n > maxGroupingSets {
+			panic(errTooManyGroupingSets)
+		}
+		sets := make([]tree.GroupBy, 0, 1<<n)
+		for mask := 1<<n - 1; mask >= 0; mask-- {
+			var set tree.GroupBy
+			for i, e := range gs.Exprs {
+				if mask&(1<<(n-1-i)) != 0 {
+					set = append(set, e)
+				}
+			}
+			sets = append(sets, set)
+		}
+		return sets
+
+	case tree.ExplicitGroupingSets:
+		var sets []tree.GroupBy
+		for _, e := range gs.Exprs {
+			sets = append(sets, expandGroupingItem(e)...)
+			if len(sets) > maxGroupingSets {
+				panic(errTooManyGroupingSets)
+			}
+		}
+		return sets
+
+	default:
+		panic(errors.AssertionFailedf("unknown grouping set type %v", gs.Type))
+	}
+}
+
+// groupingSetExprs appends the GROUP BY expressions of all the grouping sets in
+// the given GROUP BY clause to exprs, and returns the result.
+func groupingSetExprs(groupBy tree.GroupBy, exprs tree.Exprs) tree.Exprs {
+	for _, e := range groupBy {
+		if gs, ok := e.(*tree.GroupingSet); ok {
+			exprs = groupingSetExprs(tree.GroupBy(gs.Exprs), exprs)
+		} else {
+			exprs = append(exprs, e)
+		}
+	}
+	return exprs
+}
+
+// copyGroupingSetsInput returns a new scope that projects the given input
+// columns of a grouping sets query using new column IDs, so that each grouping
+// set can be built on top of its own copy of the input.
+func (b *Builder) copyGroupingSetsInput(
+	input memo.RelExpr, inputCols []scopeColumn, inScope *scope,
+) (outScope *scope) {
+	outScope = inScope.push()
+	outScope.cols = make([]scopeColumn, 0, len(inputCols))
+	projections := make(memo.ProjectionsExpr, 0, len(inputCols))
+	newColIDs := make(map[opt.ColumnID]opt.ColumnID, len(inputCols))
+	for i := range inputCols {
+		col := inputCols[i]
+		if newColID, ok := newColIDs[col.id]; ok {
+			// The same column can appear multiple times in the input scope; it
+			// only needs to be projected once.
+			col.id = newColID
+			outScope.cols = append(outScope.cols, col)
+			continue
+		}
+		b.populateSynthesizedColumn(&col, nil /* scalar */)
+		newColIDs[inputCols[i].id] = col.id
+		projections = append(projections, b.factory.ConstructProjectionsItem(
+			b.factory.ConstructVariable(inputCols[i].id), col.id,
+		))
+		outScope.cols = append(outScope.cols, col)
+	}
+	outScope.expr = b.factory.ConstructProject(input, projections, opt.ColSet{})
+	return outScope
+}
+
+// buildGroupingSetsUnionAll combines the rows produced by two grouping sets (or
+// by UNION ALLs of grouping sets) with a UNION ALL. Unlike buildSetOp, the
+// ORDER BY columns and the ordering of the inputs are preserved; the inputs
+// have identical columns since they are built from the same select clause.
+func (b *Builder) buildGroupingSetsUnionAll(leftScope, rightScope, inScope *scope) (outScope *scope) {
+	outScope = inScope.push()
+	outScope.cols = make([]scopeColumn, len(leftScope.cols))
+	for i := range leftScope.cols {
+		outScope.cols[i] = leftScope.cols[i]
+		b.populateSynthesizedColumn(&outScope.cols[i], nil /* scalar */)
+	}
+	outScope.extraCols = make([]scopeColumn, len(leftScope.extraCols))
+	for i := range leftScope.extraCols {
+		outScope.extraCols[i] = leftScope.extraCols[i]
+		b.populateSynthesizedColumn(&outScope.extraCols[i], nil /* scalar */)
+	}
+
+	leftCols := append(colsToColList(leftScope.cols), colsToColList(leftScope.extraCols)...)
+	rightCols := append(colsToColList(rightScope.cols), colsToColList(rightScope.extraCols)...)
+	outCols := append(colsToColList(outScope.cols), colsToColList(outScope.extraCols)...)
+	outScope.expr = b.factory.ConstructUnionAll(
+		leftScope.expr.(memo.RelExpr),
+		rightScope.expr.(memo.RelExpr),
+		&memo.SetPrivate{LeftCols: leftCols, RightCols: rightCols, OutCols: outCols},
+	)
+
+	if !leftScope.ordering.Empty() {
+		outScope.ordering = make(opt.Ordering, len(leftScope.ordering))
+		for i, ordCol := range leftScope.ordering {
+			idx, ok := leftCols.Find(ordCol.ID())
+			if !ok {
+				panic(errors.AssertionFailedf("ordering column %d not found", ordCol.ID()))
+			}
+			outScope.ordering[i] = opt.MakeOrderingColumn(outCols[idx], ordCol.Descending())
+		}
+	}
+	return outScope
+}
+
+// buildGroupingFunc builds a GROUPING() operation. Since each grouping set is
+// built separately (see buildGroupingSets), GROUPING() is a constant bit mask
+// which has a bit set for each argument that is not grouped on by the grouping
+// set being built.
+func (b *Builder) buildGroupingFunc(t *tree.GroupingExpr, inScope *scope) opt.ScalarExpr {
+	switch inScope.context {
+	case exprKindWhere, exprKindOn, exprKindLateralJoin:
+		panic(pgerror.Newf(pgcode.Grouping,
+			"grouping operations are not allowed in %s", inScope.context.String(),
+		))
+	}
+	if !inScope.inGroupingContext() || inScope.inAgg || inScope.groupby.buildingGroupingCols {
+		panic(errGroupingArgs)
+	}
+	g := inScope.groupby
+	var mask tree.DInt
+	for _, e := range t.Exprs {
+		mask <<= 1
+		exprStr := symbolicExprStr(e.(tree.TypedExpr))
+		if _, ok := g.groupStrs[exprStr]; ok {
+			continue
+		}
+		if _, ok := g.rolledUpStrs[exprStr]; ok {
+			mask |= 1
+			continue
+		}
+		panic(errGroupingArgs)
+	}
+	return b.factory.ConstructConstVal(tree.NewDInt(mask), types.Int)
+}
+
+var errGroupingArgs = pgerror.New(
+	pgcode.Grouping, "arguments to GROUPING must be grouping expressions of the associated query level",
+)
@@ -53,8 +53,12 @@ func (b *Builder) buildJoin(
 	b.validateJoinTableNames(leftScope, rightScope)
 
 	joinType := descpb.JoinTypeFromAstString(join.JoinType)
+	hint := join.Hint
+	if hint == "" {
+		hint = b.joinHintFromStatementHints(joinType)
+	}
 	var flags memo.JoinFlags
-	switch join.Hint {
+	switch hint {
 	case "":
 	case tree.AstHash:
 		telemetry.Inc(sqltelemetry.HashJoinHintUseCounter)
@@ -88,7 +92,7 @@ func (b *Builder) buildJoin(
 
 	default:
 		panic(pgerror.Newf(
-			pgcode.FeatureNotSupported, "join hint %s not supported", join.Hint,
+			pgcode.FeatureNotSupported, "join hint %s not supported", hint,
 		))
 	}
 
@@ -140,6 +144,20 @@ func (b *Builder) buildJoin(
 	}
 }
 
+// joinHintFromStatementHints returns the join hint to use for a join of the
+// given type that has no explicit hint, according to the statement hints. A
+// join algorithm that cannot be used for the join type is ignored.
+func (b *Builder) joinHintFromStatementHints(joinType descpb.JoinType) string {
+	hint := b.StatementHints.JoinAlgorithm()
+	switch hint {
+	case tree.AstLookup, tree.AstInverted:
+		if joinType != descpb.InnerJoin && joinType != descpb.LeftOuterJoin {
+			return ""
+		}
+	}
+	return hint
+}
+
 // validateJoinTableNames checks that table names are not repeated between the
 // left and right sides of a join. leftTables contains a pre-built map of the
 // tables from the left side of the join, and rightScope contains the
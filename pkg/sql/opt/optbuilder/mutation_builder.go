@@ -251,6 +251,8 @@ func (mb *mutationBuilder) buildInputForUpdate(
 		indexFlags = source.IndexFlags
 		telemetry.Inc(sqltelemetry.IndexHintUseCounter)
 		telemetry.Inc(sqltelemetry.IndexHintUpdateUseCounter)
+	} else {
+		indexFlags = mb.b.indexFlagsFromStatementHints(mb.tab)
 	}
 
 	// Fetch columns from different instance of the table metadata, so that it's
@@ -358,6 +360,8 @@ func (mb *mutationBuilder) buildInputForDelete(
 		indexFlags = source.IndexFlags
 		telemetry.Inc(sqltelemetry.IndexHintUseCounter)
 		telemetry.Inc(sqltelemetry.IndexHintDeleteUseCounter)
+	} else {
+		indexFlags = mb.b.indexFlagsFromStatementHints(mb.tab)
 	}
 
 	// Fetch columns from different instance of the table metadata, so that it's
@@ -58,7 +58,8 @@ func (b *Builder) buildScalar(
 		// TODO(rytaft): This currently regenerates a string for each subexpression.
 		// Change this to generate the string once for the top-level expression and
 		// check the relevant slice for this subexpression.
-		if col, ok := inScope.groupby.groupStrs[symbolicExprStr(scalar)]; ok {
+		exprStr := symbolicExprStr(scalar)
+		if col, ok := inScope.groupby.groupStrs[exprStr]; ok {
 			// We pass aggOutScope as the input scope because it contains all of
 			// the aggregates and grouping columns that are available for projection.
 			// finishBuildScalarRef wraps projected columns in a variable expression
@@ -67,6 +68,12 @@ func (b *Builder) buildScalar(
 			// necessary.
 			return b.finishBuildScalarRef(col, inScope.groupby.aggOutScope, outScope, outCol, colRefs)
 		}
+		if typ, ok := inScope.groupby.rolledUpStrs[exprStr]; ok {
+			// This expression is grouped on by a different grouping set of a
+			// GROUPING SETS, ROLLUP or CUBE clause, so it is NULL in the rows
+			// produced by the grouping set being built.
+			return b.finishBuildScalar(scalar, b.factory.ConstructNull(typ), inScope, outScope, outCol)
+		}
 	}
 
 	switch t := scalar.(type) {
@@ -293,6 +300,9 @@ func (b *Builder) buildScalar(
 	case *tree.FuncExpr:
 		return b.buildFunction(t, inScope, outScope, outCol, colRefs)
 
+	case *tree.GroupingExpr:
+		out = b.buildGroupingFunc(t, inScope)
+
 	case *tree.IfExpr:
 		valType := t.ResolvedType()
 		input := b.buildScalar(t.Cond.(tree.TypedExpr), inScope, nil, nil, colRefs)
@@ -705,6 +715,8 @@ func (b *Builder) constructComparison(
 			return b.factory.ConstructBBoxIntersects(left, right)
 		}
 		return b.factory.ConstructOverlaps(left, right)
+	case tree.TSMatches:
+		return b.factory.ConstructTSMatches(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled comparison operator: %s", log.Safe(cmp.Operator)))
 }
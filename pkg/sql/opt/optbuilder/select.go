@@ -11,6 +11,7 @@
 package optbuilder
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -110,6 +111,9 @@ func (b *Builder) buildDataSource(
 
 		switch t := ds.(type) {
 		case cat.Table:
+			if indexFlags == nil {
+				indexFlags = b.indexFlagsFromStatementHints(t)
+			}
 			tabMeta := b.addTable(t, &resName)
 			return b.buildScan(
 				tabMeta,
@@ -410,6 +414,23 @@ func (b *Builder) buildScanFromTableRef(
 	return b.buildScan(tabMeta, ordinals, indexFlags, locking, inScope)
 }
 
+// indexFlagsFromStatementHints returns the index flags that force a scan of
+// the given table to use the index named by a statement hint, or nil if there
+// is no such hint. Hints that name an index that does not exist (e.g. because
+// it was dropped after the hint was added) are ignored.
+func (b *Builder) indexFlagsFromStatementHints(tab cat.Table) *tree.IndexFlags {
+	idxName, ok := b.StatementHints.Index(tab.Name())
+	if !ok || tab.IsVirtualTable() {
+		return nil
+	}
+	for i := 0; i < tab.IndexCount(); i++ {
+		if tab.Index(i).Name() == idxName {
+			return &tree.IndexFlags{Index: tree.UnrestrictedName(idxName)}
+		}
+	}
+	return nil
+}
+
 // addTable adds a table to the metadata and returns the TableMeta. The table
 // name is passed separately in order to preserve knowledge of whether the
 // catalog and schema names were explicitly specified.
@@ -1076,6 +1097,26 @@ func (b *Builder) buildSelectClause(
 	b.processWindowDefs(sel, fromScope)
 	b.buildWhere(sel.Where, fromScope)
 
+	if hasGroupingSets(sel.GroupBy) {
+		return b.buildGroupingSets(sel, orderBy, locking, desiredTypes, inScope, fromScope)
+	}
+	return b.finishBuildSelectClause(sel, nil /* groupingSetExprs */, orderBy, locking, desiredTypes, fromScope)
+}
+
+// finishBuildSelectClause builds the remainder of the given select clause
+// (everything except for the FROM and WHERE clauses) on top of fromScope.
+//
+// If sel is one of the grouping sets of a GROUPING SETS, ROLLUP or CUBE clause,
+// groupingSetExprs contains the GROUP BY expressions of all the grouping sets
+// (see buildGroupingSets).
+func (b *Builder) finishBuildSelectClause(
+	sel *tree.SelectClause,
+	groupingSetExprs tree.Exprs,
+	orderBy tree.OrderBy,
+	locking lockingSpec,
+	desiredTypes []*types.T,
+	fromScope *scope,
+) (outScope *scope) {
 	projectionsScope := fromScope.replace()
 
 	// This is where the magic happens. When this call reaches an aggregate
@@ -1089,14 +1130,21 @@ func (b *Builder) buildSelectClause(
 	havingExpr := b.analyzeHaving(sel.Having, fromScope)
 	orderByScope := b.analyzeOrderBy(orderBy, fromScope, projectionsScope, tree.RejectGenerators)
 	distinctOnScope := b.analyzeDistinctOnArgs(sel.DistinctOn, fromScope, projectionsScope)
+	if groupingSetExprs != nil && len(fromScope.windows) > 0 {
+		// Window functions would need to be computed over the rows of all the
+		// grouping sets, but each grouping set is built separately.
+		panic(unimplemented.New("grouping sets with window functions",
+			"window functions are not supported with GROUPING SETS, ROLLUP or CUBE"))
+	}
 
 	var having opt.ScalarExpr
-	needsAgg := b.needsAggregation(sel, fromScope)
+	// Every grouping set is an aggregation, even the empty one.
+	needsAgg := groupingSetExprs != nil || b.needsAggregation(sel, fromScope)
 	if needsAgg {
 		// Grouping columns must be built before building the projection list so
 		// we can check that any column references that appear in the SELECT list
 		// outside of aggregate functions are present in the grouping list.
-		b.buildGroupingColumns(sel, projectionsScope, fromScope)
+		b.buildGroupingColumns(sel, groupingSetExprs, projectionsScope, fromScope)
 		having = b.buildHaving(havingExpr, fromScope)
 	}
 
@@ -1322,10 +1370,13 @@ func (b *Builder) buildFromWithLateral(
 // validateAsOf ensures that any AS OF SYSTEM TIME timestamp is consistent with
 // that of the root statement.
 func (b *Builder) validateAsOf(asOf tree.AsOfClause) {
-	ts, err := tree.EvalAsOfTimestamp(b.ctx, asOf, b.semaCtx, b.evalCtx)
+	asOfSystemTime, err := tree.EvalAsOfTimestamp(
+		b.ctx, asOf, b.semaCtx, b.evalCtx, tree.EvalAsOfTimestampOptionAllowBoundedStaleness,
+	)
 	if err != nil {
 		panic(err)
 	}
+	ts := asOfSystemTime.Timestamp
 
 	if b.semaCtx.AsOfTimestamp == nil {
 		panic(pgerror.Newf(pgcode.Syntax,
@@ -1390,8 +1441,12 @@ func (b *Builder) validateLockingInFrom(
 		case tree.LockWaitBlock:
 			// Default. Block on conflicting locks.
 		case tree.LockWaitSkip:
-			panic(unimplementedWithIssueDetailf(40476, "",
-				"SKIP LOCKED lock wait policy is not supported"))
+			// Skip rows that are locked by other transactions.
+			if !b.evalCtx.Settings.Version.IsActive(b.ctx, clusterversion.SkipLockedWaitPolicy) {
+				panic(pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+					"SKIP LOCKED requires all nodes to be upgraded to %s",
+					clusterversion.ByKey(clusterversion.SkipLockedWaitPolicy)))
+			}
 		case tree.LockWaitError:
 			// Raise an error on conflicting locks.
 		default:
@@ -0,0 +1,151 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/optgen/lang"
+)
+
+// execPlanGistGen generates the PlanGistFactory, which encodes the operators
+// of a plan (along with the tables, indexes and join types they use) into a
+// compact "plan gist", and the decoder for those gists.
+type execPlanGistGen struct {
+	compiled *lang.CompiledExpr
+	w        *matchWriter
+}
+
+func (g *execPlanGistGen) generate(compiled *lang.CompiledExpr, w io.Writer) {
+	g.compiled = compiled
+	g.w = &matchWriter{writer: w}
+
+	g.w.write("package explain\n\n")
+
+	g.w.nestIndent("import (\n")
+	g.w.writeIndent("\"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo\"\n")
+	g.w.writeIndent("\"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb\"\n")
+	g.w.writeIndent("\"github.com/cockroachdb/cockroach/pkg/sql/opt\"\n")
+	g.w.writeIndent("\"github.com/cockroachdb/cockroach/pkg/sql/opt/cat\"\n")
+	g.w.writeIndent("\"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint\"\n")
+	g.w.writeIndent("\"github.com/cockroachdb/cockroach/pkg/sql/opt/exec\"\n")
+	g.w.writeIndent("\"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedexpr\"\n")
+	g.w.writeIndent("\"github.com/cockroachdb/cockroach/pkg/sql/sem/tree\"\n")
+	g.w.writeIndent("\"github.com/cockroachdb/cockroach/pkg/sql/types\"\n")
+	g.w.writeIndent("\"github.com/cockroachdb/errors\"\n")
+	g.w.unnest(")\n")
+
+	g.genPlanGistFactory()
+	g.genDecodeOperatorBody()
+}
+
+func (g *execPlanGistGen) genPlanGistFactory() {
+	for _, define := range g.compiled.Defines {
+		g.w.write("\n")
+		g.w.nest("func (f *PlanGistFactory) Construct%s(\n", define.Name)
+		for _, field := range define.Fields {
+			generateComments(g.w.writer, field.Comments, string(field.Name), unTitle(string(field.Name)))
+			g.w.writeIndent("%s %s,\n", unTitle(string(field.Name)), field.Type)
+		}
+		g.w.write(") (exec.Node, error) {\n")
+		g.w.writeIndent("f.encodeOperator(%sOp)\n", unTitle(string(define.Name)))
+		for i, field := range define.Fields {
+			name := unTitle(string(field.Name))
+			switch field.Type {
+			case "cat.Table":
+				// A table that is immediately followed by one of its indexes is
+				// encoded along with the index.
+				if !isTableOfNextIndex(define, i) {
+					g.w.writeIndent("f.encodeTable(%s)\n", name)
+				}
+			case "cat.Index":
+				g.w.writeIndent("f.encodeIndex(%s)\n", name)
+			case "descpb.JoinType", "tree.UnionType":
+				g.w.writeIndent("f.encodeByte(byte(%s))\n", name)
+			}
+		}
+		g.w.nestIndent("return f.wrappedFactory.Construct%s(\n", define.Name)
+		for _, field := range define.Fields {
+			g.w.writeIndent("%s,\n", unTitle(string(field.Name)))
+		}
+		g.w.unnest(")\n")
+		g.w.unnest("}\n")
+	}
+}
+
+func (g *execPlanGistGen) genDecodeOperatorBody() {
+	g.w.write("\n")
+	g.w.write("// decodeOperatorBody decodes the fields that were encoded for the given\n")
+	g.w.write("// node's operator, and pops its children off the stack of decoded nodes.\n")
+	g.w.nest("func (d *planGistDecoder) decodeOperatorBody(n *gistNode) error {\n")
+	g.w.writeIndent("switch n.op {\n")
+	for _, define := range g.compiled.Defines {
+		g.w.nestIndent("case %sOp:\n", unTitle(string(define.Name)))
+		numChildren := 0
+		for i, field := range define.Fields {
+			switch field.Type {
+			case "exec.Node":
+				numChildren++
+			case "cat.Table":
+				if !isTableOfNextIndex(define, i) {
+					g.w.writeIndent("d.decodeTable(n, %q)\n", fieldLabel(string(field.Name)))
+				}
+			case "cat.Index":
+				label := fieldLabel(string(field.Name))
+				if i > 0 && isTableOfNextIndex(define, i-1) {
+					label = fieldLabel(string(define.Fields[i-1].Name))
+				}
+				g.w.writeIndent("d.decodeIndex(n, %q)\n", label)
+			case "descpb.JoinType":
+				g.w.writeIndent("n.joinType = descpb.JoinType(d.decodeByte())\n")
+			case "tree.UnionType":
+				g.w.writeIndent("n.unionType = tree.UnionType(d.decodeByte())\n")
+			}
+		}
+		// ScanBuffer is an exception here, the node it references is not a
+		// "child".
+		if define.Name != "ScanBuffer" && numChildren > 0 {
+			g.w.writeIndent("d.popChildren(n, %d)\n", numChildren)
+		}
+		g.w.nesting--
+	}
+	g.w.nestIndent("default:\n")
+	g.w.writeIndent("return errors.Errorf(\"invalid operator %%d in plan gist\", n.op)\n")
+	g.w.nesting--
+	g.w.writeIndent("}\n")
+	g.w.writeIndent("return d.err\n")
+	g.w.unnest("}\n")
+}
+
+// isTableOfNextIndex returns true if the i-th field of the define is a table
+// that is immediately followed by an index field.
+func isTableOfNextIndex(define *lang.DefineExpr, i int) bool {
+	return define.Fields[i].Type == "cat.Table" &&
+		i+1 < len(define.Fields) && define.Fields[i+1].Type == "cat.Index"
+}
+
+// fieldLabel converts the name of a field to the label used for it in the
+// decoded plan gist, e.g. "LeftTable" becomes "left table".
+func fieldLabel(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
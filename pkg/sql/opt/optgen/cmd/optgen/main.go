@@ -98,7 +98,7 @@ func (g *optgen) run(args ...string) bool {
 	case "ops":
 	case "rulenames":
 
-	case "execfactory", "execexplain":
+	case "execfactory", "execexplain", "execplangist":
 		runValidate = false
 
 	default:
@@ -189,6 +189,10 @@ func (g *optgen) run(args ...string) bool {
 	case "execexplain":
 		var gen execExplainGen
 		err = g.generate(compiled, gen.generate)
+
+	case "execplangist":
+		var gen execPlanGistGen
+		err = g.generate(compiled, gen.generate)
 	}
 
 	if err != nil {
@@ -201,6 +201,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"OrderingChoice":    {fullName: "physical.OrderingChoice", passByVal: true},
 		"TupleOrdinal":      {fullName: "memo.TupleOrdinal", passByVal: true},
 		"ScanLimit":         {fullName: "memo.ScanLimit", passByVal: true},
+		"SkipScanPrefix":    {fullName: "memo.SkipScanPrefix", passByVal: true},
 		"ScanFlags":         {fullName: "memo.ScanFlags", passByVal: true},
 		"JoinFlags":         {fullName: "memo.JoinFlags", passByVal: true},
 		"WindowFrame":       {fullName: "memo.WindowFrame", passByVal: true},
@@ -73,6 +73,13 @@ func ScanPrivateCanProvide(
 		if s.HardLimit.Reverse() {
 			direction = rev
 		}
+	} else if s.SkipScanPrefix.IsSet() {
+		// Similarly, a skip scan returns the first row of each distinct prefix in
+		// the scan direction, so the direction affects the results.
+		direction = fwd
+		if s.SkipScanPrefix.Reverse() {
+			direction = rev
+		}
 	} else if s.Flags.Direction != 0 {
 		direction = fwd
 		if s.Flags.Direction == tree.Descending {
@@ -26,19 +26,35 @@ func (tc *Catalog) SetZoneConfig(stmt *tree.SetZoneConfig) *zonepb.ZoneConfig {
 	tc.qualifyTableName(&tabName)
 	tab := tc.Table(&tabName)
 
-	// Handle special case of primary index.
-	if stmt.TableOrIndex.Index == "" {
-		tab.Indexes[0].IdxZone = makeZoneConfig(stmt.Options)
-		return tab.Indexes[0].IdxZone
+	// Find the index. The zone applies to the primary index if no index is
+	// specified.
+	idx := tab.Indexes[0]
+	if stmt.TableOrIndex.Index != "" {
+		idx = nil
+		for _, i := range tab.Indexes {
+			if i.IdxName == string(stmt.TableOrIndex.Index) {
+				idx = i
+				break
+			}
+		}
+		if idx == nil {
+			panic(fmt.Errorf("\"%q\" is not an index", stmt.TableOrIndex.Index))
+		}
 	}
 
-	for _, idx := range tab.Indexes {
-		if idx.IdxName == string(stmt.TableOrIndex.Index) {
-			idx.IdxZone = makeZoneConfig(stmt.Options)
-			return idx.IdxZone
+	// Handle the case of a partition of the index.
+	if stmt.Partition != "" {
+		for _, p := range idx.getPartitions() {
+			if p.name == string(stmt.Partition) {
+				p.zone = makeZoneConfig(stmt.Options)
+				return p.zone
+			}
 		}
+		panic(fmt.Errorf("%q is not a partition of index %q", stmt.Partition, idx.IdxName))
 	}
-	panic(fmt.Errorf("\"%q\" is not an index", stmt.TableOrIndex.Index))
+
+	idx.IdxZone = makeZoneConfig(stmt.Options)
+	return idx.IdxZone
 }
 
 // makeZoneConfig constructs a ZoneConfig from options provided to the CONFIGURE
@@ -785,6 +785,10 @@ type Index struct {
 	// to implement PartitionByListPrefixes.
 	partitionBy *tree.PartitionBy
 
+	// partitions stores the PARTITION BY LIST partitions of the index. It is
+	// built lazily from partitionBy; see getPartitions.
+	partitions []*Partition
+
 	// predicate is the partial index predicate expression, if it exists.
 	predicate string
 
@@ -885,61 +889,93 @@ func (ti *Index) Predicate() (string, bool) {
 
 // PartitionByListPrefixes is part of the cat.Index interface.
 func (ti *Index) PartitionByListPrefixes() []tree.Datums {
-	ctx := context.Background()
+	var res []tree.Datums
+	for _, p := range ti.getPartitions() {
+		res = append(res, p.datums...)
+	}
+	return res
+}
+
+// PartitionCount is part of the cat.Index interface.
+func (ti *Index) PartitionCount() int {
+	return len(ti.getPartitions())
+}
+
+// Partition is part of the cat.Index interface.
+func (ti *Index) Partition(i int) cat.Partition {
+	return ti.getPartitions()[i]
+}
+
+// getPartitions returns the PARTITION BY LIST partitions of the index, building
+// them from the partitioning clause the first time it is called.
+func (ti *Index) getPartitions() []*Partition {
 	p := ti.partitionBy
-	if p == nil {
+	if p == nil || len(p.List) == 0 {
 		return nil
 	}
-	if len(p.List) == 0 {
-		return nil
+	if ti.partitions != nil {
+		return ti.partitions
 	}
-	var res []tree.Datums
-	semaCtx := tree.MakeSemaContext()
-	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
 	for i := range p.Fields {
 		if i >= len(ti.Columns) || p.Fields[i] != ti.Columns[i].ColName() {
 			panic("partition by columns must be a prefix of the index columns")
 		}
 	}
+	ti.partitions = make([]*Partition, len(p.List))
 	for i := range p.List {
-		// Exprs contains a list of values.
-		for _, e := range p.List[i].Exprs {
-			var vals []tree.Expr
-			switch t := e.(type) {
-			case *tree.Tuple:
-				vals = t.Exprs
-			default:
-				vals = []tree.Expr{e}
-			}
+		ti.partitions[i] = &Partition{
+			name:   string(p.List[i].Name),
+			index:  ti,
+			datums: ti.partitionDatums(&p.List[i]),
+		}
+	}
+	return ti.partitions
+}
+
+// partitionDatums evaluates the values of the given PARTITION BY LIST
+// partition.
+func (ti *Index) partitionDatums(p *tree.ListPartition) []tree.Datums {
+	ctx := context.Background()
+	var res []tree.Datums
+	semaCtx := tree.MakeSemaContext()
+	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	// Exprs contains a list of values.
+	for _, e := range p.Exprs {
+		var vals []tree.Expr
+		switch t := e.(type) {
+		case *tree.Tuple:
+			vals = t.Exprs
+		default:
+			vals = []tree.Expr{e}
+		}
 
-			// Cut off at DEFAULT, if present.
-			for i := range vals {
-				if _, ok := vals[i].(tree.DefaultVal); ok {
-					vals = vals[:i]
-				}
+		// Cut off at DEFAULT, if present.
+		for i := range vals {
+			if _, ok := vals[i].(tree.DefaultVal); ok {
+				vals = vals[:i]
 			}
-			if len(vals) == 0 {
-				continue
+		}
+		if len(vals) == 0 {
+			continue
+		}
+		d := make(tree.Datums, len(vals))
+		for i := range vals {
+			c := tree.CastExpr{Expr: vals[i], Type: ti.Columns[i].DatumType()}
+			cTyped, err := c.TypeCheck(ctx, &semaCtx, types.Any)
+			if err != nil {
+				panic(err)
 			}
-			d := make(tree.Datums, len(vals))
-			for i := range vals {
-				c := tree.CastExpr{Expr: vals[i], Type: ti.Columns[i].DatumType()}
-				cTyped, err := c.TypeCheck(ctx, &semaCtx, types.Any)
-				if err != nil {
-					panic(err)
-				}
-				d[i], err = cTyped.Eval(&evalCtx)
-				if err != nil {
-					panic(err)
-				}
+			d[i], err = cTyped.Eval(&evalCtx)
+			if err != nil {
+				panic(err)
 			}
+		}
 
-			// TODO(radu): split into multiple prefixes if Subpartition is also by list.
-			// Note that this functionality should be kept in sync with the real catalog
-			// implementation (opt_catalog.go).
+		// TODO(radu): split into multiple prefixes if Subpartition is also by list.
+		// Note that this functionality should be kept in sync with the real catalog
+		// implementation (opt_catalog.go).
 
-			res = append(res, d)
-		}
+		res = append(res, d)
 	}
 	return res
 }
@@ -974,6 +1010,37 @@ func (ti *Index) Version() descpb.IndexDescriptorVersion {
 	return ti.version
 }
 
+// Partition implements the cat.Partition interface for testing purposes.
+type Partition struct {
+	name   string
+	index  *Index
+	datums []tree.Datums
+
+	// zone is the zone of the partition, if one was explicitly assigned.
+	// Otherwise the partition inherits the zone of its index.
+	zone *zonepb.ZoneConfig
+}
+
+var _ cat.Partition = &Partition{}
+
+// Name is part of the cat.Partition interface.
+func (p *Partition) Name() string {
+	return p.name
+}
+
+// Zone is part of the cat.Partition interface.
+func (p *Partition) Zone() cat.Zone {
+	if p.zone == nil {
+		return p.index.IdxZone
+	}
+	return p.zone
+}
+
+// PartitionByListPrefixes is part of the cat.Partition interface.
+func (p *Partition) PartitionByListPrefixes() []tree.Datums {
+	return p.datums
+}
+
 // TableStat implements the cat.TableStatistic interface for testing purposes.
 type TableStat struct {
 	js stats.JSONStatistic
@@ -116,6 +116,21 @@ const (
 	// See joinreader.go.
 	joinReaderBatchSize = 100.0
 
+	// skipScanSeekCost is the cost of seeking to the next distinct index prefix
+	// during a skip scan. Each seek is issued as a separate KV request, so it is
+	// much more expensive than reading the next row of a sequential scan; a skip
+	// scan is therefore only preferred when the prefix has few distinct values
+	// compared to the number of rows in the index.
+	skipScanSeekCost = 10 * randIOCostFactor
+
+	// localityOptimizedScanCostDivisor is the factor by which the cost of each
+	// scan in a locality optimized search is reduced. Together, the local and
+	// remote scans read the same spans as the single scan they replace, so
+	// without the reduction the locality optimized search would never be
+	// cheaper. Since the remote scan is usually not executed, the reduction
+	// reflects the cross-region latency that is avoided.
+	localityOptimizedScanCostDivisor = 3
+
 	// In the case of a limit hint, a scan will read this multiple of the expected
 	// number of rows. See scanNode.limitHint.
 	scanSoftLimitMultiplier = 2.0
@@ -465,6 +480,9 @@ func (c *coster) ComputeCost(candidate memo.RelExpr, required *physical.Required
 		opt.UnionAllOp, opt.IntersectAllOp, opt.ExceptAllOp:
 		cost = c.computeSetCost(candidate)
 
+	case opt.LocalityOptimizedSearchOp:
+		cost = c.computeLocalityOptimizedSearchCost(candidate.(*memo.LocalityOptimizedSearchExpr))
+
 	case opt.GroupByOp, opt.ScalarGroupByOp, opt.DistinctOnOp, opt.EnsureDistinctOnOp,
 		opt.UpsertDistinctOnOp, opt.EnsureUpsertDistinctOnOp:
 		cost = c.computeGroupingCost(candidate, required)
@@ -575,6 +593,12 @@ func (c *coster) computeScanCost(scan *memo.ScanExpr, required *physical.Require
 		}
 	}
 
+	if scan.SkipScanPrefix.IsSet() {
+		// Each row returned by a skip scan requires a seek to the next distinct
+		// prefix.
+		perRowCost += skipScanSeekCost
+	}
+
 	numSpans := 1
 	if scan.Constraint != nil {
 		numSpans = scan.Constraint.Spans.Count()
@@ -594,7 +618,15 @@ func (c *coster) computeScanCost(scan *memo.ScanExpr, required *physical.Require
 	if scan.IsUnfiltered(c.mem.Metadata()) {
 		baseCost += cpuCostFactor
 	}
-	return baseCost + memo.Cost(rowCount)*(seqIOCostFactor+perRowCost)
+	cost := baseCost + memo.Cost(rowCount)*(seqIOCostFactor+perRowCost)
+
+	// The scans of a locality optimized search are discounted, since the
+	// remote scan is only executed if the local scan does not find all rows.
+	// See localityOptimizedScanCostDivisor.
+	if scan.LocalityOptimized {
+		cost /= localityOptimizedScanCostDivisor
+	}
+	return cost
 }
 
 func (c *coster) computeSelectCost(sel *memo.SelectExpr) memo.Cost {
@@ -973,6 +1005,14 @@ func (c *coster) computeSetCost(set memo.RelExpr) memo.Cost {
 	return cost
 }
 
+func (c *coster) computeLocalityOptimizedSearchCost(
+	los *memo.LocalityOptimizedSearchExpr,
+) memo.Cost {
+	// Add the CPU cost of emitting the rows. No other work is required, since
+	// the rows of the Local and Remote inputs are passed through unchanged.
+	return memo.Cost(los.Relational().Stats.RowCount) * cpuCostFactor
+}
+
 func (c *coster) computeGroupingCost(grouping memo.RelExpr, required *physical.Required) memo.Cost {
 	// Start with some extra fixed overhead, since the grouping operators have
 	// setup overhead that is greater than other operators like Project. This
@@ -12,7 +12,9 @@ package xform
 
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/ordering"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/errors"
 )
@@ -150,6 +152,139 @@ func (c *CustomFuncs) GenerateStreamingGroupBy(
 	}
 }
 
+// GenerateSkipScans generates a variant of a GroupBy or DistinctOn expression
+// whose input Scan is replaced with a skip scan over the distinct prefixes of
+// the scanned index. See the GenerateSkipScans rule.
+func (c *CustomFuncs) GenerateSkipScans(
+	grp memo.RelExpr,
+	op opt.Operator,
+	input memo.RelExpr,
+	scanPrivate *memo.ScanPrivate,
+	aggs memo.AggregationsExpr,
+	private *memo.GroupingPrivate,
+) {
+	if private.GroupingCols.Empty() ||
+		scanPrivate.HardLimit.IsSet() ||
+		scanPrivate.SkipScanPrefix.IsSet() ||
+		scanPrivate.InvertedConstraint != nil ||
+		scanPrivate.IsLocking() {
+		return
+	}
+	md := c.e.mem.Metadata()
+	tab := md.Table(scanPrivate.Table)
+	if tab.IsVirtualTable() {
+		return
+	}
+	index := tab.Index(scanPrivate.Index)
+	if index.IsInverted() {
+		return
+	}
+
+	// Skip scans aren't currently equipped to produce system columns.
+	foundSystemCol := false
+	scanPrivate.Cols.ForEach(func(colID opt.ColumnID) {
+		if tab.Column(scanPrivate.Table.ColumnOrdinal(colID)).Kind() == cat.System {
+			foundSystemCol = true
+		}
+	})
+	if foundSystemCol {
+		return
+	}
+
+	// Find the shortest prefix of the index key that contains all the grouping
+	// columns. Every column in the prefix must be produced by the scan and must
+	// be functionally determined by the grouping columns, so that each distinct
+	// prefix corresponds to exactly one group.
+	groupingClosure := input.Relational().FuncDeps.ComputeClosure(private.GroupingCols)
+	var prefixCols opt.ColSet
+	prefixLen := 0
+	for ; !private.GroupingCols.SubsetOf(prefixCols); prefixLen++ {
+		if prefixLen >= index.KeyColumnCount() {
+			return
+		}
+		col := scanPrivate.Table.IndexColumnID(index, prefixLen)
+		if !scanPrivate.Cols.Contains(col) || !groupingClosure.Contains(col) {
+			return
+		}
+		prefixCols.Add(col)
+	}
+	if prefixLen == index.KeyColumnCount() {
+		// Every group has a single row, so there is nothing to skip.
+		return
+	}
+
+	// The skip scan only returns the first row of each group in the scan
+	// direction, so every aggregate must be computable from that row alone.
+	// Determine the ordering within each group that the first row must satisfy.
+	var required physical.OrderingChoice
+	required.Optional = prefixCols.Union(private.Ordering.Optional)
+	required.Columns = private.Ordering.Columns
+	foundMinMax := false
+	for i := range aggs {
+		agg := aggs[i].Agg
+		switch agg.Op() {
+		case opt.ConstAggOp, opt.FirstAggOp:
+			// ConstAgg returns the same value for every row of the group. FirstAgg
+			// returns the first row according to the intra-group ordering, which is
+			// accounted for by the required ordering.
+
+		case opt.AnyNotNullAggOp:
+			// AnyNotNullAgg returns the value of any row with a non-NULL value, so
+			// the first row is sufficient only if the column is never NULL.
+			if !c.IsColNotNull(agg.Child(0).(*memo.VariableExpr).Col, input) {
+				return
+			}
+
+		case opt.MinOp, opt.MaxOp:
+			// The first row must have the minimum or maximum value of the group.
+			// Only a single Min or Max aggregate is supported, and only if there is
+			// no other intra-group ordering. NULL values sort first, so a Min
+			// aggregate requires the column to be NOT NULL (see the header comment
+			// for the ReplaceMinWithLimit rule).
+			if foundMinMax || len(private.Ordering.Columns) > 0 {
+				return
+			}
+			col := agg.Child(0).(*memo.VariableExpr).Col
+			if agg.Op() == opt.MinOp && !c.IsColNotNull(col, input) {
+				return
+			}
+			required = required.Copy()
+			required.AppendCol(col, agg.Op() == opt.MaxOp /* descending */)
+			foundMinMax = true
+
+		default:
+			return
+		}
+	}
+
+	ok, reverse := ordering.ScanPrivateCanProvide(md, scanPrivate, &required)
+	if !ok {
+		return
+	}
+
+	newScanPrivate := *scanPrivate
+	newScanPrivate.SkipScanPrefix = memo.MakeSkipScanPrefix(prefixLen, reverse)
+	newInput := c.e.f.ConstructScan(&newScanPrivate)
+
+	switch op {
+	case opt.GroupByOp:
+		newExpr := memo.GroupByExpr{
+			Input:           newInput,
+			Aggregations:    aggs,
+			GroupingPrivate: *private,
+		}
+		c.e.mem.AddGroupByToGroup(&newExpr, grp)
+
+	case opt.DistinctOnOp:
+		newExpr := memo.DistinctOnExpr{
+			Input:           newInput,
+			Aggregations:    aggs,
+			GroupingPrivate: *private,
+		}
+		c.e.mem.AddDistinctOnToGroup(&newExpr, grp)
+	}
+}
+
 // OtherAggsAreConst returns true if all items in the given aggregate list
 // contain ConstAgg functions, except for the "except" item. The ConstAgg
 // functions will always return the same value, as long as there is at least
@@ -54,6 +54,12 @@ func (c *CustomFuncs) CanLimitFilteredScan(
 		return false
 	}
 
+	if scanPrivate.SkipScanPrefix.IsSet() {
+		// A skip scan only returns a subset of the rows of the scanned spans, so
+		// the limit cannot be applied to the underlying spans.
+		return false
+	}
+
 	if scanPrivate.Constraint == nil && !scanPrivate.UsesPartialIndex(c.e.mem.Metadata()) {
 		// This is not a constrained scan nor a partial index scan, so skip it.
 		// The GenerateLimitedScans rule is responsible for limited
@@ -287,6 +287,12 @@ func (mf *memoFormatter) formatPrivate(e opt.Expr, physProps *physical.Required)
 		if t.HardLimit.IsSet() {
 			fmt.Fprintf(mf.buf, ",lim=%s", t.HardLimit)
 		}
+		if t.SkipScanPrefix.IsSet() {
+			fmt.Fprintf(mf.buf, ",skip=%s", t.SkipScanPrefix)
+		}
+		if t.LocalityOptimized {
+			fmt.Fprintf(mf.buf, ",locality-optimized")
+		}
 
 	case *memo.IndexJoinExpr:
 		fmt.Fprintf(mf.buf, ",cols=%s", t.Cols)
@@ -11,9 +11,14 @@
 package xform
 
 import (
+	"math"
+
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
 )
 
 // GenerateIndexScans enumerates all non-inverted secondary indexes on the given
@@ -69,3 +74,244 @@ func (c *CustomFuncs) GenerateIndexScans(grp memo.RelExpr, scanPrivate *memo.Sca
 		sb.build(grp)
 	})
 }
+
+// regionKey is the key used for the region tier of a node locality, as well as
+// for the region in zone constraints and lease preferences.
+const regionKey = "region"
+
+// CanMaybeGenerateLocalityOptimizedScan returns true if it may be possible to
+// generate a locality optimized scan from the given scan private.
+// CanMaybeGenerateLocalityOptimizedScan performs simple checks that are
+// inexpensive to execute and can filter out cases where the optimization
+// definitely cannot apply. See the comment above the
+// GenerateLocalityOptimizedScan rule for details.
+func (c *CustomFuncs) CanMaybeGenerateLocalityOptimizedScan(scanPrivate *memo.ScanPrivate) bool {
+	// The scan must be constrained, and it must not already be limited or
+	// otherwise altered.
+	if scanPrivate.Constraint == nil || scanPrivate.InvertedConstraint != nil ||
+		scanPrivate.HardLimit.IsSet() || scanPrivate.SkipScanPrefix.IsSet() ||
+		scanPrivate.IsLocking() || scanPrivate.LocalityOptimized {
+		return false
+	}
+
+	// The optimization only applies to indexes with at least two partitions,
+	// since there must be at least one local and one remote partition.
+	tab := c.e.mem.Metadata().Table(scanPrivate.Table)
+	if tab.Index(scanPrivate.Index).PartitionCount() < 2 {
+		return false
+	}
+
+	// There must be at least two spans, since each span must be either local or
+	// remote.
+	if scanPrivate.Constraint.Spans.Count() < 2 {
+		return false
+	}
+
+	// The gateway region must be known.
+	_, ok := c.e.evalCtx.Locality.Find(regionKey)
+	return ok
+}
+
+// GenerateLocalityOptimizedScan generates a locality optimized search on top of
+// a scan whose spans target both local and remote partitions of a partitioned
+// index. The local spans are scanned first, and the remote spans are only
+// scanned if the local scan does not return the maximum number of rows that the
+// scan's group can produce. See the comment above the
+// GenerateLocalityOptimizedScan rule for details.
+func (c *CustomFuncs) GenerateLocalityOptimizedScan(
+	grp memo.RelExpr, scanPrivate *memo.ScanPrivate,
+) {
+	// The maximum number of rows returned by the group must be known, since it
+	// determines when the remote spans can be skipped.
+	if grp.Relational().Cardinality.Max == math.MaxUint32 {
+		return
+	}
+
+	tabMeta := c.e.mem.Metadata().TableMeta(scanPrivate.Table)
+	index := tabMeta.Table.Index(scanPrivate.Index)
+	localRegion, _ := c.e.evalCtx.Locality.Find(regionKey)
+
+	// Determine which partitions are local to the gateway region.
+	var localPartitions util.FastIntSet
+	for i, n := 0, index.PartitionCount(); i < n; i++ {
+		if isZoneLocal(index.Partition(i).Zone(), localRegion) {
+			localPartitions.Add(i)
+		}
+	}
+	if localPartitions.Empty() || localPartitions.Len() == index.PartitionCount() {
+		// All partitions are local or all partitions are remote, so there is no
+		// benefit to splitting the scan.
+		return
+	}
+
+	// Split the spans into local and remote spans.
+	cons := scanPrivate.Constraint
+	var localSpans, remoteSpans constraint.Spans
+	localSpans.Alloc(cons.Spans.Count())
+	remoteSpans.Alloc(cons.Spans.Count())
+	for i, n := 0, cons.Spans.Count(); i < n; i++ {
+		span := cons.Spans.Get(i)
+		if ord, ok := c.findSpanPartition(index, span); ok && localPartitions.Contains(ord) {
+			localSpans.Append(span)
+		} else {
+			remoteSpans.Append(span)
+		}
+	}
+	if localSpans.Count() == 0 || remoteSpans.Count() == 0 {
+		return
+	}
+
+	// Create the local and remote scans. Each scan uses a duplicate of the
+	// original table so that its output columns are distinct from the output
+	// columns of the group.
+	localScan, localCols := c.makeLocalityOptimizedScan(scanPrivate, &localSpans)
+	remoteScan, remoteCols := c.makeLocalityOptimizedScan(scanPrivate, &remoteSpans)
+
+	locOptSearch := memo.LocalityOptimizedSearchExpr{
+		Local:  localScan,
+		Remote: remoteScan,
+		SetPrivate: memo.SetPrivate{
+			LeftCols:  localCols,
+			RightCols: remoteCols,
+			OutCols:   opt.ColSetToList(scanPrivate.Cols),
+		},
+	}
+	c.e.mem.AddLocalityOptimizedSearchToGroup(&locOptSearch, grp)
+}
+
+// makeLocalityOptimizedScan constructs a scan over a duplicate of the table in
+// the given scan private, constrained to the given subset of the spans of the
+// original constraint. It also returns the output columns of the new scan, in
+// the same order as the columns of the original scan (sorted by column ID).
+func (c *CustomFuncs) makeLocalityOptimizedScan(
+	scanPrivate *memo.ScanPrivate, spans *constraint.Spans,
+) (memo.RelExpr, opt.ColList) {
+	newScanPrivate := c.DuplicateScanPrivate(scanPrivate)
+	newScanPrivate.PartitionConstrainedScan = scanPrivate.PartitionConstrainedScan
+	newScanPrivate.LocalityOptimized = true
+	newScanPrivate.Constraint = &constraint.Constraint{
+		Columns: scanPrivate.Constraint.Columns.RemapColumns(scanPrivate.Table, newScanPrivate.Table),
+		Spans:   *spans,
+	}
+
+	md := c.e.mem.Metadata()
+	newCols := make(opt.ColList, 0, scanPrivate.Cols.Len())
+	scanPrivate.Cols.ForEach(func(col opt.ColumnID) {
+		ord := md.ColumnMeta(col).Table.ColumnOrdinal(col)
+		newCols = append(newCols, newScanPrivate.Table.ColumnID(ord))
+	})
+	return c.e.f.ConstructScan(newScanPrivate), newCols
+}
+
+// findSpanPartition returns the ordinal of the PARTITION BY LIST partition of
+// the given index that contains all keys in the given span. If there is more
+// than one matching partition, the one with the longest prefix is returned.
+// ok is false if the span does not belong to a single partition, for example
+// because it spans multiple partitions, or because it may also include keys
+// from a partition with a longer prefix.
+func (c *CustomFuncs) findSpanPartition(
+	index cat.Index, span *constraint.Span,
+) (ord int, ok bool) {
+	start, end := span.StartKey(), span.EndKey()
+
+	// matchesPrefix returns true if the first len(prefix) columns of both the
+	// start and end key of the span are equal to the prefix values.
+	matchesPrefix := func(prefix tree.Datums) bool {
+		if start.Length() < len(prefix) || end.Length() < len(prefix) {
+			return false
+		}
+		for i := range prefix {
+			if start.Value(i).Compare(c.e.evalCtx, prefix[i]) != 0 ||
+				end.Value(i).Compare(c.e.evalCtx, prefix[i]) != 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	bestLen := -1
+	for i, n := 0, index.PartitionCount(); i < n; i++ {
+		for _, prefix := range index.Partition(i).PartitionByListPrefixes() {
+			if len(prefix) > bestLen && matchesPrefix(prefix) {
+				ord, bestLen = i, len(prefix)
+			}
+		}
+	}
+	if bestLen == -1 {
+		return 0, false
+	}
+
+	// Make sure the span cannot include keys from a partition with a longer
+	// prefix that extends the matched prefix. Such a prefix was already
+	// rejected above if the span has enough fixed columns to determine that it
+	// doesn't match.
+	for i, n := 0, index.PartitionCount(); i < n; i++ {
+		for _, prefix := range index.Partition(i).PartitionByListPrefixes() {
+			if len(prefix) > bestLen && (start.Length() < len(prefix) || end.Length() < len(prefix)) &&
+				matchesPrefix(prefix[:bestLen]) {
+				return 0, false
+			}
+		}
+	}
+	return ord, true
+}
+
+// isZoneLocal returns true if the given zone indicates that the replicas it
+// constrains will be primarily located in the given local region. Replica
+// constraints are consulted first; if they are not conclusive, the first lease
+// preference is used as a tie breaker.
+func isZoneLocal(zone cat.Zone, localRegion string) bool {
+	// Count the number of local and remote replica constraints. If all
+	// conclusive constraints agree, return early.
+	local, remote := 0, 0
+	for i, n := 0, zone.ReplicaConstraintsCount(); i < n; i++ {
+		replicaConstraint := zone.ReplicaConstraints(i)
+		for j, m := 0, replicaConstraint.ConstraintCount(); j < m; j++ {
+			if isLocal, ok := isConstraintLocal(replicaConstraint.Constraint(j), localRegion); ok {
+				if isLocal {
+					local++
+				} else {
+					remote++
+				}
+			}
+		}
+	}
+	if local > 0 && remote == 0 {
+		return true
+	}
+	if remote > 0 && local == 0 {
+		return false
+	}
+
+	// Use the lease preferences as a tie breaker. Only the first one matters,
+	// since subsequent lease preferences only apply in edge cases.
+	if zone.LeasePreferenceCount() > 0 {
+		leasePref := zone.LeasePreference(0)
+		for i, n := 0, leasePref.ConstraintCount(); i < n; i++ {
+			if isLocal, ok := isConstraintLocal(leasePref.Constraint(i), localRegion); ok {
+				return isLocal
+			}
+		}
+	}
+	return false
+}
+
+// isConstraintLocal returns isLocal=true and ok=true if the given constraint
+// requires the given local region. It returns isLocal=false and ok=true if the
+// constraint prohibits the local region or requires a different region. In all
+// other cases ok is false, since the constraint gives no information about
+// whether the constrained replicas are local or remote.
+func isConstraintLocal(constraint cat.Constraint, localRegion string) (isLocal bool, ok bool) {
+	if constraint.GetKey() != regionKey {
+		return false, false
+	}
+	if constraint.GetValue() == localRegion {
+		return constraint.IsRequired(), true
+	}
+	if constraint.IsRequired() {
+		// A remote region is required.
+		return false, true
+	}
+	// A remote region is prohibited, which does not tell us anything.
+	return false, false
+}
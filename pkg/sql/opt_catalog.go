@@ -12,6 +12,7 @@ package sql
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/indexrec"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
@@ -265,6 +267,8 @@ func getDescFromCatalogObjectForPermissions(o cat.Object) (catalog.Descriptor, e
 		return t.getDescriptorForPermissionsCheck(), nil
 	case *optTable:
 		return t.desc, nil
+	case *optHypotheticalTable:
+		return t.desc, nil
 	case *optVirtualTable:
 		return t.desc, nil
 	case *optView:
@@ -280,6 +284,8 @@ func getDescForDataSource(o cat.DataSource) (*tabledesc.Immutable, error) {
 	switch t := o.(type) {
 	case *optTable:
 		return t.desc, nil
+	case *optHypotheticalTable:
+		return t.desc, nil
 	case *optVirtualTable:
 		return t.desc, nil
 	case *optView:
@@ -413,6 +419,8 @@ func (oc *optCatalog) dataSourceForTable(
 		}
 	}
 
+	useForecasts := stats.UseStatisticsForecasts.Get(&oc.planner.execCfg.Settings.SV)
+
 	zoneConfig, err := oc.getZoneConfig(desc)
 	if err != nil {
 		return nil, err
@@ -420,11 +428,12 @@ func (oc *optCatalog) dataSourceForTable(
 
 	// Check to see if there's already a data source wrapper for this descriptor,
 	// and it was created with the same stats and zone config.
-	if ds, ok := oc.dataSources[desc]; ok && !ds.(*optTable).isStale(desc, tableStats, zoneConfig) {
+	if ds, ok := oc.dataSources[desc]; ok &&
+		!ds.(*optTable).isStale(desc, tableStats, useForecasts, zoneConfig) {
 		return ds, nil
 	}
 
-	ds, err := newOptTable(desc, oc.codec(), tableStats, zoneConfig)
+	ds, err := newOptTable(desc, oc.codec(), tableStats, useForecasts, zoneConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -579,6 +588,9 @@ type optTable struct {
 	// check that the statistics haven't changed.
 	rawStats []*stats.TableStatistic
 
+	// useForecasts is true if forecast statistics are included in stats.
+	useForecasts bool
+
 	// stats are the inlined wrappers for table statistics.
 	stats []optTableStat
 
@@ -614,13 +626,15 @@ func newOptTable(
 	desc *tabledesc.Immutable,
 	codec keys.SQLCodec,
 	stats []*stats.TableStatistic,
+	useForecasts bool,
 	tblZone *zonepb.ZoneConfig,
 ) (*optTable, error) {
 	ot := &optTable{
-		desc:     desc,
-		codec:    codec,
-		rawStats: stats,
-		zone:     tblZone,
+		desc:         desc,
+		codec:        codec,
+		rawStats:     stats,
+		useForecasts: useForecasts,
+		zone:         tblZone,
 	}
 
 	// First, determine how many columns we will potentially need.
@@ -720,6 +734,20 @@ func newOptTable(
 				idxZone = &copyZone
 			}
 		}
+		// Look up the zones of the index's partitions, if any. Partitions
+		// without a subzone of their own inherit the index zone.
+		var partZones map[string]*zonepb.ZoneConfig
+		for j := range tblZone.Subzones {
+			subzone := &tblZone.Subzones[j]
+			if subzone.IndexID == uint32(idxDesc.ID) && subzone.PartitionName != "" {
+				copyZone := subzone.Config
+				copyZone.InheritFromParent(idxZone)
+				if partZones == nil {
+					partZones = make(map[string]*zonepb.ZoneConfig)
+				}
+				partZones[subzone.PartitionName] = &copyZone
+			}
+		}
 		if idxDesc.Type == descpb.IndexDescriptor_INVERTED {
 			// The last column of an inverted index is special: in the
 			// descriptors, it looks as if the table column is part of the
@@ -744,9 +772,9 @@ func newOptTable(
 				false, /* nullable */
 				invertedSourceColOrdinal,
 			)
-			ot.indexes[i].init(ot, i, idxDesc, idxZone, virtualColOrd)
+			ot.indexes[i].init(ot, i, idxDesc, idxZone, partZones, virtualColOrd)
 		} else {
-			ot.indexes[i].init(ot, i, idxDesc, idxZone, -1 /* virtualColOrd */)
+			ot.indexes[i].init(ot, i, idxDesc, idxZone, partZones, -1 /* virtualColOrd */)
 		}
 	}
 
@@ -826,6 +854,11 @@ func newOptTable(
 		ot.stats = make([]optTableStat, len(stats))
 		n := 0
 		for i := range stats {
+			// Partial stats only cover some of the rows of the table; they are
+			// used through the merged stats created from them instead.
+			if stats[i].IsPartial() || (stats[i].IsForecast() && !useForecasts) {
+				continue
+			}
 			// We skip any stats that have columns that don't exist in the table anymore.
 			if ok, err := ot.stats[n].init(ot, stats[i]); err != nil {
 				return nil, err
@@ -852,7 +885,10 @@ func (ot *optTable) PostgresDescriptorID() cat.StableID {
 // isStale checks if the optTable object needs to be refreshed because the stats,
 // zone config, or used types have changed. False positives are ok.
 func (ot *optTable) isStale(
-	rawDesc *tabledesc.Immutable, tableStats []*stats.TableStatistic, zone *zonepb.ZoneConfig,
+	rawDesc *tabledesc.Immutable,
+	tableStats []*stats.TableStatistic,
+	useForecasts bool,
+	zone *zonepb.ZoneConfig,
 ) bool {
 	// Fast check to verify that the statistics haven't changed: we check the
 	// length and the address of the underlying array. This is not a perfect
@@ -864,6 +900,9 @@ func (ot *optTable) isStale(
 	if len(tableStats) > 0 && &tableStats[0] != &ot.rawStats[0] {
 		return true
 	}
+	if useForecasts != ot.useForecasts {
+		return true
+	}
 	if !zone.Equal(ot.zone) {
 		return true
 	}
@@ -1081,6 +1120,10 @@ type optIndex struct {
 	// ordinal of the virtual column created to refer to the key of this index.
 	// It is -1 if this is not an inverted index.
 	invertedVirtualColOrd int
+
+	// partitions stores zone information and datums for PARTITION BY LIST
+	// partitions.
+	partitions []optPartition
 }
 
 var _ cat.Index = &optIndex{}
@@ -1092,6 +1135,7 @@ func (oi *optIndex) init(
 	indexOrdinal int,
 	desc *descpb.IndexDescriptor,
 	zone *zonepb.ZoneConfig,
+	partZones map[string]*zonepb.ZoneConfig,
 	invertedVirtualColOrd int,
 ) {
 	oi.tab = tab
@@ -1099,6 +1143,35 @@ func (oi *optIndex) init(
 	oi.zone = zone
 	oi.indexOrdinal = indexOrdinal
 	oi.invertedVirtualColOrd = invertedVirtualColOrd
+	if list := desc.Partitioning.List; len(list) > 0 {
+		oi.partitions = make([]optPartition, len(list))
+		var a rowenc.DatumAlloc
+		for i := range list {
+			p := &list[i]
+			partZone, ok := partZones[p.Name]
+			if !ok {
+				partZone = zone
+			}
+			oi.partitions[i] = optPartition{
+				name:   p.Name,
+				zone:   partZone,
+				datums: make([]tree.Datums, 0, len(p.Values)),
+			}
+			for _, valueEncBuf := range p.Values {
+				t, _, err := rowenc.DecodePartitionTuple(
+					&a, tab.codec, tab.desc, desc, &desc.Partitioning,
+					valueEncBuf, nil, /* prefixDatums */
+				)
+				if err != nil {
+					panic(errors.NewAssertionErrorWithWrappedErrf(err, "while decoding partition tuple"))
+				}
+				// Ignore the DEFAULT case, where there is nothing to return.
+				if len(t.Datums) > 0 {
+					oi.partitions[i].datums = append(oi.partitions[i].datums, t.Datums)
+				}
+			}
+		}
+	}
 	if desc == &tab.desc.PrimaryIndex {
 		// Although the primary index contains all columns in the table, the index
 		// descriptor does not contain columns that are not explicitly part of the
@@ -1293,6 +1366,16 @@ func (oi *optIndex) PartitionByListPrefixes() []tree.Datums {
 	return res
 }
 
+// PartitionCount is part of the cat.Index interface.
+func (oi *optIndex) PartitionCount() int {
+	return len(oi.partitions)
+}
+
+// Partition is part of the cat.Index interface.
+func (oi *optIndex) Partition(i int) cat.Partition {
+	return &oi.partitions[i]
+}
+
 // InterleaveAncestorCount is part of the cat.Index interface.
 func (oi *optIndex) InterleaveAncestorCount() int {
 	return len(oi.desc.Interleave.Ancestors)
@@ -1325,6 +1408,217 @@ func (oi *optIndex) Version() descpb.IndexDescriptorVersion {
 	return oi.desc.Version
 }
 
+// optHypotheticalCatalog is a cat.Catalog that wraps an optCatalog and
+// resolves tables with candidate indexes to optHypotheticalTables. It is used
+// to determine whether creating the candidate indexes would allow the
+// optimizer to find a cheaper plan (see makeIndexRecommendations).
+type optHypotheticalCatalog struct {
+	*optCatalog
+
+	// candidates contains the candidate indexes for each table.
+	candidates map[cat.StableID][]indexrec.Candidate
+
+	// tables caches the hypothetical table wrappers, so that the same wrapper
+	// is returned every time a table is resolved.
+	tables map[cat.StableID]*optHypotheticalTable
+}
+
+var _ cat.Catalog = &optHypotheticalCatalog{}
+
+func (oc *optHypotheticalCatalog) init(
+	catalog *optCatalog, candidates map[cat.StableID][]indexrec.Candidate,
+) {
+	oc.optCatalog = catalog
+	oc.candidates = candidates
+	oc.tables = make(map[cat.StableID]*optHypotheticalTable, len(candidates))
+}
+
+// ResolveDataSource is part of the cat.Catalog interface.
+func (oc *optHypotheticalCatalog) ResolveDataSource(
+	ctx context.Context, flags cat.Flags, name *cat.DataSourceName,
+) (cat.DataSource, cat.DataSourceName, error) {
+	ds, resName, err := oc.optCatalog.ResolveDataSource(ctx, flags, name)
+	if err != nil {
+		return nil, cat.DataSourceName{}, err
+	}
+	return oc.hypotheticalDataSource(ds), resName, nil
+}
+
+// ResolveDataSourceByID is part of the cat.Catalog interface.
+func (oc *optHypotheticalCatalog) ResolveDataSourceByID(
+	ctx context.Context, flags cat.Flags, dataSourceID cat.StableID,
+) (_ cat.DataSource, isAdding bool, _ error) {
+	ds, isAdding, err := oc.optCatalog.ResolveDataSourceByID(ctx, flags, dataSourceID)
+	if err != nil {
+		return nil, isAdding, err
+	}
+	return oc.hypotheticalDataSource(ds), isAdding, nil
+}
+
+// hypotheticalDataSource returns the hypothetical wrapper for the given data
+// source if it is a table with candidate indexes, or the data source itself
+// otherwise.
+func (oc *optHypotheticalCatalog) hypotheticalDataSource(ds cat.DataSource) cat.DataSource {
+	tab, ok := ds.(*optTable)
+	if !ok || len(oc.candidates[tab.ID()]) == 0 {
+		return ds
+	}
+	if tab.DeletableIndexCount() != tab.IndexCount() {
+		// The hypothetical indexes are ordered after the public indexes, which
+		// would change the ordinals of mutation indexes.
+		return ds
+	}
+	if ht, ok := oc.tables[tab.ID()]; ok && ht.optTable == tab {
+		return ht
+	}
+	ht := newOptHypotheticalTable(tab, oc.candidates[tab.ID()])
+	oc.tables[tab.ID()] = ht
+	return ht
+}
+
+// optHypotheticalTable is a wrapper around optTable that presents a set of
+// hypothetical indexes in addition to the table's own indexes. The
+// hypothetical indexes are public, and are ordered after the table's public
+// indexes.
+type optHypotheticalTable struct {
+	*optTable
+
+	indexes []optHypotheticalIndex
+}
+
+var _ cat.Table = &optHypotheticalTable{}
+
+func newOptHypotheticalTable(
+	tab *optTable, candidates []indexrec.Candidate,
+) *optHypotheticalTable {
+	ht := &optHypotheticalTable{
+		optTable: tab,
+		indexes:  make([]optHypotheticalIndex, len(candidates)),
+	}
+	for i := range ht.indexes {
+		ht.indexes[i].init(ht, tab.IndexCount()+i, &candidates[i])
+	}
+	return ht
+}
+
+// IndexCount is part of the cat.Table interface.
+func (ht *optHypotheticalTable) IndexCount() int {
+	return ht.optTable.IndexCount() + len(ht.indexes)
+}
+
+// WritableIndexCount is part of the cat.Table interface.
+func (ht *optHypotheticalTable) WritableIndexCount() int {
+	return ht.optTable.WritableIndexCount() + len(ht.indexes)
+}
+
+// DeletableIndexCount is part of the cat.Table interface.
+func (ht *optHypotheticalTable) DeletableIndexCount() int {
+	return ht.optTable.DeletableIndexCount() + len(ht.indexes)
+}
+
+// Index is part of the cat.Table interface.
+func (ht *optHypotheticalTable) Index(i cat.IndexOrdinal) cat.Index {
+	if n := ht.optTable.IndexCount(); i >= n {
+		return &ht.indexes[i-n]
+	}
+	return ht.optTable.Index(i)
+}
+
+// optHypotheticalIndex is an index that does not exist; it wraps an optIndex
+// built over a synthesized index descriptor. The index is not unique, its key
+// consists of the candidate's columns followed by the primary key columns, and
+// it stores all other columns of the table so that it covers any query.
+type optHypotheticalIndex struct {
+	optIndex
+
+	hypTab    *optHypotheticalTable
+	candidate *indexrec.Candidate
+	hypDesc   descpb.IndexDescriptor
+}
+
+var _ indexrec.HypotheticalIndex = &optHypotheticalIndex{}
+
+func (hi *optHypotheticalIndex) init(
+	tab *optHypotheticalTable, indexOrdinal int, candidate *indexrec.Candidate,
+) {
+	hi.hypTab = tab
+	hi.candidate = candidate
+
+	ot := tab.optTable
+	hypOrdinal := indexOrdinal - ot.IndexCount()
+	hi.hypDesc = descpb.IndexDescriptor{
+		Name: fmt.Sprintf("_hyp_%d", hypOrdinal+1),
+		// Use IDs that no index of the table has, or will have.
+		ID:      ot.desc.NextIndexID + descpb.IndexID(hypOrdinal),
+		Version: descpb.SecondaryIndexFamilyFormatVersion,
+	}
+	desc := &hi.hypDesc
+
+	var keyCols util.FastIntSet
+	for _, col := range candidate.Columns {
+		c := ot.Column(col.Ordinal)
+		desc.ColumnNames = append(desc.ColumnNames, string(c.ColName()))
+		desc.ColumnIDs = append(desc.ColumnIDs, descpb.ColumnID(c.ColID()))
+		dir := descpb.IndexDescriptor_ASC
+		if col.Descending {
+			dir = descpb.IndexDescriptor_DESC
+		}
+		desc.ColumnDirections = append(desc.ColumnDirections, dir)
+		keyCols.Add(int(c.ColID()))
+	}
+	for _, id := range ot.desc.PrimaryIndex.ColumnIDs {
+		if !keyCols.Contains(int(id)) {
+			desc.ExtraColumnIDs = append(desc.ExtraColumnIDs, id)
+			keyCols.Add(int(id))
+		}
+	}
+	for i, n := 0, ot.ColumnCount(); i < n; i++ {
+		c := ot.Column(i)
+		if c.Kind() == cat.Ordinary && !keyCols.Contains(int(c.ColID())) {
+			desc.StoreColumnNames = append(desc.StoreColumnNames, string(c.ColName()))
+			desc.StoreColumnIDs = append(desc.StoreColumnIDs, descpb.ColumnID(c.ColID()))
+		}
+	}
+
+	// The index is placed in the same zone as the primary index.
+	hi.optIndex.init(ot, indexOrdinal, desc, ot.indexes[cat.PrimaryIndex].zone, nil, -1 /* virtualColOrd */)
+}
+
+// Table is part of the cat.Index interface.
+func (hi *optHypotheticalIndex) Table() cat.Table {
+	return hi.hypTab
+}
+
+// Candidate is part of the indexrec.HypotheticalIndex interface.
+func (hi *optHypotheticalIndex) Candidate() *indexrec.Candidate {
+	return hi.candidate
+}
+
+// optPartition implements cat.Partition and represents a PARTITION BY LIST
+// partition of an index.
+type optPartition struct {
+	name   string
+	zone   *zonepb.ZoneConfig
+	datums []tree.Datums
+}
+
+var _ cat.Partition = &optPartition{}
+
+// Name is part of the cat.Partition interface.
+func (op *optPartition) Name() string {
+	return op.name
+}
+
+// Zone is part of the cat.Partition interface.
+func (op *optPartition) Zone() cat.Zone {
+	return op.zone
+}
+
+// PartitionByListPrefixes is part of the cat.Partition interface.
+func (op *optPartition) PartitionByListPrefixes() []tree.Datums {
+	return op.datums
+}
+
 type optTableStat struct {
 	stat           *stats.TableStatistic
 	columnOrdinals []int
@@ -1954,6 +2248,16 @@ func (oi *optVirtualIndex) PartitionByListPrefixes() []tree.Datums {
 	return nil
 }
 
+// PartitionCount is part of the cat.Index interface.
+func (oi *optVirtualIndex) PartitionCount() int {
+	return 0
+}
+
+// Partition is part of the cat.Index interface.
+func (oi *optVirtualIndex) Partition(i int) cat.Partition {
+	return nil
+}
+
 // InterleaveAncestorCount is part of the cat.Index interface.
 func (oi *optVirtualIndex) InterleaveAncestorCount() int {
 	return 0
@@ -108,6 +108,7 @@ func (ef *execFactory) ConstructScan(
 	scan.index = indexDesc
 	scan.hardLimit = params.HardLimit
 	scan.softLimit = params.SoftLimit
+	scan.skipScanPrefixLen = params.SkipScanPrefixLen
 
 	scan.reverse = params.Reverse
 	scan.parallelize = params.Parallelize
@@ -497,9 +498,9 @@ func (ef *execFactory) ConstructDistinct(
 
 // ConstructSetOp is part of the exec.Factory interface.
 func (ef *execFactory) ConstructSetOp(
-	typ tree.UnionType, all bool, left, right exec.Node,
+	typ tree.UnionType, all bool, left, right exec.Node, hardLimit uint64,
 ) (exec.Node, error) {
-	return ef.planner.newUnionNode(typ, all, left.(planNode), right.(planNode))
+	return ef.planner.newUnionNode(typ, all, left.(planNode), right.(planNode), hardLimit)
 }
 
 // ConstructSort is part of the exec.Factory interface.
@@ -1891,8 +1892,10 @@ func (ef *execFactory) ConstructExplain(
 	}
 	flags := explain.MakeFlags(options)
 	n := &explainPlanNode{
-		flags: flags,
-		plan:  plan.(*explain.Plan),
+		flags:                flags,
+		plan:                 plan.(*explain.Plan),
+		hints:                ef.planner.optPlanningCtx.hints,
+		indexRecommendations: ef.planner.optPlanningCtx.indexRecommendations,
 	}
 	return n, nil
 }
@@ -122,6 +122,8 @@ func TestParse(t *testing.T) {
 		{`CREATE INVERTED INDEX a ON b.c (d)`},
 		{`CREATE INVERTED INDEX a ON b (c) STORING (d)`},
 		{`CREATE INVERTED INDEX a ON b (c) WHERE d > 3`},
+		{`CREATE INVERTED INDEX a ON b (c gin_trgm_ops)`},
+		{`CREATE INVERTED INDEX a ON b (c, d gin_trgm_ops)`},
 		{`CREATE INVERTED INDEX a ON b (c) INTERLEAVE IN PARENT d (e)`},
 		{`CREATE INVERTED INDEX IF NOT EXISTS a ON b (c) WHERE d > 3`},
 		{`CREATE INDEX a ON b (c) WITH (fillfactor = 100, y_bounds = 50)`},
@@ -271,6 +273,7 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, INDEX (b) STORING (c))`},
 		{`CREATE TABLE a (b INT8, INDEX (b) WHERE b > 3)`},
 		{`CREATE TABLE a (b INT8, INVERTED INDEX (b) WHERE b > 3)`},
+		{`CREATE TABLE a (b STRING, INVERTED INDEX (b gin_trgm_ops))`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX (b ASC, c DESC) STORING (c))`},
 		{`CREATE TABLE a (b INT8, INDEX (b) INTERLEAVE IN PARENT c (d, e))`},
 		{`CREATE TABLE a (b INT8, FAMILY (b))`},
@@ -406,6 +409,8 @@ func TestParse(t *testing.T) {
 		{`CREATE STATISTICS a ON col1 FROM t WITH OPTIONS THROTTLING 0.9`},
 		{`CREATE STATISTICS a ON col1 FROM t WITH OPTIONS AS OF SYSTEM TIME '2016-01-01'`},
 		{`CREATE STATISTICS a ON col1 FROM t WITH OPTIONS THROTTLING 0.1 AS OF SYSTEM TIME '2016-01-01'`},
+		{`CREATE STATISTICS a ON col1 FROM t WITH OPTIONS USING EXTREMES`},
+		{`CREATE STATISTICS a ON col1 FROM t WITH OPTIONS THROTTLING 0.1 USING EXTREMES`},
 
 		{`ANALYZE t`},
 		{`ANALYZE db.sc.t`},
@@ -1122,6 +1127,13 @@ func TestParse(t *testing.T) {
 		{`SELECT 1 FROM t GROUP BY a`},
 		{`SELECT 1 FROM t GROUP BY a, b`},
 		{`SELECT 1 FROM t GROUP BY ()`},
+		{`SELECT 1 FROM t GROUP BY ROLLUP (a, b)`},
+		{`SELECT 1 FROM t GROUP BY a, ROLLUP (b, (c, d))`},
+		{`SELECT 1 FROM t GROUP BY CUBE (a, b)`},
+		{`SELECT 1 FROM t GROUP BY GROUPING SETS ((a, b), a, ())`},
+		{`SELECT 1 FROM t GROUP BY GROUPING SETS (ROLLUP (a, b), CUBE (c), GROUPING SETS (d))`},
+		{`SELECT GROUPING(a), GROUPING(a, b) FROM t GROUP BY ROLLUP (a, b)`},
+		{`SELECT rollup(a), cube(a) FROM t GROUP BY rollup, cube`},
 		{`SELECT sum(x ORDER BY y) FROM t`},
 		{`SELECT sum(x ORDER BY y, z) FROM t`},
 
@@ -1710,6 +1722,8 @@ func TestParse(t *testing.T) {
 		{`RESTORE FROM $4 IN $1, $2, 'bar' AS OF SYSTEM TIME '1' WITH skip_missing_foreign_keys`},
 
 		{`RESTORE TENANT 36 FROM ($1, $2) AS OF SYSTEM TIME '1'`},
+		{`RESTORE TENANT 36 FROM REPLICATION STREAM FROM 'bar'`},
+		{`RESTORE TENANT 36 FROM REPLICATION STREAM FROM ($1, $2)`},
 
 		{`BACKUP TABLE foo TO 'bar' WITH revision_history, detached`},
 		{`RESTORE TABLE foo FROM 'bar' WITH skip_missing_foreign_keys, skip_missing_sequences, detached`},
@@ -1743,6 +1757,9 @@ func TestParse(t *testing.T) {
 		// {`CREATE CHANGEFEED FOR DATABASE foo INTO 'sink'`},
 		{`CREATE CHANGEFEED FOR TABLE foo INTO 'sink' WITH bar = 'baz'`},
 
+		{`CREATE REPLICATION STREAM FOR TENANT 7`},
+		{`CREATE REPLICATION STREAM FOR TENANT 7 WITH cursor = '1.0'`},
+
 		// Regression for #15926
 		{`SELECT * FROM ((t1 NATURAL JOIN t2 WITH ORDINALITY AS o1)) WITH ORDINALITY AS o2`},
 
@@ -1826,6 +1843,8 @@ func TestParse2(t *testing.T) {
 			`CREATE INVERTED INDEX a ON b (c)`},
 		{`CREATE UNIQUE INDEX a ON b USING GIN (c)`,
 			`CREATE UNIQUE INVERTED INDEX a ON b (c)`},
+		{`CREATE INDEX a ON b USING GIN (c gin_trgm_ops)`,
+			`CREATE INVERTED INDEX a ON b (c gin_trgm_ops)`},
 
 		{`CREATE INDEX ON a (a, (lower(b)))`,
 			`CREATE INDEX ON a (a, lower(b))`},
@@ -1865,6 +1884,8 @@ func TestParse2(t *testing.T) {
 
 		{`CREATE STATISTICS a ON col1 FROM t AS OF SYSTEM TIME '2016-01-01'`,
 			`CREATE STATISTICS a ON col1 FROM t WITH OPTIONS AS OF SYSTEM TIME '2016-01-01'`},
+		{`CREATE STATISTICS a ON col1 FROM t USING EXTREMES`,
+			`CREATE STATISTICS a ON col1 FROM t WITH OPTIONS USING EXTREMES`},
 
 		{`ANALYSE t`, `ANALYZE t`},
 
@@ -2244,6 +2265,7 @@ $function$`,
 		{`SHOW SESSION database`, `SHOW database`},
 		{`SHOW SESSION TIME ZONE`, `SHOW timezone`},
 		{`SHOW SESSION TIMEZONE`, `SHOW timezone`},
+		{`SHOW pg_trgm.similarity_threshold`, `SHOW "pg_trgm.similarity_threshold"`},
 
 		{`SHOW ALL ZONE CONFIGURATIONS`, `SHOW ZONE CONFIGURATIONS`},
 
@@ -2380,6 +2402,14 @@ $function$`,
 			`ALTER USER 'foo' WITH PASSWORD 'bar'`},
 		{`ALTER USER foo WITH PASSWORD NULL`,
 			`ALTER USER 'foo' WITH PASSWORD NULL`},
+		{`ALTER USER foo WITH AUTHENTICATION cert`,
+			`ALTER USER 'foo' WITH AUTHENTICATION 'cert'`},
+		{`ALTER ROLE foo AUTHENTICATION 'cert' CONNECTION FROM '10.0.0.0/8'`,
+			`ALTER ROLE 'foo' WITH AUTHENTICATION 'cert' CONNECTION FROM '10.0.0.0/8'`},
+		{`ALTER ROLE foo WITH AUTHENTICATION NULL CONNECTION FROM NULL`,
+			`ALTER ROLE 'foo' WITH AUTHENTICATION NULL CONNECTION FROM NULL`},
+		{`CREATE USER foo LOGIN AUTHENTICATION 'cert'`,
+			`CREATE USER 'foo' WITH LOGIN AUTHENTICATION 'cert'`},
 
 		{`ALTER TABLE a RENAME b TO c`,
 			`ALTER TABLE a RENAME COLUMN b TO c`},
@@ -3252,7 +3282,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE INDEX a ON b USING SPGIST (c)`, 0, `index using spgist`, ``},
 		{`CREATE INDEX a ON b USING BRIN (c)`, 0, `index using brin`, ``},
 
-		{`CREATE INDEX a ON b(c gin_trgm_ops)`, 41285, `index using gin_trgm_ops`, ``},
 		{`CREATE INDEX a ON b(c gist_trgm_ops)`, 41285, `index using gist_trgm_ops`, ``},
 		{`CREATE INDEX a ON b(c bobby)`, 47420, ``, ``},
 		{`CREATE INDEX a ON b(a NULLS LAST)`, 6224, ``, ``},
@@ -3267,16 +3296,10 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`SELECT a(b) 'c'`, 0, `a(...) SCONST`, ``},
 		{`SELECT (a,b) OVERLAPS (c,d)`, 0, `overlaps`, ``},
 		{`SELECT UNIQUE (SELECT b)`, 0, `UNIQUE predicate`, ``},
-		{`SELECT GROUPING (a,b,c)`, 0, `d_expr grouping`, ``},
 		{`SELECT a(VARIADIC b)`, 0, `variadic`, ``},
 		{`SELECT a(b, c, VARIADIC b)`, 0, `variadic`, ``},
 		{`SELECT TREAT (a AS INT8)`, 0, `treat`, ``},
 
-		{`SELECT 1 FROM t GROUP BY ROLLUP (b)`, 46280, `rollup`, ``},
-		{`SELECT 1 FROM t GROUP BY a, ROLLUP (b)`, 46280, `rollup`, ``},
-		{`SELECT 1 FROM t GROUP BY CUBE (b)`, 46280, `cube`, ``},
-		{`SELECT 1 FROM t GROUP BY GROUPING SETS (b)`, 46280, `grouping sets`, ``},
-
 		{`SELECT a FROM t ORDER BY a NULLS LAST`, 6224, ``, ``},
 		{`SELECT a FROM t ORDER BY a ASC NULLS LAST`, 6224, ``, ``},
 		{`SELECT a FROM t ORDER BY a DESC NULLS FIRST`, 6224, ``, ``},
@@ -3294,8 +3317,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE TABLE a(b PG_LSN)`, 0, `pg_lsn`, ``},
 		{`CREATE TABLE a(b POINT)`, 21286, `point`, ``},
 		{`CREATE TABLE a(b POLYGON)`, 21286, `polygon`, ``},
-		{`CREATE TABLE a(b TSQUERY)`, 7821, `tsquery`, ``},
-		{`CREATE TABLE a(b TSVECTOR)`, 7821, `tsvector`, ``},
 		{`CREATE TABLE a(b TXID_SNAPSHOT)`, 0, `txid_snapshot`, ``},
 		{`CREATE TABLE a(b XML)`, 0, `xml`, ``},
 
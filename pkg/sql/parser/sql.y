@@ -596,7 +596,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 // Ordinary key words in alphabetical order.
 %token <str> ABORT ACCESS ACTION ADD ADMIN AFFINITY AFTER AGGREGATE
 %token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC
-%token <str> ASYMMETRIC AT ATTRIBUTE AUTHORIZATION AUTOMATIC
+%token <str> ASYMMETRIC AT ATTRIBUTE AUTHENTICATION AUTHORIZATION AUTOMATIC
 
 %token <str> BACKUP BACKUPS BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
 %token <str> BUCKET_COUNT
@@ -620,7 +620,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT
-%token <str> EXPIRATION EXPLAIN EXPORT EXTENSION EXTRACT EXTRACT_DURATION
+%token <str> EXPIRATION EXPLAIN EXPORT EXTENSION EXTRACT EXTRACT_DURATION EXTREMES
 
 %token <str> FAILURE FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH
 %token <str> FILES FILTER
@@ -669,7 +669,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 
 %token <str> RANGE RANGES READ REAL REASSIGN RECURSIVE RECURRING REF REFERENCES REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE REINDEX
-%token <str> REMOVE_PATH RENAME REPEATABLE REPLACE
+%token <str> REMOVE_PATH RENAME REPEATABLE REPLACE REPLICATION
 %token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETRY REVISION_HISTORY REVOKE RIGHT
 %token <str> ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT RULE RUNNING
 
@@ -678,13 +678,13 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %token <str> SHARE SHOW SIMILAR SIMPLE SKIP SKIP_MISSING_FOREIGN_KEYS
 %token <str> SKIP_MISSING_SEQUENCES SKIP_MISSING_SEQUENCE_OWNERS SKIP_MISSING_VIEWS SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
-%token <str> START STATISTICS STATUS STDIN STRICT STRING STORAGE STORE STORED STORING SUBSTRING
+%token <str> START STATISTICS STATUS STDIN STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING
 %token <str> SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION
 
 %token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
 %token <str> TIES TIME TIMETZ TIMESTAMP TIMESTAMPTZ TO THROTTLING TRAILING TRACE
 %token <str> TRANSACTION TRANSACTIONS TREAT TRIGGER TRIM TRUE
-%token <str> TRUNCATE TRUSTED TYPE TYPES
+%token <str> TRUNCATE TRUSTED TSMATCHES TYPE TYPES
 %token <str> TRACING
 
 %token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLOGGED UNSPLIT
@@ -802,6 +802,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 
 %type <tree.Statement> create_stmt
 %type <tree.Statement> create_changefeed_stmt
+%type <tree.Statement> create_replication_stream_stmt
 %type <tree.Statement> create_ddl_stmt
 %type <tree.Statement> create_database_stmt
 %type <tree.Statement> create_extension_stmt
@@ -971,6 +972,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <str> name opt_name opt_name_parens
 %type <str> privilege savepoint_name
 %type <tree.KVOption> role_option password_clause valid_until_clause
+%type <tree.KVOption> authentication_clause connection_from_clause
 %type <tree.Operator> subquery_op
 %type <*tree.UnresolvedName> func_name func_name_no_crdb_extra
 %type <str> opt_class opt_collate
@@ -1253,7 +1255,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %left      '|'
 %left      '#'
 %left      '&'
-%left      LSHIFT RSHIFT INET_CONTAINS_OR_EQUALS INET_CONTAINED_BY_OR_EQUALS AND_AND SQRT CBRT
+%left      LSHIFT RSHIFT INET_CONTAINS_OR_EQUALS INET_CONTAINED_BY_OR_EQUALS AND_AND SQRT CBRT TSMATCHES
 %left      '+' '-'
 %left      '*' '/' FLOORDIV '%'
 %left      '^'
@@ -2547,6 +2549,8 @@ opt_with_schedule_options:
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
 //
+// RESTORE TENANT <tenant_id> FROM REPLICATION STREAM FROM <stream address>
+//
 // Targets:
 //    TABLE <pattern> [, ...]
 //    DATABASE <databasename> [, ...]
@@ -2603,6 +2607,13 @@ restore_stmt:
       Options: *($8.restoreOptions()),
     }
   }
+| RESTORE targets FROM REPLICATION STREAM FROM string_or_placeholder_opt_list
+  {
+    $$.val = &tree.StreamIngestion{
+      Targets: $2.targetList(),
+      From: $7.stringOrPlaceholderOptList(),
+    }
+  }
 | RESTORE error // SHOW HELP: RESTORE
 
 string_or_placeholder_opt_list:
@@ -3146,6 +3157,7 @@ drop_unsupported:
 
 create_ddl_stmt:
   create_changefeed_stmt
+| create_replication_stream_stmt // EXTEND WITH HELP: CREATE REPLICATION STREAM
 | create_database_stmt // EXTEND WITH HELP: CREATE DATABASE
 | create_index_stmt    // EXTEND WITH HELP: CREATE INDEX
 | create_schema_stmt   // EXTEND WITH HELP: CREATE SCHEMA
@@ -3162,7 +3174,7 @@ create_ddl_stmt:
 // %Text:
 // CREATE STATISTICS <statisticname>
 //   [ON <colname> [, ...]]
-//   FROM <tablename> [AS OF SYSTEM TIME <expr>]
+//   FROM <tablename> [AS OF SYSTEM TIME <expr> | USING EXTREMES]
 create_stats_stmt:
   CREATE STATISTICS statistics_name opt_stats_columns FROM create_stats_target opt_create_stats_options
   {
@@ -3212,6 +3224,12 @@ opt_create_stats_options:
       AsOf: $1.asOfClause(),
     }
   }
+| USING EXTREMES
+  {
+    $$.val = &tree.CreateStatsOptions{
+      UsingExtremes: true,
+    }
+  }
 | /* EMPTY */
   {
     $$.val = &tree.CreateStatsOptions{}
@@ -3251,6 +3269,12 @@ create_stats_option:
       AsOf: $1.asOfClause(),
     }
   }
+| USING EXTREMES
+  {
+    $$.val = &tree.CreateStatsOptions{
+      UsingExtremes: true,
+    }
+  }
 
 create_changefeed_stmt:
   CREATE CHANGEFEED FOR changefeed_targets opt_changefeed_sink opt_with_options
@@ -3270,6 +3294,26 @@ create_changefeed_stmt:
     }
   }
 
+// %Help: CREATE REPLICATION STREAM - stream the KV changes of a tenant
+// %Category: CCL
+// %Text:
+// CREATE REPLICATION STREAM FOR TENANT <tenant_id>
+//        [ WITH <option> [= <value>] [, ...] ]
+//
+// Options:
+//    cursor='[timestamp]': start the stream at the given timestamp instead of
+//                          with a full scan of the tenant's keyspace
+// %SeeAlso: RESTORE
+create_replication_stream_stmt:
+  CREATE REPLICATION STREAM FOR targets opt_with_options
+  {
+    $$.val = &tree.ReplicationStream{
+      Targets: $5.targetList(),
+      Options: $6.kvOptions(),
+    }
+  }
+| CREATE REPLICATION error // SHOW HELP: CREATE REPLICATION STREAM
+
 changefeed_targets:
   single_table_pattern_list
   {
@@ -4437,6 +4481,9 @@ show_session_stmt:
 
 session_var:
   IDENT
+// Session variables of extensions are qualified by the extension name, e.g.
+// pg_trgm.similarity_threshold.
+| IDENT '.' IDENT { $$ = $1 + "." + $3 }
 // Although ALL, SESSION_USER and DATABASE are identifiers for the
 // purpose of SHOW, they lex as separate token types, so they need
 // separate rules.
@@ -6798,6 +6845,8 @@ role_option:
   }
 | password_clause
 | valid_until_clause
+| authentication_clause
+| connection_from_clause
 
 
 role_options:
@@ -6830,6 +6879,26 @@ valid_until_clause:
     $$.val = tree.KVOption{Key: tree.Name(fmt.Sprintf("%s_%s",$1, $2)), Value: tree.DNull}
   }
 
+authentication_clause:
+  AUTHENTICATION string_or_placeholder
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: $2.expr()}
+  }
+| AUTHENTICATION NULL
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: tree.DNull}
+  }
+
+connection_from_clause:
+  CONNECTION FROM string_or_placeholder
+  {
+    $$.val = tree.KVOption{Key: tree.Name(fmt.Sprintf("%s_%s",$1, $2)), Value: $3.expr()}
+  }
+| CONNECTION FROM NULL
+  {
+    $$.val = tree.KVOption{Key: tree.Name(fmt.Sprintf("%s_%s",$1, $2)), Value: tree.DNull}
+  }
+
 opt_view_recursive:
   /* EMPTY */ { /* no error */ }
 | RECURSIVE { return unimplemented(sqllex, "create recursive view") }
@@ -7051,10 +7120,12 @@ index_elem_options:
     dir := $2.dir()
     nullsOrder := $3.nullsOrder()
     if opClass != "" {
-      if opClass == "gin_trgm_ops" || opClass == "gist_trgm_ops" {
+      if opClass == "gist_trgm_ops" {
         return unimplementedWithIssueDetail(sqllex, 41285, "index using " + opClass)
       }
-      return unimplementedWithIssue(sqllex, 47420)
+      if opClass != "gin_trgm_ops" {
+        return unimplementedWithIssue(sqllex, 47420)
+      }
     }
     // We currently only support the opposite of Postgres defaults.
     if nullsOrder != tree.DefaultNullsOrder {
@@ -7065,7 +7136,7 @@ index_elem_options:
         return unimplementedWithIssue(sqllex, 6224)
       }
     }
-    $$.val = tree.IndexElem{Direction: dir, NullsOrder: nullsOrder}
+    $$.val = tree.IndexElem{OpClass: tree.Name(opClass), Direction: dir, NullsOrder: nullsOrder}
   }
 
 opt_class:
@@ -8670,9 +8741,18 @@ group_by_list:
 // rather than reducing the conflicting unreserved_keyword rule.
 group_by_item:
   a_expr { $$.val = $1.expr() }
-| ROLLUP '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "rollup") }
-| CUBE '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "cube") }
-| GROUPING SETS error { return unimplementedWithIssueDetail(sqllex, 46280, "grouping sets") }
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.RollupGroupingSet, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.CubeGroupingSet, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.ExplicitGroupingSets, Exprs: $4.exprs()}
+  }
 
 having_clause:
   HAVING a_expr
@@ -10027,6 +10107,10 @@ a_expr:
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.Overlaps, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr TSMATCHES a_expr
+  {
+    $$.val = &tree.ComparisonExpr{Operator: tree.TSMatches, Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr INET_CONTAINS_OR_EQUALS a_expr
   {
     $$.val = &tree.FuncExpr{Func: tree.WrapFunction("inet_contains_or_equals"), Exprs: tree.Exprs{$1.expr(), $3.expr()}}
@@ -10514,7 +10598,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingExpr{Exprs: $3.exprs()}
+  }
 
 func_application:
   func_name '(' ')'
@@ -11993,6 +12080,7 @@ unreserved_keyword:
 | ALWAYS
 | AT
 | ATTRIBUTE
+| AUTHENTICATION
 | AUTOMATIC
 | BACKUP
 | BACKUPS
@@ -12067,6 +12155,7 @@ unreserved_keyword:
 | EXPLAIN
 | EXPORT
 | EXTENSION
+| EXTREMES
 | FAILURE
 | FILES
 | FILTER
@@ -12221,6 +12310,7 @@ unreserved_keyword:
 | RENAME
 | REPEATABLE
 | REPLACE
+| REPLICATION
 | RESET
 | RESTORE
 | RESTRICT
@@ -12273,6 +12363,7 @@ unreserved_keyword:
 | STORE
 | STORED
 | STORING
+| STREAM
 | STRICT
 | SUBSCRIPTION
 | SURVIVE
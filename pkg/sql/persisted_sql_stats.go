@@ -0,0 +1,486 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// SQLStatsFlushEnabled determines whether the in-memory SQL statistics are
+// periodically flushed to the system.statement_statistics and
+// system.transaction_statistics tables.
+var SQLStatsFlushEnabled = settings.RegisterPublicBoolSetting(
+	"sql.stats.flush.enabled",
+	"if set, SQL execution statistics are periodically flushed to disk",
+	true,
+)
+
+// SQLStatsFlushInterval is the interval at which the in-memory SQL statistics
+// are flushed to disk.
+var SQLStatsFlushInterval = func() *settings.DurationSetting {
+	s := settings.RegisterValidatedDurationSetting(
+		"sql.stats.flush.interval",
+		"the interval at which SQL execution statistics are flushed to disk",
+		10*time.Minute,
+		validatePositiveDuration,
+	)
+	s.SetVisibility(settings.Public)
+	return s
+}()
+
+// SQLStatsAggregationInterval is the length of the intervals over which the
+// persisted SQL statistics are aggregated. Statistics flushed within the same
+// interval are merged into a single row.
+var SQLStatsAggregationInterval = settings.RegisterValidatedDurationSetting(
+	"sql.stats.aggregation.interval",
+	"the interval over which persisted SQL execution statistics are aggregated",
+	time.Hour,
+	validatePositiveDuration,
+)
+
+// SQLStatsPersistedTTL is the amount of time persisted SQL statistics are
+// retained for.
+var SQLStatsPersistedTTL = settings.RegisterPublicNonNegativeDurationSetting(
+	"sql.stats.persisted_rows.ttl",
+	"the amount of time persisted SQL execution statistics are retained for "+
+		"(0 disables the cleanup of old statistics)",
+	7*24*time.Hour,
+)
+
+func validatePositiveDuration(v time.Duration) error {
+	if v <= 0 {
+		return errors.Errorf("cannot set to a non-positive duration: %s", v)
+	}
+	return nil
+}
+
+// PersistedStatementStatistics is the statement statistics of one node for a
+// single fingerprint and application, aggregated over one aggregation
+// interval.
+type PersistedStatementStatistics struct {
+	roachpb.CollectedStatementStatistics
+
+	AggregatedTs time.Time
+	AggInterval  time.Duration
+	NodeID       base.SQLInstanceID
+}
+
+// PersistedTransactionStatistics is the transaction counterpart of
+// PersistedStatementStatistics.
+type PersistedTransactionStatistics struct {
+	roachpb.CollectedTransactionStatistics
+
+	AggregatedTs time.Time
+	AggInterval  time.Duration
+	NodeID       base.SQLInstanceID
+}
+
+// encodeFingerprintID returns the encoding of a statement or transaction
+// fingerprint ID used by the fingerprint_id columns of the persisted SQL
+// statistics tables.
+func encodeFingerprintID(id uint64) []byte {
+	return encoding.EncodeUint64Ascending(nil, id)
+}
+
+// sqlStatsFlushEnabled returns whether the SQL statistics should be flushed
+// to disk.
+func (s *Server) sqlStatsFlushEnabled(ctx context.Context) bool {
+	return SQLStatsFlushEnabled.Get(&s.cfg.Settings.SV) &&
+		s.cfg.Settings.Version.IsActive(ctx, clusterversion.PersistedSQLStats)
+}
+
+// periodicallyFlushSQLStats spawns a loop that flushes the in-memory SQL
+// statistics to disk at the interval dictated by SQLStatsFlushInterval.
+func (s *Server) periodicallyFlushSQLStats(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(SQLStatsFlushInterval.Get(&s.cfg.Settings.SV))
+			select {
+			case <-stopper.ShouldQuiesce():
+				return
+			case <-timer.C:
+				timer.Read = true
+			}
+			if !s.sqlStatsFlushEnabled(ctx) {
+				continue
+			}
+			if err := s.FlushSQLStats(ctx); err != nil {
+				log.Warningf(ctx, "failed to flush SQL statistics: %v", err)
+			}
+		}
+	})
+}
+
+// maybeFlushAndResetSQLStats is used by the periodic reset of the SQL
+// statistics. If the statistics are persisted, they are flushed to disk
+// instead of merely being cleared so that they are not lost.
+func (s *Server) maybeFlushAndResetSQLStats(ctx context.Context) {
+	if !s.sqlStatsFlushEnabled(ctx) {
+		s.ResetSQLStats(ctx)
+		return
+	}
+	if err := s.FlushSQLStats(ctx); err != nil {
+		log.Warningf(ctx, "failed to flush SQL statistics: %v", err)
+	}
+}
+
+// FlushSQLStats resets the in-memory SQL statistics and writes them to the
+// system.statement_statistics and system.transaction_statistics tables,
+// merging them with the statistics previously flushed in the current
+// aggregation interval. Like ResetSQLStats, the flushed statistics are also
+// moved into the reported stats. Rows older than SQLStatsPersistedTTL are
+// deleted.
+func (s *Server) FlushSQLStats(ctx context.Context) error {
+	if !s.cfg.Settings.Version.IsActive(ctx, clusterversion.PersistedSQLStats) {
+		return errors.Newf("persisted SQL statistics require the cluster to be upgraded to %s",
+			clusterversion.ByKey(clusterversion.PersistedSQLStats))
+	}
+	flushed := sqlStats{st: s.cfg.Settings, apps: make(map[string]*appStats)}
+	s.sqlStats.resetAndMaybeDumpStats(ctx, &flushed)
+	for appName, a := range flushed.apps {
+		s.reportedStats.getStatsForApplication(appName).Add(a)
+	}
+
+	aggInterval := SQLStatsAggregationInterval.Get(&s.cfg.Settings.SV)
+	aggregatedTs := timeutil.Now().Truncate(aggInterval)
+	nodeID := s.cfg.NodeID.SQLInstanceID()
+	for _, stmt := range flushed.getUnscrubbedStmtStats(s.cfg.VirtualSchemas) {
+		if err := s.persistStmtStats(ctx, aggregatedTs, aggInterval, nodeID, stmt); err != nil {
+			return err
+		}
+	}
+	for _, txn := range flushed.getUnscrubbedTxnStats() {
+		if err := s.persistTxnStats(ctx, aggregatedTs, aggInterval, nodeID, txn); err != nil {
+			return err
+		}
+	}
+	return s.deleteExpiredSQLStats(ctx, nodeID)
+}
+
+func (s *Server) persistStmtStats(
+	ctx context.Context,
+	aggregatedTs time.Time,
+	aggInterval time.Duration,
+	nodeID base.SQLInstanceID,
+	stmt roachpb.CollectedStatementStatistics,
+) error {
+	return s.upsertSQLStats(
+		ctx, "statement_statistics", aggregatedTs, aggInterval, nodeID,
+		encodeFingerprintID(uint64(stmt.ID)), stmt.Key.App,
+		func(existing []byte) (protoutil.Message, error) {
+			if existing != nil {
+				var prev roachpb.CollectedStatementStatistics
+				if err := protoutil.Unmarshal(existing, &prev); err != nil {
+					return nil, err
+				}
+				prev.Stats.Add(&stmt.Stats)
+				stmt.Stats = prev.Stats
+			}
+			return &stmt, nil
+		},
+	)
+}
+
+func (s *Server) persistTxnStats(
+	ctx context.Context,
+	aggregatedTs time.Time,
+	aggInterval time.Duration,
+	nodeID base.SQLInstanceID,
+	txn roachpb.CollectedTransactionStatistics,
+) error {
+	return s.upsertSQLStats(
+		ctx, "transaction_statistics", aggregatedTs, aggInterval, nodeID,
+		encodeFingerprintID(uint64(txn.TransactionFingerprintID)), txn.App,
+		func(existing []byte) (protoutil.Message, error) {
+			if existing != nil {
+				var prev roachpb.CollectedTransactionStatistics
+				if err := protoutil.Unmarshal(existing, &prev); err != nil {
+					return nil, err
+				}
+				prev.Stats.Add(&txn.Stats)
+				txn.Stats = prev.Stats
+			}
+			return &txn, nil
+		},
+	)
+}
+
+// upsertSQLStats writes the statistics returned by merge into the row of the
+// given persisted SQL statistics table identified by the aggregation
+// timestamp, fingerprint, application and node. merge is passed the encoded
+// statistics currently stored in that row, or nil if there is no such row.
+func (s *Server) upsertSQLStats(
+	ctx context.Context,
+	table string,
+	aggregatedTs time.Time,
+	aggInterval time.Duration,
+	nodeID base.SQLInstanceID,
+	fingerprintID []byte,
+	appName string,
+	merge func(existing []byte) (protoutil.Message, error),
+) error {
+	ts, err := tree.MakeDTimestampTZ(aggregatedTs, time.Microsecond)
+	if err != nil {
+		return err
+	}
+	asNode := sessiondata.InternalExecutorOverride{User: security.NodeUserName()}
+	ie := s.cfg.InternalExecutor
+	return s.cfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		row, err := ie.QueryRowEx(ctx, "read-sql-stats", txn, asNode,
+			fmt.Sprintf(`SELECT statistics FROM system.%s
+WHERE aggregated_ts = $1 AND fingerprint_id = $2 AND app_name = $3 AND node_id = $4`, table),
+			ts, fingerprintID, appName, int64(nodeID),
+		)
+		if err != nil {
+			return err
+		}
+		var existing []byte
+		if row != nil {
+			existing = []byte(tree.MustBeDBytes(row[0]))
+		}
+		merged, err := merge(existing)
+		if err != nil {
+			return err
+		}
+		stats, err := protoutil.Marshal(merged)
+		if err != nil {
+			return err
+		}
+		_, err = ie.ExecEx(ctx, "upsert-sql-stats", txn, asNode,
+			fmt.Sprintf(`UPSERT INTO system.%s
+(aggregated_ts, fingerprint_id, app_name, node_id, agg_interval, statistics)
+VALUES ($1, $2, $3, $4, $5, $6)`, table),
+			ts, fingerprintID, appName, int64(nodeID), aggInterval, stats,
+		)
+		return err
+	})
+}
+
+// deleteExpiredSQLStats deletes the statistics persisted by the given node
+// that are older than SQLStatsPersistedTTL.
+func (s *Server) deleteExpiredSQLStats(ctx context.Context, nodeID base.SQLInstanceID) error {
+	ttl := SQLStatsPersistedTTL.Get(&s.cfg.Settings.SV)
+	if ttl == 0 {
+		return nil
+	}
+	cutoff, err := tree.MakeDTimestampTZ(timeutil.Now().Add(-ttl), time.Microsecond)
+	if err != nil {
+		return err
+	}
+	asNode := sessiondata.InternalExecutorOverride{User: security.NodeUserName()}
+	for _, table := range []string{"statement_statistics", "transaction_statistics"} {
+		if _, err := s.cfg.InternalExecutor.ExecEx(ctx, "delete-expired-sql-stats", nil /* txn */, asNode,
+			fmt.Sprintf(`DELETE FROM system.%s WHERE aggregated_ts < $1 AND node_id = $2`, table),
+			cutoff, int64(nodeID),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistedSQLStatsRow is a row of one of the persisted SQL statistics
+// tables, with the statistics column left encoded.
+type persistedSQLStatsRow struct {
+	aggregatedTs time.Time
+	aggInterval  time.Duration
+	nodeID       base.SQLInstanceID
+	statistics   []byte
+}
+
+// readPersistedSQLStats returns the rows of the given persisted SQL
+// statistics table whose aggregation interval starts in [start, end). A zero
+// start or end leaves the corresponding side of the range unbounded.
+func readPersistedSQLStats(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, table string, start, end time.Time,
+) ([]persistedSQLStatsRow, error) {
+	query := fmt.Sprintf(
+		`SELECT aggregated_ts, agg_interval, node_id, statistics FROM system.%s WHERE true`, table)
+	var args []interface{}
+	for _, b := range []struct {
+		ts time.Time
+		op string
+	}{{start, ">="}, {end, "<"}} {
+		if b.ts.IsZero() {
+			continue
+		}
+		ts, err := tree.MakeDTimestampTZ(b.ts, time.Microsecond)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, ts)
+		query += fmt.Sprintf(" AND aggregated_ts %s $%d", b.op, len(args))
+	}
+	query += " ORDER BY aggregated_ts, fingerprint_id, app_name, node_id"
+	rows, err := ie.QueryEx(ctx, "read-persisted-sql-stats", txn,
+		sessiondata.InternalExecutorOverride{User: security.NodeUserName()}, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]persistedSQLStatsRow, len(rows))
+	for i, row := range rows {
+		res[i] = persistedSQLStatsRow{
+			aggregatedTs: tree.MustBeDTimestampTZ(row[0]).Time,
+			aggInterval:  time.Duration(tree.MustBeDInterval(row[1]).Duration.Nanos()),
+			nodeID:       base.SQLInstanceID(tree.MustBeDInt(row[2])),
+			statistics:   []byte(tree.MustBeDBytes(row[3])),
+		}
+	}
+	return res, nil
+}
+
+// ReadPersistedStatementStatistics returns the statement statistics persisted
+// in system.statement_statistics whose aggregation interval starts in
+// [start, end). A zero start or end leaves the corresponding side of the
+// range unbounded.
+func ReadPersistedStatementStatistics(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, start, end time.Time,
+) ([]PersistedStatementStatistics, error) {
+	rows, err := readPersistedSQLStats(ctx, ie, txn, "statement_statistics", start, end)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]PersistedStatementStatistics, len(rows))
+	for i, row := range rows {
+		res[i] = PersistedStatementStatistics{
+			AggregatedTs: row.aggregatedTs,
+			AggInterval:  row.aggInterval,
+			NodeID:       row.nodeID,
+		}
+		if err := protoutil.Unmarshal(row.statistics, &res[i].CollectedStatementStatistics); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// ReadPersistedTransactionStatistics is the transaction counterpart of
+// ReadPersistedStatementStatistics.
+func ReadPersistedTransactionStatistics(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, start, end time.Time,
+) ([]PersistedTransactionStatistics, error) {
+	rows, err := readPersistedSQLStats(ctx, ie, txn, "transaction_statistics", start, end)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]PersistedTransactionStatistics, len(rows))
+	for i, row := range rows {
+		res[i] = PersistedTransactionStatistics{
+			AggregatedTs: row.aggregatedTs,
+			AggInterval:  row.aggInterval,
+			NodeID:       row.nodeID,
+		}
+		if err := protoutil.Unmarshal(row.statistics, &res[i].CollectedTransactionStatistics); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// combinedStmtStats returns the persisted statement statistics merged with
+// the statistics held in memory by the local node, which are attributed to
+// the current aggregation interval.
+func (p *planner) combinedStmtStats(ctx context.Context) ([]PersistedStatementStatistics, error) {
+	persisted, err := ReadPersistedStatementStatistics(
+		ctx, p.ExecCfg().InternalExecutor, p.txn, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	type key struct {
+		aggregatedTs time.Time
+		id           roachpb.StmtID
+		app          string
+		nodeID       base.SQLInstanceID
+	}
+	idx := make(map[key]int, len(persisted))
+	for i := range persisted {
+		s := &persisted[i]
+		idx[key{s.AggregatedTs, s.ID, s.Key.App, s.NodeID}] = i
+	}
+	aggInterval := SQLStatsAggregationInterval.Get(&p.ExecCfg().Settings.SV)
+	aggregatedTs := timeutil.Now().Truncate(aggInterval)
+	nodeID := p.ExecCfg().NodeID.SQLInstanceID()
+	for _, stmt := range p.extendedEvalCtx.sqlStatsCollector.sqlStats.getUnscrubbedStmtStats(
+		p.ExecCfg().VirtualSchemas,
+	) {
+		k := key{aggregatedTs, stmt.ID, stmt.Key.App, nodeID}
+		if i, ok := idx[k]; ok {
+			persisted[i].Stats.Add(&stmt.Stats)
+			persisted[i].Key = stmt.Key
+			continue
+		}
+		idx[k] = len(persisted)
+		persisted = append(persisted, PersistedStatementStatistics{
+			CollectedStatementStatistics: stmt,
+			AggregatedTs:                 aggregatedTs,
+			AggInterval:                  aggInterval,
+			NodeID:                       nodeID,
+		})
+	}
+	return persisted, nil
+}
+
+// combinedTxnStats is the transaction counterpart of combinedStmtStats.
+func (p *planner) combinedTxnStats(ctx context.Context) ([]PersistedTransactionStatistics, error) {
+	persisted, err := ReadPersistedTransactionStatistics(
+		ctx, p.ExecCfg().InternalExecutor, p.txn, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	type key struct {
+		aggregatedTs time.Time
+		id           roachpb.TransactionFingerprintID
+		app          string
+		nodeID       base.SQLInstanceID
+	}
+	idx := make(map[key]int, len(persisted))
+	for i := range persisted {
+		s := &persisted[i]
+		idx[key{s.AggregatedTs, s.TransactionFingerprintID, s.App, s.NodeID}] = i
+	}
+	aggInterval := SQLStatsAggregationInterval.Get(&p.ExecCfg().Settings.SV)
+	aggregatedTs := timeutil.Now().Truncate(aggInterval)
+	nodeID := p.ExecCfg().NodeID.SQLInstanceID()
+	for _, txn := range p.extendedEvalCtx.sqlStatsCollector.sqlStats.getUnscrubbedTxnStats() {
+		k := key{aggregatedTs, txn.TransactionFingerprintID, txn.App, nodeID}
+		if i, ok := idx[k]; ok {
+			persisted[i].Stats.Add(&txn.Stats)
+			continue
+		}
+		idx[k] = len(persisted)
+		persisted = append(persisted, PersistedTransactionStatistics{
+			CollectedTransactionStatistics: txn,
+			AggregatedTs:                   aggregatedTs,
+			AggInterval:                    aggInterval,
+			NodeID:                         nodeID,
+		})
+	}
+	return persisted, nil
+}
@@ -83,7 +83,7 @@ func (c *conn) handleAuthentication(
 
 	// Check that the requested user exists and retrieve the hashed
 	// password in case password authentication is needed.
-	exists, canLogin, pwRetrievalFn, validUntilFn, err := sql.GetUserHashedPassword(
+	exists, canLogin, pwRetrievalFn, validUntilFn, authPolicy, err := sql.GetUserHashedPassword(
 		ctx, authOpt.ie, c.sessionArgs.User,
 	)
 	if err != nil {
@@ -103,7 +103,7 @@ func (c *conn) handleAuthentication(
 	}
 
 	// Retrieve the authentication method.
-	tlsState, hbaEntry, methodFn, err := c.findAuthenticationMethod(authOpt)
+	tlsState, hbaEntry, methodFn, err := c.findAuthenticationMethod(authOpt, authPolicy)
 	if err != nil {
 		ac.Logf(ctx, "auth method lookup failed: %v", err)
 		return nil, sendError(err)
@@ -131,7 +131,7 @@ func (c *conn) handleAuthentication(
 }
 
 func (c *conn) findAuthenticationMethod(
-	authOpt authOptions,
+	authOpt authOptions, authPolicy sql.RoleAuthenticationPolicy,
 ) (tlsState tls.ConnectionState, hbaEntry *hba.Entry, methodFn AuthMethod, err error) {
 	if authOpt.insecure {
 		// Insecure connections always use "trust" no matter what, and the
@@ -141,18 +141,29 @@ func (c *conn) findAuthenticationMethod(
 		return
 	}
 
-	// Look up the method from the HBA configuration.
+	// Look up the method from the role's authentication policy first,
+	// then from the HBA configuration.
 	var mi methodInfo
-	mi, hbaEntry, err = c.lookupAuthenticationMethodUsingRules(authOpt.connType, authOpt.auth)
+	mi, hbaEntry, err = c.lookupAuthenticationMethodUsingPolicy(authOpt.connType, authPolicy)
 	if err != nil {
 		return
 	}
+	fromPolicy := hbaEntry != nil
+	if !fromPolicy {
+		mi, hbaEntry, err = c.lookupAuthenticationMethodUsingRules(authOpt.connType, authOpt.auth)
+		if err != nil {
+			return
+		}
+	}
 	methodFn = mi.fn
 
 	// Check that this method can be used over this connection type.
 	if authOpt.connType&mi.validConnTypes == 0 {
 		err = errors.Newf("method %q required for this user, but unusable over this connection type",
 			hbaEntry.Method.Value)
+		if fromPolicy {
+			err = errors.WithDetailf(err, "matched rule: %s", hbaEntry.Input)
+		}
 		return
 	}
 
@@ -170,18 +181,74 @@ func (c *conn) findAuthenticationMethod(
 	return
 }
 
+// lookupAuthenticationMethodUsingPolicy checks the connection against the
+// authentication policy configured for the connecting user via the
+// AUTHENTICATION and CONNECTION FROM role options.
+//
+// An error is returned if the policy restricts the network the user can
+// connect from and the client address does not match. Otherwise, if the
+// policy specifies an authentication method, the method and the entry
+// corresponding to the policy are returned. A nil entry is returned if
+// the HBA configuration decides how to authenticate the user.
+func (c *conn) lookupAuthenticationMethodUsingPolicy(
+	connType hba.ConnType, policy sql.RoleAuthenticationPolicy,
+) (mi methodInfo, entry *hba.Entry, err error) {
+	if policy.IsEmpty() {
+		return
+	}
+	entry, err = makeRoleAuthenticationEntry(c.sessionArgs.User, policy)
+	if err != nil {
+		return
+	}
+
+	if policy.Address != "" {
+		var ip net.IP
+		ip, err = c.clientIP(connType)
+		if err != nil {
+			return
+		}
+		var connMatch bool
+		connMatch, err = entry.ConnMatches(connType, ip)
+		if err != nil {
+			return
+		}
+		if !connMatch {
+			origin := "local connections"
+			if ip != nil {
+				origin = "host " + ip.String()
+			}
+			err = errors.WithDetailf(
+				errors.Newf("user %s is not allowed to connect from %s", c.sessionArgs.User, origin),
+				"matched rule: %s", entry.Input)
+			return
+		}
+	}
+
+	if policy.Method == "" {
+		// The policy only restricts the network; the HBA configuration
+		// determines the authentication method.
+		return methodInfo{}, nil, nil
+	}
+	info, ok := hbaAuthMethods[policy.Method]
+	if !ok || info.fn == nil {
+		err = errors.WithDetailf(
+			errors.WithHintf(
+				errors.Newf("unknown auth method %q required for user %s", policy.Method, c.sessionArgs.User),
+				"Supported methods: %s", listRegisteredMethods()),
+			"matched rule: %s", entry.Input)
+		return
+	}
+	entry.MethodFn = info
+	return info, entry, nil
+}
+
 func (c *conn) lookupAuthenticationMethodUsingRules(
 	connType hba.ConnType, auth *hba.Conf,
 ) (mi methodInfo, entry *hba.Entry, err error) {
 	var ip net.IP
-	if connType != hba.ConnLocal {
-		// Extract the IP address of the client.
-		tcpAddr, ok := c.conn.RemoteAddr().(*net.TCPAddr)
-		if !ok {
-			err = errors.AssertionFailedf("client address type %T unsupported", c.conn.RemoteAddr())
-			return
-		}
-		ip = tcpAddr.IP
+	ip, err = c.clientIP(connType)
+	if err != nil {
+		return
 	}
 
 	// Look up the method.
@@ -211,6 +278,19 @@ func (c *conn) lookupAuthenticationMethodUsingRules(
 	return
 }
 
+// clientIP returns the IP address of the client, or nil if the client
+// is connected over a local socket.
+func (c *conn) clientIP(connType hba.ConnType) (net.IP, error) {
+	if connType == hba.ConnLocal {
+		return nil, nil
+	}
+	tcpAddr, ok := c.conn.RemoteAddr().(*net.TCPAddr)
+	if !ok {
+		return nil, errors.AssertionFailedf("client address type %T unsupported", c.conn.RemoteAddr())
+	}
+	return tcpAddr.IP, nil
+}
+
 // authenticatorIO is the interface used by the connection to pass password data
 // to the authenticator and expect an authentication decision from it.
 type authenticatorIO interface {
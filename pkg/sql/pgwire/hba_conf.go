@@ -12,6 +12,7 @@ package pgwire
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/http"
 	"sort"
@@ -21,6 +22,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -252,6 +255,38 @@ var rootEntry = hba.Entry{
 	Input:    "host  all root all cert-password # CockroachDB mandatory rule",
 }
 
+// makeRoleAuthenticationEntry builds the entry corresponding to the
+// authentication policy configured for the given user via role options.
+// The entry's Input spells out the role options, so that logs and error
+// messages can name the rule that was applied to the connection.
+func makeRoleAuthenticationEntry(
+	user security.SQLUsername, policy sql.RoleAuthenticationPolicy,
+) (*hba.Entry, error) {
+	entry := &hba.Entry{
+		ConnType: hba.ConnAny,
+		User:     []hba.String{{Value: user.Normalized(), Quoted: false}},
+		Address:  hba.AnyAddr{},
+		Method:   hba.String{Value: policy.Method},
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "ALTER ROLE %s WITH", lex.EscapeSQLString(user.Normalized()))
+	if policy.Method != "" {
+		fmt.Fprintf(&buf, " AUTHENTICATION %s", lex.EscapeSQLString(policy.Method))
+	}
+	if policy.Address != "" {
+		_, ipNet, err := net.ParseCIDR(policy.Address)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid CONNECTION FROM address for user %s", user)
+		}
+		// Local connections do not originate from any network.
+		entry.ConnType = hba.ConnHostAny
+		entry.Address = ipNet
+		fmt.Fprintf(&buf, " CONNECTION FROM %s", lex.EscapeSQLString(policy.Address))
+	}
+	entry.Input = buf.String()
+	return entry, nil
+}
+
 // DefaultHBAConfig is used when the stored HBA configuration string
 // is empty or invalid.
 var DefaultHBAConfig = func() *hba.Conf {
@@ -431,6 +431,26 @@ func (p *PhysicalPlan) EnsureSingleStreamOnGateway() {
 	}
 }
 
+// EnsureSingleStreamOnGatewaySerially ensures that there is only one stream on
+// the gateway node in the plan, merging multiple streams serially: the streams
+// are consumed one at a time in the order of ResultRouters, and a stream is not
+// read at all until all the streams before it are exhausted. The merge does not
+// maintain MergeOrdering.
+func (p *PhysicalPlan) EnsureSingleStreamOnGatewaySerially() {
+	if len(p.ResultRouters) == 1 {
+		p.EnsureSingleStreamOnGateway()
+		return
+	}
+	p.MergeOrdering = execinfrapb.Ordering{}
+	p.AddSingleGroupStage(
+		p.GatewayNodeID,
+		execinfrapb.ProcessorCoreUnion{Noop: &execinfrapb.NoopCoreSpec{}},
+		execinfrapb.PostProcessSpec{},
+		p.GetResultTypes(),
+	)
+	p.Processors[p.ResultRouters[0]].Spec.Input[0].Type = execinfrapb.InputSyncSpec_SERIAL_UNORDERED
+}
+
 // CheckLastStagePost checks that the processors of the last stage of the
 // PhysicalPlan have identical post-processing, returning an error if not.
 func (p *PhysicalPlan) CheckLastStagePost() error {
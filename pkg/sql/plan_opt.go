@@ -14,13 +14,17 @@ import (
 	"context"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/execbuilder"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/explain"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/indexrec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
@@ -30,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmthints"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 )
@@ -197,6 +202,21 @@ func (p *planner) makeOptimizerPlan(ctx context.Context) error {
 		return err
 	}
 
+	if opc.shouldMakeIndexRecommendations(execMemo) {
+		recs, err := opc.makeIndexRecommendations(ctx, execMemo)
+		if err != nil {
+			// Index recommendations are best-effort; failing to produce them
+			// should not prevent the statement from running.
+			log.VEventf(ctx, 1, "unable to make index recommendations: %v", err)
+			recs = nil
+		}
+		if _, isExplain := execMemo.RootExpr().(*memo.ExplainExpr); isExplain {
+			opc.indexRecommendations = recs
+		} else {
+			p.instrumentation.RecordIndexRecommendations(recs)
+		}
+	}
+
 	// Build the plan tree.
 	if mode := p.SessionData().ExperimentalDistSQLPlanningMode; mode != sessiondata.ExperimentalDistSQLPlanningOff {
 		planningMode := distSQLDefaultPlanning
@@ -288,6 +308,14 @@ type optPlanningCtx struct {
 	// allowMemoReuse is false.
 	useCache bool
 
+	// hints are the statement hints that apply to the statement, if any.
+	hints *stmthints.Hints
+
+	// indexRecommendations are the indexes that would make the explained
+	// statement cheaper, if any. They are only set when the statement is an
+	// EXPLAIN of the plan, and are shown in its output.
+	indexRecommendations []indexrec.Recommendation
+
 	flags planFlags
 }
 
@@ -304,6 +332,13 @@ func (opc *optPlanningCtx) reset() {
 	opc.catalog.reset()
 	opc.optimizer.Init(p.EvalContext(), &opc.catalog)
 	opc.flags = 0
+	opc.hints = p.statementHints()
+	opc.indexRecommendations = nil
+	if hints := opc.hints; hints.HasDisabledRules() {
+		opc.optimizer.NotifyOnMatchedRule(func(ruleName opt.RuleName) bool {
+			return !hints.RuleDisabled(ruleName)
+		})
+	}
 
 	// We only allow memo caching for SELECT/INSERT/UPDATE/DELETE. We could
 	// support it for all statements in principle, but it would increase the
@@ -331,6 +366,14 @@ func (opc *optPlanningCtx) reset() {
 		opc.allowMemoReuse = false
 		opc.useCache = false
 	}
+
+	if opc.hints != nil {
+		// Memos are cached by SQL string, and don't record which hints they were
+		// built with. Hinted statements are always planned from scratch, so that
+		// hints added or removed after a memo was cached take effect.
+		opc.allowMemoReuse = false
+		opc.useCache = false
+	}
 }
 
 func (opc *optPlanningCtx) log(ctx context.Context, msg string) {
@@ -378,6 +421,7 @@ func (opc *optPlanningCtx) buildReusableMemo(ctx context.Context) (_ *memo.Memo,
 	f := opc.optimizer.Factory()
 	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &opc.catalog, f, opc.p.stmt.AST)
 	bld.KeepPlaceholders = true
+	bld.StatementHints = opc.hints
 	if err := bld.Build(); err != nil {
 		return nil, err
 	}
@@ -506,6 +550,7 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 	f := opc.optimizer.Factory()
 	f.FoldingControl().AllowStableFolds()
 	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &opc.catalog, f, opc.p.stmt.AST)
+	bld.StatementHints = opc.hints
 	if err := bld.Build(); err != nil {
 		return nil, err
 	}
@@ -549,6 +594,13 @@ func (opc *optPlanningCtx) runExecBuilder(
 	var isDDL bool
 	var containsFullTableScan bool
 	var containsFullIndexScan bool
+	var gistFactory *explain.PlanGistFactory
+	if planTop.instrumentation.ShouldCollectPlanGist() {
+		// The plan gist factory is wrapped by the explain factory (if any), so
+		// that the execbuilder can still annotate the explain nodes.
+		gistFactory = explain.NewPlanGistFactory(f)
+		f = gistFactory
+	}
 	if !planTop.instrumentation.ShouldBuildExplainPlan() {
 		// No instrumentation.
 		bld := execbuilder.New(f, mem, &opc.catalog, mem.RootExpr(), evalCtx, allowAutoCommit)
@@ -574,7 +626,10 @@ func (opc *optPlanningCtx) runExecBuilder(
 		containsFullTableScan = bld.ContainsFullTableScan
 		containsFullIndexScan = bld.ContainsFullIndexScan
 
-		planTop.instrumentation.RecordExplainPlan(explainPlan)
+		planTop.instrumentation.RecordExplainPlan(explainPlan, opc.hints)
+	}
+	if gistFactory != nil {
+		planTop.instrumentation.RecordPlanGist(gistFactory.PlanGist())
 	}
 
 	if stmt.ExpectedTypes != nil {
@@ -600,3 +655,78 @@ func (opc *optPlanningCtx) runExecBuilder(
 	}
 	return nil
 }
+
+// shouldMakeIndexRecommendations returns true if index recommendations should
+// be made for the statement: either it is an EXPLAIN of the plan and the
+// index_recommendations_enabled session setting is on, or the plan of the
+// statement is being sampled for statement statistics.
+func (opc *optPlanningCtx) shouldMakeIndexRecommendations(execMemo *memo.Memo) bool {
+	p := opc.p
+	if strings.HasPrefix(p.SessionData().ApplicationName, catconstants.InternalAppNamePrefix) {
+		return false
+	}
+	if e, ok := execMemo.RootExpr().(*memo.ExplainExpr); ok {
+		return e.Options.Mode == tree.ExplainPlan && !e.Analyze &&
+			p.SessionData().IndexRecommendationsEnabled
+	}
+	return p.instrumentation.ShouldSaveIndexRecommendations()
+}
+
+// makeIndexRecommendations determines whether there are indexes that, if they
+// existed, would allow the optimizer to find a cheaper plan for the statement.
+// Candidate indexes are derived from the plan in execMemo and added to the
+// catalog as hypothetical indexes, after which the statement is optimized
+// again. The hypothetical indexes used by the new plan are recommended if that
+// plan is cheaper than the original one.
+func (opc *optPlanningCtx) makeIndexRecommendations(
+	ctx context.Context, execMemo *memo.Memo,
+) ([]indexrec.Recommendation, error) {
+	p := opc.p
+	stmt := opc.p.stmt.AST
+	root := execMemo.RootExpr().(memo.RelExpr)
+	if e, ok := root.(*memo.ExplainExpr); ok {
+		root = e.Input
+		stmt = stmt.(*tree.Explain).Statement
+	}
+	switch stmt.(type) {
+	case *tree.Select, *tree.ParenSelect, *tree.SelectClause, *tree.Update, *tree.Delete:
+	default:
+		return nil, nil
+	}
+
+	// Only recommend indexes on user tables.
+	candidates := make(map[cat.StableID][]indexrec.Candidate)
+	for _, c := range indexrec.FindCandidates(root, execMemo.Metadata(), p.EvalContext()) {
+		if tab, ok := c.Table.(*optTable); ok && tab.desc.ID > keys.MaxReservedDescID {
+			candidates[tab.ID()] = append(candidates[tab.ID()], c)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	var catalog optHypotheticalCatalog
+	catalog.init(&opc.catalog, candidates)
+	var o xform.Optimizer
+	o.Init(p.EvalContext(), &catalog)
+	if hints := opc.hints; hints.HasDisabledRules() {
+		o.NotifyOnMatchedRule(func(ruleName opt.RuleName) bool {
+			return !hints.RuleDisabled(ruleName)
+		})
+	}
+	f := o.Factory()
+	f.FoldingControl().AllowStableFolds()
+	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &catalog, f, stmt)
+	bld.StatementHints = opc.hints
+	if err := bld.Build(); err != nil {
+		return nil, err
+	}
+	if _, err := o.Optimize(); err != nil {
+		return nil, err
+	}
+	hypRoot := o.Memo().RootExpr().(memo.RelExpr)
+	if hypRoot.Cost() >= root.Cost() {
+		return nil, nil
+	}
+	return indexrec.FindRecommendations(ctx, hypRoot, o.Memo().Metadata(), &catalog)
+}
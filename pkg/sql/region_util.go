@@ -16,11 +16,17 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/errors"
 )
 
@@ -145,3 +151,162 @@ func (p *planner) applyZoneConfigFromRegionConfigForDatabase(
 	}
 	return nil
 }
+
+// genZoneConfigFromRegionConfigForRegion generates a desired ZoneConfig for a
+// table or partition which is homed in the given region. It matches the zone
+// configuration of the database, but places leaseholders in the given region.
+func genZoneConfigFromRegionConfigForRegion(
+	regionConfig descpb.DatabaseDescriptor_RegionConfig, region descpb.Region,
+) *zonepb.ZoneConfig {
+	zc := genZoneConfigFromRegionConfigForDatabase(regionConfig)
+	zc.LeasePreferences = []zonepb.LeasePreference{
+		{Constraints: []zonepb.Constraint{makeRequiredZoneConstraintForRegion(region)}},
+	}
+	return zc
+}
+
+// makeTableLocalityConfig converts the LOCALITY clause of a statement into the
+// locality config of a table descriptor.
+func makeTableLocalityConfig(
+	locality *tree.Locality,
+) (*descpb.TableDescriptor_LocalityConfig, error) {
+	ret := &descpb.TableDescriptor_LocalityConfig{}
+	switch locality.LocalityLevel {
+	case tree.LocalityLevelGlobal:
+		ret.Locality = &descpb.TableDescriptor_LocalityConfig_Global_{
+			Global: &descpb.TableDescriptor_LocalityConfig_Global{},
+		}
+	case tree.LocalityLevelTable:
+		l := &descpb.TableDescriptor_LocalityConfig_RegionalByTable_{
+			RegionalByTable: &descpb.TableDescriptor_LocalityConfig_RegionalByTable{},
+		}
+		if locality.TableRegion != "" {
+			region := descpb.Region(locality.TableRegion)
+			l.RegionalByTable.Region = &region
+		}
+		ret.Locality = l
+	case tree.LocalityLevelRow:
+		ret.Locality = &descpb.TableDescriptor_LocalityConfig_RegionalByRow_{
+			RegionalByRow: &descpb.TableDescriptor_LocalityConfig_RegionalByRow{},
+		}
+	default:
+		return nil, errors.Newf("unknown locality level: %v", locality.LocalityLevel)
+	}
+	return ret, nil
+}
+
+// partitionByRegion returns a list partitioning of an index whose first column
+// is the region column of a REGIONAL BY ROW table, with one partition named
+// after each region.
+func partitionByRegion(
+	regionType *types.T, regions descpb.Regions,
+) (descpb.PartitioningDescriptor, error) {
+	partitioning := descpb.PartitioningDescriptor{NumColumns: 1}
+	for _, region := range regions {
+		d, err := tree.MakeDEnumFromLogicalRepresentation(regionType, string(region))
+		if err != nil {
+			return descpb.PartitioningDescriptor{}, err
+		}
+		value, err := rowenc.EncodeTableValue(
+			nil /* appendTo */, descpb.ColumnID(encoding.NoColumnID), d, nil, /* scratch */
+		)
+		if err != nil {
+			return descpb.PartitioningDescriptor{}, err
+		}
+		partitioning.List = append(partitioning.List, descpb.PartitioningDescriptor_List{
+			Name:   string(region),
+			Values: [][]byte{value},
+		})
+	}
+	return partitioning, nil
+}
+
+// applyZoneConfigForMultiRegionTable rewrites the zone configuration of a
+// table in a multi-region database to match the given locality. Tables which
+// are REGIONAL BY TABLE in a region other than the primary region keep their
+// leaseholders in that region, and each partition of a REGIONAL BY ROW table
+// keeps its leaseholders in the region it is named after. Otherwise, the table
+// inherits the zone configuration of the database. Noop if run on behalf of a
+// tenant.
+func applyZoneConfigForMultiRegionTable(
+	ctx context.Context,
+	txn *kv.Txn,
+	execCfg *ExecutorConfig,
+	regionConfig descpb.DatabaseDescriptor_RegionConfig,
+	table *tabledesc.Mutable,
+	localityConfig descpb.TableDescriptor_LocalityConfig,
+) error {
+	if !execCfg.Codec.ForSystemTenant() {
+		// Tenants are agnostic to zone configs.
+		return nil
+	}
+	zone, err := getZoneConfigRaw(ctx, txn, execCfg.Codec, table.ID)
+	if err != nil {
+		return err
+	}
+	if zone == nil {
+		zone = zonepb.NewZoneConfig()
+		zone.DeleteTableConfig()
+	}
+
+	// Reset the fields which are derived from the locality of the table.
+	if !zone.IsSubzonePlaceholder() {
+		zone.NumReplicas = nil
+		zone.Constraints = nil
+		zone.InheritedConstraints = true
+		zone.LeasePreferences = nil
+		zone.InheritedLeasePreferences = true
+	}
+
+	hasNewSubzones := false
+	switch l := localityConfig.Locality.(type) {
+	case *descpb.TableDescriptor_LocalityConfig_RegionalByTable_:
+		if region := l.RegionalByTable.Region; region != nil && *region != regionConfig.PrimaryRegion {
+			regionZone := genZoneConfigFromRegionConfigForRegion(regionConfig, *region)
+			zone.NumReplicas = regionZone.NumReplicas
+			zone.Constraints = regionZone.Constraints
+			zone.InheritedConstraints = false
+			zone.LeasePreferences = regionZone.LeasePreferences
+			zone.InheritedLeasePreferences = false
+		}
+	case *descpb.TableDescriptor_LocalityConfig_RegionalByRow_:
+		regionColumn, _, err := table.FindColumnByName(tree.Name(tree.RegionalByRowRegionDefaultCol))
+		if err != nil {
+			return err
+		}
+		if err := table.ForeachIndex(catalog.IndexOpts{
+			AddMutations: true,
+		}, func(idxDesc *descpb.IndexDescriptor, _ bool) error {
+			if idxDesc.Partitioning.NumColumns != 1 ||
+				!isIndexPrefixedByColumn(idxDesc, regionColumn.ID) {
+				return nil
+			}
+			for _, p := range idxDesc.Partitioning.List {
+				zone.SetSubzone(zonepb.Subzone{
+					IndexID:       uint32(idxDesc.ID),
+					PartitionName: p.Name,
+					Config: *genZoneConfigFromRegionConfigForRegion(
+						regionConfig, descpb.Region(p.Name),
+					),
+				})
+				hasNewSubzones = true
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	// If none of the fields of the table's zone configuration remain set, it
+	// only exists to hold the configuration of its subzones.
+	if !zone.IsSubzonePlaceholder() {
+		tableZone := *zone
+		tableZone.Subzones, tableZone.SubzoneSpans = nil, nil
+		if tableZone.Equal(zonepb.NewZoneConfig()) {
+			zone.DeleteTableConfig()
+		}
+	}
+
+	_, err = writeZoneConfig(ctx, txn, table.ID, table, zone, execCfg, hasNewSubzones)
+	return err
+}
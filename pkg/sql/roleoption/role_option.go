@@ -11,6 +11,7 @@
 package roleoption
 
 import (
+	"net"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -55,6 +56,8 @@ const (
 	NOCANCELQUERY
 	MODIFYCLUSTERSETTING
 	NOMODIFYCLUSTERSETTING
+	AUTHENTICATION
+	CONNECTIONFROM
 )
 
 // toSQLStmts is a map of Kind -> SQL statement string for applying the
@@ -79,6 +82,15 @@ var toSQLStmts = map[Option]string{
 	NOCANCELQUERY:          `DELETE FROM system.role_options WHERE username = $1 AND option = 'CANCELQUERY'`,
 	MODIFYCLUSTERSETTING:   `UPSERT INTO system.role_options (username, option) VALUES ($1, 'MODIFYCLUSTERSETTING')`,
 	NOMODIFYCLUSTERSETTING: `DELETE FROM system.role_options WHERE username = $1 AND option = 'MODIFYCLUSTERSETTING'`,
+	AUTHENTICATION:         `UPSERT INTO system.role_options (username, option, value) VALUES ($1, 'AUTHENTICATION', $2)`,
+	CONNECTIONFROM:         `UPSERT INTO system.role_options (username, option, value) VALUES ($1, 'CONNECTION FROM', $2)`,
+}
+
+// valueCheckers is a map of Kind -> function validating the value of the
+// option before it is applied to the role.
+var valueCheckers = map[Option]func(string) error{
+	AUTHENTICATION: checkAuthenticationMethod,
+	CONNECTIONFROM: checkConnectionAddress,
 }
 
 // Mask returns the bitmask for a given role option.
@@ -108,6 +120,8 @@ var ByName = map[string]Option{
 	"NOCANCELQUERY":          NOCANCELQUERY,
 	"MODIFYCLUSTERSETTING":   MODIFYCLUSTERSETTING,
 	"NOMODIFYCLUSTERSETTING": NOMODIFYCLUSTERSETTING,
+	"AUTHENTICATION":         AUTHENTICATION,
+	"CONNECTION_FROM":        CONNECTIONFROM,
 }
 
 // ToOption takes a string and returns the corresponding Option.
@@ -153,6 +167,9 @@ func (rol List) GetSQLStmts(op string) (map[string]func() (bool, string, error),
 		stmt := toSQLStmts[ro.Option]
 		if ro.HasValue {
 			stmts[stmt] = ro.Value
+			if check, ok := valueCheckers[ro.Option]; ok {
+				stmts[stmt] = checkedValue(ro.Value, check)
+			}
 		} else {
 			stmts[stmt] = nil
 		}
@@ -161,6 +178,43 @@ func (rol List) GetSQLStmts(op string) (map[string]func() (bool, string, error),
 	return stmts, nil
 }
 
+// checkedValue wraps the value function of a role option so that non-NULL
+// values are validated before being applied.
+func checkedValue(
+	value func() (bool, string, error), check func(string) error,
+) func() (bool, string, error) {
+	return func() (bool, string, error) {
+		isNull, val, err := value()
+		if err != nil || isNull {
+			return isNull, val, err
+		}
+		return isNull, val, check(val)
+	}
+}
+
+// checkAuthenticationMethod verifies that the value of the AUTHENTICATION
+// option looks like an authentication method name. Whether the method is
+// known is only determined when the role attempts to log in, since methods
+// can be registered by optional modules.
+func checkAuthenticationMethod(method string) error {
+	if method == "" || strings.ContainsAny(method, " \t\n,#\"") {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"invalid authentication method: %q", method)
+	}
+	return nil
+}
+
+// checkConnectionAddress verifies that the value of the CONNECTION FROM
+// option is a network address in CIDR notation.
+func checkConnectionAddress(addr string) error {
+	if _, _, err := net.ParseCIDR(addr); err != nil {
+		return errors.WithHint(
+			pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid connection address %q", addr),
+			"Use the CIDR notation, for example: 10.0.0.0/8.")
+	}
+	return nil
+}
+
 // ToBitField returns the bitfield representation of
 // a list of role options.
 func (rol List) ToBitField() (uint32, error) {
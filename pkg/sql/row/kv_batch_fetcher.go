@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/errors"
@@ -79,6 +80,10 @@ type txnKVFetcher struct {
 	remainingBatches [][]byte
 	mon              *mon.BytesMonitor
 	acc              mon.BoundAccount
+
+	// contentionEvents accumulates the contention events reported by KV in
+	// the responses to the requests issued by this fetcher.
+	contentionEvents []roachpb.ContentionEvent
 }
 
 var _ kvBatchFetcher = &txnKVFetcher{}
@@ -140,9 +145,10 @@ func (f *txnKVFetcher) getKeyLockingStrength() lock.Strength {
 		// Promote to FOR_SHARE.
 		fallthrough
 	case descpb.ScanLockingStrength_FOR_SHARE:
-		// We currently perform no per-key locking when FOR_SHARE is used
-		// because Shared locks have not yet been implemented.
-		return lock.None
+		// Shared locks are only supported with replicated durability. If the
+		// transaction's coordinator is not configured to acquire replicated
+		// locks, it performs the scan without per-key locking.
+		return lock.Shared
 
 	case descpb.ScanLockingStrength_FOR_NO_KEY_UPDATE:
 		// Promote to FOR_UPDATE.
@@ -165,8 +171,7 @@ func (f *txnKVFetcher) getWaitPolicy() lock.WaitPolicy {
 		return lock.WaitPolicy_Block
 
 	case descpb.ScanLockingWaitPolicy_SKIP:
-		// Should not get here. Query should be rejected during planning.
-		panic(errors.AssertionFailedf("unsupported wait policy %s", f.lockWaitPolicy))
+		return lock.WaitPolicy_SkipLocked
 
 	case descpb.ScanLockingWaitPolicy_ERROR:
 		return lock.WaitPolicy_Error
@@ -198,6 +203,21 @@ func makeKVBatchFetcher(
 		if err != nil {
 			return nil, err.GoError()
 		}
+		// Processing the response consumes CPU on this node, so it is subject
+		// to admission control when the request was issued on behalf of SQL.
+		if q := txn.DB().SQLKVResponseAdmissionQ; q != nil {
+			if h := txn.AdmissionHeader(); h.Source == roachpb.AdmissionHeader_FROM_SQL {
+				if _, err := q.Admit(ctx, admission.WorkInfo{
+					// Only the system tenant runs SQL on the nodes that admit
+					// this work.
+					TenantID:   roachpb.SystemTenantID,
+					Priority:   admission.WorkPriority(h.Priority),
+					CreateTime: h.CreateTime,
+				}); err != nil {
+					return nil, err
+				}
+			}
+		}
 		return res, nil
 	}
 	return makeKVBatchFetcherWithSendFunc(
@@ -359,6 +379,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 	}
 	if br != nil {
 		f.responses = br.Responses
+		f.contentionEvents = append(f.contentionEvents, br.ContentionEvents...)
 	} else {
 		f.responses = nil
 	}
@@ -90,6 +90,14 @@ func (f *KVFetcher) GetContentionEvents() []roachpb.ContentionEvent {
 	if f == nil {
 		return nil
 	}
+	if t, ok := f.kvBatchFetcher.(*txnKVFetcher); ok && len(t.contentionEvents) > 0 {
+		if len(f.contentionEvents) == 0 {
+			return t.contentionEvents
+		}
+		events := make([]roachpb.ContentionEvent, 0, len(f.contentionEvents)+len(t.contentionEvents))
+		events = append(events, f.contentionEvents...)
+		return append(events, t.contentionEvents...)
+	}
 	return f.contentionEvents
 }
 
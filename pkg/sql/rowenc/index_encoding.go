@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/cockroachdb/cockroach/pkg/util/unique"
 	"github.com/cockroachdb/errors"
 )
@@ -788,8 +789,10 @@ func EncodeInvertedIndexKeys(
 }
 
 // EncodeInvertedIndexTableKeys produces one inverted index key per element in
-// the input datum, which should be a container (either JSON or Array). For
-// JSON, "element" means unique path through the document. Each output key is
+// the input datum, which should be a container (either JSON or Array), a
+// string or a tsvector. For JSON, "element" means unique path through the
+// document. For strings, "element" means trigram (see trigram.MakeTrigrams).
+// For tsvectors, "element" means lexeme. Each output key is
 // prefixed by inKey, and is guaranteed to be lexicographically sortable, but
 // not guaranteed to be round-trippable during decoding. If the input Datum
 // is (SQL) NULL, no inverted index keys will be produced, because inverted
@@ -815,10 +818,52 @@ func EncodeInvertedIndexTableKeys(
 		return json.EncodeInvertedIndexKeys(inKey, val.(*tree.DJSON).JSON)
 	case types.ArrayFamily:
 		return encodeArrayInvertedIndexTableKeys(val.(*tree.DArray), inKey, version)
+	case types.StringFamily:
+		return EncodeTrigramInvertedIndexKeys(
+			trigram.MakeTrigrams(string(tree.MustBeDString(datum)), true /* pad */), inKey,
+		), nil
+	case types.TSVectorFamily:
+		return encodeStringsInvertedIndexKeys(tree.MustBeDTSVector(datum).Lexemes(), inKey), nil
 	}
 	return nil, errors.AssertionFailedf("trying to apply inverted index to unsupported type %s", datum.ResolvedType())
 }
 
+// EncodeTrigramInvertedIndexKeys returns the inverted index keys of the given
+// trigrams, one per trigram, in the same order. The input inKey is prefixed to
+// all returned keys.
+func EncodeTrigramInvertedIndexKeys(trigrams []string, inKey []byte) [][]byte {
+	return encodeStringsInvertedIndexKeys(trigrams, inKey)
+}
+
+// EncodeLexemeInvertedIndexKey returns the inverted index key of the given
+// tsvector lexeme. If prefix is true, the key is instead the common prefix of
+// the keys of all lexemes that start with the given lexeme. The input inKey is
+// prefixed to the returned key.
+func EncodeLexemeInvertedIndexKey(lexeme string, prefix bool, inKey []byte) []byte {
+	outKey := make([]byte, len(inKey), len(inKey)+len(lexeme)+2)
+	copy(outKey, inKey)
+	outKey = encoding.EncodeStringAscending(outKey, lexeme)
+	if prefix {
+		// Remove the terminator, which is the only part of the encoding of a
+		// string that is not a prefix of the encoding of its extensions.
+		outKey = outKey[:len(outKey)-2]
+	}
+	return outKey
+}
+
+// encodeStringsInvertedIndexKeys returns the inverted index keys of the given
+// strings, one per string, in the same order. The input inKey is prefixed to
+// all returned keys.
+func encodeStringsInvertedIndexKeys(strs []string, inKey []byte) [][]byte {
+	outKeys := make([][]byte, len(strs))
+	for i, s := range strs {
+		outKey := make([]byte, len(inKey), len(inKey)+len(s)+2)
+		copy(outKey, inKey)
+		outKeys[i] = encoding.EncodeStringAscending(outKey, s)
+	}
+	return outKeys
+}
+
 // EncodeContainingInvertedIndexSpans takes in a key prefix and returns the
 // spans that must be scanned in the inverted index to evaluate a contains (@>)
 // predicate with the given datum, which should be a container (either JSON
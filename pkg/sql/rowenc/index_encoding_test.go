@@ -67,7 +67,7 @@ func makeTableDescForTest(test indexKeyTest) (*tabledesc.Immutable, catalog.Tabl
 			primaryColumnIDs[i] = columns[i].ID
 		} else {
 			columns[i].Type = test.secondaryValues[i-len(test.primaryValues)].ResolvedType()
-			if colinfo.ColumnTypeIsInvertedIndexable(columns[i].Type) {
+			if colinfo.ColumnTypeIsOnlyInvertedIndexable(columns[i].Type) {
 				secondaryType = descpb.IndexDescriptor_INVERTED
 			}
 			secondaryColumnIDs[i-len(test.primaryValues)] = columns[i].ID
@@ -48,7 +48,7 @@ func TestRandParseDatumStringAs(t *testing.T) {
 	},
 		types.Scalar...)
 	for _, ty := range types.Scalar {
-		if ty != types.Jsonb {
+		if ok, _ := types.IsValidArrayElementType(ty); ok {
 			tests = append(tests, types.MakeArray(ty))
 		}
 	}
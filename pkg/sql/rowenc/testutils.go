@@ -43,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -173,6 +174,10 @@ func RandDatumWithNullChance(rng *rand.Rand, typ *types.T, nullChance int) tree.
 			return nil
 		}
 		return &tree.DJSON{JSON: j}
+	case types.TSQueryFamily:
+		return tree.NewDTSQuery(tsearch.RandomTSQuery(rng))
+	case types.TSVectorFamily:
+		return tree.NewDTSVector(tsearch.RandomTSVector(rng))
 	case types.TupleFamily:
 		tuple := tree.DTuple{D: make(tree.Datums, len(typ.TupleContents()))}
 		for i := range typ.TupleContents() {
@@ -321,6 +326,18 @@ func RandDatumSimple(rng *rand.Rand, typ *types.T) tree.Datum {
 		})
 	case types.JsonFamily:
 		datum = tree.NewDJSON(randJSONSimple(rng))
+	case types.TSQueryFamily:
+		q, err := tsearch.ParseTSQuery(randStringSimple(rng))
+		if err != nil {
+			panic(err)
+		}
+		datum = tree.NewDTSQuery(q)
+	case types.TSVectorFamily:
+		v, err := tsearch.ParseTSVector(randStringSimple(rng))
+		if err != nil {
+			panic(err)
+		}
+		datum = tree.NewDTSVector(v)
 	case types.OidFamily:
 		datum = tree.NewDOid(tree.DInt(rng.Intn(simpleRange)))
 	case types.StringFamily:
@@ -635,6 +652,36 @@ var (
 			}
 			return res
 		}(),
+		types.TSQueryFamily: func() []tree.Datum {
+			var res []tree.Datum
+			for _, s := range []string{
+				``,
+				`a`,
+				`!a & (b:* | c:AB) <-> d`,
+			} {
+				d, err := tree.ParseDTSQuery(s)
+				if err != nil {
+					panic(err)
+				}
+				res = append(res, d)
+			}
+			return res
+		}(),
+		types.TSVectorFamily: func() []tree.Datum {
+			var res []tree.Datum
+			for _, s := range []string{
+				``,
+				`a`,
+				`'it''s' a:1,2A b:3`,
+			} {
+				d, err := tree.ParseDTSVector(s)
+				if err != nil {
+					panic(err)
+				}
+				res = append(res, d)
+			}
+			return res
+		}(),
 		types.BitFamily: func() []tree.Datum {
 			var res []tree.Datum
 			for _, i := range []int64{
@@ -0,0 +1,353 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rowexec
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/optional"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// indexSkipTableReader is a processor that performs a skip scan (also known as
+// a loose index scan) over an index. For each distinct value of the first
+// keyPrefixLen index key columns in its spans, it returns only the first row in
+// the scan direction, and then seeks directly to the next distinct prefix
+// instead of reading the remaining rows with the same prefix.
+type indexSkipTableReader struct {
+	execinfra.ProcessorBase
+
+	spans roachpb.Spans
+
+	// currentSpan is the index of the span currently being scanned. Spans are
+	// scanned from first to last for a forward scan and from last to first for
+	// a reverse scan.
+	currentSpan int
+
+	// keyPrefixLen is the number of index key columns that make up the prefix
+	// being skipped over.
+	keyPrefixLen int
+
+	reverse bool
+
+	ignoreMisplannedRanges bool
+
+	// fetcher wraps a row.Fetcher, allowing the indexSkipTableReader to add a
+	// stat collection layer.
+	fetcher rowFetcher
+	alloc   rowenc.DatumAlloc
+
+	// rowsRead is the number of rows read and is tracked unconditionally.
+	rowsRead int64
+}
+
+var _ execinfra.Processor = &indexSkipTableReader{}
+var _ execinfra.RowSource = &indexSkipTableReader{}
+var _ execinfrapb.MetadataSource = &indexSkipTableReader{}
+var _ execinfra.Releasable = &indexSkipTableReader{}
+var _ execinfra.OpNode = &indexSkipTableReader{}
+var _ execinfra.KVReader = &indexSkipTableReader{}
+
+const indexSkipTableReaderProcName = "index skip table reader"
+
+var istrPool = sync.Pool{
+	New: func() interface{} {
+		return &indexSkipTableReader{}
+	},
+}
+
+// newIndexSkipTableReader creates an indexSkipTableReader.
+func newIndexSkipTableReader(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec *execinfrapb.IndexSkipTableReaderSpec,
+	post *execinfrapb.PostProcessSpec,
+	output execinfra.RowReceiver,
+) (*indexSkipTableReader, error) {
+	// NB: we hit this with a zero NodeID (but !ok) with multi-tenancy.
+	if nodeID, ok := flowCtx.NodeID.OptionalNodeID(); ok && nodeID == 0 {
+		return nil, errors.Errorf("attempting to create an indexSkipTableReader with uninitialized NodeID")
+	}
+	if spec.KeyPrefixLen == 0 {
+		return nil, errors.AssertionFailedf("indexSkipTableReader requires a non-empty key prefix")
+	}
+
+	t := istrPool.Get().(*indexSkipTableReader)
+
+	tableDesc := tabledesc.NewImmutable(spec.Table)
+	returnMutations := spec.Visibility == execinfra.ScanVisibilityPublicAndNotPublic
+	resultTypes := tableDesc.ColumnTypesWithMutations(returnMutations)
+	columnIdxMap := tableDesc.ColumnIdxMapWithMutations(returnMutations)
+
+	t.ignoreMisplannedRanges = flowCtx.Local
+	t.reverse = spec.Reverse
+	t.keyPrefixLen = int(spec.KeyPrefixLen)
+
+	if err := t.Init(
+		t,
+		post,
+		resultTypes,
+		flowCtx,
+		processorID,
+		output,
+		nil, /* memMonitor */
+		execinfra.ProcStateOpts{
+			// As with the tableReader, the fetcher is not an input to drain.
+			InputsToDrain:        nil,
+			TrailingMetaCallback: t.generateTrailingMeta,
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	neededColumns := t.Out.NeededColumns()
+
+	var fetcher row.Fetcher
+	if _, _, err := initRowFetcher(
+		flowCtx,
+		&fetcher,
+		tableDesc,
+		int(spec.IndexIdx),
+		columnIdxMap,
+		spec.Reverse,
+		neededColumns,
+		false, /* isCheck */
+		flowCtx.EvalCtx.Mon,
+		&t.alloc,
+		spec.Visibility,
+		spec.LockingStrength,
+		spec.LockingWaitPolicy,
+		nil, /* systemColumns */
+	); err != nil {
+		return nil, err
+	}
+
+	// The spans are modified as the scan makes progress, so they are copied
+	// from the spec.
+	nSpans := len(spec.Spans)
+	if cap(t.spans) >= nSpans {
+		t.spans = t.spans[:nSpans]
+	} else {
+		t.spans = make(roachpb.Spans, nSpans)
+	}
+	for i, s := range spec.Spans {
+		t.spans[i] = s.Span
+	}
+
+	if sp := tracing.SpanFromContext(flowCtx.EvalCtx.Ctx()); sp != nil && sp.IsRecording() {
+		t.fetcher = newRowFetcherStatCollector(&fetcher)
+		t.ExecStatsForTrace = t.execStatsForTrace
+	} else {
+		t.fetcher = &fetcher
+	}
+
+	return t, nil
+}
+
+func (t *indexSkipTableReader) generateTrailingMeta(
+	ctx context.Context,
+) []execinfrapb.ProducerMetadata {
+	trailingMeta := t.generateMeta(ctx)
+	t.close()
+	return trailingMeta
+}
+
+// Start is part of the RowSource interface.
+func (t *indexSkipTableReader) Start(ctx context.Context) context.Context {
+	if t.FlowCtx.Txn == nil {
+		log.Fatalf(ctx, "indexSkipTableReader outside of txn")
+	}
+
+	ctx = t.StartInternal(ctx, indexSkipTableReaderProcName)
+	if t.reverse {
+		t.currentSpan = len(t.spans) - 1
+	} else {
+		t.currentSpan = 0
+	}
+	return ctx
+}
+
+// Release releases this indexSkipTableReader back to the pool.
+func (t *indexSkipTableReader) Release() {
+	t.ProcessorBase.Reset()
+	t.fetcher.Reset()
+	*t = indexSkipTableReader{
+		ProcessorBase: t.ProcessorBase,
+		fetcher:       t.fetcher,
+		spans:         t.spans[:0],
+	}
+	istrPool.Put(t)
+}
+
+// nextSpan moves on to the next span in the scan direction.
+func (t *indexSkipTableReader) nextSpan() {
+	if t.reverse {
+		t.currentSpan--
+	} else {
+		t.currentSpan++
+	}
+}
+
+// Next is part of the RowSource interface.
+func (t *indexSkipTableReader) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	for t.State == execinfra.StateRunning {
+		if t.currentSpan < 0 || t.currentSpan >= len(t.spans) {
+			t.MoveToDraining(nil /* err */)
+			break
+		}
+
+		// Start a new scan of the remainder of the current span; the first row
+		// it returns is the first row with the next distinct prefix.
+		err := t.fetcher.StartScan(
+			t.Ctx, t.FlowCtx.Txn, t.spans[t.currentSpan:t.currentSpan+1],
+			true /* limitBatches */, 1 /* limitHint */, t.FlowCtx.TraceKV,
+		)
+		if err != nil {
+			t.MoveToDraining(err)
+			break
+		}
+
+		// Get the prefix of the key of the row that is about to be returned.
+		// This key must not be modified, since it is owned by the fetcher.
+		key, err := t.fetcher.PartialKey(t.keyPrefixLen)
+		if err != nil {
+			t.MoveToDraining(err)
+			break
+		}
+
+		row, _, _, err := t.fetcher.NextRow(t.Ctx)
+		if err != nil {
+			t.MoveToDraining(err)
+			break
+		}
+		if row == nil {
+			// There are no more rows in the current span.
+			t.nextSpan()
+			continue
+		}
+		t.rowsRead++
+
+		// Skip the remaining rows with the same prefix by narrowing the current
+		// span so that it starts (or, for a reverse scan, ends) at the next
+		// distinct prefix.
+		if t.reverse {
+			// The end key of a span is exclusive, so the prefix itself is the end
+			// of the remaining keys with smaller prefixes.
+			t.spans[t.currentSpan].EndKey = key
+		} else {
+			t.spans[t.currentSpan].Key = key.PrefixEnd()
+		}
+		if !t.spans[t.currentSpan].Valid() {
+			t.nextSpan()
+		}
+
+		if outRow := t.ProcessRowHelper(row); outRow != nil {
+			return outRow, nil
+		}
+	}
+	return nil, t.DrainHelper()
+}
+
+func (t *indexSkipTableReader) close() {
+	if t.InternalClose() {
+		if t.fetcher != nil {
+			t.fetcher.Close(t.Ctx)
+		}
+	}
+}
+
+// ConsumerClosed is part of the RowSource interface.
+func (t *indexSkipTableReader) ConsumerClosed() {
+	t.close()
+}
+
+// execStatsForTrace implements ProcessorBase.ExecStatsForTrace.
+func (t *indexSkipTableReader) execStatsForTrace() *execinfrapb.ComponentStats {
+	is, ok := getFetcherInputStats(t.fetcher)
+	if !ok {
+		return nil
+	}
+	return &execinfrapb.ComponentStats{
+		KV: execinfrapb.KVStats{
+			TuplesRead:     is.NumTuples,
+			BytesRead:      optional.MakeUint(uint64(t.GetBytesRead())),
+			KVTime:         is.WaitTime,
+			ContentionTime: optional.MakeTimeValue(t.GetCumulativeContentionTime()),
+		},
+		Output: t.Out.Stats(),
+	}
+}
+
+// GetBytesRead is part of the execinfra.KVReader interface.
+func (t *indexSkipTableReader) GetBytesRead() int64 {
+	return t.fetcher.GetBytesRead()
+}
+
+// GetRowsRead is part of the execinfra.KVReader interface.
+func (t *indexSkipTableReader) GetRowsRead() int64 {
+	return t.rowsRead
+}
+
+// GetCumulativeContentionTime is part of the execinfra.KVReader interface.
+func (t *indexSkipTableReader) GetCumulativeContentionTime() time.Duration {
+	return getCumulativeContentionTime(t.fetcher.GetContentionEvents())
+}
+
+func (t *indexSkipTableReader) generateMeta(ctx context.Context) []execinfrapb.ProducerMetadata {
+	var trailingMeta []execinfrapb.ProducerMetadata
+	if !t.ignoreMisplannedRanges {
+		nodeID, ok := t.FlowCtx.NodeID.OptionalNodeID()
+		if ok {
+			ranges := execinfra.MisplannedRanges(ctx, t.spans, nodeID, t.FlowCtx.Cfg.RangeCache)
+			if ranges != nil {
+				trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{Ranges: ranges})
+			}
+		}
+	}
+	if tfs := execinfra.GetLeafTxnFinalState(ctx, t.FlowCtx.Txn); tfs != nil {
+		trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{LeafTxnFinalState: tfs})
+	}
+
+	meta := execinfrapb.GetProducerMeta()
+	meta.Metrics = execinfrapb.GetMetricsMeta()
+	meta.Metrics.BytesRead, meta.Metrics.RowsRead = t.GetBytesRead(), t.GetRowsRead()
+	trailingMeta = append(trailingMeta, *meta)
+
+	if contentionEvents := t.fetcher.GetContentionEvents(); len(contentionEvents) != 0 {
+		trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{ContentionEvents: contentionEvents})
+	}
+	return trailingMeta
+}
+
+// DrainMeta is part of the MetadataSource interface.
+func (t *indexSkipTableReader) DrainMeta(ctx context.Context) []execinfrapb.ProducerMetadata {
+	return t.generateMeta(ctx)
+}
+
+// ChildCount is part of the execinfra.OpNode interface.
+func (t *indexSkipTableReader) ChildCount(bool) int {
+	return 0
+}
+
+// Child is part of the execinfra.OpNode interface.
+func (t *indexSkipTableReader) Child(nth int, _ bool) execinfra.OpNode {
+	panic(errors.AssertionFailedf("invalid index %d", nth))
+}
@@ -0,0 +1,210 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rowexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/distsqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+func TestIndexSkipTableReader(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	// Create a table where each row is:
+	//
+	//  |     a    |     b    |     c    |
+	//  |--------------------------------|
+	//  | rowId/10 | rowId%10 | rowId%3  |
+
+	aFn := func(row int) tree.Datum {
+		return tree.NewDInt(tree.DInt(row / 10))
+	}
+	bFn := func(row int) tree.Datum {
+		return tree.NewDInt(tree.DInt(row % 10))
+	}
+	cFn := func(row int) tree.Datum {
+		return tree.NewDInt(tree.DInt(row % 3))
+	}
+
+	sqlutils.CreateTable(t, sqlDB, "t",
+		"a INT, b INT, c INT, PRIMARY KEY (a, b), INDEX c (c)",
+		29,
+		sqlutils.ToRowFn(aFn, bFn, cFn))
+
+	td := catalogkv.TestingGetTableDescriptor(kvDB, keys.SystemSQLCodec, "test", "t")
+
+	makePrimaryIndexSpan := func(start, end int) execinfrapb.TableReaderSpan {
+		var span roachpb.Span
+		prefix := roachpb.Key(rowenc.MakeIndexKeyPrefix(keys.SystemSQLCodec, td, td.PrimaryIndex.ID))
+		span.Key = append(prefix, encoding.EncodeVarintAscending(nil, int64(start))...)
+		span.EndKey = append(span.EndKey, prefix...)
+		span.EndKey = append(span.EndKey, encoding.EncodeVarintAscending(nil, int64(end))...)
+		return execinfrapb.TableReaderSpan{Span: span}
+	}
+
+	testCases := []struct {
+		desc     string
+		spec     execinfrapb.IndexSkipTableReaderSpec
+		post     execinfrapb.PostProcessSpec
+		expected string
+	}{
+		{
+			desc: "primary index",
+			spec: execinfrapb.IndexSkipTableReaderSpec{
+				Spans:        []execinfrapb.TableReaderSpan{{Span: td.PrimaryIndexSpan(keys.SystemSQLCodec)}},
+				KeyPrefixLen: 1,
+			},
+			post: execinfrapb.PostProcessSpec{
+				Projection:    true,
+				OutputColumns: []uint32{0, 1},
+			},
+			expected: "[[0 1] [1 0] [2 0]]",
+		},
+		{
+			desc: "primary index reverse",
+			spec: execinfrapb.IndexSkipTableReaderSpec{
+				Spans:        []execinfrapb.TableReaderSpan{{Span: td.PrimaryIndexSpan(keys.SystemSQLCodec)}},
+				Reverse:      true,
+				KeyPrefixLen: 1,
+			},
+			post: execinfrapb.PostProcessSpec{
+				Projection:    true,
+				OutputColumns: []uint32{0, 1},
+			},
+			expected: "[[2 9] [1 9] [0 9]]",
+		},
+		{
+			desc: "multiple spans",
+			spec: execinfrapb.IndexSkipTableReaderSpec{
+				Spans:        []execinfrapb.TableReaderSpan{makePrimaryIndexSpan(0, 1), makePrimaryIndexSpan(2, 3)},
+				KeyPrefixLen: 1,
+			},
+			post: execinfrapb.PostProcessSpec{
+				Projection:    true,
+				OutputColumns: []uint32{0, 1},
+			},
+			expected: "[[0 1] [2 0]]",
+		},
+		{
+			desc: "multiple spans reverse",
+			spec: execinfrapb.IndexSkipTableReaderSpec{
+				Spans:        []execinfrapb.TableReaderSpan{makePrimaryIndexSpan(0, 1), makePrimaryIndexSpan(2, 3)},
+				Reverse:      true,
+				KeyPrefixLen: 1,
+			},
+			post: execinfrapb.PostProcessSpec{
+				Projection:    true,
+				OutputColumns: []uint32{0, 1},
+			},
+			expected: "[[2 9] [0 9]]",
+		},
+		{
+			desc: "secondary index",
+			spec: execinfrapb.IndexSkipTableReaderSpec{
+				IndexIdx:     1,
+				Spans:        []execinfrapb.TableReaderSpan{{Span: td.IndexSpan(keys.SystemSQLCodec, td.Indexes[0].ID)}},
+				KeyPrefixLen: 1,
+			},
+			post: execinfrapb.PostProcessSpec{
+				Projection:    true,
+				OutputColumns: []uint32{2, 0, 1},
+			},
+			expected: "[[0 0 3] [1 0 1] [2 0 2]]",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.desc, func(t *testing.T) {
+			testutils.RunTrueAndFalse(t, "row-source", func(t *testing.T, rowSource bool) {
+				ts := c.spec
+				ts.Table = *td.TableDesc()
+				ts.Visibility = execinfra.ScanVisibilityPublic
+				ts.LockingStrength = descpb.ScanLockingStrength_FOR_NONE
+				ts.LockingWaitPolicy = descpb.ScanLockingWaitPolicy_BLOCK
+
+				evalCtx := tree.MakeTestingEvalContext(s.ClusterSettings())
+				defer evalCtx.Stop(ctx)
+				flowCtx := execinfra.FlowCtx{
+					EvalCtx: &evalCtx,
+					Cfg: &execinfra.ServerConfig{
+						Settings:   s.ClusterSettings(),
+						RangeCache: kvcoord.NewRangeDescriptorCache(s.ClusterSettings(), nil, func() int64 { return 2 << 10 }, s.Stopper()),
+					},
+					Txn:    kv.NewTxn(ctx, s.DB(), s.NodeID()),
+					NodeID: evalCtx.NodeID,
+				}
+
+				var out execinfra.RowReceiver
+				var buf *distsqlutils.RowBuffer
+				if !rowSource {
+					buf = &distsqlutils.RowBuffer{}
+					out = buf
+				}
+				istr, err := newIndexSkipTableReader(&flowCtx, 0 /* processorID */, &ts, &c.post, out)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var results execinfra.RowSource
+				if rowSource {
+					istr.Start(ctx)
+					results = istr
+				} else {
+					istr.Run(ctx)
+					if !buf.ProducerClosed() {
+						t.Fatalf("output RowReceiver not closed")
+					}
+					buf.Start(ctx)
+					results = buf
+				}
+
+				var res rowenc.EncDatumRows
+				for {
+					row, meta := results.Next()
+					if meta != nil && meta.LeafTxnFinalState == nil && meta.Metrics == nil {
+						t.Fatalf("unexpected metadata: %+v", meta)
+					}
+					if row == nil {
+						break
+					}
+					res = append(res, row.Copy())
+				}
+				if result := res.String(istr.OutputTypes()); result != c.expected {
+					t.Errorf("invalid results: %s, expected %s'", result, c.expected)
+				}
+			})
+		})
+	}
+}
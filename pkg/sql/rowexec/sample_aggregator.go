@@ -451,8 +451,13 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 				columnIDs[i] = s.sampledCols[c]
 			}
 
-			// Delete old stats that have been superseded.
-			if err := stats.DeleteOldStatsForColumns(
+			// Delete old stats that have been superseded. A partial stat only
+			// supersedes the previous partial stats on the same columns.
+			deleteOldStats := stats.DeleteOldStatsForColumns
+			if si.spec.PartialPredicate != "" {
+				deleteOldStats = stats.DeletePartialStatsForColumns
+			}
+			if err := deleteOldStats(
 				ctx,
 				s.FlowCtx.Cfg.Executor,
 				txn,
@@ -474,6 +479,7 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 				distinctCount,
 				si.numNulls,
 				histogram,
+				si.spec.PartialPredicate,
 			); err != nil {
 				return err
 			}
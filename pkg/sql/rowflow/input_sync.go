@@ -389,3 +389,107 @@ func makeOrderedSync(
 	}
 	return s, nil
 }
+
+// serialUnorderedSynchronizer receives rows from multiple streams and produces
+// a single stream of rows by returning all the rows of a source before moving
+// on to the next one, in index order. Unlike the orderedSynchronizer with no
+// ordering, sources are started lazily: a source is not started (and thus not
+// read from at all) until all the sources before it are exhausted. This allows
+// the consumer to avoid reading the later sources altogether if it stops early,
+// which is what locality optimized search relies on.
+type serialUnorderedSynchronizer struct {
+	// ctx is the context passed to Start; it is used to start the sources
+	// lazily.
+	ctx context.Context
+
+	sources []srcInfo
+
+	types []*types.T
+
+	// curIdx is the index of the source currently being read. All sources
+	// before it have been exhausted, and all sources after it haven't been
+	// started yet.
+	curIdx int
+	// curStarted is set once the source at curIdx has been started.
+	curStarted bool
+	// draining is set once ConsumerDone has been called. Only metadata is
+	// forwarded from then on.
+	draining bool
+}
+
+var _ execinfra.RowSource = &serialUnorderedSynchronizer{}
+
+// OutputTypes is part of the RowSource interface.
+func (s *serialUnorderedSynchronizer) OutputTypes() []*types.T {
+	return s.types
+}
+
+// Start is part of the RowSource interface.
+func (s *serialUnorderedSynchronizer) Start(ctx context.Context) context.Context {
+	s.ctx = ctx
+	return ctx
+}
+
+// Next is part of the RowSource interface.
+func (s *serialUnorderedSynchronizer) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	for s.curIdx < len(s.sources) {
+		src := s.sources[s.curIdx].src
+		if !s.curStarted {
+			src.Start(s.ctx)
+			s.curStarted = true
+		}
+		row, meta := src.Next()
+		if row == nil && meta == nil {
+			s.curIdx++
+			s.curStarted = false
+			continue
+		}
+		if row != nil && s.draining {
+			continue
+		}
+		return row, meta
+	}
+	return nil, nil
+}
+
+// ConsumerDone is part of the RowSource interface.
+func (s *serialUnorderedSynchronizer) ConsumerDone() {
+	if s.draining {
+		return
+	}
+	s.draining = true
+	firstUnstarted := s.curIdx
+	if s.curIdx < len(s.sources) && s.curStarted {
+		// The current source is drained through Next.
+		s.sources[s.curIdx].src.ConsumerDone()
+		firstUnstarted++
+	}
+	// The sources that haven't been started yet don't have anything to drain,
+	// so we close them right away instead of starting them.
+	for i := firstUnstarted; i < len(s.sources); i++ {
+		s.sources[i].src.ConsumerClosed()
+	}
+	s.sources = s.sources[:firstUnstarted]
+}
+
+// ConsumerClosed is part of the RowSource interface.
+func (s *serialUnorderedSynchronizer) ConsumerClosed() {
+	for ; s.curIdx < len(s.sources); s.curIdx++ {
+		s.sources[s.curIdx].src.ConsumerClosed()
+	}
+}
+
+// makeSerialUnorderedSync creates a serialUnorderedSynchronizer.
+func makeSerialUnorderedSync(sources []execinfra.RowSource) (execinfra.RowSource, error) {
+	if len(sources) < 2 {
+		return nil, errors.Errorf("only %d sources for serial unordered synchronizer", len(sources))
+	}
+	s := &serialUnorderedSynchronizer{
+		sources: make([]srcInfo, len(sources)),
+		types:   sources[0].OutputTypes(),
+	}
+	for i := range s.sources {
+		s.sources[i].src = sources[i]
+	}
+	return s, nil
+}
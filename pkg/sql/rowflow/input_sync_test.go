@@ -182,6 +182,117 @@ func TestOrderedSyncDrainBeforeNext(t *testing.T) {
 	}
 }
 
+func TestSerialUnorderedSync(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	v := [6]rowenc.EncDatum{}
+	for i := range v {
+		v[i] = rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(i)))
+	}
+	expectedMeta := &execinfrapb.ProducerMetadata{Err: errors.New("expected metadata")}
+
+	makeSources := func(nextCalls []int) []*distsqlutils.RowBuffer {
+		var sources []*distsqlutils.RowBuffer
+		for i := 0; i < 3; i++ {
+			i := i
+			rowBuf := distsqlutils.NewRowBuffer(
+				rowenc.OneIntCol,
+				rowenc.EncDatumRows{{v[2*i]}, {v[2*i+1]}},
+				distsqlutils.RowBufferArgs{
+					OnNext: func(*distsqlutils.RowBuffer) (rowenc.EncDatumRow, *execinfrapb.ProducerMetadata) {
+						nextCalls[i]++
+						return nil, nil
+					},
+				},
+			)
+			rowBuf.Push(nil, expectedMeta)
+			sources = append(sources, rowBuf)
+		}
+		return sources
+	}
+	makeSync := func(sources []*distsqlutils.RowBuffer) execinfra.RowSource {
+		rowSources := make([]execinfra.RowSource, len(sources))
+		for i := range sources {
+			rowSources[i] = sources[i]
+		}
+		s, err := makeSerialUnorderedSync(rowSources)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Start(context.Background())
+		return s
+	}
+
+	t.Run("all", func(t *testing.T) {
+		nextCalls := make([]int, 3)
+		s := makeSync(makeSources(nextCalls))
+		var rows rowenc.EncDatumRows
+		metasFound := 0
+		for {
+			row, meta := s.Next()
+			if meta != nil {
+				if meta != expectedMeta {
+					t.Fatalf("unexpected meta %v, expected %v", meta, expectedMeta)
+				}
+				metasFound++
+				continue
+			}
+			if row == nil {
+				break
+			}
+			rows = append(rows, row)
+		}
+		if metasFound != 3 {
+			t.Fatalf("unexpected number of metadata items %d, expected 3", metasFound)
+		}
+		// The rows are returned one source at a time, in the order of the
+		// sources.
+		expected := rowenc.EncDatumRows{{v[0]}, {v[1]}, {v[2]}, {v[3]}, {v[4]}, {v[5]}}
+		if rows.String(rowenc.OneIntCol) != expected.String(rowenc.OneIntCol) {
+			t.Fatalf("expected %s, got %s", expected.String(rowenc.OneIntCol), rows.String(rowenc.OneIntCol))
+		}
+	})
+
+	t.Run("consumer done", func(t *testing.T) {
+		nextCalls := make([]int, 3)
+		sources := makeSources(nextCalls)
+		s := makeSync(sources)
+		row, meta := s.Next()
+		if meta != nil || row == nil || row.String(rowenc.OneIntCol) != "[0]" {
+			t.Fatalf("unexpected row %v and meta %v", row, meta)
+		}
+
+		// Once the consumer is done, the first source is drained and the
+		// remaining sources are closed without ever being read.
+		s.ConsumerDone()
+		metasFound := 0
+		for {
+			row, meta := s.Next()
+			if row != nil {
+				t.Fatalf("unexpected row %s while draining", row.String(rowenc.OneIntCol))
+			}
+			if meta == nil {
+				break
+			}
+			metasFound++
+		}
+		if metasFound != 1 {
+			t.Fatalf("unexpected number of metadata items %d, expected 1", metasFound)
+		}
+		if sources[0].ConsumerStatus != execinfra.DrainRequested {
+			t.Fatalf("expected the first source to be draining, found %d", sources[0].ConsumerStatus)
+		}
+		for i := 1; i < len(sources); i++ {
+			if nextCalls[i] != 0 {
+				t.Fatalf("source %d was read %d times, expected it not to be read", i, nextCalls[i])
+			}
+			if sources[i].ConsumerStatus != execinfra.ConsumerClosed {
+				t.Fatalf("expected source %d to be closed, found %d", i, sources[i].ConsumerStatus)
+			}
+		}
+	})
+}
+
 func TestUnorderedSync(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
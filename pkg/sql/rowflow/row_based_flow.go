@@ -122,14 +122,20 @@ func (f *rowBasedFlow) setupProcessors(
 						return true
 					}
 					// ps has an input with multiple streams. This can be either a
-					// multiplexed RowChannel (in case of some unordered synchronizers)
-					// or an orderedSynchronizer (for other unordered synchronizers or
-					// ordered synchronizers). If it's a multiplexed RowChannel,
-					// then its inputs run in parallel, so there's no fusing with them.
-					// If it's an orderedSynchronizer, then we look inside it to see if
-					// the processor we're trying to fuse feeds into it.
-					orderedSync, ok := inputSyncs[pIdx][inIdx].(*orderedSynchronizer)
-					if !ok {
+					// multiplexed RowChannel (in case of some unordered synchronizers),
+					// a serialUnorderedSynchronizer, or an orderedSynchronizer (for
+					// other unordered synchronizers or ordered synchronizers). If it's
+					// a multiplexed RowChannel, then its inputs run in parallel, so
+					// there's no fusing with them. Otherwise, we look inside the
+					// synchronizer to see if the processor we're trying to fuse feeds
+					// into it.
+					var sources []srcInfo
+					switch sync := inputSyncs[pIdx][inIdx].(type) {
+					case *orderedSynchronizer:
+						sources = sync.sources
+					case *serialUnorderedSynchronizer:
+						sources = sync.sources
+					default:
 						continue
 					}
 					// See if we can find a stream attached to the processor we're
@@ -142,8 +148,8 @@ func (f *rowBasedFlow) setupProcessors(
 						if input.ProcessorID != pspec.ProcessorID {
 							continue
 						}
-						// Fuse the processor with this orderedSynchronizer.
-						orderedSync.sources[sIdx].src = source
+						// Fuse the processor with this synchronizer.
+						sources[sIdx].src = source
 						return true
 					}
 				}
@@ -264,7 +270,8 @@ func (f *rowBasedFlow) setupInputSyncs(
 			}
 			var sync execinfra.RowSource
 			if is.Type != execinfrapb.InputSyncSpec_UNORDERED &&
-				is.Type != execinfrapb.InputSyncSpec_ORDERED {
+				is.Type != execinfrapb.InputSyncSpec_ORDERED &&
+				is.Type != execinfrapb.InputSyncSpec_SERIAL_UNORDERED {
 				return nil, errors.Errorf("unsupported input sync type %s", is.Type)
 			}
 
@@ -293,11 +300,11 @@ func (f *rowBasedFlow) setupInputSyncs(
 				}
 			}
 			if sync == nil {
-				// We have an ordered synchronizer or an unordered one that we really
-				// want to fuse because of the FuseAggressively option. We'll create a
-				// RowChannel for each input for now, but the inputs might be fused with
-				// the orderedSynchronizer later (in which case the RowChannels will be
-				// dropped).
+				// We have an ordered or a serial unordered synchronizer, or an
+				// unordered one that we really want to fuse because of the
+				// FuseAggressively option. We'll create a RowChannel for each input for
+				// now, but the inputs might be fused with the synchronizer later (in
+				// which case the RowChannels will be dropped).
 				streams := make([]execinfra.RowSource, len(is.Streams))
 				for i, s := range is.Streams {
 					rowChan := &execinfra.RowChannel{}
@@ -308,11 +315,15 @@ func (f *rowBasedFlow) setupInputSyncs(
 					streams[i] = rowChan
 				}
 				var err error
-				ordering := colinfo.NoOrdering
-				if is.Type == execinfrapb.InputSyncSpec_ORDERED {
-					ordering = execinfrapb.ConvertToColumnOrdering(is.Ordering)
+				if is.Type == execinfrapb.InputSyncSpec_SERIAL_UNORDERED {
+					sync, err = makeSerialUnorderedSync(streams)
+				} else {
+					ordering := colinfo.NoOrdering
+					if is.Type == execinfrapb.InputSyncSpec_ORDERED {
+						ordering = execinfrapb.ConvertToColumnOrdering(is.Ordering)
+					}
+					sync, err = makeOrderedSync(ordering, f.EvalCtx, streams)
 				}
-				sync, err = makeOrderedSync(ordering, f.EvalCtx, streams)
 				if err != nil {
 					return nil, err
 				}
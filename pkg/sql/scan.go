@@ -76,6 +76,11 @@ type scanNode struct {
 	// (non-zero), softLimit must be unset (zero).
 	softLimit int64
 
+	// if non-zero, skipScanPrefixLen indicates that the scanNode is a skip scan
+	// which only needs to provide the first row for each distinct value of the
+	// first skipScanPrefixLen index key columns.
+	skipScanPrefixLen int
+
 	disableBatchLimits bool
 
 	// See exec.Factory.ConstructScan.
@@ -1245,6 +1245,30 @@ func (sc *SchemaChanger) done(ctx context.Context) error {
 						}
 					}
 				}
+				// If the primary key change was performed to alter the locality of
+				// the table, the new locality has just taken effect, so the zone
+				// configuration of the table can be updated to match it.
+				if pkSwap.LocalityConfigSwap != nil &&
+					mutation.Direction == descpb.DescriptorMutation_ADD {
+					dbDesc, err := descsCol.GetDatabaseVersionByID(
+						ctx, txn, scTable.ParentID, tree.DatabaseLookupFlags{Required: true},
+					)
+					if err != nil {
+						return err
+					}
+					if dbDesc.IsMultiRegion() {
+						if err := applyZoneConfigForMultiRegionTable(
+							ctx,
+							txn,
+							sc.execCfg,
+							*dbDesc.RegionConfig,
+							scTable,
+							pkSwap.LocalityConfigSwap.NewLocalityConfig,
+						); err != nil {
+							return err
+						}
+					}
+				}
 				// If we performed MakeMutationComplete on a PrimaryKeySwap mutation, then we need to start
 				// a job for the index deletion mutations that the primary key swap mutation added, if any.
 				if childJobs, err = sc.queueCleanupJobs(ctx, scTable, txn, childJobs); err != nil {
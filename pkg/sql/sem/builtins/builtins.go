@@ -58,7 +58,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/streaming"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
@@ -66,6 +68,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/unaccent"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -2200,6 +2204,60 @@ nearest replica.`, defaultFollowerReadDuration),
 		},
 	),
 
+	tree.WithMinTimestampFunctionName: makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"min_timestamp", types.TimestampTZ},
+			},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return withMinTimestamp(ctx, tree.MustBeDTimestampTZ(args[0]).Time)
+			},
+			Info:       withMinTimestampInfo(false /* nearestOnly */),
+			Volatility: tree.VolatilityVolatile,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"min_timestamp", types.TimestampTZ},
+				{"nearest_only", types.Bool},
+			},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return withMinTimestamp(ctx, tree.MustBeDTimestampTZ(args[0]).Time)
+			},
+			Info:       withMinTimestampInfo(true /* nearestOnly */),
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
+	tree.WithMaxStalenessFunctionName: makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"max_staleness", types.Interval},
+			},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return withMaxStaleness(ctx, tree.MustBeDInterval(args[0]).Duration)
+			},
+			Info:       withMaxStalenessInfo(false /* nearestOnly */),
+			Volatility: tree.VolatilityVolatile,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"max_staleness", types.Interval},
+				{"nearest_only", types.Bool},
+			},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return withMaxStaleness(ctx, tree.MustBeDInterval(args[0]).Duration)
+			},
+			Info:       withMaxStalenessInfo(true /* nearestOnly */),
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
 	"cluster_logical_timestamp": makeBuiltin(
 		tree.FunctionProperties{
 			Category: categorySystemInfo,
@@ -2896,6 +2954,119 @@ may increase either contention or retry errors, or both.`,
 	})),
 
 	// Full text search functions.
+	"to_tsvector": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tsConfigOverloads(types.TSVector, "Converts `text` to a tsvector, which holds the normalized lexemes of "+
+			"the words of `text` and their positions.",
+			func(config *tsearch.Config, text string) (tree.Datum, error) {
+				return tree.NewDTSVector(config.ToTSVector(text)), nil
+			},
+		)...,
+	),
+	"to_tsquery": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tsConfigOverloads(types.TSQuery, "Converts `text`, which must be in tsquery syntax, to a tsquery, "+
+			"normalizing its lexemes.",
+			func(config *tsearch.Config, text string) (tree.Datum, error) {
+				q, err := config.ToTSQuery(text)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSQuery(q), nil
+			},
+		)...,
+	),
+	"plainto_tsquery": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tsConfigOverloads(types.TSQuery, "Converts `text` to a tsquery that matches all of its normalized "+
+			"words, ignoring punctuation.",
+			func(config *tsearch.Config, text string) (tree.Datum, error) {
+				return tree.NewDTSQuery(config.PlainToTSQuery(text)), nil
+			},
+		)...,
+	),
+	"phraseto_tsquery": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tsConfigOverloads(types.TSQuery, "Converts `text` to a tsquery that matches the phrase formed by its "+
+			"normalized words, ignoring punctuation.",
+			func(config *tsearch.Config, text string) (tree.Datum, error) {
+				return tree.NewDTSQuery(config.PhraseToTSQuery(text)), nil
+			},
+		)...,
+	),
+	"ts_rank": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(tsearch.DefaultRankWeights, args[0], args[1], 0 /* method */), nil
+			},
+			Info:       "Ranks `vector` for `query`, based on the frequency of the matching lexemes.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"vector", types.TSVector}, {"query", types.TSQuery}, {"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(
+					tsearch.DefaultRankWeights, args[0], args[1], int(tree.MustBeDInt(args[2])),
+				), nil
+			},
+			Info: "Ranks `vector` for `query`, based on the frequency of the matching lexemes. " +
+				"`normalization` is a bit mask that determines how the rank is scaled by the " +
+				"document length.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"weights", types.MakeArray(types.Float4)}, {"vector", types.TSVector}, {"query", types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				weights, err := tsRankWeights(args[0])
+				if err != nil {
+					return nil, err
+				}
+				return tsRank(weights, args[1], args[2], 0 /* method */), nil
+			},
+			Info: "Ranks `vector` for `query`, based on the frequency of the matching lexemes. " +
+				"`weights` are the weights of the D, C, B and A positions.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"weights", types.MakeArray(types.Float4)},
+				{"vector", types.TSVector},
+				{"query", types.TSQuery},
+				{"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				weights, err := tsRankWeights(args[0])
+				if err != nil {
+					return nil, err
+				}
+				return tsRank(weights, args[1], args[2], int(tree.MustBeDInt(args[3]))), nil
+			},
+			Info: "Ranks `vector` for `query`, based on the frequency of the matching lexemes. " +
+				"`weights` are the weights of the D, C, B and A positions, and `normalization` is " +
+				"a bit mask that determines how the rank is scaled by the document length.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"get_current_ts_config": makeBuiltin(tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(ctx *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				config, err := currentTSConfig(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDString(config.Name()), nil
+			},
+			Info:       "Returns the name of the default text search configuration of the session.",
+			Volatility: tree.VolatilityStable,
+		},
+	),
 	"ts_match_qv":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_match_vq":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"tsvector_cmp":                   makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
@@ -2905,20 +3076,14 @@ may increase either contention or retry errors, or both.`,
 	"ts_lexize":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"websearch_to_tsquery":           makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"array_to_tsvector":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"get_current_ts_config":          makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"numnode":                        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"plainto_tsquery":                makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"phraseto_tsquery":               makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"querytree":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"setweight":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"strip":                          makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"to_tsquery":                     makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"to_tsvector":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"json_to_tsvector":               makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"jsonb_to_tsvector":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_delete":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_filter":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"ts_rank":                        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_rank_cd":                     makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_rewrite":                     makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"tsquery_phrase":                 makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
@@ -2927,12 +3092,68 @@ may increase either contention or retry errors, or both.`,
 	"tsvector_update_trigger_column": makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 
 	// Trigram functions.
-	"similarity":             makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
-	"show_trgm":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
-	"word_similarity":        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
+	"similarity": makeBuiltin(tree.FunctionProperties{Category: categoryTrigram},
+		stringOverload2(
+			"left",
+			"right",
+			func(_ *tree.EvalContext, l, r string) (tree.Datum, error) {
+				return tree.NewDFloat(tree.DFloat(trigram.Similarity(l, r))), nil
+			},
+			types.Float,
+			"Returns a number that indicates how similar the two arguments are, "+
+				"based on the number of trigrams they share. The result ranges from 0 "+
+				"(completely dissimilar) to 1 (identical).",
+			tree.VolatilityImmutable,
+		),
+	),
+
+	"show_trgm": makeBuiltin(tree.FunctionProperties{Category: categoryTrigram},
+		stringOverload1(
+			func(_ *tree.EvalContext, s string) (tree.Datum, error) {
+				arr := tree.NewDArray(types.String)
+				for _, t := range trigram.MakeTrigrams(s, true /* pad */) {
+					if err := arr.Append(tree.NewDString(t)); err != nil {
+						return nil, err
+					}
+				}
+				return arr, nil
+			},
+			types.StringArray,
+			"Returns an array of all the trigrams in the given string.",
+			tree.VolatilityImmutable,
+		),
+	),
+
+	"word_similarity": makeBuiltin(tree.FunctionProperties{Category: categoryTrigram},
+		stringOverload2(
+			"left",
+			"right",
+			func(_ *tree.EvalContext, l, r string) (tree.Datum, error) {
+				return tree.NewDFloat(tree.DFloat(trigram.WordSimilarity(l, r))), nil
+			},
+			types.Float,
+			"Returns the greatest similarity between the trigrams of `left` and "+
+				"any contiguous extent of the ordered trigrams of `right`.",
+			tree.VolatilityImmutable,
+		),
+	),
+
 	"strict_word_similarity": makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
-	"show_limit":             makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
-	"set_limit":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
+
+	"show_limit": makeBuiltin(tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(ctx *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				return tree.NewDFloat(tree.DFloat(ctx.SessionData.TrigramSimilarityThreshold)), nil
+			},
+			Info: "Returns the current similarity threshold used by the % operator. " +
+				"It is controlled by the pg_trgm.similarity_threshold session variable.",
+			Volatility: tree.VolatilityStable,
+		},
+	),
+
+	"set_limit": makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
 
 	// JSON functions.
 	// The behavior of both the JSON and JSONB data types in CockroachDB is
@@ -3535,6 +3756,41 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
+	"crdb_internal.complete_stream_ingestion_job": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         categoryMultiTenancy,
+			DistsqlBlocklist: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"job_id", types.Int},
+				{"cutover_ts", types.TimestampTZ},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if streaming.CompleteIngestionHook == nil {
+					return nil, pgerror.New(pgcode.CCLRequired,
+						"completing a stream ingestion job requires a CCL binary")
+				}
+				jobID := int64(tree.MustBeDInt(args[0]))
+				cutoverTime := tree.MustBeDTimestampTZ(args[1]).Time
+				cutoverTimestamp := hlc.Timestamp{WallTime: cutoverTime.UnixNano()}
+				if err := streaming.CompleteIngestionHook(evalCtx, evalCtx.Txn, jobID, cutoverTimestamp); err != nil {
+					return nil, err
+				}
+				return tree.NewDInt(tree.DInt(jobID)), nil
+			},
+			Info: "This function can be used to signal a running stream ingestion job to complete. " +
+				"The job will eventually stop ingesting, revert to the specified timestamp and leave the " +
+				"cluster in a consistent state. The specified timestamp can only be specified up to the" +
+				" microsecond. This function does not wait for the job to reach a terminal state, " +
+				"but instead returns the job id as soon as it has signaled the job to complete. " +
+				"This builtin can be used in conjunction with SHOW JOBS WHEN COMPLETE to ensure that the" +
+				" job has left the cluster in a consistent state.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
 	"crdb_internal.encode_key": makeBuiltin(
 		tree.FunctionProperties{Category: categorySystemInfo},
 		tree.Overload{
@@ -3657,6 +3913,62 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
+	"crdb_internal.add_statement_hint": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         categorySystemInfo,
+			DistsqlBlocklist: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"fingerprint", types.String},
+				{"hint_type", types.String},
+				{"hint_value", types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if err := evalCtx.Planner.AddStatementHint(
+					evalCtx.Ctx(),
+					string(tree.MustBeDString(args[0])),
+					string(tree.MustBeDString(args[1])),
+					string(tree.MustBeDString(args[2])),
+				); err != nil {
+					return nil, err
+				}
+				return tree.DBoolTrue, nil
+			},
+			Info: "Adds an optimizer hint for the statements with the given fingerprint. " +
+				"The hint type is one of index (with a value of the form table@index), " +
+				"join_algorithm (hash, merge, lookup or inverted), join_order (syntactic) " +
+				"or disable_rule (the name of an optimizer rule). The fingerprint can also be " +
+				"given as a statement with constants, which are ignored.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
+	"crdb_internal.remove_statement_hints": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         categorySystemInfo,
+			DistsqlBlocklist: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"fingerprint", types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				n, err := evalCtx.Planner.RemoveStatementHints(
+					evalCtx.Ctx(), string(tree.MustBeDString(args[0])),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDInt(tree.DInt(n)), nil
+			},
+			Info:       "Removes all optimizer hints for the statements with the given fingerprint.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
 	"crdb_internal.force_error": makeBuiltin(
 		tree.FunctionProperties{
 			Category: categorySystemInfo,
@@ -4101,6 +4413,24 @@ may increase either contention or retry errors, or both.`,
 			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
 			Volatility: tree.VolatilityStable,
 		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.String}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return trigramNumInvertedIndexEntries(ctx, args[0])
+			},
+			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
+			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsvectorNumInvertedIndexEntries(ctx, args[0])
+			},
+			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
+			Volatility: tree.VolatilityStable,
+		},
 		tree.Overload{
 			Types: tree.ArgTypes{
 				{"val", types.Jsonb},
@@ -4129,6 +4459,20 @@ may increase either contention or retry errors, or both.`,
 			},
 			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
 			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"val", types.String},
+				{"version", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				// The version argument is ignored for trigram inverted indexes, since
+				// all of their versions include the same entries.
+				return trigramNumInvertedIndexEntries(ctx, args[0])
+			},
+			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
+			Volatility: tree.VolatilityStable,
 		}),
 
 	// Returns true iff the current user has admin role.
@@ -4521,6 +4865,42 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
+	// Used to configure the tenant token bucket. See UpdateTenantResourceLimits.
+	"crdb_internal.update_tenant_resource_limits": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categoryMultiTenancy,
+			Undocumented: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"tenant_id", types.Int},
+				{"available_request_units", types.Float},
+				{"refill_rate", types.Float},
+				{"max_burst_request_units", types.Float},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				sTenID := int64(tree.MustBeDInt(args[0]))
+				if sTenID <= 0 {
+					return nil, pgerror.New(pgcode.InvalidParameterValue, "tenant ID must be positive")
+				}
+				availableRU := float64(tree.MustBeDFloat(args[1]))
+				refillRate := float64(tree.MustBeDFloat(args[2]))
+				maxBurstRU := float64(tree.MustBeDFloat(args[3]))
+				if err := ctx.Tenant.UpdateTenantResourceLimits(
+					ctx.Context, uint64(sTenID), availableRU, refillRate, maxBurstRU,
+				); err != nil {
+					return nil, err
+				}
+				return args[0], nil
+			},
+			Info: "Updates the Request Unit token bucket of a tenant: the number of available " +
+				"Request Units, the refill rate (in Request Units per second) and the maximum " +
+				"burst (zero means no limit). Must be run by the System tenant.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
 	"num_nulls": makeBuiltin(
 		tree.FunctionProperties{
 			Category:     categoryComparison,
@@ -6657,6 +7037,59 @@ func followerReadTimestamp(ctx *tree.EvalContext, _ tree.Datums) (tree.Datum, er
 	return tree.MakeDTimestampTZ(ts, time.Microsecond)
 }
 
+func withMinTimestamp(ctx *tree.EvalContext, t time.Time) (tree.Datum, error) {
+	// The statement timestamp is rounded in the same way as the datums
+	// derived from it, e.g. statement_timestamp().
+	if t.After(ctx.GetStmtTimestamp().Round(time.Microsecond)) {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "timestamp cannot be in the future")
+	}
+	return tree.MakeDTimestampTZ(t, time.Microsecond)
+}
+
+func withMaxStaleness(ctx *tree.EvalContext, d duration.Duration) (tree.Datum, error) {
+	if d.Compare(duration.Duration{}) < 0 {
+		return nil, pgerror.New(pgcode.InvalidParameterValue,
+			"interval duration must be greater than or equal to 0")
+	}
+	return tree.MakeDTimestampTZ(duration.Add(ctx.GetStmtTimestamp(), d.Mul(-1)), time.Microsecond)
+}
+
+const nearestOnlyInfo = `
+
+If nearest_only is set to true, reads that cannot be served using the nearest
+available replica will error.
+`
+
+func withMinTimestampInfo(nearestOnly bool) string {
+	var nearestOnlyText string
+	if nearestOnly {
+		nearestOnlyText = nearestOnlyInfo
+	}
+	return fmt.Sprintf(
+		`When used in the AS OF SYSTEM TIME clause of a single-statement,
+read-only transaction, CockroachDB chooses the newest timestamp no older than min_timestamp
+that allows execution of the reads at the closest available replica without blocking.%s
+
+Note this function requires an enterprise license on a CCL distribution.`,
+		nearestOnlyText,
+	)
+}
+
+func withMaxStalenessInfo(nearestOnly bool) string {
+	var nearestOnlyText string
+	if nearestOnly {
+		nearestOnlyText = nearestOnlyInfo
+	}
+	return fmt.Sprintf(
+		`When used in the AS OF SYSTEM TIME clause of a single-statement,
+read-only transaction, CockroachDB chooses the newest timestamp within the staleness
+bound that allows execution of the reads at the closest available replica without blocking.%s
+
+Note this function requires an enterprise license on a CCL distribution.`,
+		nearestOnlyText,
+	)
+}
+
 func jsonNumInvertedIndexEntries(_ *tree.EvalContext, val tree.Datum) (tree.Datum, error) {
 	if val == tree.DNull {
 		return tree.DZero, nil
@@ -6668,6 +7101,90 @@ func jsonNumInvertedIndexEntries(_ *tree.EvalContext, val tree.Datum) (tree.Datu
 	return tree.NewDInt(tree.DInt(n)), nil
 }
 
+func trigramNumInvertedIndexEntries(_ *tree.EvalContext, val tree.Datum) (tree.Datum, error) {
+	if val == tree.DNull {
+		return tree.DZero, nil
+	}
+	trigrams := trigram.MakeTrigrams(string(tree.MustBeDString(val)), true /* pad */)
+	return tree.NewDInt(tree.DInt(len(trigrams))), nil
+}
+
+func tsvectorNumInvertedIndexEntries(_ *tree.EvalContext, val tree.Datum) (tree.Datum, error) {
+	if val == tree.DNull {
+		return tree.DZero, nil
+	}
+	return tree.NewDInt(tree.DInt(len(tree.MustBeDTSVector(val).Lexemes()))), nil
+}
+
+// tsConfigOverloads returns the overloads of a full-text search function that
+// converts text using a text search configuration: one that uses the default
+// configuration of the session, and one that takes the configuration as its
+// first argument.
+func tsConfigOverloads(
+	returnType *types.T, info string, fn func(config *tsearch.Config, text string) (tree.Datum, error),
+) []tree.Overload {
+	return []tree.Overload{
+		{
+			Types:      tree.ArgTypes{{"text", types.String}},
+			ReturnType: tree.FixedReturnType(returnType),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				config, err := currentTSConfig(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return fn(config, string(tree.MustBeDString(args[0])))
+			},
+			Info:       info + " Uses the default_text_search_config session variable.",
+			Volatility: tree.VolatilityStable,
+		},
+		{
+			Types:      tree.ArgTypes{{"config", types.String}, {"text", types.String}},
+			ReturnType: tree.FixedReturnType(returnType),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				config, err := tsearch.GetConfig(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return fn(config, string(tree.MustBeDString(args[1])))
+			},
+			Info:       info + " Uses the text search configuration named `config`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	}
+}
+
+// currentTSConfig returns the default text search configuration of the
+// session.
+func currentTSConfig(ctx *tree.EvalContext) (*tsearch.Config, error) {
+	name := tsearch.DefaultConfigName
+	if ctx.SessionData != nil && ctx.SessionData.DefaultTextSearchConfig != "" {
+		name = ctx.SessionData.DefaultTextSearchConfig
+	}
+	return tsearch.GetConfig(name)
+}
+
+// tsRankWeights returns the position weights of ts_rank given as a float4
+// array.
+func tsRankWeights(arg tree.Datum) ([4]float32, error) {
+	arr := tree.MustBeDArray(arg)
+	weights := make([]float64, len(arr.Array))
+	for i, d := range arr.Array {
+		if d == tree.DNull {
+			return [4]float32{}, pgerror.New(
+				pgcode.NullValueNotAllowed, "array of weight must not contain nulls",
+			)
+		}
+		weights[i] = float64(tree.MustBeDFloat(d))
+	}
+	return tsearch.MakeRankWeights(weights)
+}
+
+func tsRank(weights [4]float32, vector, query tree.Datum, method int) tree.Datum {
+	return tree.NewDFloat(tree.DFloat(tsearch.Rank(
+		weights, tree.MustBeDTSVector(vector).TSVector, tree.MustBeDTSQuery(query).TSQuery, method,
+	)))
+}
+
 func arrayNumInvertedIndexEntries(
 	ctx *tree.EvalContext, val, version tree.Datum,
 ) (tree.Datum, error) {
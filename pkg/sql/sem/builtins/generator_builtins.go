@@ -285,6 +285,25 @@ var generators = map[string]builtinDefinition{
 	"json_each_text":            makeBuiltin(genPropsWithLabels(jsonEachGeneratorLabels), jsonEachTextImpl),
 	"jsonb_each_text":           makeBuiltin(genPropsWithLabels(jsonEachGeneratorLabels), jsonEachTextImpl),
 
+	"crdb_internal.decode_plan_gist": makeBuiltin(
+		tree.FunctionProperties{
+			Class:            tree.GeneratorClass,
+			Category:         categorySystemInfo,
+			DistsqlBlocklist: true,
+		},
+		makeGeneratorOverload(
+			tree.ArgTypes{
+				{Name: "gist", Typ: types.String},
+			},
+			types.String,
+			makeDecodePlanGistGenerator,
+			"Returns the plan encoded by a plan gist, such as the ones in the planGists "+
+				"field of the statistics in crdb_internal.statement_statistics, in a form "+
+				"similar to the output of EXPLAIN.",
+			tree.VolatilityVolatile,
+		),
+	),
+
 	"crdb_internal.check_consistency": makeBuiltin(
 		tree.FunctionProperties{
 			Class:    tree.GeneratorClass,
@@ -384,6 +403,21 @@ func (g *regexpSplitToTableGenerator) Values() (tree.Datums, error) {
 	return tree.Datums{tree.NewDString(g.words[g.curr])}, nil
 }
 
+func makeDecodePlanGistGenerator(
+	ctx *tree.EvalContext, args tree.Datums,
+) (tree.ValueGenerator, error) {
+	rows, err := ctx.Planner.DecodePlanGist(ctx.Ctx(), string(tree.MustBeDString(args[0])))
+	if err != nil {
+		return nil, err
+	}
+	// The rows are returned one at a time, like the substrings of
+	// regexp_split_to_table.
+	return &regexpSplitToTableGenerator{
+		words: rows,
+		curr:  -1,
+	}, nil
+}
+
 // keywordsValueGenerator supports the execution of pg_get_keywords().
 type keywordsValueGenerator struct {
 	curKeyword int
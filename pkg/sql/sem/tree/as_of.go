@@ -34,13 +34,60 @@ const FollowerReadTimestampFunctionName = "follower_read_timestamp"
 // "experimental_" function, which we keep for backwards compatibility.
 const FollowerReadTimestampExperimentalFunctionName = "experimental_follower_read_timestamp"
 
-var errInvalidExprForAsOf = errors.Errorf("AS OF SYSTEM TIME: only constant expressions or " +
-	FollowerReadTimestampFunctionName + " are allowed")
+// WithMinTimestampFunctionName is the name of the function that can be used
+// with AOST clauses to generate a bounded staleness at a fixed timestamp.
+const WithMinTimestampFunctionName = "with_min_timestamp"
+
+// WithMaxStalenessFunctionName is the name of the function that can be used
+// with AOST clauses to generate a bounded staleness at a maximum interval.
+const WithMaxStalenessFunctionName = "with_max_staleness"
+
+var errInvalidExprForAsOf = errors.Errorf("AS OF SYSTEM TIME: only constant expressions, " +
+	WithMinTimestampFunctionName + ", " + WithMaxStalenessFunctionName +
+	", or " + FollowerReadTimestampFunctionName + " are allowed")
+
+// AsOfSystemTime represents the result from the evaluation of an AS OF SYSTEM
+// TIME clause.
+type AsOfSystemTime struct {
+	// Timestamp is the HLC timestamp evaluated from the AS OF SYSTEM TIME
+	// clause. If BoundedStaleness is set, it is the minimum timestamp bound of
+	// the read.
+	Timestamp hlc.Timestamp
+	// BoundedStaleness is true if the AS OF SYSTEM TIME clause specifies
+	// that the read should be performed using bounded staleness, i.e. at
+	// the freshest timestamp no older than Timestamp that can be served
+	// locally without blocking.
+	BoundedStaleness bool
+	// NearestOnly is true if a bounded staleness read must be served by the
+	// nearest replica and fail rather than be redirected to the leaseholder
+	// when the minimum timestamp bound cannot be satisfied locally.
+	NearestOnly bool
+}
+
+// EvalAsOfTimestampOption is an option to pass into EvalAsOfTimestamp.
+type EvalAsOfTimestampOption int
+
+const (
+	// EvalAsOfTimestampOptionAllowBoundedStaleness signifies that
+	// EvalAsOfTimestamp should accept the bounded staleness functions.
+	EvalAsOfTimestampOptionAllowBoundedStaleness EvalAsOfTimestampOption = iota + 1
+)
 
 // EvalAsOfTimestamp evaluates the timestamp argument to an AS OF SYSTEM TIME query.
 func EvalAsOfTimestamp(
-	ctx context.Context, asOf AsOfClause, semaCtx *SemaContext, evalCtx *EvalContext,
-) (tsss hlc.Timestamp, err error) {
+	ctx context.Context,
+	asOf AsOfClause,
+	semaCtx *SemaContext,
+	evalCtx *EvalContext,
+	opts ...EvalAsOfTimestampOption,
+) (AsOfSystemTime, error) {
+	allowBoundedStaleness := false
+	for _, opt := range opts {
+		if opt == EvalAsOfTimestampOptionAllowBoundedStaleness {
+			allowBoundedStaleness = true
+		}
+	}
+
 	// We need to save and restore the previous value of the field in
 	// semaCtx in case we are recursively called within a subquery
 	// context.
@@ -49,42 +96,71 @@ func EvalAsOfTimestamp(
 	scalarProps.Require("AS OF SYSTEM TIME", RejectSpecial|RejectSubqueries)
 
 	// In order to support the follower reads feature we permit this expression
-	// to be a simple invocation of the `FollowerReadTimestampFunction`.
+	// to be a simple invocation of the `FollowerReadTimestampFunction` or of
+	// one of the bounded staleness functions.
 	// Over time we could expand the set of allowed functions or expressions.
 	// All non-function expressions must be const and must TypeCheck into a
 	// string.
 	var te TypedExpr
+	var res AsOfSystemTime
 	if fe, ok := asOf.Expr.(*FuncExpr); ok {
 		def, err := fe.Func.Resolve(semaCtx.SearchPath)
 		if err != nil {
-			return hlc.Timestamp{}, errInvalidExprForAsOf
+			return AsOfSystemTime{}, errInvalidExprForAsOf
 		}
-		if def.Name != FollowerReadTimestampFunctionName &&
-			def.Name != FollowerReadTimestampExperimentalFunctionName {
-			return hlc.Timestamp{}, errInvalidExprForAsOf
+		switch def.Name {
+		case FollowerReadTimestampFunctionName, FollowerReadTimestampExperimentalFunctionName:
+		case WithMinTimestampFunctionName, WithMaxStalenessFunctionName:
+			if !allowBoundedStaleness {
+				return AsOfSystemTime{}, pgerror.Newf(
+					pgcode.FeatureNotSupported,
+					"AS OF SYSTEM TIME: %s can only be used with a single-statement, read-only "+
+						"SELECT in an implicit transaction",
+					def.Name,
+				)
+			}
+			res.BoundedStaleness = true
+		default:
+			return AsOfSystemTime{}, errInvalidExprForAsOf
 		}
 		if te, err = fe.TypeCheck(ctx, semaCtx, types.TimestampTZ); err != nil {
-			return hlc.Timestamp{}, err
+			return AsOfSystemTime{}, err
+		}
+		if res.BoundedStaleness {
+			// The optional second argument of the bounded staleness functions
+			// determines whether the read must be served by the nearest replica.
+			if args := te.(*FuncExpr).Exprs; len(args) > 1 {
+				d, err := args[1].(TypedExpr).Eval(evalCtx)
+				if err != nil {
+					return AsOfSystemTime{}, err
+				}
+				if b, ok := d.(*DBool); ok {
+					res.NearestOnly = bool(*b)
+				}
+			}
 		}
 	} else {
 		var err error
 		te, err = asOf.Expr.TypeCheck(ctx, semaCtx, types.String)
 		if err != nil {
-			return hlc.Timestamp{}, err
+			return AsOfSystemTime{}, err
 		}
 		if !IsConst(evalCtx, te) {
-			return hlc.Timestamp{}, errInvalidExprForAsOf
+			return AsOfSystemTime{}, errInvalidExprForAsOf
 		}
 	}
 
 	d, err := te.Eval(evalCtx)
 	if err != nil {
-		return hlc.Timestamp{}, err
+		return AsOfSystemTime{}, err
 	}
 
 	stmtTimestamp := evalCtx.GetStmtTimestamp()
-	ts, err := DatumToHLC(evalCtx, stmtTimestamp, d)
-	return ts, errors.Wrap(err, "AS OF SYSTEM TIME")
+	res.Timestamp, err = DatumToHLC(evalCtx, stmtTimestamp, d)
+	if err != nil {
+		return AsOfSystemTime{}, errors.Wrap(err, "AS OF SYSTEM TIME")
+	}
+	return res, nil
 }
 
 // DatumToHLC performs the conversion from a Datum to an HLC timestamp.
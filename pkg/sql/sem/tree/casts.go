@@ -176,6 +176,8 @@ var validCasts = []castInfo{
 	{from: types.OidFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.INetFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.JsonFamily, to: types.StringFamily, volatility: VolatilityImmutable},
+	{from: types.TSQueryFamily, to: types.StringFamily, volatility: VolatilityImmutable},
+	{from: types.TSVectorFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.EnumFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 
 	// Casts to CollatedStringFamily.
@@ -203,6 +205,8 @@ var validCasts = []castInfo{
 	{from: types.OidFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.INetFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.JsonFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
+	{from: types.TSQueryFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
+	{from: types.TSVectorFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.EnumFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 
 	// Casts to BytesFamily.
@@ -300,6 +304,18 @@ var validCasts = []castInfo{
 	{from: types.GeometryFamily, to: types.JsonFamily, volatility: VolatilityImmutable},
 	{from: types.GeographyFamily, to: types.JsonFamily, volatility: VolatilityImmutable},
 
+	// Casts to TSQueryFamily.
+	{from: types.UnknownFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+	{from: types.StringFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+	{from: types.CollatedStringFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+	{from: types.TSQueryFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+
+	// Casts to TSVectorFamily.
+	{from: types.UnknownFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+	{from: types.StringFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+	{from: types.CollatedStringFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+	{from: types.TSVectorFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+
 	// Casts to EnumFamily.
 	{from: types.UnknownFamily, to: types.EnumFamily, volatility: VolatilityImmutable},
 	{from: types.StringFamily, to: types.EnumFamily, volatility: VolatilityImmutable},
@@ -856,6 +872,10 @@ func performCastWithoutPrecisionTruncation(ctx *EvalContext, d Datum, t *types.T
 			s = t.String()
 		case *DJSON:
 			s = t.JSON.String()
+		case *DTSQuery:
+			s = t.TSQuery.String()
+		case *DTSVector:
+			s = t.TSVector.String()
 		case *DEnum:
 			s = t.LogicalRep
 		}
@@ -943,6 +963,26 @@ func performCastWithoutPrecisionTruncation(ctx *EvalContext, d Datum, t *types.T
 			return NewDBox2D(*bbox), nil
 		}
 
+	case types.TSQueryFamily:
+		switch d := d.(type) {
+		case *DString:
+			return ParseDTSQuery(string(*d))
+		case *DCollatedString:
+			return ParseDTSQuery(d.Contents)
+		case *DTSQuery:
+			return d, nil
+		}
+
+	case types.TSVectorFamily:
+		switch d := d.(type) {
+		case *DString:
+			return ParseDTSVector(string(*d))
+		case *DCollatedString:
+			return ParseDTSVector(d.Contents)
+		case *DTSVector:
+			return d, nil
+		}
+
 	case types.GeographyFamily:
 		switch d := d.(type) {
 		case *DString:
@@ -491,6 +491,8 @@ var (
 		types.INet,
 		types.Jsonb,
 		types.VarBit,
+		types.TSQuery,
+		types.TSVector,
 		types.AnyEnum,
 		types.INetArray,
 		types.VarBitArray,
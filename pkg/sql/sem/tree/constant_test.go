@@ -217,7 +217,9 @@ func TestStringConstantVerifyAvailableTypes(t *testing.T) {
 
 			semaCtx := tree.MakeSemaContext()
 			if _, err := test.c.ResolveAsType(context.Background(), &semaCtx, availType); err != nil {
-				if !strings.Contains(err.Error(), "could not parse") {
+				if !strings.Contains(err.Error(), "could not parse") &&
+					!strings.Contains(err.Error(), "in tsquery") &&
+					!strings.Contains(err.Error(), "in tsvector") {
 					// Parsing errors are permitted for this test, as proper tree.StrVal parsing
 					// is tested in TestStringConstantTypeResolution. Any other error should
 					// throw a failure.
@@ -349,6 +351,20 @@ func mustParseDVarBit(t *testing.T, s string) tree.Datum {
 	}
 	return d
 }
+func mustParseDTSQuery(t *testing.T, s string) tree.Datum {
+	d, err := tree.ParseDTSQuery(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+func mustParseDTSVector(t *testing.T, s string) tree.Datum {
+	d, err := tree.ParseDTSVector(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
 func mustParseDArrayOfType(typ *types.T) func(t *testing.T, s string) tree.Datum {
 	return func(t *testing.T, s string) tree.Datum {
 		evalContext := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
@@ -380,6 +396,8 @@ var parseFuncs = map[*types.T]func(*testing.T, string) tree.Datum{
 	types.Geometry:         mustParseDGeometry,
 	types.INet:             mustParseDINet,
 	types.VarBit:           mustParseDVarBit,
+	types.TSQuery:          mustParseDTSQuery,
+	types.TSVector:         mustParseDTSVector,
 	types.DecimalArray:     mustParseDArrayOfType(types.Decimal),
 	types.FloatArray:       mustParseDArrayOfType(types.Float),
 	types.IntArray:         mustParseDArrayOfType(types.Int),
@@ -418,15 +436,28 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 	}{
 		{
 			c:            tree.NewStrVal("abc 世界"),
-			parseOptions: typeSet(types.String, types.Bytes),
+			parseOptions: typeSet(types.String, types.Bytes, types.TSVector),
 		},
 		{
-			c:            tree.NewStrVal("true"),
-			parseOptions: typeSet(types.String, types.Bytes, types.Bool, types.Jsonb),
+			c: tree.NewStrVal("true"),
+			parseOptions: typeSet(
+				types.String,
+				types.Bytes,
+				types.Bool,
+				types.Jsonb,
+				types.TSQuery,
+				types.TSVector),
 		},
 		{
-			c:            tree.NewStrVal("2010-09-28"),
-			parseOptions: typeSet(types.String, types.Bytes, types.Date, types.Timestamp, types.TimestampTZ),
+			c: tree.NewStrVal("2010-09-28"),
+			parseOptions: typeSet(
+				types.String,
+				types.Bytes,
+				types.Date,
+				types.Timestamp,
+				types.TimestampTZ,
+				types.TSQuery,
+				types.TSVector),
 		},
 		{
 			c:            tree.NewStrVal("2010-09-28 12:00:00.1"),
@@ -437,8 +468,13 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 			parseOptions: typeSet(types.String, types.Bytes, types.Time, types.TimeTZ, types.Timestamp, types.TimestampTZ, types.Date),
 		},
 		{
-			c:            tree.NewStrVal("PT12H2M"),
-			parseOptions: typeSet(types.String, types.Bytes, types.Interval),
+			c: tree.NewStrVal("PT12H2M"),
+			parseOptions: typeSet(
+				types.String,
+				types.Bytes,
+				types.Interval,
+				types.TSQuery,
+				types.TSVector),
 		},
 		{
 			c:            tree.NewBytesStrVal("abc 世界"),
@@ -462,15 +498,20 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 		},
 		{
 			c:            tree.NewStrVal("box(0 0, 1 1)"),
-			parseOptions: typeSet(types.String, types.Bytes, types.Box2D),
+			parseOptions: typeSet(types.String, types.Bytes, types.Box2D, types.TSVector),
 		},
 		{
-			c:            tree.NewStrVal("POINT(-100.59 42.94)"),
-			parseOptions: typeSet(types.String, types.Bytes, types.Geography, types.Geometry),
+			c: tree.NewStrVal("POINT(-100.59 42.94)"),
+			parseOptions: typeSet(
+				types.String,
+				types.Bytes,
+				types.Geography,
+				types.Geometry,
+				types.TSVector),
 		},
 		{
 			c:            tree.NewStrVal("192.168.100.128/25"),
-			parseOptions: typeSet(types.String, types.Bytes, types.INet),
+			parseOptions: typeSet(types.String, types.Bytes, types.INet, types.TSQuery, types.TSVector),
 		},
 		{
 			c: tree.NewStrVal("111000110101"),
@@ -482,7 +523,9 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 				types.Float,
 				types.Decimal,
 				types.Interval,
-				types.Jsonb),
+				types.Jsonb,
+				types.TSQuery,
+				types.TSVector),
 		},
 		{
 			c:            tree.NewStrVal(`{"a": 1}`),
@@ -497,7 +540,9 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 				types.IntArray,
 				types.FloatArray,
 				types.DecimalArray,
-				types.IntervalArray),
+				types.IntervalArray,
+				types.TSQuery,
+				types.TSVector),
 		},
 		{
 			c: tree.NewStrVal(`{1.5,2.0}`),
@@ -507,11 +552,18 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 				types.StringArray,
 				types.FloatArray,
 				types.DecimalArray,
-				types.IntervalArray),
+				types.IntervalArray,
+				types.TSQuery,
+				types.TSVector),
 		},
 		{
-			c:            tree.NewStrVal(`{a,b}`),
-			parseOptions: typeSet(types.String, types.Bytes, types.StringArray),
+			c: tree.NewStrVal(`{a,b}`),
+			parseOptions: typeSet(
+				types.String,
+				types.Bytes,
+				types.StringArray,
+				types.TSQuery,
+				types.TSVector),
 		},
 		{
 			c:            tree.NewBytesStrVal(string([]byte{0xff, 0xfe, 0xfd})),
@@ -519,19 +571,36 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 		},
 		{
 			c:            tree.NewStrVal(`18e7b17e-4ead-4e27-bfd5-bb6d11261bb6`),
-			parseOptions: typeSet(types.String, types.Bytes, types.Uuid),
+			parseOptions: typeSet(types.String, types.Bytes, types.Uuid, types.TSQuery, types.TSVector),
 		},
 		{
-			c:            tree.NewStrVal(`{18e7b17e-4ead-4e27-bfd5-bb6d11261bb6, 18e7b17e-4ead-4e27-bfd5-bb6d11261bb7}`),
-			parseOptions: typeSet(types.String, types.Bytes, types.StringArray, types.UUIDArray),
+			c: tree.NewStrVal(`{18e7b17e-4ead-4e27-bfd5-bb6d11261bb6, 18e7b17e-4ead-4e27-bfd5-bb6d11261bb7}`),
+			parseOptions: typeSet(
+				types.String,
+				types.Bytes,
+				types.StringArray,
+				types.UUIDArray,
+				types.TSVector),
 		},
 		{
-			c:            tree.NewStrVal("{true, false}"),
-			parseOptions: typeSet(types.String, types.Bytes, types.StringArray, types.BoolArray),
+			c: tree.NewStrVal("{true, false}"),
+			parseOptions: typeSet(
+				types.String,
+				types.Bytes,
+				types.StringArray,
+				types.BoolArray,
+				types.TSVector),
 		},
 		{
-			c:            tree.NewStrVal("{2010-09-28, 2010-09-29}"),
-			parseOptions: typeSet(types.String, types.Bytes, types.StringArray, types.DateArray, types.TimestampArray, types.TimestampTZArray),
+			c: tree.NewStrVal("{2010-09-28, 2010-09-29}"),
+			parseOptions: typeSet(
+				types.String,
+				types.Bytes,
+				types.StringArray,
+				types.DateArray,
+				types.TimestampArray,
+				types.TimestampTZArray,
+				types.TSVector),
 		},
 		{
 			c: tree.NewStrVal("{2010-09-28 12:00:00.1, 2010-09-29 12:00:00.1}"),
@@ -575,7 +644,8 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 				types.FloatArray,
 				types.DecimalArray,
 				types.IntervalArray,
-				types.VarBitArray),
+				types.VarBitArray,
+				types.TSVector),
 		},
 	}
 
@@ -603,6 +673,8 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 			if err != nil {
 				if !strings.Contains(err.Error(), "could not parse") &&
 					!strings.Contains(err.Error(), "parsing") &&
+					!strings.Contains(err.Error(), "in tsquery") &&
+					!strings.Contains(err.Error(), "in tsvector") &&
 					!strings.Contains(err.Error(), "out of range") &&
 					!strings.Contains(err.Error(), "exceeds supported") {
 					// Parsing errors are permitted for this test, but the number of correctly
@@ -96,7 +96,10 @@ type IndexElem struct {
 	Column Name
 	// Expr is set if the index element is an expression (part of an
 	// expression-based index). If set, Column is empty.
-	Expr       Expr
+	Expr Expr
+	// OpClass is set if an operator class was specified for the index element,
+	// e.g. gin_trgm_ops for a trigram inverted index.
+	OpClass    Name
 	Direction  Direction
 	NullsOrder NullsOrder
 }
@@ -117,6 +120,10 @@ func (node *IndexElem) Format(ctx *FmtCtx) {
 			ctx.WriteByte(')')
 		}
 	}
+	if node.OpClass != "" {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(&node.OpClass)
+	}
 	if node.Direction != DefaultDirection {
 		ctx.WriteByte(' ')
 		ctx.WriteString(node.Direction.String())
@@ -139,6 +146,9 @@ func (node *IndexElem) doc(p *PrettyCfg) pretty.Doc {
 			d = p.bracket("(", d, ")")
 		}
 	}
+	if node.OpClass != "" {
+		d = pretty.ConcatSpace(d, p.Doc(&node.OpClass))
+	}
 	if node.Direction != DefaultDirection {
 		d = pretty.ConcatSpace(d, pretty.Keyword(node.Direction.String()))
 	}
@@ -1801,11 +1811,16 @@ type CreateStatsOptions struct {
 	// Note that the timestamp will be moved up during the operation if it gets
 	// too old (in order to avoid problems with TTL expiration).
 	AsOf AsOfClause
+
+	// UsingExtremes creates a partial statistic that only covers the values
+	// below and above the bounds of the histogram of the most recent full
+	// statistic on the column.
+	UsingExtremes bool
 }
 
 // Empty returns true if no options were provided.
 func (o *CreateStatsOptions) Empty() bool {
-	return o.Throttling == 0 && o.AsOf.Expr == nil
+	return o.Throttling == 0 && o.AsOf.Expr == nil && !o.UsingExtremes
 }
 
 // Format implements the NodeFormatter interface.
@@ -1820,6 +1835,10 @@ func (o *CreateStatsOptions) Format(ctx *FmtCtx) {
 		ctx.FormatNode(&o.AsOf)
 		sep = " "
 	}
+	if o.UsingExtremes {
+		ctx.WriteString(sep)
+		ctx.WriteString("USING EXTREMES")
+	}
 }
 
 // CombineWith combines two options, erroring out if the two options contain
@@ -1837,6 +1856,12 @@ func (o *CreateStatsOptions) CombineWith(other *CreateStatsOptions) error {
 		}
 		o.AsOf = other.AsOf
 	}
+	if other.UsingExtremes {
+		if o.UsingExtremes {
+			return errors.New("USING EXTREMES specified multiple times")
+		}
+		o.UsingExtremes = true
+	}
 	return nil
 }
 
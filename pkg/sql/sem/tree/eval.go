@@ -46,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -1637,6 +1638,17 @@ var BinOps = map[BinaryOperator]binOpOverload{
 			},
 			Volatility: VolatilityImmutable,
 		},
+		&BinOp{
+			// The trigram similarity operator of pg_trgm.
+			LeftType:   types.String,
+			RightType:  types.String,
+			ReturnType: types.Bool,
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				s := trigram.Similarity(string(MustBeDString(left)), string(MustBeDString(right)))
+				return MakeDBool(s >= ctx.SessionData.TrigramSimilarityThreshold), nil
+			},
+			Volatility: VolatilityStable,
+		},
 	},
 
 	Concat: {
@@ -2116,6 +2128,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
 		makeEqFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeEqFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
+		makeEqFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeEqFn(types.TSVector, types.TSVector, VolatilityImmutable),
 		makeEqFn(types.Uuid, types.Uuid, VolatilityLeakProof),
 		makeEqFn(types.VarBit, types.VarBit, VolatilityLeakProof),
 
@@ -2172,6 +2186,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeLtFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
 		makeLtFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeLtFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
+		makeLtFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeLtFn(types.TSVector, types.TSVector, VolatilityImmutable),
 		makeLtFn(types.Uuid, types.Uuid, VolatilityLeakProof),
 		makeLtFn(types.VarBit, types.VarBit, VolatilityLeakProof),
 
@@ -2228,6 +2244,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeLeFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
 		makeLeFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeLeFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
+		makeLeFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeLeFn(types.TSVector, types.TSVector, VolatilityImmutable),
 		makeLeFn(types.Uuid, types.Uuid, VolatilityLeakProof),
 		makeLeFn(types.VarBit, types.VarBit, VolatilityLeakProof),
 
@@ -2294,6 +2312,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
 		makeIsFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeIsFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
+		makeIsFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeIsFn(types.TSVector, types.TSVector, VolatilityImmutable),
 		makeIsFn(types.Uuid, types.Uuid, VolatilityLeakProof),
 		makeIsFn(types.VarBit, types.VarBit, VolatilityLeakProof),
 
@@ -2352,6 +2372,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(types.TimeTZ, VolatilityLeakProof),
 		makeEvalTupleIn(types.Timestamp, VolatilityLeakProof),
 		makeEvalTupleIn(types.TimestampTZ, VolatilityLeakProof),
+		makeEvalTupleIn(types.TSQuery, VolatilityLeakProof),
+		makeEvalTupleIn(types.TSVector, VolatilityLeakProof),
 		makeEvalTupleIn(types.Uuid, VolatilityLeakProof),
 		makeEvalTupleIn(types.VarBit, VolatilityLeakProof),
 	},
@@ -2566,6 +2588,29 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 			},
 		)...,
 	),
+
+	TSMatches: {
+		&CmpOp{
+			LeftType:  types.TSVector,
+			RightType: types.TSQuery,
+			Fn: func(_ *EvalContext, left, right Datum) (Datum, error) {
+				v := MustBeDTSVector(left).TSVector
+				q := MustBeDTSQuery(right).TSQuery
+				return MakeDBool(DBool(q.Matches(v))), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+		&CmpOp{
+			LeftType:  types.TSQuery,
+			RightType: types.TSVector,
+			Fn: func(_ *EvalContext, left, right Datum) (Datum, error) {
+				q := MustBeDTSQuery(left).TSQuery
+				v := MustBeDTSVector(right).TSVector
+				return MakeDBool(DBool(q.Matches(v))), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+	},
 })
 
 const experimentalBox2DClusterSettingName = "sql.spatial.experimental_box2d_comparison_operators.enabled"
@@ -3064,6 +3109,18 @@ type EvalPlanner interface {
 		descID int64,
 		force bool,
 	) error
+
+	// AddStatementHint adds a hint for the statements with the given
+	// fingerprint to system.statement_hints.
+	AddStatementHint(ctx context.Context, fingerprint string, hintType string, hintValue string) error
+
+	// RemoveStatementHints removes all hints for the statements with the given
+	// fingerprint, and returns the number of hints removed.
+	RemoveStatementHints(ctx context.Context, fingerprint string) (int, error)
+
+	// DecodePlanGist decodes a plan gist into an EXPLAIN-like representation
+	// of the plan, one row per string.
+	DecodePlanGist(ctx context.Context, gist string) ([]string, error)
 }
 
 // EvalSessionAccessor is a limited interface to access session variables.
@@ -3183,6 +3240,16 @@ type TenantOperator interface {
 	// success it also removes the tenant record.
 	// It returns an error if the tenant does not exist.
 	GCTenant(ctx context.Context, tenantID uint64) error
+
+	// UpdateTenantResourceLimits reconfigures the tenant resource limits.
+	// See multitenant.TenantUsageServer for more details on the arguments.
+	UpdateTenantResourceLimits(
+		ctx context.Context,
+		tenantID uint64,
+		availableRU float64,
+		refillRate float64,
+		maxBurstRU float64,
+	) error
 }
 
 // EvalContextTestingKnobs contains test knobs.
@@ -3315,6 +3382,10 @@ type EvalContext struct {
 	// that is also able to save the plan to skip work during the exec step.
 	PrepareOnly bool
 
+	// AsOfSystemTime denotes the result of the evaluation of the AS OF SYSTEM
+	// TIME clause of the statement being executed, if any.
+	AsOfSystemTime *AsOfSystemTime
+
 	// SkipNormalize indicates whether expressions should be normalized
 	// (false) or not (true).  It is set to true conditionally by
 	// EXPLAIN(TYPES[, NORMALIZE]).
@@ -3982,6 +4053,11 @@ func ensureExpectedType(exp *types.T, d Datum) error {
 	return nil
 }
 
+// Eval implements the TypedExpr interface.
+func (expr *GroupingExpr) Eval(ctx *EvalContext) (Datum, error) {
+	return nil, errors.AssertionFailedf("unhandled type %T", expr)
+}
+
 // Eval implements the TypedExpr interface.
 func (expr *IfErrExpr) Eval(ctx *EvalContext) (Datum, error) {
 	cond, evalErr := expr.Cond.(TypedExpr).Eval(ctx)
@@ -4361,6 +4437,16 @@ func (t *DJSON) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DTSQuery) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DTSVector) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t dNull) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
@@ -119,6 +119,9 @@ const (
 	// RegionEnum is the name of the per-database region enum required for
 	// multi-region.
 	RegionEnum string = "crdb_internal_region"
+	// RegionalByRowRegionDefaultCol is the name of the hidden column that
+	// stores the region of each row in a REGIONAL BY ROW table.
+	RegionalByRowRegionDefaultCol string = "crdb_region"
 )
 
 // NumResolutionResults represents the number of results in the lookup
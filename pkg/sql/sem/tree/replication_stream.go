@@ -0,0 +1,29 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// ReplicationStream represents a CREATE REPLICATION STREAM statement.
+type ReplicationStream struct {
+	Targets TargetList
+	Options KVOptions
+}
+
+var _ Statement = &ReplicationStream{}
+
+// Format implements the NodeFormatter interface.
+func (node *ReplicationStream) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE REPLICATION STREAM FOR ")
+	ctx.FormatNode(&node.Targets)
+	if node.Options != nil {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
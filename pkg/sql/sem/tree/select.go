@@ -570,6 +570,49 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetType represents the form of a GroupingSet.
+type GroupingSetType int
+
+// GroupingSetType values.
+const (
+	// RollupGroupingSet represents ROLLUP (a, b, ...), which groups on every
+	// prefix of its list of expressions.
+	RollupGroupingSet GroupingSetType = iota
+	// CubeGroupingSet represents CUBE (a, b, ...), which groups on every subset
+	// of its list of expressions.
+	CubeGroupingSet
+	// ExplicitGroupingSets represents GROUPING SETS (...), which lists the
+	// grouping sets explicitly.
+	ExplicitGroupingSets
+)
+
+var groupingSetTypeName = [...]string{
+	RollupGroupingSet:    "ROLLUP",
+	CubeGroupingSet:      "CUBE",
+	ExplicitGroupingSets: "GROUPING SETS",
+}
+
+func (t GroupingSetType) String() string {
+	return groupingSetTypeName[t]
+}
+
+// GroupingSet represents a ROLLUP, CUBE or GROUPING SETS item in a GROUP BY
+// clause. Each of the Exprs is either a single grouping expression, a Tuple of
+// grouping expressions that are treated as a unit (the empty Tuple stands for
+// the empty grouping set) or, for GROUPING SETS, a nested GroupingSet.
+type GroupingSet struct {
+	Type  GroupingSetType
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
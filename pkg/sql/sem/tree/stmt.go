@@ -102,7 +102,7 @@ func CanWriteData(stmt Statement) bool {
 	case *Insert, *Delete, *Update, *Truncate:
 		return true
 	// Import operations.
-	case *CopyFrom, *Import, *Restore:
+	case *CopyFrom, *Import, *Restore, *StreamIngestion:
 		return true
 	// CockroachDB extensions.
 	case *Split, *Unsplit, *Relocate, *Scatter:
@@ -169,6 +169,8 @@ var _ CCLOnlyStatement = &CreateChangefeed{}
 var _ CCLOnlyStatement = &Import{}
 var _ CCLOnlyStatement = &Export{}
 var _ CCLOnlyStatement = &ScheduledBackup{}
+var _ CCLOnlyStatement = &StreamIngestion{}
+var _ CCLOnlyStatement = &ReplicationStream{}
 
 // StatementType implements the Statement interface.
 func (*AlterDatabaseOwner) StatementType() StatementType { return DDL }
@@ -686,6 +688,14 @@ func (n *Relocate) StatementTag() string {
 	return "EXPERIMENTAL_RELOCATE"
 }
 
+// StatementType implements the Statement interface.
+func (*ReplicationStream) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ReplicationStream) StatementTag() string { return "CREATE REPLICATION STREAM" }
+
+func (*ReplicationStream) cclOnlyStatement() {}
+
 // StatementType implements the Statement interface.
 func (*Restore) StatementType() StatementType { return Rows }
 
@@ -1039,6 +1049,14 @@ func (*Split) StatementType() StatementType { return Rows }
 // StatementTag returns a short string identifying the type of statement.
 func (*Split) StatementTag() string { return "SPLIT" }
 
+// StatementType implements the Statement interface.
+func (*StreamIngestion) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*StreamIngestion) StatementTag() string { return "RESTORE FROM REPLICATION STREAM" }
+
+func (*StreamIngestion) cclOnlyStatement() {}
+
 // StatementType implements the Statement interface.
 func (*Unsplit) StatementType() StatementType { return Rows }
 
@@ -1150,6 +1168,7 @@ func (n *RenameDatabase) String() string                 { return AsString(n) }
 func (n *ReparentDatabase) String() string               { return AsString(n) }
 func (n *RenameIndex) String() string                    { return AsString(n) }
 func (n *RenameTable) String() string                    { return AsString(n) }
+func (n *ReplicationStream) String() string              { return AsString(n) }
 func (n *Restore) String() string                        { return AsString(n) }
 func (n *Revoke) String() string                         { return AsString(n) }
 func (n *RevokeRole) String() string                     { return AsString(n) }
@@ -1207,6 +1226,7 @@ func (n *ShowVar) String() string                        { return AsString(n) }
 func (n *ShowZoneConfig) String() string                 { return AsString(n) }
 func (n *ShowFingerprints) String() string               { return AsString(n) }
 func (n *Split) String() string                          { return AsString(n) }
+func (n *StreamIngestion) String() string                { return AsString(n) }
 func (n *Unsplit) String() string                        { return AsString(n) }
 func (n *Truncate) String() string                       { return AsString(n) }
 func (n *UnionClause) String() string                    { return AsString(n) }
@@ -80,6 +80,12 @@ func SampleDatum(t *types.T) Datum {
 		return j
 	case types.OidFamily:
 		return NewDOid(DInt(1009))
+	case types.TSQueryFamily:
+		q, _ := ParseDTSQuery("'a' & 'b'")
+		return q
+	case types.TSVectorFamily:
+		v, _ := ParseDTSVector("'a':1 'b':2")
+		return v
 	case types.Box2DFamily:
 		b := geo.NewCartesianBoundingBox().AddPoint(1, 2).AddPoint(3, 4)
 		return NewDBox2D(*b)
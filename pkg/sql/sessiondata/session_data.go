@@ -182,6 +182,9 @@ type LocalOnlySessionData struct {
 	// ZigzagJoinEnabled indicates whether the optimizer should try and plan a
 	// zigzag join.
 	ZigzagJoinEnabled bool
+	// IndexRecommendationsEnabled indicates whether EXPLAIN should include
+	// recommendations for indexes that would make the statement cheaper.
+	IndexRecommendationsEnabled bool
 	// RequireExplicitPrimaryKeys indicates whether CREATE TABLE statements should
 	// error out if no primary key is provided.
 	RequireExplicitPrimaryKeys bool
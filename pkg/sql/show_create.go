@@ -96,9 +96,11 @@ func ShowCreateTable(
 			return "", err
 		}
 		f.WriteString(colstr)
-		if desc.IsPhysicalTable() && desc.GetPrimaryIndex().ColumnIDs[0] == col.ID {
+		if desc.IsPhysicalTable() && desc.GetPrimaryIndex().ContainsColumnID(col.ID) {
 			// Only set primaryKeyIsOnVisibleColumn to true if the primary key
-			// is on a visible column (not rowid).
+			// is on a visible column (not rowid). The primary key of a
+			// REGIONAL BY ROW table is prefixed by the hidden region column,
+			// but still contains visible columns.
 			primaryKeyIsOnVisibleColumn = true
 		}
 	}
@@ -74,7 +74,8 @@ func (p *planner) ShowTableStats(ctx context.Context, n *tree.ShowTableStats) (p
 					      "rowCount",
 					      "distinctCount",
 					      "nullCount",
-					      histogram
+					      histogram,
+					      "partialPredicate"
 				 FROM system.table_statistics
 				 WHERE "tableID" = $1
 				 ORDER BY "createdAt"`,
@@ -93,6 +94,7 @@ func (p *planner) ShowTableStats(ctx context.Context, n *tree.ShowTableStats) (p
 				distinctCountIdx
 				nullCountIdx
 				histogramIdx
+				partialPredicateIdx
 				numCols
 			)
 
@@ -107,6 +109,9 @@ func (p *planner) ShowTableStats(ctx context.Context, n *tree.ShowTableStats) (p
 					if r[nameIdx] != tree.DNull {
 						result[i].Name = string(*r[nameIdx].(*tree.DString))
 					}
+					if r[partialPredicateIdx] != tree.DNull {
+						result[i].PartialPredicate = string(*r[partialPredicateIdx].(*tree.DString))
+					}
 					colIDs := r[columnIDsIdx].(*tree.DArray).Array
 					result[i].Columns = make([]string, len(colIDs))
 					for j, d := range colIDs {
@@ -0,0 +1,96 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmthints"
+	"github.com/cockroachdb/errors"
+)
+
+// statementHints returns the statement hints that apply to the statement
+// being planned, or nil if there are none. The hints of an EXPLAIN statement
+// are those of the explained statement.
+func (p *planner) statementHints() *stmthints.Hints {
+	cache := p.execCfg.StatementHintsCache
+	if cache == nil || p.stmt.AST == nil {
+		return nil
+	}
+	// Statements that the system runs internally are never hinted. Besides
+	// being pointless, this prevents hints from changing the plans of the
+	// queries that maintain the hints themselves.
+	if strings.HasPrefix(p.SessionData().ApplicationName, catconstants.InternalAppNamePrefix) {
+		return nil
+	}
+	return cache.Lookup(func() string {
+		stmt := p.stmt.AST
+		switch t := stmt.(type) {
+		case *tree.Explain:
+			stmt = t.Statement
+		case *tree.ExplainAnalyze:
+			stmt = t.Statement
+		}
+		return anonymizeStmt(stmt)
+	})
+}
+
+// AddStatementHint is part of the tree.EvalPlanner interface.
+func (p *planner) AddStatementHint(
+	ctx context.Context, fingerprint string, hintType string, hintValue string,
+) error {
+	if err := p.RequireAdminRole(ctx, "add statement hints"); err != nil {
+		return err
+	}
+	fingerprint, err := p.normalizeStatementFingerprint(fingerprint)
+	if err != nil {
+		return err
+	}
+	return p.execCfg.StatementHintsCache.AddHint(ctx, fingerprint, stmthints.Hint{
+		Type:  stmthints.HintType(hintType),
+		Value: hintValue,
+	})
+}
+
+// RemoveStatementHints is part of the tree.EvalPlanner interface.
+func (p *planner) RemoveStatementHints(ctx context.Context, fingerprint string) (int, error) {
+	if err := p.RequireAdminRole(ctx, "remove statement hints"); err != nil {
+		return 0, err
+	}
+	fingerprint, err := p.normalizeStatementFingerprint(fingerprint)
+	if err != nil {
+		return 0, err
+	}
+	return p.execCfg.StatementHintsCache.RemoveHints(ctx, fingerprint)
+}
+
+// normalizeStatementFingerprint returns the fingerprint of the given
+// statement, which can be either a fingerprint already or a statement with
+// constants. Reformatting the statement makes the fingerprint insensitive to
+// spacing and capitalization, so that it matches the fingerprint computed for
+// executed statements.
+func (p *planner) normalizeStatementFingerprint(fingerprint string) (string, error) {
+	if p.execCfg.StatementHintsCache == nil {
+		return "", errors.AssertionFailedf("statement hints are not available")
+	}
+	stmt, err := parser.ParseOne(fingerprint)
+	if err != nil {
+		return "", pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+			"invalid statement fingerprint %q", fingerprint)
+	}
+	return anonymizeStmt(stmt.AST), nil
+}
@@ -490,7 +490,7 @@ func (r *Refresher) refreshStats(ctx context.Context, tableID descpb.ID, asOf ti
 func mostRecentAutomaticStat(tableStats []*TableStatistic) *TableStatistic {
 	// Stats are sorted with the most recent first.
 	for _, stat := range tableStats {
-		if stat.Name == AutoStatsName {
+		if stat.Name == AutoStatsName && !stat.IsPartial() {
 			return stat
 		}
 	}
@@ -511,7 +511,7 @@ func avgRefreshTime(tableStats []*TableStatistic) time.Duration {
 	var sum time.Duration
 	var count int
 	for _, stat := range tableStats {
-		if stat.Name != AutoStatsName {
+		if stat.Name != AutoStatsName || stat.IsPartial() {
 			continue
 		}
 		if reference == nil {
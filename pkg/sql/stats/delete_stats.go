@@ -32,7 +32,7 @@ const (
 // DeleteOldStatsForColumns deletes old statistics from the
 // system.table_statistics table. For the given tableID and columnIDs,
 // DeleteOldStatsForColumns keeps the most recent keepCount automatic
-// statistics and deletes all the others.
+// statistics and deletes all the others, including partial statistics.
 func DeleteOldStatsForColumns(
 	ctx context.Context,
 	executor sqlutil.InternalExecutor,
@@ -40,17 +40,15 @@ func DeleteOldStatsForColumns(
 	tableID descpb.ID,
 	columnIDs []descpb.ColumnID,
 ) error {
-	columnIDsVal := tree.NewDArray(types.Int)
-	for _, c := range columnIDs {
-		if err := columnIDsVal.Append(tree.NewDInt(tree.DInt(int(c)))); err != nil {
-			return err
-		}
+	columnIDsVal, err := makeColumnIDsArray(columnIDs)
+	if err != nil {
+		return err
 	}
 
 	// This will delete all old statistics for the given table and columns,
 	// including stats created manually (except for a few automatic statistics,
 	// which are identified by the name AutoStatsName).
-	_, err := executor.Exec(
+	_, err = executor.Exec(
 		ctx, "delete-statistics", txn,
 		`DELETE FROM system.table_statistics
                WHERE "tableID" = $1
@@ -60,6 +58,7 @@ func DeleteOldStatsForColumns(
                    WHERE "tableID" = $1
                    AND "name" = $2
                    AND "columnIDs" = $3
+                   AND "partialPredicate" IS NULL
                    ORDER BY "createdAt" DESC
                    LIMIT $4
                )`,
@@ -70,3 +69,40 @@ func DeleteOldStatsForColumns(
 	)
 	return err
 }
+
+// DeletePartialStatsForColumns deletes all the partial statistics for the
+// given tableID and columnIDs from the system.table_statistics table. It is
+// used before inserting a new partial statistic, which supersedes them.
+func DeletePartialStatsForColumns(
+	ctx context.Context,
+	executor sqlutil.InternalExecutor,
+	txn *kv.Txn,
+	tableID descpb.ID,
+	columnIDs []descpb.ColumnID,
+) error {
+	columnIDsVal, err := makeColumnIDsArray(columnIDs)
+	if err != nil {
+		return err
+	}
+
+	_, err = executor.Exec(
+		ctx, "delete-partial-statistics", txn,
+		`DELETE FROM system.table_statistics
+               WHERE "tableID" = $1
+               AND "columnIDs" = $2
+               AND "partialPredicate" IS NOT NULL`,
+		tableID,
+		columnIDsVal,
+	)
+	return err
+}
+
+func makeColumnIDsArray(columnIDs []descpb.ColumnID) (*tree.DArray, error) {
+	columnIDsVal := tree.NewDArray(types.Int)
+	for _, c := range columnIDs {
+		if err := columnIDsVal.Append(tree.NewDInt(tree.DInt(int(c)))); err != nil {
+			return nil, err
+		}
+	}
+	return columnIDsVal, nil
+}
@@ -0,0 +1,598 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stats
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/errors"
+)
+
+// ForecastStatsName is the name of the statistics that are forecast from the
+// history of the full statistics on the same columns.
+const ForecastStatsName = "__forecast__"
+
+// UseStatisticsForecasts controls whether the optimizer uses forecast
+// statistics in preference to the most recently collected statistics.
+var UseStatisticsForecasts = settings.RegisterPublicBoolSetting(
+	"sql.stats.forecasts.enabled",
+	"when true, the optimizer uses table statistics forecast from the history "+
+		"of collected statistics",
+	true,
+)
+
+const (
+	// minObservationsForForecast is the minimum number of full statistics on a
+	// set of columns required to forecast statistics on those columns.
+	minObservationsForForecast = 3
+
+	// minGoodnessOfFit is the minimum R² (coefficient of determination) of the
+	// linear regression models used to forecast statistics. If the history of
+	// statistics does not fit a line at least this well, it is not forecast.
+	minGoodnessOfFit = 0.95
+
+	// maxForecastDistance is the maximum distance in time between the most
+	// recent statistic on a set of columns and the forecast.
+	maxForecastDistance = 7 * 24 * time.Hour
+)
+
+// forecastTableStatistics returns a forecast statistic for each set of columns
+// with enough history of full statistics that change linearly over time. The
+// forecast is made for the time at which the next statistic on the columns
+// would be expected to be collected, based on the average interval between
+// the existing statistics. The given statistics must be ordered by their
+// CreatedAt time (newest-to-oldest).
+//
+// Row counts, distinct counts and NULL counts are forecast using simple linear
+// regression. Histograms on numeric, date and timestamp columns are forecast by
+// treating each histogram as a quantile function and forecasting the value of
+// the function at each quantile, which allows histogram bounds to move. This
+// makes the forecast statistics of append-mostly tables cover the values that
+// have been inserted since the most recent statistic was collected.
+func forecastTableStatistics(ctx context.Context, stats []*TableStatistic) []*TableStatistic {
+	var keys []string
+	observations := make(map[string][]*TableStatistic)
+	for _, stat := range stats {
+		if stat.IsPartial() || stat.IsForecast() {
+			continue
+		}
+		key := makeColumnIDsKey(stat.ColumnIDs)
+		if _, ok := observations[key]; !ok {
+			keys = append(keys, key)
+		}
+		observations[key] = append(observations[key], stat)
+	}
+
+	var forecasts []*TableStatistic
+	for _, key := range keys {
+		obs := observations[key]
+		if len(obs) < minObservationsForForecast {
+			continue
+		}
+		forecast, err := forecastColumnStatistics(obs)
+		if err != nil {
+			log.VEventf(
+				ctx, 2, "unable to forecast statistics on columns %v of table %d: %v",
+				obs[0].ColumnIDs, obs[0].TableID, err,
+			)
+			continue
+		}
+		if forecast != nil {
+			forecasts = append(forecasts, forecast)
+		}
+	}
+	return forecasts
+}
+
+// forecastColumnStatistics forecasts a statistic from the given observations
+// of full statistics on the same set of columns, ordered newest-to-oldest. It
+// returns nil if the forecast would not differ from the most recent
+// observation.
+func forecastColumnStatistics(obs []*TableStatistic) (*TableStatistic, error) {
+	latest := obs[0]
+	interval := latest.CreatedAt.Sub(obs[len(obs)-1].CreatedAt) / time.Duration(len(obs)-1)
+	if interval <= 0 {
+		return nil, errors.New("statistics were collected at the same time")
+	}
+	if interval > maxForecastDistance {
+		interval = maxForecastDistance
+	}
+
+	// Times are measured in seconds relative to the most recent observation.
+	x := make([]float64, len(obs))
+	for i := range obs {
+		x[i] = obs[i].CreatedAt.Sub(latest.CreatedAt).Seconds()
+	}
+	fit := makeLinearFit(x, interval.Seconds())
+
+	observed := func(count func(*TableStatistic) uint64) []float64 {
+		y := make([]float64, len(obs))
+		for i := range obs {
+			y[i] = float64(count(obs[i]))
+		}
+		return y
+	}
+	rowCount, ssRes, ssTot := fit.predict(observed(func(s *TableStatistic) uint64 { return s.RowCount }))
+	if goodnessOfFit(ssRes, ssTot) < minGoodnessOfFit {
+		return nil, errors.New("row counts do not change linearly")
+	}
+	changed := ssTot != 0
+
+	// The distinct and NULL counts fall back to the most recent observation if
+	// they do not change linearly.
+	forecastCount := func(count func(*TableStatistic) uint64) float64 {
+		c, ssRes, ssTot := fit.predict(observed(count))
+		if goodnessOfFit(ssRes, ssTot) < minGoodnessOfFit {
+			return float64(count(latest))
+		}
+		changed = changed || ssTot != 0
+		return c
+	}
+	distinctCount := forecastCount(func(s *TableStatistic) uint64 { return s.DistinctCount })
+	nullCount := forecastCount(func(s *TableStatistic) uint64 { return s.NullCount })
+
+	rowCount = math.Max(math.Round(rowCount), 0)
+	nullCount = math.Min(math.Max(math.Round(nullCount), 0), rowCount)
+	nonNullRows := rowCount - nullCount
+	nonNullDistinct := math.Round(distinctCount)
+	if nullCount > 0 {
+		// The distinct count includes NULL as a value.
+		nonNullDistinct--
+	}
+	nonNullDistinct = math.Min(math.Max(nonNullDistinct, 0), nonNullRows)
+	if nonNullRows > 0 && nonNullDistinct < 1 {
+		nonNullDistinct = 1
+	}
+	distinctCount = nonNullDistinct
+	if nullCount > 0 {
+		distinctCount++
+	}
+
+	res := &TableStatistic{TableStatisticProto: latest.TableStatisticProto}
+	res.StatisticID = 0
+	res.Name = ForecastStatsName
+	res.CreatedAt = latest.CreatedAt.Add(interval)
+	res.RowCount = uint64(rowCount)
+	res.DistinctCount = uint64(distinctCount)
+	res.NullCount = uint64(nullCount)
+	res.HistogramData = nil
+	res.Histogram = nil
+
+	if latest.HistogramData != nil {
+		hist, histChanged, err := forecastHistogram(&fit, obs, nonNullRows, nonNullDistinct)
+		if err != nil {
+			return nil, err
+		}
+		changed = changed || histChanged
+		res.HistogramData = &hist
+		res.Histogram = make([]cat.HistogramBucket, 0, len(hist.Buckets)+1)
+		if nullCount > 0 {
+			// A bucket for just NULL is added at the beginning of the decoded
+			// histogram, as in parseStats.
+			res.Histogram = append(res.Histogram, cat.HistogramBucket{
+				NumEq:         float64(nullCount),
+				NumRange:      0,
+				DistinctRange: 0,
+				UpperBound:    tree.DNull,
+			})
+		}
+		var a rowenc.DatumAlloc
+		for i := range hist.Buckets {
+			datum, _, err := rowenc.DecodeTableKey(&a, hist.ColumnType, hist.Buckets[i].UpperBound, encoding.Ascending)
+			if err != nil {
+				return nil, err
+			}
+			res.Histogram = append(res.Histogram, cat.HistogramBucket{
+				NumEq:         float64(hist.Buckets[i].NumEq),
+				NumRange:      float64(hist.Buckets[i].NumRange),
+				DistinctRange: hist.Buckets[i].DistinctRange,
+				UpperBound:    datum,
+			})
+		}
+	}
+
+	if !changed {
+		return nil, nil
+	}
+	return res, nil
+}
+
+// forecastHistogram forecasts the histogram of the non-NULL values from the
+// histograms of the given observations. It also returns whether the histograms
+// of the observations differ at all.
+func forecastHistogram(
+	fit *linearFit, obs []*TableStatistic, nonNullRows, nonNullDistinct float64,
+) (_ HistogramData, changed bool, _ error) {
+	typ := obs[0].HistogramData.ColumnType
+	if !canForecastHistogram(typ) {
+		return HistogramData{}, false, errors.Newf("cannot forecast histograms on type %s", typ)
+	}
+	quantiles := make([]quantile, len(obs))
+	for i := range obs {
+		if obs[i].HistogramData == nil || !obs[i].HistogramData.ColumnType.Equivalent(typ) {
+			return HistogramData{}, false, errors.New("histograms are missing or of different types")
+		}
+		q, err := makeQuantile(obs[i].Histogram)
+		if err != nil {
+			return HistogramData{}, false, err
+		}
+		quantiles[i] = q
+	}
+
+	q, ssRes, ssTot := forecastQuantile(fit, quantiles)
+	if goodnessOfFit(ssRes, ssTot) < minGoodnessOfFit {
+		return HistogramData{}, false, errors.New("histograms do not change linearly")
+	}
+	hist, err := q.toHistogram(typ, nonNullRows, nonNullDistinct)
+	return hist, ssTot != 0, err
+}
+
+// canForecastHistogram returns whether histograms on columns of the given type
+// can be forecast, which requires the values to be convertible to and from
+// float64 while preserving their order.
+func canForecastHistogram(typ *types.T) bool {
+	switch typ.Family() {
+	case types.IntFamily, types.FloatFamily, types.DateFamily,
+		types.TimestampFamily, types.TimestampTZFamily:
+		return true
+	}
+	return false
+}
+
+// linearFit is a simple linear regression model of observations taken at
+// times x, used to predict the observed value at a given time.
+type linearFit struct {
+	x []float64
+	// meanX is the mean of x.
+	meanX float64
+	// sxx is the sum of the squared deviations of x from its mean.
+	sxx float64
+	// weights are the coefficients of the observed values in the prediction,
+	// which is a linear combination of the observed values.
+	weights []float64
+}
+
+// makeLinearFit returns a linearFit of observations taken at times x that
+// predicts the observed value at time at. The times must not all be equal.
+func makeLinearFit(x []float64, at float64) linearFit {
+	n := float64(len(x))
+	f := linearFit{x: x, weights: make([]float64, len(x))}
+	for i := range x {
+		f.meanX += x[i]
+	}
+	f.meanX /= n
+	for i := range x {
+		f.sxx += (x[i] - f.meanX) * (x[i] - f.meanX)
+	}
+	for i := range x {
+		f.weights[i] = 1/n + (x[i]-f.meanX)*(at-f.meanX)/f.sxx
+	}
+	return f
+}
+
+// predict returns the predicted value given the observed values y, along with
+// the residual and total sums of squares of the fit. If all of the observed
+// values are equal, the prediction is exactly that value.
+func (f *linearFit) predict(y []float64) (prediction, ssRes, ssTot float64) {
+	var meanY float64
+	for i := range y {
+		meanY += y[i]
+	}
+	meanY /= float64(len(y))
+	var sxy float64
+	for i := range y {
+		sxy += (f.x[i] - f.meanX) * (y[i] - meanY)
+		ssTot += (y[i] - meanY) * (y[i] - meanY)
+	}
+	if ssTot == 0 {
+		return y[0], 0, 0
+	}
+	slope := sxy / f.sxx
+	for i := range y {
+		fitted := meanY + slope*(f.x[i]-f.meanX)
+		ssRes += (y[i] - fitted) * (y[i] - fitted)
+	}
+	for i := range y {
+		prediction += f.weights[i] * y[i]
+	}
+	return prediction, ssRes, ssTot
+}
+
+// goodnessOfFit returns the coefficient of determination (R²) of a fit with the
+// given residual and total sums of squares.
+func goodnessOfFit(ssRes, ssTot float64) float64 {
+	if ssTot == 0 {
+		return 1
+	}
+	return 1 - ssRes/ssTot
+}
+
+// quantile is a piecewise linear function mapping a fraction p of the non-NULL
+// rows of a column, ordered by value, to a column value v. It is represented by
+// a list of points ordered by p and then v, starting at p = 0 and ending at
+// p = 1. Consecutive points with the same p are a jump between values with no
+// rows between them, and consecutive points with the same v are a single value
+// shared by a fraction of the rows.
+type quantile []quantilePoint
+
+type quantilePoint struct {
+	p, v float64
+}
+
+// makeQuantile converts a decoded histogram to a quantile function.
+func makeQuantile(hist []cat.HistogramBucket) (quantile, error) {
+	if len(hist) > 0 && hist[0].UpperBound == tree.DNull {
+		hist = hist[1:]
+	}
+	var total float64
+	for i := range hist {
+		total += hist[i].NumRange + hist[i].NumEq
+	}
+	if total <= 0 {
+		return nil, errors.New("histogram has no rows")
+	}
+	if hist[0].NumRange != 0 {
+		return nil, errors.New("histogram has no lower bound")
+	}
+	q := make(quantile, 0, 2*len(hist))
+	var cumulative float64
+	for i := range hist {
+		v, err := histogramValueToFloat(hist[i].UpperBound)
+		if err != nil {
+			return nil, err
+		}
+		if len(q) > 0 && v <= q[len(q)-1].v {
+			return nil, errors.New("histogram bounds are not increasing")
+		}
+		cumulative += hist[i].NumRange
+		q = append(q, quantilePoint{p: cumulative / total, v: v})
+		if hist[i].NumEq > 0 {
+			cumulative += hist[i].NumEq
+			q = append(q, quantilePoint{p: cumulative / total, v: v})
+		}
+	}
+	q[len(q)-1].p = 1
+	return q, nil
+}
+
+// valuesAt returns the smallest and largest values of the quantile function at
+// p, which differ if the function jumps at p.
+func (q quantile) valuesAt(p float64) (lo, hi float64) {
+	i := sort.Search(len(q), func(i int) bool { return q[i].p >= p })
+	if i == len(q) {
+		return q[i-1].v, q[i-1].v
+	}
+	if q[i].p == p || i == 0 {
+		j := i
+		for j+1 < len(q) && q[j+1].p == q[i].p {
+			j++
+		}
+		return q[i].v, q[j].v
+	}
+	a, b := q[i-1], q[i]
+	v := a.v + (b.v-a.v)*(p-a.p)/(b.p-a.p)
+	return v, v
+}
+
+// forecastQuantile forecasts a quantile function from the given observed
+// quantile functions by predicting the value at each of their breakpoints. It
+// also returns the residual and total sums of squares of all of the fits.
+func forecastQuantile(fit *linearFit, quantiles []quantile) (_ quantile, ssRes, ssTot float64) {
+	var ps []float64
+	for _, q := range quantiles {
+		for i := range q {
+			ps = append(ps, q[i].p)
+		}
+	}
+	sort.Float64s(ps)
+
+	res := make(quantile, 0, 2*len(ps))
+	lo := make([]float64, len(quantiles))
+	hi := make([]float64, len(quantiles))
+	for i, p := range ps {
+		if i > 0 && p == ps[i-1] {
+			continue
+		}
+		jump := false
+		for j, q := range quantiles {
+			lo[j], hi[j] = q.valuesAt(p)
+			jump = jump || lo[j] != hi[j]
+		}
+		v, r, t := fit.predict(lo)
+		res = append(res, quantilePoint{p: p, v: v})
+		ssRes, ssTot = ssRes+r, ssTot+t
+		if jump {
+			v, r, t = fit.predict(hi)
+			res = append(res, quantilePoint{p: p, v: v})
+			ssRes, ssTot = ssRes+r, ssTot+t
+		}
+	}
+
+	// The predicted values are not necessarily increasing, for example if a
+	// value is becoming less frequent. Since the quantile function must be
+	// non-decreasing, treat any decreasing values as equal to the previous one.
+	for i := 1; i < len(res); i++ {
+		if res[i].v < res[i-1].v {
+			res[i].v = res[i-1].v
+		}
+	}
+	return res, ssRes, ssTot
+}
+
+// toHistogram converts the quantile function to a histogram of the given type
+// with numRows non-NULL rows and distinctCount distinct non-NULL values.
+func (q quantile) toHistogram(
+	typ *types.T, numRows, distinctCount float64,
+) (HistogramData, error) {
+	hist := HistogramData{ColumnType: typ}
+	if numRows <= 0 {
+		return hist, nil
+	}
+
+	// Round the values to values of the type, which may turn ranges of
+	// values into single values.
+	for i := range q {
+		q[i].v = roundHistogramValue(q[i].v, typ)
+	}
+
+	type bucket struct {
+		numEq, numRange, upperBound float64
+	}
+	buckets := []bucket{{upperBound: q[0].v}}
+	for i := 1; i < len(q); i++ {
+		rows := (q[i].p - q[i-1].p) * numRows
+		if q[i].v == q[i-1].v {
+			buckets[len(buckets)-1].numEq += rows
+		} else {
+			buckets = append(buckets, bucket{numRange: rows, upperBound: q[i].v})
+		}
+	}
+
+	// Distribute the distinct values that are not upper bounds of buckets
+	// among the bucket ranges, in proportion with the number of rows in each
+	// range.
+	var totalRange, numEqValues float64
+	for i := range buckets {
+		buckets[i].numEq = math.Round(buckets[i].numEq)
+		buckets[i].numRange = math.Round(buckets[i].numRange)
+		totalRange += buckets[i].numRange
+		if buckets[i].numEq > 0 {
+			numEqValues++
+		}
+	}
+	distinctRange := math.Max(distinctCount-numEqValues, 0)
+
+	hist.Buckets = make([]HistogramData_Bucket, len(buckets))
+	for i := range buckets {
+		b := &hist.Buckets[i]
+		b.NumEq = int64(buckets[i].numEq)
+		b.NumRange = int64(buckets[i].numRange)
+		if totalRange > 0 {
+			b.DistinctRange = distinctRange * buckets[i].numRange / totalRange
+			b.DistinctRange = math.Min(b.DistinctRange, buckets[i].numRange)
+			if i > 0 && isDiscreteHistogramType(typ) {
+				b.DistinctRange = math.Min(
+					b.DistinctRange, buckets[i].upperBound-buckets[i-1].upperBound-1,
+				)
+			}
+		}
+		datum, err := histogramValueFromFloat(buckets[i].upperBound, typ)
+		if err != nil {
+			return HistogramData{}, err
+		}
+		if b.UpperBound, err = rowenc.EncodeTableKey(nil, datum, encoding.Ascending); err != nil {
+			return HistogramData{}, err
+		}
+	}
+	return hist, nil
+}
+
+// isDiscreteHistogramType returns whether histogram values of the given type
+// are integers.
+func isDiscreteHistogramType(typ *types.T) bool {
+	switch typ.Family() {
+	case types.IntFamily, types.DateFamily:
+		return true
+	}
+	return false
+}
+
+// roundHistogramValue rounds the float64 representation of a histogram value
+// to the nearest value of the given type.
+func roundHistogramValue(v float64, typ *types.T) float64 {
+	switch typ.Family() {
+	case types.IntFamily:
+		switch typ.Width() {
+		case 16:
+			return math.Min(math.Max(math.Round(v), math.MinInt16), math.MaxInt16)
+		case 32:
+			return math.Min(math.Max(math.Round(v), math.MinInt32), math.MaxInt32)
+		}
+		return math.Min(math.Max(math.Round(v), math.MinInt64), math.MaxInt64)
+	case types.DateFamily:
+		return math.Min(math.Max(math.Round(v), math.MinInt32), math.MaxInt32)
+	case types.TimestampFamily, types.TimestampTZFamily:
+		round := float64(tree.TimeFamilyPrecisionToRoundDuration(typ.Precision()) / time.Microsecond)
+		return math.Round(v/round) * round
+	}
+	return v
+}
+
+// histogramValueToFloat converts a histogram value to a float64 that preserves
+// its order relative to the other values of its type. Dates are converted to
+// days and timestamps to microseconds.
+func histogramValueToFloat(d tree.Datum) (float64, error) {
+	switch t := d.(type) {
+	case *tree.DInt:
+		return float64(*t), nil
+	case *tree.DFloat:
+		f := float64(*t)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, errors.Newf("cannot forecast histogram value %s", d)
+		}
+		return f, nil
+	case *tree.DDate:
+		if !t.IsFinite() {
+			return 0, errors.Newf("cannot forecast histogram value %s", d)
+		}
+		return float64(t.PGEpochDays()), nil
+	case *tree.DTimestamp:
+		return timeToMicros(t.Time), nil
+	case *tree.DTimestampTZ:
+		return timeToMicros(t.Time), nil
+	}
+	return 0, errors.Newf("cannot forecast histogram value %s", d)
+}
+
+// histogramValueFromFloat is the inverse of histogramValueToFloat.
+func histogramValueFromFloat(v float64, typ *types.T) (tree.Datum, error) {
+	switch typ.Family() {
+	case types.IntFamily:
+		if v >= math.MaxInt64 {
+			return tree.NewDInt(math.MaxInt64), nil
+		}
+		return tree.NewDInt(tree.DInt(v)), nil
+	case types.FloatFamily:
+		return tree.NewDFloat(tree.DFloat(v)), nil
+	case types.DateFamily:
+		d, err := pgdate.MakeDateFromPGEpoch(int32(v))
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDDate(d), nil
+	case types.TimestampFamily:
+		return tree.MakeDTimestamp(microsToTime(v), time.Microsecond)
+	case types.TimestampTZFamily:
+		return tree.MakeDTimestampTZ(microsToTime(v), time.Microsecond)
+	}
+	return nil, errors.AssertionFailedf("cannot forecast histogram values of type %s", typ)
+}
+
+func timeToMicros(t time.Time) float64 {
+	return float64(t.Unix())*1e6 + float64(t.Nanosecond()/1000)
+}
+
+func microsToTime(v float64) time.Time {
+	secs := math.Floor(v / 1e6)
+	return timeutil.Unix(int64(secs), int64(v-secs*1e6)*1000)
+}
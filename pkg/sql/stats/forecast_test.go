@@ -0,0 +1,242 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stats
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+type testBucket struct {
+	upper         int64
+	numEq         int64
+	numRange      int64
+	distinctRange float64
+}
+
+// makeTestStat returns a statistic on the INT column with ID 1, with a
+// histogram built from the given buckets (if any).
+func makeTestStat(
+	t *testing.T,
+	createdAt time.Time,
+	rowCount, distinctCount, nullCount uint64,
+	buckets []testBucket,
+	partialPredicate string,
+) *TableStatistic {
+	stat := &TableStatistic{TableStatisticProto: TableStatisticProto{
+		TableID:          100,
+		StatisticID:      uint64(createdAt.Unix()),
+		Name:             AutoStatsName,
+		ColumnIDs:        []descpb.ColumnID{1},
+		CreatedAt:        createdAt,
+		RowCount:         rowCount,
+		DistinctCount:    distinctCount,
+		NullCount:        nullCount,
+		PartialPredicate: partialPredicate,
+	}}
+	if buckets == nil {
+		return stat
+	}
+	stat.HistogramData = &HistogramData{ColumnType: types.Int}
+	if nullCount > 0 {
+		stat.Histogram = append(stat.Histogram, cat.HistogramBucket{
+			NumEq: float64(nullCount), UpperBound: tree.DNull,
+		})
+	}
+	for _, b := range buckets {
+		datum := tree.NewDInt(tree.DInt(b.upper))
+		key, err := rowenc.EncodeTableKey(nil, datum, encoding.Ascending)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stat.HistogramData.Buckets = append(stat.HistogramData.Buckets, HistogramData_Bucket{
+			NumEq: b.numEq, NumRange: b.numRange, DistinctRange: b.distinctRange, UpperBound: key,
+		})
+		stat.Histogram = append(stat.Histogram, cat.HistogramBucket{
+			NumEq:         float64(b.numEq),
+			NumRange:      float64(b.numRange),
+			DistinctRange: b.distinctRange,
+			UpperBound:    datum,
+		})
+	}
+	return stat
+}
+
+func TestLinearFit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	fit := makeLinearFit([]float64{0, -1, -2, -3}, 1)
+	if pred, ssRes, ssTot := fit.predict([]float64{40, 30, 20, 10}); pred != 50 || ssRes != 0 || ssTot != 500 {
+		t.Errorf("expected perfect fit predicting 50, got %f (ssRes %f, ssTot %f)", pred, ssRes, ssTot)
+	}
+	if pred, _, ssTot := fit.predict([]float64{7, 7, 7, 7}); pred != 7 || ssTot != 0 {
+		t.Errorf("expected constant fit predicting 7, got %f (ssTot %f)", pred, ssTot)
+	}
+	pred, ssRes, ssTot := fit.predict([]float64{10, 40, 10, 40})
+	if r2 := goodnessOfFit(ssRes, ssTot); r2 >= minGoodnessOfFit {
+		t.Errorf("expected poor fit, got R² %f predicting %f", r2, pred)
+	}
+}
+
+func TestForecastTableStatistics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	day := 24 * time.Hour
+	now := timeutil.Unix(1600000000, 0)
+
+	// sequence returns a statistic on a column containing the values 1 to n.
+	sequence := func(createdAt time.Time, n int64) *TableStatistic {
+		return makeTestStat(t, createdAt, uint64(n), uint64(n), 0 /* nullCount */, []testBucket{
+			{upper: 1, numEq: 1},
+			{upper: n, numEq: 1, numRange: n - 2, distinctRange: float64(n - 2)},
+		}, "" /* partialPredicate */)
+	}
+
+	t.Run("too-few-observations", func(t *testing.T) {
+		stats := []*TableStatistic{sequence(now, 200), sequence(now.Add(-day), 100)}
+		if forecasts := forecastTableStatistics(ctx, stats); len(forecasts) != 0 {
+			t.Errorf("expected no forecasts, got %d", len(forecasts))
+		}
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		stats := []*TableStatistic{
+			sequence(now, 100), sequence(now.Add(-day), 100), sequence(now.Add(-2*day), 100),
+		}
+		if forecasts := forecastTableStatistics(ctx, stats); len(forecasts) != 0 {
+			t.Errorf("expected no forecasts, got %d", len(forecasts))
+		}
+	})
+
+	t.Run("nonlinear", func(t *testing.T) {
+		stats := []*TableStatistic{
+			sequence(now, 100), sequence(now.Add(-day), 1000), sequence(now.Add(-2*day), 100),
+		}
+		if forecasts := forecastTableStatistics(ctx, stats); len(forecasts) != 0 {
+			t.Errorf("expected no forecasts, got %d", len(forecasts))
+		}
+	})
+
+	t.Run("sequence", func(t *testing.T) {
+		stats := []*TableStatistic{
+			sequence(now, 300), sequence(now.Add(-day), 200), sequence(now.Add(-2*day), 100),
+		}
+		forecasts := forecastTableStatistics(ctx, stats)
+		if len(forecasts) != 1 {
+			t.Fatalf("expected one forecast, got %d", len(forecasts))
+		}
+		f := forecasts[0]
+		if f.Name != ForecastStatsName || !f.IsForecast() {
+			t.Errorf("expected forecast, got name %q", f.Name)
+		}
+		if !f.CreatedAt.Equal(now.Add(day)) {
+			t.Errorf("expected forecast at %s, got %s", now.Add(day), f.CreatedAt)
+		}
+		if f.RowCount != 400 || f.DistinctCount != 400 || f.NullCount != 0 {
+			t.Errorf(
+				"expected 400 rows and distinct values, got %d rows, %d distinct values and %d nulls",
+				f.RowCount, f.DistinctCount, f.NullCount,
+			)
+		}
+		if len(f.Histogram) != len(f.HistogramData.Buckets) || len(f.Histogram) < 2 {
+			t.Fatalf("unexpected histogram %v", f.Histogram)
+		}
+		first, last := f.Histogram[0].UpperBound, f.Histogram[len(f.Histogram)-1].UpperBound
+		if *first.(*tree.DInt) != 1 || *last.(*tree.DInt) != 400 {
+			t.Errorf("expected histogram from 1 to 400, got %s to %s", first, last)
+		}
+		var rows, distinct float64
+		for i, b := range f.Histogram {
+			rows += b.NumEq + b.NumRange
+			if b.NumEq > 0 {
+				distinct++
+			}
+			distinct += b.DistinctRange
+			if i > 0 && b.UpperBound.Compare(nil /* ctx */, f.Histogram[i-1].UpperBound) <= 0 {
+				t.Errorf("histogram bounds are not increasing: %v", f.Histogram)
+			}
+		}
+		if math.Abs(rows-400) > 4 || math.Abs(distinct-400) > 4 {
+			t.Errorf("expected about 400 rows and distinct values in histogram, got %f and %f", rows, distinct)
+		}
+	})
+
+	t.Run("max-distance", func(t *testing.T) {
+		month := 30 * day
+		stats := []*TableStatistic{
+			sequence(now, 300), sequence(now.Add(-month), 200), sequence(now.Add(-2*month), 100),
+		}
+		forecasts := forecastTableStatistics(ctx, stats)
+		if len(forecasts) != 1 {
+			t.Fatalf("expected one forecast, got %d", len(forecasts))
+		}
+		if exp := now.Add(maxForecastDistance); !forecasts[0].CreatedAt.Equal(exp) {
+			t.Errorf("expected forecast at %s, got %s", exp, forecasts[0].CreatedAt)
+		}
+	})
+
+	t.Run("partial-ignored", func(t *testing.T) {
+		stats := []*TableStatistic{
+			makeTestStat(t, now.Add(day), 100, 100, 0, []testBucket{{upper: 400, numEq: 1}}, "(a > 300)"),
+			sequence(now, 300), sequence(now.Add(-day), 200),
+		}
+		if forecasts := forecastTableStatistics(ctx, stats); len(forecasts) != 0 {
+			t.Errorf("expected no forecasts, got %d", len(forecasts))
+		}
+	})
+}
+
+func TestHistogramValueConversion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ts := timeutil.Unix(1600000000, 123456000)
+	date, err := tree.NewDDateFromTime(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []tree.Datum{
+		tree.NewDInt(-5),
+		tree.NewDFloat(1.5),
+		date,
+		tree.MustMakeDTimestamp(ts, time.Microsecond),
+		tree.MustMakeDTimestampTZ(ts, time.Microsecond),
+	} {
+		v, err := histogramValueToFloat(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := histogramValueFromFloat(roundHistogramValue(v, d.ResolvedType()), d.ResolvedType())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.String() != d.String() {
+			t.Errorf("expected %s, got %s", d, res)
+		}
+	}
+
+	if _, err := histogramValueToFloat(tree.NewDString("a")); err == nil {
+		t.Errorf("expected error converting string")
+	}
+}
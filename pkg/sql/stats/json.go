@@ -36,6 +36,9 @@ type JSONStatistic struct {
 	// tree.GetTypeFromValidSQLSyntax.
 	HistogramColumnType string            `json:"histo_col_type"`
 	HistogramBuckets    []JSONHistoBucket `json:"histo_buckets,omitempty"`
+	// PartialPredicate is the predicate satisfied by the rows of a partial
+	// statistic (or unset if the statistic is not partial).
+	PartialPredicate string `json:"partial_predicate,omitempty"`
 }
 
 // JSONHistoBucket is a struct used for JSON marshaling and unmarshaling of
@@ -0,0 +1,142 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stats
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// MergedStatsName is the name of the statistics that are created by merging a
+// partial statistic into the full statistic on the same column.
+const MergedStatsName = "__merged__"
+
+// mergePartialStatistics returns a merged statistic for each column with a
+// partial statistic that was collected after the most recent full statistic
+// with a histogram on the same column. Only the most recent partial statistic
+// on each column is merged, and only if no full statistic was collected on
+// the column since. The given statistics must be ordered by their
+// CreatedAt time (newest-to-oldest).
+//
+// Partial statistics are created by CREATE STATISTICS ... USING EXTREMES,
+// which only scans the values below the lowest bound and above the highest
+// bound of the histogram of the full statistic. The merged statistic is
+// created at the same time as the partial statistic, and its histogram is the
+// histogram of the full statistic extended with the buckets of the partial
+// statistic on either side.
+func mergePartialStatistics(ctx context.Context, stats []*TableStatistic) []*TableStatistic {
+	var merged []*TableStatistic
+	seen := make(map[string]struct{})
+	for i, partial := range stats {
+		// Only the most recent statistic on each set of columns is considered;
+		// if it is a full statistic, there is nothing to merge.
+		key := makeColumnIDsKey(partial.ColumnIDs)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if !partial.IsPartial() || len(partial.ColumnIDs) != 1 {
+			continue
+		}
+
+		for _, full := range stats[i+1:] {
+			if full.IsPartial() || !areEqual(full.ColumnIDs, partial.ColumnIDs) {
+				continue
+			}
+			// The most recent full statistic is the only one that the partial
+			// statistic can be merged into, since its bounds were used to collect
+			// the partial statistic.
+			if m := mergePartialStatistic(full, partial); m != nil {
+				merged = append(merged, m)
+			} else {
+				log.VEventf(
+					ctx, 2, "unable to merge partial statistic %d into statistic %d of table %d",
+					partial.StatisticID, full.StatisticID, full.TableID,
+				)
+			}
+			break
+		}
+	}
+	return merged
+}
+
+// mergePartialStatistic merges the partial statistic into the full statistic,
+// or returns nil if their histograms cannot be merged.
+func mergePartialStatistic(full, partial *TableStatistic) *TableStatistic {
+	fullData, partialData := full.HistogramData, partial.HistogramData
+	if fullData == nil || partialData == nil || len(fullData.Buckets) == 0 ||
+		!fullData.ColumnType.Equivalent(partialData.ColumnType) {
+		return nil
+	}
+	// The histogram data never contains the NULL bucket, but the decoded
+	// histograms do if the statistic has NULL values.
+	fullOffset := len(full.Histogram) - len(fullData.Buckets)
+	partialOffset := len(partial.Histogram) - len(partialData.Buckets)
+	lowest := fullData.Buckets[0].UpperBound
+	highest := fullData.Buckets[len(fullData.Buckets)-1].UpperBound
+
+	// Split the partial buckets into the ones below the lowest bound and the
+	// ones above the highest bound of the full histogram. The partial statistic
+	// should not contain any other buckets; if it does, the full statistic is
+	// not the one it was collected against.
+	lower := 0
+	for lower < len(partialData.Buckets) &&
+		bytes.Compare(partialData.Buckets[lower].UpperBound, lowest) < 0 {
+		lower++
+	}
+	upper := lower
+	for upper < len(partialData.Buckets) &&
+		bytes.Compare(partialData.Buckets[upper].UpperBound, highest) <= 0 {
+		upper++
+	}
+	if upper != lower {
+		return nil
+	}
+
+	res := &TableStatistic{TableStatisticProto: full.TableStatisticProto}
+	res.StatisticID = 0
+	res.Name = MergedStatsName
+	res.CreatedAt = partial.CreatedAt
+	res.RowCount = full.RowCount + partial.RowCount
+	res.DistinctCount = full.DistinctCount + partial.DistinctCount
+	res.HistogramData = &HistogramData{
+		ColumnType: fullData.ColumnType,
+		Buckets:    make([]HistogramData_Bucket, 0, len(fullData.Buckets)+len(partialData.Buckets)),
+	}
+	res.HistogramData.Buckets = append(res.HistogramData.Buckets, partialData.Buckets[:lower]...)
+	res.HistogramData.Buckets = append(res.HistogramData.Buckets, fullData.Buckets...)
+	res.HistogramData.Buckets = append(res.HistogramData.Buckets, partialData.Buckets[upper:]...)
+
+	res.Histogram = make([]cat.HistogramBucket, 0, len(full.Histogram)+len(partialData.Buckets))
+	res.Histogram = append(res.Histogram, full.Histogram[:fullOffset]...)
+	res.Histogram = append(res.Histogram, partial.Histogram[partialOffset:partialOffset+lower]...)
+	res.Histogram = append(res.Histogram, full.Histogram[fullOffset:]...)
+	res.Histogram = append(res.Histogram, partial.Histogram[partialOffset+upper:]...)
+	return res
+}
+
+// makeColumnIDsKey returns a string that uniquely identifies the given list
+// of column IDs.
+func makeColumnIDsKey(columnIDs []descpb.ColumnID) string {
+	var buf bytes.Buffer
+	for i, c := range columnIDs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(strconv.Itoa(int(c)))
+	}
+	return buf.String()
+}
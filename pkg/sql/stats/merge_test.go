@@ -0,0 +1,87 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+func TestMergePartialStatistics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	now := timeutil.Unix(1600000000, 0)
+	full := makeTestStat(t, now.Add(-time.Hour), 21, 13, 1 /* nullCount */, []testBucket{
+		{upper: 10, numEq: 1},
+		{upper: 20, numEq: 1, numRange: 18, distinctRange: 9},
+	}, "" /* partialPredicate */)
+
+	t.Run("merge", func(t *testing.T) {
+		partial := makeTestStat(t, now, 5, 3, 0 /* nullCount */, []testBucket{
+			{upper: 5, numEq: 1},
+			{upper: 25, numEq: 2},
+			{upper: 30, numEq: 1, numRange: 1, distinctRange: 1},
+		}, "(a < 10:::INT8) OR (a > 20:::INT8)")
+		merged := mergePartialStatistics(ctx, []*TableStatistic{partial, full})
+		if len(merged) != 1 {
+			t.Fatalf("expected one merged statistic, got %d", len(merged))
+		}
+		m := merged[0]
+		if !m.IsMerged() || m.IsPartial() || !m.CreatedAt.Equal(now) {
+			t.Errorf("unexpected merged statistic %+v", m.TableStatisticProto)
+		}
+		if m.RowCount != 26 || m.DistinctCount != 16 || m.NullCount != 1 {
+			t.Errorf(
+				"expected 26 rows, 16 distinct values and 1 null, got %d, %d and %d",
+				m.RowCount, m.DistinctCount, m.NullCount,
+			)
+		}
+		expBounds := []string{"NULL", "5", "10", "20", "25", "30"}
+		if len(m.Histogram) != len(expBounds) || len(m.HistogramData.Buckets) != len(expBounds)-1 {
+			t.Fatalf("unexpected histogram %v", m.Histogram)
+		}
+		for i, b := range m.Histogram {
+			if b.UpperBound.String() != expBounds[i] {
+				t.Errorf("expected bucket %d to have upper bound %s, got %s", i, expBounds[i], b.UpperBound)
+			}
+		}
+		if m.Histogram[0].UpperBound != tree.DNull || m.Histogram[0].NumEq != 1 {
+			t.Errorf("expected NULL bucket first, got %v", m.Histogram[0])
+		}
+	})
+
+	t.Run("overlapping", func(t *testing.T) {
+		partial := makeTestStat(t, now, 2, 2, 0 /* nullCount */, []testBucket{
+			{upper: 15, numEq: 1},
+			{upper: 25, numEq: 1},
+		}, "(a < 10:::INT8) OR (a > 20:::INT8)")
+		if merged := mergePartialStatistics(ctx, []*TableStatistic{partial, full}); len(merged) != 0 {
+			t.Errorf("expected no merged statistics, got %d", len(merged))
+		}
+	})
+
+	t.Run("full-after-partial", func(t *testing.T) {
+		partial := makeTestStat(t, now.Add(-2*time.Hour), 1, 1, 0 /* nullCount */, []testBucket{
+			{upper: 25, numEq: 1},
+		}, "(a < 10:::INT8) OR (a > 20:::INT8)")
+		if merged := mergePartialStatistics(ctx, []*TableStatistic{full, partial}); len(merged) != 0 {
+			t.Errorf("expected no merged statistics, got %d", len(merged))
+		}
+	})
+}
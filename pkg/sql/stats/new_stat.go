@@ -43,6 +43,7 @@ func InsertNewStats(
 			int64(statistic.DistinctCount),
 			int64(statistic.NullCount),
 			statistic.HistogramData,
+			statistic.PartialPredicate,
 		)
 		if err != nil {
 			return err
@@ -51,7 +52,9 @@ func InsertNewStats(
 	return nil
 }
 
-// InsertNewStat inserts a new statistic in the system table.
+// InsertNewStat inserts a new statistic in the system table. If
+// partialPredicate is not empty, the statistic is a partial statistic which
+// only covers the rows that satisfy the predicate.
 // The caller is responsible for calling GossipTableStatAdded to notify the stat
 // caches.
 func InsertNewStat(
@@ -63,12 +66,16 @@ func InsertNewStat(
 	columnIDs []descpb.ColumnID,
 	rowCount, distinctCount, nullCount int64,
 	h *HistogramData,
+	partialPredicate string,
 ) error {
 	// We must pass a nil interface{} if we want to insert a NULL.
-	var nameVal, histogramVal interface{}
+	var nameVal, histogramVal, partialPredicateVal interface{}
 	if name != "" {
 		nameVal = name
 	}
+	if partialPredicate != "" {
+		partialPredicateVal = partialPredicate
+	}
 	if h != nil {
 		var err error
 		histogramVal, err = protoutil.Marshal(h)
@@ -93,8 +100,9 @@ func InsertNewStat(
 					"rowCount",
 					"distinctCount",
 					"nullCount",
-					histogram
-				) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+					histogram,
+					"partialPredicate"
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
 		tableID,
 		nameVal,
 		columnIDsVal,
@@ -102,6 +110,7 @@ func InsertNewStat(
 		distinctCount,
 		nullCount,
 		histogramVal,
+		partialPredicateVal,
 	)
 	return err
 }
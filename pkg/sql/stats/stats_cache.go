@@ -12,6 +12,7 @@ package stats
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/cockroachdb/cockroach/pkg/gossip"
@@ -45,6 +46,24 @@ type TableStatistic struct {
 	Histogram []cat.HistogramBucket
 }
 
+// IsPartial returns true if the statistic only covers the rows that satisfy
+// its partial predicate (see CREATE STATISTICS ... USING EXTREMES).
+func (tabStat *TableStatistic) IsPartial() bool {
+	return tabStat.PartialPredicate != ""
+}
+
+// IsMerged returns true if the statistic was created by merging a partial
+// statistic into a full statistic (see mergePartialStatistics).
+func (tabStat *TableStatistic) IsMerged() bool {
+	return tabStat.Name == MergedStatsName
+}
+
+// IsForecast returns true if the statistic was forecast from the history of
+// statistics on its columns (see forecastTableStatistics).
+func (tabStat *TableStatistic) IsForecast() bool {
+	return tabStat.Name == ForecastStatsName
+}
+
 // A TableStatisticsCache contains two underlying LRU caches:
 // (1) A cache of []*TableStatistic objects, keyed by table ID.
 //     Each entry consists of all the statistics for different columns and
@@ -146,6 +165,12 @@ func (sc *TableStatisticsCache) tableStatAddedGossipUpdate(key string, value roa
 // and if the stats are not present in the cache, it looks them up in
 // system.table_statistics.
 //
+// In addition to the statistics stored in system.table_statistics, the result
+// contains the statistics obtained by merging partial statistics into full
+// statistics, and the statistics forecast from the history of the full
+// statistics on each set of columns. These can be told apart using IsMerged
+// and IsForecast.
+//
 // The statistics are ordered by their CreatedAt time (newest-to-oldest).
 func (sc *TableStatisticsCache) GetTableStats(
 	ctx context.Context, tableID descpb.ID,
@@ -345,6 +370,7 @@ const (
 	distinctCountIndex
 	nullCountIndex
 	histogramIndex
+	partialPredicateIndex
 	statsLen
 )
 
@@ -378,6 +404,7 @@ func (sc *TableStatisticsCache) parseStats(
 		{"distinctCount", distinctCountIndex, types.Int, false},
 		{"nullCount", nullCountIndex, types.Int, false},
 		{"histogram", histogramIndex, types.Bytes, true},
+		{"partialPredicate", partialPredicateIndex, types.String, true},
 	}
 	for _, v := range expectedTypes {
 		if !datums[v.fieldIndex].ResolvedType().Equivalent(v.expectedType) &&
@@ -406,6 +433,9 @@ func (sc *TableStatisticsCache) parseStats(
 	if datums[nameIndex] != tree.DNull {
 		res.Name = string(*datums[nameIndex].(*tree.DString))
 	}
+	if datums[partialPredicateIndex] != tree.DNull {
+		res.PartialPredicate = string(*datums[partialPredicateIndex].(*tree.DString))
+	}
 	if datums[histogramIndex] != tree.DNull {
 		res.HistogramData = &HistogramData{}
 		if err := protoutil.Unmarshal(
@@ -480,7 +510,8 @@ func (sc *TableStatisticsCache) parseStats(
 }
 
 // getTableStatsFromDB retrieves the statistics in system.table_statistics
-// for the given table ID.
+// for the given table ID, and adds the merged and forecast statistics derived
+// from them.
 func (sc *TableStatisticsCache) getTableStatsFromDB(
 	ctx context.Context, tableID descpb.ID,
 ) ([]*TableStatistic, error) {
@@ -494,7 +525,8 @@ SELECT
 	"rowCount",
 	"distinctCount",
 	"nullCount",
-	histogram
+	histogram,
+	"partialPredicate"
 FROM system.table_statistics
 WHERE "tableID" = $1
 ORDER BY "createdAt" DESC
@@ -515,5 +547,15 @@ ORDER BY "createdAt" DESC
 		statsList = append(statsList, stats)
 	}
 
+	merged := mergePartialStatistics(ctx, statsList)
+	statsList = append(statsList, merged...)
+	forecasts := forecastTableStatistics(ctx, statsList)
+	statsList = append(statsList, forecasts...)
+	if len(merged) > 0 || len(forecasts) > 0 {
+		sort.SliceStable(statsList, func(i, j int) bool {
+			return statsList[i].CreatedAt.After(statsList[j].CreatedAt)
+		})
+	}
+
 	return statsList, nil
 }
@@ -0,0 +1,297 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stmthints
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+var pollingInterval = settings.RegisterDurationSetting(
+	"sql.statement_hints.poll_interval",
+	"rate at which each node reloads system.statement_hints, set to zero to disable",
+	10*time.Second)
+
+// Cache maintains a per-node view of system.statement_hints. Lookups are
+// served from memory so that they can be performed during the planning of
+// every statement; the view is refreshed periodically, and immediately on
+// the node where hints are changed.
+type Cache struct {
+	mu struct {
+		// NOTE: This lock can't be held while the cache runs any statements
+		// internally; it'd deadlock.
+		syncutil.RWMutex
+		// rows holds the contents of system.statement_hints, by fingerprint.
+		rows map[string][]Hint
+		// hints holds the Hints built from rows, by fingerprint.
+		hints map[string]*Hints
+
+		// epoch is observed before reading system.statement_hints, and then
+		// checked again before loading the table contents. If the value changed
+		// in between, then the table contents might be stale.
+		epoch int
+	}
+	st *cluster.Settings
+	ie sqlutil.InternalExecutor
+	db *kv.DB
+}
+
+// NewCache constructs a new Cache.
+func NewCache(ie sqlutil.InternalExecutor, db *kv.DB, st *cluster.Settings) *Cache {
+	return &Cache{ie: ie, db: db, st: st}
+}
+
+// Start will start the polling loop for the Cache.
+func (c *Cache) Start(ctx context.Context, stopper *stop.Stopper) {
+	ctx, _ = stopper.WithCancelOnQuiesce(ctx)
+	// NB: The only error that should occur here would be if the server were
+	// shutting down so let's swallow it.
+	_ = stopper.RunAsyncTask(ctx, "statement-hints-poll", c.poll)
+}
+
+func (c *Cache) poll(ctx context.Context) {
+	var (
+		timer               timeutil.Timer
+		lastPoll            time.Time
+		deadline            time.Time
+		pollIntervalChanged = make(chan struct{}, 1)
+		maybeResetTimer     = func() {
+			if interval := pollingInterval.Get(&c.st.SV); interval <= 0 {
+				// Setting the interval to a non-positive value stops the polling.
+				timer.Stop()
+			} else {
+				newDeadline := lastPoll.Add(interval)
+				if deadline.IsZero() || !deadline.Equal(newDeadline) {
+					deadline = newDeadline
+					timer.Reset(timeutil.Until(deadline))
+				}
+			}
+		}
+	)
+	pollingInterval.SetOnChange(&c.st.SV, func() {
+		select {
+		case pollIntervalChanged <- struct{}{}:
+		default:
+		}
+	})
+	for {
+		maybeResetTimer()
+		select {
+		case <-pollIntervalChanged:
+			continue // go back around and maybe reset the timer
+		case <-timer.C:
+			timer.Read = true
+		case <-ctx.Done():
+			return
+		}
+		if err := c.refresh(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warningf(ctx, "error polling for statement hints: %s", err)
+		}
+		lastPoll = timeutil.Now()
+	}
+}
+
+// refresh reloads the whole of system.statement_hints, which is expected to be
+// small.
+func (c *Cache) refresh(ctx context.Context) error {
+	if !c.st.Version.IsActive(ctx, clusterversion.StatementHints) {
+		return nil
+	}
+	var rows []tree.Datums
+	// Loop until we run the query without straddling an epoch increment.
+	for {
+		c.mu.RLock()
+		epoch := c.mu.epoch
+		c.mu.RUnlock()
+
+		var err error
+		rows, err = c.ie.QueryEx(ctx, "statement-hints-poll", nil, /* txn */
+			sessiondata.InternalExecutorOverride{
+				User: security.RootUserName(),
+			},
+			"SELECT fingerprint, hint_type, hint_value FROM system.statement_hints")
+		if err != nil {
+			return err
+		}
+
+		c.mu.Lock()
+		// If the epoch changed it means that hints were changed on this node
+		// while the query was running, and the results might not reflect that
+		// change.
+		if c.mu.epoch != epoch {
+			c.mu.Unlock()
+			continue
+		}
+		break
+	}
+	defer c.mu.Unlock()
+
+	c.mu.rows = make(map[string][]Hint)
+	for _, row := range rows {
+		fingerprint := string(tree.MustBeDString(row[0]))
+		c.mu.rows[fingerprint] = append(c.mu.rows[fingerprint], Hint{
+			Type:  HintType(tree.MustBeDString(row[1])),
+			Value: string(tree.MustBeDString(row[2])),
+		})
+	}
+	c.mu.hints = make(map[string]*Hints, len(c.mu.rows))
+	for fingerprint, hints := range c.mu.rows {
+		if h := makeHints(hints); h != nil {
+			c.mu.hints[fingerprint] = h
+		}
+	}
+	return nil
+}
+
+// Lookup returns the hints for the statement whose fingerprint is returned by
+// the given function, or nil if there are none. The fingerprint function is
+// only called if there are any hints at all, so that statements don't pay for
+// computing their fingerprint in the common case.
+func (c *Cache) Lookup(fingerprint func() string) *Hints {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.mu.hints) == 0 {
+		return nil
+	}
+	return c.mu.hints[fingerprint()]
+}
+
+// AddHint adds a hint for statements with the given fingerprint. If the hint
+// replaces an existing hint (for example, a second join algorithm), the
+// existing hint is removed.
+func (c *Cache) AddHint(ctx context.Context, fingerprint string, h Hint) error {
+	if err := c.checkVersion(ctx); err != nil {
+		return err
+	}
+	if err := ValidateHint(h); err != nil {
+		return err
+	}
+	var hints []Hint
+	err := c.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		hints = hints[:0]
+		rows, err := c.ie.QueryEx(ctx, "statement-hints-get", txn,
+			sessiondata.InternalExecutorOverride{
+				User: security.RootUserName(),
+			},
+			"SELECT hint_type, hint_value FROM system.statement_hints WHERE fingerprint = $1",
+			fingerprint)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			existing := Hint{
+				Type:  HintType(tree.MustBeDString(row[0])),
+				Value: string(tree.MustBeDString(row[1])),
+			}
+			if existing == h {
+				continue
+			}
+			if h.replaces(existing) {
+				if _, err := c.ie.ExecEx(ctx, "statement-hints-replace", txn,
+					sessiondata.InternalExecutorOverride{
+						User: security.RootUserName(),
+					},
+					"DELETE FROM system.statement_hints "+
+						"WHERE fingerprint = $1 AND hint_type = $2 AND hint_value = $3",
+					fingerprint, string(existing.Type), existing.Value,
+				); err != nil {
+					return err
+				}
+				continue
+			}
+			hints = append(hints, existing)
+		}
+		hints = append(hints, h)
+		_, err = c.ie.ExecEx(ctx, "statement-hints-add", txn,
+			sessiondata.InternalExecutorOverride{
+				User: security.RootUserName(),
+			},
+			"UPSERT INTO system.statement_hints (fingerprint, hint_type, hint_value, created_at) "+
+				"VALUES ($1, $2, $3, $4)",
+			fingerprint, string(h.Type), h.Value, timeutil.Now())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Manually update the (local) cache. This lets this node apply the hints
+	// right away, without waiting for the poller.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.epoch++
+	c.setLocked(fingerprint, hints)
+	return nil
+}
+
+// RemoveHints removes all hints for statements with the given fingerprint,
+// and returns the number of hints that were removed.
+func (c *Cache) RemoveHints(ctx context.Context, fingerprint string) (int, error) {
+	if err := c.checkVersion(ctx); err != nil {
+		return 0, err
+	}
+	n, err := c.ie.ExecEx(ctx, "statement-hints-remove", nil, /* txn */
+		sessiondata.InternalExecutorOverride{
+			User: security.RootUserName(),
+		},
+		"DELETE FROM system.statement_hints WHERE fingerprint = $1",
+		fingerprint)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.epoch++
+	c.setLocked(fingerprint, nil /* hints */)
+	return n, nil
+}
+
+func (c *Cache) setLocked(fingerprint string, hints []Hint) {
+	if c.mu.rows == nil {
+		c.mu.rows = make(map[string][]Hint)
+		c.mu.hints = make(map[string]*Hints)
+	}
+	h := makeHints(hints)
+	if h == nil {
+		delete(c.mu.rows, fingerprint)
+		delete(c.mu.hints, fingerprint)
+		return
+	}
+	c.mu.rows[fingerprint] = hints
+	c.mu.hints[fingerprint] = h
+}
+
+func (c *Cache) checkVersion(ctx context.Context) error {
+	if !c.st.Version.IsActive(ctx, clusterversion.StatementHints) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"statement hints are not supported until version upgrade is finalized")
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package stmthints implements statement hints: optimizer directives that are
+// stored in system.statement_hints, keyed by statement fingerprint, and are
+// applied to every statement with a matching fingerprint without having to
+// rewrite its SQL.
+package stmthints
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
+)
+
+// HintType identifies the kind of a statement hint. It is stored in the
+// hint_type column of system.statement_hints.
+type HintType string
+
+const (
+	// IndexHint forces scans of a table to use a given index. Its value has the
+	// form "table@index", and it is equivalent to the table@index syntax.
+	IndexHint HintType = "index"
+	// JoinAlgorithmHint forces joins to use the given algorithm: one of hash,
+	// merge, lookup or inverted. It is equivalent to the INNER <algorithm> JOIN
+	// syntax, and only applies to joins which do not have a hint already.
+	JoinAlgorithmHint HintType = "join_algorithm"
+	// JoinOrderHint controls join reordering. The only supported value is
+	// "syntactic", which keeps joins in the order they are written.
+	JoinOrderHint HintType = "join_order"
+	// DisableRuleHint disables the optimizer rule with the given name.
+	DisableRuleHint HintType = "disable_rule"
+)
+
+// SyntacticJoinOrder is the value of a JoinOrderHint that disables join
+// reordering.
+const SyntacticJoinOrder = "syntactic"
+
+// Hint is a single row of system.statement_hints.
+type Hint struct {
+	Type  HintType
+	Value string
+}
+
+func (h Hint) String() string {
+	return string(h.Type) + "=" + h.Value
+}
+
+// ruleNames maps the name of every optimizer rule to the rule.
+var ruleNames = func() map[string]opt.RuleName {
+	m := make(map[string]opt.RuleName, opt.NumRuleNames)
+	for r := opt.RuleName(1); r < opt.NumRuleNames; r++ {
+		m[r.String()] = r
+	}
+	return m
+}()
+
+// syntacticJoinOrderRules are the exploration rules that change the order of
+// joins, which are disabled by a JoinOrderHint.
+var syntacticJoinOrderRules = []opt.RuleName{
+	opt.ReorderJoins,
+	opt.CommuteLeftJoin,
+	opt.CommuteSemiJoin,
+}
+
+// ValidateHint checks that the given hint is well formed. It does not check
+// that any table, index or rule that the hint refers to exists, since hints
+// can outlive schema changes; hints that no longer apply are ignored.
+func ValidateHint(h Hint) error {
+	switch h.Type {
+	case IndexHint:
+		if _, _, ok := splitIndexHint(h.Value); !ok {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid %s hint %q: expected table@index", h.Type, h.Value)
+		}
+	case JoinAlgorithmHint:
+		switch strings.ToUpper(h.Value) {
+		case tree.AstHash, tree.AstMerge, tree.AstLookup, tree.AstInverted:
+		default:
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid %s hint %q: expected one of hash, merge, lookup or inverted",
+				h.Type, h.Value)
+		}
+	case JoinOrderHint:
+		if h.Value != SyntacticJoinOrder {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid %s hint %q: expected %s", h.Type, h.Value, SyntacticJoinOrder)
+		}
+	case DisableRuleHint:
+		if _, ok := ruleNames[h.Value]; !ok {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid %s hint: unknown optimizer rule %q", h.Type, h.Value)
+		}
+	default:
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"unknown statement hint type %q", h.Type)
+	}
+	return nil
+}
+
+// replaces returns true if adding the hint h removes an existing hint other
+// for the same fingerprint. A statement can only have one join algorithm, one
+// join order and one forced index per table.
+func (h Hint) replaces(other Hint) bool {
+	if h.Type != other.Type {
+		return false
+	}
+	switch h.Type {
+	case JoinAlgorithmHint, JoinOrderHint:
+		return true
+	case IndexHint:
+		tab, _, _ := splitIndexHint(h.Value)
+		otherTab, _, _ := splitIndexHint(other.Value)
+		return tab == otherTab
+	}
+	return false
+}
+
+func splitIndexHint(value string) (tab, idx tree.Name, ok bool) {
+	i := strings.IndexByte(value, '@')
+	if i <= 0 || i == len(value)-1 {
+		return "", "", false
+	}
+	return tree.Name(value[:i]), tree.Name(value[i+1:]), true
+}
+
+// Hints is the set of hints that apply to one statement fingerprint. A Hints
+// is immutable once built, and can be shared between sessions.
+type Hints struct {
+	hints []Hint
+
+	indexes       map[tree.Name]tree.Name
+	joinAlgorithm string
+	disabledRules util.FastIntSet
+}
+
+// makeHints builds a Hints from the rows stored for a fingerprint. Rows that
+// no longer validate (for example because they name a rule that was removed)
+// are skipped.
+func makeHints(hints []Hint) *Hints {
+	res := &Hints{}
+	for _, h := range hints {
+		if ValidateHint(h) != nil {
+			continue
+		}
+		res.hints = append(res.hints, h)
+		switch h.Type {
+		case IndexHint:
+			tab, idx, _ := splitIndexHint(h.Value)
+			if res.indexes == nil {
+				res.indexes = make(map[tree.Name]tree.Name)
+			}
+			res.indexes[tab] = idx
+		case JoinAlgorithmHint:
+			res.joinAlgorithm = strings.ToUpper(h.Value)
+		case JoinOrderHint:
+			for _, r := range syntacticJoinOrderRules {
+				res.disabledRules.Add(int(r))
+			}
+		case DisableRuleHint:
+			res.disabledRules.Add(int(ruleNames[h.Value]))
+		}
+	}
+	if len(res.hints) == 0 {
+		return nil
+	}
+	sort.Slice(res.hints, func(i, j int) bool {
+		if res.hints[i].Type != res.hints[j].Type {
+			return res.hints[i].Type < res.hints[j].Type
+		}
+		return res.hints[i].Value < res.hints[j].Value
+	})
+	return res
+}
+
+// Index returns the name of the index that scans of the given table are
+// forced to use, if any.
+func (h *Hints) Index(table tree.Name) (_ tree.Name, ok bool) {
+	if h == nil {
+		return "", false
+	}
+	idx, ok := h.indexes[table]
+	return idx, ok
+}
+
+// JoinAlgorithm returns the join hint (one of the tree.Ast* join hint
+// constants) to use for joins that are not already hinted, or the empty
+// string if there is none.
+func (h *Hints) JoinAlgorithm() string {
+	if h == nil {
+		return ""
+	}
+	return h.joinAlgorithm
+}
+
+// RuleDisabled returns true if the given optimizer rule must not be applied.
+func (h *Hints) RuleDisabled(r opt.RuleName) bool {
+	if h == nil {
+		return false
+	}
+	return h.disabledRules.Contains(int(r))
+}
+
+// HasDisabledRules returns true if at least one optimizer rule is disabled.
+func (h *Hints) HasDisabledRules() bool {
+	return h != nil && !h.disabledRules.Empty()
+}
+
+// String returns the hints in a form suitable for EXPLAIN output, for example
+// "index=t@t_b_idx, join_algorithm=merge".
+func (h *Hints) String() string {
+	if h == nil {
+		return ""
+	}
+	var b strings.Builder
+	for i, hint := range h.hints {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(hint.String())
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stmthints
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateHint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		hint Hint
+		err  string
+	}{
+		{hint: Hint{IndexHint, "t@t_b_idx"}},
+		{hint: Hint{IndexHint, "t"}, err: `invalid index hint "t": expected table@index`},
+		{hint: Hint{IndexHint, "@idx"}, err: `invalid index hint "@idx": expected table@index`},
+		{hint: Hint{IndexHint, "t@"}, err: `invalid index hint "t@": expected table@index`},
+		{hint: Hint{JoinAlgorithmHint, "merge"}},
+		{hint: Hint{JoinAlgorithmHint, "LOOKUP"}},
+		{hint: Hint{JoinAlgorithmHint, "nested"}, err: `expected one of hash, merge, lookup or inverted`},
+		{hint: Hint{JoinOrderHint, "syntactic"}},
+		{hint: Hint{JoinOrderHint, "greedy"}, err: `expected syntactic`},
+		{hint: Hint{DisableRuleHint, "GenerateMergeJoins"}},
+		{hint: Hint{DisableRuleHint, "NoSuchRule"}, err: `unknown optimizer rule "NoSuchRule"`},
+		{hint: Hint{"no_such_type", "x"}, err: `unknown statement hint type "no_such_type"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.hint.String(), func(t *testing.T) {
+			err := ValidateHint(tc.hint)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestMakeHints(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	require.Nil(t, makeHints(nil))
+	require.Nil(t, makeHints([]Hint{{DisableRuleHint, "NoSuchRule"}}))
+
+	h := makeHints([]Hint{
+		{JoinOrderHint, "syntactic"},
+		{IndexHint, "t@t_b_idx"},
+		{JoinAlgorithmHint, "merge"},
+		{DisableRuleHint, "GenerateZigzagJoins"},
+		{IndexHint, "u@u_c_idx"},
+	})
+	idx, ok := h.Index("t")
+	require.True(t, ok)
+	require.Equal(t, "t_b_idx", string(idx))
+	_, ok = h.Index("v")
+	require.False(t, ok)
+	require.Equal(t, "MERGE", h.JoinAlgorithm())
+	require.True(t, h.HasDisabledRules())
+	require.True(t, h.RuleDisabled(opt.GenerateZigzagJoins))
+	require.True(t, h.RuleDisabled(opt.ReorderJoins))
+	require.False(t, h.RuleDisabled(opt.GenerateMergeJoins))
+	require.Equal(t,
+		"disable_rule=GenerateZigzagJoins, index=t@t_b_idx, index=u@u_c_idx, "+
+			"join_algorithm=merge, join_order=syntactic",
+		h.String(),
+	)
+
+	// A nil Hints has no hints.
+	var none *Hints
+	_, ok = none.Index("t")
+	require.False(t, ok)
+	require.Equal(t, "", none.JoinAlgorithm())
+	require.False(t, none.HasDisabledRules())
+}
+
+func TestHintReplaces(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	require.True(t, Hint{JoinAlgorithmHint, "hash"}.replaces(Hint{JoinAlgorithmHint, "merge"}))
+	require.True(t, Hint{IndexHint, "t@a"}.replaces(Hint{IndexHint, "t@b"}))
+	require.False(t, Hint{IndexHint, "t@a"}.replaces(Hint{IndexHint, "u@a"}))
+	require.False(t, Hint{DisableRuleHint, "ReorderJoins"}.replaces(
+		Hint{DisableRuleHint, "GenerateMergeJoins"}))
+	require.False(t, Hint{JoinOrderHint, "syntactic"}.replaces(Hint{JoinAlgorithmHint, "hash"}))
+}
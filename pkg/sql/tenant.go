@@ -14,6 +14,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -282,6 +283,13 @@ func GCTenant(ctx context.Context, execCfg *ExecutorConfig, info *descpb.TenantI
 		} else if num != 1 {
 			log.Fatalf(ctx, "unexpected number of rows affected: %d", num)
 		}
+
+		if _, err := execCfg.InternalExecutor.ExecEx(
+			ctx, "delete-tenant-usage", txn, sessiondata.NodeUserSessionDataOverride,
+			`DELETE FROM system.tenant_usage WHERE tenant_id = $1`, info.ID,
+		); err != nil {
+			return errors.Wrapf(err, "deleting tenant %d usage", info.ID)
+		}
 		return nil
 	})
 	return errors.Wrapf(err, "deleting tenant %d record", info.ID)
@@ -307,3 +315,32 @@ func (p *planner) GCTenant(ctx context.Context, tenID uint64) error {
 
 	return GCTenant(ctx, p.ExecCfg(), info)
 }
+
+// UpdateTenantResourceLimits implements the tree.TenantOperator interface.
+func (p *planner) UpdateTenantResourceLimits(
+	ctx context.Context, tenantID uint64, availableRU float64, refillRate float64, maxBurstRU float64,
+) error {
+	const op = "update-resource-limits"
+	if err := rejectIfCantCoordinateMultiTenancy(p.execCfg.Codec, op); err != nil {
+		return err
+	}
+	if err := rejectIfSystemTenant(tenantID, op); err != nil {
+		return err
+	}
+	if !p.execCfg.Settings.Version.IsActive(ctx, clusterversion.TenantUsageTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot update tenant resource limits until upgrade to version %s is finalized",
+			clusterversion.TenantUsageTable)
+	}
+	if availableRU < 0 || refillRate < 0 || maxBurstRU < 0 {
+		return pgerror.New(pgcode.InvalidParameterValue,
+			"tenant resource limits must be non-negative")
+	}
+	// Make sure the tenant exists.
+	if _, err := getTenantRecord(ctx, p.execCfg, p.txn, tenantID); err != nil {
+		return err
+	}
+	return p.execCfg.TenantUsageServer.ReconfigureTokenBucket(
+		ctx, p.txn, roachpb.MakeTenantID(tenantID), availableRU, refillRate, maxBurstRU,
+	)
+}
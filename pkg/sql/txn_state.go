@@ -287,6 +287,24 @@ func (ts *txnState) setHistoricalTimestamp(ctx context.Context, historicalTimest
 	ts.isHistorical = true
 }
 
+// setBoundedStaleness configures the transaction to negotiate its timestamp
+// through a bounded staleness read described by the provided AS OF SYSTEM TIME
+// clause. The negotiation happens when the first read-only batch is sent
+// through the transaction.
+func (ts *txnState) setBoundedStaleness(ctx context.Context, asOf tree.AsOfSystemTime) error {
+	ts.mu.Lock()
+	err := ts.mu.txn.SetBoundedStaleness(ctx, roachpb.BoundedStalenessHeader{
+		MinTimestampBound:       asOf.Timestamp,
+		MinTimestampBoundStrict: asOf.NearestOnly,
+	})
+	ts.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	ts.isHistorical = true
+	return nil
+}
+
 // getReadTimestamp returns the transaction's current read timestamp.
 func (ts *txnState) getReadTimestamp() hlc.Timestamp {
 	ts.mu.RLock()
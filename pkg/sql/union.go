@@ -76,10 +76,17 @@ type unionNode struct {
 	unionType tree.UnionType
 	// all indicates if the operation is the ALL or DISTINCT version
 	all bool
+
+	// hardLimit, if non-zero, is the maximum number of rows returned by a UNION
+	// ALL. It is used for locality optimized search: the left plan is read
+	// first, and the right plan can be skipped once the limit is reached. The
+	// limit is enforced by the physical plan, which merges the inputs serially
+	// and limits the merged stream (see createPlanForSetOp).
+	hardLimit uint64
 }
 
 func (p *planner) newUnionNode(
-	typ tree.UnionType, all bool, left, right planNode,
+	typ tree.UnionType, all bool, left, right planNode, hardLimit uint64,
 ) (planNode, error) {
 	emitAll := false
 	switch typ {
@@ -92,6 +99,9 @@ func (p *planner) newUnionNode(
 	default:
 		return nil, errors.Errorf("%v is not supported", typ)
 	}
+	if hardLimit != 0 && (typ != tree.UnionOp || !all) {
+		return nil, errors.AssertionFailedf("a hard limit is only supported for UNION ALL")
+	}
 
 	leftColumns := planColumns(left)
 	rightColumns := planColumns(right)
@@ -137,6 +147,7 @@ func (p *planner) newUnionNode(
 		emitAll:   emitAll,
 		unionType: typ,
 		all:       all,
+		hardLimit: hardLimit,
 	}
 	return node, nil
 }
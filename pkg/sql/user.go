@@ -25,8 +25,26 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
+// RoleAuthenticationPolicy is the authentication policy configured for a
+// role using the AUTHENTICATION and CONNECTION FROM role options. It is
+// consulted before the HBA configuration when the role logs in.
+type RoleAuthenticationPolicy struct {
+	// Method, if non-empty, is the authentication method that the role
+	// must use, regardless of the HBA configuration.
+	Method string
+	// Address, if non-empty, is the network in CIDR notation from which
+	// connections for the role must originate.
+	Address string
+}
+
+// IsEmpty returns true if the role has no authentication policy.
+func (p RoleAuthenticationPolicy) IsEmpty() bool {
+	return p.Method == "" && p.Address == ""
+}
+
 // GetUserHashedPassword determines if the given user exists and
-// also returns a password retrieval function.
+// also returns a password retrieval function and the authentication
+// policy configured for the user.
 //
 // The caller is responsible for normalizing the username.
 // (CockroachDB has case-insensitive usernames, unlike PostgreSQL.)
@@ -46,6 +64,9 @@ import (
 //   ignored. This ensures that root has a modicum of comfort
 //   logging into an unavailable cluster.
 //
+//   Any authentication policy configured for root is ignored, so that
+//   root cannot be locked out of the cluster.
+//
 //   TODO(knz): this does not yet quite work becaus even if the pw
 //   auth on the UI succeeds writing to system.web_sessions will still
 //   stall on an unavailable cluster and prevent root from logging in.
@@ -61,6 +82,7 @@ func GetUserHashedPassword(
 	canLogin bool,
 	pwRetrieveFn func(ctx context.Context) (hashedPassword []byte, err error),
 	validUntilFn func(ctx context.Context) (timestamp *tree.DTimestamp, err error),
+	authPolicy RoleAuthenticationPolicy,
 	err error,
 ) {
 	isRoot := username.IsRootUser()
@@ -70,7 +92,7 @@ func GetUserHashedPassword(
 		// immediately, and delay retrieving the password until strictly
 		// necessary.
 		rootFn := func(ctx context.Context) ([]byte, error) {
-			_, _, hashedPassword, _, err := retrieveUserAndPassword(ctx, ie, isRoot, username)
+			_, _, hashedPassword, _, _, err := retrieveUserAndPassword(ctx, ie, isRoot, username)
 			return hashedPassword, err
 		}
 
@@ -78,21 +100,29 @@ func GetUserHashedPassword(
 		validUntilFn := func(ctx context.Context) (*tree.DTimestamp, error) {
 			return nil, nil
 		}
-		return true, true, rootFn, validUntilFn, nil
+		return true, true, rootFn, validUntilFn, RoleAuthenticationPolicy{}, nil
 	}
 
 	// Other users must reach for system.users no matter what, because
 	// only that contains the truth about whether the user exists.
-	exists, canLogin, hashedPassword, validUntil, err := retrieveUserAndPassword(ctx, ie, isRoot, username)
+	exists, canLogin, hashedPassword, validUntil, authPolicy, err := retrieveUserAndPassword(ctx, ie, isRoot, username)
 	return exists, canLogin,
 		func(ctx context.Context) ([]byte, error) { return hashedPassword, nil },
 		func(ctx context.Context) (*tree.DTimestamp, error) { return validUntil, nil },
+		authPolicy,
 		err
 }
 
 func retrieveUserAndPassword(
 	ctx context.Context, ie *InternalExecutor, isRoot bool, normalizedUsername security.SQLUsername,
-) (exists bool, canLogin bool, hashedPassword []byte, validUntil *tree.DTimestamp, err error) {
+) (
+	exists bool,
+	canLogin bool,
+	hashedPassword []byte,
+	validUntil *tree.DTimestamp,
+	authPolicy RoleAuthenticationPolicy,
+	err error,
+) {
 	// We may be operating with a timeout.
 	timeout := userLoginTimeout.Get(&ie.s.cfg.Settings.SV)
 	// We don't like long timeouts for root.
@@ -132,7 +162,7 @@ func retrieveUserAndPassword(
 		}
 
 		getLoginDependencies := `SELECT option, value FROM system.role_options ` +
-			`WHERE username=$1 AND option IN ('NOLOGIN', 'VALID UNTIL')`
+			`WHERE username=$1 AND option IN ('NOLOGIN', 'VALID UNTIL', 'AUTHENTICATION', 'CONNECTION FROM')`
 
 		loginDependencies, err := ie.QueryEx(
 			ctx, "get-login-dependencies", nil, /* txn */
@@ -168,6 +198,14 @@ func retrieveUserAndPassword(
 					}
 				}
 			}
+
+			if option == "AUTHENTICATION" && row[1] != tree.DNull {
+				authPolicy.Method = string(tree.MustBeDString(row[1]))
+			}
+
+			if option == "CONNECTION FROM" && row[1] != tree.DNull {
+				authPolicy.Address = string(tree.MustBeDString(row[1]))
+			}
 		}
 
 		return nil
@@ -178,7 +216,7 @@ func retrieveUserAndPassword(
 		log.Warningf(ctx, "user lookup for %q failed: %v", normalizedUsername, err)
 		err = errors.Wrap(errors.Handled(err), "internal error while retrieving user account")
 	}
-	return exists, canLogin, hashedPassword, validUntil, err
+	return exists, canLogin, hashedPassword, validUntil, authPolicy, err
 }
 
 var userLoginTimeout = settings.RegisterPublicNonNegativeDurationSetting(
@@ -34,6 +34,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/errors"
 )
 
@@ -311,6 +313,22 @@ var varGen = map[string]sessionVar{
 		GlobalDefault: func(sv *settings.Values) string { return "" },
 	},
 
+	// See https://www.postgresql.org/docs/13/runtime-config-client.html#GUC-DEFAULT-TEXT-SEARCH-CONFIG
+	`default_text_search_config`: {
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			config, err := tsearch.GetConfig(s)
+			if err != nil {
+				return err
+			}
+			m.SetDefaultTextSearchConfig("pg_catalog." + config.Name())
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return evalCtx.SessionData.DefaultTextSearchConfig
+		},
+		GlobalDefault: func(sv *settings.Values) string { return tsearch.DefaultConfigName },
+	},
+
 	// See https://www.postgresql.org/docs/10/static/runtime-config-client.html#GUC-DEFAULT-TRANSACTION-ISOLATION
 	`default_transaction_isolation`: {
 		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
@@ -444,6 +462,25 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension.
+	`index_recommendations_enabled`: {
+		GetStringVal: makePostgresBoolGetStringValFn(`index_recommendations_enabled`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			b, err := paramparse.ParseBoolVar("index_recommendations_enabled", s)
+			if err != nil {
+				return err
+			}
+			m.SetIndexRecommendationsEnabled(b)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return formatBoolAsPostgresSetting(evalCtx.SessionData.IndexRecommendationsEnabled)
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return formatBoolAsPostgresSetting(indexRecommendationsClusterMode.Get(sv))
+		},
+	},
+
 	// CockroachDB extension.
 	`reorder_joins_limit`: {
 		GetStringVal: makeIntGetStringValFn(`reorder_joins_limit`),
@@ -1146,6 +1183,29 @@ var varGen = map[string]sessionVar{
 			return formatBoolAsPostgresSetting(experimentalMultiColumnInvertedIndexesMode.Get(sv))
 		},
 	},
+
+	// See https://www.postgresql.org/docs/13/pgtrgm.html#id-1.11.7.40.8
+	`pg_trgm.similarity_threshold`: {
+		GetStringVal: makeFloatGetStringValFn(`pg_trgm.similarity_threshold`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return wrapSetVarError("pg_trgm.similarity_threshold", s, "%v", err)
+			}
+			if f < 0 || f > 1 {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					`%g is outside the valid range for parameter "pg_trgm.similarity_threshold" (0 .. 1)`, f)
+			}
+			m.SetTrigramSimilarityThreshold(f)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return strconv.FormatFloat(evalCtx.SessionData.TrigramSimilarityThreshold, 'g', -1, 64)
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return strconv.FormatFloat(trigram.DefaultSimilarityThreshold, 'g', -1, 64)
+		},
+	},
 }
 
 const compatErrMsg = "this parameter is currently recognized only for compatibility and has no effect in CockroachDB."
@@ -1300,6 +1360,19 @@ func makeIntGetStringValFn(name string) getStringValFn {
 	}
 }
 
+func makeFloatGetStringValFn(name string) getStringValFn {
+	return func(ctx context.Context, evalCtx *extendedEvalContext, values []tree.TypedExpr) (string, error) {
+		if len(values) != 1 {
+			return "", newSingleArgVarError(name)
+		}
+		f, err := paramparse.DatumAsFloat(&evalCtx.EvalContext, name, values[0])
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	}
+}
+
 // IsSessionVariableConfigurable returns true iff there is a session
 // variable with the given name and it is settable by a client
 // (e.g. in pgwire).
@@ -330,6 +330,36 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		// Introduced in v20.2.
 		name: "mark non-terminal schema change jobs with a pre-20.1 format version as failed",
 	},
+	{
+		// Introduced in v21.1.
+		name:                "create new system.tenant_usage table",
+		workFn:              createTenantUsageTable,
+		includedInBootstrap: clusterversion.ByKey(clusterversion.TenantUsageTable),
+		newDescriptorIDs:    staticIDs(keys.TenantUsageTableID),
+		clusterWide:         true,
+	},
+	{
+		// Introduced in v21.1.
+		name:                "create new system.statement_statistics and system.transaction_statistics tables",
+		workFn:              createSQLStatsTables,
+		includedInBootstrap: clusterversion.ByKey(clusterversion.PersistedSQLStats),
+		newDescriptorIDs: staticIDs(
+			keys.StatementStatisticsTableID, keys.TransactionStatisticsTableID,
+		),
+	},
+	{
+		// Introduced in v21.1.
+		name:                "create system.statement_hints table",
+		workFn:              createStatementHintsTable,
+		includedInBootstrap: clusterversion.ByKey(clusterversion.StatementHints),
+		newDescriptorIDs:    staticIDs(keys.StatementHintsTableID),
+	},
+	{
+		// Introduced in v21.1.
+		name:                "add partialPredicate column to system.table_statistics",
+		workFn:              alterSystemTableStatisticsAddPartialPredicate,
+		includedInBootstrap: clusterversion.ByKey(clusterversion.PartialTableStats),
+	},
 }
 
 func staticIDs(
@@ -1263,6 +1293,34 @@ func createTenantsTable(ctx context.Context, r runner) error {
 	return createSystemTable(ctx, r, systemschema.TenantsTable)
 }
 
+func createTenantUsageTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, systemschema.TenantUsageTable)
+}
+
+func createSQLStatsTables(ctx context.Context, r runner) error {
+	if err := createSystemTable(ctx, r, systemschema.StatementStatisticsTable); err != nil {
+		return err
+	}
+	return createSystemTable(ctx, r, systemschema.TransactionStatisticsTable)
+}
+
+func createStatementHintsTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, systemschema.StatementHintsTable)
+}
+
+func alterSystemTableStatisticsAddPartialPredicate(ctx context.Context, r runner) error {
+	// NB: we use the family name as it existed in the original
+	// system.table_statistics schema to avoid renaming the family.
+	addColStmt := `
+ALTER TABLE system.table_statistics
+ADD COLUMN IF NOT EXISTS "partialPredicate" STRING
+FAMILY "fam_0_tableID_statisticID_name_columnIDs_createdAt_rowCount_distinctCount_nullCount_histogram"
+`
+	asNode := sessiondata.InternalExecutorOverride{User: security.NodeUserName()}
+	_, err := r.sqlExecutor.ExecEx(ctx, "add-table-statistics-partial-predicate", nil, asNode, addColStmt)
+	return err
+}
+
 func alterSystemScheduledJobsFixTableSchema(ctx context.Context, r runner) error {
 	setOwner := "UPDATE system.scheduled_jobs SET owner='root' WHERE owner IS NULL"
 	asNode := sessiondata.InternalExecutorOverride{User: security.NodeUserName()}
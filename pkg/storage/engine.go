@@ -714,6 +714,9 @@ type Metrics struct {
 	L0SublevelCount                int64
 	ReadAmplification              int64
 	NumSSTables                    int64
+	// Pebble holds the raw metrics of the underlying Pebble instance, which
+	// are consumed by admission control.
+	Pebble *pebble.Metrics
 }
 
 // EnvStats is a set of RocksDB env stats, including encryption status.
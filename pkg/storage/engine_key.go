@@ -169,9 +169,9 @@ func (k EngineKey) ToLockTableKey() (LockTableKey, error) {
 	key := LockTableKey{Key: lockedKey}
 	switch len(k.Version) {
 	case engineKeyVersionLockTableLen:
-		key.Strength = lock.Strength(k.Version[0])
-		if key.Strength < lock.None || key.Strength > lock.Exclusive {
-			return LockTableKey{}, errors.Errorf("unknown strength %d", key.Strength)
+		var ok bool
+		if key.Strength, ok = getReplicatedLockStrengthForByte(k.Version[0]); !ok {
+			return LockTableKey{}, errors.Errorf("unknown strength %d", k.Version[0])
 		}
 		key.TxnUUID = k.Version[1:]
 	default:
@@ -236,6 +236,49 @@ func (m EngineKeyFormatter) Format(f fmt.State, c rune) {
 	m.key.Format(f, c)
 }
 
+// replicatedLockStrengthToByte is a mapping between the strengths of the
+// replicated locks that can be stored in the lock table and the byte that
+// represents each strength in the version of a lock table key. Intents use
+// the byte that lock.Exclusive mapped to when intents were the only kind of
+// lock stored in the lock table, so that existing separated intents continue
+// to decode correctly.
+var replicatedLockStrengthToByte = [...]byte{
+	lock.Shared:    1,
+	lock.Exclusive: 2,
+	lock.Intent:    3,
+}
+
+// byteToReplicatedLockStrength is the inverse of replicatedLockStrengthToByte.
+var byteToReplicatedLockStrength = func() (arr [len(replicatedLockStrengthToByte)]lock.Strength) {
+	for str, b := range replicatedLockStrengthToByte {
+		if b != 0 {
+			arr[b] = lock.Strength(str)
+		}
+	}
+	return arr
+}()
+
+// getByteForReplicatedLockStrength returns the byte that represents the
+// given strength in a lock table key. The second return value is false if
+// locks of the given strength cannot be stored in the lock table.
+func getByteForReplicatedLockStrength(str lock.Strength) (byte, bool) {
+	if str < 0 || int(str) >= len(replicatedLockStrengthToByte) {
+		return 0, false
+	}
+	b := replicatedLockStrengthToByte[str]
+	return b, b != 0
+}
+
+// getReplicatedLockStrengthForByte is the inverse of
+// getByteForReplicatedLockStrength.
+func getReplicatedLockStrengthForByte(b byte) (lock.Strength, bool) {
+	if int(b) >= len(byteToReplicatedLockStrength) {
+		return lock.None, false
+	}
+	str := byteToReplicatedLockStrength[b]
+	return str, str != lock.None
+}
+
 // LockTableKey is a key representing a lock in the lock table.
 type LockTableKey struct {
 	Key      roachpb.Key
@@ -252,7 +295,8 @@ func (lk LockTableKey) ToEngineKey(buf []byte) (EngineKey, []byte) {
 	if len(lk.TxnUUID) != uuid.Size {
 		panic("invalid TxnUUID")
 	}
-	if lk.Strength != lock.Exclusive {
+	strByte, ok := getByteForReplicatedLockStrength(lk.Strength)
+	if !ok {
 		panic("unsupported lock strength")
 	}
 	// The first term in estimatedLen is for LockTableSingleKey.
@@ -270,7 +314,7 @@ func (lk LockTableKey) ToEngineKey(buf []byte) (EngineKey, []byte) {
 		// estimatedLen was an underestimate.
 		k.Version = make([]byte, engineKeyVersionLockTableLen)
 	}
-	k.Version[0] = byte(lk.Strength)
+	k.Version[0] = strByte
 	copy(k.Version[1:], lk.TxnUUID)
 	return k, buf
 }
@@ -36,6 +36,8 @@ func TestLockTableKeyEncodeDecode(t *testing.T) {
 	}{
 		{key: LockTableKey{Key: roachpb.Key("foo"), Strength: lock.Exclusive, TxnUUID: uuid1[:]}},
 		{key: LockTableKey{Key: roachpb.Key("a"), Strength: lock.Exclusive, TxnUUID: uuid2[:]}},
+		{key: LockTableKey{Key: roachpb.Key("a"), Strength: lock.Shared, TxnUUID: uuid2[:]}},
+		{key: LockTableKey{Key: roachpb.Key("b"), Strength: lock.Intent, TxnUUID: uuid1[:]}},
 		// Causes a doubly-local range local key.
 		{key: LockTableKey{
 			Key:      keys.RangeDescriptorKey(roachpb.RKey("baz")),
@@ -79,6 +81,27 @@ func TestLockTableKeyEncodeDecode(t *testing.T) {
 	}
 }
 
+// TestLockTableKeyStrengthEncoding verifies that intents continue to be
+// encoded with the strength byte they used before other kinds of replicated
+// locks could be stored in the lock table, and that strengths that cannot be
+// stored in the lock table are rejected.
+func TestLockTableKeyStrengthEncoding(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	txnUUID := uuid.MakeV4()
+	eKey, _ := LockTableKey{Key: roachpb.Key("a"), Strength: lock.Intent, TxnUUID: txnUUID[:]}.ToEngineKey(nil)
+	require.Equal(t, byte(3), eKey.Version[0])
+	for _, str := range []lock.Strength{lock.None, lock.Upgrade} {
+		require.Panics(t, func() {
+			LockTableKey{Key: roachpb.Key("a"), Strength: str, TxnUUID: txnUUID[:]}.ToEngineKey(nil)
+		})
+	}
+	for _, b := range []byte{0, 4} {
+		eKey.Version[0] = b
+		_, err := eKey.ToLockTableKey()
+		require.Error(t, err)
+	}
+}
+
 func TestMVCCAndEngineKeyEncodeDecode(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	testCases := []struct {
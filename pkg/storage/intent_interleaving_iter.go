@@ -12,6 +12,7 @@ package storage
 
 import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -25,7 +26,9 @@ import (
 //   However, for a particular roachpb.Key there will be at most one intent,
 //   either interleaved or separated.
 // - An intent will have a corresponding provisional value.
-// - The only single key locks in the lock table key space are intents.
+// - The only single key locks in the lock table key space that are
+//   interleaved are intents. Other replicated locks, which have no
+//   provisional value, are skipped (see lockTableIntentIter).
 //
 // Semantically, the functionality is equivalent to merging two MVCCIterators:
 // - A MVCCIterator on the MVCC key space.
@@ -108,7 +111,7 @@ func newIntentInterleavingIterator(reader Reader, opts IterOptions) MVCCIterator
 		intentOpts.UpperBound, _ = keys.LockTableSingleKey(opts.UpperBound, nil)
 	}
 	// Note that we can reuse intentKeyBuf after NewEngineIterator returns.
-	intentIter := reader.NewEngineIterator(intentOpts)
+	intentIter := lockTableIntentIter{reader.NewEngineIterator(intentOpts)}
 
 	// We assume that callers iterating forward will set an upper bound,
 	// and callers iterating in reverse will set a lower bound, which
@@ -127,6 +130,57 @@ func newIntentInterleavingIterator(reader Reader, opts IterOptions) MVCCIterator
 	}
 }
 
+// lockTableIntentIter wraps an EngineIterator over the lock table key space
+// and skips over the replicated locks that are not intents, i.e. the Shared
+// and Exclusive locks acquired by locking reads. These locks have no
+// provisional value, so they must not be interleaved with MVCC keys.
+type lockTableIntentIter struct {
+	EngineIterator
+}
+
+// SeekEngineKeyGE implements the EngineIterator interface.
+func (i lockTableIntentIter) SeekEngineKeyGE(key EngineKey) (valid bool, err error) {
+	valid, err = i.EngineIterator.SeekEngineKeyGE(key)
+	return i.skipNonIntents(valid, err, i.EngineIterator.NextEngineKey)
+}
+
+// SeekEngineKeyLT implements the EngineIterator interface.
+func (i lockTableIntentIter) SeekEngineKeyLT(key EngineKey) (valid bool, err error) {
+	valid, err = i.EngineIterator.SeekEngineKeyLT(key)
+	return i.skipNonIntents(valid, err, i.EngineIterator.PrevEngineKey)
+}
+
+// NextEngineKey implements the EngineIterator interface.
+func (i lockTableIntentIter) NextEngineKey() (valid bool, err error) {
+	valid, err = i.EngineIterator.NextEngineKey()
+	return i.skipNonIntents(valid, err, i.EngineIterator.NextEngineKey)
+}
+
+// PrevEngineKey implements the EngineIterator interface.
+func (i lockTableIntentIter) PrevEngineKey() (valid bool, err error) {
+	valid, err = i.EngineIterator.PrevEngineKey()
+	return i.skipNonIntents(valid, err, i.EngineIterator.PrevEngineKey)
+}
+
+// skipNonIntents steps the iterator in the direction of step until it is
+// positioned at an intent or is exhausted.
+func (i lockTableIntentIter) skipNonIntents(
+	valid bool, err error, step func() (bool, error),
+) (bool, error) {
+	intentByte := replicatedLockStrengthToByte[lock.Intent]
+	for valid && err == nil {
+		var key EngineKey
+		if key, err = i.UnsafeEngineKey(); err != nil {
+			return false, err
+		}
+		if !key.IsLockTableKey() || key.Version[0] == intentByte {
+			return true, nil
+		}
+		valid, err = step()
+	}
+	return valid, err
+}
+
 func (i *intentInterleavingIter) SeekGE(key MVCCKey) {
 	i.dir = +1
 	i.valid = true
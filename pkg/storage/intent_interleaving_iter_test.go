@@ -241,7 +241,7 @@ func TestIntentInterleavingIter(t *testing.T) {
 								return err.Error()
 							}
 						} else {
-							ltKey := LockTableKey{Key: key, Strength: lock.Exclusive, TxnUUID: txnUUID[:]}
+							ltKey := LockTableKey{Key: key, Strength: lock.Intent, TxnUUID: txnUUID[:]}
 							eKey, _ := ltKey.ToEngineKey(nil)
 							if err := batch.PutEngineKey(eKey, val); err != nil {
 								return err.Error()
@@ -372,7 +372,7 @@ func generateRandomData(t *testing.T, rng *rand.Rand) (lkv []lockKeyValue, mvcck
 			require.NoError(t, err)
 			isSeparated := rng.Int31n(2) == 0
 			if isSeparated {
-				ltKey := LockTableKey{Key: key, Strength: lock.Exclusive, TxnUUID: txnUUID[:]}
+				ltKey := LockTableKey{Key: key, Strength: lock.Intent, TxnUUID: txnUUID[:]}
 				lkv = append(lkv, lockKeyValue{key: ltKey, val: val})
 			} else {
 				mvcckv = append(mvcckv, MVCCKeyValue{Key: MVCCKey{Key: key}, Value: val})
@@ -574,7 +574,7 @@ func writeBenchData(
 			require.NoError(b, err)
 			if separated {
 				eKey, _ :=
-					LockTableKey{Key: key, Strength: lock.Exclusive, TxnUUID: txnUUID[:]}.ToEngineKey(nil)
+					LockTableKey{Key: key, Strength: lock.Intent, TxnUUID: txnUUID[:]}.ToEngineKey(nil)
 				require.NoError(b, batch.PutEngineKey(eKey, val))
 			} else {
 				require.NoError(b, batch.PutUnversioned(key, val))
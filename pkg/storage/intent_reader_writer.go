@@ -81,7 +81,7 @@ func (idw intentDemuxWriter) ClearIntent(
 		var engineKey EngineKey
 		engineKey, buf = LockTableKey{
 			Key:      key,
-			Strength: lock.Exclusive,
+			Strength: lock.Intent,
 			TxnUUID:  txnUUID[:],
 		}.ToEngineKey(buf)
 		if txnDidNotUpdateMeta {
@@ -108,7 +108,7 @@ func (idw intentDemuxWriter) PutIntent(
 	if state == ExistingIntentSeparated || idw.enabledSeparatedIntents {
 		engineKey, buf = LockTableKey{
 			Key:      key,
-			Strength: lock.Exclusive,
+			Strength: lock.Intent,
 			TxnUUID:  txnUUID[:],
 		}.ToEngineKey(buf)
 	}
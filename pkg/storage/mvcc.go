@@ -22,9 +22,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
@@ -70,6 +73,21 @@ var minWALSyncInterval = settings.RegisterDurationSetting(
 	0*time.Millisecond,
 )
 
+// ReplicatedLockingEnabled controls whether locking reads acquire replicated
+// locks. Unlike unreplicated locks, which are held only in the leaseholder's
+// in-memory lock table, replicated locks survive lease transfers and range
+// merges, at the cost of a round of consensus for each locking read. Writes
+// only look for conflicting replicated locks while the setting is enabled, so
+// a replicated lock acquired before the setting is disabled is not respected
+// by writes evaluated after it is disabled.
+var ReplicatedLockingEnabled = settings.RegisterBoolSetting(
+	"kv.transaction.replicated_locking.enabled",
+	"if enabled, locking reads acquire replicated locks, which are durable across "+
+		"lease transfers; otherwise, Exclusive locking reads acquire unreplicated locks "+
+		"and Shared locking reads acquire no locks",
+	false,
+)
+
 var rocksdbConcurrency = envutil.EnvOrDefaultInt(
 	"COCKROACH_ROCKSDB_CONCURRENCY", func() int {
 		// Use up to min(numCPU, 4) threads for background RocksDB compactions per
@@ -770,6 +788,13 @@ type MVCCGetOptions struct {
 	Tombstones       bool
 	FailOnMoreRecent bool
 	Txn              *roachpb.Transaction
+	// SkipLocked causes the read to ignore a key that is locked by a
+	// conflicting transaction, either with a replicated intent or with a lock
+	// in LockTable, instead of returning an error.
+	SkipLocked bool
+	// LockTable is consulted by SkipLocked reads to determine whether the key
+	// is locked with an unreplicated lock. Must be set if SkipLocked is.
+	LockTable LockTableView
 }
 
 func (opts *MVCCGetOptions) validate() error {
@@ -779,9 +804,26 @@ func (opts *MVCCGetOptions) validate() error {
 	if opts.Inconsistent && opts.FailOnMoreRecent {
 		return errors.Errorf("cannot allow inconsistent reads with fail on more recent option")
 	}
+	if opts.Inconsistent && opts.SkipLocked {
+		return errors.Errorf("cannot allow inconsistent reads with skip locked option")
+	}
+	if opts.SkipLocked && opts.LockTable == nil {
+		return errors.Errorf("cannot allow skip locked option without a lock table")
+	}
 	return nil
 }
 
+// LockTableView is a transaction-bound view into an in-memory collection of
+// key-level locks.
+type LockTableView interface {
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked
+	// or reserved (for strength > lock.None) by a conflicting transaction,
+	// given the caller's own desired locking strength. If so, true is
+	// returned. If the key is locked, the lock holder is also returned.
+	// Otherwise, if the key is only reserved, nil is returned.
+	IsKeyLockedByConflictingTxn(roachpb.Key, lock.Strength) (bool, *enginepb.TxnMeta)
+}
+
 func newMVCCIterator(reader Reader, inlineMeta bool, opts IterOptions) MVCCIterator {
 	iterKind := MVCCKeyAndIntentsIterKind
 	if inlineMeta {
@@ -854,6 +896,8 @@ func mvccGet(
 		inconsistent:     opts.Inconsistent,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		skipLocked:       opts.SkipLocked,
+		lockTable:        opts.LockTable,
 		keyBuf:           mvccScanner.keyBuf,
 	}
 
@@ -1092,6 +1136,9 @@ func MVCCPut(
 	var iter MVCCIterator
 	blind := ms == nil && timestamp.IsEmpty()
 	if !blind {
+		if err := mvccCheckForConflictingReplicatedLocks(rw, key, timestamp, txn); err != nil {
+			return err
+		}
 		iter = rw.NewMVCCIterator(MVCCKeyAndIntentsIterKind, IterOptions{Prefix: true})
 		defer iter.Close()
 	}
@@ -1134,6 +1181,9 @@ func MVCCDelete(
 	timestamp hlc.Timestamp,
 	txn *roachpb.Transaction,
 ) error {
+	if err := mvccCheckForConflictingReplicatedLocks(rw, key, timestamp, txn); err != nil {
+		return err
+	}
 	iter := newMVCCIterator(rw, timestamp.IsEmpty(), IterOptions{Prefix: true})
 	defer iter.Close()
 
@@ -1764,6 +1814,9 @@ func MVCCIncrement(
 	txn *roachpb.Transaction,
 	inc int64,
 ) (int64, error) {
+	if err := mvccCheckForConflictingReplicatedLocks(rw, key, timestamp, txn); err != nil {
+		return 0, err
+	}
 	iter := newMVCCIterator(rw, timestamp.IsEmpty(), IterOptions{Prefix: true})
 	defer iter.Close()
 
@@ -1836,6 +1889,9 @@ func MVCCConditionalPut(
 	allowIfDoesNotExist CPutMissingBehavior,
 	txn *roachpb.Transaction,
 ) error {
+	if err := mvccCheckForConflictingReplicatedLocks(rw, key, timestamp, txn); err != nil {
+		return err
+	}
 	iter := newMVCCIterator(rw, timestamp.IsEmpty(), IterOptions{Prefix: true})
 	defer iter.Close()
 
@@ -1914,6 +1970,9 @@ func MVCCInitPut(
 	failOnTombstones bool,
 	txn *roachpb.Transaction,
 ) error {
+	if err := mvccCheckForConflictingReplicatedLocks(rw, key, timestamp, txn); err != nil {
+		return err
+	}
 	iter := newMVCCIterator(rw, timestamp.IsEmpty(), IterOptions{Prefix: true})
 	defer iter.Close()
 	return mvccInitPutUsingIter(ctx, rw, iter, ms, key, timestamp, value, failOnTombstones, txn)
@@ -2258,6 +2317,9 @@ func MVCCDeleteRange(
 
 	var keys []roachpb.Key
 	for i, kv := range res.KVs {
+		if err := mvccCheckForConflictingReplicatedLocks(rw, kv.Key, timestamp, txn); err != nil {
+			return nil, nil, 0, err
+		}
 		if err := mvccPutInternal(ctx, rw, iter, ms, kv.Key, timestamp, nil, txn, buf, nil); err != nil {
 			return nil, nil, 0, err
 		}
@@ -2303,6 +2365,8 @@ func mvccScanToBytes(
 		inconsistent:     opts.Inconsistent,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		skipLocked:       opts.SkipLocked,
+		lockTable:        opts.LockTable,
 		keyBuf:           mvccScanner.keyBuf,
 	}
 
@@ -2390,6 +2454,47 @@ func buildScanIntents(data []byte) ([]roachpb.Intent, error) {
 	return intents, nil
 }
 
+// ScanIntents returns the intents in the key span [start, end) without
+// reading any committed values. The scan stops once maxIntents intents have
+// been found or the key and value bytes of the intents found reach
+// targetBytes, in which case the key at which the scan stopped is returned as
+// the resume key. A zero limit is treated as no limit.
+func ScanIntents(
+	_ context.Context, reader Reader, start, end roachpb.Key, maxIntents int64, targetBytes int64,
+) (intents []roachpb.Intent, resumeKey roachpb.Key, err error) {
+	it := reader.NewMVCCIterator(MVCCKeyAndIntentsIterKind, IterOptions{LowerBound: start, UpperBound: end})
+	defer it.Close()
+
+	var meta enginepb.MVCCMetadata
+	var intentBytes int64
+	// Iterate through all keys using NextKey. This only looks at the first
+	// MVCC version of each key, which is the metadata key if the key has an
+	// intent.
+	for it.SeekGE(MakeMVCCMetadataKey(start)); ; it.NextKey() {
+		if ok, err := it.Valid(); err != nil {
+			return nil, nil, err
+		} else if !ok {
+			return intents, nil, nil
+		}
+		unsafeKey := it.UnsafeKey()
+		if unsafeKey.IsValue() {
+			continue
+		}
+		if (maxIntents > 0 && int64(len(intents)) >= maxIntents) ||
+			(targetBytes > 0 && intentBytes >= targetBytes) {
+			return intents, append(roachpb.Key(nil), unsafeKey.Key...), nil
+		}
+		if err := protoutil.Unmarshal(it.UnsafeValue(), &meta); err != nil {
+			return nil, nil, errors.Wrapf(err, "unmarshaling mvcc meta: %v", unsafeKey)
+		}
+		if meta.Txn == nil {
+			continue
+		}
+		intents = append(intents, roachpb.MakeIntent(meta.Txn, append(roachpb.Key(nil), unsafeKey.Key...)))
+		intentBytes += int64(len(unsafeKey.Key) + len(it.UnsafeValue()))
+	}
+}
+
 // MVCCScanOptions bundles options for the MVCCScan family of functions.
 type MVCCScanOptions struct {
 	// See the documentation for MVCCScan for information on these parameters.
@@ -2416,6 +2521,14 @@ type MVCCScanOptions struct {
 	//
 	// The zero value indicates no limit.
 	TargetBytes int64
+	// SkipLocked causes the scan to skip over keys that are locked by
+	// conflicting transactions, either with replicated intents or with locks
+	// in LockTable, instead of returning an error. Keys that are skipped are
+	// omitted from the result set.
+	SkipLocked bool
+	// LockTable is consulted by SkipLocked scans to determine which keys are
+	// locked with unreplicated locks. Must be set if SkipLocked is.
+	LockTable LockTableView
 }
 
 func (opts *MVCCScanOptions) validate() error {
@@ -2425,6 +2538,12 @@ func (opts *MVCCScanOptions) validate() error {
 	if opts.Inconsistent && opts.FailOnMoreRecent {
 		return errors.Errorf("cannot allow inconsistent reads with fail on more recent option")
 	}
+	if opts.Inconsistent && opts.SkipLocked {
+		return errors.Errorf("cannot allow inconsistent reads with skip locked option")
+	}
+	if opts.SkipLocked && opts.LockTable == nil {
+		return errors.Errorf("cannot allow skip locked option without a lock table")
+	}
 	return nil
 }
 
@@ -2475,6 +2594,13 @@ type MVCCScanResult struct {
 // the read timestamp, the maximum will be returned in the WriteTooOldError.
 // Similarly, a WriteIntentError will be returned if the scan observes another
 // transaction's intent, even if it has a timestamp above the read timestamp.
+//
+// When scanning in "skip locked" mode, keys that are locked by transactions
+// other than the reader are not included in the result set and do not result
+// in a WriteIntentError. Keys are considered locked if they have a conflicting
+// intent or if the lock table view reports them as locked. The remaining keys
+// are returned as of the read timestamp, so the result set is consistent with
+// the reader's snapshot.
 func MVCCScan(
 	ctx context.Context,
 	reader Reader,
@@ -2632,7 +2758,11 @@ func MVCCResolveWriteIntentUsingIter(
 	if len(intent.EndKey) > 0 {
 		return false, errors.Errorf("can't resolve range intent as point intent")
 	}
-	return mvccResolveWriteIntent(ctx, rw, iterAndBuf.iter, ms, intent, iterAndBuf.buf)
+	ok, err := mvccResolveWriteIntent(ctx, rw, iterAndBuf.iter, ms, intent, iterAndBuf.buf)
+	if err != nil {
+		return false, err
+	}
+	return ok, mvccReleaseReplicatedLocks(rw, intent, intent.Key, nil /* end */)
 }
 
 // unsafeNextVersion positions the iterator at the successor to latestKey. If this value
@@ -3054,10 +3184,14 @@ func MVCCResolveWriteIntentRangeUsingIter(
 
 	var keyBuf []byte
 	num := int64(0)
+	update := intent
 	intent.EndKey = nil
 
 	for {
 		if max > 0 && num == max {
+			if err := mvccReleaseReplicatedLocks(rw, update, encKey.Key, nextKey.Key); err != nil {
+				return 0, nil, err
+			}
 			return num, &roachpb.Span{Key: nextKey.Key, EndKey: encEndKey.Key}, nil
 		}
 
@@ -3096,9 +3230,235 @@ func MVCCResolveWriteIntentRangeUsingIter(
 		}
 	}
 
+	if err := mvccReleaseReplicatedLocks(rw, update, encKey.Key, encEndKey.Key); err != nil {
+		return 0, nil, err
+	}
 	return num, nil, nil
 }
 
+// MVCCAcquireLock acquires a replicated lock of the specified strength on the
+// key for the transaction. Unlike an intent, a replicated lock is not
+// accompanied by a provisional value: it is stored only in the lock table key
+// space, so it is invisible to non-locking readers, but it survives lease
+// transfers and leaseholder crashes. Replicated locks are released by intent
+// resolution once the transaction is finalized or moves to a new epoch.
+//
+// Shared locks are compatible with the Shared locks held by other
+// transactions. All other combinations of replicated locks held by different
+// transactions conflict, in which case a WriteIntentError for the conflicting
+// lock is returned. The caller is expected to have already checked for
+// conflicting intents on the key, e.g. by scanning it with FailOnMoreRecent.
+//
+// Acquiring a lock that the transaction already holds, at the same or a
+// stronger strength and in its current epoch, is a no-op. Like the rest of the
+// lock table key space, replicated locks are not accounted for in MVCCStats.
+func MVCCAcquireLock(
+	ctx context.Context, rw ReadWriter, txn *roachpb.Transaction, str lock.Strength, key roachpb.Key,
+) error {
+	if len(key) == 0 {
+		return emptyKeyError()
+	}
+	if txn == nil {
+		return errors.Errorf("cannot acquire lock on %q outside of a transaction", key)
+	}
+	if str != lock.Shared && str != lock.Exclusive {
+		return errors.AssertionFailedf("cannot acquire replicated lock with strength %s", str)
+	}
+
+	var alreadyHeld bool
+	var staleLocks []EngineKey
+	var conflict *roachpb.Intent
+	if err := mvccIterateReplicatedLocks(rw, key, func(
+		engineKey EngineKey, ltKey LockTableKey, meta *enginepb.MVCCMetadata,
+	) (stop bool) {
+		if meta.Txn.ID == txn.ID {
+			if meta.Txn.Epoch < txn.Epoch {
+				// The lock was acquired in a previous epoch. Replace it.
+				staleLocks = append(staleLocks, engineKey)
+			} else if ltKey.Strength >= str {
+				alreadyHeld = true
+			}
+			return false
+		}
+		if str == lock.Shared && ltKey.Strength == lock.Shared {
+			return false
+		}
+		intent := roachpb.MakeIntent(meta.Txn, key)
+		intent.Strength = ltKey.Strength
+		conflict = &intent
+		return true
+	}); err != nil {
+		return err
+	}
+	if conflict != nil {
+		return &roachpb.WriteIntentError{Intents: []roachpb.Intent{*conflict}}
+	}
+	for _, engineKey := range staleLocks {
+		if err := rw.ClearEngineKey(engineKey); err != nil {
+			return err
+		}
+	}
+	if alreadyHeld {
+		return nil
+	}
+
+	meta := enginepb.MVCCMetadata{
+		Txn:       &txn.TxnMeta,
+		Timestamp: txn.WriteTimestamp.ToLegacyTimestamp(),
+	}
+	metaBytes, err := protoutil.Marshal(&meta)
+	if err != nil {
+		return err
+	}
+	engineKey, _ := LockTableKey{
+		Key:      key,
+		Strength: str,
+		TxnUUID:  txn.ID.GetBytes(),
+	}.ToEngineKey(nil)
+	return rw.PutEngineKey(engineKey, metaBytes)
+}
+
+// mvccCheckForConflictingReplicatedLocks returns a WriteIntentError if a
+// transaction other than txn holds a replicated Shared or Exclusive lock on
+// the key. Writes conflict with replicated locks of all strengths. Conflicts
+// with intents are detected separately, when the key's metadata is read.
+// Inline writes (with an empty timestamp) are never checked, since inline
+// values are not transactional and so cannot be locked. Neither are writes
+// through readers that know that no replicated locks can have been acquired.
+func mvccCheckForConflictingReplicatedLocks(
+	reader Reader, key roachpb.Key, timestamp hlc.Timestamp, txn *roachpb.Transaction,
+) error {
+	if timestamp.IsEmpty() {
+		return nil
+	}
+	if r, ok := reader.(replicatedLocksReader); ok && !r.replicatedLocksMayExist() {
+		return nil
+	}
+	var conflict *roachpb.Intent
+	if err := mvccIterateReplicatedLocks(reader, key, func(
+		_ EngineKey, ltKey LockTableKey, meta *enginepb.MVCCMetadata,
+	) (stop bool) {
+		if txn != nil && meta.Txn.ID == txn.ID {
+			return false
+		}
+		intent := roachpb.MakeIntent(meta.Txn, key)
+		intent.Strength = ltKey.Strength
+		conflict = &intent
+		return true
+	}); err != nil {
+		return err
+	}
+	if conflict != nil {
+		return &roachpb.WriteIntentError{Intents: []roachpb.Intent{*conflict}}
+	}
+	return nil
+}
+
+// replicatedLocksReader is implemented by Readers that can tell whether
+// replicated Shared or Exclusive locks may have been acquired. Readers that
+// don't implement it are always checked for conflicting replicated locks.
+type replicatedLocksReader interface {
+	replicatedLocksMayExist() bool
+}
+
+// replicatedLocksMayExist returns false if the cluster version or the
+// ReplicatedLockingEnabled setting prevent locking reads from acquiring
+// replicated locks. A nil settings is taken to allow them.
+func replicatedLocksMayExist(st *cluster.Settings) bool {
+	if st == nil {
+		return true
+	}
+	return ReplicatedLockingEnabled.Get(&st.SV) &&
+		st.Version.IsActive(context.Background(), clusterversion.ReplicatedLocks)
+}
+
+// mvccIterateReplicatedLocks calls fn for each replicated lock on the key
+// that is not an intent, until fn returns true. The EngineKey and
+// LockTableKey passed to fn are only valid for the duration of the call.
+func mvccIterateReplicatedLocks(
+	reader Reader,
+	key roachpb.Key,
+	fn func(EngineKey, LockTableKey, *enginepb.MVCCMetadata) (stop bool),
+) error {
+	ltStart, _ := keys.LockTableSingleKey(key, nil)
+	iter := reader.NewEngineIterator(IterOptions{Prefix: true, LowerBound: ltStart})
+	defer iter.Close()
+	return mvccIterateReplicatedLocksUsingIter(iter, ltStart, fn)
+}
+
+func mvccIterateReplicatedLocksUsingIter(
+	iter EngineIterator,
+	ltStart roachpb.Key,
+	fn func(EngineKey, LockTableKey, *enginepb.MVCCMetadata) (stop bool),
+) error {
+	valid, err := iter.SeekEngineKeyGE(EngineKey{Key: ltStart})
+	for ; valid; valid, err = iter.NextEngineKey() {
+		engineKey, err := iter.EngineKey()
+		if err != nil {
+			return err
+		}
+		ltKey, err := engineKey.ToLockTableKey()
+		if err != nil {
+			return err
+		}
+		if ltKey.Strength == lock.Intent {
+			continue
+		}
+		var meta enginepb.MVCCMetadata
+		if err := protoutil.Unmarshal(iter.UnsafeValue(), &meta); err != nil {
+			return err
+		}
+		if meta.Txn == nil {
+			return errors.AssertionFailedf("replicated lock on %q without transaction", ltKey.Key)
+		}
+		if fn(engineKey, ltKey, &meta) {
+			return nil
+		}
+	}
+	return err
+}
+
+// mvccReleaseReplicatedLocks releases the replicated locks, other than
+// intents, held by the updated transaction on the keys in [start, end). If end
+// is nil, only the locks on start are released. Locks are released if the
+// transaction has been finalized. Locks acquired in earlier epochs are also
+// released if the transaction is still pending.
+func mvccReleaseReplicatedLocks(
+	rw ReadWriter, update roachpb.LockUpdate, start, end roachpb.Key,
+) error {
+	ltStart, _ := keys.LockTableSingleKey(start, nil)
+	opts := IterOptions{Prefix: true, LowerBound: ltStart}
+	if end != nil {
+		ltEnd, _ := keys.LockTableSingleKey(end, nil)
+		opts = IterOptions{LowerBound: ltStart, UpperBound: ltEnd}
+	}
+	iter := rw.NewEngineIterator(opts)
+	var toRelease []EngineKey
+	err := mvccIterateReplicatedLocksUsingIter(iter, ltStart, func(
+		engineKey EngineKey, _ LockTableKey, meta *enginepb.MVCCMetadata,
+	) (stop bool) {
+		if meta.Txn.ID != update.Txn.ID {
+			return false
+		}
+		if update.Status == roachpb.PENDING && meta.Txn.Epoch >= update.Txn.Epoch {
+			return false
+		}
+		toRelease = append(toRelease, engineKey)
+		return false
+	})
+	// Close the iterator before writing to rw.
+	iter.Close()
+	if err != nil {
+		return err
+	}
+	for _, engineKey := range toRelease {
+		if err := rw.ClearEngineKey(engineKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // MVCCGarbageCollect creates an iterator on the ReadWriter. In parallel
 // it iterates through the keys listed for garbage collection by the
 // keys slice. The iterator is seeked in turn to each listed
@@ -19,6 +19,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
@@ -47,6 +49,7 @@ import (
 //
 // resolve_intent t=<name> k=<key> [status=<txnstatus>]
 // check_intent   k=<key> [none]
+// acquire_lock   t=<name> k=<key> str=<shared|exclusive>
 //
 // cput      [t=<name>] [ts=<int>[,<int>]] [resolve [status=<txnstatus>]] k=<key> v=<string> [raw] [cond=<string>]
 // del       [t=<name>] [ts=<int>[,<int>]] [resolve [status=<txnstatus>]] k=<key>
@@ -98,21 +101,56 @@ func TestMVCCHistories(t *testing.T) {
 
 				reportDataEntries := func(buf *bytes.Buffer) error {
 					hasData := false
-					err := engine.MVCCIterate(span.Key, span.EndKey, MVCCKeyAndIntentsIterKind, func(r MVCCKeyValue) error {
-						hasData = true
-						if r.Key.Timestamp.IsEmpty() {
-							// Meta is at timestamp zero.
-							meta := enginepb.MVCCMetadata{}
-							if err := protoutil.Unmarshal(r.Value, &meta); err != nil {
-								fmt.Fprintf(buf, "meta: %v -> error decoding proto from %v: %v\n", r.Key, r.Value, err)
+					// The replicated lock table key space is reported separately below.
+					mvccSpans := []roachpb.Span{
+						{Key: span.Key, EndKey: keys.LocalRangeLockTablePrefix},
+						{Key: keys.LocalRangeLockTablePrefix.PrefixEnd(), EndKey: span.EndKey},
+					}
+					for _, sp := range mvccSpans {
+						err := engine.MVCCIterate(sp.Key, sp.EndKey, MVCCKeyAndIntentsIterKind, func(r MVCCKeyValue) error {
+							hasData = true
+							if r.Key.Timestamp.IsEmpty() {
+								// Meta is at timestamp zero.
+								meta := enginepb.MVCCMetadata{}
+								if err := protoutil.Unmarshal(r.Value, &meta); err != nil {
+									fmt.Fprintf(buf, "meta: %v -> error decoding proto from %v: %v\n", r.Key, r.Value, err)
+								} else {
+									fmt.Fprintf(buf, "meta: %v -> %+v\n", r.Key, &meta)
+								}
 							} else {
-								fmt.Fprintf(buf, "meta: %v -> %+v\n", r.Key, &meta)
+								fmt.Fprintf(buf, "data: %v -> %s\n", r.Key, roachpb.Value{RawBytes: r.Value}.PrettyPrint())
 							}
-						} else {
-							fmt.Fprintf(buf, "data: %v -> %s\n", r.Key, roachpb.Value{RawBytes: r.Value}.PrettyPrint())
+							return nil
+						})
+						if err != nil {
+							return err
+						}
+					}
+					ltStart, _ := keys.LockTableSingleKey(span.Key, nil)
+					ltEnd, _ := keys.LockTableSingleKey(span.EndKey, nil)
+					iter := engine.NewEngineIterator(IterOptions{LowerBound: ltStart, UpperBound: ltEnd})
+					defer iter.Close()
+					valid, err := iter.SeekEngineKeyGE(EngineKey{Key: ltStart})
+					for ; valid; valid, err = iter.NextEngineKey() {
+						key, err := iter.EngineKey()
+						if err != nil {
+							return err
+						}
+						ltKey, err := key.ToLockTableKey()
+						if err != nil {
+							return err
+						}
+						if ltKey.Strength == lock.Intent {
+							// Separated intents were already reported above.
+							continue
+						}
+						hasData = true
+						meta := enginepb.MVCCMetadata{}
+						if err := protoutil.Unmarshal(iter.UnsafeValue(), &meta); err != nil {
+							return err
 						}
-						return nil
-					})
+						fmt.Fprintf(buf, "lock (%s): %v -> %+v\n", ltKey.Strength, ltKey.Key, &meta)
+					}
 					if !hasData {
 						buf.WriteString("<no data>\n")
 					}
@@ -382,6 +420,7 @@ var commands = map[string]cmd{
 	"resolve_intent": {typDataUpdate, cmdResolveIntent},
 	// TODO(nvanbenschoten): test "resolve_intent_range".
 	"check_intent": {typReadOnly, cmdCheckIntent},
+	"acquire_lock": {typDataUpdate, cmdAcquireLock},
 
 	"clear_range": {typDataUpdate, cmdClearRange},
 	"cput":        {typDataUpdate, cmdCPut},
@@ -570,6 +609,15 @@ func cmdCheckIntent(e *evalCtx) error {
 	return nil
 }
 
+func cmdAcquireLock(e *evalCtx) error {
+	txn := e.getTxn(mandatory)
+	key := e.getKey()
+	str := e.getStrength()
+	return e.withWriter("acquire_lock", func(rw ReadWriter) error {
+		return MVCCAcquireLock(e.ctx, rw, txn, str, key)
+	})
+}
+
 func cmdClearRange(e *evalCtx) error {
 	key, endKey := e.getKeyRange()
 	return e.engine.ClearRawRange(key, endKey)
@@ -833,6 +881,20 @@ func (e *evalCtx) getTxnStatus() roachpb.TransactionStatus {
 	return status
 }
 
+func (e *evalCtx) getStrength() lock.Strength {
+	var strS string
+	e.scanArg("str", &strS)
+	switch strS {
+	case "shared":
+		return lock.Shared
+	case "exclusive":
+		return lock.Exclusive
+	default:
+		e.Fatalf("invalid lock strength: %s", strS)
+		return lock.None
+	}
+}
+
 func (e *evalCtx) scanArg(key string, dests ...interface{}) {
 	e.t.Helper()
 	e.td.ScanArgs(e.t, key, dests...)
@@ -24,7 +24,9 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
@@ -1431,6 +1433,183 @@ func TestMVCCScanInconsistent(t *testing.T) {
 	}
 }
 
+// TestScanIntents verifies that ScanIntents returns the intents in a span and
+// stops at the intent and byte limits.
+func TestScanIntents(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			txn1ts := makeTxn(*txn1, hlc.Timestamp{WallTime: 2})
+			txn2ts := makeTxn(*txn2, hlc.Timestamp{WallTime: 3})
+			require.NoError(t, MVCCPut(ctx, engine, nil, testKey1, hlc.Timestamp{WallTime: 1}, value1, nil))
+			require.NoError(t, MVCCPut(ctx, engine, nil, testKey1, txn1ts.ReadTimestamp, value2, txn1ts))
+			require.NoError(t, MVCCPut(ctx, engine, nil, testKey2, hlc.Timestamp{WallTime: 1}, value2, nil))
+			require.NoError(t, MVCCPut(ctx, engine, nil, testKey3, txn2ts.ReadTimestamp, value3, txn2ts))
+			require.NoError(t, MVCCPut(ctx, engine, nil, testKey4, hlc.Timestamp{WallTime: 1}, value4, nil))
+
+			expIntents := []roachpb.Intent{
+				roachpb.MakeIntent(&txn1ts.TxnMeta, testKey1),
+				roachpb.MakeIntent(&txn2ts.TxnMeta, testKey3),
+			}
+			intents, resumeKey, err := ScanIntents(ctx, engine, testKey1, testKey4.Next(), 0, 0)
+			require.NoError(t, err)
+			require.Nil(t, resumeKey)
+			require.Equal(t, expIntents, intents)
+
+			// The scan stops at the next intent once the limit is reached.
+			intents, resumeKey, err = ScanIntents(ctx, engine, testKey1, testKey4.Next(), 1, 0)
+			require.NoError(t, err)
+			require.Equal(t, testKey3, resumeKey)
+			require.Equal(t, expIntents[:1], intents)
+
+			intents, resumeKey, err = ScanIntents(ctx, engine, testKey1, testKey4.Next(), 0, 1)
+			require.NoError(t, err)
+			require.Equal(t, testKey3, resumeKey)
+			require.Equal(t, expIntents[:1], intents)
+
+			// A limit that is not reached does not stop the scan.
+			intents, resumeKey, err = ScanIntents(ctx, engine, testKey2, testKey4.Next(), 1, 0)
+			require.NoError(t, err)
+			require.Nil(t, resumeKey)
+			require.Equal(t, expIntents[1:], intents)
+		})
+	}
+}
+
+// TestMVCCPutReplicatedLocksGatedOnSetting verifies that writes only check for
+// conflicting replicated locks while replicated locking is enabled.
+func TestMVCCPutReplicatedLocksGatedOnSetting(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	testutils.RunTrueAndFalse(t, "enabled", func(t *testing.T, enabled bool) {
+		st := cluster.MakeTestingClusterSettings()
+		ReplicatedLockingEnabled.Override(&st.SV, enabled)
+		engine := newPebbleInMem(ctx, roachpb.Attributes{}, 1<<20, st)
+		defer engine.Close()
+
+		txn1ts := makeTxn(*txn1, hlc.Timestamp{WallTime: 1})
+		txn2ts := makeTxn(*txn2, hlc.Timestamp{WallTime: 1})
+		require.NoError(t, MVCCAcquireLock(ctx, engine, txn1ts, lock.Exclusive, testKey1))
+
+		batch := engine.NewBatch()
+		defer batch.Close()
+		err := MVCCPut(ctx, batch, nil, testKey1, txn2ts.ReadTimestamp, value1, txn2ts)
+		if enabled {
+			require.IsType(t, &roachpb.WriteIntentError{}, err)
+		} else {
+			require.NoError(t, err)
+		}
+	})
+}
+
+// mockLockTableView is a LockTableView that reports a fixed set of keys as
+// locked by a conflicting transaction.
+type mockLockTableView map[string]*enginepb.TxnMeta
+
+func (m mockLockTableView) IsKeyLockedByConflictingTxn(
+	key roachpb.Key, _ lock.Strength,
+) (bool, *enginepb.TxnMeta) {
+	holder, ok := m[string(key)]
+	return ok, holder
+}
+
+// TestMVCCScanSkipLocked verifies that a scan with the SkipLocked option omits
+// keys with intents written by other transactions and keys that are locked in
+// the lock table, while still returning keys written by its own transaction.
+func TestMVCCScanSkipLocked(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			ts1 := hlc.Timestamp{WallTime: 1}
+			ts2 := hlc.Timestamp{WallTime: 2}
+			if err := MVCCPut(ctx, engine, nil, testKey1, ts1, value1, nil); err != nil {
+				t.Fatal(err)
+			}
+			txn2ts1 := makeTxn(*txn2, ts1)
+			if err := MVCCPut(ctx, engine, nil, testKey2, txn2ts1.ReadTimestamp, value2, txn2ts1); err != nil {
+				t.Fatal(err)
+			}
+			if err := MVCCPut(ctx, engine, nil, testKey3, ts1, value3, nil); err != nil {
+				t.Fatal(err)
+			}
+			txn1ts2 := makeTxn(*txn1, ts2)
+			if err := MVCCPut(ctx, engine, nil, testKey4, txn1ts2.ReadTimestamp, value4, txn1ts2); err != nil {
+				t.Fatal(err)
+			}
+			// testKey3 is locked by txn2 with an unreplicated lock, which is only
+			// visible through the lock table.
+			lockTable := mockLockTableView{string(testKey3): &txn2ts1.TxnMeta}
+
+			// SkipLocked requires a lock table.
+			if _, err := MVCCScan(
+				ctx, engine, keyMin, keyMax, ts2,
+				MVCCScanOptions{Txn: txn1ts2, SkipLocked: true},
+			); !testutils.IsError(err, "skip locked option without a lock table") {
+				t.Fatalf("expected error scanning with SkipLocked without LockTable, found %v", err)
+			}
+
+			for _, failOnMoreRecent := range []bool{false, true} {
+				for _, reverse := range []bool{false, true} {
+					res, err := MVCCScan(ctx, engine, keyMin, keyMax, ts2, MVCCScanOptions{
+						Txn:              txn1ts2,
+						Reverse:          reverse,
+						FailOnMoreRecent: failOnMoreRecent,
+						SkipLocked:       true,
+						LockTable:        lockTable,
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					expKeys := []roachpb.Key{testKey1, testKey4}
+					if reverse {
+						expKeys = []roachpb.Key{testKey4, testKey1}
+					}
+					if len(res.KVs) != len(expKeys) {
+						t.Fatalf("expected %d keys, found %v", len(expKeys), res.KVs)
+					}
+					for i, kv := range res.KVs {
+						if !kv.Key.Equal(expKeys[i]) {
+							t.Errorf("%d: expected key %s, found %s", i, expKeys[i], kv.Key)
+						}
+					}
+					if len(res.Intents) != 0 {
+						t.Errorf("expected no intents, found %v", res.Intents)
+					}
+				}
+			}
+
+			// Gets behave in the same way.
+			for _, key := range []roachpb.Key{testKey2, testKey3} {
+				val, intent, err := MVCCGet(ctx, engine, key, ts2, MVCCGetOptions{
+					Txn:        txn1ts2,
+					SkipLocked: true,
+					LockTable:  lockTable,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if val != nil || intent != nil {
+					t.Errorf("expected %s to be skipped, found value %v, intent %v", key, val, intent)
+				}
+			}
+		})
+	}
+}
+
 func TestMVCCDeleteRange(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -646,6 +646,11 @@ func (p *Pebble) Closed() bool {
 	return p.closed
 }
 
+// replicatedLocksMayExist implements the replicatedLocksReader interface.
+func (p *Pebble) replicatedLocksMayExist() bool {
+	return replicatedLocksMayExist(p.settings)
+}
+
 // ExportMVCCToSst is part of the engine.Reader interface.
 func (p *Pebble) ExportMVCCToSst(
 	startKey, endKey roachpb.Key,
@@ -942,6 +947,7 @@ func (p *Pebble) GetMetrics() (*Metrics, error) {
 		L0SublevelCount:                int64(m.Levels[0].Sublevels),
 		ReadAmplification:              int64(m.ReadAmp()),
 		NumSSTables:                    numSSTables,
+		Pebble:                         m,
 	}, nil
 }
 
@@ -1038,7 +1044,7 @@ func (p *Pebble) GetAuxiliaryDir() string {
 
 // NewBatch implements the Engine interface.
 func (p *Pebble) NewBatch() Batch {
-	return newPebbleBatch(p.db, p.db.NewIndexedBatch())
+	return newPebbleBatch(p.db, p.db.NewIndexedBatch(), p.settings)
 }
 
 // NewReadOnly implements the Engine interface.
@@ -1050,7 +1056,7 @@ func (p *Pebble) NewReadOnly() ReadWriter {
 
 // NewWriteOnlyBatch implements the Engine interface.
 func (p *Pebble) NewWriteOnlyBatch() Batch {
-	return newPebbleBatch(p.db, p.db.NewBatch())
+	return newPebbleBatch(p.db, p.db.NewBatch(), p.settings)
 }
 
 // NewSnapshot implements the Engine interface.
@@ -1231,6 +1237,11 @@ func (p *pebbleReadOnly) Closed() bool {
 	return p.closed
 }
 
+// replicatedLocksMayExist implements the replicatedLocksReader interface.
+func (p *pebbleReadOnly) replicatedLocksMayExist() bool {
+	return p.parent.replicatedLocksMayExist()
+}
+
 // ExportMVCCToSst is part of the engine.Reader interface.
 func (p *pebbleReadOnly) ExportMVCCToSst(
 	startKey, endKey roachpb.Key,
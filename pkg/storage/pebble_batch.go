@@ -14,6 +14,7 @@ import (
 	"sync"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
@@ -23,9 +24,10 @@ import (
 
 // Wrapper struct around a pebble.Batch.
 type pebbleBatch struct {
-	db    *pebble.DB
-	batch *pebble.Batch
-	buf   []byte
+	db       *pebble.DB
+	batch    *pebble.Batch
+	settings *cluster.Settings
+	buf      []byte
 	// The iterator reuse optimization in pebbleBatch is for servicing a
 	// BatchRequest, such that the iterators get reused across different
 	// requests in the batch.
@@ -57,12 +59,15 @@ var pebbleBatchPool = sync.Pool{
 }
 
 // Instantiates a new pebbleBatch.
-func newPebbleBatch(db *pebble.DB, batch *pebble.Batch) *pebbleBatch {
+func newPebbleBatch(
+	db *pebble.DB, batch *pebble.Batch, settings *cluster.Settings,
+) *pebbleBatch {
 	pb := pebbleBatchPool.Get().(*pebbleBatch)
 	*pb = pebbleBatch{
-		db:    db,
-		batch: batch,
-		buf:   pb.buf,
+		db:       db,
+		batch:    batch,
+		settings: settings,
+		buf:      pb.buf,
 		prefixIter: pebbleIterator{
 			lowerBoundBuf: pb.prefixIter.lowerBoundBuf,
 			upperBoundBuf: pb.prefixIter.upperBoundBuf,
@@ -117,6 +122,11 @@ func (p *pebbleBatch) Closed() bool {
 	return p.closed
 }
 
+// replicatedLocksMayExist implements the replicatedLocksReader interface.
+func (p *pebbleBatch) replicatedLocksMayExist() bool {
+	return replicatedLocksMayExist(p.settings)
+}
+
 // ExportMVCCToSst is part of the engine.Reader interface.
 func (p *pebbleBatch) ExportMVCCToSst(
 	startKey, endKey roachpb.Key,
@@ -508,7 +518,7 @@ func (p *pebbleBatch) Distinct() ReadWriter {
 	// optimization. In Pebble we're still using the same underlying batch and if
 	// it is indexed we'll still be indexing it as we Go.
 	p.distinctOpen = true
-	d := newPebbleBatch(p.db, p.batch)
+	d := newPebbleBatch(p.db, p.batch, p.settings)
 	d.parentBatch = p
 	d.isDistinct = true
 	return d
@@ -16,6 +16,7 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -111,11 +112,14 @@ type pebbleMVCCScanner struct {
 	txnIgnoredSeqNums []enginepb.IgnoredSeqNumRange
 	// Metadata object for unmarshalling intents.
 	meta enginepb.MVCCMetadata
+	// View into the in-memory lock table, consulted when skipLocked is set.
+	lockTable LockTableView
 	// Bools copied over from MVCC{Scan,Get}Options. See the comment on the
 	// package level MVCCScan for what these mean.
 	inconsistent, tombstones bool
 	failOnMoreRecent         bool
 	checkUncertainty         bool
+	skipLocked               bool
 	isGet                    bool
 	keyBuf                   []byte
 	savedBuf                 []byte
@@ -296,6 +300,12 @@ func (p *pebbleMVCCScanner) getAndAdvance() bool {
 		// ts == read_ts
 		if p.curKey.Timestamp.EqOrdering(p.ts) {
 			if p.failOnMoreRecent {
+				if p.skipLocked && p.isKeyLockedByConflictingTxn() {
+					// 2a. The scanner has been configured to skip locked keys and
+					// this key is locked by a conflicting transaction, so we can
+					// skip it without raising a write too old error.
+					return p.advanceKey()
+				}
 				// 2. Our txn's read timestamp is equal to the most recent
 				// version's timestamp and the scanner has been configured to
 				// throw a write too old error on equal or more recent versions.
@@ -313,6 +323,12 @@ func (p *pebbleMVCCScanner) getAndAdvance() bool {
 
 		// ts > read_ts
 		if p.failOnMoreRecent {
+			if p.skipLocked && p.isKeyLockedByConflictingTxn() {
+				// 4a. The scanner has been configured to skip locked keys and
+				// this key is locked by a conflicting transaction, so we can
+				// skip it without raising a write too old error.
+				return p.advanceKey()
+			}
 			// 4. Our txn's read timestamp is less than the most recent
 			// version's timestamp and the scanner has been configured to
 			// throw a write too old error on equal or more recent versions.
@@ -424,6 +440,12 @@ func (p *pebbleMVCCScanner) getAndAdvance() bool {
 		// Note that this will trigger an error higher up the stack. We
 		// continue scanning so that we can return all of the intents
 		// in the scan range.
+		//
+		// If the scanner has been configured to skip locked keys, the
+		// intent is not returned and the key is skipped instead.
+		if p.skipLocked {
+			return p.advanceKey()
+		}
 		p.err = p.intents.Set(p.curRawKey, p.curValue, nil)
 		if p.err != nil {
 			return false
@@ -605,6 +627,13 @@ func (p *pebbleMVCCScanner) addAndAdvance(rawKey []byte, val []byte) bool {
 	// Don't include deleted versions len(val) == 0, unless we've been instructed
 	// to include tombstones in the results.
 	if len(val) > 0 || p.tombstones {
+		// If the scanner has been configured to skip locked keys, don't
+		// include keys that are locked with unreplicated locks in the result
+		// set. Replicated locks are represented as intents, which are skipped
+		// over in getAndAdvance.
+		if p.skipLocked && p.isKeyLockedByConflictingTxn() {
+			return p.advanceKey()
+		}
 		p.results.put(rawKey, val)
 		if p.targetBytes > 0 && p.results.bytes >= p.targetBytes {
 			// When the target bytes are met or exceeded, stop producing more
@@ -621,6 +650,19 @@ func (p *pebbleMVCCScanner) addAndAdvance(rawKey []byte, val []byte) bool {
 	return p.advanceKey()
 }
 
+// isKeyLockedByConflictingTxn consults the lock table view to determine
+// whether the current key is locked by a transaction that conflicts with the
+// scan. Scans that fail on more recent writes are locking reads, so they also
+// conflict with reservations held by other transactions.
+func (p *pebbleMVCCScanner) isKeyLockedByConflictingTxn() bool {
+	strength := lock.None
+	if p.failOnMoreRecent {
+		strength = lock.Exclusive
+	}
+	locked, _ := p.lockTable.IsKeyLockedByConflictingTxn(p.curKey.Key, strength)
+	return locked
+}
+
 // Seeks to the latest revision of the current key that's still less than or
 // equal to the specified timestamp, adds it to the result set, then moves onto
 // the next user key.
@@ -0,0 +1,25 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package streaming contains the hooks through which OSS code reaches the
+// CCL cluster-to-cluster replication implementation.
+package streaming
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// CompleteIngestionHook is the hook run by the
+// crdb_internal.complete_stream_ingestion_job builtin. It signals a running
+// stream ingestion job to stop ingesting once it has caught up to the given
+// cutover timestamp, revert any data newer than that timestamp, and complete.
+var CompleteIngestionHook func(*tree.EvalContext, *kv.Txn, int64, hlc.Timestamp) error
@@ -30,6 +30,62 @@ package catalog
 //		(chartDefaultsPerMetricType).
 
 var charts = []sectionDescription{
+	{
+		Organization: [][]string{{Process, "Admission Control"}},
+		Charts: []chartDescription{
+			{
+				Title: "Requests",
+				Metrics: []string{
+					"admission.requested.kv",
+					"admission.admitted.kv",
+					"admission.errored.kv",
+					"admission.requested.sql-kv-response",
+					"admission.admitted.sql-kv-response",
+					"admission.errored.sql-kv-response",
+				},
+				AxisLabel: "Requests",
+			},
+			{
+				Title: "Wait Sum",
+				Metrics: []string{
+					"admission.wait_sum.kv",
+					"admission.wait_sum.sql-kv-response",
+				},
+			},
+			{
+				Title:   "Wait Durations KV",
+				Metrics: []string{"admission.wait_durations.kv"},
+			},
+			{
+				Title:   "Wait Durations SQL KV Response",
+				Metrics: []string{"admission.wait_durations.sql-kv-response"},
+			},
+			{
+				Title: "Wait Queue Length",
+				Metrics: []string{
+					"admission.wait_queue_length.kv",
+					"admission.wait_queue_length.sql-kv-response",
+				},
+				AxisLabel: "Requests",
+			},
+			{
+				Title: "KV Slots",
+				Metrics: []string{
+					"admission.granter.total_slots.kv",
+					"admission.granter.used_slots.kv",
+				},
+				AxisLabel: "Slots",
+			},
+			{
+				Title:   "KV IO Tokens Exhausted Duration",
+				Metrics: []string{"admission.granter.io_tokens_exhausted_duration.kv"},
+			},
+			{
+				Title:   "SQL KV Response Burst Tokens",
+				Metrics: []string{"admission.granter.max_burst_tokens.sql-kv-response"},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{Process, "Build Info"}},
 		Charts: []chartDescription{
@@ -68,6 +124,12 @@ var charts = []sectionDescription{
 				Aggregator:  DescribeAggregator_MAX,
 				Metrics:     []string{"security.certificate.expiration.node-client"},
 			},
+			{
+				Title:       "Client Cert Expiration",
+				Downsampler: DescribeAggregator_MAX,
+				Aggregator:  DescribeAggregator_MAX,
+				Metrics:     []string{"security.certificate.expiration.client"},
+			},
 			{
 				Title:       "UI Cert Expiration",
 				Downsampler: DescribeAggregator_MAX,
@@ -529,6 +591,10 @@ var charts = []sectionDescription{
 			{DistributionLayer, "Rebalancing"},
 		},
 		Charts: []chartDescription{
+			{
+				Title:   "Wall Time",
+				Metrics: []string{"rebalancing.wallnanospersecond"},
+			},
 			{
 				Title:   "QPS",
 				Metrics: []string{"rebalancing.queriespersecond"},
@@ -772,6 +838,48 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{
+			{KVTransactionLayer, "Requests", "Tenant Consumption"}},
+		Charts: []chartDescription{
+			{
+				Title:       "Request Units Consumed",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"tenant.consumption.request_units"},
+			},
+			{
+				Title:       "Read Requests",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"tenant.consumption.read_requests"},
+			},
+			{
+				Title:       "Read Bytes",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"tenant.consumption.read_bytes"},
+			},
+			{
+				Title:       "Write Requests",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"tenant.consumption.write_requests"},
+			},
+			{
+				Title:       "Write Bytes",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"tenant.consumption.write_bytes"},
+			},
+			{
+				Title:       "SQL Pods CPU Seconds",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"tenant.consumption.sql_pods_cpu_seconds"},
+			},
+		},
+	},
 	{
 		Organization: [][]string{
 			{KVTransactionLayer, "Requests", "Slow"},
@@ -2278,6 +2386,7 @@ var charts = []sectionDescription{
 					"jobs.restore.currently_running",
 					"jobs.schema_change.currently_running",
 					"jobs.schema_change_gc.currently_running",
+					"jobs.stream_ingestion.currently_running",
 					"jobs.typedesc_schema_change.currently_running",
 				},
 			},
@@ -2377,6 +2486,18 @@ var charts = []sectionDescription{
 				},
 				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
 			},
+			{
+				Title: "Stream Ingestion",
+				Metrics: []string{
+					"jobs.stream_ingestion.fail_or_cancel_completed",
+					"jobs.stream_ingestion.fail_or_cancel_failed",
+					"jobs.stream_ingestion.fail_or_cancel_retry_error",
+					"jobs.stream_ingestion.resume_completed",
+					"jobs.stream_ingestion.resume_failed",
+					"jobs.stream_ingestion.resume_retry_error",
+				},
+				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
+			},
 			{
 				Title: "Type Descriptor Change",
 				Metrics: []string{
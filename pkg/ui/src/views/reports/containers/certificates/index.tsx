@@ -108,6 +108,7 @@ export class Certificates extends React.Component<CertificatesProps, {}> {
   renderFields(fields: protos.cockroach.server.serverpb.CertificateDetails.IFields, id: number) {
     return [
       this.renderSimpleRow("Cert ID", id.toString()),
+      this.renderSimpleRow("Serial Number", fields.serial_number),
       this.renderSimpleRow("Issuer", fields.issuer),
       this.renderSimpleRow("Subject", fields.subject),
       this.renderTimestampRow("Valid From", fields.valid_from),
@@ -0,0 +1,40 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package admission contains the admission control subsystem, which protects
+// a node from overload by queueing work before it consumes resources.
+//
+// The subsystem consists of requesters and granters. A requester is a
+// WorkQueue, which queues work of a particular WorkKind, and orders it first
+// across tenants (preferring the tenant that is using the fewest resources)
+// and then within a tenant by priority and creation time. A granter decides
+// when the work can be admitted, based on resource availability. There are two
+// kinds of resources granted:
+//
+// - Slots, which are used for work whose completion is known, and which bound
+//   the concurrency of that work. KVWork uses slots, and the number of slots
+//   is adjusted based on the number of runnable goroutines (see
+//   goschedstats), which is a good signal of CPU overload.
+//
+// - Tokens, which are used for work whose completion is not tracked, and which
+//   bound the rate of that work. SQLKVResponseWork uses tokens that are
+//   refilled at every CPU load sample. Additionally, when the LSM of a store
+//   is unhealthy (too many files or sublevels in L0), KVWork also needs IO
+//   tokens, which are computed from the rate at which bytes are being
+//   compacted out of L0.
+//
+// All the granters of a node are coordinated by a single GrantCoordinator,
+// which grants to waiting work in WorkKind order, so that e.g. SQL response
+// processing is not admitted while KV work is waiting.
+//
+// Lock ordering: the GrantCoordinator calls into a WorkQueue while holding
+// its mutex, so a WorkQueue never calls into its granter while holding its own
+// mutex.
+package admission
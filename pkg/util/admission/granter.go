@@ -0,0 +1,737 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/goschedstats"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
+)
+
+// KVSlotAdjusterOverloadThreshold sets a goroutine runnable threshold at
+// which the CPU will be considered overloaded, when running in a node that
+// executes KV operations.
+var KVSlotAdjusterOverloadThreshold = settings.RegisterPositiveIntSetting(
+	"admission.kv_slot_adjuster.overload_threshold",
+	"when the number of runnable goroutines per CPU is greater than this threshold, the "+
+		"slot adjuster considers the cpu to be overloaded",
+	32)
+
+// L0FileCountOverloadThreshold sets a file count threshold that signals an
+// overloaded level 0 in the LSM.
+var L0FileCountOverloadThreshold = settings.RegisterPositiveIntSetting(
+	"admission.l0_file_count_overload_threshold",
+	"when the L0 file count exceeds this threshold, the store is considered overloaded",
+	1000)
+
+// L0SubLevelCountOverloadThreshold sets a sub-level count threshold that
+// signals an overloaded level 0 in the LSM.
+var L0SubLevelCountOverloadThreshold = settings.RegisterPositiveIntSetting(
+	"admission.l0_sub_level_count_overload_threshold",
+	"when the L0 sub-level count exceeds this threshold, the store is considered overloaded",
+	20)
+
+// granter is paired with a requester in that a requester for a particular
+// WorkKind will interact with a granter. See the package documentation.
+type granter interface {
+	// tryGet is used by a requester to get a slot or token for a piece of work
+	// when it has no waiting requests. It returns false if the work must be
+	// queued.
+	tryGet() bool
+	// returnGrant is called to return a slot after the work is done, or to
+	// return a slot or token that was granted but not used.
+	returnGrant()
+	// tookWithoutPermission informs the granter that a slot or token was
+	// taken unilaterally, without permission.
+	tookWithoutPermission()
+	// tryGrant asks the granter to grant to waiting requests, if it has
+	// available slots or tokens.
+	tryGrant()
+}
+
+// requester is an interface implemented by an object that orders admission
+// work for a particular WorkKind. See WorkQueue for the implementation.
+type requester interface {
+	// hasWaitingRequests returns whether there are any waiting requests.
+	hasWaitingRequests() bool
+	// granted is called by a granter to grant admission to the waiting request
+	// at the head of the queue. It returns false if there was no waiting
+	// request.
+	granted() bool
+	close()
+}
+
+// granterWithLockedCalls is the part of a granter that is called by the
+// GrantCoordinator with its mutex held.
+type granterWithLockedCalls interface {
+	granter
+	tryGetLocked() bool
+	returnGrantLocked()
+	tookWithoutPermissionLocked()
+	getState() string
+}
+
+// unlimitedTokens is used when a resource is not constrained.
+const unlimitedTokens = math.MaxInt64
+
+// kvGranter implements granterWithLockedCalls for KVWork. Admission requires a
+// slot, which bounds the concurrency of KV work, and, when the LSM is
+// overloaded, an IO token.
+type kvGranter struct {
+	coord      *GrantCoordinator
+	usedSlots  int
+	totalSlots int
+	// ioTokensEnabled is false when the LSM is not overloaded, in which case
+	// availableIOTokens is ignored.
+	ioTokensEnabled   bool
+	availableIOTokens int64
+	// ioTokensExhaustedStart is the time at which the IO tokens were
+	// exhausted, or zero if they are available.
+	ioTokensExhaustedStart time.Time
+}
+
+var _ granterWithLockedCalls = &kvGranter{}
+
+func (kg *kvGranter) tryGet() bool {
+	return kg.coord.tryGet(KVWork)
+}
+
+func (kg *kvGranter) returnGrant() {
+	kg.coord.returnGrant(KVWork)
+}
+
+func (kg *kvGranter) tookWithoutPermission() {
+	kg.coord.tookWithoutPermission(KVWork)
+}
+
+func (kg *kvGranter) tryGrant() {
+	kg.coord.tryGrant()
+}
+
+func (kg *kvGranter) tryGetLocked() bool {
+	if kg.usedSlots >= kg.totalSlots {
+		return false
+	}
+	if kg.ioTokensEnabled && kg.availableIOTokens <= 0 {
+		return false
+	}
+	kg.usedSlots++
+	kg.subtractIOTokenLocked()
+	kg.coord.metrics.KVUsedSlots.Update(int64(kg.usedSlots))
+	return true
+}
+
+func (kg *kvGranter) returnGrantLocked() {
+	if kg.usedSlots <= 0 {
+		panic(errors.AssertionFailedf("used slots is %d", kg.usedSlots))
+	}
+	kg.usedSlots--
+	kg.coord.metrics.KVUsedSlots.Update(int64(kg.usedSlots))
+}
+
+func (kg *kvGranter) tookWithoutPermissionLocked() {
+	kg.usedSlots++
+	kg.subtractIOTokenLocked()
+	kg.coord.metrics.KVUsedSlots.Update(int64(kg.usedSlots))
+}
+
+func (kg *kvGranter) subtractIOTokenLocked() {
+	if !kg.ioTokensEnabled {
+		return
+	}
+	kg.availableIOTokens--
+	if kg.availableIOTokens <= 0 && kg.ioTokensExhaustedStart.IsZero() {
+		kg.ioTokensExhaustedStart = kg.coord.timeSource()
+	}
+}
+
+// setAvailableIOTokensLocked sets the IO tokens that are available until the
+// next call. Unused tokens do not carry over, but tokens that were taken
+// without permission beyond the available tokens are deducted.
+func (kg *kvGranter) setAvailableIOTokensLocked(tokens int64) {
+	if tokens == unlimitedTokens {
+		kg.ioTokensEnabled = false
+		kg.availableIOTokens = 0
+	} else {
+		kg.ioTokensEnabled = true
+		if kg.availableIOTokens > 0 {
+			kg.availableIOTokens = 0
+		}
+		kg.availableIOTokens += tokens
+	}
+	if !kg.ioTokensExhaustedStart.IsZero() && (!kg.ioTokensEnabled || kg.availableIOTokens > 0) {
+		exhaustedDur := kg.coord.timeSource().Sub(kg.ioTokensExhaustedStart)
+		kg.coord.metrics.KVIOTokensExhaustedDuration.Inc(exhaustedDur.Microseconds())
+		kg.ioTokensExhaustedStart = time.Time{}
+	}
+}
+
+func (kg *kvGranter) getState() string {
+	ioTokens := "unlimited"
+	if kg.ioTokensEnabled {
+		ioTokens = fmt.Sprint(kg.availableIOTokens)
+	}
+	return fmt.Sprintf("%s: used: %d, total: %d, io-avail: %s",
+		workKindString(KVWork), kg.usedSlots, kg.totalSlots, ioTokens)
+}
+
+// tokenGranter implements granterWithLockedCalls for work that uses tokens,
+// i.e. SQLKVResponseWork. The tokens are refilled up to maxBurstTokens at
+// every CPU load sample.
+type tokenGranter struct {
+	coord                *GrantCoordinator
+	workKind             WorkKind
+	availableBurstTokens int
+	maxBurstTokens       int
+}
+
+var _ granterWithLockedCalls = &tokenGranter{}
+
+func (tg *tokenGranter) tryGet() bool {
+	return tg.coord.tryGet(tg.workKind)
+}
+
+func (tg *tokenGranter) returnGrant() {
+	tg.coord.returnGrant(tg.workKind)
+}
+
+func (tg *tokenGranter) tookWithoutPermission() {
+	tg.coord.tookWithoutPermission(tg.workKind)
+}
+
+func (tg *tokenGranter) tryGrant() {
+	tg.coord.tryGrant()
+}
+
+func (tg *tokenGranter) tryGetLocked() bool {
+	if tg.availableBurstTokens <= 0 {
+		return false
+	}
+	tg.availableBurstTokens--
+	return true
+}
+
+func (tg *tokenGranter) returnGrantLocked() {
+	if tg.availableBurstTokens < tg.maxBurstTokens {
+		tg.availableBurstTokens++
+	}
+}
+
+func (tg *tokenGranter) tookWithoutPermissionLocked() {
+	tg.availableBurstTokens--
+}
+
+func (tg *tokenGranter) refillBurstTokensLocked() {
+	tg.availableBurstTokens = tg.maxBurstTokens
+}
+
+func (tg *tokenGranter) getState() string {
+	return fmt.Sprintf("%s: avail: %d, max-burst: %d",
+		workKindString(tg.workKind), tg.availableBurstTokens, tg.maxBurstTokens)
+}
+
+// PebbleMetricsProvider provides the pebble.Metrics for all stores of a node.
+type PebbleMetricsProvider interface {
+	GetPebbleMetrics() []*pebble.Metrics
+}
+
+const (
+	// ioTokenTickDuration is the interval at which IO tokens are handed out.
+	ioTokenTickDuration = time.Second
+	// adjustmentInterval is the number of IO token ticks after which the IO
+	// tokens are recomputed from the pebble metrics.
+	adjustmentInterval = 15
+)
+
+// GrantCoordinator is the top-level object that coordinates the granters of
+// all WorkKinds on a node, and exposes a WorkQueue for each. Work is granted
+// in WorkKind order: if a WorkKind has waiting work, later WorkKinds are not
+// granted to, so that e.g. SQL work that processes KV responses is not
+// admitted while KV work is waiting.
+type GrantCoordinator struct {
+	settings *cluster.Settings
+	// cpuLoadCallbackID is the id of the goschedstats callback, if registered.
+	cpuLoadCallbackID int64
+	registeredCPULoad bool
+	closeCh           chan struct{}
+	timeSource        func() time.Time
+
+	mu syncutil.Mutex
+	// granters and queues are indexed by WorkKind.
+	granters [numWorkKinds]granterWithLockedCalls
+	queues   [numWorkKinds]requester
+
+	kvGranter            *kvGranter
+	sqlKVResponseGranter *tokenGranter
+	// minCPUSlots and maxCPUSlots bound the total KV slots, and also the
+	// burst tokens for SQLKVResponseWork.
+	minCPUSlots    int
+	maxCPUSlots    int
+	ioLoadListener *ioLoadListener
+
+	metrics GranterMetrics
+}
+
+type makeRequesterFunc func(
+	workKind WorkKind, granter granter, settings *cluster.Settings,
+	histogramWindowInterval time.Duration, opts workQueueOptions,
+) requester
+
+type grantCoordinatorOptions struct {
+	minCPUSlots int
+	maxCPUSlots int
+	// cpuLoadUnsupported is true when the CPU load is not known, in which
+	// case KV slots and SQL response tokens are unlimited.
+	cpuLoadUnsupported      bool
+	histogramWindowInterval time.Duration
+	makeRequesterFunc       makeRequesterFunc
+	timeSource              func() time.Time
+}
+
+// NewGrantCoordinator constructs a GrantCoordinator and the WorkQueues for
+// all WorkKinds. The GrantCoordinator reacts to CPU load when the runnable
+// goroutine count of the Go runtime is available, and to the health of the
+// LSM once SetPebbleMetricsProvider is called. Close must be called when the
+// GrantCoordinator is no longer needed.
+func NewGrantCoordinator(
+	st *cluster.Settings, histogramWindowInterval time.Duration,
+) *GrantCoordinator {
+	coord := makeGrantCoordinator(st, grantCoordinatorOptions{
+		minCPUSlots:             1,
+		maxCPUSlots:             100000,
+		cpuLoadUnsupported:      !goschedstats.Supported,
+		histogramWindowInterval: histogramWindowInterval,
+		makeRequesterFunc:       makeWorkQueue,
+		timeSource:              timeutil.Now,
+	})
+	if goschedstats.Supported {
+		coord.cpuLoadCallbackID = goschedstats.RegisterRunnableCountCallback(coord.CPULoad)
+		coord.registeredCPULoad = true
+	}
+	return coord
+}
+
+func makeGrantCoordinator(
+	st *cluster.Settings, opts grantCoordinatorOptions,
+) *GrantCoordinator {
+	coord := &GrantCoordinator{
+		settings:    st,
+		closeCh:     make(chan struct{}),
+		timeSource:  opts.timeSource,
+		minCPUSlots: opts.minCPUSlots,
+		maxCPUSlots: opts.maxCPUSlots,
+		metrics:     makeGranterMetrics(),
+	}
+	// The slots and burst tokens start at the minimum, and are quickly
+	// increased by CPULoad, which is called every millisecond, if the CPU is
+	// not overloaded.
+	initialSlots := opts.minCPUSlots
+	if opts.cpuLoadUnsupported {
+		// Without the CPU load, there is no signal to adjust the slots and
+		// refill the tokens, so CPU is not constrained.
+		initialSlots = math.MaxInt32
+		coord.minCPUSlots = initialSlots
+		coord.maxCPUSlots = initialSlots
+	}
+
+	kvg := &kvGranter{coord: coord, totalSlots: initialSlots}
+	coord.kvGranter = kvg
+	coord.granters[KVWork] = kvg
+	coord.queues[KVWork] = opts.makeRequesterFunc(
+		KVWork, kvg, st, opts.histogramWindowInterval, makeWorkQueueOptions(KVWork))
+	coord.metrics.KVTotalSlots.Update(int64(initialSlots))
+
+	tg := &tokenGranter{
+		coord:                coord,
+		workKind:             SQLKVResponseWork,
+		availableBurstTokens: initialSlots,
+		maxBurstTokens:       initialSlots,
+	}
+	coord.sqlKVResponseGranter = tg
+	coord.granters[SQLKVResponseWork] = tg
+	coord.queues[SQLKVResponseWork] = opts.makeRequesterFunc(
+		SQLKVResponseWork, tg, st, opts.histogramWindowInterval,
+		makeWorkQueueOptions(SQLKVResponseWork))
+	coord.metrics.SQLKVResponseMaxBurstTokens.Update(int64(initialSlots))
+
+	coord.ioLoadListener = &ioLoadListener{
+		settings:    st,
+		totalTokens: unlimitedTokens,
+	}
+	if q, ok := coord.queues[KVWork].(*WorkQueue); ok {
+		coord.ioLoadListener.admittedCount = q.getAdmittedCount
+	}
+	return coord
+}
+
+// GetWorkQueue returns the WorkQueue for a particular WorkKind.
+func (coord *GrantCoordinator) GetWorkQueue(workKind WorkKind) *WorkQueue {
+	return coord.queues[workKind].(*WorkQueue)
+}
+
+// Metrics returns the metrics of the GrantCoordinator and its WorkQueues.
+func (coord *GrantCoordinator) Metrics() []metric.Struct {
+	metrics := []metric.Struct{coord.metrics}
+	for _, q := range coord.queues {
+		if wq, ok := q.(*WorkQueue); ok {
+			metrics = append(metrics, wq.Metrics())
+		}
+	}
+	return metrics
+}
+
+// SetPebbleMetricsProvider sets the provider of the LSM metrics, and starts
+// the goroutine that periodically computes the IO tokens for KVWork. It must
+// be called at most once.
+func (coord *GrantCoordinator) SetPebbleMetricsProvider(pmp PebbleMetricsProvider) {
+	coord.pebbleMetricsTick(pmp.GetPebbleMetrics())
+	coord.allocateIOTokensTick()
+	go func() {
+		ticker := time.NewTicker(ioTokenTickDuration)
+		defer ticker.Stop()
+		tickNum := 0
+		for {
+			select {
+			case <-ticker.C:
+				tickNum++
+				if tickNum == adjustmentInterval {
+					coord.pebbleMetricsTick(pmp.GetPebbleMetrics())
+					tickNum = 0
+				}
+				coord.allocateIOTokensTick()
+			case <-coord.closeCh:
+				return
+			}
+		}
+	}()
+}
+
+// Close implements the stop.Closer interface.
+func (coord *GrantCoordinator) Close() {
+	if coord.registeredCPULoad {
+		goschedstats.UnregisterRunnableCountCallback(coord.cpuLoadCallbackID)
+	}
+	close(coord.closeCh)
+	for _, q := range coord.queues {
+		if q != nil {
+			q.close()
+		}
+	}
+}
+
+// CPULoad implements goschedstats.RunnableCountCallback. It adjusts the total
+// KV slots, and the SQL response burst tokens, based on whether the CPU is
+// overloaded, and refills the SQL response tokens.
+func (coord *GrantCoordinator) CPULoad(runnable int, procs int) {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	threshold := int(KVSlotAdjusterOverloadThreshold.Get(&coord.settings.SV))
+	overloaded := runnable >= threshold*procs
+	underloaded := runnable <= threshold*procs/2
+
+	kvg := coord.kvGranter
+	if overloaded {
+		// Reduce the slots only if they are being used, since otherwise the
+		// overload is not caused by KV work.
+		if kvg.usedSlots > 0 && kvg.usedSlots <= kvg.totalSlots && kvg.totalSlots > coord.minCPUSlots {
+			kvg.totalSlots--
+		}
+	} else if underloaded {
+		// Increase the slots only if they are all used and there is work
+		// waiting for them.
+		if kvg.usedSlots >= kvg.totalSlots && kvg.totalSlots < coord.maxCPUSlots &&
+			coord.queues[KVWork].hasWaitingRequests() {
+			kvg.totalSlots++
+		}
+	}
+	coord.metrics.KVTotalSlots.Update(int64(kvg.totalSlots))
+
+	tg := coord.sqlKVResponseGranter
+	if overloaded {
+		if tg.maxBurstTokens > coord.minCPUSlots {
+			tg.maxBurstTokens--
+		}
+	} else if underloaded {
+		// Increase the burst only if the tokens were exhausted since the last
+		// refill.
+		if tg.availableBurstTokens <= 0 && tg.maxBurstTokens < coord.maxCPUSlots {
+			tg.maxBurstTokens++
+		}
+	}
+	tg.refillBurstTokensLocked()
+	coord.metrics.SQLKVResponseMaxBurstTokens.Update(int64(tg.maxBurstTokens))
+
+	coord.tryGrantLocked()
+}
+
+func (coord *GrantCoordinator) tryGet(workKind WorkKind) bool {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	// Work must not bypass the waiting work of an earlier WorkKind.
+	for kind := WorkKind(0); kind < workKind; kind++ {
+		if coord.queues[kind].hasWaitingRequests() {
+			return false
+		}
+	}
+	return coord.granters[workKind].tryGetLocked()
+}
+
+func (coord *GrantCoordinator) returnGrant(workKind WorkKind) {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	coord.granters[workKind].returnGrantLocked()
+	coord.tryGrantLocked()
+}
+
+func (coord *GrantCoordinator) tookWithoutPermission(workKind WorkKind) {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	coord.granters[workKind].tookWithoutPermissionLocked()
+}
+
+func (coord *GrantCoordinator) tryGrant() {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	coord.tryGrantLocked()
+}
+
+// tryGrantLocked grants to waiting work in WorkKind order, until a WorkKind
+// runs out of slots or tokens while it still has waiting work.
+func (coord *GrantCoordinator) tryGrantLocked() {
+	for kind := WorkKind(0); kind < numWorkKinds; kind++ {
+		g, q := coord.granters[kind], coord.queues[kind]
+		for q.hasWaitingRequests() {
+			if !g.tryGetLocked() {
+				break
+			}
+			if !q.granted() {
+				// The waiting request went away in the meantime.
+				g.returnGrantLocked()
+				break
+			}
+		}
+		if q.hasWaitingRequests() {
+			return
+		}
+	}
+}
+
+// pebbleMetricsTick recomputes the IO tokens from the pebble metrics.
+func (coord *GrantCoordinator) pebbleMetricsTick(metrics []*pebble.Metrics) {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	coord.ioLoadListener.pebbleMetricsTick(metrics)
+}
+
+// allocateIOTokensTick hands out the IO tokens for the next tick.
+func (coord *GrantCoordinator) allocateIOTokensTick() {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	coord.kvGranter.setAvailableIOTokensLocked(coord.ioLoadListener.allocateTokensTick())
+	coord.tryGrantLocked()
+}
+
+func (coord *GrantCoordinator) String() string {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	var buf strings.Builder
+	for i, g := range coord.granters {
+		if i > 0 {
+			buf.WriteString(" ")
+		}
+		fmt.Fprintf(&buf, "(%s)", g.getState())
+	}
+	return buf.String()
+}
+
+// ioLoadListener computes the number of IO tokens for KVWork based on the
+// health of the LSM of each store. When the level 0 of a store has too many
+// files or sub-levels, KV work is admitted at a rate that allows compactions
+// to remove bytes from level 0 faster than they are added. The IO tokens are
+// recomputed every adjustmentInterval ticks, and handed out evenly across
+// the ticks.
+type ioLoadListener struct {
+	settings *cluster.Settings
+	// admittedCount returns the cumulative count of admitted KVWork.
+	admittedCount func() uint64
+
+	statsInitialized bool
+	cumAdmitted      uint64
+	stores           []storeIOLoad
+
+	// totalTokens is the number of tokens for the current adjustment interval,
+	// or unlimitedTokens.
+	totalTokens     int64
+	tokensAllocated int64
+	tickNum         int
+}
+
+// storeIOLoad tracks the level 0 statistics of a store across adjustment
+// intervals.
+type storeIOLoad struct {
+	cumL0AddedBytes uint64
+	curL0Bytes      int64
+	// smoothedIntL0CompactedBytes is the exponentially smoothed number of
+	// bytes compacted out of level 0 per adjustment interval.
+	smoothedIntL0CompactedBytes int64
+	// smoothedBytesAddedPerWork is the exponentially smoothed number of bytes
+	// added to level 0 per admitted work.
+	smoothedBytesAddedPerWork float64
+}
+
+// isOverloaded returns whether the level 0 of the LSM is unhealthy.
+func (io *ioLoadListener) isOverloaded(m *pebble.Metrics) bool {
+	return m.Levels[0].NumFiles > L0FileCountOverloadThreshold.Get(&io.settings.SV) ||
+		int64(m.Levels[0].Sublevels) > L0SubLevelCountOverloadThreshold.Get(&io.settings.SV)
+}
+
+func (io *ioLoadListener) pebbleMetricsTick(metrics []*pebble.Metrics) {
+	var cumAdmitted uint64
+	if io.admittedCount != nil {
+		cumAdmitted = io.admittedCount()
+	}
+	io.tokensAllocated = 0
+	io.tickNum = 0
+	if !io.statsInitialized || len(metrics) != len(io.stores) {
+		// The first tick, or the number of stores changed, so there is no
+		// interval to compute the tokens from.
+		io.statsInitialized = true
+		io.cumAdmitted = cumAdmitted
+		io.stores = make([]storeIOLoad, len(metrics))
+		for i, m := range metrics {
+			io.stores[i] = storeIOLoad{
+				cumL0AddedBytes: m.Levels[0].BytesFlushed + m.Levels[0].BytesIngested,
+				curL0Bytes:      m.Levels[0].Size,
+			}
+		}
+		io.totalTokens = unlimitedTokens
+		return
+	}
+	intAdmitted := cumAdmitted - io.cumAdmitted
+	io.cumAdmitted = cumAdmitted
+
+	totalTokens := int64(unlimitedTokens)
+	for i, m := range metrics {
+		s := &io.stores[i]
+		cumL0AddedBytes := m.Levels[0].BytesFlushed + m.Levels[0].BytesIngested
+		intL0AddedBytes := int64(cumL0AddedBytes - s.cumL0AddedBytes)
+		curL0Bytes := m.Levels[0].Size
+		// The bytes that were removed from level 0 by compactions.
+		intL0CompactedBytes := s.curL0Bytes + intL0AddedBytes - curL0Bytes
+		if intL0CompactedBytes < 0 {
+			intL0CompactedBytes = 0
+		}
+		s.cumL0AddedBytes = cumL0AddedBytes
+		s.curL0Bytes = curL0Bytes
+		const alpha = 0.5
+		s.smoothedIntL0CompactedBytes = int64(
+			alpha*float64(intL0CompactedBytes) + (1-alpha)*float64(s.smoothedIntL0CompactedBytes))
+		if intAdmitted > 0 && intL0AddedBytes > 0 {
+			// The admitted work is spread across all stores, so this is an
+			// overestimate when there are multiple stores, which errs on the
+			// side of admitting less work.
+			bytesAddedPerWork := float64(intL0AddedBytes) / float64(intAdmitted)
+			if s.smoothedBytesAddedPerWork == 0 {
+				s.smoothedBytesAddedPerWork = bytesAddedPerWork
+			} else {
+				s.smoothedBytesAddedPerWork = alpha*bytesAddedPerWork + (1-alpha)*s.smoothedBytesAddedPerWork
+			}
+		}
+		if !io.isOverloaded(m) {
+			continue
+		}
+		// Admit work that adds half the bytes that are being compacted out of
+		// level 0, so that level 0 shrinks.
+		bytesAddedPerWork := s.smoothedBytesAddedPerWork
+		if bytesAddedPerWork < 1 {
+			bytesAddedPerWork = 1
+		}
+		tokens := int64(float64(s.smoothedIntL0CompactedBytes) / 2 / bytesAddedPerWork)
+		if tokens < totalTokens {
+			totalTokens = tokens
+		}
+	}
+	io.totalTokens = totalTokens
+}
+
+// allocateTokensTick returns the tokens for the next tick.
+func (io *ioLoadListener) allocateTokensTick() int64 {
+	if io.totalTokens == unlimitedTokens {
+		return unlimitedTokens
+	}
+	remainingTicks := int64(adjustmentInterval - io.tickNum)
+	if remainingTicks < 1 {
+		// The adjustment is late, so keep handing out tokens at the same rate.
+		remainingTicks = 1
+		io.tokensAllocated = 0
+	}
+	toAllocate := (io.totalTokens - io.tokensAllocated + remainingTicks - 1) / remainingTicks
+	io.tokensAllocated += toAllocate
+	io.tickNum++
+	return toAllocate
+}
+
+var (
+	kvTotalSlots = metric.Metadata{
+		Name:        "admission.granter.total_slots.kv",
+		Help:        "Total slots for kv work",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+	kvUsedSlots = metric.Metadata{
+		Name:        "admission.granter.used_slots.kv",
+		Help:        "Used slots for kv work",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+	kvIOTokensExhaustedDuration = metric.Metadata{
+		Name:        "admission.granter.io_tokens_exhausted_duration.kv",
+		Help:        "Total duration when IO tokens were exhausted, in micros",
+		Measurement: "Microseconds",
+		Unit:        metric.Unit_COUNT,
+	}
+	sqlKVResponseMaxBurstTokens = metric.Metadata{
+		Name:        "admission.granter.max_burst_tokens.sql-kv-response",
+		Help:        "Maximum burst tokens for sql-kv-response work",
+		Measurement: "Tokens",
+		Unit:        metric.Unit_COUNT,
+	}
+)
+
+// GranterMetrics are the metrics of a GrantCoordinator.
+type GranterMetrics struct {
+	KVTotalSlots                *metric.Gauge
+	KVUsedSlots                 *metric.Gauge
+	KVIOTokensExhaustedDuration *metric.Counter
+	SQLKVResponseMaxBurstTokens *metric.Gauge
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (GranterMetrics) MetricStruct() {}
+
+func makeGranterMetrics() GranterMetrics {
+	return GranterMetrics{
+		KVTotalSlots:                metric.NewGauge(kvTotalSlots),
+		KVUsedSlots:                 metric.NewGauge(kvUsedSlots),
+		KVIOTokensExhaustedDuration: metric.NewCounter(kvIOTokensExhaustedDuration),
+		SQLKVResponseMaxBurstTokens: metric.NewGauge(sqlKVResponseMaxBurstTokens),
+	}
+}
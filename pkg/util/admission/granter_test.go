@@ -0,0 +1,335 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/pebble"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequester struct {
+	workKind               WorkKind
+	granter                granter
+	buf                    *strings.Builder
+	waitingRequests        bool
+	returnFalseFromGranted bool
+}
+
+var _ requester = &testRequester{}
+
+func (tr *testRequester) hasWaitingRequests() bool {
+	return tr.waitingRequests
+}
+
+func (tr *testRequester) granted() bool {
+	fmt.Fprintf(tr.buf, "%s: granted returned %t\n", workKindString(tr.workKind),
+		!tr.returnFalseFromGranted)
+	return !tr.returnFalseFromGranted
+}
+
+func (tr *testRequester) close() {}
+
+func (tr *testRequester) tryGet() {
+	rv := tr.granter.tryGet()
+	fmt.Fprintf(tr.buf, "%s: tryGet returned %t\n", workKindString(tr.workKind), rv)
+}
+
+func (tr *testRequester) returnGrant() {
+	fmt.Fprintf(tr.buf, "%s: returnGrant\n", workKindString(tr.workKind))
+	tr.granter.returnGrant()
+}
+
+func (tr *testRequester) tookWithoutPermission() {
+	fmt.Fprintf(tr.buf, "%s: tookWithoutPermission\n", workKindString(tr.workKind))
+	tr.granter.tookWithoutPermission()
+}
+
+func scanWorkKind(t *testing.T, d *datadriven.TestData) WorkKind {
+	var kindStr string
+	d.ScanArgs(t, "work", &kindStr)
+	switch kindStr {
+	case "kv":
+		return KVWork
+	case "sql-kv-response":
+		return SQLKVResponseWork
+	}
+	t.Fatalf("unknown work kind %s", kindStr)
+	return numWorkKinds
+}
+
+// TestGranterBasic is a datadriven test with the following commands:
+//
+// init-grant-coordinator min-cpu=<int> max-cpu=<int>
+// set-has-waiting-requests work=<kind> v=<true|false>
+// set-return-value-from-granted work=<kind> v=<true|false>
+// try-get work=<kind>
+// return-grant work=<kind>
+// took-without-permission work=<kind>
+// cpu-load runnable=<int> procs=<int>
+// set-io-tokens tokens=<int>|unlimited
+//
+// The output of each command is the calls made to the test requesters,
+// followed by the state of the granters.
+func TestGranterBasic(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var coord *GrantCoordinator
+	var requesters [numWorkKinds]*testRequester
+	var buf strings.Builder
+	datadriven.RunTest(t, "testdata/granter", func(t *testing.T, d *datadriven.TestData) string {
+		switch d.Cmd {
+		case "init-grant-coordinator":
+			var minCPU, maxCPU int
+			d.ScanArgs(t, "min-cpu", &minCPU)
+			d.ScanArgs(t, "max-cpu", &maxCPU)
+			coord = makeGrantCoordinator(cluster.MakeTestingClusterSettings(), grantCoordinatorOptions{
+				minCPUSlots:             minCPU,
+				maxCPUSlots:             maxCPU,
+				histogramWindowInterval: time.Minute,
+				makeRequesterFunc: func(
+					workKind WorkKind, granter granter, _ *cluster.Settings, _ time.Duration,
+					_ workQueueOptions,
+				) requester {
+					req := &testRequester{workKind: workKind, granter: granter, buf: &buf}
+					requesters[workKind] = req
+					return req
+				},
+				timeSource: timeutil.Now,
+			})
+			return coord.String()
+
+		case "set-has-waiting-requests":
+			var v bool
+			d.ScanArgs(t, "v", &v)
+			requesters[scanWorkKind(t, d)].waitingRequests = v
+			return coord.String()
+
+		case "set-return-value-from-granted":
+			var v bool
+			d.ScanArgs(t, "v", &v)
+			requesters[scanWorkKind(t, d)].returnFalseFromGranted = !v
+			return coord.String()
+
+		case "try-get":
+			requesters[scanWorkKind(t, d)].tryGet()
+			return flushAndReset(&buf, coord)
+
+		case "return-grant":
+			requesters[scanWorkKind(t, d)].returnGrant()
+			return flushAndReset(&buf, coord)
+
+		case "took-without-permission":
+			requesters[scanWorkKind(t, d)].tookWithoutPermission()
+			return flushAndReset(&buf, coord)
+
+		case "cpu-load":
+			var runnable, procs int
+			d.ScanArgs(t, "runnable", &runnable)
+			d.ScanArgs(t, "procs", &procs)
+			coord.CPULoad(runnable, procs)
+			return flushAndReset(&buf, coord)
+
+		case "set-io-tokens":
+			var tokensStr string
+			d.ScanArgs(t, "tokens", &tokensStr)
+			tokens := int64(unlimitedTokens)
+			if tokensStr != "unlimited" {
+				var err error
+				tokens, err = strconv.ParseInt(tokensStr, 10, 64)
+				require.NoError(t, err)
+			}
+			coord.mu.Lock()
+			coord.kvGranter.setAvailableIOTokensLocked(tokens)
+			coord.tryGrantLocked()
+			coord.mu.Unlock()
+			return flushAndReset(&buf, coord)
+
+		default:
+			return fmt.Sprintf("unknown command: %s", d.Cmd)
+		}
+	})
+}
+
+func flushAndReset(buf *strings.Builder, coord *GrantCoordinator) string {
+	fmt.Fprintf(buf, "%s\n", coord.String())
+	str := buf.String()
+	buf.Reset()
+	return str
+}
+
+// TestIOLoadListener is a datadriven test with the following commands:
+//
+// set-state admitted=<int> l0-bytes=<int> l0-added=<int> l0-files=<int> l0-sublevels=<int>
+//
+// which runs an adjustment interval of the ioLoadListener with the given
+// cumulative admitted count and level 0 metrics, and prints the resulting
+// state and the tokens handed out at each tick of the interval.
+func TestIOLoadListener(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var io *ioLoadListener
+	var admitted uint64
+	datadriven.RunTest(t, "testdata/io_load_listener", func(t *testing.T, d *datadriven.TestData) string {
+		switch d.Cmd {
+		case "init":
+			admitted = 0
+			io = &ioLoadListener{
+				settings:      cluster.MakeTestingClusterSettings(),
+				admittedCount: func() uint64 { return admitted },
+				totalTokens:   unlimitedTokens,
+			}
+			return ""
+
+		case "set-state":
+			var l0Bytes, l0Added, l0Files, l0SubLevels uint64
+			d.ScanArgs(t, "admitted", &admitted)
+			d.ScanArgs(t, "l0-bytes", &l0Bytes)
+			d.ScanArgs(t, "l0-added", &l0Added)
+			d.ScanArgs(t, "l0-files", &l0Files)
+			d.ScanArgs(t, "l0-sublevels", &l0SubLevels)
+			var m pebble.Metrics
+			m.Levels[0] = pebble.LevelMetrics{
+				Sublevels:     int32(l0SubLevels),
+				NumFiles:      int64(l0Files),
+				Size:          int64(l0Bytes),
+				BytesFlushed:  l0Added,
+				BytesIngested: 0,
+			}
+			io.pebbleMetricsTick([]*pebble.Metrics{&m})
+			var buf strings.Builder
+			s := io.stores[0]
+			fmt.Fprintf(&buf, "admitted: %d, smoothed-compacted: %d, smoothed-bytes-per-work: %.2f, ",
+				io.cumAdmitted, s.smoothedIntL0CompactedBytes, s.smoothedBytesAddedPerWork)
+			if io.totalTokens == unlimitedTokens {
+				buf.WriteString("tokens: unlimited\n")
+				return buf.String()
+			}
+			fmt.Fprintf(&buf, "tokens: %d\ntick allocations:", io.totalTokens)
+			for i := 0; i < adjustmentInterval; i++ {
+				fmt.Fprintf(&buf, " %d", io.allocateTokensTick())
+			}
+			buf.WriteString("\n")
+			return buf.String()
+
+		default:
+			return fmt.Sprintf("unknown command: %s", d.Cmd)
+		}
+	})
+}
+
+// TestGrantCoordinatorSyntheticLoad runs concurrent KV and SQL response work
+// through a GrantCoordinator with real WorkQueues, while feeding it a
+// synthetic CPU load, and verifies that all the work is admitted and that the
+// KV slots react to the load.
+func TestGrantCoordinatorSyntheticLoad(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	st := cluster.MakeTestingClusterSettings()
+	ctx := context.Background()
+	KVAdmissionControlEnabled.Override(&st.SV, true)
+	SQLKVResponseAdmissionControlEnabled.Override(&st.SV, true)
+	coord := makeGrantCoordinator(st, grantCoordinatorOptions{
+		minCPUSlots:             1,
+		maxCPUSlots:             20,
+		histogramWindowInterval: time.Minute,
+		makeRequesterFunc:       makeWorkQueue,
+		timeSource:              timeutil.Now,
+	})
+	defer coord.Close()
+	kvQ := coord.GetWorkQueue(KVWork)
+	sqlQ := coord.GetWorkQueue(SQLKVResponseWork)
+
+	// Feed the CPU load: the load is overloaded while more than 10 KV slots
+	// are in use, which should keep the total slots in the vicinity of 10.
+	var maxTotalSlots int64
+	stopLoad := make(chan struct{})
+	var loadWG sync.WaitGroup
+	loadWG.Add(1)
+	go func() {
+		defer loadWG.Done()
+		for {
+			select {
+			case <-stopLoad:
+				return
+			default:
+			}
+			coord.mu.Lock()
+			used, total := coord.kvGranter.usedSlots, coord.kvGranter.totalSlots
+			coord.mu.Unlock()
+			if int64(total) > atomic.LoadInt64(&maxTotalSlots) {
+				atomic.StoreInt64(&maxTotalSlots, int64(total))
+			}
+			runnable := 0
+			if used > 10 {
+				runnable = 100
+			}
+			coord.CPULoad(runnable, 1)
+			time.Sleep(100 * time.Microsecond)
+		}
+	}()
+
+	const numWorkers = 50
+	const numIterations = 20
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < numIterations; j++ {
+				info := WorkInfo{
+					TenantID:   roachpb.MakeTenantID(uint64(i%3 + 1)),
+					Priority:   WorkPriority(i%3 - 1),
+					CreateTime: timeutil.Now().UnixNano(),
+				}
+				enabled, err := kvQ.Admit(ctx, info)
+				require.NoError(t, err)
+				require.True(t, enabled)
+				time.Sleep(50 * time.Microsecond)
+				kvQ.AdmittedWorkDone(info.TenantID)
+				enabled, err = sqlQ.Admit(ctx, info)
+				require.NoError(t, err)
+				require.True(t, enabled)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(stopLoad)
+	loadWG.Wait()
+
+	require.Equal(t, int64(numWorkers*numIterations), kvQ.Metrics().Admitted.Count())
+	require.Equal(t, int64(numWorkers*numIterations), sqlQ.Metrics().Admitted.Count())
+	require.Equal(t, int64(0), kvQ.Metrics().WaitQueueLength.Value())
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	require.Equal(t, 0, coord.kvGranter.usedSlots)
+	// The slots were increased from the minimum to admit the waiting work,
+	// but never beyond the maximum.
+	require.Greater(t, atomic.LoadInt64(&maxTotalSlots), int64(1))
+	require.LessOrEqual(t, atomic.LoadInt64(&maxTotalSlots), int64(20))
+}
@@ -0,0 +1,597 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// KVAdmissionControlEnabled controls whether KV server-side admission control
+// is enabled.
+var KVAdmissionControlEnabled = settings.RegisterBoolSetting(
+	"admission.kv.enabled",
+	"when true, work performed by the KV layer is subject to admission control",
+	false)
+
+// SQLKVResponseAdmissionControlEnabled controls whether the processing of KV
+// responses by SQL is subject to admission control.
+var SQLKVResponseAdmissionControlEnabled = settings.RegisterBoolSetting(
+	"admission.sql_kv_response.enabled",
+	"when true, work performed by the SQL layer when receiving a KV response is subject to "+
+		"admission control",
+	false)
+
+var admissionControlEnabledSettings = [numWorkKinds]*settings.BoolSetting{
+	KVWork:            KVAdmissionControlEnabled,
+	SQLKVResponseWork: SQLKVResponseAdmissionControlEnabled,
+}
+
+// WorkPriority represents the priority of work. In a WorkQueue, it is only
+// used for ordering within a tenant. High priority work can starve lower
+// priority work.
+type WorkPriority int8
+
+const (
+	// LowPri is low priority work, such as bulk ingestion and other
+	// background work.
+	LowPri WorkPriority = math.MinInt8
+	// NormalPri is normal priority work, such as user SQL statements.
+	NormalPri WorkPriority = 0
+	// HighPri is high priority work, such as work on behalf of the system.
+	HighPri WorkPriority = math.MaxInt8
+)
+
+// WorkKind represents various types of work that are subject to admission
+// control.
+type WorkKind int8
+
+const (
+	// KVWork represents requests submitted to the KV layer, from the same
+	// node or a different node. They may originate from the SQL layer or the
+	// KV layer.
+	KVWork WorkKind = iota
+	// SQLKVResponseWork is SQL work that is performed when a KV response is
+	// received by the SQL layer, such as decoding and processing the rows.
+	SQLKVResponseWork
+	numWorkKinds
+)
+
+func workKindString(workKind WorkKind) string {
+	switch workKind {
+	case KVWork:
+		return "kv"
+	case SQLKVResponseWork:
+		return "sql-kv-response"
+	default:
+		panic(errors.AssertionFailedf("unknown WorkKind %d", workKind))
+	}
+}
+
+// WorkInfo provides information that is used to order work within a
+// WorkQueue. The WorkKind is not included as a field since an instance of
+// WorkQueue is for a single WorkKind.
+type WorkInfo struct {
+	// TenantID is the id of the tenant. For single-tenant clusters, this will
+	// always be the SystemTenantID.
+	TenantID roachpb.TenantID
+	// Priority is utilized within a tenant.
+	Priority WorkPriority
+	// CreateTime is equivalent to Time.UnixNano() at the creation time of this
+	// work or a parent work (e.g. the start time of the transaction). Work with
+	// the same priority is admitted in CreateTime order.
+	CreateTime int64
+	// BypassAdmission allows the work to bypass admission control, while still
+	// being accounted for. It is only honored for KVWork of the system tenant,
+	// and is used for work that must not be delayed, such as work on behalf of
+	// the system.
+	BypassAdmission bool
+}
+
+// WorkQueue maintains a queue of work waiting to be admitted. Ordering of work
+// is achieved via two heaps: a tenant heap orders the tenants with waiting
+// work in increasing order of used slots or tokens, so that tenants share the
+// resources fairly. Within each tenant, the waiting work is ordered by
+// priority, and then by create time.
+//
+// WorkQueue is safe for concurrent use.
+type WorkQueue struct {
+	workKind   WorkKind
+	granter    granter
+	usesTokens bool
+	settings   *cluster.Settings
+
+	mu struct {
+		syncutil.Mutex
+		// tenants contains all the tenants that have used slots or tokens
+		// recently, or have waiting work. It is periodically garbage collected.
+		tenants map[uint64]*tenantInfo
+		// tenantHeap contains the tenants with waiting work.
+		tenantHeap tenantHeap
+	}
+	// admittedCount is the total number of admitted work. It is accessed
+	// atomically.
+	admittedCount uint64
+	metrics       WorkQueueMetrics
+	stopCh        chan struct{}
+}
+
+var _ requester = &WorkQueue{}
+
+type workQueueOptions struct {
+	usesTokens bool
+	// disableTickerForTesting disables the background goroutine that garbage
+	// collects tenants and resets token usage.
+	disableTickerForTesting bool
+}
+
+func makeWorkQueueOptions(workKind WorkKind) workQueueOptions {
+	return workQueueOptions{usesTokens: workKind == SQLKVResponseWork}
+}
+
+func makeWorkQueue(
+	workKind WorkKind,
+	granter granter,
+	settings *cluster.Settings,
+	histogramWindowInterval time.Duration,
+	opts workQueueOptions,
+) requester {
+	q := &WorkQueue{
+		workKind:   workKind,
+		granter:    granter,
+		usesTokens: opts.usesTokens,
+		settings:   settings,
+		metrics:    makeWorkQueueMetrics(workKindString(workKind), histogramWindowInterval),
+		stopCh:     make(chan struct{}),
+	}
+	q.mu.tenants = make(map[uint64]*tenantInfo)
+	if !opts.disableTickerForTesting {
+		go func() {
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					q.gcTenantsAndResetTokens()
+				case <-q.stopCh:
+					return
+				}
+			}
+		}()
+	}
+	return q
+}
+
+// Metrics returns the metrics of the WorkQueue.
+func (q *WorkQueue) Metrics() *WorkQueueMetrics {
+	return &q.metrics
+}
+
+// Admit is called when requesting admission for some work. If err != nil, the
+// request was not admitted, potentially due to the deadline being exceeded.
+// If enabled=true, and err=nil, the request was admitted and, for KVWork,
+// AdmittedWorkDone must be called when the work is done. If enabled=false,
+// admission control is disabled and AdmittedWorkDone must not be called.
+func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err error) {
+	if s := admissionControlEnabledSettings[q.workKind]; s != nil && !s.Get(&q.settings.SV) {
+		return false, nil
+	}
+	q.metrics.Requested.Inc(1)
+	tenantID := info.TenantID.ToUint64()
+	if info.BypassAdmission && roachpb.IsSystemTenantID(tenantID) && q.workKind == KVWork {
+		q.mu.Lock()
+		tenant := q.getOrCreateTenantLocked(tenantID)
+		tenant.used++
+		if tenant.heapIndex != -1 {
+			heap.Fix(&q.mu.tenantHeap, tenant.heapIndex)
+		}
+		q.mu.Unlock()
+		q.granter.tookWithoutPermission()
+		q.admitted()
+		return true, nil
+	}
+
+	q.mu.Lock()
+	tenant := q.getOrCreateTenantLocked(tenantID)
+	if len(q.mu.tenantHeap) == 0 {
+		// Fast path: there is no waiting work, so try to get a slot or token
+		// right away. The usage is incremented optimistically, and q.mu is
+		// released since the granter must not be called with it held.
+		tenant.used++
+		q.mu.Unlock()
+		if q.granter.tryGet() {
+			q.admitted()
+			return true, nil
+		}
+		q.mu.Lock()
+		// The tenant may have been garbage collected in the meantime.
+		tenant = q.getOrCreateTenantLocked(tenantID)
+		tenant.decUsed()
+	}
+	// Queue the work.
+	work := &waitingWork{
+		priority:       info.Priority,
+		createTime:     info.CreateTime,
+		ch:             make(chan struct{}, 1),
+		enqueueingTime: timeutil.Now(),
+	}
+	heap.Push(&tenant.waitingWorkHeap, work)
+	if len(tenant.waitingWorkHeap) == 1 {
+		heap.Push(&q.mu.tenantHeap, tenant)
+	}
+	q.mu.Unlock()
+	q.metrics.WaitQueueLength.Inc(1)
+	// A slot or token may have been released after the failed fast path, but
+	// before the work was queued, so ask the granter to grant to waiting work.
+	q.granter.tryGrant()
+
+	select {
+	case <-ctx.Done():
+		q.mu.Lock()
+		if work.heapIndex == -1 {
+			// The work was granted concurrently with the cancellation, so the
+			// grant must be returned.
+			tenant.decUsed()
+			if tenant.heapIndex != -1 {
+				heap.Fix(&q.mu.tenantHeap, tenant.heapIndex)
+			}
+			q.mu.Unlock()
+			q.granter.returnGrant()
+		} else {
+			heap.Remove(&tenant.waitingWorkHeap, work.heapIndex)
+			if len(tenant.waitingWorkHeap) == 0 {
+				heap.Remove(&q.mu.tenantHeap, tenant.heapIndex)
+			}
+			q.mu.Unlock()
+		}
+		waitDur := q.recordWait(work)
+		q.metrics.Errored.Inc(1)
+		log.Eventf(ctx, "%s admission queue: canceled after waiting %s", workKindString(q.workKind), waitDur)
+		return true, errors.Wrapf(ctx.Err(),
+			"canceled after waiting %s in %s admission queue", waitDur, workKindString(q.workKind))
+	case <-work.ch:
+		waitDur := q.recordWait(work)
+		q.admitted()
+		log.Eventf(ctx, "%s admission queue: admitted after waiting %s", workKindString(q.workKind), waitDur)
+		return true, nil
+	}
+}
+
+// AdmittedWorkDone is used to inform the WorkQueue that admitted work is
+// finished. It must be called iff Admit returned enabled=true and a nil error,
+// and only for a WorkQueue of a WorkKind that uses slots (KVWork).
+func (q *WorkQueue) AdmittedWorkDone(tenantID roachpb.TenantID) {
+	if q.usesTokens {
+		panic(errors.AssertionFailedf("tokens should not be returned"))
+	}
+	q.mu.Lock()
+	tenant, ok := q.mu.tenants[tenantID.ToUint64()]
+	if !ok {
+		q.mu.Unlock()
+		panic(errors.AssertionFailedf("tenant %s not found", tenantID))
+	}
+	tenant.decUsed()
+	if tenant.heapIndex != -1 {
+		heap.Fix(&q.mu.tenantHeap, tenant.heapIndex)
+	}
+	q.mu.Unlock()
+	q.granter.returnGrant()
+}
+
+func (q *WorkQueue) admitted() {
+	atomic.AddUint64(&q.admittedCount, 1)
+	q.metrics.Admitted.Inc(1)
+}
+
+// getAdmittedCount returns the total number of work admitted by the queue.
+func (q *WorkQueue) getAdmittedCount() uint64 {
+	return atomic.LoadUint64(&q.admittedCount)
+}
+
+func (q *WorkQueue) recordWait(work *waitingWork) time.Duration {
+	waitDur := timeutil.Since(work.enqueueingTime)
+	q.metrics.WaitQueueLength.Dec(1)
+	q.metrics.WaitDurationSum.Inc(waitDur.Nanoseconds())
+	q.metrics.WaitDurations.RecordValue(waitDur.Nanoseconds())
+	return waitDur
+}
+
+func (q *WorkQueue) getOrCreateTenantLocked(tenantID uint64) *tenantInfo {
+	tenant, ok := q.mu.tenants[tenantID]
+	if !ok {
+		tenant = &tenantInfo{id: tenantID, heapIndex: -1}
+		q.mu.tenants[tenantID] = tenant
+	}
+	return tenant
+}
+
+// hasWaitingRequests implements the requester interface.
+func (q *WorkQueue) hasWaitingRequests() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.mu.tenantHeap) > 0
+}
+
+// granted implements the requester interface. It grants to the highest
+// priority waiting work of the tenant that is using the fewest resources.
+func (q *WorkQueue) granted() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.mu.tenantHeap) == 0 {
+		return false
+	}
+	tenant := q.mu.tenantHeap[0]
+	work := heap.Pop(&tenant.waitingWorkHeap).(*waitingWork)
+	tenant.used++
+	if len(tenant.waitingWorkHeap) == 0 {
+		heap.Pop(&q.mu.tenantHeap)
+	} else {
+		heap.Fix(&q.mu.tenantHeap, 0)
+	}
+	// The channel is buffered, so this does not block.
+	work.ch <- struct{}{}
+	return true
+}
+
+// close implements the requester interface.
+func (q *WorkQueue) close() {
+	close(q.stopCh)
+}
+
+// gcTenantsAndResetTokens removes the tenants that have no used slots and no
+// waiting work. For a WorkQueue that uses tokens, the tokens used by each
+// tenant are also reset, so that fair sharing is based on recent usage.
+func (q *WorkQueue) gcTenantsAndResetTokens() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for id, tenant := range q.mu.tenants {
+		if tenant.used == 0 && len(tenant.waitingWorkHeap) == 0 {
+			delete(q.mu.tenants, id)
+		} else if q.usesTokens {
+			tenant.used = 0
+		}
+	}
+	if q.usesTokens {
+		heap.Init(&q.mu.tenantHeap)
+	}
+}
+
+func (q *WorkQueue) String() string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "tenantHeap len: %d", len(q.mu.tenantHeap))
+	if len(q.mu.tenantHeap) > 0 {
+		fmt.Fprintf(&buf, " top tenant: %d", q.mu.tenantHeap[0].id)
+	}
+	ids := make([]uint64, 0, len(q.mu.tenants))
+	for id := range q.mu.tenants {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		tenant := q.mu.tenants[id]
+		fmt.Fprintf(&buf, "\n tenant-id: %d used: %d", tenant.id, tenant.used)
+		if len(tenant.waitingWorkHeap) > 0 {
+			// Print the waiting work in the order it will be granted.
+			work := make(waitingWorkHeap, len(tenant.waitingWorkHeap))
+			copy(work, tenant.waitingWorkHeap)
+			sort.Slice(work, func(i, j int) bool { return work.less(i, j) })
+			buf.WriteString(" waiting work heap:")
+			for _, w := range work {
+				fmt.Fprintf(&buf, " [pri: %d, ct: %d]", w.priority, w.createTime)
+			}
+		}
+	}
+	return buf.String()
+}
+
+// tenantInfo is the per-tenant information in a WorkQueue.
+type tenantInfo struct {
+	id uint64
+	// used is the number of slots in use for a WorkQueue that uses slots, or
+	// the number of tokens recently consumed for a WorkQueue that uses tokens.
+	used            uint64
+	waitingWorkHeap waitingWorkHeap
+	// heapIndex is the index of the tenant in the tenantHeap, or -1 if the
+	// tenant has no waiting work.
+	heapIndex int
+}
+
+func (t *tenantInfo) decUsed() {
+	// The tokens used by a tenant are periodically reset, so this can be
+	// called when used is already zero.
+	if t.used > 0 {
+		t.used--
+	}
+}
+
+// tenantHeap is a heap of tenants with waiting work, ordered in increasing
+// order of used slots or tokens.
+type tenantHeap []*tenantInfo
+
+var _ heap.Interface = (*tenantHeap)(nil)
+
+func (th *tenantHeap) Len() int {
+	return len(*th)
+}
+
+func (th *tenantHeap) Less(i, j int) bool {
+	if (*th)[i].used == (*th)[j].used {
+		return (*th)[i].id < (*th)[j].id
+	}
+	return (*th)[i].used < (*th)[j].used
+}
+
+func (th *tenantHeap) Swap(i, j int) {
+	(*th)[i], (*th)[j] = (*th)[j], (*th)[i]
+	(*th)[i].heapIndex = i
+	(*th)[j].heapIndex = j
+}
+
+func (th *tenantHeap) Push(x interface{}) {
+	n := len(*th)
+	item := x.(*tenantInfo)
+	item.heapIndex = n
+	*th = append(*th, item)
+}
+
+func (th *tenantHeap) Pop() interface{} {
+	old := *th
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.heapIndex = -1
+	*th = old[0 : n-1]
+	return item
+}
+
+// waitingWork is the information about work that is waiting for admission.
+type waitingWork struct {
+	priority   WorkPriority
+	createTime int64
+	// ch is used to inform the waiting goroutine that the work was granted.
+	// It is buffered so that the granter never blocks.
+	ch chan struct{}
+	// heapIndex is the index of the work in the waitingWorkHeap, or -1 if the
+	// work has been granted.
+	heapIndex      int
+	enqueueingTime time.Time
+}
+
+// waitingWorkHeap is a heap of waiting work within a tenant, ordered in
+// decreasing order of priority, and then in increasing order of create time.
+type waitingWorkHeap []*waitingWork
+
+var _ heap.Interface = (*waitingWorkHeap)(nil)
+
+func (wwh waitingWorkHeap) less(i, j int) bool {
+	if wwh[i].priority == wwh[j].priority {
+		return wwh[i].createTime < wwh[j].createTime
+	}
+	return wwh[i].priority > wwh[j].priority
+}
+
+func (wwh *waitingWorkHeap) Len() int {
+	return len(*wwh)
+}
+
+func (wwh *waitingWorkHeap) Less(i, j int) bool {
+	return wwh.less(i, j)
+}
+
+func (wwh *waitingWorkHeap) Swap(i, j int) {
+	(*wwh)[i], (*wwh)[j] = (*wwh)[j], (*wwh)[i]
+	(*wwh)[i].heapIndex = i
+	(*wwh)[j].heapIndex = j
+}
+
+func (wwh *waitingWorkHeap) Push(x interface{}) {
+	n := len(*wwh)
+	item := x.(*waitingWork)
+	item.heapIndex = n
+	*wwh = append(*wwh, item)
+}
+
+func (wwh *waitingWorkHeap) Pop() interface{} {
+	old := *wwh
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.heapIndex = -1
+	*wwh = old[0 : n-1]
+	return item
+}
+
+var (
+	requestedMeta = metric.Metadata{
+		Name:        "admission.requested.",
+		Help:        "Number of requests",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	admittedMeta = metric.Metadata{
+		Name:        "admission.admitted.",
+		Help:        "Number of requests admitted",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	erroredMeta = metric.Metadata{
+		Name:        "admission.errored.",
+		Help:        "Number of requests not admitted due to error",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	waitDurationSumMeta = metric.Metadata{
+		Name:        "admission.wait_sum.",
+		Help:        "Total wait time in nanoseconds",
+		Measurement: "Wait time",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	waitDurationsMeta = metric.Metadata{
+		Name:        "admission.wait_durations.",
+		Help:        "Wait time durations for requests that waited",
+		Measurement: "Wait time",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	waitQueueLengthMeta = metric.Metadata{
+		Name:        "admission.wait_queue_length.",
+		Help:        "Length of wait queue",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+)
+
+func addName(name string, meta metric.Metadata) metric.Metadata {
+	rv := meta
+	rv.Name = rv.Name + name
+	rv.Help = rv.Help + " for " + name
+	return rv
+}
+
+// WorkQueueMetrics are the metrics of a WorkQueue.
+type WorkQueueMetrics struct {
+	Requested       *metric.Counter
+	Admitted        *metric.Counter
+	Errored         *metric.Counter
+	WaitDurationSum *metric.Counter
+	WaitDurations   *metric.Histogram
+	WaitQueueLength *metric.Gauge
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (WorkQueueMetrics) MetricStruct() {}
+
+func makeWorkQueueMetrics(name string, histogramWindowInterval time.Duration) WorkQueueMetrics {
+	return WorkQueueMetrics{
+		Requested:       metric.NewCounter(addName(name, requestedMeta)),
+		Admitted:        metric.NewCounter(addName(name, admittedMeta)),
+		Errored:         metric.NewCounter(addName(name, erroredMeta)),
+		WaitDurationSum: metric.NewCounter(addName(name, waitDurationSumMeta)),
+		WaitDurations:   metric.NewLatency(addName(name, waitDurationsMeta), histogramWindowInterval),
+		WaitQueueLength: metric.NewGauge(addName(name, waitQueueLengthMeta)),
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type builderWithMu struct {
+	mu  syncutil.Mutex
+	buf strings.Builder
+}
+
+func (b *builderWithMu) printf(format string, a ...interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	fmt.Fprintf(&b.buf, format, a...)
+}
+
+func (b *builderWithMu) stringAndReset() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	str := b.buf.String()
+	b.buf.Reset()
+	return str
+}
+
+type testGranter struct {
+	buf                   *builderWithMu
+	r                     requester
+	returnValueFromTryGet bool
+}
+
+var _ granter = &testGranter{}
+
+func (tg *testGranter) tryGet() bool {
+	tg.buf.printf("tryGet: returning %t\n", tg.returnValueFromTryGet)
+	return tg.returnValueFromTryGet
+}
+
+func (tg *testGranter) returnGrant() {
+	tg.buf.printf("returnGrant\n")
+}
+
+func (tg *testGranter) tookWithoutPermission() {
+	tg.buf.printf("tookWithoutPermission\n")
+}
+
+// tryGrant is called by the WorkQueue after queueing work, and is not printed
+// since it races with the test.
+func (tg *testGranter) tryGrant() {}
+
+func (tg *testGranter) grant() bool {
+	rv := tg.r.granted()
+	tg.buf.printf("granted: returned %t\n", rv)
+	return rv
+}
+
+type testWork struct {
+	tenantID roachpb.TenantID
+	cancel   context.CancelFunc
+}
+
+// TestWorkQueueBasic is a datadriven test with the following commands:
+//
+// init
+// set-try-get-return-value v=<true|false>
+// admit id=<int> tenant=<int> priority=<int> create-time=<int> bypass=<true|false>
+// granted
+// cancel-work id=<int>
+// work-done id=<int>
+// gc-tenants-and-reset-tokens
+// print
+func TestWorkQueueBasic(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var q *WorkQueue
+	closeFn := func() {
+		if q != nil {
+			q.close()
+		}
+	}
+	defer closeFn()
+	var tg *testGranter
+	var wrkMap map[int]*testWork
+	var buf builderWithMu
+	// results receives the outcome of each Admit call, which may complete
+	// asynchronously.
+	var results chan string
+	waitForResult := func(t *testing.T) string {
+		select {
+		case res := <-results:
+			return res
+		case <-time.After(testutils.DefaultSucceedsSoonDuration):
+			t.Fatal("timed out waiting for admit result")
+			return ""
+		}
+	}
+
+	datadriven.RunTest(t, "testdata/work_queue", func(t *testing.T, d *datadriven.TestData) string {
+		switch d.Cmd {
+		case "init":
+			closeFn()
+			tg = &testGranter{buf: &buf}
+			wrkMap = make(map[int]*testWork)
+			results = make(chan string, 100)
+			st := cluster.MakeTestingClusterSettings()
+			KVAdmissionControlEnabled.Override(&st.SV, true)
+			opts := makeWorkQueueOptions(KVWork)
+			opts.disableTickerForTesting = true
+			q = makeWorkQueue(KVWork, tg, st, time.Minute, opts).(*WorkQueue)
+			tg.r = q
+			return ""
+
+		case "set-try-get-return-value":
+			d.ScanArgs(t, "v", &tg.returnValueFromTryGet)
+			return ""
+
+		case "admit":
+			var id, tenant, priority, createTime int
+			var bypass bool
+			d.ScanArgs(t, "id", &id)
+			d.ScanArgs(t, "tenant", &tenant)
+			d.ScanArgs(t, "priority", &priority)
+			d.ScanArgs(t, "create-time", &createTime)
+			d.ScanArgs(t, "bypass", &bypass)
+			if _, ok := wrkMap[id]; ok {
+				t.Fatalf("id %d is already used", id)
+			}
+			tenantID := roachpb.MakeTenantID(uint64(tenant))
+			ctx, cancel := context.WithCancel(context.Background())
+			wrkMap[id] = &testWork{tenantID: tenantID, cancel: cancel}
+			info := WorkInfo{
+				TenantID:        tenantID,
+				Priority:        WorkPriority(priority),
+				CreateTime:      int64(createTime),
+				BypassAdmission: bypass,
+			}
+			waitingBefore := q.numWaiting()
+			go func(ctx context.Context, info WorkInfo, id int) {
+				enabled, err := q.Admit(ctx, info)
+				require.True(t, enabled)
+				if err != nil {
+					results <- fmt.Sprintf("id %d: admit failed", id)
+				} else {
+					results <- fmt.Sprintf("id %d: admit succeeded", id)
+				}
+			}(ctx, info, id)
+			// Wait until the work is either admitted or queued.
+			var res string
+			testutils.SucceedsSoon(t, func() error {
+				select {
+				case res = <-results:
+					return nil
+				default:
+				}
+				if q.numWaiting() > waitingBefore {
+					return nil
+				}
+				return errors.New("work neither admitted nor queued")
+			})
+			if res != "" {
+				buf.printf("%s\n", res)
+			}
+			return buf.stringAndReset()
+
+		case "granted":
+			if tg.grant() {
+				buf.printf("%s\n", waitForResult(t))
+			}
+			return buf.stringAndReset()
+
+		case "cancel-work":
+			var id int
+			d.ScanArgs(t, "id", &id)
+			work, ok := wrkMap[id]
+			if !ok {
+				return fmt.Sprintf("unknown id: %d", id)
+			}
+			work.cancel()
+			buf.printf("%s\n", waitForResult(t))
+			return buf.stringAndReset()
+
+		case "work-done":
+			var id int
+			d.ScanArgs(t, "id", &id)
+			work, ok := wrkMap[id]
+			if !ok {
+				return fmt.Sprintf("unknown id: %d", id)
+			}
+			q.AdmittedWorkDone(work.tenantID)
+			return buf.stringAndReset()
+
+		case "gc-tenants-and-reset-tokens":
+			q.gcTenantsAndResetTokens()
+			return q.String()
+
+		case "print":
+			return q.String()
+
+		default:
+			return fmt.Sprintf("unknown command: %s", d.Cmd)
+		}
+	})
+}
+
+// numWaiting returns the number of waiting work in the queue.
+func (q *WorkQueue) numWaiting() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for _, tenant := range q.mu.tenants {
+		n += len(tenant.waitingWorkHeap)
+	}
+	return n
+}
+
+func TestWorkQueueDisabled(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	st := cluster.MakeTestingClusterSettings()
+	tg := &testGranter{buf: &builderWithMu{}}
+	q := makeWorkQueue(KVWork, tg, st, time.Minute, makeWorkQueueOptions(KVWork)).(*WorkQueue)
+	defer q.close()
+	enabled, err := q.Admit(context.Background(), WorkInfo{TenantID: roachpb.SystemTenantID})
+	require.NoError(t, err)
+	require.False(t, enabled)
+	require.Equal(t, "", tg.buf.stringAndReset())
+	require.Equal(t, int64(0), q.Metrics().Requested.Count())
+}
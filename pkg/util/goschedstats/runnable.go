@@ -0,0 +1,146 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package goschedstats exposes statistics about the Go scheduler. The number
+// of runnable goroutines that are waiting for a P is a good indicator of CPU
+// overload, and is used by admission control.
+package goschedstats
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// RunnableCountCallback is provided the current number of runnable goroutines
+// and GOMAXPROCS.
+type RunnableCountCallback func(numRunnable int, numProcs int)
+
+const (
+	// samplePeriodShort is the sampling period used when there are registered
+	// callbacks. Runnable goroutine counts fluctuate rapidly, so consumers
+	// need frequent samples to react to overload.
+	samplePeriodShort = time.Millisecond
+	// samplePeriodLong is the sampling period used when there are no
+	// registered callbacks, which is sufficient to maintain
+	// RecentNormalizedRunnableGoroutines.
+	samplePeriodLong = 250 * time.Millisecond
+	// averagingPeriod is the period over which samples are averaged before
+	// being folded into the value returned by
+	// RecentNormalizedRunnableGoroutines.
+	averagingPeriod = 250 * time.Millisecond
+)
+
+var callbackInfo struct {
+	syncutil.Mutex
+	nextID    int64
+	callbacks []callbackWithID
+}
+
+type callbackWithID struct {
+	id int64
+	cb RunnableCountCallback
+}
+
+// recentNormalizedRunnable stores the bits of a float64 that is an
+// exponentially smoothed average of the number of runnable goroutines per P.
+var recentNormalizedRunnable uint64
+
+// RegisterRunnableCountCallback registers a callback to be run with the
+// runnable goroutine count and GOMAXPROCS every millisecond. The callback must
+// be fast as it runs on the sampling goroutine. The returned id can be used to
+// unregister the callback.
+func RegisterRunnableCountCallback(cb RunnableCountCallback) (id int64) {
+	callbackInfo.Lock()
+	defer callbackInfo.Unlock()
+	id = callbackInfo.nextID
+	callbackInfo.nextID++
+	callbackInfo.callbacks = append(callbackInfo.callbacks, callbackWithID{id: id, cb: cb})
+	return id
+}
+
+// UnregisterRunnableCountCallback unregisters the callback with the given id.
+func UnregisterRunnableCountCallback(id int64) {
+	callbackInfo.Lock()
+	defer callbackInfo.Unlock()
+	for i := range callbackInfo.callbacks {
+		if callbackInfo.callbacks[i].id == id {
+			callbackInfo.callbacks = append(
+				callbackInfo.callbacks[:i], callbackInfo.callbacks[i+1:]...)
+			return
+		}
+	}
+}
+
+// RecentNormalizedRunnableGoroutines returns a recent average of the number of
+// runnable goroutines per GOMAXPROCS. It is always zero if Supported is false.
+func RecentNormalizedRunnableGoroutines() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&recentNormalizedRunnable))
+}
+
+// sampler accumulates samples of the runnable goroutine count and runs the
+// registered callbacks.
+type sampler struct {
+	period   time.Duration
+	ticker   *time.Ticker
+	sumNorm  float64
+	numSum   int
+	lastTime time.Time
+}
+
+func (s *sampler) sample(now time.Time) {
+	numRunnable, numProcs := numRunnableGoroutines()
+	if numProcs > 0 {
+		s.sumNorm += float64(numRunnable) / float64(numProcs)
+		s.numSum++
+	}
+	if now.Sub(s.lastTime) >= averagingPeriod && s.numSum > 0 {
+		// Smooth the average over the last period with the previous value, so
+		// that a short spike does not dominate.
+		prev := RecentNormalizedRunnableGoroutines()
+		next := 0.5*prev + 0.5*(s.sumNorm/float64(s.numSum))
+		atomic.StoreUint64(&recentNormalizedRunnable, math.Float64bits(next))
+		s.sumNorm = 0
+		s.numSum = 0
+		s.lastTime = now
+	}
+
+	callbackInfo.Lock()
+	period := samplePeriodLong
+	if len(callbackInfo.callbacks) > 0 {
+		period = samplePeriodShort
+	}
+	for i := range callbackInfo.callbacks {
+		callbackInfo.callbacks[i].cb(numRunnable, numProcs)
+	}
+	callbackInfo.Unlock()
+
+	if period != s.period {
+		s.period = period
+		s.ticker.Reset(period)
+	}
+}
+
+func init() {
+	s := &sampler{
+		period:   samplePeriodLong,
+		ticker:   time.NewTicker(samplePeriodLong),
+		lastTime: timeutil.Now(),
+	}
+	go func() {
+		for {
+			t := <-s.ticker.C
+			s.sample(t)
+		}
+	}()
+}
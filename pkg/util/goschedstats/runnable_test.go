@@ -0,0 +1,84 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package goschedstats
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumRunnableGoroutines(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	if !Supported {
+		t.Skip("runnable goroutine counts are not supported on this Go version")
+	}
+
+	// Start 4 goroutines per P that spin, and verify that the run queues
+	// eventually contain some of them.
+	ctx, cancel := context.WithCancel(context.Background())
+	g := ctxgroup.WithContext(ctx)
+	for i := 0; i < 4*runtime.GOMAXPROCS(0); i++ {
+		g.GoCtx(func(ctx context.Context) error {
+			for ctx.Err() == nil {
+			}
+			return nil
+		})
+	}
+	testutils.SucceedsSoon(t, func() error {
+		if n, _ := numRunnableGoroutines(); n == 0 {
+			return errors.New("no runnable goroutines")
+		}
+		return nil
+	})
+	cancel()
+	require.NoError(t, g.Wait())
+}
+
+func TestRunnableCountCallback(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var calls int64
+	var procs int64
+	id := RegisterRunnableCountCallback(func(numRunnable int, numProcs int) {
+		atomic.AddInt64(&calls, 1)
+		atomic.StoreInt64(&procs, int64(numProcs))
+	})
+	testutils.SucceedsSoon(t, func() error {
+		if atomic.LoadInt64(&calls) < 10 {
+			return errors.New("callback not called enough")
+		}
+		return nil
+	})
+	UnregisterRunnableCountCallback(id)
+	require.Equal(t, int64(runtime.GOMAXPROCS(0)), atomic.LoadInt64(&procs))
+
+	// Once unregistered, the callback is no longer called. The sampler may be
+	// running the callback concurrently with the unregistration, so allow for
+	// one more call.
+	callsAfterUnregister := atomic.LoadInt64(&calls)
+	testutils.SucceedsSoon(t, func() error {
+		callbackInfo.Lock()
+		defer callbackInfo.Unlock()
+		if len(callbackInfo.callbacks) != 0 {
+			return errors.New("callback still registered")
+		}
+		return nil
+	})
+	require.LessOrEqual(t, atomic.LoadInt64(&calls), callsAfterUnregister+1)
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// +build gc,go1.15,!go1.17
+
+package goschedstats
+
+import (
+	"runtime"
+	_ "unsafe" // required by go:linkname
+)
+
+// Supported is true if the runtime of the Go version used to build the binary
+// can be inspected for the number of runnable goroutines.
+const Supported = true
+
+// The following types mirror the layout of the corresponding structures in
+// runtime/runtime2.go for go1.15 and go1.16, up to and including the fields
+// read below. They must be revisited whenever the Go version is changed.
+
+type puintptr uintptr
+
+type muintptr uintptr
+
+type guintptr uintptr
+
+type sysmontick struct {
+	schedtick   uint32
+	schedwhen   int64
+	syscalltick uint32
+	syscallwhen int64
+}
+
+type pageCache struct {
+	base  uintptr
+	cache uint64
+	scav  uint64
+}
+
+type p struct {
+	id          int32
+	status      uint32
+	link        puintptr
+	schedtick   uint32
+	syscalltick uint32
+	sysmontick  sysmontick
+	m           muintptr
+	mcache      uintptr
+	pcache      pageCache
+	raceprocctx uintptr
+
+	deferpool    [5][]uintptr
+	deferpoolbuf [5][32]uintptr
+
+	goidcache    uint64
+	goidcacheend uint64
+
+	runqhead uint32
+	runqtail uint32
+	runq     [256]guintptr
+	runnext  guintptr
+}
+
+type mutex struct {
+	key uintptr
+}
+
+type gQueue struct {
+	head guintptr
+	tail guintptr
+}
+
+type schedt struct {
+	goidgen   uint64
+	lastpoll  uint64
+	pollUntil uint64
+
+	lock mutex
+
+	midle        muintptr
+	nmidle       int32
+	nmidlelocked int32
+	mnext        int64
+	maxmcount    int32
+	nmsys        int32
+	nmfreed      int64
+
+	ngsys uint32
+
+	pidle      puintptr
+	npidle     uint32
+	nmspinning uint32
+
+	runq     gQueue
+	runqsize int32
+}
+
+//go:linkname allp runtime.allp
+var allp []*p
+
+//go:linkname sched runtime.sched
+var sched schedt
+
+// numRunnableGoroutines returns the number of goroutines waiting in the global
+// and per-P run queues, and GOMAXPROCS. The run queues are read without
+// acquiring the scheduler lock, so the count is approximate.
+func numRunnableGoroutines() (numRunnable int, numProcs int) {
+	numRunnable = int(sched.runqsize)
+	for _, pp := range allp {
+		// runqtail is always ahead of runqhead, modulo wraparound.
+		numRunnable += int(pp.runqtail - pp.runqhead)
+		if pp.runnext != 0 {
+			numRunnable++
+		}
+	}
+	return numRunnable, runtime.GOMAXPROCS(0)
+}
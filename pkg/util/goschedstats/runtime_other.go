@@ -0,0 +1,25 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// +build !gc !go1.15 go1.17
+
+package goschedstats
+
+import "runtime"
+
+// Supported is true if the runtime of the Go version used to build the binary
+// can be inspected for the number of runnable goroutines.
+const Supported = false
+
+// numRunnableGoroutines always reports zero runnable goroutines since the
+// runtime's run queues cannot be inspected on this Go version.
+func numRunnableGoroutines() (numRunnable int, numProcs int) {
+	return 0, runtime.GOMAXPROCS(0)
+}
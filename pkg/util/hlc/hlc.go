@@ -334,7 +334,13 @@ func (c *Clock) PhysicalTime() time.Time {
 // the maximum clock offset. To receive an error response instead of forcing the
 // update in case the remote timestamp is too far into the future, use
 // UpdateAndCheckMaxOffset() instead.
+//
+// Synthetic timestamps (see TimestampFlag_SYNTHETIC) do not correspond to a
+// reading of any node's clock and are ignored.
 func (c *Clock) Update(rt Timestamp) {
+	if rt.IsFlagSet(TimestampFlag_SYNTHETIC) {
+		return
+	}
 
 	// Fast path to avoid grabbing the mutex if the remote time is behind. This
 	// requires c.mu.timestamp.WallTime to be written atomically, even though
@@ -365,8 +371,13 @@ func (c *Clock) Update(rt Timestamp) {
 
 // UpdateAndCheckMaxOffset is like Update, but also takes the wall time into account and
 // returns an error in the event that the supplied remote timestamp exceeds
-// the wall clock time by more than the maximum clock offset.
+// the wall clock time by more than the maximum clock offset. Like Update, it
+// ignores synthetic timestamps, which may legitimately lead the wall clock
+// time by more than the maximum clock offset.
 func (c *Clock) UpdateAndCheckMaxOffset(ctx context.Context, rt Timestamp) error {
+	if rt.IsFlagSet(TimestampFlag_SYNTHETIC) {
+		return nil
+	}
 	var err error
 	physicalClock := c.getPhysicalClockAndCheck(ctx)
 
@@ -385,6 +396,35 @@ func (c *Clock) UpdateAndCheckMaxOffset(ctx context.Context, rt Timestamp) error
 	return nil
 }
 
+// SleepUntil blocks until the clock's current time is at or above the
+// provided timestamp, or until the context is canceled, in which case the
+// context's error is returned. It is used to wait out timestamps in the future,
+// such as the commit timestamp of a transaction that wrote to a range whose
+// closed timestamps lead present time.
+func (c *Clock) SleepUntil(ctx context.Context, t Timestamp) error {
+	var timer timeutil.Timer
+	defer timer.Stop()
+	for {
+		now := c.Now()
+		if t.LessEq(now) {
+			return nil
+		}
+		d := time.Duration(t.WallTime - now.WallTime)
+		if d <= 0 {
+			// Only the logical component is ahead. Wait for the next tick of
+			// the physical clock.
+			d = time.Nanosecond
+		}
+		timer.Reset(d)
+		select {
+		case <-timer.C:
+			timer.Read = true
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // setForwardJumpCheckEnabled atomically sets forwardClockJumpCheckEnabled
 func (c *Clock) setForwardJumpCheckEnabled(forwardJumpCheckEnabled bool) {
 	if forwardJumpCheckEnabled {
@@ -344,6 +344,58 @@ func TestHLCClock(t *testing.T) {
 	}
 }
 
+// TestHLCClockIgnoresSyntheticTimestamps verifies that synthetic timestamps,
+// which may lead the physical time of all nodes by more than the maximum clock
+// offset, are not used to advance the clock.
+func TestHLCClockIgnoresSyntheticTimestamps(t *testing.T) {
+	m := NewManualClock(10)
+	c := NewClock(m.UnixNano, 5*time.Nanosecond)
+	syn := Timestamp{WallTime: 100}.SetFlag(TimestampFlag_SYNTHETIC)
+
+	c.Update(syn)
+	if now := c.Now(); now.WallTime != 10 {
+		t.Fatalf("clock advanced to synthetic timestamp: %s", now)
+	}
+	if err := c.UpdateAndCheckMaxOffset(context.Background(), syn); err != nil {
+		t.Fatal(err)
+	}
+	if now := c.Now(); now.WallTime != 10 {
+		t.Fatalf("clock advanced to synthetic timestamp: %s", now)
+	}
+}
+
+func TestHLCSleepUntil(t *testing.T) {
+	ctx := context.Background()
+	m := NewManualClock(10)
+	c := NewClock(m.UnixNano, time.Nanosecond)
+
+	// A timestamp in the past does not block.
+	if err := c.SleepUntil(ctx, Timestamp{WallTime: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.SleepUntil(ctx, Timestamp{WallTime: 20})
+	}()
+	select {
+	case err := <-errCh:
+		t.Fatalf("SleepUntil returned early: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	m.Set(20)
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+
+	// A canceled context unblocks the caller.
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := c.SleepUntil(ctx, Timestamp{WallTime: 100}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled error, got %v", err)
+	}
+}
+
 // TestExampleManualClock shows how a manual clock can be
 // used as a physical clock. This is useful for testing.
 func TestExampleManualClock(t *testing.T) {
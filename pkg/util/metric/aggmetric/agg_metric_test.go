@@ -108,6 +108,19 @@ foo_counter{tenant_id="3"} 4`,
 			writePrometheusMetrics(t))
 	})
 
+	t.Run("update", func(t *testing.T) {
+		g2.Update(5)
+		g3.Update(1)
+		require.Equal(t,
+			`bar_gauge 6
+bar_gauge{tenant_id="2"} 5
+bar_gauge{tenant_id="3"} 1
+foo_counter 6
+foo_counter{tenant_id="2"} 0
+foo_counter{tenant_id="3"} 4`,
+			writePrometheusMetrics(t))
+	})
+
 	t.Run("panic on label length mismatch", func(t *testing.T) {
 		require.Panics(t, func() { c.AddChild() })
 		require.Panics(t, func() { g.AddChild("", "") })
@@ -128,3 +128,9 @@ func (g *Gauge) Inc(i int64) {
 func (g *Gauge) Dec(i int64) {
 	g.Inc(-i)
 }
+
+// Update sets the gauge's value.
+func (g *Gauge) Update(val int64) {
+	old := atomic.SwapInt64(&g.value, val)
+	g.parent.g.Inc(val - old)
+}
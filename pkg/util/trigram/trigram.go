@@ -0,0 +1,147 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package trigram implements the trigram extraction and similarity functions
+// of the PostgreSQL pg_trgm extension.
+package trigram
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultSimilarityThreshold is the default similarity threshold used by the
+// trigram similarity operator (%), as in PostgreSQL.
+const DefaultSimilarityThreshold = 0.3
+
+// MakeTrigrams returns the sorted, de-duplicated trigrams of the given string.
+// Like pg_trgm, the string is split into words of alphanumeric characters,
+// and each word is lowercased before its trigrams are extracted.
+//
+// If pad is true, each word is padded with two spaces at its start and one
+// space at its end, so that the trigrams also capture the boundaries of
+// words; this is how trigrams are extracted for indexing and for similarity
+// calculations. If pad is false, only the trigrams contained in the words
+// themselves are returned, which is useful when the words are fragments of
+// larger words, e.g. the literal parts of a LIKE pattern.
+func MakeTrigrams(s string, pad bool) []string {
+	trigrams := appendTrigrams(nil /* trigrams */, s, pad)
+	if len(trigrams) == 0 {
+		return nil
+	}
+	sort.Strings(trigrams)
+	// De-duplicate the sorted trigrams.
+	n := 1
+	for i := 1; i < len(trigrams); i++ {
+		if trigrams[i] != trigrams[n-1] {
+			trigrams[n] = trigrams[i]
+			n++
+		}
+	}
+	return trigrams[:n]
+}
+
+// appendTrigrams appends the trigrams of each word of the given string to the
+// given slice, in the order in which they appear in the string.
+func appendTrigrams(trigrams []string, s string, pad bool) []string {
+	isWordRune := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return !isWordRune(r) }) {
+		runes := []rune(strings.ToLower(word))
+		if pad {
+			padded := make([]rune, 0, len(runes)+3)
+			padded = append(padded, ' ', ' ')
+			padded = append(padded, runes...)
+			padded = append(padded, ' ')
+			runes = padded
+		}
+		for i := 0; i+3 <= len(runes); i++ {
+			trigrams = append(trigrams, string(runes[i:i+3]))
+		}
+	}
+	return trigrams
+}
+
+// Similarity returns the similarity of the two given strings: the number of
+// trigrams they share, divided by the number of distinct trigrams in either
+// string. It ranges between 0 (no shared trigrams) and 1 (the same trigrams).
+func Similarity(l, r string) float64 {
+	lTrigrams, rTrigrams := MakeTrigrams(l, true /* pad */), MakeTrigrams(r, true /* pad */)
+	if len(lTrigrams) == 0 || len(rTrigrams) == 0 {
+		return 0
+	}
+	// Both slices are sorted, so we can count the shared trigrams by merging
+	// them.
+	var shared int
+	for i, j := 0, 0; i < len(lTrigrams) && j < len(rTrigrams); {
+		switch {
+		case lTrigrams[i] < rTrigrams[j]:
+			i++
+		case lTrigrams[i] > rTrigrams[j]:
+			j++
+		default:
+			shared++
+			i++
+			j++
+		}
+	}
+	return calcSimilarity(shared, len(lTrigrams), len(rTrigrams))
+}
+
+// WordSimilarity returns the greatest similarity between the trigrams of the
+// first string and any contiguous extent of the ordered trigrams of the
+// second string. It is useful to find the string in which a word (or part of
+// a word) occurs, regardless of the other words in the string.
+func WordSimilarity(l, r string) float64 {
+	lTrigrams := MakeTrigrams(l, true /* pad */)
+	if len(lTrigrams) == 0 {
+		return 0
+	}
+	inLeft := make(map[string]struct{}, len(lTrigrams))
+	for _, t := range lTrigrams {
+		inLeft[t] = struct{}{}
+	}
+	rTrigrams := appendTrigrams(nil /* trigrams */, r, true /* pad */)
+
+	var best float64
+	seen := make(map[string]struct{}, len(rTrigrams))
+	for start := range rTrigrams {
+		// An extent that starts with a trigram that is not shared can always be
+		// improved by removing that trigram.
+		if _, ok := inLeft[rTrigrams[start]]; !ok {
+			continue
+		}
+		for t := range seen {
+			delete(seen, t)
+		}
+		var shared int
+		for _, t := range rTrigrams[start:] {
+			if _, ok := seen[t]; ok {
+				continue
+			}
+			seen[t] = struct{}{}
+			if _, ok := inLeft[t]; ok {
+				shared++
+				if sim := calcSimilarity(shared, len(lTrigrams), len(seen)); sim > best {
+					best = sim
+				}
+			}
+		}
+	}
+	return best
+}
+
+// calcSimilarity returns the similarity of two sets of trigrams with the
+// given sizes that share the given number of trigrams.
+func calcSimilarity(shared, lLen, rLen int) float64 {
+	return float64(shared) / float64(lLen+rLen-shared)
+}
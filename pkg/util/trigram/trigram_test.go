@@ -0,0 +1,64 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package trigram
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMakeTrigrams(t *testing.T) {
+	testCases := []struct {
+		s        string
+		pad      bool
+		expected []string
+	}{
+		{s: "", pad: true, expected: nil},
+		{s: "a", pad: true, expected: []string{"  a", " a "}},
+		{s: "cat", pad: true, expected: []string{"  c", " ca", "at ", "cat"}},
+		{s: "cat", pad: false, expected: []string{"cat"}},
+		{s: "ca", pad: false, expected: nil},
+		{s: "Cat cAT", pad: true, expected: []string{"  c", " ca", "at ", "cat"}},
+		{s: "foo-bar!", pad: false, expected: []string{"bar", "foo"}},
+		{s: "aaaa", pad: false, expected: []string{"aaa"}},
+		{s: "héllo", pad: false, expected: []string{"hél", "llo", "éll"}},
+	}
+	for _, tc := range testCases {
+		if actual := MakeTrigrams(tc.s, tc.pad); !reflect.DeepEqual(tc.expected, actual) {
+			t.Errorf("MakeTrigrams(%q, %t): expected %q, got %q", tc.s, tc.pad, tc.expected, actual)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	testCases := []struct {
+		l, r           string
+		similarity     float64
+		wordSimilarity float64
+	}{
+		{l: "word", r: "two words", similarity: 4.0 / 11.0, wordSimilarity: 0.8},
+		{l: "word", r: "word", similarity: 1, wordSimilarity: 1},
+		{l: "Word", r: "wORD", similarity: 1, wordSimilarity: 1},
+		{l: "word", r: "", similarity: 0, wordSimilarity: 0},
+		{l: "", r: "", similarity: 0, wordSimilarity: 0},
+		{l: "abc", r: "xyz", similarity: 0, wordSimilarity: 0},
+		{l: "two words", r: "word", similarity: 4.0 / 11.0, wordSimilarity: 0.4},
+	}
+	for _, tc := range testCases {
+		if actual := Similarity(tc.l, tc.r); math.Abs(actual-tc.similarity) > 1e-9 {
+			t.Errorf("Similarity(%q, %q): expected %v, got %v", tc.l, tc.r, tc.similarity, actual)
+		}
+		if actual := WordSimilarity(tc.l, tc.r); math.Abs(actual-tc.wordSimilarity) > 1e-9 {
+			t.Errorf("WordSimilarity(%q, %q): expected %v, got %v", tc.l, tc.r, tc.wordSimilarity, actual)
+		}
+	}
+}
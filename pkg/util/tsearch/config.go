@@ -0,0 +1,201 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// Config is a text search configuration, which determines how the words of a
+// document or query are turned into lexemes.
+type Config struct {
+	name string
+	// normalize returns the lexeme for the given lowercase word, or false if the
+	// word is a stop word that should be ignored.
+	normalize func(word string) (string, bool)
+}
+
+// Name returns the name of the configuration.
+func (c *Config) Name() string {
+	return c.name
+}
+
+// DefaultConfigName is the name of the text search configuration used when
+// none is specified, unless the session specifies otherwise.
+const DefaultConfigName = "pg_catalog.english"
+
+var configs = map[string]*Config{
+	"simple": {
+		name:      "simple",
+		normalize: func(word string) (string, bool) { return word, true },
+	},
+	"english": {
+		name:      "english",
+		normalize: normalizeEnglish,
+	},
+}
+
+// Configs returns the names of the supported text search configurations.
+func Configs() []string {
+	return []string{"english", "simple"}
+}
+
+// GetConfig returns the text search configuration with the given name, which
+// may be qualified with the pg_catalog schema.
+func GetConfig(name string) (*Config, error) {
+	if c, ok := configs[strings.TrimPrefix(name, "pg_catalog.")]; ok {
+		return c, nil
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject,
+		"text search configuration %q does not exist", name)
+}
+
+// normalizeEnglish discards English stop words and stems the other words.
+// Words that contain digits or non-ASCII letters are only lowercased.
+func normalizeEnglish(word string) (string, bool) {
+	if _, ok := englishStopWords[word]; ok {
+		return "", false
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word, true
+		}
+	}
+	return stemEnglish(word), true
+}
+
+// words splits the given text into words, which are maximal sequences of
+// letters and digits, and lowercases them. This is a simplified version of
+// the default text search parser of PostgreSQL, which additionally
+// recognizes tokens such as email addresses, URLs and hyphenated words.
+func words(text string) []string {
+	var res []string
+	for _, w := range strings.FieldsFunc(text, func(r rune) bool { return !isWordChar(r) }) {
+		res = append(res, strings.Map(unicode.ToLower, w))
+	}
+	return res
+}
+
+// ToTSVector parses the given document into a tsvector, using the given
+// configuration to normalize its words. The position of each lexeme is the
+// position of its word in the document, counting stop words.
+func (c *Config) ToTSVector(document string) TSVector {
+	var terms []tsTerm
+	for i, w := range words(document) {
+		lexeme, ok := c.normalize(w)
+		if !ok || len(lexeme) > maxLexemeLen {
+			continue
+		}
+		position := i + 1
+		if position > maxPosition {
+			position = maxPosition
+		}
+		terms = append(terms, tsTerm{
+			lexeme:    lexeme,
+			positions: []tsPosition{{position: uint16(position), weight: weightD}},
+		})
+	}
+	return makeTSVector(terms)
+}
+
+// ToTSQuery parses the given text into a tsquery, using the given
+// configuration to normalize its operands. An operand that consists of
+// several words is replaced by the followed-by expression of their lexemes,
+// and operands that are stop words are removed.
+func (c *Config) ToTSQuery(text string) (TSQuery, error) {
+	return parseTSQuery(text, func(lexeme string, weightMask byte, prefix bool) (*tsNode, error) {
+		var res *tsNode
+		distance := 1
+		for _, w := range words(lexeme) {
+			l, ok := c.normalize(w)
+			if !ok {
+				distance++
+				continue
+			}
+			leaf := &tsNode{lexeme: l, weightMask: weightMask, prefix: prefix}
+			if res == nil {
+				res = leaf
+			} else {
+				res = &tsNode{op: opFollowedBy, distance: clampDistance(distance), l: res, r: leaf}
+			}
+			distance = 1
+		}
+		return res, nil
+	})
+}
+
+// PlainToTSQuery parses the given text into a tsquery that matches all of the
+// lexemes of its words, ignoring punctuation and operators.
+func (c *Config) PlainToTSQuery(text string) TSQuery {
+	return c.plainToTSQuery(text, opAnd)
+}
+
+// PhraseToTSQuery parses the given text into a tsquery that matches the
+// lexemes of its words in the same order, ignoring punctuation and operators.
+func (c *Config) PhraseToTSQuery(text string) TSQuery {
+	return c.plainToTSQuery(text, opFollowedBy)
+}
+
+func (c *Config) plainToTSQuery(text string, op tsOperator) TSQuery {
+	var root *tsNode
+	distance := 1
+	for _, w := range words(text) {
+		lexeme, ok := c.normalize(w)
+		if !ok {
+			distance++
+			continue
+		}
+		leaf := &tsNode{lexeme: lexeme}
+		if root == nil {
+			root = leaf
+		} else {
+			root = &tsNode{op: op, l: root, r: leaf}
+			if op == opFollowedBy {
+				root.distance = clampDistance(distance)
+			}
+		}
+		distance = 1
+	}
+	return TSQuery{root: root}
+}
+
+// clampDistance returns the given distance, limited to the maximum distance of
+// a followed-by operator.
+func clampDistance(distance int) uint16 {
+	if distance > maxPosition {
+		return maxPosition
+	}
+	return uint16(distance)
+}
+
+// englishStopWords are the words that are ignored by the english
+// configuration. This is the list used by PostgreSQL.
+var englishStopWords = map[string]struct{}{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		i me my myself we our ours ourselves you your yours yourself yourselves
+		he him his himself she her hers herself it its itself they them their
+		theirs themselves what which who whom this that these those am is are
+		was were be been being have has had having do does did doing a an the
+		and but if or because as until while of at by for with about against
+		between into through during before after above below to from up down
+		in out on off over under again further then once here there when where
+		why how all any both each few more most other some such no nor not
+		only own same so than too very s t can will just don should now`,
+	) {
+		englishStopWords[w] = struct{}{}
+	}
+}
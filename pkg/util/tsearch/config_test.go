@@ -0,0 +1,75 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTSVector(t *testing.T) {
+	testCases := []struct {
+		config   string
+		document string
+		expected string
+	}{
+		{
+			config:   "english",
+			document: "a fat cat sat on a mat and ate a fat rat",
+			expected: "'ate':9 'cat':3 'fat':2,11 'mat':7 'rat':12 'sat':4",
+		},
+		{
+			config:   "pg_catalog.english",
+			document: "The Supernovae, the STARS and 42 galaxies!",
+			expected: "'42':6 'galaxi':7 'star':4 'supernova':2",
+		},
+		{config: "simple", document: "The Fat Rats", expected: "'fat':2 'rats':3 'the':1"},
+		{config: "simple", document: "", expected: ""},
+		{config: "simple", document: "¿Qué tal?", expected: "'qué':1 'tal':2"},
+	}
+	for _, tc := range testCases {
+		c, err := GetConfig(tc.config)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, c.ToTSVector(tc.document).String())
+	}
+	_, err := GetConfig("french")
+	require.EqualError(t, err, `text search configuration "french" does not exist`)
+}
+
+func TestToTSQuery(t *testing.T) {
+	english, err := GetConfig("english")
+	require.NoError(t, err)
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "supernovae & stars", expected: "'supernova' & 'star'"},
+		{input: "the & cat", expected: "'cat'"},
+		{input: "the | !a", expected: ""},
+		{input: "fat <-> the <-> cat", expected: "'fat' <2> 'cat'"},
+		{input: "fat <-> (the & cat)", expected: "'fat' <-> 'cat'"},
+		{input: "'fat cats':*B", expected: "'fat':*B <-> 'cat':*B"},
+		{input: "'fat the cats' & rats", expected: "'fat' <2> 'cat' & 'rat'"},
+		{input: "Running:A", expected: "'run':A"},
+	}
+	for _, tc := range testCases {
+		q, err := english.ToTSQuery(tc.input)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, q.String(), tc.input)
+	}
+	_, err = english.ToTSQuery("fat cats")
+	require.Error(t, err)
+
+	require.Equal(t, "'fat' & 'rat'", english.PlainToTSQuery("The Fat & Rats").String())
+	require.Equal(t, "'fat' <3> 'rat'", english.PhraseToTSQuery("The Fat of the Rats").String())
+	require.Equal(t, "", english.PlainToTSQuery("the").String())
+}
@@ -0,0 +1,322 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math"
+	"sort"
+)
+
+// matchResult is the result of matching a tsquery node against a tsvector.
+// Matches can be uncertain when the tsvector has no position information for
+// the lexemes of a followed-by operator.
+type matchResult int
+
+const (
+	matchNo matchResult = iota
+	matchYes
+	matchMaybe
+)
+
+// Matches returns true if the tsvector matches the tsquery, which is the
+// semantics of the @@ operator. An empty query never matches. Followed-by
+// operators whose lexemes have no positions in the tsvector are assumed to
+// match.
+func (q TSQuery) Matches(v TSVector) bool {
+	if q.root == nil {
+		return false
+	}
+	return q.root.match(v) != matchNo
+}
+
+// match evaluates the node as a boolean expression. Followed-by operators
+// are evaluated using the positions of their lexemes.
+func (n *tsNode) match(v TSVector) matchResult {
+	switch n.op {
+	case opLexeme:
+		start, end := n.find(v)
+		for i := start; i < end; i++ {
+			if n.weightMask == 0 {
+				return matchYes
+			}
+			if len(v[i].positions) == 0 {
+				return matchMaybe
+			}
+			for _, p := range v[i].positions {
+				if n.weightMask&(1<<p.weight) != 0 {
+					return matchYes
+				}
+			}
+		}
+		return matchNo
+
+	case opNot:
+		switch n.l.match(v) {
+		case matchNo:
+			return matchYes
+		case matchYes:
+			return matchNo
+		}
+		return matchMaybe
+
+	case opAnd:
+		l := n.l.match(v)
+		if l == matchNo {
+			return matchNo
+		}
+		r := n.r.match(v)
+		if r == matchNo {
+			return matchNo
+		}
+		if l == matchYes && r == matchYes {
+			return matchYes
+		}
+		return matchMaybe
+
+	case opOr:
+		l := n.l.match(v)
+		if l == matchYes {
+			return matchYes
+		}
+		r := n.r.match(v)
+		if r == matchYes {
+			return matchYes
+		}
+		if l == matchMaybe || r == matchMaybe {
+			return matchMaybe
+		}
+		return matchNo
+
+	case opFollowedBy:
+		var data phraseData
+		return n.matchPhrase(v, &data)
+	}
+	return matchNo
+}
+
+// find returns the range of the terms of the tsvector that a leaf node
+// matches.
+func (n *tsNode) find(v TSVector) (start, end int) {
+	if n.prefix {
+		return v.findPrefix(n.lexeme)
+	}
+	if i := v.find(n.lexeme); i >= 0 {
+		return i, i + 1
+	}
+	return 0, 0
+}
+
+// phraseData holds the positions at which a node matches, for the evaluation
+// of followed-by operators.
+type phraseData struct {
+	// positions is the sorted list of positions at which the match ends.
+	positions []int
+	// width is the number of positions spanned by the match, not counting the
+	// last one.
+	width int
+	// negate is true if the match occurs at all positions except the ones
+	// listed.
+	negate bool
+}
+
+// Flags that indicate which positions are output when merging the positions
+// of two operands.
+const (
+	emitBoth = 1 << iota
+	emitLeftOnly
+	emitRightOnly
+)
+
+// matchPhrase evaluates the node and fills in the positions at which it
+// matches. This follows the TS_phrase_execute function of PostgreSQL.
+func (n *tsNode) matchPhrase(v TSVector, data *phraseData) matchResult {
+	switch n.op {
+	case opLexeme:
+		start, end := n.find(v)
+		if start == end {
+			return matchNo
+		}
+		hasPositions := false
+		for i := start; i < end; i++ {
+			for _, p := range v[i].positions {
+				hasPositions = true
+				if n.weightMask == 0 || n.weightMask&(1<<p.weight) != 0 {
+					data.positions = append(data.positions, int(p.position))
+				}
+			}
+		}
+		if !hasPositions {
+			return matchMaybe
+		}
+		if len(data.positions) == 0 {
+			return matchNo
+		}
+		if end-start > 1 {
+			data.positions = sortedUniqueInts(data.positions)
+		}
+		return matchYes
+
+	case opNot:
+		switch n.l.matchPhrase(v, data) {
+		case matchNo:
+			// Change "match nowhere" into "match everywhere".
+			data.negate = true
+			return matchYes
+		case matchYes:
+			if len(data.positions) > 0 {
+				data.negate = !data.negate
+				return matchYes
+			}
+			if data.negate {
+				// Change "match everywhere" into "match nowhere".
+				data.negate = false
+				return matchNo
+			}
+		}
+		return matchMaybe
+
+	case opFollowedBy, opAnd:
+		var l, r phraseData
+		lmatch := n.l.matchPhrase(v, &l)
+		if lmatch == matchNo {
+			return matchNo
+		}
+		rmatch := n.r.matchPhrase(v, &r)
+		if rmatch == matchNo {
+			return matchNo
+		}
+		if lmatch == matchMaybe || rmatch == matchMaybe {
+			return matchMaybe
+		}
+		var lOffset, rOffset int
+		if n.op == opFollowedBy {
+			// The right operand must match the given distance after the end of the
+			// left operand.
+			data.width = l.width + int(n.distance) + r.width
+			lOffset, rOffset = int(n.distance)+r.width, 0
+		} else {
+			// Both operands must match at the same position, aligned on their
+			// ends.
+			data.width = maxInt(l.width, r.width)
+			lOffset, rOffset = data.width-l.width, data.width-r.width
+		}
+		switch {
+		case l.negate && r.negate:
+			// !L op !R is !(L | R).
+			data.mergePositions(&l, &r, emitBoth|emitLeftOnly|emitRightOnly, lOffset, rOffset)
+			data.negate = true
+			return matchYes
+		case l.negate:
+			if data.mergePositions(&l, &r, emitRightOnly, lOffset, rOffset) {
+				return matchYes
+			}
+		case r.negate:
+			if data.mergePositions(&l, &r, emitLeftOnly, lOffset, rOffset) {
+				return matchYes
+			}
+		default:
+			if data.mergePositions(&l, &r, emitBoth, lOffset, rOffset) {
+				return matchYes
+			}
+		}
+		return matchNo
+
+	case opOr:
+		var l, r phraseData
+		lmatch := n.l.matchPhrase(v, &l)
+		rmatch := n.r.matchPhrase(v, &r)
+		if lmatch == matchNo && rmatch == matchNo {
+			return matchNo
+		}
+		if lmatch == matchMaybe || rmatch == matchMaybe {
+			return matchMaybe
+		}
+		// A side that does not match has no positions and is not negated, so it
+		// does not contribute to the merged positions.
+		data.width = maxInt(l.width, r.width)
+		lOffset, rOffset := data.width-l.width, data.width-r.width
+		switch {
+		case l.negate && r.negate:
+			// !L | !R is !(L & R).
+			data.mergePositions(&l, &r, emitBoth, lOffset, rOffset)
+			data.negate = true
+		case l.negate:
+			// !L | R is !(L & !R).
+			data.mergePositions(&l, &r, emitLeftOnly, lOffset, rOffset)
+			data.negate = true
+		case r.negate:
+			// L | !R is !(!L & R).
+			data.mergePositions(&l, &r, emitRightOnly, lOffset, rOffset)
+			data.negate = true
+		default:
+			data.mergePositions(&l, &r, emitBoth|emitLeftOnly|emitRightOnly, lOffset, rOffset)
+		}
+		return matchYes
+	}
+	return matchNo
+}
+
+// mergePositions merges the positions of the two operands, shifted by the
+// given offsets, into data. Depending on emit, it outputs the positions that
+// are present in both operands and those that are present in only one of
+// them. It returns whether data contains any positions. This follows the
+// TS_phrase_output function of PostgreSQL.
+func (data *phraseData) mergePositions(l, r *phraseData, emit int, lOffset, rOffset int) bool {
+	li, ri := 0, 0
+	for li < len(l.positions) || ri < len(r.positions) {
+		lpos, rpos := math.MaxInt32, math.MaxInt32
+		if li < len(l.positions) {
+			lpos = l.positions[li] + lOffset
+		} else if emit&emitRightOnly == 0 {
+			break
+		}
+		if ri < len(r.positions) {
+			rpos = r.positions[ri] + rOffset
+		} else if emit&emitLeftOnly == 0 {
+			break
+		}
+		output := 0
+		switch {
+		case lpos < rpos:
+			if emit&emitLeftOnly != 0 {
+				output = lpos
+			}
+			li++
+		case lpos == rpos:
+			if emit&emitBoth != 0 {
+				output = rpos
+			}
+			li++
+			ri++
+		default:
+			if emit&emitRightOnly != 0 {
+				output = rpos
+			}
+			ri++
+		}
+		if output > 0 {
+			data.positions = append(data.positions, output)
+		}
+	}
+	return len(data.positions) > 0
+}
+
+// sortedUniqueInts sorts and de-duplicates the given integers.
+func sortedUniqueInts(s []int) []int {
+	sort.Ints(s)
+	res := s[:0]
+	for i, x := range s {
+		if i == 0 || x != res[len(res)-1] {
+			res = append(res, x)
+		}
+	}
+	return res
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatches(t *testing.T) {
+	testCases := []struct {
+		vector   string
+		query    string
+		expected bool
+	}{
+		{vector: "a:1 b:2 c:3", query: "a & c", expected: true},
+		{vector: "a:1 b:2 c:3", query: "a & d", expected: false},
+		{vector: "a:1 b:2 c:3", query: "d | c", expected: true},
+		{vector: "a:1 b:2 c:3", query: "!d", expected: true},
+		{vector: "a:1 b:2 c:3", query: "a & !b", expected: false},
+		{vector: "a:1 b:2 c:3", query: "a <-> b", expected: true},
+		{vector: "a:1 b:2 c:3", query: "b <-> a", expected: false},
+		{vector: "a:1 b:2 c:3", query: "a <-> c", expected: false},
+		{vector: "a:1 b:2 c:3", query: "a <2> c", expected: true},
+		{vector: "a:1 b:2 c:3", query: "a <-> b <-> c", expected: true},
+		{vector: "a:1 b:2 c:3", query: "a <-> (b <-> c)", expected: true},
+		{vector: "a:1 b:2 c:3", query: "a <-> (c | b)", expected: true},
+		{vector: "a:1 b:2 c:3", query: "a <-> (c & b)", expected: false},
+		{vector: "a:1 b:2 c:3", query: "a <-> !c", expected: true},
+		{vector: "a:1 b:2 c:3", query: "a <-> !b", expected: false},
+		{vector: "a:1 b:2 c:3", query: "!a <-> b", expected: false},
+		{vector: "a:1 b:2 c:3", query: "!a <-> c", expected: true},
+		{vector: "a:1 b:2 c:3", query: "a <0> a", expected: true},
+		{vector: "a:1 b:2 c:3", query: "a:* <-> b", expected: true},
+		{vector: "a:1A b:2", query: "a:A", expected: true},
+		{vector: "a:1A b:2", query: "a:BC", expected: false},
+		{vector: "a:1A b:2", query: "b:D", expected: true},
+		{vector: "a:1A b:2", query: "a:B <-> b", expected: false},
+		{vector: "supernova:1", query: "super:*", expected: true},
+		{vector: "supernova:1", query: "super", expected: false},
+		{vector: "a b", query: "a <-> b", expected: true},
+		{vector: "a b", query: "a <-> c", expected: false},
+		{vector: "a b", query: "", expected: false},
+		{vector: "", query: "!a", expected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s @@ %s", tc.vector, tc.query), func(t *testing.T) {
+			v, err := ParseTSVector(tc.vector)
+			require.NoError(t, err)
+			q, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, q.Matches(v))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+// InvertedExprFactory constructs the expressions built by
+// TSQuery.BuildInvertedExpr. The expressions are opaque to this package.
+type InvertedExprFactory interface {
+	// Lexeme returns an expression that finds the tsvectors that contain the
+	// given lexeme or, if prefix is true, any lexeme that starts with it. The
+	// expression is tight if the tsvectors it finds are exactly the ones that
+	// match the leaf of the query.
+	Lexeme(lexeme string, prefix bool, tight bool) interface{}
+	// Unconstrained returns an expression that stands for a subexpression of
+	// the query that cannot be evaluated using the inverted index, such as a
+	// negation.
+	Unconstrained() interface{}
+	// And returns an expression that finds the tsvectors found by both
+	// expressions. If tight is false, the result must not be tight, even if
+	// both operands are.
+	And(left, right interface{}, tight bool) interface{}
+	// Or returns an expression that finds the tsvectors found by either
+	// expression.
+	Or(left, right interface{}) interface{}
+}
+
+// BuildInvertedExpr builds an expression that finds, in an inverted index of
+// the lexemes of tsvectors, the tsvectors that may match the query. Returns
+// nil if the query is empty, in which case it matches no tsvector.
+//
+// A leaf is tight unless it is restricted to some weights, since the index
+// only stores lexemes. Followed-by operators are evaluated as AND operators
+// that are never tight, since the index does not store positions.
+func (q TSQuery) BuildInvertedExpr(f InvertedExprFactory) interface{} {
+	if q.root == nil {
+		return nil
+	}
+	return q.root.buildInvertedExpr(f)
+}
+
+func (n *tsNode) buildInvertedExpr(f InvertedExprFactory) interface{} {
+	switch n.op {
+	case opLexeme:
+		return f.Lexeme(n.lexeme, n.prefix, n.weightMask == 0 /* tight */)
+	case opNot:
+		return f.Unconstrained()
+	case opAnd:
+		return f.And(n.l.buildInvertedExpr(f), n.r.buildInvertedExpr(f), true /* tight */)
+	case opFollowedBy:
+		return f.And(n.l.buildInvertedExpr(f), n.r.buildInvertedExpr(f), false /* tight */)
+	case opOr:
+		return f.Or(n.l.buildInvertedExpr(f), n.r.buildInvertedExpr(f))
+	}
+	return f.Unconstrained()
+}
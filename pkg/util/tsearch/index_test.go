@@ -0,0 +1,73 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testExprFactory builds a string representation of the inverted expression.
+// Loose (not tight) subexpressions are marked with a ~.
+type testExprFactory struct{}
+
+func (testExprFactory) Lexeme(lexeme string, prefix bool, tight bool) interface{} {
+	s := lexeme
+	if prefix {
+		s += "*"
+	}
+	if !tight {
+		s = "~" + s
+	}
+	return s
+}
+
+func (testExprFactory) Unconstrained() interface{} {
+	return "?"
+}
+
+func (testExprFactory) And(left, right interface{}, tight bool) interface{} {
+	s := fmt.Sprintf("(%s AND %s)", left, right)
+	if !tight {
+		s = "~" + s
+	}
+	return s
+}
+
+func (testExprFactory) Or(left, right interface{}) interface{} {
+	return fmt.Sprintf("(%s OR %s)", left, right)
+}
+
+func TestBuildInvertedExpr(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected interface{}
+	}{
+		{query: "", expected: nil},
+		{query: "a", expected: "a"},
+		{query: "a:*", expected: "a*"},
+		{query: "a:A", expected: "~a"},
+		{query: "!a", expected: "?"},
+		{query: "a & !b", expected: "(a AND ?)"},
+		{query: "a | b:*B", expected: "(a OR ~b*)"},
+		{query: "a <-> b", expected: "~(a AND b)"},
+		{query: "(a | !b) & c", expected: "((a OR ?) AND c)"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, q.BuildInvertedExpr(testExprFactory{}))
+		})
+	}
+}
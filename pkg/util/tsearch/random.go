@@ -0,0 +1,70 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "math/rand"
+
+// randomLexemeChars are the characters of random lexemes. They include a
+// quote, a backslash and a space to exercise the escaping of lexemes.
+const randomLexemeChars = `abcde' \`
+
+func randomLexeme(rng *rand.Rand) string {
+	b := make([]byte, 1+rng.Intn(5))
+	for i := range b {
+		b[i] = randomLexemeChars[rng.Intn(len(randomLexemeChars))]
+	}
+	return string(b)
+}
+
+// RandomTSVector returns a random tsvector for testing.
+func RandomTSVector(rng *rand.Rand) TSVector {
+	terms := make([]tsTerm, rng.Intn(10))
+	for i := range terms {
+		terms[i].lexeme = randomLexeme(rng)
+		for j := rng.Intn(4); j > 0; j-- {
+			terms[i].positions = append(terms[i].positions, tsPosition{
+				position: uint16(1 + rng.Intn(maxPosition)),
+				weight:   tsWeight(rng.Intn(4)),
+			})
+		}
+	}
+	return makeTSVector(terms)
+}
+
+// RandomTSQuery returns a random tsquery for testing. The query is in the
+// form produced by parsing its text representation.
+func RandomTSQuery(rng *rand.Rand) TSQuery {
+	q := TSQuery{root: randomTSNode(rng, 3 /* depth */)}
+	res, err := ParseTSQuery(q.String())
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func randomTSNode(rng *rand.Rand, depth int) *tsNode {
+	if depth == 0 || rng.Intn(3) == 0 {
+		n := &tsNode{lexeme: randomLexeme(rng), prefix: rng.Intn(4) == 0}
+		if rng.Intn(4) == 0 {
+			n.weightMask = byte(1 + rng.Intn(15))
+		}
+		return n
+	}
+	n := &tsNode{op: tsOperator(1 + rng.Intn(4)), l: randomTSNode(rng, depth-1)}
+	switch n.op {
+	case opNot:
+		return n
+	case opFollowedBy:
+		n.distance = uint16(rng.Intn(5))
+	}
+	n.r = randomTSNode(rng, depth-1)
+	return n
+}
@@ -0,0 +1,229 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// DefaultRankWeights are the weights of the D, C, B and A positions used by
+// Rank when no weights are specified.
+var DefaultRankWeights = [4]float32{0.1, 0.2, 0.4, 1.0}
+
+// Normalization flags of Rank, which determine how the rank of a document is
+// scaled by its length.
+const (
+	// RankNormLogLength divides the rank by 1 + the logarithm of the document
+	// length.
+	RankNormLogLength = 1 << 0
+	// RankNormLength divides the rank by the document length.
+	RankNormLength = 1 << 1
+	// RankNormUniq divides the rank by the number of unique words in the
+	// document.
+	RankNormUniq = 1 << 3
+	// RankNormLogUniq divides the rank by 1 + the logarithm of the number of
+	// unique words in the document.
+	RankNormLogUniq = 1 << 4
+	// RankNormRDivRPlus1 divides the rank by itself + 1.
+	RankNormRDivRPlus1 = 1 << 5
+)
+
+// MakeRankWeights validates the weights passed to ts_rank, in D, C, B, A
+// order. Negative weights are replaced by the default weights.
+func MakeRankWeights(weights []float64) ([4]float32, error) {
+	var res [4]float32
+	if len(weights) < len(res) {
+		return res, pgerror.New(pgcode.ArraySubscript, "array of weight is too short")
+	}
+	for i := range res {
+		switch w := weights[i]; {
+		case w < 0:
+			res[i] = DefaultRankWeights[i]
+		case w > 1:
+			return res, pgerror.New(pgcode.InvalidParameterValue, "weight out of range")
+		default:
+			res[i] = float32(w)
+		}
+	}
+	return res, nil
+}
+
+// Rank returns the relevance of the document represented by the tsvector for
+// the tsquery, based on the frequency of the matching lexemes and, for
+// queries that combine lexemes with AND or followed-by operators, on the
+// proximity of these lexemes. It follows the ts_rank function of PostgreSQL,
+// including its use of single-precision arithmetic.
+func Rank(weights [4]float32, v TSVector, q TSQuery, method int) float32 {
+	if len(v) == 0 || q.root == nil {
+		return 0
+	}
+	var res float32
+	if q.root.op == opAnd || q.root.op == opFollowedBy {
+		res = rankAnd(&weights, v, q)
+	} else {
+		res = rankOr(&weights, v, q)
+	}
+	if res < 0 {
+		res = 1e-20
+	}
+	if method&RankNormLogLength != 0 {
+		res = float32(float64(res) / (math.Log(float64(v.length()+1)) / math.Log(2)))
+	}
+	if method&RankNormLength != 0 {
+		if length := v.length(); length > 0 {
+			res /= float32(length)
+		}
+	}
+	if method&RankNormUniq != 0 {
+		res /= float32(len(v))
+	}
+	if method&RankNormLogUniq != 0 {
+		res = float32(float64(res) / (math.Log(float64(len(v)+1)) / math.Log(2)))
+	}
+	if method&RankNormRDivRPlus1 != 0 {
+		res /= res + 1
+	}
+	return res
+}
+
+// length returns the number of lexeme occurrences in the tsvector, counting
+// lexemes without positions once.
+func (v TSVector) length() int {
+	length := 0
+	for i := range v {
+		if n := len(v[i].positions); n > 0 {
+			length += n
+		} else {
+			length++
+		}
+	}
+	return length
+}
+
+// uniqueLeaves returns the leaves of the tsquery, sorted by lexeme and with
+// duplicate lexemes removed.
+func (q TSQuery) uniqueLeaves() []*tsNode {
+	var leaves []*tsNode
+	q.root.walk(func(n *tsNode) {
+		if n.op == opLexeme {
+			leaves = append(leaves, n)
+		}
+	})
+	sort.SliceStable(leaves, func(i, j int) bool { return leaves[i].lexeme < leaves[j].lexeme })
+	res := leaves[:0]
+	for i, l := range leaves {
+		if i == 0 || l.lexeme != res[len(res)-1].lexeme {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+// positionsOrDefault returns the positions of the term, or a single position
+// with the default weight and the given position if the term has none.
+func (t *tsTerm) positionsOrDefault(position uint16) []tsPosition {
+	if len(t.positions) == 0 {
+		return []tsPosition{{position: position, weight: weightD}}
+	}
+	return t.positions
+}
+
+// wordDistance returns the factor by which the rank of two lexemes is scaled
+// depending on their distance.
+func wordDistance(distance int) float32 {
+	if distance > 100 {
+		return 1e-30
+	}
+	return float32(1.0 / (1.005 + 0.05*math.Exp(float64(float32(distance))/1.5-2)))
+}
+
+// rankAnd computes the rank from the proximity of each pair of matching
+// lexemes. This follows the calc_rank_and function of PostgreSQL.
+func rankAnd(weights *[4]float32, v TSVector, q TSQuery) float32 {
+	leaves := q.uniqueLeaves()
+	if len(leaves) < 2 {
+		return rankOr(weights, v, q)
+	}
+	// noPositions is used for the terms that have no positions.
+	const noPositions = maxPosition
+	positions := make([][]tsPosition, len(leaves))
+	hasNoPositions := make([]bool, len(leaves))
+	var res float32 = -1
+	for i, leaf := range leaves {
+		start, end := leaf.find(v)
+		for e := start; e < end; e++ {
+			positions[i] = v[e].positionsOrDefault(noPositions)
+			hasNoPositions[i] = len(v[e].positions) == 0
+			for k := 0; k < i; k++ {
+				if positions[k] == nil {
+					continue
+				}
+				for _, p := range positions[i] {
+					for _, o := range positions[k] {
+						distance := int(p.position) - int(o.position)
+						if distance < 0 {
+							distance = -distance
+						}
+						if distance == 0 && !hasNoPositions[i] && !hasNoPositions[k] {
+							continue
+						}
+						if distance == 0 {
+							distance = maxPosition + 1
+						}
+						curw := float32(math.Sqrt(float64(
+							weights[p.weight] * weights[o.weight] * wordDistance(distance),
+						)))
+						if res < 0 {
+							res = curw
+						} else {
+							res = 1 - (1-res)*(1-curw)
+						}
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// rankOr computes the rank from the frequency of each matching lexeme. This
+// follows the calc_rank_or function of PostgreSQL.
+func rankOr(weights *[4]float32, v TSVector, q TSQuery) float32 {
+	leaves := q.uniqueLeaves()
+	var res float32
+	for _, leaf := range leaves {
+		start, end := leaf.find(v)
+		for e := start; e < end; e++ {
+			var resj float32
+			var wjm float32 = -1
+			jm := 0
+			for j, p := range v[e].positionsOrDefault(0 /* position */) {
+				w := weights[p.weight]
+				resj += w / float32((j+1)*(j+1))
+				if w > wjm {
+					wjm = w
+					jm = j
+				}
+			}
+			// The sum of 1/i^2 converges to pi^2/6.
+			res = float32(float64(res) +
+				float64(wjm+resj-wjm/float32((jm+1)*(jm+1)))/1.64493406685)
+		}
+	}
+	if len(leaves) > 0 {
+		res /= float32(len(leaves))
+	}
+	return res
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRank(t *testing.T) {
+	english, err := GetConfig("english")
+	require.NoError(t, err)
+	v := english.ToTSVector("a fat cat sat on a mat")
+	testCases := []struct {
+		query    string
+		method   int
+		expected float32
+	}{
+		{query: "cat", expected: 0.0607927},
+		{query: "fat & cat", expected: 0.0991032},
+		{query: "cat | dog", expected: 0.0303964},
+		{query: "dog", expected: 0},
+		{query: "cat", method: RankNormLength, expected: 0.0607927 / 4},
+		{query: "cat", method: RankNormUniq, expected: 0.0607927 / 4},
+		{query: "cat", method: RankNormRDivRPlus1, expected: 0.0607927 / 1.0607927},
+	}
+	for _, tc := range testCases {
+		q, err := english.ToTSQuery(tc.query)
+		require.NoError(t, err)
+		actual := Rank(DefaultRankWeights, v, q, tc.method)
+		if math.Abs(float64(actual-tc.expected)) > 1e-6 {
+			t.Errorf("Rank(%s, %d): expected %v, got %v", tc.query, tc.method, tc.expected, actual)
+		}
+	}
+
+	_, err = MakeRankWeights([]float64{0.1, 0.2})
+	require.EqualError(t, err, "array of weight is too short")
+	_, err = MakeRankWeights([]float64{0.1, 0.2, 0.3, 1.1})
+	require.EqualError(t, err, "weight out of range")
+	weights, err := MakeRankWeights([]float64{-1, 0.5, 0.5, 0.5})
+	require.NoError(t, err)
+	require.Equal(t, [4]float32{0.1, 0.5, 0.5, 0.5}, weights)
+}
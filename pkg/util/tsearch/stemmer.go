@@ -0,0 +1,363 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "strings"
+
+// stemEnglish returns the stem of the given lowercase ASCII word, as computed
+// by the English (Porter2) stemming algorithm of the Snowball project. This is
+// the stemmer used by the english text search configuration of PostgreSQL.
+// See https://snowballstem.org/algorithms/english/stemmer.html.
+func stemEnglish(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	if s, ok := stemExceptions[word]; ok {
+		return s
+	}
+	w := []byte(strings.TrimPrefix(word, "'"))
+	// Mark the y's that are used as consonants with an uppercase Y, which is
+	// not a vowel.
+	for i := range w {
+		if w[i] == 'y' && (i == 0 || isVowel(w[i-1])) {
+			w[i] = 'Y'
+		}
+	}
+	s := stemmer{w: w}
+	s.markRegions()
+
+	s.step0()
+	s.step1a()
+	if _, ok := stemInvariants[string(s.w)]; ok {
+		return string(s.w)
+	}
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	return strings.ReplaceAll(string(s.w), "Y", "y")
+}
+
+// stemExceptions are the words that are stemmed irregularly, or not at all.
+var stemExceptions = map[string]string{
+	"skies":  "sky",
+	"dying":  "die",
+	"lying":  "lie",
+	"tying":  "tie",
+	"idly":   "idl",
+	"gently": "gentl",
+	"ugly":   "ugli",
+	"early":  "earli",
+	"only":   "onli",
+	"singly": "singl",
+	"sky":    "sky",
+	"news":   "news",
+	"howe":   "howe",
+	"atlas":  "atlas",
+	"cosmos": "cosmos",
+	"bias":   "bias",
+	"andes":  "andes",
+}
+
+// stemInvariants are the words that are left unchanged once the plural
+// suffixes are removed.
+var stemInvariants = map[string]struct{}{
+	"inning":  {},
+	"outing":  {},
+	"canning": {},
+	"herring": {},
+	"earring": {},
+	"proceed": {},
+	"exceed":  {},
+	"succeed": {},
+}
+
+func isVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+type stemmer struct {
+	w []byte
+	// r1 and r2 are the offsets of the R1 and R2 regions of the word, which
+	// are the parts of the word in which some suffixes can be removed.
+	r1, r2 int
+}
+
+// markRegions computes R1, the region after the first non-vowel following a
+// vowel, and R2, the same region computed within R1.
+func (s *stemmer) markRegions() {
+	s.r1 = len(s.w)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(s.w), prefix) {
+			s.r1 = len(prefix)
+			break
+		}
+	}
+	if s.r1 == len(s.w) {
+		s.r1 = s.regionAfter(0)
+	}
+	s.r2 = s.regionAfter(s.r1)
+}
+
+func (s *stemmer) regionAfter(start int) int {
+	for i := start + 1; i < len(s.w); i++ {
+		if !isVowel(s.w[i]) && isVowel(s.w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(s.w)
+}
+
+func (s *stemmer) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(s.w), suffix)
+}
+
+// longestSuffix returns the longest of the given suffixes that the word ends
+// with, or the empty string.
+func (s *stemmer) longestSuffix(suffixes ...string) string {
+	res := ""
+	for _, suffix := range suffixes {
+		if len(suffix) > len(res) && s.hasSuffix(suffix) {
+			res = suffix
+		}
+	}
+	return res
+}
+
+// inR1 returns whether the given suffix of the word is in R1.
+func (s *stemmer) inR1(suffix string) bool {
+	return len(s.w)-len(suffix) >= s.r1
+}
+
+// inR2 returns whether the given suffix of the word is in R2.
+func (s *stemmer) inR2(suffix string) bool {
+	return len(s.w)-len(suffix) >= s.r2
+}
+
+// replace replaces the given suffix of the word.
+func (s *stemmer) replace(suffix, replacement string) {
+	s.w = append(s.w[:len(s.w)-len(suffix)], replacement...)
+}
+
+// containsVowel returns whether the word contains a vowel before the given
+// suffix.
+func (s *stemmer) containsVowel(suffix string) bool {
+	for _, c := range s.w[:len(s.w)-len(suffix)] {
+		if isVowel(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsWithShortSyllable returns whether the word ends with a short syllable:
+// a vowel followed by a non-vowel other than w, x or Y and preceded by a
+// non-vowel, or a vowel at the beginning of the word followed by a non-vowel.
+func (s *stemmer) endsWithShortSyllable() bool {
+	n := len(s.w)
+	if n == 2 {
+		return isVowel(s.w[0]) && !isVowel(s.w[1])
+	}
+	if n < 3 {
+		return false
+	}
+	c := s.w[n-1]
+	return !isVowel(s.w[n-3]) && isVowel(s.w[n-2]) && !isVowel(c) &&
+		c != 'w' && c != 'x' && c != 'Y'
+}
+
+// isShort returns whether the word ends in a short syllable and R1 is empty.
+func (s *stemmer) isShort() bool {
+	return s.r1 >= len(s.w) && s.endsWithShortSyllable()
+}
+
+// step0 removes the possessive suffixes.
+func (s *stemmer) step0() {
+	if suffix := s.longestSuffix("'", "'s", "'s'"); suffix != "" {
+		s.replace(suffix, "")
+	}
+}
+
+// step1a removes the plural suffixes.
+func (s *stemmer) step1a() {
+	switch suffix := s.longestSuffix("sses", "ied", "ies", "s", "us", "ss"); suffix {
+	case "sses":
+		s.replace(suffix, "ss")
+	case "ied", "ies":
+		if len(s.w) > 4 {
+			s.replace(suffix, "i")
+		} else {
+			s.replace(suffix, "ie")
+		}
+	case "s":
+		// Delete the s if the preceding part contains a vowel that is not
+		// immediately before the s.
+		if len(s.w) >= 2 && s.containsVowel("xs") {
+			s.replace(suffix, "")
+		}
+	}
+}
+
+// step1b removes the past tense and gerund suffixes.
+func (s *stemmer) step1b() {
+	switch suffix := s.longestSuffix("eed", "eedly", "ed", "edly", "ing", "ingly"); suffix {
+	case "eed", "eedly":
+		if s.inR1(suffix) {
+			s.replace(suffix, "ee")
+		}
+	case "ed", "edly", "ing", "ingly":
+		if !s.containsVowel(suffix) {
+			return
+		}
+		s.replace(suffix, "")
+		switch {
+		case s.hasSuffix("at"), s.hasSuffix("bl"), s.hasSuffix("iz"):
+			s.w = append(s.w, 'e')
+		case s.longestSuffix("bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt") != "":
+			s.w = s.w[:len(s.w)-1]
+		case s.isShort():
+			s.w = append(s.w, 'e')
+		}
+	}
+}
+
+// step1c replaces a final y by i if it is preceded by a non-vowel that is not
+// the first letter of the word.
+func (s *stemmer) step1c() {
+	n := len(s.w)
+	if n > 2 && (s.w[n-1] == 'y' || s.w[n-1] == 'Y') && !isVowel(s.w[n-2]) {
+		s.w[n-1] = 'i'
+	}
+}
+
+// step2Suffixes are the suffixes replaced in R1 by step 2.
+var step2Suffixes = map[string]string{
+	"tional":  "tion",
+	"enci":    "ence",
+	"anci":    "ance",
+	"abli":    "able",
+	"entli":   "ent",
+	"izer":    "ize",
+	"ization": "ize",
+	"ational": "ate",
+	"ation":   "ate",
+	"ator":    "ate",
+	"alism":   "al",
+	"aliti":   "al",
+	"alli":    "al",
+	"fulness": "ful",
+	"ousli":   "ous",
+	"ousness": "ous",
+	"iveness": "ive",
+	"iviti":   "ive",
+	"biliti":  "ble",
+	"bli":     "ble",
+	"ogi":     "og",
+	"fulli":   "ful",
+	"lessli":  "less",
+	"li":      "",
+}
+
+var step2SuffixList = mapKeys(step2Suffixes)
+
+func (s *stemmer) step2() {
+	suffix := s.longestSuffix(step2SuffixList...)
+	if suffix == "" || !s.inR1(suffix) {
+		return
+	}
+	switch suffix {
+	case "ogi":
+		if !s.hasSuffix("logi") {
+			return
+		}
+	case "li":
+		if n := len(s.w); n < 3 || !strings.ContainsRune("cdeghkmnrt", rune(s.w[n-3])) {
+			return
+		}
+	}
+	s.replace(suffix, step2Suffixes[suffix])
+}
+
+// step3Suffixes are the suffixes replaced in R1 by step 3.
+var step3Suffixes = map[string]string{
+	"tional":  "tion",
+	"ational": "ate",
+	"alize":   "al",
+	"icate":   "ic",
+	"iciti":   "ic",
+	"ical":    "ic",
+	"ful":     "",
+	"ness":    "",
+	"ative":   "",
+}
+
+var step3SuffixList = mapKeys(step3Suffixes)
+
+func (s *stemmer) step3() {
+	suffix := s.longestSuffix(step3SuffixList...)
+	if suffix == "" || !s.inR1(suffix) {
+		return
+	}
+	if suffix == "ative" && !s.inR2(suffix) {
+		return
+	}
+	s.replace(suffix, step3Suffixes[suffix])
+}
+
+// step4Suffixes are the suffixes removed in R2 by step 4.
+var step4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+}
+
+func (s *stemmer) step4() {
+	suffix := s.longestSuffix(step4Suffixes...)
+	if suffix == "" || !s.inR2(suffix) {
+		return
+	}
+	if suffix == "ion" && !s.hasSuffix("sion") && !s.hasSuffix("tion") {
+		return
+	}
+	s.replace(suffix, "")
+}
+
+// step5 removes a final e or l.
+func (s *stemmer) step5() {
+	switch {
+	case s.hasSuffix("e"):
+		if s.inR2("e") {
+			s.replace("e", "")
+		} else if s.inR1("e") {
+			s.w = s.w[:len(s.w)-1]
+			if s.endsWithShortSyllable() {
+				s.w = append(s.w, 'e')
+			}
+		}
+	case s.hasSuffix("ll"):
+		if s.inR2("l") {
+			s.replace("l", "")
+		}
+	}
+}
+
+func mapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "testing"
+
+func TestStemEnglish(t *testing.T) {
+	// The expected stems come from the sample vocabulary of the Snowball
+	// project.
+	testCases := map[string]string{
+		"consign":       "consign",
+		"consigned":     "consign",
+		"consigning":    "consign",
+		"consignment":   "consign",
+		"consist":       "consist",
+		"consisted":     "consist",
+		"consistency":   "consist",
+		"consistent":    "consist",
+		"consistently":  "consist",
+		"consisting":    "consist",
+		"consists":      "consist",
+		"consolation":   "consol",
+		"consolations":  "consol",
+		"consolatory":   "consolatori",
+		"console":       "consol",
+		"consoled":      "consol",
+		"consoles":      "consol",
+		"consolidate":   "consolid",
+		"consolidated":  "consolid",
+		"consolidating": "consolid",
+		"consoling":     "consol",
+		"consolingly":   "consol",
+		"consonant":     "conson",
+		"conspicuous":   "conspicu",
+		"conspiracy":    "conspiraci",
+		"conspirator":   "conspir",
+		"conspirators":  "conspir",
+		"conspire":      "conspir",
+		"constable":     "constabl",
+		"constancy":     "constanc",
+		"constant":      "constant",
+		"knackeries":    "knackeri",
+		"kneaded":       "knead",
+		"knee":          "knee",
+		"kneeling":      "kneel",
+		"knightly":      "knight",
+		"knitting":      "knit",
+		"knives":        "knive",
+		"knocker":       "knocker",
+		"generously":    "generous",
+		"running":       "run",
+		"hopping":       "hop",
+		"hoped":         "hope",
+		"happy":         "happi",
+		"cries":         "cri",
+		"ties":          "tie",
+		"skies":         "sky",
+		"gas":           "gas",
+		"gaps":          "gap",
+		"succeeding":    "succeed",
+		"at":            "at",
+		"yelled":        "yell",
+		"sayings":       "say",
+	}
+	for word, expected := range testCases {
+		if actual := stemEnglish(word); actual != expected {
+			t.Errorf("stemEnglish(%q): expected %q, got %q", word, expected, actual)
+		}
+	}
+}
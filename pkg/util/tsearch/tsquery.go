@@ -0,0 +1,526 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// tsOperator is the operator of an inner node of a tsquery. The values match
+// the PostgreSQL binary representation.
+type tsOperator byte
+
+const (
+	// opLexeme is the "operator" of a leaf node, which matches a lexeme.
+	opLexeme tsOperator = iota
+	opNot
+	opAnd
+	opOr
+	opFollowedBy
+)
+
+// priority returns the precedence of the operator; operators with a larger
+// priority bind more tightly.
+func (o tsOperator) priority() int {
+	switch o {
+	case opNot:
+		return 4
+	case opFollowedBy:
+		return 3
+	case opAnd:
+		return 2
+	case opOr:
+		return 1
+	}
+	return 0
+}
+
+// tsNode is a node of a tsquery expression tree. Leaf nodes match a single
+// lexeme; inner nodes combine the results of their children. The NOT
+// operator only has a left child.
+type tsNode struct {
+	op tsOperator
+
+	// lexeme is the lexeme matched by a leaf node.
+	lexeme string
+	// weightMask is a bitmask of the weights (1 << weight) of the positions
+	// that a leaf node matches. If zero, the leaf matches any weight.
+	weightMask byte
+	// prefix is true if a leaf node matches all lexemes that start with the
+	// lexeme, rather than only the lexeme itself.
+	prefix bool
+
+	// distance is the distance between the matches of the left and right
+	// children of a followed-by node.
+	distance uint16
+
+	l, r *tsNode
+}
+
+// TSQuery is a full-text search query: a boolean expression of lexemes,
+// possibly constrained to appear at given distances from each other, which
+// is matched against a TSVector.
+type TSQuery struct {
+	root *tsNode
+}
+
+// ParseTSQuery parses the text representation of a tsquery, e.g.
+//
+//   'fat' & ( 'rat':AB | ! 'cat':* ) & 'big' <-> 'dog'
+//
+// Lexemes are not normalized.
+func ParseTSQuery(input string) (TSQuery, error) {
+	return parseTSQuery(input, func(lexeme string, weightMask byte, prefix bool) (*tsNode, error) {
+		return &tsNode{lexeme: lexeme, weightMask: weightMask, prefix: prefix}, nil
+	})
+}
+
+// operandFn builds the node for an operand of a tsquery. It may return a nil
+// node to indicate that the operand should be discarded (e.g. because it is
+// a stop word).
+type operandFn func(lexeme string, weightMask byte, prefix bool) (*tsNode, error)
+
+// parseTSQuery parses a tsquery, building its operands with the given
+// function. Operands that are discarded are removed from the resulting
+// expression, adjusting the distances of the surrounding followed-by
+// operators to account for them.
+func parseTSQuery(input string, fn operandFn) (TSQuery, error) {
+	p := queryParser{parser: parser{input: input, kind: "tsquery"}, fn: fn}
+	p.skipSpace()
+	if p.done() {
+		return TSQuery{}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return TSQuery{}, err
+	}
+	p.skipSpace()
+	if !p.done() {
+		return TSQuery{}, p.syntaxError()
+	}
+	root, _, _ = cleanStopWords(root)
+	return TSQuery{root: root}, nil
+}
+
+type queryParser struct {
+	parser
+	fn operandFn
+}
+
+// parseOr parses a sequence of AND expressions separated by |.
+func (p *queryParser) parseOr() (*tsNode, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume('|') {
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = &tsNode{op: opOr, l: l, r: r}
+	}
+	return l, nil
+}
+
+// parseAnd parses a sequence of followed-by expressions separated by &.
+func (p *queryParser) parseAnd() (*tsNode, error) {
+	l, err := p.parseFollowedBy()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume('&') {
+		r, err := p.parseFollowedBy()
+		if err != nil {
+			return nil, err
+		}
+		l = &tsNode{op: opAnd, l: l, r: r}
+	}
+	return l, nil
+}
+
+// parseFollowedBy parses a sequence of unary expressions separated by <-> or
+// <N>.
+func (p *queryParser) parseFollowedBy() (*tsNode, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		distance, ok, err := p.followedBy()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = &tsNode{op: opFollowedBy, distance: distance, l: l, r: r}
+	}
+}
+
+// parseUnary parses a negation, a parenthesized expression or an operand.
+func (p *queryParser) parseUnary() (*tsNode, error) {
+	p.skipSpace()
+	if p.done() {
+		return nil, p.syntaxError()
+	}
+	switch p.peek() {
+	case '!':
+		p.pos++
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &tsNode{op: opNot, l: n}, nil
+	case '(':
+		p.pos++
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(')') {
+			return nil, p.syntaxError()
+		}
+		return n, nil
+	}
+	return p.parseOperand()
+}
+
+// parseOperand parses a lexeme, optionally followed by a colon and a list of
+// weights and the * prefix marker.
+func (p *queryParser) parseOperand() (*tsNode, error) {
+	lexeme, err := p.lexeme(isQueryDelimiter)
+	if err != nil {
+		return nil, err
+	}
+	var weightMask byte
+	var prefix bool
+	if !p.done() && p.peek() == ':' {
+		p.pos++
+		for ; !p.done(); p.pos++ {
+			if w, ok := parseWeight(p.peek()); ok {
+				weightMask |= 1 << w
+			} else if p.peek() == '*' {
+				prefix = true
+			} else {
+				break
+			}
+		}
+	}
+	return p.fn(lexeme, weightMask, prefix)
+}
+
+func isQueryDelimiter(c byte) bool {
+	switch c {
+	case ':', '&', '|', '!', '(', ')', '<':
+		return true
+	}
+	return false
+}
+
+// consume skips whitespace and then the given character, returning whether
+// the character was found.
+func (p *queryParser) consume(c byte) bool {
+	p.skipSpace()
+	if !p.done() && p.peek() == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// followedBy parses a <-> or <N> operator, returning its distance.
+func (p *queryParser) followedBy() (distance uint16, ok bool, _ error) {
+	if !p.consume('<') {
+		return 0, false, nil
+	}
+	if !p.done() && p.peek() == '-' {
+		p.pos++
+		if !p.consume('>') {
+			return 0, false, p.syntaxError()
+		}
+		return 1, true, nil
+	}
+	start := p.pos
+	for !p.done() && p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+	n, err := strconv.Atoi(p.input[start:p.pos])
+	if err != nil || !p.consume('>') {
+		return 0, false, p.syntaxError()
+	}
+	if n > maxPosition {
+		return 0, false, pgerror.Newf(pgcode.InvalidParameterValue,
+			"distance in phrase operator must be an integer value between zero and %d inclusive",
+			maxPosition)
+	}
+	return uint16(n), true, nil
+}
+
+// cleanStopWords removes the nil operands from the tree rooted at the given
+// node, along with the operators that are left without operands. The
+// distances of the followed-by operators are extended to account for the
+// removed operands, so that 'a <-> the <-> b' becomes 'a <2> b' when "the"
+// is a stop word. ladd and radd are the distances that the parent of the
+// node needs to add on its left and right side. This follows the
+// clean_stopword_intree function of PostgreSQL.
+func cleanStopWords(n *tsNode) (_ *tsNode, ladd, radd int) {
+	if n == nil || n.op == opLexeme {
+		return n, 0, 0
+	}
+	if n.op == opNot {
+		n.l, ladd, radd = cleanStopWords(n.l)
+		if n.l == nil {
+			return nil, ladd, radd
+		}
+		return n, ladd, radd
+	}
+	var lladd, lradd, rladd, rradd int
+	n.l, lladd, lradd = cleanStopWords(n.l)
+	n.r, rladd, rradd = cleanStopWords(n.r)
+	isPhrase := n.op == opFollowedBy
+	distance := 0
+	if isPhrase {
+		distance = int(n.distance)
+	}
+	switch {
+	case n.l == nil && n.r == nil:
+		if isPhrase {
+			return nil, lladd + distance + rladd, lradd + distance + rradd
+		}
+		return nil, maxInt(lladd, rladd), maxInt(lradd, rradd)
+	case n.l == nil:
+		if isPhrase {
+			return n.r, lladd + distance + rladd, rradd
+		}
+		return n.r, rladd, rradd
+	case n.r == nil:
+		if isPhrase {
+			return n.l, lladd, lradd + distance + rradd
+		}
+		return n.l, lladd, lradd
+	case isPhrase:
+		distance += lradd + rladd
+		if distance > maxPosition {
+			distance = maxPosition
+		}
+		n.distance = uint16(distance)
+		return n, lladd, rradd
+	}
+	return n, 0, 0
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// String returns the text representation of the tsquery.
+func (q TSQuery) String() string {
+	if q.root == nil {
+		return ""
+	}
+	var b strings.Builder
+	q.root.format(&b, 0 /* parentPriority */, false /* rightPhrase */)
+	return b.String()
+}
+
+// format writes the infix representation of the node. Parentheses are added
+// when the operator binds less tightly than its parent, and around
+// followed-by operators that are the right operand of another followed-by
+// operator, since these are not associative.
+func (n *tsNode) format(b *strings.Builder, parentPriority int, rightPhrase bool) {
+	if n.op == opLexeme {
+		writeLexeme(b, n.lexeme)
+		if n.weightMask != 0 || n.prefix {
+			b.WriteByte(':')
+			if n.prefix {
+				b.WriteByte('*')
+			}
+			for w := weightA; ; w-- {
+				if n.weightMask&(1<<w) != 0 {
+					b.WriteString(w.String())
+				}
+				if w == weightD {
+					break
+				}
+			}
+		}
+		return
+	}
+	priority := n.op.priority()
+	parens := priority < parentPriority || (n.op == opFollowedBy && rightPhrase)
+	if parens {
+		b.WriteString("( ")
+	}
+	switch n.op {
+	case opNot:
+		b.WriteByte('!')
+		n.l.format(b, priority, false /* rightPhrase */)
+	default:
+		n.l.format(b, priority, false /* rightPhrase */)
+		switch n.op {
+		case opAnd:
+			b.WriteString(" & ")
+		case opOr:
+			b.WriteString(" | ")
+		case opFollowedBy:
+			if n.distance == 1 {
+				b.WriteString(" <-> ")
+			} else {
+				b.WriteString(" <")
+				b.WriteString(strconv.Itoa(int(n.distance)))
+				b.WriteString("> ")
+			}
+		}
+		n.r.format(b, priority, n.op == opFollowedBy)
+	}
+	if parens {
+		b.WriteString(" )")
+	}
+}
+
+// Compare returns -1, 0 or 1 if the tsquery is respectively less than, equal
+// to or greater than the other tsquery. The ordering is only meant to be
+// consistent; it orders the queries by their binary representation.
+func (q TSQuery) Compare(other TSQuery) int {
+	return strings.Compare(
+		string(EncodeTSQuery(nil, q)), string(EncodeTSQuery(nil, other)),
+	)
+}
+
+// Size returns the approximate size in bytes of the tsquery.
+func (q TSQuery) Size() uintptr {
+	var size uintptr
+	q.root.walk(func(n *tsNode) {
+		size += uintptr(len(n.lexeme)) + 48
+	})
+	return size
+}
+
+// walk calls fn on each node of the tree rooted at n, in pre-order.
+func (n *tsNode) walk(fn func(n *tsNode)) {
+	if n == nil {
+		return
+	}
+	fn(n)
+	n.l.walk(fn)
+	n.r.walk(fn)
+}
+
+// EncodeTSQuery appends the binary representation of the tsquery to the given
+// buffer. The representation is the same as the one used by the PostgreSQL
+// binary wire protocol: the number of nodes, followed by the nodes in prefix
+// order, with the right operand of each binary operator before its left
+// operand. Leaf nodes are encoded as the type 1, the weight mask, the prefix
+// flag and the lexeme as a null-terminated string. Inner nodes are encoded as
+// the type 2 and the operator, followed by the distance for followed-by
+// operators.
+func EncodeTSQuery(appendTo []byte, q TSQuery) []byte {
+	count := 0
+	q.root.walk(func(*tsNode) { count++ })
+	appendTo = appendUint32(appendTo, uint32(count))
+	return q.root.encode(appendTo)
+}
+
+func (n *tsNode) encode(appendTo []byte) []byte {
+	if n == nil {
+		return appendTo
+	}
+	if n.op == opLexeme {
+		appendTo = append(appendTo, 1, n.weightMask)
+		if n.prefix {
+			appendTo = append(appendTo, 1)
+		} else {
+			appendTo = append(appendTo, 0)
+		}
+		appendTo = append(appendTo, n.lexeme...)
+		return append(appendTo, 0)
+	}
+	appendTo = append(appendTo, 2, byte(n.op))
+	if n.op == opFollowedBy {
+		appendTo = appendUint16(appendTo, n.distance)
+	}
+	if n.op == opNot {
+		return n.l.encode(appendTo)
+	}
+	appendTo = n.r.encode(appendTo)
+	return n.l.encode(appendTo)
+}
+
+// DecodeTSQuery decodes a tsquery encoded with EncodeTSQuery.
+func DecodeTSQuery(b []byte) (TSQuery, error) {
+	d := decoder{b: b}
+	count := int(d.uint32())
+	var root *tsNode
+	if count > 0 {
+		root = d.node(&count)
+	}
+	if d.err != nil {
+		return TSQuery{}, d.err
+	}
+	if count != 0 || len(d.b) > 0 {
+		return TSQuery{}, errors.New("invalid tsquery encoding: trailing data")
+	}
+	return TSQuery{root: root}, nil
+}
+
+// node decodes a node and its children, decrementing count for each of them.
+func (d *decoder) node(count *int) *tsNode {
+	if *count <= 0 {
+		d.fail()
+		return nil
+	}
+	*count--
+	n := &tsNode{}
+	switch d.byte() {
+	case 1:
+		n.weightMask = d.byte() & 0xf
+		n.prefix = d.byte() != 0
+		n.lexeme = d.cstring()
+		if n.lexeme == "" && d.err == nil {
+			d.err = errors.New("invalid tsquery encoding: empty lexeme")
+		}
+	case 2:
+		n.op = tsOperator(d.byte())
+		switch n.op {
+		case opNot:
+			n.l = d.node(count)
+		case opAnd, opOr, opFollowedBy:
+			if n.op == opFollowedBy {
+				n.distance = d.uint16()
+			}
+			n.r = d.node(count)
+			n.l = d.node(count)
+		default:
+			d.err = errors.Newf("invalid tsquery encoding: unknown operator %d", n.op)
+		}
+	default:
+		if d.err == nil {
+			d.err = errors.New("invalid tsquery encoding: unknown node type")
+		}
+	}
+	if d.err != nil {
+		d.b = nil
+	}
+	return n
+}
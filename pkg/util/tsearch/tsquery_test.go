@@ -0,0 +1,99 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTSQuery(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{input: "", expected: ""},
+		{input: "a", expected: "'a'"},
+		{input: "'fat' & ( 'rat' | 'cat' )", expected: "'fat' & ( 'rat' | 'cat' )"},
+		{input: "a|b&c", expected: "'a' | 'b' & 'c'"},
+		{input: "(a | b) & c", expected: "( 'a' | 'b' ) & 'c'"},
+		{input: "a & (b & c)", expected: "'a' & 'b' & 'c'"},
+		{input: "!a & b", expected: "!'a' & 'b'"},
+		{input: "!(a & b)", expected: "!( 'a' & 'b' )"},
+		{input: "!!a", expected: "!!'a'"},
+		{input: "a <-> b <-> c", expected: "'a' <-> 'b' <-> 'c'"},
+		{input: "a <-> (b <-> c)", expected: "'a' <-> ( 'b' <-> 'c' )"},
+		{input: "a<2>b", expected: "'a' <2> 'b'"},
+		{input: "a <0> b", expected: "'a' <0> 'b'"},
+		{input: "a & b <-> c", expected: "'a' & 'b' <-> 'c'"},
+		{input: "(a & b) <-> c", expected: "( 'a' & 'b' ) <-> 'c'"},
+		{input: "!a <-> b", expected: "!'a' <-> 'b'"},
+		{input: "a:*AB", expected: "'a':*AB"},
+		{input: "a:ba | b:*", expected: "'a':AB | 'b':*"},
+		{input: "'it''s'", expected: "'it''s'"},
+		{input: "a &", err: "syntax error in tsquery"},
+		{input: "a b", err: "syntax error in tsquery"},
+		{input: "(a", err: "syntax error in tsquery"},
+		{input: "a <- b", err: "syntax error in tsquery"},
+		{input: "a <x> b", err: "syntax error in tsquery"},
+		{input: "a <20000> b", err: "distance in phrase operator"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.input)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, q.String())
+
+			// Check that the output can be parsed back and that the binary
+			// encoding round-trips.
+			reparsed, err := ParseTSQuery(q.String())
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, reparsed.String())
+			decoded, err := DecodeTSQuery(EncodeTSQuery(nil, q))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, decoded.String())
+		})
+	}
+}
+
+func TestDecodeTSQueryErrors(t *testing.T) {
+	q, err := ParseTSQuery("a & !b <2> c")
+	require.NoError(t, err)
+	encoded := EncodeTSQuery(nil, q)
+	for i := 0; i < len(encoded); i++ {
+		_, err := DecodeTSQuery(encoded[:i])
+		require.Error(t, err, "prefix of length %d", i)
+	}
+	_, err = DecodeTSQuery(append(encoded, 0))
+	require.Error(t, err)
+}
+
+func TestRandomTSQueryRoundtrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		q := RandomTSQuery(rng)
+		parsed, err := ParseTSQuery(q.String())
+		require.NoError(t, err)
+		require.Equal(t, 0, q.Compare(parsed), "%s", q)
+
+		decoded, err := DecodeTSQuery(EncodeTSQuery(nil, q))
+		require.NoError(t, err)
+		require.Equal(t, 0, q.Compare(decoded), "%s", q)
+	}
+}
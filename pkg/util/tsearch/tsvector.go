@@ -0,0 +1,528 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package tsearch implements the tsvector and tsquery full-text search types
+// of PostgreSQL, along with the functions that parse documents and queries
+// into them, match them against each other and rank the matches.
+package tsearch
+
+import (
+	"encoding/binary"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// maxPosition is the largest position that can be stored in a tsvector.
+	// Larger positions are clamped to this value.
+	maxPosition = 1<<14 - 1
+	// maxPositionsPerLexeme is the maximum number of positions stored for a
+	// single lexeme. Additional positions are dropped.
+	maxPositionsPerLexeme = 256
+	// maxLexemeLen is the maximum length in bytes of a single lexeme.
+	maxLexemeLen = 1<<11 - 1
+)
+
+// tsWeight is the weight of a lexeme position, which is used to mark the
+// lexemes that come from different parts of a document (e.g. its title or
+// body). The weights are A, B, C and D, with D being the default. The values
+// match the PostgreSQL representation.
+type tsWeight byte
+
+const (
+	weightD tsWeight = iota
+	weightC
+	weightB
+	weightA
+)
+
+// String returns the letter of the weight.
+func (w tsWeight) String() string {
+	return string("DCBA"[w])
+}
+
+// parseWeight returns the weight for the given letter, which is case
+// insensitive.
+func parseWeight(c byte) (tsWeight, bool) {
+	switch c {
+	case 'a', 'A':
+		return weightA, true
+	case 'b', 'B':
+		return weightB, true
+	case 'c', 'C':
+		return weightC, true
+	case 'd', 'D':
+		return weightD, true
+	}
+	return 0, false
+}
+
+// tsPosition is a position of a lexeme in a document, along with its weight.
+type tsPosition struct {
+	position uint16
+	weight   tsWeight
+}
+
+// tsTerm is a lexeme of a tsvector, along with its positions in the
+// document. The positions are sorted and unique, and may be empty.
+type tsTerm struct {
+	lexeme    string
+	positions []tsPosition
+}
+
+// TSVector is a sorted list of distinct lexemes, each of which is annotated
+// with the positions at which it appears in the document it represents. It is
+// the document representation used by full-text search.
+type TSVector []tsTerm
+
+// ParseTSVector parses the text representation of a tsvector, e.g.
+//
+//   'a':1A 'fat':2 'cat':3,5
+//
+// Lexemes are separated by whitespace and may be quoted; within a lexeme,
+// quotes can be escaped by doubling them or with a backslash. The lexemes are
+// not normalized, but are sorted and de-duplicated.
+func ParseTSVector(input string) (TSVector, error) {
+	p := parser{input: input, kind: "tsvector"}
+	var terms []tsTerm
+	for {
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+		lexeme, err := p.lexeme(func(c byte) bool { return c == ':' })
+		if err != nil {
+			return nil, err
+		}
+		term := tsTerm{lexeme: lexeme}
+		if !p.done() && p.peek() == ':' {
+			p.pos++
+			if term.positions, err = p.positions(); err != nil {
+				return nil, err
+			}
+		}
+		terms = append(terms, term)
+	}
+	return makeTSVector(terms), nil
+}
+
+// makeTSVector sorts the given terms, merging the positions of duplicate
+// lexemes.
+func makeTSVector(terms []tsTerm) TSVector {
+	if len(terms) == 0 {
+		return TSVector{}
+	}
+	sort.SliceStable(terms, func(i, j int) bool { return terms[i].lexeme < terms[j].lexeme })
+	res := terms[:1]
+	for _, t := range terms[1:] {
+		last := &res[len(res)-1]
+		if t.lexeme == last.lexeme {
+			last.positions = append(last.positions, t.positions...)
+		} else {
+			res = append(res, t)
+		}
+	}
+	for i := range res {
+		res[i].positions = normalizePositions(res[i].positions)
+	}
+	return TSVector(res)
+}
+
+// normalizePositions sorts and de-duplicates the given positions, keeping the
+// largest weight of duplicate positions, and truncates them to the maximum
+// number of positions per lexeme.
+func normalizePositions(positions []tsPosition) []tsPosition {
+	if len(positions) == 0 {
+		return nil
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].position != positions[j].position {
+			return positions[i].position < positions[j].position
+		}
+		return positions[i].weight > positions[j].weight
+	})
+	res := positions[:1]
+	for _, p := range positions[1:] {
+		if p.position != res[len(res)-1].position {
+			res = append(res, p)
+		}
+	}
+	if len(res) > maxPositionsPerLexeme {
+		res = res[:maxPositionsPerLexeme]
+	}
+	return res
+}
+
+// String returns the text representation of the tsvector.
+func (v TSVector) String() string {
+	var b strings.Builder
+	for i, t := range v {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		writeLexeme(&b, t.lexeme)
+		for j, p := range t.positions {
+			if j == 0 {
+				b.WriteByte(':')
+			} else {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Itoa(int(p.position)))
+			if p.weight != weightD {
+				b.WriteString(p.weight.String())
+			}
+		}
+	}
+	return b.String()
+}
+
+// writeLexeme writes the given lexeme to the builder, surrounded by quotes.
+// Quotes and backslashes in the lexeme are escaped by doubling them.
+func writeLexeme(b *strings.Builder, lexeme string) {
+	b.WriteByte('\'')
+	for i := 0; i < len(lexeme); i++ {
+		if c := lexeme[i]; c == '\'' || c == '\\' {
+			b.WriteByte(c)
+		}
+		b.WriteByte(lexeme[i])
+	}
+	b.WriteByte('\'')
+}
+
+// Compare returns -1, 0 or 1 if the tsvector is respectively less than, equal
+// to or greater than the other tsvector. Tsvectors are ordered by their
+// lexemes and then by the positions of the lexemes.
+func (v TSVector) Compare(other TSVector) int {
+	for i := 0; i < len(v) && i < len(other); i++ {
+		if c := v[i].compare(&other[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(v), len(other))
+}
+
+func (t *tsTerm) compare(other *tsTerm) int {
+	if c := strings.Compare(t.lexeme, other.lexeme); c != 0 {
+		return c
+	}
+	for i := 0; i < len(t.positions) && i < len(other.positions); i++ {
+		p, o := t.positions[i], other.positions[i]
+		if c := compareInts(int(p.position), int(o.position)); c != 0 {
+			return c
+		}
+		if c := compareInts(int(p.weight), int(o.weight)); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(t.positions), len(other.positions))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Lexemes returns the lexemes of the tsvector, in sorted order.
+func (v TSVector) Lexemes() []string {
+	res := make([]string, len(v))
+	for i := range v {
+		res[i] = v[i].lexeme
+	}
+	return res
+}
+
+// Size returns the approximate size in bytes of the tsvector.
+func (v TSVector) Size() uintptr {
+	var size uintptr
+	for i := range v {
+		size += uintptr(len(v[i].lexeme)) + uintptr(len(v[i].positions))*2 + 40
+	}
+	return size
+}
+
+// find returns the index of the term with the given lexeme, or -1 if there is
+// no such term.
+func (v TSVector) find(lexeme string) int {
+	i := sort.Search(len(v), func(i int) bool { return v[i].lexeme >= lexeme })
+	if i < len(v) && v[i].lexeme == lexeme {
+		return i
+	}
+	return -1
+}
+
+// findPrefix returns the range of the terms that have the given prefix.
+func (v TSVector) findPrefix(prefix string) (start, end int) {
+	start = sort.Search(len(v), func(i int) bool { return v[i].lexeme >= prefix })
+	end = start
+	for end < len(v) && strings.HasPrefix(v[end].lexeme, prefix) {
+		end++
+	}
+	return start, end
+}
+
+// EncodeTSVector appends the binary representation of the tsvector to the
+// given buffer. The representation is the same as the one used by the
+// PostgreSQL binary wire protocol: the number of lexemes, followed by each
+// lexeme as a null-terminated string, its number of positions and the
+// positions themselves, with their weights stored in the two high bits.
+func EncodeTSVector(appendTo []byte, v TSVector) []byte {
+	appendTo = appendUint32(appendTo, uint32(len(v)))
+	for _, t := range v {
+		appendTo = append(appendTo, t.lexeme...)
+		appendTo = append(appendTo, 0)
+		appendTo = appendUint16(appendTo, uint16(len(t.positions)))
+		for _, p := range t.positions {
+			appendTo = appendUint16(appendTo, uint16(p.weight)<<14|p.position)
+		}
+	}
+	return appendTo
+}
+
+// DecodeTSVector decodes a tsvector encoded with EncodeTSVector.
+func DecodeTSVector(b []byte) (TSVector, error) {
+	d := decoder{b: b}
+	n := d.uint32()
+	v := make(TSVector, 0, n)
+	for i := uint32(0); i < n && d.err == nil; i++ {
+		t := tsTerm{lexeme: d.cstring()}
+		if t.lexeme == "" && d.err == nil {
+			return nil, errors.New("invalid tsvector encoding: empty lexeme")
+		}
+		numPositions := d.uint16()
+		if numPositions > 0 {
+			t.positions = make([]tsPosition, numPositions)
+		}
+		for j := range t.positions {
+			p := d.uint16()
+			t.positions[j] = tsPosition{position: p & maxPosition, weight: tsWeight(p >> 14)}
+		}
+		v = append(v, t)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	if len(d.b) > 0 {
+		return nil, errors.New("invalid tsvector encoding: trailing bytes")
+	}
+	// Normalize the tsvector in case the encoding came from a client that did
+	// not sort the lexemes.
+	return makeTSVector(v), nil
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// decoder reads the binary representations of tsvectors and tsqueries. The
+// first error encountered is stored in err, after which all reads return
+// zero values.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) fail() {
+	if d.err == nil {
+		d.err = errors.New("invalid text search encoding: unexpected end of input")
+	}
+	d.b = nil
+}
+
+func (d *decoder) byte() byte {
+	if len(d.b) < 1 {
+		d.fail()
+		return 0
+	}
+	v := d.b[0]
+	d.b = d.b[1:]
+	return v
+}
+
+func (d *decoder) uint16() uint16 {
+	if len(d.b) < 2 {
+		d.fail()
+		return 0
+	}
+	v := binary.BigEndian.Uint16(d.b)
+	d.b = d.b[2:]
+	return v
+}
+
+func (d *decoder) uint32() uint32 {
+	if len(d.b) < 4 {
+		d.fail()
+		return 0
+	}
+	v := binary.BigEndian.Uint32(d.b)
+	d.b = d.b[4:]
+	return v
+}
+
+func (d *decoder) cstring() string {
+	i := strings.IndexByte(string(d.b), 0)
+	if i < 0 {
+		d.fail()
+		return ""
+	}
+	v := string(d.b[:i])
+	d.b = d.b[i+1:]
+	return v
+}
+
+// parser holds the state shared by the tsvector and tsquery text parsers.
+type parser struct {
+	input string
+	pos   int
+	// kind is the name of the parsed type, used in error messages.
+	kind string
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() byte {
+	return p.input[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.done() && isSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+// lexeme parses a quoted or unquoted lexeme. An unquoted lexeme ends at a
+// whitespace character or at a character for which isDelimiter returns true.
+func (p *parser) lexeme(isDelimiter func(c byte) bool) (string, error) {
+	var b strings.Builder
+	if p.peek() == '\'' {
+		p.pos++
+		for {
+			if p.done() {
+				return "", p.syntaxError()
+			}
+			c := p.peek()
+			p.pos++
+			switch {
+			case c == '\\':
+				if p.done() {
+					return "", p.syntaxError()
+				}
+				b.WriteByte(p.peek())
+				p.pos++
+				continue
+			case c == '\'':
+				if !p.done() && p.peek() == '\'' {
+					// A doubled quote is an escaped quote.
+					b.WriteByte('\'')
+					p.pos++
+					continue
+				}
+			default:
+				b.WriteByte(c)
+				continue
+			}
+			break
+		}
+	} else {
+		for !p.done() && !isSpace(p.peek()) && !isDelimiter(p.peek()) {
+			c := p.peek()
+			p.pos++
+			if c == '\\' {
+				if p.done() {
+					return "", p.syntaxError()
+				}
+				c = p.peek()
+				p.pos++
+			}
+			b.WriteByte(c)
+		}
+	}
+	if b.Len() == 0 {
+		return "", p.syntaxError()
+	}
+	if b.Len() > maxLexemeLen {
+		return "", pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"word is too long (%d bytes, max %d bytes)", b.Len(), maxLexemeLen)
+	}
+	return b.String(), nil
+}
+
+// positions parses a comma-separated list of positions, each of which may be
+// followed by a weight.
+func (p *parser) positions() ([]tsPosition, error) {
+	var positions []tsPosition
+	for {
+		start := p.pos
+		for !p.done() && p.peek() >= '0' && p.peek() <= '9' {
+			p.pos++
+		}
+		if start == p.pos {
+			return nil, p.syntaxError()
+		}
+		n, err := strconv.Atoi(p.input[start:p.pos])
+		if err != nil || n > maxPosition {
+			n = maxPosition
+		}
+		if n == 0 {
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"wrong position info in tsvector: %q", p.input)
+		}
+		pos := tsPosition{position: uint16(n), weight: weightD}
+		if !p.done() {
+			if w, ok := parseWeight(p.peek()); ok {
+				pos.weight = w
+				p.pos++
+			}
+		}
+		positions = append(positions, pos)
+		if p.done() || p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if !p.done() && !isSpace(p.peek()) {
+		return nil, p.syntaxError()
+	}
+	return positions, nil
+}
+
+func (p *parser) syntaxError() error {
+	return pgerror.Newf(pgcode.Syntax, "syntax error in %s: %q", p.kind, p.input)
+}
+
+// isWordChar returns true if the given rune is part of a word, for the
+// purpose of splitting documents and queries into words.
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
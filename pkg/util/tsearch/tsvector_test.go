@@ -0,0 +1,101 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTSVector(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{input: "", expected: ""},
+		{input: "  a:1 b:2 a:3 ", expected: "'a':1,3 'b':2"},
+		{input: "c b a", expected: "'a' 'b' 'c'"},
+		{input: "'a':1A,2,1 'c'", expected: "'a':1A,2 'c'"},
+		{input: "a:1A,2B,3C,4D", expected: "'a':1A,2B,3C,4"},
+		{input: "a:3 a:3b", expected: "'a':3B"},
+		{input: "'it''s' 'back\\\\slash'", expected: "'back\\\\slash' 'it''s'"},
+		{input: "a\\:b", expected: "'a:b'"},
+		{input: "'a b':20000", expected: "'a b':16383"},
+		{input: "a:0", err: "wrong position info"},
+		{input: "a:", err: "syntax error in tsvector"},
+		{input: "a:1x", err: "syntax error in tsvector"},
+		{input: "'a", err: "syntax error in tsvector"},
+		{input: "''", err: "syntax error in tsvector"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := ParseTSVector(tc.input)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, v.String())
+
+			// Check that the output can be parsed back and that the binary
+			// encoding round-trips.
+			reparsed, err := ParseTSVector(v.String())
+			require.NoError(t, err)
+			require.Equal(t, 0, v.Compare(reparsed))
+			decoded, err := DecodeTSVector(EncodeTSVector(nil, v))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, decoded.String())
+		})
+	}
+}
+
+func TestTSVectorCompare(t *testing.T) {
+	ordered := []string{"", "a", "a b", "a:1", "a:1,2", "a:2", "a:2A", "b"}
+	for i := range ordered {
+		for j := range ordered {
+			l, err := ParseTSVector(ordered[i])
+			require.NoError(t, err)
+			r, err := ParseTSVector(ordered[j])
+			require.NoError(t, err)
+			require.Equal(t, compareInts(i, j), l.Compare(r), "%q vs %q", ordered[i], ordered[j])
+		}
+	}
+}
+
+func TestDecodeTSVectorErrors(t *testing.T) {
+	v, err := ParseTSVector("a:1 b")
+	require.NoError(t, err)
+	encoded := EncodeTSVector(nil, v)
+	for i := 0; i < len(encoded); i++ {
+		_, err := DecodeTSVector(encoded[:i])
+		require.Error(t, err, "prefix of length %d", i)
+	}
+	_, err = DecodeTSVector(append(encoded, 0))
+	require.Error(t, err)
+}
+
+func TestRandomTSVectorRoundtrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		v := RandomTSVector(rng)
+		parsed, err := ParseTSVector(v.String())
+		require.NoError(t, err)
+		require.Equal(t, 0, v.Compare(parsed), "%s", v)
+
+		decoded, err := DecodeTSVector(EncodeTSVector(nil, v))
+		require.NoError(t, err)
+		require.Equal(t, 0, v.Compare(decoded), "%s", v)
+	}
+}